@@ -0,0 +1,427 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+)
+
+/*
+Package admin provides a mountable handler exposing REST endpoints to
+manage the routes and the API keys, so that the operators do not need
+direct database access to manage the auth plane.
+
+The endpoints are protected by the HMAC middleware of the API key store,
+see apikey.Store.Middleware, and are reachable only by the keys of the root
+tenancy, as their routes are root only. The keys restricted by scopes need
+the scopes of the admin resources, e.g. "admin-routes:*" or
+"admin-keys:list".
+
+# Usage
+
+    handler := admin.NewHandler(routeTable, keyStore,
+        admin.WithMiddlewareOptions(apikey.WithAudit(auditor)))
+    mux.Handle(admin.DefaultPrefix+"/", handler)
+
+# Endpoints
+
+  - GET    /admin/v1/routes        list the routes, filtered by the url,
+    tenant and provider query parameters, paged by offset and limit
+  - POST   /admin/v1/routes        create the route of the body
+  - PUT    /admin/v1/routes        update the route of the body
+  - DELETE /admin/v1/routes        delete the route of the url, method and
+    tenant query parameters
+  - GET    /admin/v1/keys          list the keys of the tenant and owner
+    query parameters
+  - POST   /admin/v1/keys          create the key of the body, returning
+    its secret
+  - GET    /admin/v1/keys/{id}     get the key
+  - PATCH  /admin/v1/keys/{id}     update the key as per the KeyUpdate body
+  - DELETE /admin/v1/keys/{id}     delete the key
+
+The bodies are the JSON encoding of route.Route and apikey.Key, the secrets
+of the keys are never returned except once on creation.
+*/
+
+// DefaultPrefix is the path prefix of the admin endpoints.
+const DefaultPrefix = "/admin/v1"
+
+// RBAC resources of the admin routes, the verbs being list, create,
+// update, delete and get.
+const (
+	ResourceRoutes = "admin-routes"
+	ResourceKeys   = "admin-keys"
+)
+
+// RouteStore manages the routes, implemented by the route.RouteTable.
+type RouteStore interface {
+	QueryRoutes(ctx context.Context, filter *route.RouteFilter, offset, limit int32) (*route.RouteList, error)
+	AddRoute(ctx context.Context, r *route.Route) error
+	UpdateRoute(ctx context.Context, r *route.Route) error
+	DeleteRoute(ctx context.Context, key *route.Key) error
+}
+
+// KeyStore manages the API keys and validates the requests of the admin
+// endpoints, implemented by the apikey.Store.
+type KeyStore interface {
+	List(ctx context.Context, tenant, owner string) ([]*apikey.Key, error)
+	Get(ctx context.Context, id string) (*apikey.Key, error)
+	Create(ctx context.Context, k *apikey.Key) (*apikey.Key, string, error)
+	Delete(ctx context.Context, id string) error
+	Disable(ctx context.Context, id string) error
+	Enable(ctx context.Context, id string) error
+	SetRateLimit(ctx context.Context, id string, l *apikey.RateLimit) error
+	SetNetworkPolicy(ctx context.Context, id string, p *apikey.NetworkPolicy) error
+	SetImpersonation(ctx context.Context, id string, g *apikey.ImpersonationGrant) error
+	Middleware(v hash.Validator, routes apikey.RouteResolver, opts ...apikey.MiddlewareOption) func(http.Handler) http.Handler
+}
+
+// KeyUpdate is the body of the key update, fields not set are left
+// unchanged, while the empty rate limit, network policy and impersonation
+// grant restore their defaults.
+type KeyUpdate struct {
+	Disabled      *bool
+	RateLimit     *apikey.RateLimit
+	Network       *apikey.NetworkPolicy
+	Impersonation *apikey.ImpersonationGrant
+}
+
+// CreatedKey is the response of the key creation, the only one carrying
+// the plaintext secret of the key.
+type CreatedKey struct {
+	Key    *apikey.Key
+	Secret string
+}
+
+// Option configures the admin handler.
+type Option func(*options)
+
+// options holds the configuration of the admin handler.
+type options struct {
+	prefix    string
+	validator hash.Validator
+	mwOpts    []apikey.MiddlewareOption
+}
+
+// WithPrefix sets the path prefix of the endpoints, DefaultPrefix if not
+// set.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithValidator sets the validator of the signed requests, a validator
+// with a validity of 60 seconds if not set.
+func WithValidator(v hash.Validator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// WithMiddlewareOptions configures the HMAC middleware protecting the
+// endpoints, e.g. with apikey.WithAudit.
+func WithMiddlewareOptions(opts ...apikey.MiddlewareOption) Option {
+	return func(o *options) {
+		o.mwOpts = append(o.mwOpts, opts...)
+	}
+}
+
+// handler serves the admin endpoints.
+type handler struct {
+	routes RouteStore
+	keys   KeyStore
+}
+
+// NewHandler returns the handler of the admin endpoints managing the routes
+// and the keys, see the package documentation.
+func NewHandler(routes RouteStore, keys KeyStore, opts ...Option) http.Handler {
+	o := &options{prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.validator == nil {
+		o.validator = hash.NewValidator(60)
+	}
+	h := &handler{routes: routes, keys: keys}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+o.prefix+"/routes", h.listRoutes)
+	mux.HandleFunc("POST "+o.prefix+"/routes", h.createRoute)
+	mux.HandleFunc("PUT "+o.prefix+"/routes", h.updateRoute)
+	mux.HandleFunc("DELETE "+o.prefix+"/routes", h.deleteRoute)
+	mux.HandleFunc("GET "+o.prefix+"/keys", h.listKeys)
+	mux.HandleFunc("POST "+o.prefix+"/keys", h.createKey)
+	mux.HandleFunc("GET "+o.prefix+"/keys/{id}", h.getKey)
+	mux.HandleFunc("PATCH "+o.prefix+"/keys/{id}", h.updateKey)
+	mux.HandleFunc("DELETE "+o.prefix+"/keys/{id}", h.deleteKey)
+	return keys.Middleware(o.validator, newAdminRoutes(o.prefix), o.mwOpts...)(mux)
+}
+
+// adminRoutes resolves the requests of the admin endpoints to their root
+// only routes, for the HMAC middleware.
+type adminRoutes struct {
+	routes []*route.Route
+}
+
+// newAdminRoutes returns the routes of the admin endpoints under the
+// prefix.
+func newAdminRoutes(prefix string) *adminRoutes {
+	root := true
+	r := func(method route.MethodType, url, resource, verb string) *route.Route {
+		return &route.Route{
+			Key:      &route.Key{Url: prefix + url, Method: method},
+			IsRoot:   &root,
+			Resource: resource,
+			Verb:     verb,
+		}
+	}
+	return &adminRoutes{routes: []*route.Route{
+		r(route.GET, "/routes", ResourceRoutes, "list"),
+		r(route.POST, "/routes", ResourceRoutes, "create"),
+		r(route.PUT, "/routes", ResourceRoutes, "update"),
+		r(route.DELETE, "/routes", ResourceRoutes, "delete"),
+		r(route.GET, "/keys", ResourceKeys, "list"),
+		r(route.POST, "/keys", ResourceKeys, "create"),
+		r(route.GET, "/keys/{id}", ResourceKeys, "get"),
+		r(route.PATCH, "/keys/{id}", ResourceKeys, "update"),
+		r(route.DELETE, "/keys/{id}", ResourceKeys, "delete"),
+	}}
+}
+
+// ResolveTenantRoute implements the apikey.RouteResolver.
+func (a *adminRoutes) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	for _, r := range a.routes {
+		if r.Key.Method != method {
+			continue
+		}
+		if _, ok := route.MatchPath(r.Key.Url, path); ok {
+			return r, nil
+		}
+	}
+	return nil, errors.Wrapf(errors.NotFound, "no route found for %s %s", method, path)
+}
+
+// writeJSON writes the value as the JSON response with the status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the error response with the status of the error.
+func writeError(w http.ResponseWriter, err error) {
+	if _, ok := err.(*route.ConflictError); ok {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	switch {
+	case errors.IsInvalidArgument(err):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.IsNotFound(err):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.IsAlreadyExists(err):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.IsForbidden(err):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	}
+}
+
+// decode decodes the JSON body of the request into v, writing the error
+// response if it fails.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		http.Error(w, "invalid body: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// pageParam returns the offset or limit query parameter, zero if not set.
+func pageParam(r *http.Request, name string) (int32, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(errors.InvalidArgument, "invalid %s %q", name, v)
+	}
+	return int32(n), nil
+}
+
+// listRoutes lists the page of the routes matching the query parameters.
+func (h *handler) listRoutes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := &route.RouteFilter{Url: q.Get("url"), Provider: q.Get("provider")}
+	if q.Has("tenant") {
+		tenant := q.Get("tenant")
+		filter.Tenant = &tenant
+	}
+	offset, err := pageParam(r, "offset")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	limit, err := pageParam(r, "limit")
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list, err := h.routes.QueryRoutes(r.Context(), filter, offset, limit)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// createRoute creates the route of the body.
+func (h *handler) createRoute(w http.ResponseWriter, r *http.Request) {
+	rt := &route.Route{}
+	if !decode(w, r, rt) {
+		return
+	}
+	if err := h.routes.AddRoute(r.Context(), rt); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, rt)
+}
+
+// updateRoute updates the route of the body.
+func (h *handler) updateRoute(w http.ResponseWriter, r *http.Request) {
+	rt := &route.Route{}
+	if !decode(w, r, rt) {
+		return
+	}
+	if err := h.routes.UpdateRoute(r.Context(), rt); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rt)
+}
+
+// deleteRoute deletes the route of the query parameters.
+func (h *handler) deleteRoute(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	method, err := route.ParseMethod(q.Get("method"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	key := &route.Key{Url: q.Get("url"), Method: method, Tenant: q.Get("tenant")}
+	if err := h.routes.DeleteRoute(r.Context(), key); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// keyView returns the key without the hashes of its secrets.
+func keyView(k *apikey.Key) *apikey.Key {
+	out := *k
+	out.Secrets = make([]*apikey.Secret, 0, len(k.Secrets))
+	for _, s := range k.Secrets {
+		out.Secrets = append(out.Secrets, &apikey.Secret{
+			Generation: s.Generation,
+			Created:    s.Created,
+			Expiry:     s.Expiry,
+		})
+	}
+	return &out
+}
+
+// listKeys lists the keys of the tenant and owner query parameters.
+func (h *handler) listKeys(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	keys, err := h.keys.List(r.Context(), q.Get("tenant"), q.Get("owner"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	list := make([]*apikey.Key, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, keyView(k))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// createKey creates the key of the body, returning its secret.
+func (h *handler) createKey(w http.ResponseWriter, r *http.Request) {
+	k := &apikey.Key{}
+	if !decode(w, r, k) {
+		return
+	}
+	created, secret, err := h.keys.Create(r.Context(), k)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, &CreatedKey{Key: keyView(created), Secret: secret})
+}
+
+// getKey returns the key.
+func (h *handler) getKey(w http.ResponseWriter, r *http.Request) {
+	k, err := h.keys.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, keyView(k))
+}
+
+// updateKey applies the KeyUpdate of the body to the key.
+func (h *handler) updateKey(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	update := &KeyUpdate{}
+	if !decode(w, r, update) {
+		return
+	}
+	ctx := r.Context()
+	var err error
+	if update.Disabled != nil {
+		if *update.Disabled {
+			err = h.keys.Disable(ctx, id)
+		} else {
+			err = h.keys.Enable(ctx, id)
+		}
+	}
+	if err == nil && update.RateLimit != nil {
+		err = h.keys.SetRateLimit(ctx, id, update.RateLimit)
+	}
+	if err == nil && update.Network != nil {
+		err = h.keys.SetNetworkPolicy(ctx, id, update.Network)
+	}
+	if err == nil && update.Impersonation != nil {
+		err = h.keys.SetImpersonation(ctx, id, update.Impersonation)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	h.getKey(w, r)
+}
+
+// deleteKey deletes the key.
+func (h *handler) deleteKey(w http.ResponseWriter, r *http.Request) {
+	if err := h.keys.Delete(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
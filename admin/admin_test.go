@@ -0,0 +1,226 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+)
+
+// the stores of the route table and the API keys implement the interfaces
+var (
+	_ RouteStore = (*route.RouteTable)(nil)
+	_ KeyStore   = (*apikey.Store)(nil)
+)
+
+// fakeRoutes keeps the routes in memory
+type fakeRoutes struct {
+	routes map[route.Key]*route.Route
+}
+
+func (f *fakeRoutes) QueryRoutes(ctx context.Context, filter *route.RouteFilter, offset, limit int32) (*route.RouteList, error) {
+	list := &route.RouteList{}
+	for _, r := range f.routes {
+		if filter.Url == "" || filter.Url == r.Key.Url {
+			list.Routes = append(list.Routes, r)
+		}
+	}
+	list.Total = int64(len(list.Routes))
+	return list, nil
+}
+
+func (f *fakeRoutes) AddRoute(ctx context.Context, r *route.Route) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if _, ok := f.routes[*r.Key]; ok {
+		return errors.Wrapf(errors.AlreadyExists, "route exists")
+	}
+	f.routes[*r.Key] = r
+	return nil
+}
+
+func (f *fakeRoutes) UpdateRoute(ctx context.Context, r *route.Route) error {
+	if _, ok := f.routes[*r.Key]; !ok {
+		return errors.Wrapf(errors.NotFound, "route not found")
+	}
+	f.routes[*r.Key] = r
+	return nil
+}
+
+func (f *fakeRoutes) DeleteRoute(ctx context.Context, key *route.Key) error {
+	if _, ok := f.routes[*key]; !ok {
+		return errors.Wrapf(errors.NotFound, "route not found")
+	}
+	delete(f.routes, *key)
+	return nil
+}
+
+// fakeKeys keeps the keys in memory, its middleware records the routes
+// resolver and lets every request through
+type fakeKeys struct {
+	keys     map[string]*apikey.Key
+	resolver apikey.RouteResolver
+}
+
+func (f *fakeKeys) List(ctx context.Context, tenant, owner string) ([]*apikey.Key, error) {
+	list := []*apikey.Key{}
+	for _, k := range f.keys {
+		if k.Tenant == tenant {
+			list = append(list, k)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeKeys) Get(ctx context.Context, id string) (*apikey.Key, error) {
+	k, ok := f.keys[id]
+	if !ok {
+		return nil, errors.Wrapf(errors.NotFound, "api key %s not found", id)
+	}
+	return k, nil
+}
+
+func (f *fakeKeys) Create(ctx context.Context, k *apikey.Key) (*apikey.Key, string, error) {
+	if k.Owner == "" {
+		return nil, "", errors.Wrapf(errors.InvalidArgument, "api key owner is required")
+	}
+	k.Key = &apikey.KeyId{Id: "k1"}
+	k.Secrets = []*apikey.Secret{{Generation: 1, Hash: "hash", Created: 1}}
+	f.keys["k1"] = k
+	return k, "supersecret", nil
+}
+
+func (f *fakeKeys) Delete(ctx context.Context, id string) error {
+	delete(f.keys, id)
+	return nil
+}
+
+func (f *fakeKeys) Disable(ctx context.Context, id string) error {
+	disabled := true
+	f.keys[id].Disabled = &disabled
+	return nil
+}
+
+func (f *fakeKeys) Enable(ctx context.Context, id string) error {
+	f.keys[id].Disabled = nil
+	return nil
+}
+
+func (f *fakeKeys) SetRateLimit(ctx context.Context, id string, l *apikey.RateLimit) error {
+	f.keys[id].RateLimit = l
+	return nil
+}
+
+func (f *fakeKeys) SetNetworkPolicy(ctx context.Context, id string, p *apikey.NetworkPolicy) error {
+	f.keys[id].Network = p
+	return nil
+}
+
+func (f *fakeKeys) SetImpersonation(ctx context.Context, id string, g *apikey.ImpersonationGrant) error {
+	f.keys[id].Impersonation = g
+	return nil
+}
+
+func (f *fakeKeys) Middleware(v hash.Validator, routes apikey.RouteResolver, opts ...apikey.MiddlewareOption) func(http.Handler) http.Handler {
+	f.resolver = routes
+	return func(next http.Handler) http.Handler {
+		return next
+	}
+}
+
+func TestHandler(t *testing.T) {
+	routes := &fakeRoutes{routes: map[route.Key]*route.Route{}}
+	keys := &fakeKeys{keys: map[string]*apikey.Key{}}
+	h := NewHandler(routes, keys)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/admin/v1/routes", `{"Key":{"Url":"/books","Method":"GET"},"Endpoint":"http://books:8080"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected route to be created, got %d %s", rec.Code, rec.Body)
+	}
+	if rec := do(http.MethodPost, "/admin/v1/routes", `{"Key":{"Url":"/books","Method":"GET"},"Endpoint":"http://books:8080"}`); rec.Code != http.StatusConflict {
+		t.Errorf("expected existing route to conflict, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/admin/v1/routes", `{"Unknown":true}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected unknown fields to be rejected, got %d", rec.Code)
+	}
+	rec = do(http.MethodGet, "/admin/v1/routes?url=/books", "")
+	list := &route.RouteList{}
+	if err := json.Unmarshal(rec.Body.Bytes(), list); err != nil || list.Total != 1 || list.Routes[0].Endpoint != "http://books:8080" {
+		t.Errorf("unexpected route list %s: %v", rec.Body, err)
+	}
+	if rec := do(http.MethodPut, "/admin/v1/routes", `{"Key":{"Url":"/books","Method":"GET"},"Endpoint":"http://books-v2:8080"}`); rec.Code != http.StatusOK {
+		t.Errorf("expected route to be updated, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/admin/v1/routes?url=/books&method=GET", ""); rec.Code != http.StatusNoContent || len(routes.routes) != 0 {
+		t.Errorf("expected route to be deleted, got %d", rec.Code)
+	}
+
+	rec = do(http.MethodPost, "/admin/v1/keys", `{"Owner":"alice","Tenant":"acme"}`)
+	created := &CreatedKey{}
+	if err := json.Unmarshal(rec.Body.Bytes(), created); err != nil || rec.Code != http.StatusCreated || created.Secret != "supersecret" {
+		t.Fatalf("unexpected key creation %d %s: %v", rec.Code, rec.Body, err)
+	}
+	if created.Key.Secrets[0].Hash != "" {
+		t.Errorf("expected the hash of the secret not to be returned")
+	}
+	if rec := do(http.MethodPost, "/admin/v1/keys", `{"Tenant":"acme"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected invalid key to be rejected, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPatch, "/admin/v1/keys/k1", `{"Disabled":true,"Network":{"Allow":["10.0.0.0/8"]}}`); rec.Code != http.StatusOK {
+		t.Errorf("expected key to be updated, got %d", rec.Code)
+	}
+	if k := keys.keys["k1"]; !k.IsDisabled() || k.Network == nil {
+		t.Errorf("unexpected updated key %+v", k)
+	}
+	rec = do(http.MethodGet, "/admin/v1/keys?tenant=acme", "")
+	if !strings.Contains(rec.Body.String(), `"Owner":"alice"`) || strings.Contains(rec.Body.String(), "supersecret") {
+		t.Errorf("unexpected key list %s", rec.Body)
+	}
+	if rec := do(http.MethodDelete, "/admin/v1/keys/k1", ""); rec.Code != http.StatusNoContent {
+		t.Errorf("expected key to be deleted, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/admin/v1/keys/k1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected deleted key not to be found, got %d", rec.Code)
+	}
+}
+
+func TestAdminRoutes(t *testing.T) {
+	keys := &fakeKeys{keys: map[string]*apikey.Key{}}
+	NewHandler(&fakeRoutes{}, keys, WithPrefix("/ops"))
+
+	rt, err := keys.resolver.ResolveTenantRoute(context.Background(), "acme", route.PATCH, "/ops/keys/k1")
+	if err != nil {
+		t.Fatalf("failed to resolve admin route: %s", err)
+	}
+	if rt.Resource != ResourceKeys || rt.Verb != "update" {
+		t.Errorf("unexpected admin route %+v", rt)
+	}
+	if rt.AllowsTenant("acme") || !rt.AllowsTenant(route.RootTenant) {
+		t.Errorf("expected admin routes to be root only")
+	}
+	s, _ := apikey.ParseScope(ResourceKeys + ":*")
+	if !s.Covers(rt, http.MethodPatch, "/ops/keys/k1") {
+		t.Errorf("expected admin scope to cover the route")
+	}
+	if _, err := keys.resolver.ResolveTenantRoute(context.Background(), "", route.GET, "/ops/other"); !errors.IsNotFound(err) {
+		t.Errorf("expected unknown path not to resolve, got %v", err)
+	}
+}
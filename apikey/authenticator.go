@@ -0,0 +1,285 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/audit"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/route"
+)
+
+/*
+This file provides the Authenticator, enforcing the controls of the
+Middleware for the callers resolving the route of the requests themselves,
+e.g. the gateway. The controls are split in two steps:
+
+  - Authenticate, before the route is known: the lockout of the key and of
+    the source address, the validation of the signature with all the valid
+    generations of the secret of the active key, its replay, the network
+    policy of the key and its impersonation grant
+  - Authorize, once the route is resolved for the tenant of the caller: the
+    tenancy of the route and the scopes of the key, recording the usage of
+    the key for the route
+
+Revalidate re-checks the key periodically for the long lived connections.
+The stages of both steps are bounded by the latency budgets of the options,
+see budget.go.
+
+# Usage
+
+    a := store.Authenticator(validator, apikey.WithLockout(lockout))
+    k, authCtx, err := a.Authenticate(r)
+    if err != nil {
+        // *LockedError, *BudgetExceededError, or codes Unauthorized and
+        // Forbidden
+    }
+    rt, err := routes.ResolveTenantRoute(ctx, authCtx.Tenant, method, r.URL.Path)
+    err = a.Authorize(r, k, authCtx, rt)
+*/
+
+// Authenticator authenticates the signed requests with the keys of the
+// store, see the file documentation. Shadow mode is a property of the
+// Middleware and is ignored.
+type Authenticator struct {
+	store *Store
+	v     hash.Validator
+	o     *middlewareOptions
+
+	// keys and routes last loaded, for the stages falling back to them
+	keys   *staleCache[*Key]
+	routes *staleCache[*route.Route]
+}
+
+// Authenticator returns the authenticator validating the requests with the
+// validator, configured with the options of the Middleware.
+func (s *Store) Authenticator(v hash.Validator, opts ...MiddlewareOption) *Authenticator {
+	o := &middlewareOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Authenticator{
+		store:  s,
+		v:      v,
+		o:      o,
+		keys:   newStaleCache[*Key](o, StageSecretLookup),
+		routes: newStaleCache[*route.Route](o, StageRouteLookup),
+	}
+}
+
+// Authenticate validates the request and enforces the controls not
+// depending on its route, returning the key and the auth context of its
+// owner, or of the user impersonated. Locked keys and addresses fail with a
+// *LockedError, the stages exceeding their latency budget with a
+// *BudgetExceededError, the failed validations with code Unauthorized and
+// the denied requests with code Forbidden.
+func (a *Authenticator) Authenticate(r *http.Request) (*Key, *model.AuthContext, error) {
+	k, authCtx, _, err := a.authenticate(r, audit.NewRequestRecord(audit.KindAuthentication, r))
+	return k, authCtx, err
+}
+
+// Authorize enforces the controls depending on the route resolved for the
+// request, failing with code Forbidden, and records the usage of the key
+// for the route.
+func (a *Authenticator) Authorize(r *http.Request, k *Key, authCtx *model.AuthContext, rt *route.Route) error {
+	if err := authorizeRoute(r, k, authCtx, rt); err != nil {
+		return err
+	}
+	a.recordUsage(k, rt, r)
+	return nil
+}
+
+// Revalidate re-validates the key of a long lived connection established
+// with the request, e.g. a WebSocket, reloading it from the store: failing
+// with code Unauthorized once the key is disabled, expired or deleted, and
+// with code Forbidden once its impersonation grant, tenancy or scopes no
+// longer allow the route.
+func (a *Authenticator) Revalidate(ctx context.Context, r *http.Request, k *Key, authCtx *model.AuthContext, rt *route.Route) error {
+	cur, err := a.store.Get(ctx, k.Key.Id)
+	if err != nil || !cur.IsActive(time.Now()) {
+		return errors.Wrapf(errors.Unauthorized, "api key %s no longer active", k.Key.Id)
+	}
+	if authCtx.ImpersonatedBy != "" && !cur.CanImpersonate(authCtx.Tenant) {
+		return errors.Wrap(errors.Forbidden, "api key no longer allowed to impersonate")
+	}
+	return authorizeRoute(r, cur, authCtx, rt)
+}
+
+// authenticate authenticates the request as per Authenticate, filling the
+// audit record of the request, and returns the client address.
+func (a *Authenticator) authenticate(r *http.Request, rec *audit.Record) (*Key, *model.AuthContext, netip.Addr, error) {
+	ctx := r.Context()
+	o := a.o
+	rec.KeyId = a.v.GetKeyId(r)
+	addr, _ := o.clients.ClientAddr(r)
+	if addr.IsValid() {
+		rec.SourceIP = addr.String()
+	}
+	if o.lockout != nil {
+		err := a.lockout(ctx, func(ctx context.Context) error {
+			return o.lockout.Check(ctx, rec.KeyId, rec.SourceIP)
+		})
+		switch err.(type) {
+		case nil:
+		case *LockedError:
+			return nil, nil, addr, err
+		case *BudgetExceededError:
+			if o.fallback(StagePolicy, err) != FallbackSkip {
+				return nil, nil, addr, err
+			}
+		default:
+			return nil, nil, addr, errors.Wrapf(errors.Unknown, "failed to check the lockout: %s", err)
+		}
+	}
+	k, err := a.lookupKey(ctx, rec.KeyId)
+	if err == nil {
+		k, err = a.store.verify(k, a.v, r)
+	}
+	if err != nil {
+		if _, ok := err.(*BudgetExceededError); ok {
+			return nil, nil, addr, err
+		}
+		if o.lockout != nil && (errors.IsUnauthorized(err) || errors.IsNotFound(err)) {
+			locked, ok := a.lockout(ctx, func(ctx context.Context) error {
+				return o.lockout.Fail(ctx, rec.KeyId, rec.SourceIP)
+			}).(*LockedError)
+			if ok && o.audit != nil {
+				lock := *rec
+				lock.Kind = audit.KindLockout
+				o.audit.Emit(ctx, lock.Deny(locked.Error()))
+			}
+		}
+		return nil, nil, addr, errors.Wrap(errors.Unauthorized, err.Error())
+	}
+	if err := a.checkReplay(ctx, r, k); err != nil {
+		return nil, nil, addr, err
+	}
+	if o.lockout != nil {
+		_ = a.lockout(ctx, func(ctx context.Context) error {
+			return o.lockout.Succeed(ctx, rec.KeyId)
+		})
+	}
+	rec.Tenant, rec.Subject = k.Tenant, k.Owner
+	if !k.AllowsAddr(addr) {
+		return nil, nil, addr, errors.Wrap(errors.Forbidden, "api key not usable from the client address")
+	}
+	authCtx := k.AuthContext()
+	if target := hash.RequestImpersonation(a.v, r); target != nil {
+		if target.User == "" || !k.CanImpersonate(target.Tenant) {
+			return nil, nil, addr, errors.Wrap(errors.Forbidden, "api key not allowed to impersonate")
+		}
+		authCtx = authCtx.Impersonate(target.User, target.Tenant)
+		rec.Tenant, rec.Subject, rec.ImpersonatedBy = target.Tenant, target.User, k.Owner
+	}
+	return k, authCtx, addr, nil
+}
+
+// lockout runs the operation on the lockout within the budget of
+// StagePolicy.
+func (a *Authenticator) lockout(ctx context.Context, op func(ctx context.Context) error) error {
+	_, err := withinBudget(ctx, a.o, StagePolicy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, op(ctx)
+	})
+	return err
+}
+
+// checkReplay records the nonce of the request within the budget of
+// StagePolicy, failing with code Unauthorized if already recorded for the
+// key within the replay window. The requests without a signed nonce, see
+// hash.RequestNonce, are not recorded, as identical requests signed within
+// the same second have the same signature.
+func (a *Authenticator) checkReplay(ctx context.Context, r *http.Request, k *Key) error {
+	o := a.o
+	if o.replays == nil {
+		return nil
+	}
+	nonce := hash.RequestNonce(a.v, r)
+	if nonce == "" {
+		return nil
+	}
+	seen, err := withinBudget(ctx, o, StagePolicy, func(ctx context.Context) (bool, error) {
+		return o.replays.Seen(ctx, k.Key.Id+":"+nonce, time.Now().Add(o.replayWindow))
+	})
+	switch err.(type) {
+	case nil:
+	case *BudgetExceededError:
+		if o.fallback(StagePolicy, err) != FallbackSkip {
+			return err
+		}
+	default:
+		return errors.Wrapf(errors.Unknown, "failed to check the replay: %s", err)
+	}
+	if seen {
+		return errors.Wrap(errors.Unauthorized, "replayed request")
+	}
+	return nil
+}
+
+// lookupKey loads the active key within the budget of StageSecretLookup,
+// falling back to the key last loaded if configured.
+func (a *Authenticator) lookupKey(ctx context.Context, id string) (*Key, error) {
+	k, err := withinBudget(ctx, a.o, StageSecretLookup, func(ctx context.Context) (*Key, error) {
+		return a.store.activeKey(ctx, id)
+	})
+	if err == nil {
+		a.keys.add(id, k)
+		return k, nil
+	}
+	if a.o.fallback(StageSecretLookup, err) == FallbackStale {
+		if k, ok := a.keys.get(id); ok && k.IsActive(time.Now()) {
+			return k, nil
+		}
+	}
+	return nil, err
+}
+
+// authorizeRoute enforces the tenancy of the route and the scopes of the
+// key.
+func authorizeRoute(r *http.Request, k *Key, authCtx *model.AuthContext, rt *route.Route) error {
+	if !rt.AllowsTenant(authCtx.Tenant) {
+		return errors.Wrap(errors.Forbidden, "route not accessible for the tenant of the api key")
+	}
+	if !k.Covers(rt, r.Method, r.URL.Path) {
+		return errors.Wrap(errors.Forbidden, "api key scopes do not cover the route")
+	}
+	return nil
+}
+
+// recordUsage records the usage of the key for the route, if enabled.
+func (a *Authenticator) recordUsage(k *Key, rt *route.Route, r *http.Request) {
+	if a.o.usage != nil {
+		a.o.usage.Record(k.Key.Id, rt.Key.Url, r.Method, time.Now())
+	}
+}
+
+// resolveRoute resolves the route of the request for the tenant within
+// the budget of StageRouteLookup, falling back to the route last resolved
+// if configured.
+func (a *Authenticator) resolveRoute(ctx context.Context, routes RouteResolver, tenant string, r *http.Request) (*route.Route, error) {
+	method, err := route.ParseMethod(r.Method)
+	if err != nil {
+		return nil, err
+	}
+	rt, err := withinBudget(ctx, a.o, StageRouteLookup, func(ctx context.Context) (*route.Route, error) {
+		return routes.ResolveTenantRoute(ctx, tenant, method, r.URL.Path)
+	})
+	key := tenant + " " + method.String() + " " + r.URL.Path
+	if err == nil {
+		a.routes.add(key, rt)
+		return rt, nil
+	}
+	if a.o.fallback(StageRouteLookup, err) == FallbackStale {
+		if rt, ok := a.routes.get(key); ok {
+			return rt, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,232 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-core-stack/auth/internal/lru"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+/*
+This file provides the latency budgets of the stages of the authentication
+performed by the Middleware and the Authenticator, so that a slow
+dependency degrades the requests depending on it rather than stalling all
+of them:
+
+  - StageSecretLookup: loading the key and its secrets from the store
+  - StageRouteLookup: resolving the route of the request, by the Middleware
+  - StagePolicy: checking and recording the lockout of the key and of the
+    source address, and the replay of the request, typically in stores
+    shared by the replicas
+
+A stage exceeding its budget is abandoned, its context being cancelled, is
+counted by the metrics set with WithMetrics, and is handled as per the
+fallback of the budget: the request is rejected with 503 by default, served
+with the key or route last loaded by the stage with FallbackStale, or
+proceeds without the lockout and the replay protection with FallbackSkip.
+The failures and successes recorded in the lockout after the validation
+are abandoned regardless of the fallback, as the request is decided by
+then.
+
+# Usage
+
+    handler = store.Middleware(validator, routes,
+        apikey.WithLockout(lockout),
+        apikey.WithBudget(apikey.StageSecretLookup, apikey.Budget{Timeout: 50 * time.Millisecond, Fallback: apikey.FallbackStale}),
+        apikey.WithBudget(apikey.StagePolicy, apikey.Budget{Timeout: 10 * time.Millisecond, Fallback: apikey.FallbackSkip}),
+        apikey.WithMetrics(metrics),
+    )(handler)
+*/
+
+// Stage is a stage of the authentication with a latency budget.
+type Stage string
+
+const (
+	// StageSecretLookup loads the key and its secrets from the store
+	StageSecretLookup Stage = "secret_lookup"
+
+	// StageRouteLookup resolves the route of the request
+	StageRouteLookup Stage = "route_lookup"
+
+	// StagePolicy checks and records the lockout of the key and of the
+	// source address, and the replay of the request
+	StagePolicy Stage = "policy"
+)
+
+// Fallback is the handling of the requests once a stage exceeds its
+// budget.
+type Fallback int
+
+const (
+	// FallbackReject rejects the request with 503, the default
+	FallbackReject Fallback = iota
+
+	// FallbackStale uses the key or route last loaded by the stage within
+	// the MaxStale of the budget, rejecting the request if none, for
+	// StageSecretLookup and StageRouteLookup
+	FallbackStale
+
+	// FallbackSkip proceeds without enforcing the lockout and the replay
+	// protection, for StagePolicy
+	FallbackSkip
+)
+
+const (
+	// DefaultMaxStale is the maximum age of the keys and routes used by
+	// FallbackStale, unless set otherwise by the budget.
+	DefaultMaxStale = 5 * time.Minute
+
+	// maxStaleEntries bounds the keys and routes kept for FallbackStale,
+	// per stage.
+	maxStaleEntries = 10000
+)
+
+// Budget is the latency budget of a stage.
+type Budget struct {
+	// maximum duration of the stage, unbounded if zero
+	Timeout time.Duration
+
+	// handling of the requests once the stage exceeds the timeout, the
+	// fallbacks not supported by the stage rejecting the requests
+	Fallback Fallback
+
+	// maximum age of the keys and routes used by FallbackStale,
+	// DefaultMaxStale if zero
+	MaxStale time.Duration
+}
+
+// BudgetExceededError is the error of the requests rejected for a stage
+// exceeding its budget.
+type BudgetExceededError struct {
+	// stage exceeding the budget
+	Stage Stage
+
+	// timeout of the budget
+	Timeout time.Duration
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s exceeded its latency budget of %s", e.Stage, e.Timeout)
+}
+
+// WithBudget bounds the duration of the stage as per the budget, see the
+// file documentation.
+func WithBudget(stage Stage, b Budget) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		if o.budgets == nil {
+			o.budgets = map[Stage]Budget{}
+		}
+		o.budgets[stage] = b
+	}
+}
+
+// WithMetrics records the stages exceeding their budget with the metrics,
+// see WithBudget.
+func WithMetrics(metrics telemetry.Metrics) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.metrics = metrics
+	}
+}
+
+// staleEntry is a value loaded by a stage, along with the time it was
+// loaded.
+type staleEntry[V any] struct {
+	value  V
+	loaded time.Time
+}
+
+// staleCache keeps the values last loaded by a stage for FallbackStale, a
+// nil cache keeping none.
+type staleCache[V any] struct {
+	maxAge  time.Duration
+	entries *lru.Cache[string, staleEntry[V]]
+}
+
+// newStaleCache returns the cache of the values loaded by the stage if its
+// budget falls back to them, nil otherwise.
+func newStaleCache[V any](o *middlewareOptions, stage Stage) *staleCache[V] {
+	b := o.budgets[stage]
+	if b.Timeout <= 0 || b.Fallback != FallbackStale {
+		return nil
+	}
+	maxAge := b.MaxStale
+	if maxAge <= 0 {
+		maxAge = DefaultMaxStale
+	}
+	return &staleCache[V]{maxAge: maxAge, entries: lru.New[string, staleEntry[V]](maxStaleEntries)}
+}
+
+// add records the value loaded for the key.
+func (c *staleCache[V]) add(key string, v V) {
+	if c != nil {
+		c.entries.Add(key, staleEntry[V]{value: v, loaded: time.Now()})
+	}
+}
+
+// get returns the value last loaded for the key, if not older than the
+// maximum age.
+func (c *staleCache[V]) get(key string) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+	e, ok := c.entries.Get(key)
+	if !ok || time.Since(e.loaded) > c.maxAge {
+		return zero, false
+	}
+	return e.value, true
+}
+
+// withinBudget runs the stage within its budget, if any, failing with a
+// *BudgetExceededError once exceeded, the stage being abandoned with its
+// context cancelled.
+func withinBudget[T any](ctx context.Context, o *middlewareOptions, stage Stage, fn func(ctx context.Context) (T, error)) (T, error) {
+	b := o.budgets[stage]
+	if b.Timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, b.Timeout)
+	defer cancel()
+
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v: v, err: err}
+	}()
+	var res result
+	select {
+	case res = <-done:
+		if res.err == nil || ctx.Err() == nil {
+			return res.v, res.err
+		}
+	case <-ctx.Done():
+		res.err = ctx.Err()
+	}
+	if ctx.Err() != context.DeadlineExceeded {
+		// the request is cancelled
+		return res.v, res.err
+	}
+	if o.metrics != nil {
+		o.metrics.RecordBudgetExceeded(string(stage))
+	}
+	var zero T
+	return zero, &BudgetExceededError{Stage: stage, Timeout: b.Timeout}
+}
+
+// fallback returns the fallback of the stage exceeding its budget with
+// the error, FallbackReject for the other errors.
+func (o *middlewareOptions) fallback(stage Stage, err error) Fallback {
+	if _, ok := err.(*BudgetExceededError); !ok {
+		return FallbackReject
+	}
+	return o.budgets[stage].Fallback
+}
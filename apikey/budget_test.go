@@ -0,0 +1,166 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// slowTable delays the lookups of the keys while slow is set, ignoring the
+// cancellation as a stalled dependency would.
+type slowTable struct {
+	storage.Table[KeyId, Key]
+	slow atomic.Bool
+}
+
+func (t *slowTable) Find(ctx context.Context, key *KeyId) (*Key, error) {
+	if t.slow.Load() {
+		time.Sleep(200 * time.Millisecond)
+	}
+	return t.Table.Find(ctx, key)
+}
+
+// slowRoutes delays the route lookups while slow is set.
+type slowRoutes struct {
+	fakeRoutes
+	slow atomic.Bool
+}
+
+func (f *slowRoutes) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	if f.slow.Load() {
+		time.Sleep(200 * time.Millisecond)
+	}
+	return f.fakeRoutes.ResolveTenantRoute(ctx, tenant, method, path)
+}
+
+// slowLockoutStore delays the lockout checks.
+type slowLockoutStore struct {
+	LockoutStore
+}
+
+func (s *slowLockoutStore) Locked(ctx context.Context, subject string) (time.Time, error) {
+	time.Sleep(200 * time.Millisecond)
+	return s.LockoutStore.Locked(ctx, subject)
+}
+
+// budgetMetrics counts the exceeded budgets by stage.
+type budgetMetrics struct {
+	mu       sync.Mutex
+	exceeded map[string]int
+}
+
+func (m *budgetMetrics) RecordValidation(ctx context.Context, outcome telemetry.Outcome, duration time.Duration) {
+}
+
+func (m *budgetMetrics) RecordClientRequest(ctx context.Context, method string, status int, duration time.Duration) {
+}
+
+func (m *budgetMetrics) RecordCacheLookup(cache string, hit bool) {}
+func (m *budgetMetrics) RecordCacheEviction(cache string)         {}
+
+func (m *budgetMetrics) RecordBudgetExceeded(stage string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exceeded[stage]++
+}
+
+func (m *budgetMetrics) count(stage Stage) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exceeded[string(stage)]
+}
+
+func TestMiddlewareBudgets(t *testing.T) {
+	ctx := context.Background()
+	tbl := &slowTable{Table: storage.NewMemoryTable[KeyId, Key]()}
+	store, _ := NewStoreWithStorage(tbl, storage.NewMemoryTable[UsageKey, Usage](), make([]byte, 32))
+	k, secret, _ := store.Create(ctx, &Key{Owner: "svc", Tenant: "acme"})
+	routes := &slowRoutes{fakeRoutes: fakeRoutes{route: &route.Route{Key: &route.Key{Url: "/books", Method: route.GET}}}}
+	metrics := &budgetMetrics{exceeded: map[string]int{}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	send := func(handler http.Handler, keyId string) (int, time.Duration) {
+		r := hash.NewGenerator(keyId, secret).AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+		w := httptest.NewRecorder()
+		start := time.Now()
+		handler.ServeHTTP(w, r)
+		return w.Code, time.Since(start)
+	}
+	budget := func(stage Stage, fallback Fallback) MiddlewareOption {
+		return WithBudget(stage, Budget{Timeout: 20 * time.Millisecond, Fallback: fallback})
+	}
+
+	// the slow key lookup is rejected within the budget, unless falling
+	// back to the key last loaded
+	reject := store.Middleware(hash.NewValidator(60), routes, budget(StageSecretLookup, FallbackReject), WithMetrics(metrics))(next)
+	stale := store.Middleware(hash.NewValidator(60), routes, budget(StageSecretLookup, FallbackStale), WithMetrics(metrics))(next)
+	if code, _ := send(stale, k.Key.Id); code != http.StatusOK {
+		t.Fatalf("expected the request within the budget to pass, got %d", code)
+	}
+	tbl.slow.Store(true)
+	if code, elapsed := send(reject, k.Key.Id); code != http.StatusServiceUnavailable || elapsed > 150*time.Millisecond {
+		t.Errorf("expected the slow key lookup to be rejected within the budget, got %d after %s", code, elapsed)
+	}
+	if code, _ := send(stale, k.Key.Id); code != http.StatusOK {
+		t.Errorf("expected the stale key to be used, got %d", code)
+	}
+	if code, _ := send(stale, "unknown"); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the key never loaded to be rejected, got %d", code)
+	}
+	if n := metrics.count(StageSecretLookup); n != 3 {
+		t.Errorf("expected 3 key lookups exceeding the budget, got %d", n)
+	}
+	tbl.slow.Store(false)
+
+	// the slow route lookup likewise
+	reject = store.Middleware(hash.NewValidator(60), routes, budget(StageRouteLookup, FallbackReject), WithMetrics(metrics))(next)
+	stale = store.Middleware(hash.NewValidator(60), routes, budget(StageRouteLookup, FallbackStale), WithMetrics(metrics))(next)
+	if code, _ := send(stale, k.Key.Id); code != http.StatusOK {
+		t.Fatalf("expected the request within the budget to pass, got %d", code)
+	}
+	routes.slow.Store(true)
+	if code, elapsed := send(reject, k.Key.Id); code != http.StatusServiceUnavailable || elapsed > 150*time.Millisecond {
+		t.Errorf("expected the slow route lookup to be rejected within the budget, got %d after %s", code, elapsed)
+	}
+	if code, _ := send(stale, k.Key.Id); code != http.StatusOK {
+		t.Errorf("expected the stale route to be used, got %d", code)
+	}
+	if n := metrics.count(StageRouteLookup); n != 2 {
+		t.Errorf("expected 2 route lookups exceeding the budget, got %d", n)
+	}
+	routes.slow.Store(false)
+
+	// the slow lockout rejects the requests, or is skipped
+	lockout := NewLockout(LockoutPolicy{}, &slowLockoutStore{LockoutStore: NewMemoryLockoutStore()})
+	reject = store.Middleware(hash.NewValidator(60), routes, WithLockout(lockout), budget(StagePolicy, FallbackReject), WithMetrics(metrics))(next)
+	skip := store.Middleware(hash.NewValidator(60), routes, WithLockout(lockout), budget(StagePolicy, FallbackSkip), WithMetrics(metrics))(next)
+	if code, elapsed := send(reject, k.Key.Id); code != http.StatusServiceUnavailable || elapsed > 150*time.Millisecond {
+		t.Errorf("expected the slow lockout to be rejected within the budget, got %d after %s", code, elapsed)
+	}
+	if code, elapsed := send(skip, k.Key.Id); code != http.StatusOK || elapsed > 150*time.Millisecond {
+		t.Errorf("expected the slow lockout to be skipped, got %d after %s", code, elapsed)
+	}
+	if n := metrics.count(StagePolicy); n != 2 {
+		t.Errorf("expected 2 lockout checks exceeding the budget, got %d", n)
+	}
+
+	// unsupported fallbacks reject the requests
+	tbl.slow.Store(true)
+	defer tbl.slow.Store(false)
+	skip = store.Middleware(hash.NewValidator(60), routes, budget(StageSecretLookup, FallbackSkip))(next)
+	if code, _ := send(skip, k.Key.Id); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the unsupported fallback to reject, got %d", code)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import "time"
+
+const (
+	// API keys collection name, within the database the consumer supplies
+	// via db.Store
+	KeysCollectionName = "api_keys"
+
+	// API key usage collection name, holding the usage per route
+	UsageCollectionName = "api_key_usage"
+
+	// Replay protection collection name, holding the nonces recorded,
+	// see WithReplayProtection
+	ReplayCollectionName = "api_key_replays"
+
+	// DefaultClass is the class of the keys created without one, see the
+	// rotation package for the rotation policies per class.
+	DefaultClass = "default"
+
+	// DefaultGracePeriod is how long the previous generation of the secret
+	// remains valid after a manual rotation, when no grace period is
+	// requested.
+	DefaultGracePeriod = 24 * time.Hour
+
+	// maxSecretUpdates bounds the attempts to update the generations of
+	// the secret of a key changed concurrently.
+	maxSecretUpdates = 5
+)
@@ -0,0 +1,83 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"slices"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// AnyTenant grants the impersonation of the users of every tenant, only
+// to the keys of the root tenancy.
+const AnyTenant = "*"
+
+// ImpersonationGrant allows an API key to sign requests on behalf of the
+// users of the tenants, see hash.WithImpersonation.
+type ImpersonationGrant struct {
+	// tenants whose users may be impersonated, AnyTenant for all
+	Tenants []string `bson:"tenants,omitempty"`
+}
+
+// validateImpersonation ensures the grant, if any, only lists the tenant
+// of the key, unless the key belongs to the root tenancy.
+func validateImpersonation(k *Key, g *ImpersonationGrant) error {
+	if g == nil || k.IsRoot() {
+		return nil
+	}
+	for _, tenant := range g.Tenants {
+		if tenant != k.Tenant {
+			return errors.Wrapf(errors.InvalidArgument, "api key of tenant %s can not impersonate users of tenant %s", k.Tenant, tenant)
+		}
+	}
+	return nil
+}
+
+// CanImpersonate reports whether the key may impersonate the users of the
+// tenant.
+func (k *Key) CanImpersonate(tenant string) bool {
+	if k.Impersonation == nil || tenant == "" {
+		return false
+	}
+	if k.IsRoot() && slices.Contains(k.Impersonation.Tenants, AnyTenant) {
+		return true
+	}
+	return slices.Contains(k.Impersonation.Tenants, tenant) && (k.IsRoot() || tenant == k.Tenant)
+}
+
+// SetImpersonation sets the impersonation grant of the key, nil revoking
+// it.
+func (s *Store) SetImpersonation(ctx context.Context, id string, g *ImpersonationGrant) error {
+	key := &KeyId{Id: id}
+	k, err := s.table.Find(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := validateImpersonation(k, g); err != nil {
+		return err
+	}
+	if g == nil {
+		// an empty grant allows no impersonation, as omitted fields are
+		// not updated
+		g = &ImpersonationGrant{}
+	}
+	return s.table.Update(ctx, key, &Key{Impersonation: g})
+}
+
+// CanImpersonate implements the hash.ImpersonationPolicy, failing with
+// Forbidden unless the active key is granted the impersonation of the
+// users of the target tenant.
+func (s *Store) CanImpersonate(ctx context.Context, keyId string, target *hash.Impersonation) error {
+	k, err := s.activeKey(ctx, keyId)
+	if err != nil {
+		return err
+	}
+	if !k.CanImpersonate(target.Tenant) {
+		return errors.Wrapf(errors.Forbidden, "api key %s can not impersonate users of tenant %s", keyId, target.Tenant)
+	}
+	return nil
+}
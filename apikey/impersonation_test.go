@@ -0,0 +1,69 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+)
+
+func TestImpersonationGrant(t *testing.T) {
+	root := &Key{Tenant: "root", Impersonation: &ImpersonationGrant{Tenants: []string{AnyTenant}}}
+	if !root.CanImpersonate("acme") || root.CanImpersonate("") {
+		t.Errorf("expected root key to impersonate users of any tenant")
+	}
+	tenant := &Key{Tenant: "acme", Impersonation: &ImpersonationGrant{Tenants: []string{"acme", AnyTenant}}}
+	if !tenant.CanImpersonate("acme") || tenant.CanImpersonate("other") {
+		t.Errorf("expected tenant key to impersonate users of its tenant only")
+	}
+	if (&Key{Tenant: "acme"}).CanImpersonate("acme") {
+		t.Errorf("expected key without grant not to impersonate")
+	}
+	if err := validateImpersonation(tenant, &ImpersonationGrant{Tenants: []string{"other"}}); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected grant of another tenant to be invalid, got %v", err)
+	}
+	if err := validateImpersonation(root, root.Impersonation); err != nil {
+		t.Errorf("expected grant of root key to be valid, got %s", err)
+	}
+}
+
+func TestMiddlewareImpersonationHeaders(t *testing.T) {
+	ctx := context.Background()
+	store, _ := NewStoreWithStorage(storage.NewMemoryTable[KeyId, Key](), storage.NewMemoryTable[UsageKey, Usage](), make([]byte, 32))
+	admin, adminSecret, _ := store.Create(ctx, &Key{Owner: "admin", Tenant: "acme", Impersonation: &ImpersonationGrant{Tenants: []string{"acme"}}})
+	plain, plainSecret, _ := store.Create(ctx, &Key{Owner: "svc", Tenant: "acme"})
+
+	var served *model.AuthContext
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served, _ = model.FromContext(r.Context())
+	})
+	routes := &fakeRoutes{route: &route.Route{Key: &route.Key{Url: "/books", Method: route.GET}}}
+	v := hash.NewValidator(60, hash.WithHeaderPrefix("x-acme-"))
+	handler := store.Middleware(v, routes)(next)
+
+	send := func(keyId, secret string) int {
+		served = nil
+		r := hash.NewGenerator(keyId, secret, hash.WithHeaderPrefix("x-acme-"), hash.WithImpersonation("alice", "acme")).
+			AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+	// the impersonation headers are read with the names of the validator
+	if code := send(plain.Key.Id, plainSecret); code != http.StatusForbidden || served != nil {
+		t.Errorf("expected the key without grant to be denied, got %d", code)
+	}
+	if code := send(admin.Key.Id, adminSecret); code != http.StatusOK || served == nil || served.Subject != "alice" || served.ImpersonatedBy != "admin" {
+		t.Errorf("expected the impersonated identity, got %d: %+v", code, served)
+	}
+}
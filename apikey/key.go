@@ -0,0 +1,193 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/go-core-stack/auth/labels"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/throttle"
+)
+
+// KeyId identifies an API key, the key id carried by the signed requests.
+type KeyId struct {
+	Id string `bson:"id,omitempty"`
+}
+
+// Secret is a generation of the secret of an API key.
+type Secret struct {
+	// generation number, increasing with every rotation
+	Generation int32 `bson:"generation,omitempty"`
+
+	// hex sha256 of the secret
+	Hash string `bson:"hash,omitempty"`
+
+	// secret encrypted at rest, required to validate HMAC signatures, or
+	// its verifier if derived for a scope, see WithDerivedVerifiers
+	Sealed string `bson:"sealed,omitempty"`
+
+	// scope the sealed verifier is derived for, empty if the secret
+	// itself is sealed
+	Scope string `bson:"scope,omitempty"`
+
+	// time of creation, unix seconds
+	Created int64 `bson:"created,omitempty"`
+
+	// time the generation is revoked once superseded, unix seconds,
+	// zero while it is the latest generation
+	Expiry int64 `bson:"expiry,omitempty"`
+}
+
+// isValid reports whether the generation is valid at the time.
+func (s *Secret) isValid(now time.Time) bool {
+	return s.Expiry == 0 || s.Expiry > now.Unix()
+}
+
+// RateLimit allows Limit requests per Window seconds to the key.
+type RateLimit struct {
+	Limit  int32 `bson:"limit,omitempty"`
+	Window int64 `bson:"window,omitempty"`
+}
+
+// Policy returns the rate limit as the throttle policy.
+func (l *RateLimit) Policy() *throttle.Policy {
+	return &throttle.Policy{Limit: int(l.Limit), Window: time.Duration(l.Window) * time.Second}
+}
+
+// Key is an API key.
+type Key struct {
+	Key *KeyId `bson:"key,omitempty"`
+
+	// subject owning the key and the tenancy it belongs to, keys of the
+	// root tenancy reach the routes and keys of all the tenants
+	Owner  string `bson:"owner,omitempty"`
+	Tenant string `bson:"tenant,omitempty"`
+
+	// human readable description of the key
+	Description string `bson:"description,omitempty"`
+
+	// class of the key, selecting its rotation policy
+	Class string `bson:"class,omitempty"`
+
+	// scopes restricting the requests the key is usable for, see Scope,
+	// empty grants all the permissions of the owner
+	Scopes []string `bson:"scopes,omitempty"`
+
+	// free form labels of the key
+	Labels labels.Labels `bson:"labels,omitempty"`
+
+	// time of creation and expiry of the key, unix seconds, zero expiry
+	// never expires
+	Created int64 `bson:"created,omitempty"`
+	Expiry  int64 `bson:"expiry,omitempty"`
+
+	// time the key was last used, unix seconds, recorded by the
+	// UsageRecorder
+	LastUsed int64 `bson:"lastUsed,omitempty"`
+
+	// rate limit of the key overriding the default of the
+	// throttle.KeyLimiter, if any
+	RateLimit *RateLimit `bson:"rateLimit,omitempty"`
+
+	// client addresses the key is usable from, any if not set
+	Network *NetworkPolicy `bson:"network,omitempty"`
+
+	// users the key may sign requests on behalf of, none if not set
+	Impersonation *ImpersonationGrant `bson:"impersonation,omitempty"`
+
+	// disabled keys fail validation until enabled again
+	Disabled *bool `bson:"disabled,omitempty"`
+
+	// generations of the secret, ordered by generation
+	Secrets []*Secret `bson:"secrets,omitempty"`
+
+	// revision of the secrets, increased with every change of the
+	// generations guarding against the concurrent changes
+	Revision int64 `bson:"revision,omitempty"`
+}
+
+// IsExpired reports whether the key is expired at the time.
+func (k *Key) IsExpired(now time.Time) bool {
+	return k.Expiry != 0 && k.Expiry <= now.Unix()
+}
+
+// IsDisabled reports whether the key is disabled.
+func (k *Key) IsDisabled() bool {
+	return k.Disabled != nil && *k.Disabled
+}
+
+// IsActive reports whether the key is usable at the time.
+func (k *Key) IsActive(now time.Time) bool {
+	return !k.IsDisabled() && !k.IsExpired(now)
+}
+
+// IsRoot reports whether the key belongs to the root tenancy.
+func (k *Key) IsRoot() bool {
+	return k.Tenant == route.RootTenant
+}
+
+// AuthContext returns the auth context of a request authenticated with
+// the key, on behalf of its owner.
+func (k *Key) AuthContext() *model.AuthContext {
+	a := &model.AuthContext{
+		Tenant:      k.Tenant,
+		Subject:     k.Owner,
+		SubjectType: model.SubjectService,
+		IsRoot:      k.IsRoot(),
+	}
+	if k.Key != nil {
+		a.KeyId = k.Key.Id
+	}
+	return a
+}
+
+// latest returns the latest generation of the secret.
+func (k *Key) latest() *Secret {
+	if len(k.Secrets) == 0 {
+		return nil
+	}
+	return k.Secrets[len(k.Secrets)-1]
+}
+
+// validSecrets returns the generations valid at the time, latest first.
+func (k *Key) validSecrets(now time.Time) []*Secret {
+	list := []*Secret{}
+	for i := len(k.Secrets) - 1; i >= 0; i-- {
+		if s := k.Secrets[i]; s != nil && s.isValid(now) {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// Verify reports whether the secret matches any valid generation of the
+// secret of the active key.
+func (k *Key) Verify(secret string, now time.Time) bool {
+	if !k.IsActive(now) {
+		return false
+	}
+	hash := []byte(hashSecret(secret))
+	for _, s := range k.validSecrets(now) {
+		if subtle.ConstantTimeCompare(hash, []byte(s.Hash)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// redacted returns a copy of the key without the sealed secrets, as
+// returned to the consumers.
+func (k *Key) redacted() *Key {
+	out := *k
+	out.Secrets = make([]*Secret, 0, len(k.Secrets))
+	for _, s := range k.Secrets {
+		c := *s
+		c.Sealed = ""
+		out.Secrets = append(out.Secrets, &c)
+	}
+	return &out
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyVerify(t *testing.T) {
+	now := time.Now()
+	k := &Key{
+		Key: &KeyId{Id: "k1"},
+		Secrets: []*Secret{
+			{Generation: 1, Hash: hashSecret("old"), Expiry: now.Add(-time.Minute).Unix()},
+			{Generation: 2, Hash: hashSecret("previous"), Expiry: now.Add(time.Hour).Unix()},
+			{Generation: 3, Hash: hashSecret("current")},
+		},
+	}
+	if !k.Verify("current", now) || !k.Verify("previous", now) {
+		t.Errorf("expected valid generations to verify")
+	}
+	if k.Verify("old", now) || k.Verify("unknown", now) {
+		t.Errorf("expected revoked or unknown secrets to fail")
+	}
+	if gens := k.validSecrets(now); len(gens) != 2 || gens[0].Generation != 3 {
+		t.Errorf("unexpected valid generations %v", gens)
+	}
+
+	disabled := true
+	k.Disabled = &disabled
+	if k.Verify("current", now) {
+		t.Errorf("expected disabled key to fail")
+	}
+	k.Disabled = nil
+	k.Expiry = now.Unix()
+	if k.Verify("current", now) {
+		t.Errorf("expected expired key to fail")
+	}
+}
+
+func TestKeyRedacted(t *testing.T) {
+	k := &Key{Key: &KeyId{Id: "k1"}, Secrets: []*Secret{{Generation: 1, Hash: "h", Sealed: "s"}}}
+	r := k.redacted()
+	if r.Secrets[0].Sealed != "" || r.Secrets[0].Hash != "h" {
+		t.Errorf("unexpected redacted secret %+v", r.Secrets[0])
+	}
+	if k.Secrets[0].Sealed != "s" {
+		t.Errorf("redaction modified the key")
+	}
+}
+
+func TestSealer(t *testing.T) {
+	if _, err := newSealer([]byte("short")); err == nil {
+		t.Fatalf("expected error for short encryption key")
+	}
+	s, err := newSealer(make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to create sealer: %s", err)
+	}
+	sealed, err := s.seal("k1", 1, "secret")
+	if err != nil {
+		t.Fatalf("failed to seal: %s", err)
+	}
+	got, err := s.open("k1", 1, sealed)
+	if err != nil || got != "secret" {
+		t.Errorf("unexpected open result %q: %v", got, err)
+	}
+	if _, err := s.open("k2", 1, sealed); err == nil {
+		t.Errorf("expected sealed secret bound to its key")
+	}
+	if _, err := s.open("k1", 2, sealed); err == nil {
+		t.Errorf("expected sealed secret bound to its generation")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	p := (&RateLimit{Limit: 100, Window: 60}).Policy()
+	if p.Limit != 100 || p.Window != time.Minute {
+		t.Errorf("unexpected policy %+v", p)
+	}
+	for _, l := range []*RateLimit{{Limit: -1, Window: 60}, {Limit: 10}, {Window: 60}} {
+		if err := validateRateLimit(l); err == nil {
+			t.Errorf("expected rate limit %+v to be rejected", l)
+		}
+	}
+	if err := validateRateLimit(nil); err != nil {
+		t.Errorf("expected no rate limit to be valid: %s", err)
+	}
+}
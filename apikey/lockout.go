@@ -0,0 +1,216 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/internal/lru"
+)
+
+// Defaults of the LockoutPolicy.
+const (
+	// DefaultLockoutThreshold is the number of consecutive signature
+	// failures locking the key or source address
+	DefaultLockoutThreshold = 10
+
+	// DefaultLockoutWindow is the window within which the failures are
+	// counted as consecutive
+	DefaultLockoutWindow = 5 * time.Minute
+
+	// DefaultLockoutDuration is how long the key or source address remains
+	// locked
+	DefaultLockoutDuration = 15 * time.Minute
+)
+
+// LockoutPolicy locks an API key, or a source address, for Duration once
+// Threshold signature failures occur without a success in between, each
+// within Window of the previous one.
+type LockoutPolicy struct {
+	Threshold int
+	Window    time.Duration
+	Duration  time.Duration
+}
+
+// LockedError is returned for the requests of a locked API key or source
+// address.
+type LockedError struct {
+	// locked key id or source address
+	Subject string
+
+	// time the lock is released
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s locked until %s after repeated authentication failures", e.Subject, e.Until.UTC().Format(time.RFC3339))
+}
+
+// LockoutStore keeps the consecutive failures of the API keys and source
+// addresses. Implementations shared across the replicas must count the
+// failures atomically.
+type LockoutStore interface {
+	// Locked returns the time the lock of the subject is released, zero
+	// if not locked.
+	Locked(ctx context.Context, subject string) (time.Time, error)
+
+	// Fail records a failure of the subject, returning the time the lock
+	// is released if the failure locks it, zero otherwise.
+	Fail(ctx context.Context, subject string, p LockoutPolicy) (time.Time, error)
+
+	// Reset clears the failures of the subject after a success.
+	Reset(ctx context.Context, subject string) error
+}
+
+// failures are the consecutive failures of a subject.
+type failures struct {
+	count  int
+	last   time.Time
+	locked time.Time
+}
+
+// maxLockoutEntries bounds the subjects tracked, the least recently seen
+// ones being dropped.
+const maxLockoutEntries = 100000
+
+// memoryLockoutStore keeps the failures in memory.
+type memoryLockoutStore struct {
+	mu      sync.Mutex
+	entries *lru.Cache[string, *failures]
+	now     func() time.Time
+}
+
+// NewMemoryLockoutStore returns a LockoutStore for a single instance,
+// keeping the failures of the most recently seen subjects in memory.
+func NewMemoryLockoutStore() LockoutStore {
+	return &memoryLockoutStore{entries: lru.New[string, *failures](maxLockoutEntries), now: time.Now}
+}
+
+// Locked returns the time the lock of the subject is released.
+func (s *memoryLockoutStore) Locked(ctx context.Context, subject string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if f, ok := s.entries.Get(subject); ok && s.now().Before(f.locked) {
+		return f.locked, nil
+	}
+	return time.Time{}, nil
+}
+
+// Fail records a failure of the subject.
+func (s *memoryLockoutStore) Fail(ctx context.Context, subject string, p LockoutPolicy) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	f, ok := s.entries.Get(subject)
+	if !ok {
+		f = &failures{}
+		s.entries.Add(subject, f)
+	}
+	if now.Sub(f.last) > p.Window {
+		f.count = 0
+	}
+	f.count++
+	f.last = now
+	if f.count >= p.Threshold && !now.Before(f.locked) {
+		f.count = 0
+		f.locked = now.Add(p.Duration)
+		return f.locked, nil
+	}
+	return time.Time{}, nil
+}
+
+// Reset clears the failures of the subject.
+func (s *memoryLockoutStore) Reset(ctx context.Context, subject string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.Remove(subject)
+	return nil
+}
+
+// Lockout tracks the consecutive signature failures per API key id and
+// per source address, locking them as per the policy to slow down secret
+// guessing.
+type Lockout struct {
+	policy LockoutPolicy
+	store  LockoutStore
+	events events.Publisher
+}
+
+// NewLockout creates the lockout enforcing the policy, with the defaults
+// for its unset fields, keeping the failures in store, in memory if nil.
+func NewLockout(p LockoutPolicy, store LockoutStore) *Lockout {
+	if p.Threshold < 1 {
+		p.Threshold = DefaultLockoutThreshold
+	}
+	if p.Window <= 0 {
+		p.Window = DefaultLockoutWindow
+	}
+	if p.Duration <= 0 {
+		p.Duration = DefaultLockoutDuration
+	}
+	if store == nil {
+		store = NewMemoryLockoutStore()
+	}
+	return &Lockout{policy: p, store: store}
+}
+
+// SetPublisher makes the lockout publish an event for every key or source
+// address locked.
+func (l *Lockout) SetPublisher(p events.Publisher) {
+	l.events = p
+}
+
+// lockoutSubjects returns the subjects tracked for a request, its key id
+// if any and its source address.
+func lockoutSubjects(keyId, addr string) []string {
+	if keyId == "" {
+		return []string{"addr:" + addr}
+	}
+	return []string{"key:" + keyId, "addr:" + addr}
+}
+
+// Check returns a LockedError if the key id or the source address is
+// locked.
+func (l *Lockout) Check(ctx context.Context, keyId, addr string) error {
+	for _, subject := range lockoutSubjects(keyId, addr) {
+		until, err := l.store.Locked(ctx, subject)
+		if err != nil {
+			return err
+		}
+		if !until.IsZero() {
+			return &LockedError{Subject: subject, Until: until}
+		}
+	}
+	return nil
+}
+
+// Fail records a signature failure of the key id from the source address,
+// returning a LockedError if the failure locks either of them.
+func (l *Lockout) Fail(ctx context.Context, keyId, addr string) error {
+	var locked error
+	for _, subject := range lockoutSubjects(keyId, addr) {
+		until, err := l.store.Fail(ctx, subject, l.policy)
+		if err != nil {
+			return err
+		}
+		if !until.IsZero() && locked == nil {
+			locked = &LockedError{Subject: subject, Until: until}
+			if l.events != nil {
+				_ = l.events.Publish(ctx, &events.Event{Kind: events.KindLockout, Resource: subject})
+			}
+		}
+	}
+	return locked
+}
+
+// Succeed clears the failures of the key id. The failures of the source
+// address are kept, so that a valid key can't be used to keep guessing the
+// secrets of the other keys from the same address.
+func (l *Lockout) Succeed(ctx context.Context, keyId string) error {
+	return l.store.Reset(ctx, "key:"+keyId)
+}
@@ -0,0 +1,80 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLockout(t *testing.T) {
+	store := NewMemoryLockoutStore()
+	now := time.Now()
+	store.(*memoryLockoutStore).now = func() time.Time { return now }
+	l := NewLockout(LockoutPolicy{Threshold: 3, Window: time.Minute, Duration: 10 * time.Minute}, store)
+	ctx := context.Background()
+
+	for i := range 2 {
+		if err := l.Fail(ctx, "k1", "192.0.2.1"); err != nil {
+			t.Fatalf("unexpected lock after %d failures: %s", i+1, err)
+		}
+	}
+	// a success clears the failures of the key only
+	_ = l.Succeed(ctx, "k1")
+	_ = l.Fail(ctx, "k1", "192.0.2.2")
+	if err := l.Check(ctx, "k1", "192.0.2.3"); err != nil {
+		t.Fatalf("unexpected lock: %s", err)
+	}
+
+	// the third failure from the address locks it, for any key
+	err := l.Fail(ctx, "k2", "192.0.2.1")
+	locked, ok := err.(*LockedError)
+	if !ok || locked.Subject != "addr:192.0.2.1" || !locked.Until.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("expected the address to be locked, got %v", err)
+	}
+	if _, ok := l.Check(ctx, "k3", "192.0.2.1").(*LockedError); !ok {
+		t.Errorf("expected requests from the locked address to be rejected")
+	}
+	if err := l.Check(ctx, "k3", "192.0.2.9"); err != nil {
+		t.Errorf("unexpected lock of another address: %s", err)
+	}
+
+	// failures spread beyond the window are not consecutive
+	for range 3 {
+		now = now.Add(2 * time.Minute)
+		_ = l.Fail(ctx, "k4", "192.0.2.4")
+	}
+	if err := l.Check(ctx, "k4", "192.0.2.4"); err != nil {
+		t.Errorf("unexpected lock: %s", err)
+	}
+
+	// the lock is released after its duration
+	now = now.Add(time.Hour)
+	if err := l.Check(ctx, "k3", "192.0.2.1"); err != nil {
+		t.Errorf("expected the lock to be released: %s", err)
+	}
+}
+
+func TestMemoryLockoutStoreBound(t *testing.T) {
+	store := NewMemoryLockoutStore().(*memoryLockoutStore)
+	p := LockoutPolicy{Threshold: 1, Window: time.Minute, Duration: time.Minute}
+	ctx := context.Background()
+	_, _ = store.Fail(ctx, "key:k1", p)
+
+	// failures sprayed from fresh addresses never grow the store beyond
+	// its bound, the locked subject checked meanwhile being retained
+	for i := range maxLockoutEntries + 100 {
+		_, _ = store.Fail(ctx, fmt.Sprintf("addr:%d", i), LockoutPolicy{Threshold: 10, Window: time.Minute})
+		if i%1000 == 0 {
+			if until, _ := store.Locked(ctx, "key:k1"); until.IsZero() {
+				t.Fatalf("expected the key to remain locked after %d failures", i)
+			}
+		}
+	}
+	if n := store.entries.Len(); n != maxLockoutEntries {
+		t.Errorf("expected %d entries, got %d", maxLockoutEntries, n)
+	}
+}
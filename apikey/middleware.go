@@ -0,0 +1,245 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/audit"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/ipaddr"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// RouteResolver resolves the route of a request for a tenant, implemented
+// by the route.RouteTable.
+type RouteResolver interface {
+	ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error)
+}
+
+// struct identifier for the context
+type keyInfo struct{}
+
+// ContextWithKey returns a new context with the API key attached.
+func ContextWithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, keyInfo{}, k)
+}
+
+// KeyFromContext returns the API key attached to the context by the
+// Middleware.
+func KeyFromContext(ctx context.Context) (*Key, error) {
+	if k, ok := ctx.Value(keyInfo{}).(*Key); ok {
+		return k, nil
+	}
+	return nil, errors.Wrapf(errors.NotFound, "api key not found")
+}
+
+// MiddlewareOption configures the Middleware.
+type MiddlewareOption func(*middlewareOptions)
+
+// middlewareOptions holds the configuration of the Middleware.
+type middlewareOptions struct {
+	// emitter receiving the audit records of the decisions
+	audit audit.Emitter
+
+	// lockout of the keys and source addresses failing validation
+	lockout *Lockout
+
+	// recorder of the usage of the keys
+	usage *UsageRecorder
+
+	// resolver of the client address behind the trusted proxies
+	clients *ipaddr.Resolver
+
+	// record the denials without enforcing them, except for the routes
+	// enforced explicitly
+	shadow bool
+
+	// latency budgets of the stages, see WithBudget
+	budgets map[Stage]Budget
+
+	// metrics of the stages exceeding their budget
+	metrics telemetry.Metrics
+
+	// store of the requests accepted within the replay window, see
+	// WithReplayProtection
+	replays      ReplayStore
+	replayWindow time.Duration
+}
+
+// WithAudit emits an audit.Record of every decision of the Middleware,
+// allowed or denied along with the reason, to the emitter.
+func WithAudit(e audit.Emitter) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.audit = e
+	}
+}
+
+// WithLockout locks the API keys and the source addresses repeatedly
+// failing validation as per the lockout, rejecting their requests with 403
+// and a Retry-After hint until the lock is released. Locking emits an
+// audit.Record of kind audit.KindLockout.
+func WithLockout(l *Lockout) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.lockout = l
+	}
+}
+
+// WithReplayProtection rejects with 401 the validly signed requests whose
+// nonce was already accepted for the key within the window, recording the
+// key id and nonce of the requests in the store, see ReplayStore. The
+// requests signed without a nonce, see hash.WithNonce, are not recorded.
+// The window must cover the validity of the validator. The store is
+// checked within the budget of StagePolicy, see WithBudget.
+func WithReplayProtection(store ReplayStore, window time.Duration) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.replays = store
+		o.replayWindow = window
+	}
+}
+
+// WithUsage records the usage of the keys for the routes of the allowed
+// requests with the recorder, see UsageRecorder.
+func WithUsage(u *UsageRecorder) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.usage = u
+	}
+}
+
+// WithTrustedProxies resolves the client address of the requests received
+// from the trusted proxies using the forwarding headers, see
+// ipaddr.Resolver, for the network policy of the keys, the lockout of the
+// source addresses and the audit records. Otherwise the client resolved by
+// an ipaddr.Resolver middleware in front, or the remote address of the
+// request is used.
+func WithTrustedProxies(proxies ipaddr.Allowlist) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.clients = ipaddr.NewResolver(proxies)
+	}
+}
+
+// WithShadowMode validates every request and records the decisions, but
+// passes the requests that would be denied to the next handler, without the
+// key and model.AuthContext attached, for rolling out the enforcement on an
+// existing fleet. The denials are audited with audit.Record.Shadow set,
+// while the validations are counted as usual by the meter of the
+// validator. The routes with Enforce set are enforced regardless, allowing
+// the enforcement to be turned on route by route.
+func WithShadowMode() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.shadow = true
+	}
+}
+
+// Middleware returns a middleware validating the signed requests with the
+// keys of the store. It rejects with 403 the requests:
+//   - whose route is not reachable from the tenant of the key, or not
+//     covered by the scopes of the key, see Scope
+//   - signed with a locked key, see WithLockout
+//   - from a client address outside the network policy of the key, see
+//     NetworkPolicy
+//   - impersonating a user the key is not granted to, see
+//     ImpersonationGrant
+//
+// Requests failing validation or replayed, see WithReplayProtection, are
+// rejected with 401, and requests without a route with 404. Requests
+// exceeding the latency budget of a stage, see WithBudget, are rejected
+// with 503.
+//
+// The key and the model.AuthContext are attached to the context of the
+// request passed to the next handler. The auth context is the one of the
+// owner of the key, or of the user impersonated. See WithShadowMode for
+// recording the denials without enforcing them.
+func (s *Store) Middleware(v hash.Validator, routes RouteResolver, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	a := s.Authenticator(v, opts...)
+	o := a.o
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			rec := audit.NewRequestRecord(audit.KindAuthentication, r)
+			var rt *route.Route
+			// reject denies the request for the reason with the message,
+			// or only records the denial in shadow mode
+			reject := func(reason, message string, status int) {
+				if o.shadow && !a.enforced(ctx, routes, rec.Tenant, r, &rt) {
+					// the hints of the denial are not part of the response
+					w.Header().Del("Retry-After")
+					rec.Shadow = true
+					if o.audit != nil {
+						o.audit.Emit(ctx, rec.Deny(reason))
+					}
+					next.ServeHTTP(w, r)
+					return
+				}
+				if o.audit != nil {
+					o.audit.Emit(ctx, rec.Deny(reason))
+				}
+				http.Error(w, message, status)
+			}
+			deny := func(reason string, status int) {
+				reject(reason, reason, status)
+			}
+			k, authCtx, _, err := a.authenticate(r, rec)
+			if err != nil {
+				if locked, ok := err.(*LockedError); ok {
+					w.Header().Set("Retry-After", RetryAfter(locked))
+					deny(locked.Error(), http.StatusForbidden)
+					return
+				}
+				switch errors.GetErrCode(err) {
+				case errors.Unauthorized:
+					reject(err.Error(), "authentication failed", http.StatusUnauthorized)
+				case errors.Forbidden:
+					deny(err.Error(), http.StatusForbidden)
+				default:
+					http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+				}
+				return
+			}
+			rt, err = a.resolveRoute(ctx, routes, authCtx.Tenant, r)
+			if err != nil {
+				if _, ok := err.(*BudgetExceededError); ok {
+					http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+					return
+				}
+				deny("route not found", http.StatusNotFound)
+				return
+			}
+			rec.Route = rt.Key.Url
+			if err := authorizeRoute(r, k, authCtx, rt); err != nil {
+				deny(err.Error(), http.StatusForbidden)
+				return
+			}
+			if o.audit != nil {
+				o.audit.Emit(ctx, rec.Allow())
+			}
+			a.recordUsage(k, rt, r)
+			ctx = model.WithAuthContext(ContextWithKey(ctx, k), authCtx)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RetryAfter returns the value of the Retry-After header of the requests
+// rejected for the lock, in seconds.
+func RetryAfter(locked *LockedError) string {
+	return strconv.FormatInt(max(int64(math.Ceil(time.Until(locked.Until).Seconds())), 1), 10)
+}
+
+// enforced reports whether the route of the request is enforced in shadow
+// mode, resolving it for the tenant, if known, unless resolved already.
+func (a *Authenticator) enforced(ctx context.Context, routes RouteResolver, tenant string, r *http.Request, rt **route.Route) bool {
+	if *rt == nil {
+		*rt, _ = a.resolveRoute(ctx, routes, tenant, r)
+	}
+	return *rt != nil && (*rt).Enforce != nil && *(*rt).Enforce
+}
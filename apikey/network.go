@@ -0,0 +1,90 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/ipaddr"
+)
+
+// NetworkPolicy restricts the client addresses an API key is usable from,
+// so that a leaked key is not usable from arbitrary networks. Entries are
+// CIDR prefixes or single addresses, see ipaddr.ParsePrefix.
+type NetworkPolicy struct {
+	// prefixes the key is usable from, empty allows any address not
+	// denied
+	Allow []string `bson:"allow,omitempty"`
+
+	// prefixes the key is never usable from, taking precedence over Allow
+	Deny []string `bson:"deny,omitempty"`
+}
+
+// validateNetwork ensures the entries of the policy, if any, are valid.
+func validateNetwork(p *NetworkPolicy) error {
+	if p == nil {
+		return nil
+	}
+	for _, e := range append(p.Allow, p.Deny...) {
+		if _, err := ipaddr.ParsePrefix(e); err != nil {
+			return errors.Wrapf(errors.InvalidArgument, "invalid network policy: %s", err)
+		}
+	}
+	return nil
+}
+
+// Allows reports whether the policy allows the address, a nil policy
+// allowing any address. Malformed allow entries never match, while
+// malformed deny entries are ignored.
+func (p *NetworkPolicy) Allows(addr netip.Addr) bool {
+	if p == nil {
+		return true
+	}
+	if !addr.IsValid() {
+		return len(p.Allow) == 0 && len(p.Deny) == 0
+	}
+	addr = ipaddr.Normalize(addr)
+	if matchesAny(p.Deny, addr) {
+		return false
+	}
+	return len(p.Allow) == 0 || matchesAny(p.Allow, addr)
+}
+
+// matchesAny reports whether the address is within one of the entries,
+// skipping the malformed ones.
+func matchesAny(entries []string, addr netip.Addr) bool {
+	for _, e := range entries {
+		if prefix, err := ipaddr.ParsePrefix(e); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsAddr reports whether the key is usable from the client address as
+// per its network policy.
+func (k *Key) AllowsAddr(addr netip.Addr) bool {
+	return k.Network.Allows(addr)
+}
+
+// SetNetworkPolicy sets the network policy of the key, nil allowing the
+// key from any address.
+func (s *Store) SetNetworkPolicy(ctx context.Context, id string, p *NetworkPolicy) error {
+	if err := validateNetwork(p); err != nil {
+		return err
+	}
+	key := &KeyId{Id: id}
+	if _, err := s.table.Find(ctx, key); err != nil {
+		return err
+	}
+	if p == nil {
+		// an empty policy allows any address, as omitted fields are not
+		// updated
+		p = &NetworkPolicy{}
+	}
+	return s.table.Update(ctx, key, &Key{Network: p})
+}
@@ -0,0 +1,44 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func TestNetworkPolicy(t *testing.T) {
+	p := &NetworkPolicy{Allow: []string{"10.0.0.0/8", "2001:db8::/32"}, Deny: []string{"10.1.0.0/16"}}
+	if err := validateNetwork(p); err != nil {
+		t.Fatalf("expected valid policy, got %s", err)
+	}
+	k := &Key{Network: p}
+	cases := map[string]bool{
+		"10.0.0.1":        true,
+		"::ffff:10.0.0.1": true,
+		"2001:db8::1":     true,
+		"10.1.0.1":        false,
+		"192.0.2.1":       false,
+	}
+	for addr, allowed := range cases {
+		if got := k.AllowsAddr(netip.MustParseAddr(addr)); got != allowed {
+			t.Errorf("AllowsAddr(%s) = %v, expected %v", addr, got, allowed)
+		}
+	}
+	if k.AllowsAddr(netip.Addr{}) {
+		t.Errorf("expected unknown address not allowed by a restricted key")
+	}
+	if !(&Key{}).AllowsAddr(netip.MustParseAddr("192.0.2.1")) {
+		t.Errorf("expected key without policy usable from any address")
+	}
+	deny := &Key{Network: &NetworkPolicy{Deny: []string{"192.0.2.0/24"}}}
+	if deny.AllowsAddr(netip.MustParseAddr("192.0.2.1")) || !deny.AllowsAddr(netip.MustParseAddr("198.51.100.1")) {
+		t.Errorf("unexpected decisions of a deny only policy")
+	}
+	if err := validateNetwork(&NetworkPolicy{Allow: []string{"10.0.0.0/33"}}); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected invalid policy, got %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+
+	"github.com/go-core-stack/auth/storage"
+)
+
+/*
+This file provides the replay protection of the Middleware and the
+Authenticator, see WithReplayProtection. The nonce signed along with every
+validly signed request, see hash.WithNonce, is recorded per key in a
+ReplayStore for the replay window, the requests carrying a nonce already
+recorded being rejected with 401. The requests signed without a nonce are
+not recorded: their signature covers a timestamp of one second resolution
+only, identical requests signed within the same second, e.g. parallel
+polls, being legitimate.
+
+The nonces remain in the store once expired, until deleted by the
+ReplayCollector running in the background, in batches so that a backlog
+of expired nonces doesn't hold the store for long, and counting the
+nonces collected, see ReplayStats. Otherwise the store would grow without
+bound under sustained traffic.
+
+The stores shared by the replicas record the nonces atomically, so that a
+request replayed to another replica is rejected as well:
+
+  - NewStoreReplayStore, a collection of the core db store, or
+    NewReplayStoreWithStorage over any storage.Table, claiming the expired
+    nonces not collected yet with a conditional update
+  - a Redis adapter is expected to record every nonce as a key with the
+    replay window as TTL, using SET with NX and PX, the keys expiring on
+    their own so that its Collect has nothing to delete. Sorted sets per
+    API key, scored by the expiry, are to be avoided: they need a
+    ZREMRANGEBYSCORE compaction of every key in the collector, and a ZADD
+    racing with it could record a replayed nonce as new
+
+# Usage
+
+    // clients
+    gen := hash.NewGenerator(keyId, secret, hash.WithNonce())
+
+    replays, _ := apikey.NewStoreReplayStore(dbStore)
+    collector := apikey.NewReplayCollector(replays, time.Minute, 0)
+    go collector.Run(ctx)
+    handler = store.Middleware(validator, routeTable,
+        apikey.WithReplayProtection(replays, 2*time.Minute))(handler)
+*/
+
+// Defaults of the ReplayCollector.
+const (
+	// DefaultReplayCollectInterval is how often the expired nonces are
+	// collected
+	DefaultReplayCollectInterval = time.Minute
+
+	// DefaultReplayCollectBatch is the maximum number of expired nonces
+	// deleted at once
+	DefaultReplayCollectBatch = 1000
+)
+
+// ReplayStore records the nonces of the requests accepted, see the file
+// documentation. Implementations shared across the replicas must record
+// the nonces atomically.
+type ReplayStore interface {
+	// Seen records the request id, the key id and nonce of the request,
+	// until the expiry, reporting whether it was already
+	// recorded and not expired.
+	Seen(ctx context.Context, id string, expiry time.Time) (bool, error)
+
+	// Collect deletes up to batch request ids expired, returning the
+	// number deleted.
+	Collect(ctx context.Context, batch int) (int, error)
+}
+
+// replayEntry is a request id recorded along with its expiry.
+type replayEntry struct {
+	id     string
+	expiry time.Time
+}
+
+// memoryReplayStore records the request ids in memory.
+type memoryReplayStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+
+	// request ids in the order recorded, for the collection, the ones
+	// recorded once again being skipped until their last expiry
+	order []replayEntry
+	now   func() time.Time
+}
+
+// NewMemoryReplayStore returns a ReplayStore for a single instance,
+// keeping the request ids in memory.
+func NewMemoryReplayStore() ReplayStore {
+	return &memoryReplayStore{entries: map[string]time.Time{}, now: time.Now}
+}
+
+// Seen records the request id until the expiry.
+func (s *memoryReplayStore) Seen(ctx context.Context, id string, expiry time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.entries[id]; ok && s.now().Before(cur) {
+		return true, nil
+	}
+	s.entries[id] = expiry
+	s.order = append(s.order, replayEntry{id: id, expiry: expiry})
+	return false, nil
+}
+
+// Collect deletes up to batch request ids expired, in the order recorded.
+func (s *memoryReplayStore) Collect(ctx context.Context, batch int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	n, deleted := 0, 0
+	for ; n < len(s.order) && deleted < batch; n++ {
+		e := s.order[n]
+		if now.Before(e.expiry) {
+			break
+		}
+		if cur, ok := s.entries[e.id]; ok && cur.Equal(e.expiry) {
+			delete(s.entries, e.id)
+			deleted++
+		}
+	}
+	s.order = s.order[n:]
+	return deleted, nil
+}
+
+// ReplayKey identifies a recorded request.
+type ReplayKey struct {
+	Id string `bson:"id,omitempty"`
+}
+
+// Replay is a recorded request.
+type Replay struct {
+	Key *ReplayKey `bson:"key,omitempty"`
+
+	// time the record expires, unix seconds
+	Expiry int64 `bson:"expiry,omitempty"`
+}
+
+// tableReplayStore records the request ids in a table.
+type tableReplayStore struct {
+	table storage.Table[ReplayKey, Replay]
+	now   func() time.Time
+}
+
+// NewStoreReplayStore creates the replay table in the database supplied by
+// the consumer.
+func NewStoreReplayStore(store db.Store) (ReplayStore, error) {
+	if store == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "apikey: db store is required")
+	}
+	tbl, err := storage.NewStoreTable[ReplayKey, Replay](store.GetCollection(ReplayCollectionName))
+	if err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "apikey: failed to initialize replay table: %s", err)
+	}
+	return NewReplayStoreWithStorage(tbl)
+}
+
+// NewReplayStoreWithStorage creates the store recording the request ids
+// in the storage, e.g. storage.NewMemoryTable for the tests.
+func NewReplayStoreWithStorage(tbl storage.Table[ReplayKey, Replay]) (ReplayStore, error) {
+	if tbl == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "apikey: replay storage is required")
+	}
+	return &tableReplayStore{table: tbl, now: time.Now}, nil
+}
+
+// Seen records the request id until the expiry, claiming the expired
+// record of the request id not collected yet.
+func (s *tableReplayStore) Seen(ctx context.Context, id string, expiry time.Time) (bool, error) {
+	key := &ReplayKey{Id: id}
+	entry := &Replay{Key: key, Expiry: expiry.Unix()}
+	err := s.table.Insert(ctx, key, entry)
+	if errors.GetErrCode(err) == errors.AlreadyExists {
+		filter := bson.D{{Key: "expiry", Value: bson.D{{Key: "$lte", Value: s.now().Unix()}}}}
+		err = s.table.UpdateIf(ctx, key, filter, &Replay{Expiry: entry.Expiry})
+		if errors.IsNotFound(err) {
+			// collected meanwhile
+			err = s.table.Insert(ctx, key, entry)
+		}
+	}
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.GetErrCode(err) == errors.AlreadyExists:
+		return true, nil
+	}
+	return false, err
+}
+
+// Collect deletes up to batch request ids expired.
+func (s *tableReplayStore) Collect(ctx context.Context, batch int) (int, error) {
+	expired := bson.E{Key: "expiry", Value: bson.D{{Key: "$lte", Value: s.now().Unix()}}}
+	list, err := s.table.FindManyWithOpts(ctx, bson.D{expired}, table.WithLimit(int32(batch)))
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+	if len(list) == 0 {
+		return 0, nil
+	}
+	ids := make(bson.A, 0, len(list))
+	for _, entry := range list {
+		ids = append(ids, entry.Key.Id)
+	}
+	// the request ids recorded once again meanwhile are kept
+	n, err := s.table.DeleteByFilter(ctx, bson.D{
+		{Key: "_id.id", Value: bson.D{{Key: "$in", Value: ids}}},
+		expired,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// ReplayStats captures the counters of a ReplayCollector.
+type ReplayStats struct {
+	Collected uint64 // expired request ids deleted
+	Batches   uint64 // batches deleting at least one request id
+	Failures  uint64 // batches failing to collect
+}
+
+// ReplayCollector deletes the expired request ids of a ReplayStore in
+// batches, see the file documentation. It is safe for concurrent use.
+type ReplayCollector struct {
+	store    ReplayStore
+	interval time.Duration
+	batch    int
+
+	collected atomic.Uint64
+	batches   atomic.Uint64
+	failures  atomic.Uint64
+}
+
+// NewReplayCollector creates the collector deleting the expired request
+// ids of the store every interval, DefaultReplayCollectInterval if zero,
+// in batches of up to batch, DefaultReplayCollectBatch if zero, once
+// started with Run.
+func NewReplayCollector(store ReplayStore, interval time.Duration, batch int) *ReplayCollector {
+	if interval <= 0 {
+		interval = DefaultReplayCollectInterval
+	}
+	if batch <= 0 {
+		batch = DefaultReplayCollectBatch
+	}
+	return &ReplayCollector{store: store, interval: interval, batch: batch}
+}
+
+// Collect deletes the expired request ids, batch after batch until a batch
+// is not full, returning the number deleted.
+func (c *ReplayCollector) Collect(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := c.store.Collect(ctx, c.batch)
+		if err != nil {
+			c.failures.Add(1)
+			return total, err
+		}
+		if n > 0 {
+			c.batches.Add(1)
+			c.collected.Add(uint64(n))
+		}
+		total += n
+		if n < c.batch || ctx.Err() != nil {
+			return total, ctx.Err()
+		}
+	}
+}
+
+// Run collects the expired request ids every interval, until the context
+// is done.
+func (c *ReplayCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, _ = c.Collect(ctx)
+	}
+}
+
+// Stats returns a snapshot of the counters of the collector.
+func (c *ReplayCollector) Stats() ReplayStats {
+	return ReplayStats{
+		Collected: c.collected.Load(),
+		Batches:   c.batches.Load(),
+		Failures:  c.failures.Load(),
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+)
+
+func TestReplayStores(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	clock := func() time.Time { return now }
+	memory := NewMemoryReplayStore()
+	memory.(*memoryReplayStore).now = clock
+	tbl, _ := NewReplayStoreWithStorage(storage.NewMemoryTable[ReplayKey, Replay]())
+	tbl.(*tableReplayStore).now = clock
+
+	for name, store := range map[string]ReplayStore{"memory": memory, "table": tbl} {
+		t.Run(name, func(t *testing.T) {
+			now = time.Now()
+			for i := range 5 {
+				if seen, err := store.Seen(ctx, fmt.Sprintf("k1:%d", i), now.Add(time.Minute)); seen || err != nil {
+					t.Fatalf("expected the request %d to be new, got %v, %v", i, seen, err)
+				}
+			}
+			if seen, err := store.Seen(ctx, "k1:0", now.Add(time.Minute)); !seen || err != nil {
+				t.Errorf("expected the replayed request to be seen, got %v, %v", seen, err)
+			}
+
+			// the expired requests are new once again, until collected
+			now = now.Add(2 * time.Minute)
+			if seen, err := store.Seen(ctx, "k1:0", now.Add(time.Minute)); seen || err != nil {
+				t.Errorf("expected the expired request to be new, got %v, %v", seen, err)
+			}
+			if n, err := store.Collect(ctx, 3); n != 3 || err != nil {
+				t.Errorf("expected a batch of 3 expired requests collected, got %d, %v", n, err)
+			}
+			if n, err := store.Collect(ctx, 3); n != 1 || err != nil {
+				t.Errorf("expected the last expired request collected, got %d, %v", n, err)
+			}
+			if seen, _ := store.Seen(ctx, "k1:0", now.Add(time.Minute)); !seen {
+				t.Errorf("expected the request recorded once again to be kept")
+			}
+			if seen, _ := store.Seen(ctx, "k1:1", now.Add(time.Minute)); seen {
+				t.Errorf("expected the collected request to be new")
+			}
+		})
+	}
+}
+
+func TestReplayCollector(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryReplayStore()
+	now := time.Now()
+	store.(*memoryReplayStore).now = func() time.Time { return now }
+	for i := range 25 {
+		_, _ = store.Seen(ctx, fmt.Sprintf("k1:%d", i), now.Add(time.Minute))
+	}
+	c := NewReplayCollector(store, time.Minute, 10)
+	if n, err := c.Collect(ctx); n != 0 || err != nil {
+		t.Errorf("expected nothing to collect, got %d, %v", n, err)
+	}
+	now = now.Add(2 * time.Minute)
+	if n, err := c.Collect(ctx); n != 25 || err != nil {
+		t.Errorf("expected all the expired requests collected, got %d, %v", n, err)
+	}
+	if stats := c.Stats(); stats != (ReplayStats{Collected: 25, Batches: 3}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if n := len(store.(*memoryReplayStore).entries); n != 0 {
+		t.Errorf("expected the store to be empty, got %d", n)
+	}
+}
+
+func TestMiddlewareReplayProtection(t *testing.T) {
+	ctx := context.Background()
+	store, _ := NewStoreWithStorage(storage.NewMemoryTable[KeyId, Key](), storage.NewMemoryTable[UsageKey, Usage](), make([]byte, 32))
+	k, secret, _ := store.Create(ctx, &Key{Owner: "svc", Tenant: "acme"})
+	routes := &fakeRoutes{route: &route.Route{Key: &route.Key{Url: "/books", Method: route.GET}}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	replays := NewMemoryReplayStore()
+	handler := store.Middleware(hash.NewValidator(60), routes, WithReplayProtection(replays, 2*time.Minute))(next)
+
+	send := func(r *http.Request) int {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.Clone(ctx))
+		return w.Code
+	}
+	gen := hash.NewGenerator(k.Key.Id, secret, hash.WithNonce())
+	signed := gen.AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+	if code := send(signed); code != http.StatusOK {
+		t.Fatalf("expected the request to pass, got %d", code)
+	}
+	if code := send(signed); code != http.StatusUnauthorized {
+		t.Errorf("expected the replayed request to be rejected, got %d", code)
+	}
+	// identical requests signed within the same second are distinct with
+	// a nonce, and not recorded without
+	for _, g := range []hash.Generator{gen, hash.NewGenerator(k.Key.Id, secret, hash.WithEpochTimestamp())} {
+		first := g.AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+		second := g.AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+		if code1, code2 := send(first), send(second); code1 != http.StatusOK || code2 != http.StatusOK {
+			t.Errorf("expected the identical requests to pass, got %d and %d", code1, code2)
+		}
+	}
+	if code := send(hash.NewGenerator(k.Key.Id, secret).AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))); code != http.StatusOK {
+		t.Errorf("expected the request without nonce to pass, got %d", code)
+	}
+	replays.(*memoryReplayStore).mu.Lock()
+	recorded := len(replays.(*memoryReplayStore).entries)
+	replays.(*memoryReplayStore).mu.Unlock()
+	if recorded != 3 {
+		t.Errorf("expected the 3 requests with a nonce to be recorded, got %d", recorded)
+	}
+	// the requests failing validation are not recorded
+	forged := signed.Clone(ctx)
+	forged.Header.Set("x-signature", "00"+forged.Header.Get("x-signature")[2:])
+	if code := send(forged); code != http.StatusUnauthorized {
+		t.Errorf("expected the forged request to be rejected, got %d", code)
+	}
+	if n := len(replays.(*memoryReplayStore).entries); n != recorded {
+		t.Errorf("expected only the valid requests to be recorded, got %d", n)
+	}
+
+	// the slow replay store rejects the requests, or is skipped
+	slow := &slowReplayStore{ReplayStore: NewMemoryReplayStore()}
+	budget := Budget{Timeout: 20 * time.Millisecond, Fallback: FallbackSkip}
+	handler = store.Middleware(hash.NewValidator(60), routes, WithReplayProtection(slow, 2*time.Minute), WithBudget(StagePolicy, budget))(next)
+	if code := send(signed); code != http.StatusOK {
+		t.Errorf("expected the slow replay store to be skipped, got %d", code)
+	}
+	budget.Fallback = FallbackReject
+	handler = store.Middleware(hash.NewValidator(60), routes, WithReplayProtection(slow, 2*time.Minute), WithBudget(StagePolicy, budget))(next)
+	if code := send(signed); code != http.StatusServiceUnavailable {
+		t.Errorf("expected the slow replay store to be rejected, got %d", code)
+	}
+}
+
+// slowReplayStore delays the recording of the requests.
+type slowReplayStore struct {
+	ReplayStore
+}
+
+func (s *slowReplayStore) Seen(ctx context.Context, id string, expiry time.Time) (bool, error) {
+	time.Sleep(200 * time.Millisecond)
+	return s.ReplayStore.Seen(ctx, id, expiry)
+}
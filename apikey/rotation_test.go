@@ -0,0 +1,87 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/rotation"
+	"github.com/go-core-stack/auth/storage"
+)
+
+// racingTable rotates the key concurrently before the first conditional
+// update of the secrets.
+type racingTable struct {
+	storage.Table[KeyId, Key]
+	race func()
+}
+
+func (t *racingTable) UpdateIf(ctx context.Context, key *KeyId, filter any, entry *Key) error {
+	if race := t.race; race != nil {
+		t.race = nil
+		race()
+	}
+	return t.Table.UpdateIf(ctx, key, filter, entry)
+}
+
+func TestStoreConcurrentRotation(t *testing.T) {
+	ctx := context.Background()
+	tbl := &racingTable{Table: storage.NewMemoryTable[KeyId, Key]()}
+	store, _ := NewStoreWithStorage(tbl, storage.NewMemoryTable[UsageKey, Usage](), make([]byte, 32))
+	k, _, _ := store.Create(ctx, &Key{Owner: "alice"})
+
+	// the rotation racing with another one is retried on top of it
+	var raced string
+	tbl.race = func() {
+		raced, _ = store.Rotate(ctx, k.Key.Id, time.Hour)
+	}
+	rotated, err := store.Rotate(ctx, k.Key.Id, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to rotate key: %s", err)
+	}
+	stored, _ := tbl.Find(ctx, k.Key)
+	if len(stored.Secrets) != 3 || stored.Revision != 2 {
+		t.Fatalf("expected both the rotations to be kept, got %d generations at revision %d", len(stored.Secrets), stored.Revision)
+	}
+	for i, plaintext := range []string{raced, rotated} {
+		if sec := stored.Secrets[i+1]; sec.Generation != int32(i+2) || sec.Hash != hashSecret(plaintext) {
+			t.Errorf("unexpected generation %d of the secret", sec.Generation)
+		}
+	}
+
+	// the rotations racing with the scheduler do not create a generation
+	// past the current one
+	current := &rotation.Generation{KeyId: k.Key.Id, Generation: 3}
+	tbl.race = func() {
+		_, _ = store.Rotate(ctx, k.Key.Id, time.Hour)
+	}
+	if _, err := store.CreateGeneration(ctx, current); errors.GetErrCode(err) != errors.AlreadyExists {
+		t.Errorf("expected the stale generation to be rejected, got %v", err)
+	}
+
+	// concurrent rotations each create their own generation, conflicting
+	// with fewer rotations than the attempts
+	secrets := make(chan string, maxSecretUpdates-1)
+	for range cap(secrets) {
+		go func() {
+			secret, err := store.Rotate(ctx, k.Key.Id, time.Hour)
+			if err != nil {
+				secret = err.Error()
+			}
+			secrets <- secret
+		}()
+	}
+	for range cap(secrets) {
+		secret := <-secrets
+		stored, _ = tbl.Find(ctx, k.Key)
+		if !slices.ContainsFunc(stored.Secrets, func(sec *Secret) bool { return sec.Hash == hashSecret(secret) }) {
+			t.Errorf("expected the rotated secret to be kept, got %q", secret)
+		}
+	}
+}
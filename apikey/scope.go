@@ -0,0 +1,104 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"strings"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/route"
+)
+
+// RouteScopePrefix prefixes the scopes restricting a key to route
+// patterns.
+const RouteScopePrefix = "route:"
+
+// Scope restricts the requests a key is usable for, either to a resource
+// and verb, as "<resource>:<verb>", or to a route pattern, as
+// "route:[<METHOD> ]<url>", where the url is a route template, see
+// route.MatchPath, e.g. "books:get", "books:*" or "route:GET /books/*".
+type Scope struct {
+	// resource and verb granted, "*" matching any
+	Resource string
+	Verb     string
+
+	// method and route template granted, an empty method matching any
+	Method string
+	Url    string
+}
+
+// ParseScope parses the scope string.
+func ParseScope(s string) (*Scope, error) {
+	if pattern, ok := strings.CutPrefix(s, RouteScopePrefix); ok {
+		method, url, found := strings.Cut(strings.TrimSpace(pattern), " ")
+		if !found {
+			method, url = "", method
+		}
+		if method != "" {
+			if _, err := route.ParseMethod(method); err != nil {
+				return nil, errors.Wrapf(errors.InvalidArgument, "invalid scope %q: %s", s, err)
+			}
+		}
+		if !strings.HasPrefix(url, "/") {
+			return nil, errors.Wrapf(errors.InvalidArgument, "invalid scope %q: route url must start with /", s)
+		}
+		return &Scope{Method: strings.ToUpper(method), Url: url}, nil
+	}
+	resource, verb, found := strings.Cut(s, ":")
+	if !found || resource == "" || verb == "" {
+		return nil, errors.Wrapf(errors.InvalidArgument, "invalid scope %q: expected <resource>:<verb> or %s<url>", s, RouteScopePrefix)
+	}
+	return &Scope{Resource: resource, Verb: verb}, nil
+}
+
+// wildcardMatch reports whether the value matches the pattern.
+func wildcardMatch(pattern, value string) bool {
+	return pattern == "*" || pattern == value
+}
+
+// Covers reports whether the scope grants the request for the route,
+// matched by its resource and verb or by its method and url.
+func (s *Scope) Covers(r *route.Route, method, path string) bool {
+	if s.Url != "" {
+		if s.Method != "" && !strings.EqualFold(s.Method, method) {
+			return false
+		}
+		if r != nil && r.Key != nil && s.Url == r.Key.Url {
+			return true
+		}
+		_, ok := route.MatchPath(s.Url, path)
+		return ok
+	}
+	if r == nil || r.Resource == "" {
+		return false
+	}
+	return wildcardMatch(s.Resource, r.Resource) && wildcardMatch(s.Verb, r.Verb)
+}
+
+// validateScopes ensures all the scopes are well formed.
+func validateScopes(scopes []string) error {
+	for _, s := range scopes {
+		if _, err := ParseScope(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Covers reports whether the scopes of the key grant the request for the
+// route, keys without scopes being unrestricted, while malformed scopes
+// never grant anything.
+func (k *Key) Covers(r *route.Route, method, path string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+	for _, str := range k.Scopes {
+		s, err := ParseScope(str)
+		if err == nil && s.Covers(r, method, path) {
+			return true
+		}
+	}
+	return false
+}
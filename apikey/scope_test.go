@@ -0,0 +1,67 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"testing"
+
+	"github.com/go-core-stack/auth/route"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		scope string
+		want  Scope
+		fail  bool
+	}{
+		{scope: "books:get", want: Scope{Resource: "books", Verb: "get"}},
+		{scope: "route:GET /books/*", want: Scope{Method: "GET", Url: "/books/*"}},
+		{scope: "route:/books/{id}", want: Scope{Url: "/books/{id}"}},
+		{scope: "books", fail: true},
+		{scope: "route:FETCH /books", fail: true},
+		{scope: "route:books", fail: true},
+	}
+	for _, tt := range tests {
+		s, err := ParseScope(tt.scope)
+		if tt.fail {
+			if err == nil {
+				t.Errorf("expected error for scope %q", tt.scope)
+			}
+			continue
+		}
+		if err != nil || *s != tt.want {
+			t.Errorf("ParseScope(%q) = %+v, %v", tt.scope, s, err)
+		}
+	}
+}
+
+func TestKeyCovers(t *testing.T) {
+	books := &route.Route{Key: &route.Key{Url: "/books/{id}", Method: route.GET}, Resource: "books", Verb: "get"}
+	authors := &route.Route{Key: &route.Key{Url: "/authors", Method: route.POST}, Resource: "authors", Verb: "create"}
+
+	k := &Key{}
+	if !k.Covers(authors, "POST", "/authors") {
+		t.Errorf("expected key without scopes to be unrestricted")
+	}
+
+	k.Scopes = []string{"books:*"}
+	if !k.Covers(books, "GET", "/books/1") || k.Covers(authors, "POST", "/authors") {
+		t.Errorf("unexpected coverage of resource scope")
+	}
+
+	k.Scopes = []string{"route:GET /books/*"}
+	if !k.Covers(books, "GET", "/books/1") || k.Covers(books, "DELETE", "/books/1") {
+		t.Errorf("unexpected coverage of route scope")
+	}
+
+	k.Scopes = []string{"route:/books/{id}"}
+	if !k.Covers(books, "PUT", "/books/1") || k.Covers(authors, "POST", "/authors") {
+		t.Errorf("unexpected coverage of route template scope")
+	}
+
+	k.Scopes = []string{"malformed"}
+	if k.Covers(books, "GET", "/books/1") {
+		t.Errorf("expected malformed scope to grant nothing")
+	}
+}
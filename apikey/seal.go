@@ -0,0 +1,75 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// sealer encrypts the secrets at rest using AES-256-GCM, as the HMAC
+// validation requires the plaintext secret on the server side, binding
+// every sealed secret to its key id and generation.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+// newSealer creates a sealer using the 32 bytes encryption key.
+func newSealer(key []byte) (*sealer, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// additionalData returns the data authenticated along with the sealed
+// secret, preventing sealed secrets to be swapped between keys.
+func additionalData(keyId string, generation int32) []byte {
+	return fmt.Appendf(nil, "%s/%d", keyId, generation)
+}
+
+// seal encrypts the secret, returning the base64 encoding of the nonce
+// followed by the cipher text.
+func (s *sealer) seal(keyId string, generation int32, secret string) (string, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	out := s.aead.Seal(nonce, nonce, []byte(secret), additionalData(keyId, generation))
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// open decrypts the sealed secret.
+func (s *sealer) open(keyId string, generation int32, sealed string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(b) < s.aead.NonceSize() {
+		return "", fmt.Errorf("invalid sealed secret")
+	}
+	n := s.aead.NonceSize()
+	out, err := s.aead.Open(nil, b[:n], b[n:], additionalData(keyId, generation))
+	if err != nil {
+		return "", fmt.Errorf("failed to open sealed secret: %w", err)
+	}
+	return string(out), nil
+}
+
+// hashSecret returns the hex sha256 of the secret, stored to verify
+// secrets presented as such, e.g. bearer credentials.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
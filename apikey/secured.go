@@ -0,0 +1,83 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"time"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+// SecuredStore exposes the API key management on behalf of a caller,
+// authorizing every call against the built-in auth.keys resource.
+type SecuredStore struct {
+	store *Store
+	authz rbac.Authorizer
+}
+
+// NewSecuredStore creates the secured view of the store.
+func NewSecuredStore(store *Store, authz rbac.Authorizer) *SecuredStore {
+	return &SecuredStore{
+		store: store,
+		authz: authz,
+	}
+}
+
+// Create creates the API key, requiring create on auth.keys.
+func (s *SecuredStore) Create(ctx context.Context, id *authctx.Identity, k *Key) (*Key, string, error) {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbCreate); err != nil {
+		return nil, "", err
+	}
+	return s.store.Create(ctx, k)
+}
+
+// Get returns the API key, requiring get on auth.keys.
+func (s *SecuredStore) Get(ctx context.Context, id *authctx.Identity, keyId string) (*Key, error) {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbGet); err != nil {
+		return nil, err
+	}
+	return s.store.Get(ctx, keyId)
+}
+
+// List returns the API keys, requiring list on auth.keys.
+func (s *SecuredStore) List(ctx context.Context, id *authctx.Identity, tenant, owner string) ([]*Key, error) {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbList); err != nil {
+		return nil, err
+	}
+	return s.store.List(ctx, tenant, owner)
+}
+
+// Rotate rotates the secret of the API key, requiring update on auth.keys.
+func (s *SecuredStore) Rotate(ctx context.Context, id *authctx.Identity, keyId string, grace time.Duration) (string, error) {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbUpdate); err != nil {
+		return "", err
+	}
+	return s.store.Rotate(ctx, keyId, grace)
+}
+
+// Disable disables the API key, requiring update on auth.keys.
+func (s *SecuredStore) Disable(ctx context.Context, id *authctx.Identity, keyId string) error {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbUpdate); err != nil {
+		return err
+	}
+	return s.store.Disable(ctx, keyId)
+}
+
+// Enable enables the API key, requiring update on auth.keys.
+func (s *SecuredStore) Enable(ctx context.Context, id *authctx.Identity, keyId string) error {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbUpdate); err != nil {
+		return err
+	}
+	return s.store.Enable(ctx, keyId)
+}
+
+// Delete deletes the API key, requiring delete on auth.keys.
+func (s *SecuredStore) Delete(ctx context.Context, id *authctx.Identity, keyId string) error {
+	if err := rbac.Check(ctx, s.authz, id, rbac.ResourceKeys, rbac.VerbDelete); err != nil {
+		return err
+	}
+	return s.store.Delete(ctx, keyId)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/audit"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+)
+
+// fakeRoutes resolves every request to the route
+type fakeRoutes struct {
+	route *route.Route
+}
+
+func (f *fakeRoutes) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	return f.route, nil
+}
+
+func TestShadowMode(t *testing.T) {
+	lockout := NewLockout(LockoutPolicy{Threshold: 1, Window: time.Minute, Duration: time.Minute}, NewMemoryLockoutStore())
+	_ = lockout.Fail(context.Background(), "k1", "192.0.2.1")
+	records := []*audit.Record{}
+	emitter := audit.EmitterFunc(func(ctx context.Context, rec *audit.Record) {
+		records = append(records, rec)
+	})
+	routes := &fakeRoutes{route: &route.Route{Key: &route.Key{Url: "/books", Method: route.GET}}}
+	served := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	})
+	handler := (&Store{}).Middleware(hash.NewValidator(60), routes, WithLockout(lockout), WithAudit(emitter), WithShadowMode())(next)
+
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.Header.Set("x-api-key-id", "k1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if !served || rec.Code != http.StatusOK || rec.Header().Get("Retry-After") != "" {
+		t.Errorf("expected the request to pass in shadow mode, got %d", rec.Code)
+	}
+	if len(records) != 1 || records[0].Result != audit.ResultDenied || !records[0].Shadow {
+		t.Errorf("expected a shadow denial to be audited, got %+v", records)
+	}
+
+	// routes enforced explicitly are denied
+	served, records = false, nil
+	enforce := true
+	routes.route.Enforce = &enforce
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if served || rec.Code != http.StatusForbidden {
+		t.Errorf("expected the enforced route to be denied, got %d", rec.Code)
+	}
+	if len(records) != 1 || records[0].Shadow {
+		t.Errorf("expected an enforced denial to be audited, got %+v", records)
+	}
+}
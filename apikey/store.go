@@ -0,0 +1,653 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rotation"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+	"github.com/go-core-stack/auth/throttle"
+)
+
+/*
+Package apikey manages the lifecycle of the API keys used to sign the
+requests, backed by the core store or any other storage.Table.
+
+The plaintext secret of a key is returned only once, when the key is
+created or rotated. The store keeps the sha256 of every generation of the
+secret along with the secret sealed using AES-256-GCM, as validating an
+HMAC signature requires the secret itself. With WithDerivedVerifiers, the
+store keeps the verifier of the secret derived for a scope instead, see
+hash.DeriveVerifier, sealed as well since it signs requests as the secret
+does, and validates the requests signed with the derived signing keys.
+The Store implements the hash.SecretResolver for the Validator, and the
+rotation.KeyStore for the rotation scheduler.
+
+# Usage
+
+    store, _ := apikey.NewStore(dbStore, encryptionKey)
+
+    // or keeping the verifiers of the secrets, the clients and the
+    // validator signing with the keys derived for the scope
+    store, _ = apikey.NewStore(dbStore, encryptionKey, apikey.WithDerivedVerifiers("billing"))
+    validator := hash.NewValidator(60, hash.WithDerivedSigningKey("billing"))
+
+    key, secret, err := store.Create(ctx, &apikey.Key{Owner: "alice", Tenant: "acme"})
+    // hand over key.Key.Id and secret to the owner, the secret can not be
+    // retrieved once again
+
+    // validation, accepting the previous generations still in their
+    // grace period after a rotation
+    key, err := store.Validate(ctx, validator, req)
+
+    secret, err = store.Rotate(ctx, key.Key.Id, time.Hour)
+    err = store.Disable(ctx, key.Key.Id)
+
+    // keys unused for 90 days, see usage.go for the usage tracking
+    stale, err := store.StaleKeys(ctx, 90*24*time.Hour)
+
+    // middleware locking the keys and addresses guessing secrets
+    handler = store.Middleware(validator, routeTable,
+        apikey.WithLockout(apikey.NewLockout(apikey.LockoutPolicy{}, nil)))(handler)
+*/
+
+// Store holds the API keys.
+type Store struct {
+	table  storage.Table[KeyId, Key]
+	usage  storage.Table[UsageKey, Usage]
+	sealer *sealer
+	events events.Publisher
+
+	// scope of the verifiers stored in place of the secrets, see
+	// WithDerivedVerifiers
+	scope   string
+	derived bool
+}
+
+// StoreOption configures the Store.
+type StoreOption func(*Store)
+
+// WithDerivedVerifiers makes the store keep the verifier of the secrets
+// derived for the scope, see hash.DeriveVerifier, instead of the secrets
+// themselves, and resolve the verifiers for the validation, with the
+// validator configured using hash.WithDerivedSigningKey for the same scope.
+// The plaintext secrets are then not retrievable using Secret, including
+// the generations created by the rotation scheduler. The generations
+// created before keep their sealed secret, their verifier being derived
+// when resolved.
+func WithDerivedVerifiers(scope string) StoreOption {
+	return func(s *Store) {
+		s.scope = scope
+		s.derived = true
+	}
+}
+
+// NewStore creates the API key table in the database supplied by the
+// consumer, sealing the secrets using the 32 bytes encryption key.
+func NewStore(store db.Store, encryptionKey []byte, opts ...StoreOption) (*Store, error) {
+	if store == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "apikey: db store is required")
+	}
+	tbl, err := storage.NewStoreTable[KeyId, Key](store.GetCollection(KeysCollectionName))
+	if err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "apikey: failed to initialize key table: %s", err)
+	}
+	usage, err := storage.NewStoreTable[UsageKey, Usage](store.GetCollection(UsageCollectionName))
+	if err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "apikey: failed to initialize usage table: %s", err)
+	}
+	return NewStoreWithStorage(tbl, usage, encryptionKey, opts...)
+}
+
+// NewStoreWithStorage creates the store keeping the keys and their usage
+// in the storage, e.g. storage.NewMemoryTable for the tests and the
+// embedded uses not running a database, sealing the secrets using the 32
+// bytes encryption key.
+func NewStoreWithStorage(keys storage.Table[KeyId, Key], usage storage.Table[UsageKey, Usage], encryptionKey []byte, opts ...StoreOption) (*Store, error) {
+	if keys == nil || usage == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "apikey: key and usage storage are required")
+	}
+	s, err := newSealer(encryptionKey)
+	if err != nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "apikey: %s", err)
+	}
+	store := &Store{
+		table:  keys,
+		usage:  usage,
+		sealer: s,
+	}
+	for _, opt := range opts {
+		opt(store)
+	}
+	return store, nil
+}
+
+// newSecret creates the generation of the secret of the key, returning
+// it along with the plaintext secret, sealing the verifier of the secret
+// in place of the secret with WithDerivedVerifiers.
+func (s *Store) newSecret(keyId string, generation int32, now time.Time) (*Secret, string, error) {
+	secret, err := hash.GenerateSecret(hash.DefaultSecretBytes)
+	if err != nil {
+		return nil, "", errors.Wrapf(errors.Unknown, "failed to generate secret: %s", err)
+	}
+	stored := secret
+	if s.derived {
+		stored = hash.DeriveVerifier(secret, s.scope)
+	}
+	sealed, err := s.sealer.seal(keyId, generation, stored)
+	if err != nil {
+		return nil, "", errors.Wrapf(errors.Unknown, "failed to seal secret: %s", err)
+	}
+	return &Secret{
+		Generation: generation,
+		Hash:       hashSecret(secret),
+		Sealed:     sealed,
+		Scope:      s.scope,
+		Created:    now.Unix(),
+	}, secret, nil
+}
+
+// SetPublisher makes the store publish the events of the keys created,
+// rotated, disabled, enabled and deleted.
+func (s *Store) SetPublisher(p events.Publisher) {
+	s.events = p
+}
+
+// publish publishes the event of the key, if enabled, failures being
+// ignored as the change is already applied.
+func (s *Store) publish(ctx context.Context, kind events.Kind, tenant, id string) {
+	if s.events != nil {
+		_ = s.events.Publish(ctx, &events.Event{Kind: kind, Tenant: tenant, Resource: id})
+	}
+}
+
+// Create creates the API key with the owner, tenancy, scopes, labels and
+// expiry of k, returning the key along with its plaintext secret, which is
+// not retrievable afterwards.
+func (s *Store) Create(ctx context.Context, k *Key) (*Key, string, error) {
+	if k.Owner == "" {
+		return nil, "", errors.Wrapf(errors.InvalidArgument, "api key owner is required")
+	}
+	if err := k.Labels.Validate(); err != nil {
+		return nil, "", errors.Wrapf(errors.InvalidArgument, "invalid labels: %s", err)
+	}
+	if err := validateScopes(k.Scopes); err != nil {
+		return nil, "", err
+	}
+	if err := validateRateLimit(k.RateLimit); err != nil {
+		return nil, "", err
+	}
+	if err := validateNetwork(k.Network); err != nil {
+		return nil, "", err
+	}
+	if err := validateImpersonation(k, k.Impersonation); err != nil {
+		return nil, "", err
+	}
+	id, err := hash.GenerateKeyID()
+	if err != nil {
+		return nil, "", errors.Wrapf(errors.Unknown, "failed to generate key id: %s", err)
+	}
+	now := time.Now()
+	entry := *k
+	entry.Key = &KeyId{Id: id}
+	entry.Created = now.Unix()
+	if entry.Class == "" {
+		entry.Class = DefaultClass
+	}
+	secret, plaintext, err := s.newSecret(id, 1, now)
+	if err != nil {
+		return nil, "", err
+	}
+	entry.Secrets = []*Secret{secret}
+	if err := s.table.Insert(ctx, entry.Key, &entry); err != nil {
+		return nil, "", err
+	}
+	s.publish(ctx, events.KindKeyCreated, entry.Tenant, id)
+	return entry.redacted(), plaintext, nil
+}
+
+// Get returns the API key, without its sealed secrets.
+func (s *Store) Get(ctx context.Context, id string) (*Key, error) {
+	k, err := s.table.Find(ctx, &KeyId{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return k.redacted(), nil
+}
+
+// GetInTenant returns the API key if it belongs to the tenant, keys of the
+// other tenants are reported as NotFound unless the tenant is the root
+// tenancy.
+func (s *Store) GetInTenant(ctx context.Context, tenant, id string) (*Key, error) {
+	k, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tenant != route.RootTenant && k.Tenant != tenant {
+		return nil, errors.Wrapf(errors.NotFound, "api key %s not found", id)
+	}
+	return k, nil
+}
+
+// List returns the API keys of the tenancy, of only the owner if not
+// empty.
+func (s *Store) List(ctx context.Context, tenant, owner string) ([]*Key, error) {
+	filter := bson.D{{Key: "tenant", Value: tenant}}
+	if tenant == "" {
+		filter = bson.D{{Key: "tenant", Value: bson.D{{Key: "$exists", Value: false}}}}
+	}
+	if owner != "" {
+		filter = append(filter, bson.E{Key: "owner", Value: owner})
+	}
+	list, err := s.table.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(list))
+	for _, k := range list {
+		keys = append(keys, k.redacted())
+	}
+	return keys, nil
+}
+
+// updateSecrets replaces the generations of the secret of the key with the
+// ones returned by update, conditionally on the revision of the key read,
+// re-reading the key and retrying up to maxSecretUpdates times if changed
+// concurrently. A nil list leaves the key unchanged.
+func (s *Store) updateSecrets(ctx context.Context, id string, update func(k *Key) ([]*Secret, error)) (*Key, error) {
+	key := &KeyId{Id: id}
+	for attempt := 1; ; attempt++ {
+		k, err := s.table.Find(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		secrets, err := update(k)
+		if err != nil || secrets == nil {
+			return k, err
+		}
+		// the revision is omitted while zero
+		filter := bson.D{{Key: "revision", Value: k.Revision}}
+		if k.Revision == 0 {
+			filter = bson.D{{Key: "revision", Value: bson.D{{Key: "$exists", Value: false}}}}
+		}
+		err = s.table.UpdateIf(ctx, key, filter, &Key{Secrets: secrets, Revision: k.Revision + 1})
+		if err == nil {
+			return k, nil
+		}
+		if errors.GetErrCode(err) != errors.AlreadyExists || attempt >= maxSecretUpdates {
+			return nil, err
+		}
+	}
+}
+
+// rotate adds the next generation of the secret to the key, the previous
+// generations expiring at the expiry at the latest, or remaining valid
+// until revoked if zero. The generation is checked by check, if set,
+// against the key read before the update.
+func (s *Store) rotate(ctx context.Context, id string, now time.Time, expiry int64, check func(k *Key) error) (*Key, *Secret, string, error) {
+	var next *Secret
+	var plaintext string
+	k, err := s.updateSecrets(ctx, id, func(k *Key) ([]*Secret, error) {
+		if check != nil {
+			if err := check(k); err != nil {
+				return nil, err
+			}
+		}
+		var generation int32 = 1
+		if latest := k.latest(); latest != nil {
+			generation = latest.Generation + 1
+		}
+		var err error
+		next, plaintext, err = s.newSecret(k.Key.Id, generation, now)
+		if err != nil {
+			return nil, err
+		}
+		secrets := []*Secret{}
+		for _, sec := range k.validSecrets(now) {
+			if expiry != 0 && (sec.Expiry == 0 || sec.Expiry > expiry) {
+				sec.Expiry = expiry
+			}
+			secrets = append([]*Secret{sec}, secrets...)
+		}
+		return append(secrets, next), nil
+	})
+	if err != nil {
+		return nil, nil, "", err
+	}
+	s.publish(ctx, events.KindKeyRotated, k.Tenant, k.Key.Id)
+	return k, next, plaintext, nil
+}
+
+// Rotate creates the next generation of the secret of the key, returning
+// the plaintext secret. The previous generations remain valid for the
+// grace period, DefaultGracePeriod if zero, a negative grace period
+// revokes them right away. Concurrent rotations of the key each create
+// their own generation.
+func (s *Store) Rotate(ctx context.Context, id string, grace time.Duration) (string, error) {
+	if grace == 0 {
+		grace = DefaultGracePeriod
+	}
+	now := time.Now()
+	_, _, plaintext, err := s.rotate(ctx, id, now, now.Add(max(grace, 0)).Unix(), nil)
+	return plaintext, err
+}
+
+// setDisabled updates the disabled flag of the key.
+func (s *Store) setDisabled(ctx context.Context, id string, disabled bool) error {
+	key := &KeyId{Id: id}
+	k, err := s.table.Find(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := s.table.Update(ctx, key, &Key{Disabled: &disabled}); err != nil {
+		return err
+	}
+	kind := events.KindKeyEnabled
+	if disabled {
+		kind = events.KindKeyDisabled
+	}
+	s.publish(ctx, kind, k.Tenant, id)
+	return nil
+}
+
+// Disable disables the key, failing the validation of the requests signed
+// with it until enabled again. Consumers caching the secrets are expected
+// to invalidate the cached secret of the key.
+func (s *Store) Disable(ctx context.Context, id string) error {
+	return s.setDisabled(ctx, id, true)
+}
+
+// Enable enables the disabled key again.
+func (s *Store) Enable(ctx context.Context, id string) error {
+	return s.setDisabled(ctx, id, false)
+}
+
+// validateRateLimit ensures the rate limit, if any, allows requests.
+func validateRateLimit(l *RateLimit) error {
+	if l != nil && (l.Limit < 0 || l.Window < 0 || (l.Limit > 0) != (l.Window > 0)) {
+		return errors.Wrapf(errors.InvalidArgument, "invalid rate limit %d per %d seconds", l.Limit, l.Window)
+	}
+	return nil
+}
+
+// SetRateLimit sets the rate limit of the key, nil restoring the default
+// of the throttle.KeyLimiter.
+func (s *Store) SetRateLimit(ctx context.Context, id string, l *RateLimit) error {
+	if err := validateRateLimit(l); err != nil {
+		return err
+	}
+	key := &KeyId{Id: id}
+	if _, err := s.table.Find(ctx, key); err != nil {
+		return err
+	}
+	if l == nil {
+		// an empty rate limit is the default, as omitted fields are not
+		// updated
+		l = &RateLimit{}
+	}
+	return s.table.Update(ctx, key, &Key{RateLimit: l})
+}
+
+// RatePolicy returns the rate limit of the key as the policy of the
+// throttle.KeyLimiter, nil for the default or unknown keys.
+func (s *Store) RatePolicy(ctx context.Context, keyId string) (*throttle.Policy, error) {
+	k, err := s.table.Find(ctx, &KeyId{Id: keyId})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if k.RateLimit == nil || k.RateLimit.Limit == 0 {
+		return nil, nil
+	}
+	return k.RateLimit.Policy(), nil
+}
+
+// Delete deletes the key along with its usage.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	var tenant string
+	if s.events != nil {
+		// the tenant of the key, for the event
+		if k, err := s.table.Find(ctx, &KeyId{Id: id}); err == nil {
+			tenant = k.Tenant
+		}
+	}
+	if err := s.table.DeleteKey(ctx, &KeyId{Id: id}); err != nil {
+		return err
+	}
+	s.publish(ctx, events.KindKeyDeleted, tenant, id)
+	_, err := s.usage.DeleteByFilter(ctx, bson.D{{Key: "_id.keyId", Value: id}})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// activeKey returns the key if active.
+func (s *Store) activeKey(ctx context.Context, id string) (*Key, error) {
+	k, err := s.table.Find(ctx, &KeyId{Id: id})
+	if err != nil {
+		return nil, errors.Wrapf(errors.NotFound, "api key %s not found", id)
+	}
+	if !k.IsActive(time.Now()) {
+		return nil, errors.Wrapf(errors.Unauthorized, "api key %s is disabled or expired", id)
+	}
+	return k, nil
+}
+
+// GetSecret implements the hash.SecretResolver, returning the latest
+// generation of the secret of the active key, or of its verifier with
+// WithDerivedVerifiers.
+func (s *Store) GetSecret(ctx context.Context, keyId string) (string, error) {
+	k, err := s.activeKey(ctx, keyId)
+	if err != nil {
+		return "", err
+	}
+	latest := k.latest()
+	if latest == nil {
+		return "", errors.Wrapf(errors.NotFound, "api key %s has no secret", keyId)
+	}
+	return s.verificationSecret(k, latest)
+}
+
+// GetSecrets implements the hash.BulkSecretResolver, omitting the unknown
+// and inactive keys.
+func (s *Store) GetSecrets(ctx context.Context, keyIds []string) (map[string]string, error) {
+	filter := bson.D{{Key: "_id.id", Value: bson.D{{Key: "$in", Value: keyIds}}}}
+	list, err := s.table.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	now := time.Now()
+	secrets := map[string]string{}
+	for _, k := range list {
+		latest := k.latest()
+		if k.Key == nil || latest == nil || !k.IsActive(now) {
+			continue
+		}
+		secret, err := s.verificationSecret(k, latest)
+		if err != nil {
+			return nil, err
+		}
+		secrets[k.Key.Id] = secret
+	}
+	return secrets, nil
+}
+
+// open returns the plaintext of the generation of the secret, or of its
+// verifier if derived.
+func (s *Store) open(k *Key, sec *Secret) (string, error) {
+	secret, err := s.sealer.open(k.Key.Id, sec.Generation, sec.Sealed)
+	if err != nil {
+		return "", errors.Wrapf(errors.Unknown, "api key %s: %s", k.Key.Id, err)
+	}
+	return secret, nil
+}
+
+// verificationSecret returns the secret the requests signed with the
+// generation are validated with, its verifier for the scope of the store
+// with WithDerivedVerifiers.
+func (s *Store) verificationSecret(k *Key, sec *Secret) (string, error) {
+	secret, err := s.open(k, sec)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !s.derived:
+		if sec.Scope != "" {
+			return "", errors.Wrapf(errors.Unauthorized, "api key %s holds a verifier for scope %s", k.Key.Id, sec.Scope)
+		}
+		return secret, nil
+	case sec.Scope == "":
+		// sealed before the verifiers were enabled
+		return hash.DeriveVerifier(secret, s.scope), nil
+	case sec.Scope != s.scope:
+		return "", errors.Wrapf(errors.Unauthorized, "api key %s holds a verifier for scope %s", k.Key.Id, sec.Scope)
+	}
+	return secret, nil
+}
+
+// Secret returns the plaintext of the generation of the secret of the key,
+// e.g. for the rotation notifier to deliver a generation created by the
+// scheduler to the owner of the key, failing with NotFound for the
+// generations keeping a verifier, see WithDerivedVerifiers.
+func (s *Store) Secret(ctx context.Context, keyId string, generation int32) (string, error) {
+	k, err := s.table.Find(ctx, &KeyId{Id: keyId})
+	if err != nil {
+		return "", err
+	}
+	for _, sec := range k.Secrets {
+		if sec.Generation != generation {
+			continue
+		}
+		if sec.Scope != "" {
+			return "", errors.Wrapf(errors.NotFound, "generation %d of api key %s keeps a verifier, not the secret", generation, keyId)
+		}
+		return s.open(k, sec)
+	}
+	return "", errors.Wrapf(errors.NotFound, "generation %d of api key %s not found", generation, keyId)
+}
+
+// Validate validates the request signed with the active key it carries,
+// using all the valid generations of the secret, latest first, and returns
+// the key on success.
+func (s *Store) Validate(ctx context.Context, v hash.Validator, r *http.Request) (*Key, error) {
+	k, err := s.activeKey(ctx, v.GetKeyId(r))
+	if err != nil {
+		return nil, err
+	}
+	return s.verify(k, v, r)
+}
+
+// verify validates the request with the valid generations of the secret
+// of the active key, returning the key without its sealed secrets.
+func (s *Store) verify(k *Key, v hash.Validator, r *http.Request) (*Key, error) {
+	var failure error = errors.Wrapf(errors.Unauthorized, "api key %s has no valid secret", k.Key.Id)
+	for _, sec := range k.validSecrets(time.Now()) {
+		secret, err := s.verificationSecret(k, sec)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := v.Validate(r, secret)
+		if ok {
+			return k.redacted(), nil
+		}
+		failure = errors.Wrapf(errors.Unauthorized, "validation failed: %s", err)
+	}
+	return nil, failure
+}
+
+// generations returns the rotation generations of the key.
+func generations(k *Key, now time.Time) []*rotation.Generation {
+	list := []*rotation.Generation{}
+	for _, sec := range k.Secrets {
+		if !sec.isValid(now) {
+			continue
+		}
+		list = append(list, &rotation.Generation{
+			KeyId:      k.Key.Id,
+			Class:      k.Class,
+			Owner:      k.Owner,
+			Generation: sec.Generation,
+			Created:    time.Unix(sec.Created, 0),
+		})
+	}
+	return list
+}
+
+// ListGenerations implements the rotation.KeyStore, returning the valid
+// generations of the active keys of the class.
+func (s *Store) ListGenerations(ctx context.Context, class string) ([]*rotation.Generation, error) {
+	list, err := s.table.FindMany(ctx, bson.D{{Key: "class", Value: class}}, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	now := time.Now()
+	gens := []*rotation.Generation{}
+	for _, k := range list {
+		if k.Key == nil || !k.IsActive(now) {
+			continue
+		}
+		gens = append(gens, generations(k, now)...)
+	}
+	return gens, nil
+}
+
+// CreateGeneration implements the rotation.KeyStore, creating the next
+// generation of the secret while the previous ones remain valid until
+// revoked by the scheduler after the grace period. The plaintext secret is
+// retrievable using Secret for delivery to the owner.
+func (s *Store) CreateGeneration(ctx context.Context, current *rotation.Generation) (*rotation.Generation, error) {
+	// previous generations are revoked by the scheduler, keep them valid
+	// in the meantime
+	k, next, _, err := s.rotate(ctx, current.KeyId, time.Now(), 0, func(k *Key) error {
+		if latest := k.latest(); latest == nil || latest.Generation != current.Generation {
+			return errors.Wrapf(errors.AlreadyExists, "api key %s already rotated past generation %d", current.KeyId, current.Generation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rotation.Generation{
+		KeyId:      k.Key.Id,
+		Class:      k.Class,
+		Owner:      k.Owner,
+		Generation: next.Generation,
+		Created:    time.Unix(next.Created, 0),
+	}, nil
+}
+
+// RevokeGeneration implements the rotation.KeyStore, removing the
+// generation of the secret.
+func (s *Store) RevokeGeneration(ctx context.Context, gen *rotation.Generation) error {
+	_, err := s.updateSecrets(ctx, gen.KeyId, func(k *Key) ([]*Secret, error) {
+		secrets := []*Secret{}
+		for _, sec := range k.Secrets {
+			if sec.Generation != gen.Generation {
+				secrets = append(secrets, sec)
+			}
+		}
+		if len(secrets) == len(k.Secrets) {
+			return nil, nil
+		}
+		if len(secrets) == 0 {
+			return nil, errors.Wrapf(errors.InvalidArgument, "can not revoke the only generation of api key %s", gen.KeyId)
+		}
+		return secrets, nil
+	})
+	return err
+}
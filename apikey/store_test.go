@@ -0,0 +1,130 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/storage"
+)
+
+func TestStoreWithStorage(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewStoreWithStorage(storage.NewMemoryTable[KeyId, Key](), storage.NewMemoryTable[UsageKey, Usage](), make([]byte, 32))
+	if err != nil {
+		t.Fatalf("failed to create store: %s", err)
+	}
+	if _, err := NewStoreWithStorage(nil, nil, make([]byte, 32)); err == nil {
+		t.Errorf("expected the missing storage to fail")
+	}
+
+	alice, secret, err := store.Create(ctx, &Key{Owner: "alice", Tenant: "acme"})
+	if err != nil {
+		t.Fatalf("failed to create key: %s", err)
+	}
+	bob, _, err := store.Create(ctx, &Key{Owner: "bob"})
+	if err != nil {
+		t.Fatalf("failed to create key: %s", err)
+	}
+	if got, err := store.GetSecret(ctx, alice.Key.Id); err != nil || got != secret {
+		t.Errorf("expected the secret of the key, got %v", err)
+	}
+	if keys, err := store.List(ctx, "acme", ""); err != nil || len(keys) != 1 || keys[0].Key.Id != alice.Key.Id {
+		t.Errorf("unexpected keys of the tenant %v: %v", keys, err)
+	}
+	if keys, err := store.List(ctx, "", "bob"); err != nil || len(keys) != 1 || keys[0].Key.Id != bob.Key.Id {
+		t.Errorf("unexpected shared keys %v: %v", keys, err)
+	}
+
+	rotated, err := store.Rotate(ctx, alice.Key.Id, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to rotate key: %s", err)
+	}
+	secrets, err := store.GetSecrets(ctx, []string{alice.Key.Id, bob.Key.Id, "unknown"})
+	if err != nil || len(secrets) != 2 || secrets[alice.Key.Id] != rotated {
+		t.Errorf("unexpected secrets %v: %v", secrets, err)
+	}
+
+	if err := store.Disable(ctx, bob.Key.Id); err != nil {
+		t.Fatalf("failed to disable key: %s", err)
+	}
+	if _, err := store.GetSecret(ctx, bob.Key.Id); err == nil {
+		t.Errorf("expected the disabled key to be rejected")
+	}
+	if err := store.Delete(ctx, alice.Key.Id); err != nil {
+		t.Fatalf("failed to delete key: %s", err)
+	}
+	if _, err := store.Get(ctx, alice.Key.Id); !errors.IsNotFound(err) {
+		t.Errorf("expected the deleted key to not be found, got %v", err)
+	}
+}
+
+func TestStoreDerivedVerifiers(t *testing.T) {
+	ctx := context.Background()
+	keys := storage.NewMemoryTable[KeyId, Key]()
+	usage := storage.NewMemoryTable[UsageKey, Usage]()
+	plain, _ := NewStoreWithStorage(keys, usage, make([]byte, 32))
+	legacy, legacySecret, err := plain.Create(ctx, &Key{Owner: "bob"})
+	if err != nil {
+		t.Fatalf("failed to create key: %s", err)
+	}
+
+	store, _ := NewStoreWithStorage(keys, usage, make([]byte, 32), WithDerivedVerifiers("billing"))
+	k, secret, err := store.Create(ctx, &Key{Owner: "alice"})
+	if err != nil {
+		t.Fatalf("failed to create key: %s", err)
+	}
+	stored, _ := keys.Find(ctx, k.Key)
+	if opened, _ := store.sealer.open(k.Key.Id, 1, stored.Secrets[0].Sealed); opened != hash.DeriveVerifier(secret, "billing") {
+		t.Errorf("expected the verifier to be stored in place of the secret")
+	}
+	if _, err := store.Secret(ctx, k.Key.Id, 1); !errors.IsNotFound(err) {
+		t.Errorf("expected the plaintext secret to not be retrievable, got %v", err)
+	}
+	if _, err := plain.GetSecret(ctx, k.Key.Id); errors.GetErrCode(err) != errors.Unauthorized {
+		t.Errorf("expected the verifier to not be resolved as a secret, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		id     string
+		secret string
+		scope  string
+		valid  bool
+	}{
+		{"derived signing key", k.Key.Id, secret, "billing", true},
+		{"other scope", k.Key.Id, secret, "orders", false},
+		{"secret as the signing key", k.Key.Id, secret, "", false},
+		{"secret sealed before", legacy.Key.Id, legacySecret, "billing", true},
+	}
+	validator := hash.NewValidator(60, hash.WithDerivedSigningKey("billing"))
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []hash.Option
+			if tc.scope != "" {
+				opts = append(opts, hash.WithDerivedSigningKey(tc.scope))
+			}
+			req := hash.NewGenerator(tc.id, tc.secret, opts...).AddAuthHeaders(httptest.NewRequest(http.MethodGet, "/books", nil))
+			if _, err := store.Validate(ctx, validator, req); (err == nil) != tc.valid {
+				t.Errorf("expected valid %v, got %v", tc.valid, err)
+			}
+		})
+	}
+
+	// the rotated generations keep their verifier as well
+	rotated, err := store.Rotate(ctx, k.Key.Id, time.Hour)
+	if err != nil {
+		t.Fatalf("failed to rotate key: %s", err)
+	}
+	if got, err := store.GetSecret(ctx, k.Key.Id); err != nil || got != hash.DeriveVerifier(rotated, "billing") {
+		t.Errorf("expected the verifier of the rotated secret, got %v", err)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file tracks the usage of the API keys, i.e. the time a key was last
+used and the number of requests per route, so that the stale keys are
+identified and retired safely.
+
+The Middleware records the usage of the authenticated requests with the
+UsageRecorder, which aggregates it in memory and writes it to the store
+in batches, keeping the store off the request path. The counts written by
+replicas flushing the same key and route concurrently may be approximate,
+the last used time is never moved backwards.
+
+# Usage
+
+    usage := apikey.NewUsageRecorder(store, time.Minute)
+    go usage.Run(ctx)
+    handler = store.Middleware(validator, routeTable, apikey.WithUsage(usage))(handler)
+
+    // keys unused for 90 days, candidates for retirement
+    stale, err := store.StaleKeys(ctx, 90*24*time.Hour)
+*/
+
+// Defaults of the UsageRecorder.
+const (
+	// DefaultUsageFlushInterval is how often the recorded usage is
+	// written to the store
+	DefaultUsageFlushInterval = time.Minute
+
+	// DefaultUsagePending bounds the distinct key and route pairs pending
+	// a flush, the usage of further pairs being dropped until the flush
+	DefaultUsagePending = 10000
+)
+
+// UsageKey identifies the usage of an API key for a route.
+type UsageKey struct {
+	KeyId  string `bson:"keyId,omitempty"`
+	Route  string `bson:"route,omitempty"`
+	Method string `bson:"method,omitempty"`
+}
+
+// Usage is the usage of an API key for a route.
+type Usage struct {
+	Key *UsageKey `bson:"key,omitempty"`
+
+	// number of requests
+	Count int64 `bson:"count,omitempty"`
+
+	// time of the last request, unix seconds
+	LastUsed int64 `bson:"lastUsed,omitempty"`
+}
+
+// UsageSink persists the usage recorded by the UsageRecorder, implemented
+// by the Store.
+type UsageSink interface {
+	// RecordUsage adds the batch of usage to the persisted one.
+	RecordUsage(ctx context.Context, usage []*Usage) error
+}
+
+// UsageRecorder aggregates the usage of the API keys in memory, flushing
+// it to the sink in batches. It is safe for concurrent use.
+type UsageRecorder struct {
+	sink     UsageSink
+	interval time.Duration
+	limit    int
+
+	mu      sync.Mutex
+	pending map[UsageKey]*Usage
+	full    chan struct{}
+	dropped atomic.Int64
+}
+
+// NewUsageRecorder creates the recorder flushing the usage to the sink
+// every interval, DefaultUsageFlushInterval if zero, once started with Run.
+func NewUsageRecorder(sink UsageSink, interval time.Duration) *UsageRecorder {
+	if interval <= 0 {
+		interval = DefaultUsageFlushInterval
+	}
+	return &UsageRecorder{
+		sink:     sink,
+		interval: interval,
+		limit:    DefaultUsagePending,
+		pending:  map[UsageKey]*Usage{},
+		full:     make(chan struct{}, 1),
+	}
+}
+
+// Record records a request of the key for the route at the time, without
+// blocking on the store. Once the pending usage reaches its bound, an
+// early flush is requested and the usage of new pairs is dropped.
+func (u *UsageRecorder) Record(keyId, route, method string, at time.Time) {
+	key := UsageKey{KeyId: keyId, Route: route, Method: method}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	entry, ok := u.pending[key]
+	if !ok {
+		if len(u.pending) >= u.limit {
+			u.dropped.Add(1)
+			select {
+			case u.full <- struct{}{}:
+			default:
+			}
+			return
+		}
+		entry = &Usage{Key: &key}
+		u.pending[key] = entry
+	}
+	entry.Count++
+	entry.LastUsed = max(entry.LastUsed, at.Unix())
+}
+
+// Dropped returns the number of requests whose usage was dropped as the
+// pending usage reached its bound.
+func (u *UsageRecorder) Dropped() int64 {
+	return u.dropped.Load()
+}
+
+// Flush writes the pending usage to the sink. The usage failing to be
+// written is merged back to be retried with the next flush.
+func (u *UsageRecorder) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	batch := make([]*Usage, 0, len(u.pending))
+	for _, entry := range u.pending {
+		batch = append(batch, entry)
+	}
+	u.pending = map[UsageKey]*Usage{}
+	u.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	err := u.sink.RecordUsage(ctx, batch)
+	if err != nil {
+		u.mu.Lock()
+		for _, entry := range batch {
+			if current, ok := u.pending[*entry.Key]; ok {
+				current.Count += entry.Count
+				current.LastUsed = max(current.LastUsed, entry.LastUsed)
+			} else if len(u.pending) < u.limit {
+				u.pending[*entry.Key] = entry
+			}
+		}
+		u.mu.Unlock()
+	}
+	return err
+}
+
+// Run flushes the usage every interval, or earlier once the pending usage
+// reaches its bound, until the context is done, flushing the remaining
+// usage before returning.
+func (u *UsageRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			// the context is done, flush with a fresh one
+			flushCtx, cancel := context.WithTimeout(context.Background(), u.interval)
+			_ = u.Flush(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+		case <-u.full:
+		}
+		_ = u.Flush(ctx)
+	}
+}
+
+// RecordUsage adds the usage to the usage per route of the keys, and moves
+// the last used time of the keys forward.
+func (s *Store) RecordUsage(ctx context.Context, usage []*Usage) error {
+	lastUsed := map[string]int64{}
+	for _, entry := range usage {
+		if entry == nil || entry.Key == nil {
+			continue
+		}
+		current, err := s.usage.Find(ctx, entry.Key)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		update := *entry
+		if current != nil {
+			update.Count += current.Count
+			update.LastUsed = max(update.LastUsed, current.LastUsed)
+		}
+		if err := s.usage.Locate(ctx, entry.Key, &update); err != nil {
+			return err
+		}
+		lastUsed[entry.Key.KeyId] = max(lastUsed[entry.Key.KeyId], entry.LastUsed)
+	}
+	for id, at := range lastUsed {
+		k, err := s.table.Find(ctx, &KeyId{Id: id})
+		if errors.IsNotFound(err) {
+			// key deleted meanwhile
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if k.LastUsed >= at {
+			continue
+		}
+		if err := s.table.Update(ctx, k.Key, &Key{LastUsed: at}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Usage returns the usage per route of the key.
+func (s *Store) Usage(ctx context.Context, id string) ([]*Usage, error) {
+	list, err := s.usage.FindMany(ctx, bson.D{{Key: "_id.keyId", Value: id}}, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// StaleKeys returns the API keys not used for the duration, including the
+// keys created before and never used since.
+func (s *Store) StaleKeys(ctx context.Context, unused time.Duration) ([]*Key, error) {
+	cutoff := time.Now().Add(-unused).Unix()
+	filter := bson.D{{Key: "$or", Value: bson.A{
+		bson.M{"lastUsed": bson.M{"$lt": cutoff}},
+		bson.M{"lastUsed": bson.M{"$exists": false}, "created": bson.M{"$lt": cutoff}},
+	}}}
+	list, err := s.table.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(list))
+	for _, k := range list {
+		keys = append(keys, k.redacted())
+	}
+	return keys, nil
+}
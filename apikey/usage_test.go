@@ -0,0 +1,72 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package apikey
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// fakeUsageSink collects the flushed usage, failing while err is set
+type fakeUsageSink struct {
+	usage map[UsageKey]Usage
+	err   error
+}
+
+func (f *fakeUsageSink) RecordUsage(ctx context.Context, usage []*Usage) error {
+	if f.err != nil {
+		return f.err
+	}
+	for _, u := range usage {
+		current := f.usage[*u.Key]
+		current.Count += u.Count
+		current.LastUsed = max(current.LastUsed, u.LastUsed)
+		f.usage[*u.Key] = current
+	}
+	return nil
+}
+
+func TestUsageRecorder(t *testing.T) {
+	sink := &fakeUsageSink{usage: map[UsageKey]Usage{}}
+	u := NewUsageRecorder(sink, time.Minute)
+	now := time.Unix(1000, 0)
+	u.Record("k1", "/books", "GET", now)
+	u.Record("k1", "/books", "GET", now.Add(time.Second))
+	u.Record("k1", "/books", "POST", now)
+	u.Record("k2", "/books", "GET", now)
+
+	// failed flushes are retried with the next flush
+	sink.err = errors.Wrapf(errors.Unknown, "store unavailable")
+	if err := u.Flush(context.Background()); err == nil {
+		t.Fatalf("expected flush to fail")
+	}
+	sink.err = nil
+	u.Record("k1", "/books", "GET", now.Add(2*time.Second))
+	if err := u.Flush(context.Background()); err != nil {
+		t.Fatalf("failed to flush usage: %s", err)
+	}
+	got := sink.usage[UsageKey{KeyId: "k1", Route: "/books", Method: "GET"}]
+	if got.Count != 3 || got.LastUsed != 1002 {
+		t.Errorf("unexpected usage %+v", got)
+	}
+	if len(sink.usage) != 3 {
+		t.Errorf("expected usage of 3 key and route pairs, got %d", len(sink.usage))
+	}
+
+	// usage of new pairs beyond the bound is dropped
+	u.limit = 1
+	u.Record("k1", "/books", "GET", now)
+	u.Record("k3", "/books", "GET", now)
+	if u.Dropped() != 1 {
+		t.Errorf("expected a dropped usage, got %d", u.Dropped())
+	}
+	select {
+	case <-u.full:
+	default:
+		t.Errorf("expected an early flush to be requested")
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package approval
+
+import "time"
+
+const (
+	// Collection name within the database the consumer supplies via
+	// db.Store.
+	PendingOperationsCollection = "pending_operations"
+
+	// MinApprovals is the minimum number of distinct approvers required
+	// for an operation, enforcing dual control.
+	MinApprovals = 2
+
+	// DefaultTTL is how long a pending operation remains open for
+	// approvals when no expiry is requested.
+	DefaultTTL = 24 * time.Hour
+)
+
+// Well known destructive operations requiring approvals.
+const (
+	ActionDeleteTenant     = "tenant.delete"
+	ActionRevokeAllKeys    = "apikey.revoke-all"
+	ActionDisableRootRoute = "route.disable-root"
+)
@@ -0,0 +1,176 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package approval
+
+import (
+	"crypto/hmac"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+/*
+Package approval provides M-of-N approvals, i.e. dual control, for
+destructive admin operations such as deleting a tenant, revoking all the
+keys or disabling root routes.
+
+An operation is proposed as pending, naming the privileged approvers
+eligible to approve it and the number of distinct approvals required. Each
+approver approves by signing the operation with the secret of their API
+key, and the operation can be executed, exactly once, only after enough
+valid approvals are recorded before its expiry. The requester of an
+operation can never approve it.
+
+# Usage
+
+    store, _ := approval.NewStore(dbStore, resolver)
+    op, _ := store.Propose(ctx, &approval.Operation{
+        Action:    approval.ActionDeleteTenant,
+        Target:    "acme",
+        Requester: "admin-1",
+        Approvers: []string{"admin-2", "admin-3", "admin-4"},
+        Required:  2,
+    }, time.Hour)
+
+    // by every approver, with the secret of their API key
+    _, err := store.Approve(ctx, op.Key.Id, approval.Sign(op, "admin-2", secret))
+
+    err = store.Execute(ctx, op.Key.Id, func(ctx context.Context, op *approval.Operation) error {
+        return deleteTenant(ctx, op.Target)
+    })
+*/
+
+// OperationKey identifies a pending operation.
+type OperationKey struct {
+	Id string `bson:"id,omitempty"`
+}
+
+// Approval is the signed approval of an operation by an approver.
+type Approval struct {
+	// API key ID of the approver
+	Approver string `bson:"approver,omitempty" json:"approver"`
+
+	// time of approval, unix seconds
+	Timestamp int64 `bson:"timestamp,omitempty" json:"timestamp"`
+
+	// hex HMAC-SHA256 over the approval components, see ApprovalString
+	Signature string `bson:"signature,omitempty" json:"signature"`
+}
+
+// Operation is a destructive operation pending approvals.
+type Operation struct {
+	Key *OperationKey `bson:"key,omitempty"`
+
+	// operation to be performed, e.g. ActionDeleteTenant, and its target
+	Action string `bson:"action,omitempty"`
+	Target string `bson:"target,omitempty"`
+
+	// API key ID of the requester of the operation
+	Requester string `bson:"requester,omitempty"`
+
+	// API key IDs of the approvers eligible to approve the operation, and
+	// the number of distinct approvals required out of them
+	Approvers []string `bson:"approvers,omitempty"`
+	Required  int32    `bson:"required,omitempty"`
+
+	// approvals recorded so far
+	Approvals []*Approval `bson:"approvals,omitempty"`
+
+	// time of proposal and expiry, unix seconds
+	Created int64 `bson:"created,omitempty"`
+	Expiry  int64 `bson:"expiry,omitempty"`
+}
+
+// validate ensures the proposed operation can be approved at all.
+func (op *Operation) validate() error {
+	if op.Action == "" || op.Requester == "" {
+		return errors.Wrapf(errors.InvalidArgument, "operation action and requester are required")
+	}
+	if op.Required < MinApprovals {
+		return errors.Wrapf(errors.InvalidArgument, "at least %d approvals are required, got %d", MinApprovals, op.Required)
+	}
+	eligible := 0
+	for i, a := range op.Approvers {
+		if a == "" || slices.Contains(op.Approvers[:i], a) {
+			return errors.Wrapf(errors.InvalidArgument, "invalid or duplicate approver %q", a)
+		}
+		if a != op.Requester {
+			eligible++
+		}
+	}
+	if eligible < int(op.Required) {
+		return errors.Wrapf(errors.InvalidArgument, "%d approvals required with only %d eligible approvers", op.Required, eligible)
+	}
+	return nil
+}
+
+// IsExpired reports whether the operation has expired at the time.
+func (op *Operation) IsExpired(now time.Time) bool {
+	return now.Unix() >= op.Expiry
+}
+
+// IsApproved reports whether the operation has the required number of
+// approvals by distinct eligible approvers and is not expired at the time.
+func (op *Operation) IsApproved(now time.Time) bool {
+	if op.IsExpired(now) {
+		return false
+	}
+	approvers := []string{}
+	for _, a := range op.Approvals {
+		if a.Approver != op.Requester && slices.Contains(op.Approvers, a.Approver) &&
+			!slices.Contains(approvers, a.Approver) {
+			approvers = append(approvers, a.Approver)
+		}
+	}
+	return len(approvers) >= int(op.Required)
+}
+
+// ApprovalString returns the components signed by the approver for the
+// operation, binding the approval to the operation, its action and target.
+func ApprovalString(op *Operation, approver string, timestamp int64) []string {
+	return []string{"APPROVE", op.Key.Id, op.Action, op.Target, approver, strconv.FormatInt(timestamp, 10)}
+}
+
+// Sign returns the approval of the operation by the approver, signed with
+// the secret of the API key of the approver.
+func Sign(op *Operation, approver, secret string) *Approval {
+	ts := time.Now().Unix()
+	return &Approval{
+		Approver:  approver,
+		Timestamp: ts,
+		Signature: hash.GenerateSHA256HMAC(secret, ApprovalString(op, approver, ts)...),
+	}
+}
+
+// addApproval verifies the approval with the secret of the approver and
+// records it.
+func (op *Operation) addApproval(a *Approval, secret string, now time.Time) error {
+	if op.IsExpired(now) {
+		return errors.Wrapf(errors.Forbidden, "operation %s expired", op.Key.Id)
+	}
+	if a.Approver == op.Requester {
+		return errors.Wrapf(errors.Forbidden, "requester cannot approve own operation")
+	}
+	if !slices.Contains(op.Approvers, a.Approver) {
+		return errors.Wrapf(errors.Forbidden, "%s is not an approver of operation %s", a.Approver, op.Key.Id)
+	}
+	for _, e := range op.Approvals {
+		if e.Approver == a.Approver {
+			return errors.Wrapf(errors.AlreadyExists, "operation %s already approved by %s", op.Key.Id, a.Approver)
+		}
+	}
+	if a.Timestamp < op.Created || a.Timestamp > now.Unix()+60 {
+		return errors.Wrapf(errors.InvalidArgument, "invalid approval timestamp")
+	}
+	expected := hash.GenerateSHA256HMAC(secret, ApprovalString(op, a.Approver, a.Timestamp)...)
+	if !hmac.Equal([]byte(expected), []byte(a.Signature)) {
+		return errors.Wrapf(errors.Unauthorized, "invalid approval signature by %s", a.Approver)
+	}
+	op.Approvals = append(op.Approvals, a)
+	return nil
+}
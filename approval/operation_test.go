@@ -0,0 +1,85 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package approval
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func newOperation() *Operation {
+	now := time.Now()
+	return &Operation{
+		Key:       &OperationKey{Id: "op-1"},
+		Action:    ActionDeleteTenant,
+		Target:    "acme",
+		Requester: "admin-1",
+		Approvers: []string{"admin-1", "admin-2", "admin-3"},
+		Required:  2,
+		Created:   now.Unix(),
+		Expiry:    now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestValidateOperation(t *testing.T) {
+	if err := newOperation().validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	op := newOperation()
+	op.Required = 1
+	if err := op.validate(); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected single approval to be rejected, got %v", err)
+	}
+	// the requester does not count as an eligible approver
+	op = newOperation()
+	op.Required = 3
+	if err := op.validate(); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected insufficient eligible approvers to be rejected, got %v", err)
+	}
+	op = newOperation()
+	op.Approvers = []string{"admin-2", "admin-2"}
+	if err := op.validate(); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected duplicate approvers to be rejected, got %v", err)
+	}
+}
+
+func TestApprovals(t *testing.T) {
+	secrets := map[string]string{"admin-1": "s1", "admin-2": "s2", "admin-3": "s3"}
+	op := newOperation()
+	now := time.Now()
+
+	if err := op.addApproval(Sign(op, "admin-1", secrets["admin-1"]), secrets["admin-1"], now); !errors.IsForbidden(err) {
+		t.Errorf("expected requester approval to be rejected, got %v", err)
+	}
+	if err := op.addApproval(Sign(op, "admin-2", "wrong"), secrets["admin-2"], now); !errors.IsUnauthorized(err) {
+		t.Errorf("expected approval with invalid signature to be rejected, got %v", err)
+	}
+	if err := op.addApproval(Sign(op, "admin-2", secrets["admin-2"]), secrets["admin-2"], now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := op.addApproval(Sign(op, "admin-2", secrets["admin-2"]), secrets["admin-2"], now); !errors.IsAlreadyExists(err) {
+		t.Errorf("expected duplicate approval to be rejected, got %v", err)
+	}
+	if op.IsApproved(now) {
+		t.Errorf("expected operation with a single approval not to be approved")
+	}
+
+	// an approval signed for another operation is rejected
+	other := newOperation()
+	other.Target = "other"
+	if err := op.addApproval(Sign(other, "admin-3", secrets["admin-3"]), secrets["admin-3"], now); !errors.IsUnauthorized(err) {
+		t.Errorf("expected approval of another operation to be rejected, got %v", err)
+	}
+	if err := op.addApproval(Sign(op, "admin-3", secrets["admin-3"]), secrets["admin-3"], now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !op.IsApproved(now) {
+		t.Errorf("expected operation to be approved")
+	}
+	if op.IsApproved(now.Add(2 * time.Hour)) {
+		t.Errorf("expected expired operation not to be approved")
+	}
+}
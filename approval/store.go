@@ -0,0 +1,136 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package approval
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// Store holds the operations pending approvals.
+type Store struct {
+	table    *table.Table[OperationKey, Operation]
+	resolver hash.SecretResolver
+}
+
+// NewStore creates the pending operation store in the database supplied by
+// the consumer, verifying approvals with the secrets of the approvers
+// obtained from the resolver.
+func NewStore(store db.Store, resolver hash.SecretResolver) (*Store, error) {
+	if store == nil || resolver == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "approval: db store and secret resolver are required")
+	}
+	tbl := &table.Table[OperationKey, Operation]{}
+	if err := tbl.Initialize(store.GetCollection(PendingOperationsCollection)); err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "approval: failed to initialize operation table: %s", err)
+	}
+	return &Store{
+		table:    tbl,
+		resolver: resolver,
+	}, nil
+}
+
+// newOperationId returns a random operation identifier.
+func newOperationId() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Propose records the operation as pending approvals until expiry after
+// the ttl, DefaultTTL if zero, and returns it with its identifier.
+func (s *Store) Propose(ctx context.Context, op *Operation, ttl time.Duration) (*Operation, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	id, err := newOperationId()
+	if err != nil {
+		return nil, errors.Wrapf(errors.Unknown, "failed to generate operation id: %s", err)
+	}
+	now := time.Now()
+	entry := *op
+	entry.Key = &OperationKey{Id: id}
+	entry.Approvals = nil
+	entry.Created = now.Unix()
+	entry.Expiry = now.Add(ttl).Unix()
+	if err := s.table.Insert(ctx, entry.Key, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Get returns the pending operation.
+func (s *Store) Get(ctx context.Context, id string) (*Operation, error) {
+	return s.table.Find(ctx, &OperationKey{Id: id})
+}
+
+// Approve verifies the signed approval and records it for the operation,
+// returning the updated operation. Approvals recorded concurrently may
+// require the approver to retry, as the last write wins, which never
+// results in an operation being approved by fewer approvers.
+func (s *Store) Approve(ctx context.Context, id string, a *Approval) (*Operation, error) {
+	op, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.resolver.GetSecret(ctx, a.Approver)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.Wrapf(errors.Unauthorized, "unknown approver %s", a.Approver)
+		}
+		return nil, err
+	}
+	if err := op.addApproval(a, secret, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.table.Update(ctx, op.Key, &Operation{Approvals: op.Approvals}); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// Execute runs fn for the approved operation, consuming the operation so
+// that it is executed exactly once. Fails with Forbidden if the operation
+// is not approved yet or expired.
+func (s *Store) Execute(ctx context.Context, id string, fn func(ctx context.Context, op *Operation) error) error {
+	op, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !op.IsApproved(time.Now()) {
+		return errors.Wrapf(errors.Forbidden, "operation %s is not approved", id)
+	}
+	// claim the operation, only one of the concurrent executors succeeds
+	if err := s.table.DeleteKey(ctx, op.Key); err != nil {
+		return err
+	}
+	return fn(ctx, op)
+}
+
+// Cancel removes the pending operation.
+func (s *Store) Cancel(ctx context.Context, id string) error {
+	return s.table.DeleteKey(ctx, &OperationKey{Id: id})
+}
+
+// ExpireOperations removes the expired operations, returning the number of
+// operations removed.
+func (s *Store) ExpireOperations(ctx context.Context) (int64, error) {
+	filter := bson.D{{Key: "expiry", Value: bson.M{"$lte": time.Now().Unix()}}}
+	return s.table.DeleteByFilter(ctx, filter)
+}
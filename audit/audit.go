@@ -0,0 +1,146 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package audit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/auth/ipaddr"
+)
+
+/*
+Package audit records the authentication and authorization decisions, who
+was allowed or denied what and why, to a pluggable Sink: the core db store,
+a file or a callback.
+
+Records are emitted by the validation middleware and the authorizers to an
+Emitter, the Batcher writing them to the sink in batches in the background
+so that auditing does not add write latency to the requests.
+
+# Usage
+
+    sink, _ := audit.NewStoreSink(dbStore)
+    auditor := audit.NewBatcher(sink, audit.WithBatchSize(100), audit.WithFlushInterval(time.Second))
+    defer auditor.Close(ctx)
+
+    handler = keys.Middleware(validator, routes, apikey.WithAudit(auditor))(handler)
+    authorizer := audit.NewAuthorizer(rbacStore, auditor)
+*/
+
+// Kind identifies the decision recorded.
+type Kind string
+
+const (
+	KindAuthentication Kind = "authentication"
+	KindAuthorization  Kind = "authorization"
+
+	// API key or source address locked after repeated authentication
+	// failures
+	KindLockout Kind = "lockout"
+)
+
+// Result is the outcome of the decision.
+type Result string
+
+const (
+	ResultAllowed Result = "allowed"
+	ResultDenied  Result = "denied"
+)
+
+// Record is an audited decision.
+type Record struct {
+	Timestamp time.Time `json:"timestamp" bson:"timestamp,omitempty"`
+	Kind      Kind      `json:"kind" bson:"kind,omitempty"`
+	Result    Result    `json:"result" bson:"result,omitempty"`
+
+	// reason of the denial
+	Reason string `json:"reason,omitempty" bson:"reason,omitempty"`
+
+	// set for the denials recorded but not enforced, see
+	// apikey.WithShadowMode
+	Shadow bool `json:"shadow,omitempty" bson:"shadow,omitempty"`
+
+	// caller of the request
+	KeyId    string `json:"keyId,omitempty" bson:"keyId,omitempty"`
+	Subject  string `json:"subject,omitempty" bson:"subject,omitempty"`
+	Tenant   string `json:"tenant,omitempty" bson:"tenant,omitempty"`
+	SourceIP string `json:"sourceIp,omitempty" bson:"sourceIp,omitempty"`
+
+	// subject impersonating the caller, if any
+	ImpersonatedBy string `json:"impersonatedBy,omitempty" bson:"impersonatedBy,omitempty"`
+
+	// request and its route
+	Method string `json:"method,omitempty" bson:"method,omitempty"`
+	Path   string `json:"path,omitempty" bson:"path,omitempty"`
+	Route  string `json:"route,omitempty" bson:"route,omitempty"`
+
+	// RBAC resource and verb authorized
+	Resource string `json:"resource,omitempty" bson:"resource,omitempty"`
+	Verb     string `json:"verb,omitempty" bson:"verb,omitempty"`
+}
+
+// NewRequestRecord returns the record of a decision about the request,
+// with the timestamp, method, path and source IP set.
+func NewRequestRecord(kind Kind, r *http.Request) *Record {
+	return &Record{
+		Timestamp: time.Now(),
+		Kind:      kind,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		SourceIP:  SourceIP(r),
+	}
+}
+
+// Deny marks the record as denied for the reason.
+func (rec *Record) Deny(reason string) *Record {
+	rec.Result = ResultDenied
+	rec.Reason = reason
+	return rec
+}
+
+// Allow marks the record as allowed.
+func (rec *Record) Allow() *Record {
+	rec.Result = ResultAllowed
+	rec.Reason = ""
+	return rec
+}
+
+// SourceIP returns the normalized IP address of the client of the request,
+// see ipaddr.RequestIP.
+func SourceIP(r *http.Request) string {
+	return ipaddr.RequestIP(r)
+}
+
+// Sink persists the audit records.
+type Sink interface {
+	// Write persists the batch of records.
+	Write(ctx context.Context, records []*Record) error
+}
+
+// SinkFunc is an adapter allowing the use of an ordinary function as a
+// Sink.
+type SinkFunc func(ctx context.Context, records []*Record) error
+
+// Write calls f(ctx, records).
+func (f SinkFunc) Write(ctx context.Context, records []*Record) error {
+	return f(ctx, records)
+}
+
+// Emitter receives the audit records of the decisions.
+type Emitter interface {
+	// Emit records the decision, without blocking the caller on the
+	// persistence of the record.
+	Emit(ctx context.Context, rec *Record)
+}
+
+// EmitterFunc is an adapter allowing the use of an ordinary function as an
+// Emitter.
+type EmitterFunc func(ctx context.Context, rec *Record)
+
+// Emit calls f(ctx, rec).
+func (f EmitterFunc) Emit(ctx context.Context, rec *Record) {
+	f(ctx, rec)
+}
@@ -0,0 +1,163 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+// collector is a sink collecting the written batches
+type collector struct {
+	mu      sync.Mutex
+	batches [][]*Record
+}
+
+func (c *collector) Write(ctx context.Context, records []*Record) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batches = append(c.batches, records)
+	return nil
+}
+
+func (c *collector) count() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, b := range c.batches {
+		n += len(b)
+	}
+	return len(c.batches), n
+}
+
+func TestBatcherFlushesOnSize(t *testing.T) {
+	c := &collector{}
+	b := NewBatcher(c, WithBatchSize(2), WithFlushInterval(time.Hour))
+	for i := 0; i < 4; i++ {
+		b.Emit(context.Background(), &Record{Kind: KindAuthentication})
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if batches, _ := c.count(); batches == 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if batches, n := c.count(); batches != 2 || n != 4 {
+		t.Fatalf("expected 2 batches of 4 records, got %d batches of %d records", batches, n)
+	}
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %s", err)
+	}
+}
+
+func TestBatcherFlushesOnClose(t *testing.T) {
+	c := &collector{}
+	b := NewBatcher(c, WithBatchSize(100), WithFlushInterval(time.Hour))
+	for i := 0; i < 3; i++ {
+		b.Emit(context.Background(), &Record{Kind: KindAuthorization})
+	}
+	if err := b.Close(context.Background()); err != nil {
+		t.Fatalf("close failed: %s", err)
+	}
+	if _, n := c.count(); n != 3 {
+		t.Errorf("expected 3 records flushed on close, got %d", n)
+	}
+	b.Emit(context.Background(), &Record{})
+	if b.Dropped() != 1 {
+		t.Errorf("expected the record emitted after close to be dropped, got %d", b.Dropped())
+	}
+}
+
+func TestBatcherFlushesOnInterval(t *testing.T) {
+	done := make(chan int, 1)
+	sink := SinkFunc(func(ctx context.Context, records []*Record) error {
+		done <- len(records)
+		return nil
+	})
+	b := NewBatcher(sink, WithFlushInterval(10*time.Millisecond))
+	defer func() { _ = b.Close(context.Background()) }()
+	b.Emit(context.Background(), &Record{})
+	select {
+	case n := <-done:
+		if n != 1 {
+			t.Errorf("expected 1 record, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("batch not flushed on interval")
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	sink := NewWriterSink(buf)
+	err := sink.Write(context.Background(), []*Record{
+		{KeyId: "k1", Result: ResultAllowed},
+		{KeyId: "k2", Result: ResultDenied, Reason: "expired"},
+	})
+	if err != nil {
+		t.Fatalf("write failed: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 json lines, got %q", buf.String())
+	}
+	rec := &Record{}
+	if err := json.Unmarshal([]byte(lines[1]), rec); err != nil {
+		t.Fatalf("invalid json line: %s", err)
+	}
+	if rec.KeyId != "k2" || rec.Reason != "expired" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+}
+
+func TestNewRequestRecord(t *testing.T) {
+	r := httptest.NewRequest("GET", "/books?x=1", nil)
+	r.RemoteAddr = "[2001:db8::1]:4321"
+	rec := NewRequestRecord(KindAuthentication, r)
+	if rec.Method != "GET" || rec.Path != "/books" || rec.SourceIP != "2001:db8::1" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+	if rec.Deny("bad").Result != ResultDenied || rec.Allow().Reason != "" {
+		t.Errorf("unexpected result transitions %+v", rec)
+	}
+}
+
+func TestAuthorizerEmits(t *testing.T) {
+	var got []*Record
+	emitter := EmitterFunc(func(ctx context.Context, rec *Record) {
+		got = append(got, rec)
+	})
+	base := rbac.AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+		if verb == rbac.VerbDelete {
+			return errors.Wrapf(errors.Forbidden, "not allowed")
+		}
+		return nil
+	})
+	a := NewAuthorizer(base, emitter)
+	id := &authctx.Identity{Subject: "alice", Tenant: "acme"}
+	if err := a.Authorize(context.Background(), id, rbac.ResourceKeys, rbac.VerbGet); err != nil {
+		t.Fatalf("expected allowed, got %s", err)
+	}
+	if err := a.Authorize(context.Background(), id, rbac.ResourceKeys, rbac.VerbDelete); !errors.IsForbidden(err) {
+		t.Fatalf("expected forbidden, got %v", err)
+	}
+	if len(got) != 2 || got[0].Result != ResultAllowed || got[1].Result != ResultDenied {
+		t.Fatalf("unexpected records %+v", got)
+	}
+	if got[1].Subject != "alice" || got[1].Tenant != "acme" || got[1].Verb != rbac.VerbDelete || got[1].Reason == "" {
+		t.Errorf("unexpected denial record %+v", got[1])
+	}
+}
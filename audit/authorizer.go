@@ -0,0 +1,51 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+// authorizer audits the decisions of the underlying authorizer.
+type authorizer struct {
+	base    rbac.Authorizer
+	emitter Emitter
+}
+
+// NewAuthorizer returns an rbac.Authorizer emitting a record of every
+// decision of the base authorizer, with the key ID and tenant of the
+// model.AuthContext of the context when available.
+func NewAuthorizer(base rbac.Authorizer, emitter Emitter) rbac.Authorizer {
+	return &authorizer{base: base, emitter: emitter}
+}
+
+// Authorize authorizes using the base authorizer and records the decision.
+func (a *authorizer) Authorize(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+	err := rbac.Check(ctx, a.base, id, resource, verb)
+	rec := &Record{
+		Timestamp: time.Now(),
+		Kind:      KindAuthorization,
+		Resource:  resource,
+		Verb:      verb,
+	}
+	if id != nil {
+		rec.Subject = id.Subject
+		rec.Tenant = id.Tenant
+	}
+	if ac, ok := model.FromContext(ctx); ok {
+		rec.KeyId = ac.KeyId
+	}
+	if err != nil {
+		rec.Deny(err.Error())
+	} else {
+		rec.Allow()
+	}
+	a.emitter.Emit(ctx, rec)
+	return err
+}
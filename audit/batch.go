@@ -0,0 +1,186 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultBatchSize is the number of records written to the sink at once
+	DefaultBatchSize = 100
+
+	// DefaultFlushInterval is the longest a record is held before written
+	DefaultFlushInterval = time.Second
+
+	// DefaultQueueSize is the number of records queued for writing, beyond
+	// which records are dropped instead of blocking the requests
+	DefaultQueueSize = 10000
+)
+
+// BatchOption configures a Batcher.
+type BatchOption func(*batchOptions)
+
+// batchOptions holds the configuration of a Batcher.
+type batchOptions struct {
+	size     int
+	interval time.Duration
+	queue    int
+	onError  func(error)
+}
+
+// WithBatchSize sets the number of records written to the sink at once.
+func WithBatchSize(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.size = n
+	}
+}
+
+// WithFlushInterval sets the longest a record is held before it is written
+// to the sink, even if the batch is not full.
+func WithFlushInterval(d time.Duration) BatchOption {
+	return func(o *batchOptions) {
+		o.interval = d
+	}
+}
+
+// WithQueueSize sets the number of records queued for writing.
+func WithQueueSize(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.queue = n
+	}
+}
+
+// WithErrorHandler sets the callback receiving the failures of the sink,
+// which are otherwise ignored.
+func WithErrorHandler(fn func(error)) BatchOption {
+	return func(o *batchOptions) {
+		o.onError = fn
+	}
+}
+
+// Batcher is an Emitter queueing the records and writing them to the sink
+// in batches from a background goroutine, flushed when a batch is full or
+// the flush interval elapses. Records emitted while the queue is full are
+// dropped and counted, auditing never blocks the request being audited.
+type Batcher struct {
+	sink    Sink
+	opts    batchOptions
+	records chan *Record
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	closed  atomic.Bool
+	dropped atomic.Uint64
+}
+
+// NewBatcher returns a Batcher writing to the sink, running until Close.
+func NewBatcher(sink Sink, opts ...BatchOption) *Batcher {
+	o := batchOptions{
+		size:     DefaultBatchSize,
+		interval: DefaultFlushInterval,
+		queue:    DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.size = max(o.size, 1)
+	o.queue = max(o.queue, o.size)
+	if o.interval <= 0 {
+		o.interval = DefaultFlushInterval
+	}
+	b := &Batcher{
+		sink:    sink,
+		opts:    o,
+		records: make(chan *Record, o.queue),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Emit queues the record for writing, dropping it if the queue is full or
+// the batcher is closed.
+func (b *Batcher) Emit(ctx context.Context, rec *Record) {
+	if rec == nil {
+		return
+	}
+	if b.closed.Load() {
+		b.dropped.Add(1)
+		return
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now()
+	}
+	select {
+	case b.records <- rec:
+	default:
+		b.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of records dropped so far.
+func (b *Batcher) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+// Close flushes the queued records and stops the batcher, waiting for the
+// final write until the context is done.
+func (b *Batcher) Close(ctx context.Context) error {
+	b.once.Do(func() {
+		b.closed.Store(true)
+		close(b.done)
+	})
+	select {
+	case <-b.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run collects the queued records into batches and writes them.
+func (b *Batcher) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.opts.interval)
+	defer ticker.Stop()
+	batch := make([]*Record, 0, b.opts.size)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := b.sink.Write(context.Background(), batch); err != nil && b.opts.onError != nil {
+			b.opts.onError(err)
+		}
+		batch = make([]*Record, 0, b.opts.size)
+	}
+	for {
+		select {
+		case rec := <-b.records:
+			batch = append(batch, rec)
+			if len(batch) >= b.opts.size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-b.done:
+			for {
+				select {
+				case rec := <-b.records:
+					batch = append(batch, rec)
+					if len(batch) >= b.opts.size {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+)
+
+// Collection name within the database the consumer supplies via db.Store.
+const RecordsCollection = "audit_records"
+
+// writerSink writes the records as JSON lines.
+type writerSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewWriterSink returns a Sink writing the records to w as JSON lines, e.g.
+// to an append only file.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{enc: json.NewEncoder(w)}
+}
+
+// Write writes the records.
+func (s *writerSink) Write(ctx context.Context, records []*Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rec := range records {
+		if err := s.enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordKey identifies a stored audit record.
+type RecordKey struct {
+	Id string `bson:"id,omitempty"`
+}
+
+// storedRecord is the audit record as stored.
+type storedRecord struct {
+	Key    *RecordKey `bson:"key,omitempty"`
+	Record `bson:",inline"`
+}
+
+// storeSink writes the records to the core db store.
+type storeSink struct {
+	table *table.Table[RecordKey, storedRecord]
+}
+
+// NewStoreSink returns a Sink writing the records to the database supplied
+// by the consumer.
+func NewStoreSink(store db.Store) (Sink, error) {
+	if store == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "audit: db store is required")
+	}
+	tbl := &table.Table[RecordKey, storedRecord]{}
+	if err := tbl.Initialize(store.GetCollection(RecordsCollection)); err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "audit: failed to initialize record table: %s", err)
+	}
+	return &storeSink{table: tbl}, nil
+}
+
+// Write inserts the records, continuing past failures and returning the
+// first one.
+func (s *storeSink) Write(ctx context.Context, records []*Record) error {
+	var failure error
+	for _, rec := range records {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return errors.Wrapf(errors.Unknown, "failed to generate record id: %s", err)
+		}
+		entry := &storedRecord{Key: &RecordKey{Id: hex.EncodeToString(b)}, Record: *rec}
+		if err := s.table.Insert(ctx, entry.Key, entry); err != nil && failure == nil {
+			failure = err
+		}
+	}
+	return failure
+}
@@ -0,0 +1,162 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package binding
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/go-core-stack/auth/model"
+)
+
+/*
+Package binding binds the issued tokens to the credential of the client
+they are issued to, so that a stolen bearer token can not be replayed by
+another client.
+
+The token carries a confirmation claim, "cnf" as per RFC 7800, holding the
+thumbprint of the credential presented when the token was issued: the
+SHA-256 of the API key id of HMAC signed requests, or the SHA-256 of the
+client certificate of mTLS connections ("x5t#S256" as per RFC 8705). On
+use, the request must be presented with the same credential, i.e. signed
+with the same API key, as validated by the validation middleware, or sent
+over a connection authenticated with the same client certificate.
+
+Tokens are not parsed by this package, the token subsystem embeds the
+Confirmation in its claims when issuing, and extracts it for the
+Middleware when validating.
+
+# Usage
+
+    // issuing, on a request authenticated with the client credential
+    cnf, err := binding.FromRequest(r)
+    claims.Cnf = cnf
+
+    // validating, behind the validation middleware
+    handler = binding.Middleware(func(r *http.Request) (*binding.Confirmation, error) {
+        claims, err := verifyToken(r)
+        if err != nil {
+            return nil, err
+        }
+        return claims.Cnf, nil
+    })(handler)
+*/
+
+var (
+	// ErrNoCredential is returned when binding to a request presented
+	// without a client credential.
+	ErrNoCredential = errors.New("no client credential to bind to")
+
+	// ErrBindingMismatch is returned for tokens presented with another
+	// credential than the one they are bound to.
+	ErrBindingMismatch = errors.New("token binding mismatch")
+)
+
+// Confirmation is the confirmation claim of a bound token.
+type Confirmation struct {
+	// base64url SHA-256 of the API key id signing the requests
+	KeyThumbprint string `json:"kid#S256,omitempty"`
+
+	// base64url SHA-256 of the DER of the client certificate
+	CertThumbprint string `json:"x5t#S256,omitempty"`
+}
+
+// thumbprint returns the base64url SHA-256 of the value.
+func thumbprint(b []byte) string {
+	sum := sha256.Sum256(b)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// ForKey returns the confirmation binding to the API key.
+func ForKey(keyId string) *Confirmation {
+	return &Confirmation{KeyThumbprint: thumbprint([]byte(keyId))}
+}
+
+// ForCertificate returns the confirmation binding to the client
+// certificate.
+func ForCertificate(cert *x509.Certificate) *Confirmation {
+	return &Confirmation{CertThumbprint: thumbprint(cert.Raw)}
+}
+
+// clientCertificate returns the client certificate of the mTLS connection
+// of the request, if any.
+func clientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return r.TLS.PeerCertificates[0]
+}
+
+// requestKeyId returns the API key authenticated by the validation
+// middleware for the request, if any.
+func requestKeyId(r *http.Request) string {
+	if a, ok := model.FromContext(r.Context()); ok {
+		return a.KeyId
+	}
+	return ""
+}
+
+// FromRequest returns the confirmation binding to the credential the
+// request is presented with, the client certificate of mTLS connections,
+// and the API key authenticated by the validation middleware otherwise.
+func FromRequest(r *http.Request) (*Confirmation, error) {
+	if cert := clientCertificate(r); cert != nil {
+		return ForCertificate(cert), nil
+	}
+	if keyId := requestKeyId(r); keyId != "" {
+		return ForKey(keyId), nil
+	}
+	return nil, ErrNoCredential
+}
+
+// equal compares the thumbprints in constant time.
+func equal(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// Verify ensures the request is presented with the credential the token
+// is bound to, a token without any thumbprint is rejected.
+func (c *Confirmation) Verify(r *http.Request) error {
+	if c == nil || (c.KeyThumbprint == "" && c.CertThumbprint == "") {
+		return ErrBindingMismatch
+	}
+	if c.CertThumbprint != "" {
+		cert := clientCertificate(r)
+		if cert == nil || !equal(c.CertThumbprint, thumbprint(cert.Raw)) {
+			return ErrBindingMismatch
+		}
+	}
+	if c.KeyThumbprint != "" {
+		keyId := requestKeyId(r)
+		if keyId == "" || !equal(c.KeyThumbprint, thumbprint([]byte(keyId))) {
+			return ErrBindingMismatch
+		}
+	}
+	return nil
+}
+
+// ConfirmationFunc returns the confirmation claim of the token presented
+// with the request, failing if the token is invalid.
+type ConfirmationFunc func(r *http.Request) (*Confirmation, error)
+
+// Middleware returns a middleware rejecting with 401 the requests whose
+// token is invalid or not bound to the credential the request is
+// presented with. It is expected behind the validation middleware, which
+// authenticates the API key of the request.
+func Middleware(confirmation ConfirmationFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cnf, err := confirmation(r)
+			if err != nil || cnf.Verify(r) != nil {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
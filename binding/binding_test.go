@@ -0,0 +1,80 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package binding
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/auth/model"
+)
+
+func withKey(r *http.Request, keyId string) *http.Request {
+	return r.WithContext(model.WithAuthContext(r.Context(), &model.AuthContext{KeyId: keyId}))
+}
+
+func TestKeyBinding(t *testing.T) {
+	req := withKey(httptest.NewRequest(http.MethodGet, "/books", nil), "k1")
+	cnf, err := FromRequest(req)
+	if err != nil {
+		t.Fatalf("failed to bind: %s", err)
+	}
+	b, _ := json.Marshal(cnf)
+	if string(b) != `{"kid#S256":"`+ForKey("k1").KeyThumbprint+`"}` {
+		t.Errorf("unexpected claim %s", b)
+	}
+	if err := cnf.Verify(req); err != nil {
+		t.Errorf("expected the same key to verify: %s", err)
+	}
+	other := withKey(httptest.NewRequest(http.MethodGet, "/books", nil), "k2")
+	if err := cnf.Verify(other); !errors.Is(err, ErrBindingMismatch) {
+		t.Errorf("expected another key to be rejected, got %v", err)
+	}
+	if err := cnf.Verify(httptest.NewRequest(http.MethodGet, "/books", nil)); err == nil {
+		t.Errorf("expected unauthenticated request to be rejected")
+	}
+	if _, err := FromRequest(httptest.NewRequest(http.MethodGet, "/books", nil)); !errors.Is(err, ErrNoCredential) {
+		t.Errorf("expected no credential, got %v", err)
+	}
+}
+
+func TestCertificateBinding(t *testing.T) {
+	cert := &x509.Certificate{Raw: []byte("client-cert")}
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	cnf, err := FromRequest(withKey(req, "k1"))
+	if err != nil || cnf.CertThumbprint == "" || cnf.KeyThumbprint != "" {
+		t.Fatalf("expected binding to the certificate, got %+v %v", cnf, err)
+	}
+	if err := cnf.Verify(req); err != nil {
+		t.Errorf("expected the same certificate to verify: %s", err)
+	}
+	req.TLS.PeerCertificates = []*x509.Certificate{{Raw: []byte("other-cert")}}
+	if err := cnf.Verify(req); !errors.Is(err, ErrBindingMismatch) {
+		t.Errorf("expected another certificate to be rejected, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cnf := ForKey("k1")
+	h := Middleware(func(r *http.Request) (*Confirmation, error) {
+		return cnf, nil
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, withKey(httptest.NewRequest(http.MethodGet, "/books", nil), "k1"))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected bound request to be allowed, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, withKey(httptest.NewRequest(http.MethodGet, "/books", nil), "k2"))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected replayed token to be rejected, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package bundle
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+)
+
+/*
+Package bundle exports the auth configuration as a signed, versioned
+bundle, consumed by lightweight edge validators enforcing auth fully
+offline, without access to the route, key and role stores.
+
+A bundle holds the routes, the public keys of the asymmetrically signing
+API keys, the policies of the API keys (tenancy, scopes, expiry, disabled
+flag) and the snapshots of the roles and role bindings. It is signed with
+the Ed25519 key of the exporter, and carries a serial, increasing with
+every export, and an expiry past which it must no longer be enforced.
+
+The edge validator keeps the latest verified bundle in a Holder, refusing
+bundles older than the one held, and raising a staleness alarm once the
+held bundle is not refreshed in time.
+
+# Usage
+
+    // control plane
+    exporter := bundle.NewExporter("bundle-key-1", priv, time.Hour, bundle.Sources{
+        Routes:      bundle.RoutesFrom(routeTable, nil),
+        KeyPolicies: bundle.KeyPoliciesFrom(keyStore, "acme"),
+        Roles:       bundle.RolesFrom(roleStore, "acme"),
+    })
+    mux.Handle("/v1/bundle", exporter.Handler())
+
+    // edge
+    holder := bundle.NewHolder(map[string]ed25519.PublicKey{"bundle-key-1": pub},
+        bundle.WithStaleAfter(10*time.Minute),
+        bundle.WithStalenessAlarm(func(age time.Duration) { log.Printf("bundle stale for %s", age) }))
+    err := holder.Load(data)
+    b, err := holder.Current()
+*/
+
+// FormatVersion is the version of the bundle format produced by the
+// exporter, bundles of other versions are refused.
+const FormatVersion = 1
+
+// signingContext separates the bundle signatures from the signatures of
+// other payloads using the same key.
+const signingContext = "go-core-stack/auth/bundle\n"
+
+var (
+	// ErrInvalidSignature is returned for bundles not signed by a known
+	// key.
+	ErrInvalidSignature = errors.New("invalid bundle signature")
+
+	// ErrUnsupportedVersion is returned for bundles of an unsupported
+	// format version.
+	ErrUnsupportedVersion = errors.New("unsupported bundle version")
+
+	// ErrExpired is returned for bundles past their expiry.
+	ErrExpired = errors.New("bundle expired")
+
+	// ErrRollback is returned for bundles older than the one held.
+	ErrRollback = errors.New("bundle older than the current one")
+
+	// ErrNoBundle is returned by the Holder before a bundle is loaded.
+	ErrNoBundle = errors.New("no bundle loaded")
+)
+
+// PublicKey is the public key of an API key signing asymmetrically.
+type PublicKey struct {
+	KeyId     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+
+	// encoded public key, as expected by the Validator of the algorithm,
+	// e.g. hash.EncodeEd25519PublicKey
+	PublicKey string `json:"public_key"`
+}
+
+// KeyPolicy is the policy of an API key.
+type KeyPolicy struct {
+	KeyId    string   `json:"key_id"`
+	Owner    string   `json:"owner,omitempty"`
+	Tenant   string   `json:"tenant,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Expiry   int64    `json:"expiry,omitempty"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+// RoleSnapshot is the snapshot of the roles and role bindings of a
+// tenancy, see rbac.NewPolicy.
+type RoleSnapshot struct {
+	Tenant   string              `json:"tenant"`
+	Roles    []*rbac.Role        `json:"roles,omitempty"`
+	Bindings []*rbac.RoleBinding `json:"bindings,omitempty"`
+}
+
+// Policy returns the policy evaluating the permissions of the tenancy.
+func (s *RoleSnapshot) Policy() *rbac.Policy {
+	return rbac.NewPolicy(s.Tenant, s.Roles, s.Bindings)
+}
+
+// Bundle is the auth configuration enforced by the edge validators.
+type Bundle struct {
+	Version int   `json:"version"`
+	Serial  int64 `json:"serial"`
+
+	// time of the export and expiry of the bundle, unix seconds
+	Issued int64 `json:"issued"`
+	Expiry int64 `json:"expiry"`
+
+	Routes      []*route.Route  `json:"routes,omitempty"`
+	PublicKeys  []*PublicKey    `json:"public_keys,omitempty"`
+	KeyPolicies []*KeyPolicy    `json:"key_policies,omitempty"`
+	Roles       []*RoleSnapshot `json:"roles,omitempty"`
+}
+
+// IsExpired reports whether the bundle is expired at the time.
+func (b *Bundle) IsExpired(now time.Time) bool {
+	return now.Unix() >= b.Expiry
+}
+
+// Age returns the time elapsed since the export of the bundle.
+func (b *Bundle) Age(now time.Time) time.Duration {
+	return now.Sub(time.Unix(b.Issued, 0))
+}
+
+// signed is the envelope of a signed bundle.
+type signed struct {
+	KeyId     string `json:"kid"`
+	Payload   []byte `json:"payload"`
+	Signature []byte `json:"signature"`
+}
+
+// Sign signs the bundle with the private key identified by keyId,
+// returning the signed bundle.
+func Sign(b *Bundle, keyId string, priv ed25519.PrivateKey) ([]byte, error) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&signed{
+		KeyId:     keyId,
+		Payload:   payload,
+		Signature: ed25519.Sign(priv, append([]byte(signingContext), payload...)),
+	})
+}
+
+// Open verifies the signed bundle with the public key identified by the
+// bundle, failing for unknown keys, unsupported format versions and
+// bundles expired at the time.
+func Open(data []byte, keys map[string]ed25519.PublicKey, now time.Time) (*Bundle, error) {
+	env := &signed{}
+	if err := json.Unmarshal(data, env); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	pub, ok := keys[env.KeyId]
+	if !ok || !ed25519.Verify(pub, append([]byte(signingContext), env.Payload...), env.Signature) {
+		return nil, ErrInvalidSignature
+	}
+	b := &Bundle{}
+	if err := json.Unmarshal(env.Payload, b); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	if b.Version != FormatVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, b.Version)
+	}
+	if b.IsExpired(now) {
+		return nil, ErrExpired
+	}
+	return b, nil
+}
+
+// Sources provide the content of the exported bundles, sources left nil
+// are not exported.
+type Sources struct {
+	Routes      func(ctx context.Context) ([]*route.Route, error)
+	PublicKeys  func(ctx context.Context) ([]*PublicKey, error)
+	KeyPolicies func(ctx context.Context) ([]*KeyPolicy, error)
+	Roles       func(ctx context.Context) ([]*RoleSnapshot, error)
+}
+
+// RoutesFrom returns the source of the routes of the table matching the
+// filter, all the routes if nil.
+func RoutesFrom(tbl *route.RouteTable, filter *route.RouteFilter) func(ctx context.Context) ([]*route.Route, error) {
+	return func(ctx context.Context) ([]*route.Route, error) {
+		return tbl.ListRoutes(ctx, filter)
+	}
+}
+
+// KeyPoliciesFrom returns the source of the policies of the API keys of
+// the tenancy.
+func KeyPoliciesFrom(store *apikey.Store, tenant string) func(ctx context.Context) ([]*KeyPolicy, error) {
+	return func(ctx context.Context) ([]*KeyPolicy, error) {
+		keys, err := store.List(ctx, tenant, "")
+		if err != nil {
+			return nil, err
+		}
+		policies := make([]*KeyPolicy, 0, len(keys))
+		for _, k := range keys {
+			policies = append(policies, &KeyPolicy{
+				KeyId:    k.Key.Id,
+				Owner:    k.Owner,
+				Tenant:   k.Tenant,
+				Scopes:   k.Scopes,
+				Expiry:   k.Expiry,
+				Disabled: k.IsDisabled(),
+			})
+		}
+		return policies, nil
+	}
+}
+
+// RolesFrom returns the source of the snapshot of the roles and role
+// bindings of the tenancies.
+func RolesFrom(store *rbac.Store, tenants ...string) func(ctx context.Context) ([]*RoleSnapshot, error) {
+	return func(ctx context.Context) ([]*RoleSnapshot, error) {
+		snapshots := make([]*RoleSnapshot, 0, len(tenants))
+		for _, tenant := range tenants {
+			roles, err := store.ListRoles(ctx, tenant)
+			if err != nil {
+				return nil, err
+			}
+			bindings, err := store.ListBindings(ctx, tenant, "")
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, &RoleSnapshot{Tenant: tenant, Roles: roles, Bindings: bindings})
+		}
+		return snapshots, nil
+	}
+}
+
+// Exporter exports the signed bundles.
+type Exporter struct {
+	keyId    string
+	priv     ed25519.PrivateKey
+	validity time.Duration
+	sources  Sources
+	now      func() time.Time
+}
+
+// NewExporter creates the exporter signing the bundles collected from the
+// sources with the private key identified by keyId, the bundles expiring
+// after validity.
+func NewExporter(keyId string, priv ed25519.PrivateKey, validity time.Duration, sources Sources) *Exporter {
+	return &Exporter{
+		keyId:    keyId,
+		priv:     priv,
+		validity: validity,
+		sources:  sources,
+		now:      time.Now,
+	}
+}
+
+// Build collects the bundle from the sources.
+func (e *Exporter) Build(ctx context.Context) (*Bundle, error) {
+	now := e.now()
+	b := &Bundle{
+		Version: FormatVersion,
+		Serial:  now.UnixNano(),
+		Issued:  now.Unix(),
+		Expiry:  now.Add(e.validity).Unix(),
+	}
+	var err error
+	if e.sources.Routes != nil {
+		if b.Routes, err = e.sources.Routes(ctx); err != nil {
+			return nil, fmt.Errorf("failed to export routes: %w", err)
+		}
+	}
+	if e.sources.PublicKeys != nil {
+		if b.PublicKeys, err = e.sources.PublicKeys(ctx); err != nil {
+			return nil, fmt.Errorf("failed to export public keys: %w", err)
+		}
+	}
+	if e.sources.KeyPolicies != nil {
+		if b.KeyPolicies, err = e.sources.KeyPolicies(ctx); err != nil {
+			return nil, fmt.Errorf("failed to export key policies: %w", err)
+		}
+	}
+	if e.sources.Roles != nil {
+		if b.Roles, err = e.sources.Roles(ctx); err != nil {
+			return nil, fmt.Errorf("failed to export roles: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// Export collects and signs the bundle.
+func (e *Exporter) Export(ctx context.Context) ([]byte, error) {
+	b, err := e.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Sign(b, e.keyId, e.priv)
+}
+
+// Handler returns the handler serving the freshly exported bundle.
+func (e *Exporter) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := e.Export(r.Context())
+		if err != nil {
+			http.Error(w, "failed to export bundle", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(data)
+	})
+}
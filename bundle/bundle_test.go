@@ -0,0 +1,111 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package bundle
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+)
+
+func newTestExporter(t *testing.T) (*Exporter, map[string]ed25519.PublicKey) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+	e := NewExporter("bundle-key", priv, time.Hour, Sources{
+		Routes: func(ctx context.Context) ([]*route.Route, error) {
+			return []*route.Route{{Key: &route.Key{Url: "/books", Method: route.GET}, Endpoint: "http://books:8080"}}, nil
+		},
+		KeyPolicies: func(ctx context.Context) ([]*KeyPolicy, error) {
+			return []*KeyPolicy{{KeyId: "k1", Tenant: "acme", Scopes: []string{"books:read"}}}, nil
+		},
+		Roles: func(ctx context.Context) ([]*RoleSnapshot, error) {
+			return []*RoleSnapshot{{
+				Tenant:   "acme",
+				Roles:    []*rbac.Role{{Key: &rbac.RoleKey{Tenant: "acme", Name: "reader"}, Rules: []*rbac.Rule{{Resources: []string{"books"}, Verbs: []string{"get"}}}}},
+				Bindings: []*rbac.RoleBinding{{Key: &rbac.RoleBindingKey{Tenant: "acme", Subject: "alice", Role: "reader"}}},
+			}}, nil
+		},
+	})
+	return e, map[string]ed25519.PublicKey{"bundle-key": pub}
+}
+
+func TestExportAndOpen(t *testing.T) {
+	e, keys := newTestExporter(t)
+	data, err := e.Export(context.Background())
+	if err != nil {
+		t.Fatalf("failed to export: %s", err)
+	}
+	b, err := Open(data, keys, time.Now())
+	if err != nil {
+		t.Fatalf("failed to open: %s", err)
+	}
+	if len(b.Routes) != 1 || b.Routes[0].Key.Method != route.GET || len(b.KeyPolicies) != 1 {
+		t.Errorf("unexpected bundle %+v", b)
+	}
+	if !b.Roles[0].Policy().Evaluate("alice", "books", "get") {
+		t.Errorf("expected role snapshot to grant the permission")
+	}
+
+	if _, err := Open(data, map[string]ed25519.PublicKey{}, time.Now()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected unknown key to be refused, got %v", err)
+	}
+	tampered := []byte(string(data))
+	tampered[len(tampered)/3] ^= 1
+	if _, err := Open(tampered, keys, time.Now()); err == nil {
+		t.Errorf("expected tampered bundle to be refused")
+	}
+	if _, err := Open(data, keys, time.Now().Add(2*time.Hour)); !errors.Is(err, ErrExpired) {
+		t.Errorf("expected expired bundle to be refused, got %v", err)
+	}
+}
+
+func TestHolder(t *testing.T) {
+	e, keys := newTestExporter(t)
+	now := time.Unix(time.Now().Unix(), 0)
+	var alarms []time.Duration
+	h := NewHolder(keys, WithStalenessAlarm(func(age time.Duration) { alarms = append(alarms, age) }))
+	h.now = func() time.Time { return now }
+
+	if _, err := h.Current(); !errors.Is(err, ErrNoBundle) {
+		t.Errorf("expected no bundle, got %v", err)
+	}
+
+	e.now = func() time.Time { return now.Add(-time.Minute) }
+	older, _ := e.Export(context.Background())
+	e.now = func() time.Time { return now }
+	newer, _ := e.Export(context.Background())
+	if err := h.Load(newer); err != nil {
+		t.Fatalf("failed to load: %s", err)
+	}
+	if err := h.Load(older); !errors.Is(err, ErrRollback) {
+		t.Errorf("expected rollback to be refused, got %v", err)
+	}
+	if f := h.Check(); f != Fresh || len(alarms) != 0 {
+		t.Errorf("expected fresh bundle without alarm, got %s %v", f, alarms)
+	}
+
+	now = now.Add(40 * time.Minute)
+	if f := h.Check(); f != Stale || len(alarms) != 1 || alarms[0] != 40*time.Minute {
+		t.Errorf("expected stale bundle alarm, got %s %v", f, alarms)
+	}
+	if _, err := h.Current(); err != nil {
+		t.Errorf("expected stale bundle to be enforced: %s", err)
+	}
+
+	now = now.Add(time.Hour)
+	if f := h.Check(); f != Expired {
+		t.Errorf("expected expired bundle, got %s", f)
+	}
+	if _, err := h.Current(); !errors.Is(err, ErrExpired) {
+		t.Errorf("expected expired bundle to be refused, got %v", err)
+	}
+}
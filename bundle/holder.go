@@ -0,0 +1,145 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package bundle
+
+import (
+	"context"
+	"crypto/ed25519"
+	"sync"
+	"time"
+)
+
+// Freshness classifies the bundle held by the edge validator.
+type Freshness string
+
+const (
+	// bundle refreshed in time
+	Fresh Freshness = "fresh"
+
+	// bundle not refreshed in time, still enforced until it expires
+	Stale Freshness = "stale"
+
+	// bundle expired, or not loaded yet, requests can not be validated
+	Expired Freshness = "expired"
+)
+
+// HolderOption configures a Holder.
+type HolderOption func(*Holder)
+
+// WithStaleAfter sets the age past which the held bundle is stale, half
+// of its validity if not set.
+func WithStaleAfter(d time.Duration) HolderOption {
+	return func(h *Holder) {
+		h.staleAfter = d
+	}
+}
+
+// WithStalenessAlarm sets the callback invoked by Check while the held
+// bundle is stale or expired, with its age.
+func WithStalenessAlarm(alarm func(age time.Duration)) HolderOption {
+	return func(h *Holder) {
+		h.alarm = alarm
+	}
+}
+
+// Holder keeps the latest verified bundle of an edge validator.
+type Holder struct {
+	keys       map[string]ed25519.PublicKey
+	staleAfter time.Duration
+	alarm      func(age time.Duration)
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	current *Bundle
+}
+
+// NewHolder creates the holder verifying the bundles with the public keys
+// of the exporters, by key id.
+func NewHolder(keys map[string]ed25519.PublicKey, opts ...HolderOption) *Holder {
+	h := &Holder{
+		keys: keys,
+		now:  time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Load verifies the signed bundle and holds it, refusing bundles older
+// than the one held.
+func (h *Holder) Load(data []byte) error {
+	b, err := Open(data, h.keys, h.now())
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.current != nil && b.Serial < h.current.Serial {
+		return ErrRollback
+	}
+	h.current = b
+	return nil
+}
+
+// Current returns the bundle held, failing if none is loaded or it is
+// expired.
+func (h *Holder) Current() (*Bundle, error) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.current == nil {
+		return nil, ErrNoBundle
+	}
+	if h.current.IsExpired(h.now()) {
+		return nil, ErrExpired
+	}
+	return h.current, nil
+}
+
+// Freshness classifies the held bundle at the time, along with its age.
+func (h *Holder) Freshness(now time.Time) (Freshness, time.Duration) {
+	h.mu.RLock()
+	b := h.current
+	h.mu.RUnlock()
+	if b == nil {
+		return Expired, 0
+	}
+	age := b.Age(now)
+	if b.IsExpired(now) {
+		return Expired, age
+	}
+	staleAfter := h.staleAfter
+	if staleAfter <= 0 {
+		staleAfter = time.Duration(b.Expiry-b.Issued) * time.Second / 2
+	}
+	if age >= staleAfter {
+		return Stale, age
+	}
+	return Fresh, age
+}
+
+// Check classifies the held bundle, raising the staleness alarm if it is
+// not fresh.
+func (h *Holder) Check() Freshness {
+	f, age := h.Freshness(h.now())
+	if f != Fresh && h.alarm != nil {
+		h.alarm(age)
+	}
+	return f
+}
+
+// Run checks the held bundle at every interval until the context is
+// done.
+func (h *Holder) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Check()
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package chaos
+
+import (
+	"time"
+)
+
+/*
+Package chaos provides fault-injection hooks for the auth layer, allowing
+services to test their handling of secret resolver latency and errors, as
+well as forced signature validation failures.
+
+Faults are only injected in binaries built with the "chaos" build tag, in
+every other build the wrappers return the wrapped component unchanged, so
+the hooks can stay wired in production code at no cost:
+
+    go test -tags chaos ./...
+    go build -tags chaos ./cmd/...
+
+# Usage
+
+    cfg := chaos.Config{
+        Enabled:              os.Getenv("AUTH_CHAOS") == "true",
+        ResolverLatency:      200 * time.Millisecond,
+        ResolverErrorRate:    0.05,
+        SignatureFailureRate: 0.01,
+    }
+    resolver = chaos.WrapSecretResolver(resolver, cfg)
+    validator = chaos.WrapValidator(validator, cfg)
+*/
+
+// Config describes the faults to be injected, rates are fractions in the
+// range [0, 1] of the calls that are affected.
+type Config struct {
+	// Enabled toggles fault injection at runtime
+	Enabled bool
+
+	// ResolverLatency is added to every secret resolution
+	ResolverLatency time.Duration
+
+	// ResolverErrorRate is the rate of secret resolutions failing
+	ResolverErrorRate float64
+
+	// SignatureFailureRate is the rate of validations forced to fail
+	SignatureFailureRate float64
+}
@@ -0,0 +1,39 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package chaos
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestWrappers(t *testing.T) {
+	base := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "supersecret", nil
+	})
+	cfg := Config{Enabled: true, ResolverErrorRate: 1, SignatureFailureRate: 1}
+	resolver := WrapSecretResolver(base, cfg)
+	validator := WrapValidator(hash.NewValidator(60), cfg)
+
+	_, err := resolver.GetSecret(context.Background(), "test-key")
+	req := hash.NewGenerator("test-key", "supersecret").AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+	ok, _ := validator.Validate(req, "supersecret")
+
+	if Available {
+		if err == nil || ok {
+			t.Errorf("expected faults to be injected")
+		}
+	} else if err != nil || !ok {
+		t.Errorf("expected wrappers to be a no-op without the chaos build tag")
+	}
+
+	// disabled config never injects faults
+	cfg.Enabled = false
+	if _, err := WrapSecretResolver(base, cfg).GetSecret(context.Background(), "test-key"); err != nil {
+		t.Errorf("unexpected error with disabled config: %s", err)
+	}
+}
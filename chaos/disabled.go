@@ -0,0 +1,25 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build !chaos
+
+package chaos
+
+import (
+	"github.com/go-core-stack/auth/hash"
+)
+
+// Available reports whether fault injection is compiled in.
+const Available = false
+
+// WrapSecretResolver returns the resolver unchanged, faults are only
+// injected in builds with the chaos build tag.
+func WrapSecretResolver(base hash.SecretResolver, cfg Config) hash.SecretResolver {
+	return base
+}
+
+// WrapValidator returns the validator unchanged, faults are only injected
+// in builds with the chaos build tag.
+func WrapValidator(base hash.Validator, cfg Config) hash.Validator {
+	return base
+}
@@ -0,0 +1,86 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build chaos
+
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// Available reports whether fault injection is compiled in.
+const Available = true
+
+// hit reports whether a call is affected by a fault with the given rate.
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// faultyResolver injects latency and errors into secret resolution.
+type faultyResolver struct {
+	base hash.SecretResolver
+	cfg  Config
+}
+
+// GetSecret resolves the secret using the wrapped resolver after injecting
+// the configured faults.
+func (r *faultyResolver) GetSecret(ctx context.Context, keyId string) (string, error) {
+	if r.cfg.ResolverLatency > 0 {
+		select {
+		case <-time.After(r.cfg.ResolverLatency):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	if hit(r.cfg.ResolverErrorRate) {
+		return "", errors.Wrapf(errors.Unknown, "chaos: injected secret resolver failure for key %s", keyId)
+	}
+	return r.base.GetSecret(ctx, keyId)
+}
+
+// WrapSecretResolver returns a resolver injecting the configured latency and
+// errors, or the resolver unchanged if fault injection is not enabled.
+func WrapSecretResolver(base hash.SecretResolver, cfg Config) hash.SecretResolver {
+	if !cfg.Enabled {
+		return base
+	}
+	return &faultyResolver{base: base, cfg: cfg}
+}
+
+// faultyValidator forces validation failures at the configured rate.
+type faultyValidator struct {
+	hash.Validator
+	cfg Config
+}
+
+// Unwrap returns the wrapped validator.
+func (v *faultyValidator) Unwrap() hash.Validator {
+	return v.Validator
+}
+
+// Validate validates the request using the wrapped validator unless the
+// validation is forced to fail.
+func (v *faultyValidator) Validate(r *http.Request, secret string) (bool, error) {
+	if hit(v.cfg.SignatureFailureRate) {
+		return false, fmt.Errorf("chaos: injected signature failure")
+	}
+	return v.Validator.Validate(r, secret)
+}
+
+// WrapValidator returns a validator forcing failures at the configured
+// rate, or the validator unchanged if fault injection is not enabled.
+func WrapValidator(base hash.Validator, cfg Config) hash.Validator {
+	if !cfg.Enabled {
+		return base
+	}
+	return &faultyValidator{Validator: base, cfg: cfg}
+}
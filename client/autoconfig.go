@@ -0,0 +1,193 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/metadata"
+)
+
+// maxMetadataBytes caps the size of the auth configuration document read
+// from the server.
+const maxMetadataBytes = 1 << 20
+
+// AutoConfig configures the fetching of the auth configuration of the
+// server at startup, from which the strongest signing profile supported by
+// both the client and the server is picked, see WithAutoConfig.
+type AutoConfig struct {
+	// path of the configuration relative to the host of the endpoint,
+	// default metadata.WellKnownPath
+	Path string
+
+	// duration a fetched configuration is reused by the clients created
+	// for the same endpoint, default metadata.DefaultMaxAge, negative
+	// disables caching
+	CacheTTL time.Duration
+
+	// hex sha256 of the expected configuration document, rejecting any
+	// other configuration if set
+	PinnedSHA256 string
+
+	// signature versions and algorithms the client is willing to use,
+	// default all the ones known to the client
+	Versions   []hash.SignatureVersion
+	Algorithms []hash.Algorithm
+}
+
+// WithAutoConfig fetches the auth configuration of the server while
+// creating the client, signing with the header names and the strongest
+// signature version and algorithm supported by both sides. Creating the
+// client fails if the configuration can not be fetched or no profile is
+// mutually supported. Signing options provided using WithSigningOptions
+// take precedence.
+func WithAutoConfig(cfg AutoConfig) Option {
+	return func(o *options) {
+		o.autoConfig = &cfg
+	}
+}
+
+// signature versions and algorithms in the order of preference
+var (
+	preferredVersions   = []hash.SignatureVersion{hash.SignatureV2, hash.SignatureV1}
+	preferredAlgorithms = []hash.Algorithm{hash.HMACSHA512, hash.HMACSHA3_256, hash.HMACBLAKE2b256, hash.HMACSHA256}
+)
+
+// cachedConfig is a configuration fetched for an endpoint.
+type cachedConfig struct {
+	cfg    *metadata.Configuration
+	digest string
+	expiry time.Time
+}
+
+var (
+	// configCacheMu guards the configuration cache
+	configCacheMu sync.Mutex
+
+	// configCache holds the configurations fetched by configuration URL
+	configCache = map[string]*cachedConfig{}
+)
+
+// configURL returns the URL of the configuration of the endpoint.
+func (a *AutoConfig) configURL(endpoint *url.URL) string {
+	path := a.Path
+	if path == "" {
+		path = metadata.WellKnownPath
+	}
+	u := url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host, Path: path}
+	return u.String()
+}
+
+// fetch returns the configuration of the endpoint, from the cache if not
+// expired.
+func (a *AutoConfig) fetch(ctx context.Context, hc *http.Client, endpoint *url.URL) (*metadata.Configuration, error) {
+	u := a.configURL(endpoint)
+	ttl := a.CacheTTL
+	if ttl == 0 {
+		ttl = metadata.DefaultMaxAge
+	}
+
+	configCacheMu.Lock()
+	entry, ok := configCache[u]
+	configCacheMu.Unlock()
+	if ok && time.Now().Before(entry.expiry) && a.checkPin(entry.digest) == nil {
+		return entry.cfg, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch auth configuration: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch auth configuration: status %d", resp.StatusCode)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auth configuration: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	digest := hex.EncodeToString(sum[:])
+	if err := a.checkPin(digest); err != nil {
+		return nil, err
+	}
+	cfg := &metadata.Configuration{}
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("invalid auth configuration: %w", err)
+	}
+
+	if ttl > 0 {
+		configCacheMu.Lock()
+		configCache[u] = &cachedConfig{cfg: cfg, digest: digest, expiry: time.Now().Add(ttl)}
+		configCacheMu.Unlock()
+	}
+	return cfg, nil
+}
+
+// checkPin ensures the digest of the configuration matches the pin.
+func (a *AutoConfig) checkPin(digest string) error {
+	if a.PinnedSHA256 == "" {
+		return nil
+	}
+	if subtle.ConstantTimeCompare([]byte(a.PinnedSHA256), []byte(digest)) != 1 {
+		return fmt.Errorf("auth configuration does not match the pinned sha256")
+	}
+	return nil
+}
+
+// signingOptions returns the signing options for the strongest profile
+// supported by both the client and the server configuration.
+func (a *AutoConfig) signingOptions(cfg *metadata.Configuration) ([]hash.Option, error) {
+	version, ok := pickPreferred(preferredVersions, a.Versions, cfg.SignatureVersions)
+	if !ok {
+		return nil, fmt.Errorf("no mutually supported signature version in %v", cfg.SignatureVersions)
+	}
+	alg, ok := pickPreferred(preferredAlgorithms, a.Algorithms, cfg.SignatureAlgorithms)
+	if !ok {
+		return nil, fmt.Errorf("no mutually supported signature algorithm in %v", cfg.SignatureAlgorithms)
+	}
+	opts := []hash.Option{
+		hash.WithHeaderNames(cfg.Headers.HeaderNames()),
+		hash.WithSignatureVersion(version),
+		hash.WithAlgorithm(alg),
+	}
+	if len(cfg.TimestampFormats) != 0 &&
+		!slices.Contains(cfg.TimestampFormats, metadata.TimestampRFC3339) &&
+		slices.Contains(cfg.TimestampFormats, metadata.TimestampEpoch) {
+		opts = append(opts, hash.WithEpochTimestamp())
+	}
+	return opts, nil
+}
+
+// pickPreferred returns the first preferred value allowed by the client,
+// all if none specified, and supported by the server.
+func pickPreferred[T ~string](preferred, allowed []T, supported []string) (T, bool) {
+	for _, v := range preferred {
+		if len(allowed) != 0 && !slices.Contains(allowed, v) {
+			continue
+		}
+		if slices.Contains(supported, string(v)) {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,67 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/metadata"
+)
+
+func TestClientAutoConfig(t *testing.T) {
+	cfg := metadata.DefaultConfiguration(60 * time.Second)
+	cfg.SignatureAlgorithms = []string{hash.HMACSHA256.String(), hash.HMACSHA3_256.String()}
+	cfg.Headers = metadata.HeadersFrom(hash.DefaultHeaderNames())
+	cfg.Headers.Signature = "x-acme-signature"
+
+	validator := hash.NewValidator(60, hash.WithHeaderNames(cfg.Headers.HeaderNames()))
+	mux := http.NewServeMux()
+	mux.Handle(metadata.WellKnownPath, metadata.NewHandler(cfg))
+	fetches := 0
+	mux.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-signature-alg") != hash.HMACSHA3_256.String() ||
+			r.Header.Get("x-signature-version") != string(hash.SignatureV2) {
+			t.Errorf("unexpected signing profile %s %s", r.Header.Get("x-signature-alg"), r.Header.Get("x-signature-version"))
+		}
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected valid signature: %s", err)
+		}
+	})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == metadata.WellKnownPath {
+			fetches++
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	for i := 0; i < 2; i++ {
+		cli, err := NewClient(srv.URL, "key", "secret", false, WithAutoConfig(AutoConfig{}))
+		if err != nil {
+			t.Fatalf("failed to create client: %s", err)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/books", nil)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+	}
+	if fetches != 1 {
+		t.Errorf("expected configuration to be fetched once, got %d", fetches)
+	}
+
+	_, err := NewClient(srv.URL, "key", "secret", false, WithAutoConfig(AutoConfig{PinnedSHA256: "00", CacheTTL: -1}))
+	if err == nil {
+		t.Errorf("expected pin mismatch to fail")
+	}
+	_, err = NewClient(srv.URL, "key", "secret", false, WithAutoConfig(AutoConfig{Algorithms: []hash.Algorithm{hash.HMACSHA512}}))
+	if err == nil {
+		t.Errorf("expected no mutually supported algorithm to fail")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by the Client, without sending the request,
+// while the circuit breaker considers the remote endpoint unhealthy.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerPolicy configures the circuit breaker of the Client. The
+// circuit opens after FailureThreshold consecutive failures, i.e. transport
+// errors or 5xx responses, failing requests fast for OpenDuration, after
+// which up to HalfOpenProbes requests are let through to probe the
+// endpoint, closing the circuit on success and opening it again on failure.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failures opening the circuit
+	FailureThreshold int
+
+	// OpenDuration is the time the circuit stays open before probing
+	OpenDuration time.Duration
+
+	// HalfOpenProbes is the number of concurrent probe requests allowed
+	// while the circuit is half open
+	HalfOpenProbes int
+}
+
+// DefaultCircuitBreakerPolicy returns the recommended circuit breaker
+// policy: opening after 5 consecutive failures for 30s, with a single
+// probe request.
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+		HalfOpenProbes:   1,
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker with the given policy.
+func WithCircuitBreaker(policy CircuitBreakerPolicy) Option {
+	return func(o *options) {
+		o.breaker = newBreaker(policy)
+	}
+}
+
+// circuitState is the state of the circuit breaker
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// breaker implements the circuit breaker state machine
+type breaker struct {
+	mu       sync.Mutex
+	policy   CircuitBreakerPolicy
+	state    circuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // time the circuit was opened
+	probes   int       // probes in flight while half open
+	now      func() time.Time
+}
+
+// newBreaker creates a closed circuit breaker.
+func newBreaker(policy CircuitBreakerPolicy) *breaker {
+	policy.FailureThreshold = max(policy.FailureThreshold, 1)
+	policy.HalfOpenProbes = max(policy.HalfOpenProbes, 1)
+	return &breaker{
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// allow reports whether a request may be sent, returning ErrCircuitOpen
+// otherwise. Every allowed request must be followed by a call to done.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == circuitOpen {
+		if b.now().Sub(b.openedAt) < b.policy.OpenDuration {
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		b.probes = 0
+	}
+	if b.state == circuitHalfOpen {
+		if b.probes >= b.policy.HalfOpenProbes {
+			return ErrCircuitOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// done records the outcome of an allowed request.
+func (b *breaker) done(resp *http.Response, err error) {
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+	canceled := errors.Is(err, context.Canceled)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitHalfOpen:
+		b.probes--
+		if canceled {
+			return
+		}
+		if failed {
+			b.open()
+			return
+		}
+		b.state = circuitClosed
+		b.failures = 0
+	case circuitClosed:
+		if canceled {
+			return
+		}
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+// open moves the circuit to the open state, must be called with the lock
+// held.
+func (b *breaker) open() {
+	b.state = circuitOpen
+	b.openedAt = b.now()
+	b.failures = 0
+}
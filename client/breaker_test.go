@@ -0,0 +1,43 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBreakerStates(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(CircuitBreakerPolicy{FailureThreshold: 2, OpenDuration: time.Second})
+	b.now = func() time.Time { return now }
+
+	failure := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	success := &http.Response{StatusCode: http.StatusNotFound}
+	for i := 0; i < 2; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("expected closed circuit, got %s", err)
+		}
+		b.done(failure, nil)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit after threshold, got %v", err)
+	}
+
+	// half open, a single probe is allowed
+	now = now.Add(2 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected probe to be allowed, got %s", err)
+	}
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected concurrent probe to be rejected, got %v", err)
+	}
+	b.done(success, nil)
+	if err := b.allow(); err != nil {
+		t.Errorf("expected closed circuit after successful probe, got %s", err)
+	}
+	b.done(success, nil)
+}
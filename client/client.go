@@ -4,12 +4,17 @@
 package client
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
+
+	"github.com/coder/websocket"
 
 	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/telemetry"
 )
 
 /*
@@ -29,12 +34,14 @@ This client ensures:
     )
 
     func main() {
-        cli, err := client.NewClient("https://api.example.com", "api-key-id", "supersecret", false)
+        cli, err := client.New("https://api.example.com", client.WithCredentials("api-key-id", "supersecret"))
         if err != nil {
             panic(err)
         }
         req, _ := http.NewRequest("GET", "/resource", nil)
-        resp, err := cli.Do(req)
+        ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        resp, err := cli.DoWithContext(ctx, req)
         if err != nil {
             panic(err)
         }
@@ -46,50 +53,219 @@ This client ensures:
 
 - Client interface
   - Do(*http.Request) (*http.Response, error): Sends a signed HTTP request.
+  - DoWithContext(context.Context, *http.Request) (*http.Response, error):
+    Sends a signed HTTP request bound to the given context.
+  - GetJSON, PostJSON, PutJSON, DeleteJSON: Send signed JSON requests for a
+    path, decoding the response and returning *StatusError for non 2xx.
+  - DryRun(*http.Request) (*PreparedRequest, error): Returns the signed
+    request, resolved URL and canonical string without sending it.
+  - DialWebSocket(ctx, path) (*websocket.Conn, *http.Response, error):
+    Establishes a WebSocket connection with a signed upgrade request.
 
-- NewClient(endpoint, apiKey, secret string, allowInsecure bool) (Client, error)
+- New(endpoint string, opts ...Option) (Client, error)
   - endpoint:      Base API endpoint (scheme + host + optional path)
-  - apiKey:        API key identifier
-  - secret:        Secret key for HMAC signing
-  - allowInsecure: If true, disables TLS certificate verification (for testing)
+  - opts:          Credentials and optional configuration, e.g.
+    WithCredentials(apiKey, secret), WithInsecureSkipVerify() (for testing)
+
+- WithCredentialsProvider(creds hash.CredentialsProvider) Option
+  - Fetches the credentials from the provider for every request to support
+    runtime secret rotation
+
+- NewClient(endpoint, apiKey, secret string, allowInsecure bool, opts ...Option) (Client, error)
+  - Deprecated positional form of New, as is NewClientWithProvider
+
+- WithRetry(policy RetryPolicy) Option
+  - Retries failed requests with exponential backoff, re-signing every
+    attempt with a fresh timestamp, see DefaultRetryPolicy()
+
+- WithRateLimit(rps float64, burst int) Option
+  - Throttles outgoing requests using a token bucket
+
+- WithCircuitBreaker(policy CircuitBreakerPolicy) Option
+  - Fails fast with ErrCircuitOpen while the endpoint is unhealthy
+
+- WithTracer(tracer telemetry.Tracer) Option
+  - Creates a span around every request, see telemetry/otel
+
+- WithMetrics(metrics telemetry.Metrics) Option
+  - Counts the requests by status code and records their latency, see
+    telemetry/prometheus
+
+- WithHooks(hooks Hooks) Option
+  - Registers OnRequest, OnResponse and OnError callbacks invoked around
+    every attempt of a request
+
+- WithMaxRedirects(n int), WithoutRedirects(), WithCrossHostRedirects(policy) Option
+  - Caps or disables the redirects followed, and refuses the redirects
+    leaving the endpoint instead of stripping their authentication headers
+
+- WithAutoConfig(cfg AutoConfig) Option
+  - Fetches the auth configuration of the server at startup, signing with
+    the strongest mutually supported profile, see package metadata
 */
 
 type Client interface {
 	// Do sends the HTTP request after signing it with authentication headers.
+	// The request is bound to its own context.
 	Do(*http.Request) (*http.Response, error)
+
+	// DoWithContext sends the HTTP request after signing it with
+	// authentication headers, the request is bound to the given context.
+	DoWithContext(context.Context, *http.Request) (*http.Response, error)
+
+	// GetJSON sends a GET request for the path relative to the endpoint
+	// and decodes the JSON response into out.
+	GetJSON(ctx context.Context, path string, out any) error
+
+	// PostJSON sends in as JSON body of a POST request for the path and
+	// decodes the JSON response into out.
+	PostJSON(ctx context.Context, path string, in, out any) error
+
+	// PutJSON sends in as JSON body of a PUT request for the path and
+	// decodes the JSON response into out.
+	PutJSON(ctx context.Context, path string, in, out any) error
+
+	// DeleteJSON sends a DELETE request for the path and decodes the JSON
+	// response into out.
+	DeleteJSON(ctx context.Context, path string, out any) error
+
+	// DryRun prepares and signs the request exactly as it would be sent,
+	// without sending it.
+	DryRun(req *http.Request) (*PreparedRequest, error)
+
+	// DialWebSocket establishes a WebSocket connection with the path,
+	// signing the upgrade request.
+	DialWebSocket(ctx context.Context, path string) (*websocket.Conn, *http.Response, error)
 }
 
 // client is a concrete implementation of the Client interface.
 // It holds configuration for endpoint, credentials, and HTTP client.
 type client struct {
-	endpoint   string         // Base API endpoint
-	apiKey     string         // API key identifier
-	secret     string         // Secret key for HMAC signing
-	url        *url.URL       // Parsed endpoint URL
-	hClient    *http.Client   // Underlying HTTP client
-	hGenerator hash.Generator // HMAC header generator
+	endpoint    string                   // Base API endpoint
+	creds       hash.CredentialsProvider // Provider of the API key identifier and secret
+	url         *url.URL                 // Parsed endpoint URL
+	hClient     *http.Client             // Underlying HTTP client
+	hGenerator  hash.Generator           // HMAC header generator
+	authHeaders []string                 // names of the authentication headers
+	opts        *options                 // Optional configuration
 }
 
 // Do signs the HTTP request with authentication headers and sends it.
 // It enforces the configured endpoint, preventing endpoint manipulation.
 //
 // Steps:
-//  1. Overwrites the request's scheme, host, and path with the configured endpoint.
+//  1. Overwrites the request's scheme and host with the configured endpoint,
+//     and prefixes the request path with the base path of the endpoint.
 //  2. Signs the request using the HMAC generator.
 //  3. Sends the request using the underlying HTTP client.
 //
 // Returns the HTTP response or an error.
 func (c *client) Do(req *http.Request) (*http.Response, error) {
+	return c.DoWithContext(req.Context(), req)
+}
+
+// DoWithContext signs the HTTP request with authentication headers and sends
+// it, bound to the given context, so that canceling the context or reaching
+// its deadline aborts the request.
+func (c *client) DoWithContext(ctx context.Context, req *http.Request) (*http.Response, error) {
 	if c.url == nil {
 		return nil, fmt.Errorf("Client not initialized")
 	}
-	// Ensure the request uses the configured endpoint, not what the caller set.
-	req.URL.Scheme = c.url.Scheme
-	req.URL.Host = c.url.Host
-	//req.URL.Path = c.url.Path
+	if ctx == nil {
+		return nil, fmt.Errorf("nil context")
+	}
+	req = c.prepare(ctx, req)
+
+	if c.opts.metrics != nil {
+		return c.doWithMetrics(ctx, req)
+	}
+	return c.doObserved(ctx, req)
+}
+
+// doObserved sends the request, within a span if tracing is enabled.
+func (c *client) doObserved(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.opts.tracer != nil {
+		return c.doWithTracing(ctx, req)
+	}
+	return c.do(ctx, req)
+}
+
+// doWithMetrics sends the request recording its status code and latency,
+// including the retries.
+func (c *client) doWithMetrics(ctx context.Context, req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := c.doObserved(ctx, req)
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	c.opts.metrics.RecordClientRequest(ctx, req.Method, status, time.Since(start))
+	return resp, err
+}
+
+// prepare binds the request to the context and resolves its URL against
+// the configured endpoint.
+func (c *client) prepare(ctx context.Context, req *http.Request) *http.Request {
+	req = req.WithContext(ctx)
+
+	// Ensure the request uses the configured endpoint, not what the caller
+	// set, working on a copy of the URL to leave the caller's request as is.
+	u := *req.URL
+	u.Scheme = c.url.Scheme
+	u.Host = c.url.Host
+	joinBasePath(c.url, &u)
+	req.URL = &u
+	return req
+}
+
+// do sends the request retrying as per the retry policy if configured.
+func (c *client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.opts.retry != nil {
+		return c.doWithRetry(ctx, req)
+	}
 
 	// Add authentication headers and send the request.
-	return c.hClient.Do(c.hGenerator.AddAuthHeaders(req))
+	return c.send(req)
+}
+
+// doWithTracing sends the request within a span.
+func (c *client) doWithTracing(ctx context.Context, req *http.Request) (*http.Response, error) {
+	ctx, span := c.opts.tracer.Start(ctx, "auth.client.Do",
+		telemetry.String("http.request.method", req.Method),
+		telemetry.String("server.address", c.url.Host),
+		telemetry.String("url.path", req.URL.Path),
+	)
+	defer span.End()
+
+	resp, err := c.do(ctx, req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(telemetry.Int("http.response.status_code", resp.StatusCode))
+	return resp, nil
+}
+
+// joinBasePath prefixes the path of the request URL with the base path of
+// the endpoint, e.g. endpoint "https://gw.example.com/api/v2" and request
+// path "/books" result in "/api/v2/books". Joining is performed on the
+// escaped form of both paths so that escaped characters such as %2F are
+// preserved, and a trailing slash of the request path is retained.
+func joinBasePath(base, u *url.URL) {
+	basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+	if basePath == "" {
+		return
+	}
+	joined := basePath + "/" + strings.TrimPrefix(u.EscapedPath(), "/")
+	if u.Path == "" && u.RawPath == "" {
+		joined = base.EscapedPath()
+	}
+	p, err := url.PathUnescape(joined)
+	if err != nil {
+		return
+	}
+	u.Path = p
+	u.RawPath = joined
 }
 
 // NewClient creates a new HMAC-authenticated HTTP client.
@@ -99,31 +275,87 @@ func (c *client) Do(req *http.Request) (*http.Response, error) {
 //   - apiKey:        API key identifier
 //   - secret:        Secret key for HMAC signing
 //   - allowInsecure: If true, disables TLS certificate verification (for testing)
+//   - opts:          Optional configuration such as timeouts
 //
 // Returns:
 //   - Client: Secure HTTP client that signs all requests
 //   - error:  If endpoint is invalid
-func NewClient(endpoint, apiKey, secret string, allowInsecure bool) (Client, error) {
+//
+// Deprecated: use New with WithCredentials and WithInsecureSkipVerify.
+func NewClient(endpoint, apiKey, secret string, allowInsecure bool, opts ...Option) (Client, error) {
+	return NewClientWithProvider(endpoint, hash.StaticCredentials(apiKey, secret), allowInsecure, opts...)
+}
+
+// NewClientWithProvider creates a new HMAC-authenticated HTTP client, which
+// fetches the API key identifier and secret from the provider for every
+// request, allowing the credentials to be rotated without recreating the
+// client.
+//
+// Parameters:
+//   - endpoint:      Base API endpoint (e.g., "https://api.example.com")
+//   - creds:         Provider of the API key identifier and secret
+//   - allowInsecure: If true, disables TLS certificate verification (for testing)
+//   - opts:          Optional configuration such as timeouts
+//
+// Deprecated: use New with WithCredentialsProvider and
+// WithInsecureSkipVerify.
+func NewClientWithProvider(endpoint string, creds hash.CredentialsProvider, allowInsecure bool, opts ...Option) (Client, error) {
+	if creds == nil {
+		return nil, fmt.Errorf("credentials provider not specified")
+	}
+	base := []Option{WithCredentialsProvider(creds)}
+	if allowInsecure {
+		base = append(base, WithInsecureSkipVerify())
+	}
+	return New(endpoint, append(base, opts...)...)
+}
+
+// New creates a new HMAC-authenticated HTTP client for the endpoint, e.g.
+// "https://api.example.com", signing the requests with the credentials of
+// WithCredentials or WithCredentialsProvider, which are required.
+func New(endpoint string, opts ...Option) (Client, error) {
+	o := newOptions(opts...)
+	creds := o.creds
+	if creds == nil {
+		return nil, fmt.Errorf("credentials provider not specified")
+	}
 	uri, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
-	var hClient *http.Client
-	if allowInsecure {
-		hClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
+	if err := o.checkGuards(uri, creds, o.insecure); err != nil {
+		return nil, err
+	}
+
+	transport := o.sharedTransport(uri)
+	c := &client{
+		endpoint: endpoint,
+		creds:    creds,
+		url:      uri,
+		opts:     o,
+	}
+	c.hClient = &http.Client{
+		Transport:     transport,
+		Timeout:       o.timeout,
+		CheckRedirect: c.checkRedirect,
+	}
+
+	signing := o.signing
+	if o.autoConfig != nil {
+		cfg, err := o.autoConfig.fetch(context.Background(), c.hClient, uri)
+		if err != nil {
+			return nil, err
+		}
+		auto, err := o.autoConfig.signingOptions(cfg)
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		hClient = &http.Client{}
-	}
-	return &client{
-		endpoint:   endpoint,
-		apiKey:     apiKey,
-		secret:     secret,
-		url:        uri,
-		hClient:    hClient,
-		hGenerator: hash.NewGenerator(apiKey, secret),
-	}, nil
+		signing = append(auto, o.signing...)
+	}
+	c.hGenerator = hash.NewGeneratorWithProvider(creds, signing...)
+	if o.sigV4 != nil {
+		c.hGenerator = hash.NewSigV4Generator(o.sigV4.region, o.sigV4.service, creds, signing...)
+	}
+	c.authHeaders = authHeaders(o, signing)
+	return c, nil
 }
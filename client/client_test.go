@@ -0,0 +1,147 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func Test_JoinBasePath(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		path     string
+		expected string
+	}{
+		{"https://gw.example.com", "/books", "/books"},
+		{"https://gw.example.com/", "/books", "/books"},
+		{"https://gw.example.com/api/v2", "/books", "/api/v2/books"},
+		{"https://gw.example.com/api/v2/", "/books", "/api/v2/books"},
+		{"https://gw.example.com/api/v2", "books", "/api/v2/books"},
+		{"https://gw.example.com/api/v2", "/books/", "/api/v2/books/"},
+		{"https://gw.example.com/api/v2/", "", "/api/v2/"},
+		{"https://gw.example.com/api/v2", "/books/a%2Fb", "/api/v2/books/a%2Fb"},
+		{"https://gw.example.com/api%20v2", "/books", "/api%20v2/books"},
+	}
+	for _, test := range tests {
+		base, _ := url.Parse(test.endpoint)
+		u, _ := url.Parse(test.path)
+		joinBasePath(base, u)
+		if u.EscapedPath() != test.expected {
+			t.Errorf("endpoint %s, path %s: expected %s, got %s", test.endpoint, test.path, test.expected, u.EscapedPath())
+		}
+	}
+}
+
+func TestClientBasePathSigning(t *testing.T) {
+	validator := hash.NewValidator(60)
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.EscapedPath()
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected signature over the final path to be valid: %s", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli, _ := NewClient(srv.URL+"/api/v2/", "key", "secret", false)
+	req, _ := http.NewRequest(http.MethodGet, "/books/a%2Fb", nil)
+	for i := 0; i < 2; i++ {
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+		if gotPath != "/api/v2/books/a%2Fb" {
+			t.Errorf("expected path /api/v2/books/a%%2Fb, got %s", gotPath)
+		}
+	}
+	if req.URL.EscapedPath() != "/books/a%2Fb" {
+		t.Errorf("expected the caller's request to be left unmodified, got %s", req.URL.EscapedPath())
+	}
+}
+
+func TestClientRedirectResigned(t *testing.T) {
+	validator := hash.NewValidator(60)
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected %s to be signed: %s", r.URL.Path, err)
+		}
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer srv.Close()
+
+	cli, _ := NewClient(srv.URL, "key", "secret", false, WithSigningOptions(hash.WithSignatureVersion(hash.SignatureV2)))
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "/old", strings.NewReader("payload"))
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if gotBody != "payload" {
+		t.Errorf("expected redirected body payload, got %q", gotBody)
+	}
+}
+
+func TestNew(t *testing.T) {
+	validator := hash.NewValidator(60)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected request signed with the configured credentials: %s", err)
+		}
+	}))
+	defer srv.Close()
+
+	if _, err := New(srv.URL); err == nil {
+		t.Errorf("expected credentials to be required")
+	}
+	cli, err := New(srv.URL, WithCredentials("key", "secret"), WithInsecureSkipVerify())
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "/books", nil)
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+}
+
+func TestClientSigV4(t *testing.T) {
+	var auth, date string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, date = r.Header.Get("Authorization"), r.Header.Get("X-Amz-Date")
+		if r.Header.Get("x-signature") != "" {
+			t.Errorf("expected no HMAC headers with SigV4")
+		}
+	}))
+	defer srv.Close()
+
+	cli, err := New(srv.URL, WithCredentials("AKIDEXAMPLE", "secret"), WithSigV4("us-east-1", "execute-api"))
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "/books", nil)
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(auth, "/us-east-1/execute-api/aws4_request") || date == "" {
+		t.Errorf("unexpected SigV4 headers %q %q", auth, date)
+	}
+}
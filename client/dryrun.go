@@ -0,0 +1,54 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// PreparedRequest is the outcome of a DryRun, describing the request
+// exactly as it would be sent by the Client.
+type PreparedRequest struct {
+	// Request is the signed request, ready to be sent
+	Request *http.Request
+
+	// URL is the resolved URL of the request
+	URL string
+
+	// Headers are the headers of the signed request
+	Headers http.Header
+
+	// CanonicalString is the string that was signed
+	CanonicalString string
+}
+
+// DryRun prepares and signs the request, resolving its URL against the
+// endpoint, without sending it, which is useful for test assertions and
+// for generating examples in API documentation. The hooks, rate limiter
+// and circuit breaker of the client are not involved.
+func (c *client) DryRun(req *http.Request) (*PreparedRequest, error) {
+	if c.url == nil {
+		return nil, fmt.Errorf("Client not initialized")
+	}
+	r := c.prepare(req.Context(), req)
+	r, err := newAttempt(r.Context(), r)
+	if err != nil {
+		return nil, err
+	}
+	r = c.hGenerator.AddAuthHeaders(r)
+
+	canonical, err := hash.CanonicalString("", r, c.opts.signing...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign request: %s", err)
+	}
+	return &PreparedRequest{
+		Request:         r,
+		URL:             r.URL.String(),
+		Headers:         r.Header.Clone(),
+		CanonicalString: canonical,
+	}, nil
+}
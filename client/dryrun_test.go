@@ -0,0 +1,35 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestDryRun(t *testing.T) {
+	cli, _ := NewClient("https://gw.example.com/api/v2", "key", "secret", false)
+	req, _ := http.NewRequest(http.MethodGet, "/books?page=2", nil)
+
+	prepared, err := cli.DryRun(req)
+	if err != nil {
+		t.Fatalf("dry run failed: %s", err)
+	}
+	if prepared.URL != "https://gw.example.com/api/v2/books?page=2" {
+		t.Errorf("unexpected resolved url %s", prepared.URL)
+	}
+	timestamp := prepared.Headers.Get("x-timestamp")
+	if prepared.CanonicalString != strings.Join([]string{"GET", "/api/v2/books", timestamp}, "\n") {
+		t.Errorf("unexpected canonical string %q", prepared.CanonicalString)
+	}
+	if prepared.Headers.Get("x-signature") != hash.GenerateSHA256HMAC("secret", "GET", "/api/v2/books", timestamp) {
+		t.Errorf("signature does not match the canonical string")
+	}
+	if req.Header.Get("x-signature") != "" {
+		t.Errorf("expected the caller's request to be left unmodified")
+	}
+}
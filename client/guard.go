@@ -0,0 +1,85 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strconv"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/ipaddr"
+)
+
+// DevModeEnv is the environment variable marking the process as running in
+// dev mode, e.g. AUTH_DEV_MODE=true, relaxing the transport guards.
+const DevModeEnv = "AUTH_DEV_MODE"
+
+// WithGuards enables the guards refusing to create a client for an obviously
+// unsafe configuration, failing with a *hash.UnsafeConfigError when:
+//   - the credentials do not pass hash.CheckCredentials
+//   - allowInsecure is set outside dev mode (hash.ErrInsecureTLS)
+//   - the endpoint is plaintext http outside dev mode, unless it is a
+//     loopback address (hash.ErrPlaintextEndpoint)
+//
+// Credentials of a provider are checked with their value at construction.
+func WithGuards() Option {
+	return func(o *options) {
+		o.guards = true
+	}
+}
+
+// WithDevMode marks the client as used in development, allowing
+// allowInsecure and plaintext endpoints with the guards enabled, as does
+// setting DevModeEnv.
+func WithDevMode() Option {
+	return func(o *options) {
+		o.devMode = true
+	}
+}
+
+// isDevMode reports whether the client is explicitly marked for development.
+func (o *options) isDevMode() bool {
+	if o.devMode {
+		return true
+	}
+	dev, _ := strconv.ParseBool(os.Getenv(DevModeEnv))
+	return dev
+}
+
+// isLoopback reports whether the host of the endpoint is a loopback address,
+// where plaintext traffic never leaves the machine.
+func isLoopback(uri *url.URL) bool {
+	host := uri.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	addr, err := ipaddr.ParseAddr(host)
+	return err == nil && addr.IsLoopback()
+}
+
+// checkGuards runs the guards enabled by WithGuards on the configuration.
+func (o *options) checkGuards(uri *url.URL, creds hash.CredentialsProvider, allowInsecure bool) error {
+	if !o.guards {
+		return nil
+	}
+	id, secret, err := creds.Current(context.Background())
+	if err != nil {
+		return err
+	}
+	if err := hash.CheckCredentials(id, secret); err != nil {
+		return err
+	}
+	if o.isDevMode() {
+		return nil
+	}
+	if allowInsecure {
+		return &hash.UnsafeConfigError{Err: hash.ErrInsecureTLS, Detail: uri.Host}
+	}
+	if uri.Scheme == "http" && !isLoopback(uri) {
+		return &hash.UnsafeConfigError{Err: hash.ErrPlaintextEndpoint, Detail: uri.Host}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestGuards(t *testing.T) {
+	t.Setenv(DevModeEnv, "")
+	cases := []struct {
+		name          string
+		endpoint      string
+		secret        string
+		allowInsecure bool
+		opts          []Option
+		want          error
+	}{
+		{"safe", "https://api.example.com", "s3cr3t-value", false, nil, nil},
+		{"empty secret", "https://api.example.com", "", false, nil, hash.ErrEmptySecret},
+		{"secret is key id", "https://api.example.com", "key", false, nil, hash.ErrSecretIsKeyId},
+		{"insecure tls", "https://api.example.com", "s3cr3t-value", true, nil, hash.ErrInsecureTLS},
+		{"plaintext", "http://api.example.com", "s3cr3t-value", false, nil, hash.ErrPlaintextEndpoint},
+		{"plaintext loopback", "http://127.0.0.1:8080", "s3cr3t-value", false, nil, nil},
+		{"dev mode", "http://api.example.com", "s3cr3t-value", true, []Option{WithDevMode()}, nil},
+		{"dev mode empty secret", "http://api.example.com", "", false, []Option{WithDevMode()}, hash.ErrEmptySecret},
+	}
+	for _, c := range cases {
+		opts := append([]Option{WithGuards()}, c.opts...)
+		_, err := NewClient(c.endpoint, "key", c.secret, c.allowInsecure, opts...)
+		if c.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", c.name, err)
+			}
+		} else if !errors.Is(err, c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestGuardsDevModeEnv(t *testing.T) {
+	t.Setenv(DevModeEnv, "true")
+	if _, err := NewClient("http://api.example.com", "key", "s3cr3t-value", true, WithGuards()); err != nil {
+		t.Errorf("unexpected error in dev mode: %s", err)
+	}
+}
+
+func TestGuardsDisabled(t *testing.T) {
+	if _, err := NewClient("http://api.example.com", "key", "", true); err != nil {
+		t.Errorf("guards are opt-in, got %s", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+)
+
+// Hooks are callbacks invoked by the Client around every attempt of a
+// request, allowing logging, header injection, request ID propagation, and
+// response auditing without wrapping the client. Any of the callbacks may
+// be nil.
+type Hooks struct {
+	// OnRequest is invoked before the request is signed and sent, it may
+	// modify the request headers, an error aborts the request
+	OnRequest func(req *http.Request) error
+
+	// OnResponse is invoked when a response is received
+	OnResponse func(req *http.Request, resp *http.Response)
+
+	// OnError is invoked when the request fails without a response
+	OnError func(req *http.Request, err error)
+}
+
+// WithHooks registers request/response hooks with the Client, hooks are
+// invoked in the order of registration.
+func WithHooks(hooks Hooks) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hooks)
+	}
+}
+
+// send sends a single attempt of the request, guarded by the circuit
+// breaker if configured.
+func (c *client) send(req *http.Request) (*http.Response, error) {
+	if c.opts.breaker != nil {
+		if err := c.opts.breaker.allow(); err != nil {
+			c.onError(req, err)
+			return nil, err
+		}
+	}
+	resp, err := c.sendAttempt(req)
+	if c.opts.breaker != nil {
+		c.opts.breaker.done(resp, err)
+	}
+	return resp, err
+}
+
+// sendAttempt waits for the rate limiter if configured, and runs the hooks
+// around signing and sending the request.
+func (c *client) sendAttempt(req *http.Request) (*http.Response, error) {
+	if c.opts.limiter != nil {
+		if err := c.opts.limiter.Wait(req.Context()); err != nil {
+			c.onError(req, err)
+			return nil, err
+		}
+	}
+	for _, h := range c.opts.hooks {
+		if h.OnRequest == nil {
+			continue
+		}
+		if err := h.OnRequest(req); err != nil {
+			c.onError(req, err)
+			return nil, err
+		}
+	}
+
+	resp, err := c.hClient.Do(c.hGenerator.AddAuthHeaders(req))
+	if err != nil {
+		c.onError(req, err)
+		return nil, err
+	}
+	for _, h := range c.opts.hooks {
+		if h.OnResponse != nil {
+			h.OnResponse(req, resp)
+		}
+	}
+	return resp, nil
+}
+
+// onError invokes the OnError hooks.
+func (c *client) onError(req *http.Request, err error) {
+	for _, h := range c.opts.hooks {
+		if h.OnError != nil {
+			h.OnError(req, err)
+		}
+	}
+}
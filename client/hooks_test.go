@@ -0,0 +1,53 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-request-id", r.Header.Get("x-request-id"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var responses, failures int
+	cli, _ := NewClient(srv.URL, "key", "secret", false, WithHooks(Hooks{
+		OnRequest: func(req *http.Request) error {
+			if req.URL.Path == "/blocked" {
+				return fmt.Errorf("blocked")
+			}
+			req.Header.Set("x-request-id", "req-1")
+			return nil
+		},
+		OnResponse: func(req *http.Request, resp *http.Response) {
+			if resp.Header.Get("x-request-id") == "req-1" {
+				responses++
+			}
+		},
+		OnError: func(req *http.Request, err error) {
+			failures++
+		},
+	}))
+
+	req, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+
+	req, _ = http.NewRequest(http.MethodGet, "/blocked", nil)
+	if _, err := cli.Do(req); err == nil {
+		t.Errorf("expected request to be aborted by the hook")
+	}
+	if responses != 1 || failures != 1 {
+		t.Errorf("expected 1 response and 1 failure, got %d and %d", responses, failures)
+	}
+}
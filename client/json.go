@@ -0,0 +1,116 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBodySize limits the size of the response body captured in a
+// StatusError
+const maxErrorBodySize = 64 * 1024
+
+// StatusError is returned by the JSON helpers of the Client when the server
+// responds with a non 2xx status code.
+type StatusError struct {
+	// StatusCode of the response
+	StatusCode int
+
+	// Status text of the response, e.g. "404 Not Found"
+	Status string
+
+	// Body of the response, truncated to 64KB
+	Body []byte
+}
+
+// Error returns the status along with the response body if any.
+func (e *StatusError) Error() string {
+	if len(e.Body) == 0 {
+		return fmt.Sprintf("unexpected response status: %s", e.Status)
+	}
+	return fmt.Sprintf("unexpected response status: %s: %s", e.Status, bytes.TrimSpace(e.Body))
+}
+
+// IsStatusError reports whether the error is a StatusError with the given
+// status code.
+func IsStatusError(err error, code int) bool {
+	var se *StatusError
+	return errors.As(err, &se) && se.StatusCode == code
+}
+
+// GetJSON sends a signed GET request for the path and decodes the JSON
+// response into out.
+func (c *client) GetJSON(ctx context.Context, path string, out any) error {
+	return c.doJSON(ctx, http.MethodGet, path, nil, out)
+}
+
+// PostJSON sends in as JSON body of a signed POST request for the path and
+// decodes the JSON response into out.
+func (c *client) PostJSON(ctx context.Context, path string, in, out any) error {
+	return c.doJSON(ctx, http.MethodPost, path, in, out)
+}
+
+// PutJSON sends in as JSON body of a signed PUT request for the path and
+// decodes the JSON response into out.
+func (c *client) PutJSON(ctx context.Context, path string, in, out any) error {
+	return c.doJSON(ctx, http.MethodPut, path, in, out)
+}
+
+// DeleteJSON sends a signed DELETE request for the path and decodes the
+// JSON response into out.
+func (c *client) DeleteJSON(ctx context.Context, path string, out any) error {
+	return c.doJSON(ctx, http.MethodDelete, path, nil, out)
+}
+
+// doJSON builds the request against the configured endpoint, encoding in as
+// the JSON body if not nil, sends it and decodes the response into out if
+// not nil. Non 2xx responses are returned as StatusError.
+func (c *client) doJSON(ctx context.Context, method, path string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request body: %s", err)
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.DoWithContext(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       b,
+		}
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("failed to decode response body: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testItem struct {
+	Name string `json:"name"`
+}
+
+func TestJSONHelpers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-signature") == "" {
+			t.Errorf("expected signed request")
+		}
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Path == "/missing" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(testItem{Name: "item"})
+		case http.MethodPost:
+			var in testItem
+			_ = json.NewDecoder(r.Body).Decode(&in)
+			_ = json.NewEncoder(w).Encode(testItem{Name: in.Name + "-created"})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	cli, _ := NewClient(srv.URL, "key", "secret", false)
+	ctx := context.Background()
+
+	var out testItem
+	if err := cli.GetJSON(ctx, "/items/1", &out); err != nil || out.Name != "item" {
+		t.Errorf("unexpected GetJSON result %+v: %v", out, err)
+	}
+	if err := cli.PostJSON(ctx, "/items", testItem{Name: "new"}, &out); err != nil || out.Name != "new-created" {
+		t.Errorf("unexpected PostJSON result %+v: %v", out, err)
+	}
+	if err := cli.DeleteJSON(ctx, "/items/1", &out); err != nil {
+		t.Errorf("unexpected DeleteJSON error: %s", err)
+	}
+	err := cli.GetJSON(ctx, "/missing", &out)
+	if !IsStatusError(err, http.StatusNotFound) {
+		t.Errorf("expected StatusError with 404, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// Timeout defaults applied by NewClient unless configured otherwise.
+const (
+	// DefaultTimeout is the overall timeout of a request, including
+	// connection, redirects and reading the response body.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultDialTimeout is the timeout for establishing a connection.
+	DefaultDialTimeout = 10 * time.Second
+
+	// DefaultTLSHandshakeTimeout is the timeout for the TLS handshake.
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// options holds the optional configuration of a Client.
+type options struct {
+	timeout             time.Duration            // overall request timeout, 0 for none
+	dialTimeout         time.Duration            // connection establishment timeout
+	tlsHandshakeTimeout time.Duration            // TLS handshake timeout
+	retry               *RetryPolicy             // retry policy, nil disables retries
+	hooks               []Hooks                  // request/response hooks
+	limiter             *rateLimiter             // outgoing request rate limiter
+	breaker             *breaker                 // circuit breaker for the endpoint
+	tracer              telemetry.Tracer         // tracer creating spans around requests
+	metrics             telemetry.Metrics        // metrics of the requests sent
+	signing             []hash.Option            // options of the request signing Generator
+	autoConfig          *AutoConfig              // auth configuration fetched at startup
+	guards              bool                     // refuse unsafe configurations
+	creds               hash.CredentialsProvider // credentials signing the requests
+	insecure            bool                     // skip TLS certificate verification
+	devMode             bool                     // explicitly marked for development
+	sigV4               *sigV4                   // sign with AWS SigV4 instead of HMAC
+	maxIdleConnsPerHost int                      // idle connections kept per host
+	maxConnsPerHost     int                      // connections per host, 0 for no limit
+	idleConnTimeout     time.Duration            // lifetime of an idle connection
+	disableHTTP2        bool                     // disable HTTP/2 for TLS endpoints
+	maxRedirects        int                      // redirects followed, 0 for none
+	crossHost           CrossHostPolicy          // redirects leaving the endpoint
+}
+
+// Option configures a Client created using NewClient.
+type Option func(*options)
+
+// newOptions returns the default options updated with the provided ones.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		timeout:             DefaultTimeout,
+		dialTimeout:         DefaultDialTimeout,
+		tlsHandshakeTimeout: DefaultTLSHandshakeTimeout,
+		maxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		idleConnTimeout:     DefaultIdleConnTimeout,
+		maxRedirects:        DefaultMaxRedirects,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithTimeout sets the overall timeout of a request, including connection,
+// redirects and reading the response body. A zero timeout means no timeout,
+// in which case only the request context bounds the request.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithDialTimeout sets the timeout for establishing a connection.
+func WithDialTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.dialTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout sets the timeout for the TLS handshake.
+func WithTLSHandshakeTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.tlsHandshakeTimeout = timeout
+	}
+}
+
+// WithTracer enables tracing of the requests sent by the Client, with spans
+// annotated with the method, endpoint, path and response status code.
+func WithTracer(tracer telemetry.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithMetrics enables counting the requests sent by the Client by the
+// status code of their response, along with their latency, e.g. with the
+// Prometheus collectors of the telemetry/prometheus package.
+func WithMetrics(metrics telemetry.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
+
+// WithCredentials signs the requests with the API key identifier and
+// secret.
+func WithCredentials(apiKey, secret string) Option {
+	return func(o *options) {
+		o.creds = hash.StaticCredentials(apiKey, secret)
+	}
+}
+
+// WithCredentialsProvider signs the requests with the credentials fetched
+// from the provider for every request, allowing runtime secret rotation.
+func WithCredentialsProvider(creds hash.CredentialsProvider) Option {
+	return func(o *options) {
+		o.creds = creds
+	}
+}
+
+// WithInsecureSkipVerify disables the TLS certificate verification of the
+// endpoint, for testing only.
+func WithInsecureSkipVerify() Option {
+	return func(o *options) {
+		o.insecure = true
+	}
+}
+
+// WithSigningOptions configures the Generator signing the requests, e.g.
+// the algorithm, header names or signature version.
+func WithSigningOptions(opts ...hash.Option) Option {
+	return func(o *options) {
+		o.signing = append(o.signing, opts...)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+/*
+This file tunes the connection pool of the Client for high rate service to
+service traffic, where the 2 idle connections per host kept by default by
+http.Transport force new connections, and TLS handshakes, for most of the
+concurrent requests.
+
+The Clients built for the same endpoint with the same transport settings
+share their http.Transport, so that they share the pooled connections
+instead of every Client holding its own pool.
+
+# Usage
+
+    c, err := client.New("https://books.internal:8443",
+        client.WithCredentials(apiKey, secret),
+        client.WithMaxIdleConnsPerHost(256),
+        client.WithMaxConnsPerHost(512),
+        client.WithIdleConnTimeout(2*time.Minute),
+    )
+*/
+
+// Connection pool defaults applied by New unless configured otherwise.
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections kept
+	// per host.
+	DefaultMaxIdleConnsPerHost = 64
+
+	// DefaultIdleConnTimeout is how long an idle connection is kept.
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// WithMaxIdleConnsPerHost sets the number of idle connections kept per
+// host, for reuse by the subsequent requests.
+func WithMaxIdleConnsPerHost(n int) Option {
+	return func(o *options) {
+		o.maxIdleConnsPerHost = n
+	}
+}
+
+// WithMaxConnsPerHost bounds the number of connections per host, including
+// the ones in use, requests waiting for a connection once reached. Zero
+// means no limit, the default.
+func WithMaxConnsPerHost(n int) Option {
+	return func(o *options) {
+		o.maxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long an idle connection is kept before
+// being closed, zero meaning no limit.
+func WithIdleConnTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.idleConnTimeout = timeout
+	}
+}
+
+// WithHTTP2 enables or disables HTTP/2 for the TLS endpoints, enabled by
+// default. With HTTP/2 the requests to a host are multiplexed over a
+// single connection, disabling it spreads them over the pooled HTTP/1.1
+// connections instead.
+func WithHTTP2(enabled bool) Option {
+	return func(o *options) {
+		o.disableHTTP2 = !enabled
+	}
+}
+
+// transportKey identifies the transports shareable across the Clients.
+type transportKey struct {
+	scheme              string
+	host                string
+	dialTimeout         time.Duration
+	tlsHandshakeTimeout time.Duration
+	insecure            bool
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+	disableHTTP2        bool
+}
+
+// transports are the transports shared across the Clients.
+var transports = struct {
+	sync.Mutex
+	m map[transportKey]*http.Transport
+}{m: map[transportKey]*http.Transport{}}
+
+// sharedTransport returns the transport of the Clients of the endpoint
+// with the same transport settings, creating it if needed.
+func (o *options) sharedTransport(uri *url.URL) *http.Transport {
+	key := transportKey{
+		scheme:              uri.Scheme,
+		host:                uri.Host,
+		dialTimeout:         o.dialTimeout,
+		tlsHandshakeTimeout: o.tlsHandshakeTimeout,
+		insecure:            o.insecure,
+		maxIdleConnsPerHost: o.maxIdleConnsPerHost,
+		maxConnsPerHost:     o.maxConnsPerHost,
+		idleConnTimeout:     o.idleConnTimeout,
+		disableHTTP2:        o.disableHTTP2,
+	}
+	transports.Lock()
+	defer transports.Unlock()
+	if t, ok := transports.m[key]; ok {
+		return t
+	}
+	t := o.newTransport()
+	transports.m[key] = t
+	return t
+}
+
+// newTransport creates the transport as per the options.
+func (o *options) newTransport() *http.Transport {
+	// start from the default transport to retain proxy and HTTP/2 settings
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   o.dialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = o.tlsHandshakeTimeout
+	if o.insecure {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	transport.MaxIdleConnsPerHost = o.maxIdleConnsPerHost
+	transport.MaxIdleConns = max(transport.MaxIdleConns, o.maxIdleConnsPerHost)
+	transport.MaxConnsPerHost = o.maxConnsPerHost
+	transport.IdleConnTimeout = o.idleConnTimeout
+	if o.disableHTTP2 {
+		// a non nil empty map disables the HTTP/2 upgrade
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
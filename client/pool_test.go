@@ -0,0 +1,58 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConnectionPool(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	defer srv.Close()
+
+	transport := func(opts ...Option) *http.Transport {
+		c, err := New(srv.URL, append([]Option{WithCredentials("key", "secret"), WithInsecureSkipVerify()}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create client: %s", err)
+		}
+		return c.(*client).hClient.Transport.(*http.Transport)
+	}
+
+	def := transport()
+	if def.MaxIdleConnsPerHost != DefaultMaxIdleConnsPerHost || def.IdleConnTimeout != DefaultIdleConnTimeout || def.MaxConnsPerHost != 0 {
+		t.Errorf("unexpected default pool settings %d %d %s", def.MaxIdleConnsPerHost, def.MaxConnsPerHost, def.IdleConnTimeout)
+	}
+	if transport() != def {
+		t.Errorf("expected clients of the same endpoint to share the transport")
+	}
+
+	tuned := transport(WithMaxIdleConnsPerHost(256), WithMaxConnsPerHost(512), WithIdleConnTimeout(time.Minute))
+	if tuned == def {
+		t.Errorf("expected a separate transport for other settings")
+	}
+	if tuned.MaxIdleConnsPerHost != 256 || tuned.MaxConnsPerHost != 512 || tuned.IdleConnTimeout != time.Minute || tuned.MaxIdleConns < 256 {
+		t.Errorf("unexpected pool settings %d %d %d %s", tuned.MaxIdleConnsPerHost, tuned.MaxConnsPerHost, tuned.MaxIdleConns, tuned.IdleConnTimeout)
+	}
+
+	h1 := transport(WithHTTP2(false))
+	if h1.ForceAttemptHTTP2 || h1.TLSNextProto == nil {
+		t.Errorf("expected HTTP/2 to be disabled")
+	}
+	if !def.ForceAttemptHTTP2 {
+		t.Errorf("expected HTTP/2 to be enabled by default")
+	}
+	resp, err := (&http.Client{Transport: h1}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.ProtoMajor != 1 {
+		t.Errorf("expected HTTP/1.1, got %s", resp.Proto)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket limiting the rate of outgoing requests,
+// tokens are refilled continuously at rate per second up to burst.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64   // tokens added per second
+	burst  float64   // maximum tokens in the bucket
+	tokens float64   // tokens currently available
+	last   time.Time // last time the tokens were refilled
+}
+
+// newRateLimiter creates a token bucket, starting full.
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns the duration to wait before the token
+// can be used.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token that was reserved but not used.
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.burst, l.tokens+1)
+}
+
+// Wait blocks until a request is allowed to be sent or the context is done.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	wait := l.reserve(time.Now())
+	if wait == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		l.cancel()
+		return context.DeadlineExceeded
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// WithRateLimit throttles the outgoing signed requests of the Client to rps
+// requests per second, allowing bursts of up to burst requests, each
+// attempt of a retried request consumes a token. A non-positive rps
+// disables the rate limiting.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(o *options) {
+		if rps <= 0 {
+			o.limiter = nil
+			return
+		}
+		o.limiter = newRateLimiter(rps, burst)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	l := newRateLimiter(10, 2)
+	now := l.last
+	if l.reserve(now) != 0 || l.reserve(now) != 0 {
+		t.Fatalf("expected burst of 2 to be allowed immediately")
+	}
+	if wait := l.reserve(now); wait != 100*time.Millisecond {
+		t.Errorf("expected wait of 100ms, got %s", wait)
+	}
+	if wait := l.reserve(now.Add(300 * time.Millisecond)); wait != 0 {
+		t.Errorf("expected token after refill, got wait %s", wait)
+	}
+}
+
+func TestRateLimiterWaitDeadline(t *testing.T) {
+	l := newRateLimiter(1, 1)
+	_ = l.Wait(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil {
+		t.Errorf("expected wait beyond the deadline to fail")
+	}
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	cli, _ := NewClient(srv.URL, "key", "secret", false, WithRetry(testRetryPolicy()), WithRateLimit(1000, 10))
+	req, _ := http.NewRequest(http.MethodPost, "/resource", strings.NewReader("payload"))
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || calls.Load() != 2 {
+		t.Errorf("expected POST to be retried on 429, got %d after %d attempts", resp.StatusCode, calls.Load())
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+/*
+This file controls how the Client follows the redirects of the endpoint.
+
+The redirects within the configured endpoint, same scheme, host and base
+path, are re-signed, as the signature copied from the previous hop covers
+its path only. The authentication headers are stripped from the redirects
+leaving the endpoint, which http.Client would otherwise forward to the
+third-party host, as it only strips the Authorization and Cookie headers,
+unless the cross-host redirects are refused altogether.
+
+# Usage
+
+    // refuse redirects leaving the endpoint
+    c, err := client.New(endpoint, client.WithCredentials(apiKey, secret),
+        client.WithCrossHostRedirects(client.CrossHostDeny),
+        client.WithMaxRedirects(3),
+    )
+
+    // return the redirect responses to the caller
+    c, err := client.New(endpoint, client.WithCredentials(apiKey, secret),
+        client.WithoutRedirects(),
+    )
+*/
+
+// DefaultMaxRedirects is the number of redirects followed for a request
+// unless configured otherwise.
+const DefaultMaxRedirects = 10
+
+// ErrCrossHostRedirect is returned for the redirects leaving the endpoint
+// with CrossHostDeny.
+var ErrCrossHostRedirect = errors.New("redirect outside the endpoint refused")
+
+// CrossHostPolicy controls the redirects leaving the endpoint.
+type CrossHostPolicy int
+
+const (
+	// CrossHostStrip follows the redirects leaving the endpoint without
+	// the authentication headers, the default.
+	CrossHostStrip CrossHostPolicy = iota
+
+	// CrossHostDeny refuses the redirects leaving the endpoint, failing
+	// the request with ErrCrossHostRedirect.
+	CrossHostDeny
+)
+
+// WithoutRedirects disables following the redirects, returning the redirect
+// responses to the caller instead.
+func WithoutRedirects() Option {
+	return func(o *options) {
+		o.maxRedirects = 0
+	}
+}
+
+// WithMaxRedirects follows at most n redirects for a request, failing the
+// request beyond, see DefaultMaxRedirects. Zero or less disables following
+// the redirects, as WithoutRedirects.
+func WithMaxRedirects(n int) Option {
+	return func(o *options) {
+		o.maxRedirects = max(n, 0)
+	}
+}
+
+// WithCrossHostRedirects sets the policy for the redirects leaving the
+// endpoint, CrossHostStrip by default.
+func WithCrossHostRedirects(policy CrossHostPolicy) Option {
+	return func(o *options) {
+		o.crossHost = policy
+	}
+}
+
+// authHeaders returns the names of the authentication headers set by the
+// Generator with the signing options.
+func authHeaders(o *options, signing []hash.Option) []string {
+	if o.sigV4 != nil {
+		return []string{"Authorization", "X-Amz-Date", "X-Amz-Security-Token", "X-Amz-Content-Sha256"}
+	}
+	names := hash.ResolveHeaderNames(signing...)
+	return []string{
+		names.Signature, names.Algorithm, names.Version, names.Timestamp, names.KeyId,
+		names.ContentSignature, names.Nonce, names.SessionToken,
+		names.ImpersonateUser, names.ImpersonateTenant,
+	}
+}
+
+// withinEndpoint reports whether the URL is within the endpoint, with the
+// same scheme and host, and a path under its base path.
+func (c *client) withinEndpoint(u *url.URL) bool {
+	if u.Scheme != c.url.Scheme || !strings.EqualFold(u.Host, c.url.Host) {
+		return false
+	}
+	base := strings.TrimSuffix(c.url.EscapedPath(), "/")
+	path := u.EscapedPath()
+	return base == "" || path == base || strings.HasPrefix(path, base+"/")
+}
+
+// checkRedirect applies the redirect policy, re-signing the requests
+// redirected within the endpoint, the body of the redirected request being
+// replayed using GetBody, and stripping the authentication headers of the
+// ones leaving it.
+func (c *client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if c.opts.maxRedirects == 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > c.opts.maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", c.opts.maxRedirects)
+	}
+	if c.withinEndpoint(req.URL) {
+		if c.hGenerator != nil {
+			c.hGenerator.AddAuthHeaders(req)
+		}
+		return nil
+	}
+	if c.opts.crossHost == CrossHostDeny {
+		return fmt.Errorf("%w: %s", ErrCrossHostRedirect, req.URL.Redacted())
+	}
+	for _, h := range c.authHeaders {
+		if h != "" {
+			req.Header.Del(h)
+		}
+	}
+	return nil
+}
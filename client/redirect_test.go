@@ -0,0 +1,80 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestRedirectPolicy(t *testing.T) {
+	var leaked http.Header
+	third := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Clone()
+	}))
+	defer third.Close()
+
+	validator := hash.NewValidator(60, hash.WithHeaderPrefix("x-acme-"))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected %s to be signed: %s", r.URL.Path, err)
+		}
+		switch r.URL.Path {
+		case "/api/loop":
+			http.Redirect(w, r, "/api/loop", http.StatusFound)
+		case "/api/moved":
+			http.Redirect(w, r, "/api/books", http.StatusFound)
+		case "/api/away":
+			http.Redirect(w, r, third.URL+"/books", http.StatusFound)
+		case "/api/outside":
+			http.Redirect(w, r, "/other", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	get := func(path string, opts ...Option) (*http.Response, error) {
+		cli, err := New(srv.URL+"/api", append([]Option{
+			WithCredentials("key", "secret"),
+			WithSigningOptions(hash.WithHeaderPrefix("x-acme-")),
+		}, opts...)...)
+		if err != nil {
+			t.Fatalf("failed to create client: %s", err)
+		}
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		resp, err := cli.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	if resp, err := get("/moved"); err != nil || resp.Request.URL.Path != "/api/books" {
+		t.Errorf("expected the redirect within the endpoint to be re-signed and followed, got %v", err)
+	}
+	if _, err := get("/away"); err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	for _, h := range []string{"x-acme-signature", "x-acme-api-key-id", "x-acme-timestamp"} {
+		if leaked.Get(h) != "" {
+			t.Errorf("expected %s to be stripped on the cross-host redirect", h)
+		}
+	}
+	if _, err := get("/away", WithCrossHostRedirects(CrossHostDeny)); !errors.Is(err, ErrCrossHostRedirect) {
+		t.Errorf("expected the cross-host redirect to be refused, got %v", err)
+	}
+	if _, err := get("/outside", WithCrossHostRedirects(CrossHostDeny)); !errors.Is(err, ErrCrossHostRedirect) {
+		t.Errorf("expected the redirect outside the base path to be refused, got %v", err)
+	}
+
+	if resp, err := get("/moved", WithoutRedirects()); err != nil || resp.StatusCode != http.StatusFound {
+		t.Errorf("expected the redirect response to be returned, got %v", err)
+	}
+	if _, err := get("/loop", WithMaxRedirects(3)); err == nil {
+		t.Errorf("expected the redirect loop to be stopped")
+	}
+}
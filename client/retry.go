@@ -0,0 +1,235 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried by the Client. Each
+// attempt is signed afresh, so retried requests carry a new timestamp and
+// are not rejected as expired by the server.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the computed exponential backoff
+	MaxBackoff time.Duration
+
+	// Multiplier grows the backoff after every attempt
+	Multiplier float64
+
+	// Jitter is the fraction, in the range [0, 1], of the backoff that is
+	// randomized to avoid synchronized retries across clients
+	Jitter float64
+
+	// RetryableStatusCodes are the response status codes that are retried,
+	// transport errors such as connection resets are always retried
+	RetryableStatusCodes []int
+
+	// RespectRetryAfter waits at least for the duration indicated by the
+	// Retry-After header of a retryable response, or in its absence by the
+	// reset of an exhausted quota in the RateLimit header
+	RespectRetryAfter bool
+
+	// RetryNonIdempotent allows retrying requests with non idempotent
+	// methods (POST, PATCH, CONNECT) on any retryable failure, which may
+	// result in the request being processed more than once by the server,
+	// otherwise they are only retried on 429 Too Many Requests
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns the recommended retry policy: 3 attempts,
+// exponential backoff from 100ms up to 2s with 20% jitter, retrying 429,
+// 502, 503 and 504 responses and honoring Retry-After.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          3,
+		InitialBackoff:       100 * time.Millisecond,
+		MaxBackoff:           2 * time.Second,
+		Multiplier:           2,
+		Jitter:               0.2,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RespectRetryAfter:    true,
+	}
+}
+
+// WithRetry enables retrying failed requests using the given policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+// canRetry reports whether the request may be sent more than once, which
+// requires a body that can be replayed.
+func (p *RetryPolicy) canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isIdempotent reports whether the request method is idempotent
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodPost, http.MethodPatch, http.MethodConnect:
+		return false
+	}
+	return true
+}
+
+// shouldRetry reports whether the outcome of an attempt is retryable.
+// Requests with non idempotent methods are only retried, unless explicitly
+// allowed, when rejected with 429 Too Many Requests, which guarantees that
+// the request was not processed.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, req *http.Request, resp *http.Response, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	if !p.RetryNonIdempotent && !isIdempotent(req) {
+		return err == nil && resp.StatusCode == http.StatusTooManyRequests &&
+			slices.Contains(p.RetryableStatusCodes, resp.StatusCode)
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return slices.Contains(p.RetryableStatusCodes, resp.StatusCode)
+}
+
+// backoff returns the wait before the next attempt, after the given number
+// of attempts have been made.
+func (p *RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait -= wait * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	d := time.Duration(wait)
+	if p.RespectRetryAfter && resp != nil {
+		after, ok := parseRetryAfter(resp.Header.Get("Retry-After"))
+		if !ok {
+			after, ok = parseRateLimitReset(resp.Header)
+		}
+		if ok && after > d {
+			d = after
+		}
+	}
+	return d
+}
+
+// parseRateLimitReset returns the seconds until the quota is reset, once
+// exhausted, as per the RateLimit header, e.g. "limit=100, remaining=0,
+// reset=12", or the RateLimit-Remaining and RateLimit-Reset headers of the
+// earlier drafts.
+func parseRateLimitReset(h http.Header) (time.Duration, bool) {
+	remaining, reset := h.Get("RateLimit-Remaining"), h.Get("RateLimit-Reset")
+	if v := h.Get("RateLimit"); v != "" {
+		for _, param := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			switch strings.ToLower(name) {
+			case "remaining", "r":
+				remaining = value
+			case "reset", "t":
+				reset = value
+			}
+		}
+	}
+	if remaining != "0" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(reset)
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// parseRetryAfter parses the Retry-After header value, either delay seconds
+// or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// newAttempt returns a copy of the unsigned request for a single attempt,
+// with a fresh body if the request body can be replayed.
+func newAttempt(ctx context.Context, req *http.Request) (*http.Request, error) {
+	attempt := req.Clone(ctx)
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		attempt.Body = body
+	}
+	return attempt, nil
+}
+
+// drainBody discards and closes the body of a response that is not returned
+// to the caller, allowing the connection to be reused.
+func drainBody(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, 4096)
+	_ = resp.Body.Close()
+}
+
+// doWithRetry sends the request, retrying according to the retry policy,
+// where every attempt is signed with a fresh timestamp.
+func (c *client) doWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
+	policy := c.opts.retry
+	attempts := max(policy.MaxAttempts, 1)
+	if !policy.canRetry(req) {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		r, err := newAttempt(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.send(r)
+		if attempt >= attempts || !policy.shouldRetry(ctx, req, resp, err) {
+			return resp, err
+		}
+
+		wait := policy.backoff(attempt, resp)
+		drainBody(resp)
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
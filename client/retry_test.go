@@ -0,0 +1,98 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryResignsEveryAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := len(r.Header.Values("x-signature")); n != 1 {
+			t.Errorf("expected a single signature header, got %d", n)
+		}
+		if b, _ := io.ReadAll(r.Body); string(b) != "payload" {
+			t.Errorf("expected replayed body, got %q", b)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cli, err := NewClient(srv.URL, "key", "secret", false, WithRetry(testRetryPolicy()))
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	req, _ := http.NewRequest(http.MethodPut, "/resource", strings.NewReader("payload"))
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("expected success after 3 attempts, got %d after %d", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestRetrySkipsNonIdempotent(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	cli, _ := NewClient(srv.URL, "key", "secret", false, WithRetry(testRetryPolicy()))
+	req, _ := http.NewRequest(http.MethodPost, "/resource", strings.NewReader("payload"))
+	resp, err := cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("expected POST not to be retried, got %d attempts", calls.Load())
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, RespectRetryAfter: true}
+	if d := p.backoff(1, nil); d != 100*time.Millisecond {
+		t.Errorf("expected 100ms, got %s", d)
+	}
+	if d := p.backoff(3, nil); d != 400*time.Millisecond {
+		t.Errorf("expected 400ms, got %s", d)
+	}
+	if d := p.backoff(10, nil); d != time.Second {
+		t.Errorf("expected backoff capped to 1s, got %s", d)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if d := p.backoff(1, resp); d != 3*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %s", d)
+	}
+	resp = &http.Response{Header: http.Header{"Ratelimit": []string{"limit=100, remaining=0, reset=2"}}}
+	if d := p.backoff(1, resp); d != 2*time.Second {
+		t.Errorf("expected RateLimit reset to be honored, got %s", d)
+	}
+	resp = &http.Response{Header: http.Header{"Ratelimit": []string{"limit=100, remaining=5, reset=2"}}}
+	if d := p.backoff(1, resp); d != 100*time.Millisecond {
+		t.Errorf("expected RateLimit with remaining quota to be ignored, got %s", d)
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+// sigV4 is the AWS region and service the requests are signed for.
+type sigV4 struct {
+	region  string
+	service string
+}
+
+// WithSigV4 signs the requests with AWS Signature Version 4 for the region
+// and service, e.g. "us-east-1" and "execute-api", instead of the HMAC
+// headers, for endpoints behind AWS API Gateway with IAM auth. The
+// credentials are the access key id and secret access key, and the
+// session token of temporary credentials is set with
+// WithSigningOptions(hash.WithSessionToken(token)).
+func WithSigV4(region, service string) Option {
+	return func(o *options) {
+		o.sigV4 = &sigV4{region: region, service: service}
+	}
+}
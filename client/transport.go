@@ -0,0 +1,58 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+/*
+This file exposes the request signing as an http.RoundTripper, allowing HMAC
+authentication to be plugged into any http.Client, or into libraries that
+accept a Transport, and to be composed with other transports such as tracing
+or retries. Every request passing through the transport, including retries
+performed by outer transports, is signed with a fresh timestamp.
+
+# Usage
+
+    hc := &http.Client{
+        Transport: client.NewSigningTransport("api-key-id", "supersecret", nil),
+    }
+    resp, err := hc.Get("https://api.example.com/resource")
+*/
+
+// signingTransport is an http.RoundTripper signing requests before passing
+// them to the base transport.
+type signingTransport struct {
+	base       http.RoundTripper // Transport sending the signed requests
+	hGenerator hash.Generator    // HMAC header generator
+}
+
+// RoundTrip signs a copy of the request and sends it using the base
+// transport, the original request is left unmodified as required by the
+// http.RoundTripper contract.
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	signed := req.Clone(req.Context())
+	return t.base.RoundTrip(t.hGenerator.AddAuthHeaders(signed))
+}
+
+// NewSigningTransport creates an http.RoundTripper that signs every request
+// with HMAC authentication headers.
+//
+// Parameters:
+//   - apiKey: API key identifier
+//   - secret: Secret key for HMAC signing
+//   - base:   Transport sending the signed requests, http.DefaultTransport if nil
+//   - opts:   Optional signing configuration such as the algorithm or header names
+func NewSigningTransport(apiKey, secret string, base http.RoundTripper, opts ...hash.Option) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &signingTransport{
+		base:       base,
+		hGenerator: hash.NewGenerator(apiKey, secret, opts...),
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestSigningTransport(t *testing.T) {
+	validator := hash.NewValidator(60)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if validator.GetKeyId(r) != "key" {
+			t.Errorf("unexpected key id %q", validator.GetKeyId(r))
+		}
+		if ok, err := validator.Validate(r, "secret"); !ok {
+			t.Errorf("expected a valid signature: %s", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hc := &http.Client{Transport: NewSigningTransport("key", "secret", nil)}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/resource", nil)
+	resp, err := hc.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+	if req.Header.Get("x-signature") != "" {
+		t.Errorf("expected the original request to be left unmodified")
+	}
+}
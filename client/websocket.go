@@ -0,0 +1,42 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/coder/websocket"
+)
+
+// DialWebSocket establishes a WebSocket connection with the path relative
+// to the endpoint, where the upgrade request is signed with the
+// authentication headers. The timeout of the client is not applied to the
+// established connection, which lives until closed or the context used
+// for reading or writing is done.
+func (c *client) DialWebSocket(ctx context.Context, path string) (*websocket.Conn, *http.Response, error) {
+	if c.url == nil {
+		return nil, nil, fmt.Errorf("Client not initialized")
+	}
+	u, err := url.Parse(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	u.Scheme = c.url.Scheme
+	u.Host = c.url.Host
+	joinBasePath(c.url, u)
+
+	// the upgraded connection outlives the request, hence a client
+	// without overall timeout sharing the transport is used, signing the
+	// handshake request
+	hc := &http.Client{
+		Transport: &signingTransport{
+			base:       c.hClient.Transport,
+			hGenerator: c.hGenerator,
+		},
+	}
+	return websocket.Dial(ctx, u.String(), &websocket.DialOptions{HTTPClient: hc})
+}
@@ -0,0 +1,54 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coder/websocket"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestDialWebSocket(t *testing.T) {
+	validator := hash.NewValidator(60)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, err := hash.ValidateUpgrade(validator, r, "secret"); !ok {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		typ, msg, err := conn.Read(r.Context())
+		if err == nil {
+			_ = conn.Write(r.Context(), typ, msg)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	cli, _ := NewClient(srv.URL, "key", "secret", false)
+	conn, _, err := cli.DialWebSocket(ctx, "/events")
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %s", err)
+	}
+	defer conn.CloseNow()
+	if err := conn.Write(ctx, websocket.MessageText, []byte("ping")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+	if _, msg, err := conn.Read(ctx); err != nil || string(msg) != "ping" {
+		t.Errorf("unexpected echo %q: %v", msg, err)
+	}
+
+	cli, _ = NewClient(srv.URL, "key", "wrong", false)
+	if _, resp, err := cli.DialWebSocket(ctx, "/events"); err == nil || resp == nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected handshake with a wrong secret to be rejected, got %v", err)
+	}
+}
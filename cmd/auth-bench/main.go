@@ -0,0 +1,290 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	coreerrors "github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+)
+
+/*
+Command auth-bench drives the validation path of the auth module in
+process, signing a request, validating its signature with the secret
+resolved through the caching resolver, resolving its route and
+authorizing the caller with RBAC, at a configurable rate and key and route
+cardinality. It reports the latency percentiles, measured from the
+scheduled start of every operation so that stalls are not hidden, along
+with the allocations per operation.
+
+With -max-p99 or -max-allocs it exits with status 1 when exceeded, so that
+performance regressions of the middleware chain fail the release
+pipeline.
+
+# Usage
+
+    go run github.com/go-core-stack/auth/cmd/auth-bench -rps 20000 -duration 30s \
+        -keys 10000 -routes 500 -store-latency 2ms -max-p99 1ms
+*/
+
+// stages of the validation path
+const (
+	stageSign = iota
+	stageValidate
+	stageRoute
+	stageAuthz
+	numStages
+)
+
+var stageNames = [numStages]string{"sign", "validate", "route", "authz"}
+
+// config is the configuration of the run, from the flags.
+type config struct {
+	rps          int
+	duration     time.Duration
+	concurrency  int
+	keys         int
+	routes       int
+	storeLatency time.Duration
+	maxP99       time.Duration
+	maxAllocs    float64
+}
+
+// bench holds the fixture of the validation path.
+type bench struct {
+	generators []hash.Generator
+	validator  hash.Validator
+	resolver   *hash.CachingSecretResolver
+	templates  []string
+	policy     *rbac.Policy
+}
+
+// newBench creates the keys, routes and policy of the run.
+func newBench(conf *config) *bench {
+	secrets := make(map[string]string, conf.keys)
+	b := &bench{validator: hash.NewValidator(300)}
+	roles := []*rbac.Role{{
+		Key:   &rbac.RoleKey{Name: "reader"},
+		Rules: []*rbac.Rule{{Resources: []string{"svc-*"}, Verbs: []string{"get"}}},
+	}}
+	bindings := make([]*rbac.RoleBinding, 0, conf.keys)
+	for i := range conf.keys {
+		id := fmt.Sprintf("key-%06d", i)
+		secrets[id] = fmt.Sprintf("secret-%06d", i)
+		b.generators = append(b.generators, hash.NewGenerator(id, secrets[id]))
+		bindings = append(bindings, &rbac.RoleBinding{Key: &rbac.RoleBindingKey{Subject: id, Role: "reader"}})
+	}
+	for i := range conf.routes {
+		b.templates = append(b.templates, fmt.Sprintf("/svc-%d/items/{id}", i))
+	}
+	b.policy = rbac.NewPolicy("", roles, bindings)
+	b.resolver = hash.NewCachingSecretResolver(hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		if conf.storeLatency > 0 {
+			time.Sleep(conf.storeLatency)
+		}
+		secret, ok := secrets[keyId]
+		if !ok {
+			return "", coreerrors.Wrapf(coreerrors.NotFound, "api key %s not found", keyId)
+		}
+		return secret, nil
+	}))
+	return b
+}
+
+// resolveRoute returns the template matching the path, scanning the
+// templates as the route table does.
+func (b *bench) resolveRoute(path string) (string, bool) {
+	for _, t := range b.templates {
+		if _, ok := route.MatchPath(t, path); ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// run executes a single operation, adding the duration of every stage.
+func (b *bench) run(rng *rand.Rand, stages *[numStages]time.Duration) error {
+	gen := b.generators[rng.IntN(len(b.generators))]
+	svc := rng.IntN(len(b.templates))
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/svc-%d/items/%d", svc, rng.IntN(1000)), nil)
+
+	start := time.Now()
+	r = gen.AddAuthHeaders(r)
+	t := time.Now()
+	stages[stageSign] += t.Sub(start)
+
+	keyId := b.validator.GetKeyId(r)
+	secret, err := b.resolver.GetSecret(r.Context(), keyId)
+	if err != nil {
+		return err
+	}
+	if ok, err := b.validator.Validate(r, secret); !ok {
+		return err
+	}
+	start, t = t, time.Now()
+	stages[stageValidate] += t.Sub(start)
+
+	if _, ok := b.resolveRoute(r.URL.Path); !ok {
+		return errors.New("route not found")
+	}
+	start, t = t, time.Now()
+	stages[stageRoute] += t.Sub(start)
+
+	if !b.policy.Evaluate(keyId, fmt.Sprintf("svc-%d", svc), "get") {
+		return errors.New("access denied")
+	}
+	stages[stageAuthz] += time.Since(t)
+	return nil
+}
+
+// result holds the measurements of a worker.
+type result struct {
+	latencies []time.Duration
+	stages    [numStages]time.Duration
+	errors    int
+}
+
+// drive runs the operations at the configured rate until the duration
+// elapses, returning the merged measurements.
+func drive(b *bench, conf *config) *result {
+	var next atomic.Int64
+	start := time.Now()
+	deadline := start.Add(conf.duration)
+	results := make([]*result, conf.concurrency)
+	var wg sync.WaitGroup
+	for w := range conf.concurrency {
+		res := &result{}
+		results[w] = res
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(uint64(w), uint64(start.UnixNano())))
+			for {
+				n := next.Add(1) - 1
+				began := time.Now()
+				if conf.rps > 0 {
+					scheduled := start.Add(time.Duration(n) * time.Second / time.Duration(conf.rps))
+					if began.Before(scheduled) {
+						// ahead of schedule, the oversleep of the timer
+						// is not accounted
+						time.Sleep(time.Until(scheduled))
+						began = time.Now()
+					} else {
+						// behind schedule, the operation waited for a
+						// worker since its scheduled start
+						began = scheduled
+					}
+				}
+				if began.After(deadline) {
+					return
+				}
+				if err := b.run(rng, &res.stages); err != nil {
+					res.errors++
+				}
+				res.latencies = append(res.latencies, time.Since(began))
+			}
+		}()
+	}
+	wg.Wait()
+
+	merged := &result{}
+	for _, res := range results {
+		merged.latencies = append(merged.latencies, res.latencies...)
+		merged.errors += res.errors
+		for i := range merged.stages {
+			merged.stages[i] += res.stages[i]
+		}
+	}
+	return merged
+}
+
+// percentile returns the p-th percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted)-1) * p / 100)
+	return sorted[i]
+}
+
+func main() {
+	conf := &config{}
+	flag.IntVar(&conf.rps, "rps", 0, "operations per second, 0 for as fast as possible")
+	flag.DurationVar(&conf.duration, "duration", 10*time.Second, "duration of the run")
+	flag.IntVar(&conf.concurrency, "concurrency", 0, "number of concurrent workers, GOMAXPROCS or 64 with -rps by default")
+	flag.IntVar(&conf.keys, "keys", 1000, "number of API keys")
+	flag.IntVar(&conf.routes, "routes", 100, "number of routes")
+	flag.DurationVar(&conf.storeLatency, "store-latency", 0, "latency of the secret store on a cache miss")
+	flag.DurationVar(&conf.maxP99, "max-p99", 0, "fail if the p99 latency exceeds it")
+	flag.Float64Var(&conf.maxAllocs, "max-allocs", 0, "fail if the allocations per operation exceed it")
+	flag.Parse()
+	if conf.concurrency == 0 {
+		// paced workers idle between operations, enough of them absorb
+		// the oversleep of the timers
+		conf.concurrency = runtime.GOMAXPROCS(0)
+		if conf.rps > 0 {
+			conf.concurrency = 64
+		}
+	}
+	if conf.keys <= 0 || conf.routes <= 0 || conf.concurrency <= 0 {
+		fmt.Fprintln(os.Stderr, "keys, routes and concurrency must be positive")
+		os.Exit(2)
+	}
+
+	b := newBench(conf)
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	res := drive(b, conf)
+	runtime.ReadMemStats(&after)
+
+	ops := len(res.latencies)
+	if ops == 0 {
+		fmt.Fprintln(os.Stderr, "no operation completed")
+		os.Exit(2)
+	}
+	sort.Slice(res.latencies, func(i, j int) bool { return res.latencies[i] < res.latencies[j] })
+	allocs := float64(after.Mallocs-before.Mallocs) / float64(ops)
+	p99 := percentile(res.latencies, 99)
+	stats := b.resolver.Stats()
+
+	fmt.Printf("operations   %d (%.0f/s), %d errors\n", ops, float64(ops)/conf.duration.Seconds(), res.errors)
+	fmt.Printf("latency      p50 %s  p90 %s  p99 %s  p99.9 %s  max %s\n",
+		percentile(res.latencies, 50), percentile(res.latencies, 90), p99,
+		percentile(res.latencies, 99.9), res.latencies[ops-1])
+	for i, name := range stageNames {
+		fmt.Printf("%-12s %s/op\n", name, res.stages[i]/time.Duration(ops))
+	}
+	fmt.Printf("allocations  %.1f allocs/op  %.0f B/op\n", allocs, float64(after.TotalAlloc-before.TotalAlloc)/float64(ops))
+	fmt.Printf("resolver     %d hits  %d lookups\n", stats.Hits, stats.Lookups)
+
+	failed := false
+	if conf.maxP99 > 0 && p99 > conf.maxP99 {
+		fmt.Fprintf(os.Stderr, "p99 latency %s exceeds %s\n", p99, conf.maxP99)
+		failed = true
+	}
+	if conf.maxAllocs > 0 && allocs > conf.maxAllocs {
+		fmt.Fprintf(os.Stderr, "%.1f allocs/op exceed %.1f\n", allocs, conf.maxAllocs)
+		failed = true
+	}
+	if failed || res.errors != 0 {
+		os.Exit(1)
+	}
+}
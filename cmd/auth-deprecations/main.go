@@ -0,0 +1,99 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-core-stack/auth/internal/deprecation"
+)
+
+/*
+Command auth-deprecations reports the uses of the deprecated API of the
+auth module in the Go sources of a service, along with their replacements,
+so that services can migrate incrementally ahead of the v2 module, see
+docs/v2-migration.md. It exits with status 1 if any use is found, allowing
+CI to prevent new uses.
+
+# Usage
+
+    go run github.com/go-core-stack/auth/cmd/auth-deprecations ./...
+    go run github.com/go-core-stack/auth/cmd/auth-deprecations -list
+*/
+
+func main() {
+	list := flag.Bool("list", false, "list the deprecated identifiers and their replacements")
+	flag.Parse()
+
+	if *list {
+		for _, e := range deprecation.List {
+			fmt.Printf("%s.%s: use %s\n", e.Package, e.Name, e.Replacement)
+		}
+		return
+	}
+
+	patterns := flag.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	found := 0
+	for _, pattern := range patterns {
+		n, err := report(pattern)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		found += n
+	}
+	if found != 0 {
+		fmt.Fprintf(os.Stderr, "%d use(s) of deprecated auth API found\n", found)
+		os.Exit(1)
+	}
+}
+
+// report prints the uses of the deprecated API in the directory, and its
+// sub directories for patterns ending with "/...", returning their count.
+func report(pattern string) (int, error) {
+	dir, recursive := strings.CutSuffix(pattern, "/...")
+	if dir == "" {
+		dir = "."
+	}
+	fset := token.NewFileSet()
+	found := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path == dir {
+				return nil
+			}
+			name := d.Name()
+			if !recursive || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		for _, finding := range deprecation.Scan(fset, f) {
+			fmt.Println(finding)
+			found++
+		}
+		return nil
+	})
+	return found, err
+}
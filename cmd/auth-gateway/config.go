@@ -0,0 +1,129 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Environment variables carrying the secrets, kept out of the config file.
+const (
+	// base64 encoded 32 bytes key sealing the API key secrets
+	encryptionKeyEnv = "AUTH_GATEWAY_ENCRYPTION_KEY"
+
+	// password of the mongodb user
+	mongoPasswordEnv = "AUTH_GATEWAY_MONGO_PASSWORD"
+
+	// secret signing the identity headers injected in the proxied requests
+	identitySecretEnv = "AUTH_GATEWAY_IDENTITY_SECRET"
+
+	// service credentials re-signing the proxied requests
+	serviceKeyIdEnv  = "AUTH_GATEWAY_KEY_ID"
+	serviceSecretEnv = "AUTH_GATEWAY_SECRET"
+)
+
+// duration is a time.Duration encoded as a string, e.g. "30s".
+type duration time.Duration
+
+// UnmarshalJSON decodes the duration string.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// mongoConfig is the configuration of the mongodb holding the routes, API
+// keys and roles.
+type mongoConfig struct {
+	Uri      string `json:"uri,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Port     string `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+
+	// database holding the API keys and roles, routes are held in the
+	// services database
+	Database string `json:"database,omitempty"`
+}
+
+// config is the configuration of the gateway, loaded from the JSON file.
+type config struct {
+	// address serving the proxied requests
+	Listen string `json:"listen,omitempty"`
+
+	// address serving the admin endpoints, health, metrics and route
+	// cache invalidation, to be kept private
+	AdminListen string `json:"adminListen,omitempty"`
+
+	Mongo mongoConfig `json:"mongo"`
+
+	// validity in seconds of the signature timestamps
+	Validity int64 `json:"validity,omitempty"`
+
+	// number of resolved routes cached and how long
+	RouteCacheSize int      `json:"routeCacheSize,omitempty"`
+	RouteCacheTTL  duration `json:"routeCacheTTL,omitempty"`
+
+	// emit the audit records of the decisions to stdout
+	Audit bool `json:"audit,omitempty"`
+
+	// timeout for the in-flight requests to complete on shutdown
+	ShutdownTimeout duration `json:"shutdownTimeout,omitempty"`
+}
+
+// defaultConfig returns the configuration used for the unset fields.
+func defaultConfig() *config {
+	return &config{
+		Listen:          ":8080",
+		AdminListen:     "127.0.0.1:9090",
+		Mongo:           mongoConfig{Database: "auth"},
+		Validity:        300,
+		RouteCacheSize:  4096,
+		RouteCacheTTL:   duration(30 * time.Second),
+		ShutdownTimeout: duration(30 * time.Second),
+	}
+}
+
+// loadConfig returns the configuration of the file, the defaults if no
+// file is given.
+func loadConfig(path string) (*config, error) {
+	c := defaultConfig()
+	if path == "" {
+		return c, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if c.Validity <= 0 {
+		return nil, fmt.Errorf("invalid config %s: validity must be positive", path)
+	}
+	return c, nil
+}
+
+// encryptionKey returns the key sealing the API key secrets.
+func encryptionKey() ([]byte, error) {
+	v := os.Getenv(encryptionKeyEnv)
+	if v == "" {
+		return nil, fmt.Errorf("%s not set", encryptionKeyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not base64 encoded: %w", encryptionKeyEnv, err)
+	}
+	return key, nil
+}
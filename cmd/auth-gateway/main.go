@@ -0,0 +1,220 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-core-stack/core/db"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/audit"
+	"github.com/go-core-stack/auth/gateway"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+)
+
+/*
+Command auth-gateway is the reference deployment of the auth gateway,
+wiring the route table, the API key and RBAC stores held in mongodb with
+the gateway package: every request is authenticated with its HMAC
+signature against the API keys, enforcing their tenancy, scopes, network
+policy and the lockout of the keys and addresses failing validation, kept
+in memory per replica, authorized as per its route and reverse proxied to
+the endpoint of the route.
+
+The configuration is loaded from a JSON file, the secrets from the
+environment:
+
+  - AUTH_GATEWAY_ENCRYPTION_KEY   base64 32 bytes key sealing the API key secrets
+  - AUTH_GATEWAY_MONGO_PASSWORD   password of the mongodb user
+  - AUTH_GATEWAY_IDENTITY_SECRET  optional, signs the injected identity headers
+  - AUTH_GATEWAY_KEY_ID, AUTH_GATEWAY_SECRET
+    optional, service credentials re-signing the proxied requests
+
+The admin listener, to be kept private, serves:
+
+  - /healthz            liveness, along with the reachability of mongodb
+  - /metrics            expvar metrics including the gateway counters
+  - /routes/invalidate  POST, drops the cached routes after an update
+
+# Usage
+
+    auth-gateway -config /etc/auth-gateway/config.json
+
+with the config:
+
+    {
+        "listen": ":8080",
+        "adminListen": "127.0.0.1:9090",
+        "mongo": {"uri": "mongodb://mongo:27017", "username": "gateway", "database": "auth"},
+        "validity": 300,
+        "routeCacheTTL": "30s",
+        "audit": true
+    }
+*/
+
+// counters of the gateway published with expvar
+var (
+	requests = expvar.NewInt("auth_gateway_requests")
+	rejected = expvar.NewInt("auth_gateway_rejected")
+)
+
+func main() {
+	path := flag.String("config", "", "path of the JSON config file")
+	flag.Parse()
+
+	conf, err := loadConfig(*path)
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err)
+	}
+	if err := run(conf); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the gateway and the admin endpoints until interrupted.
+func run(conf *config) error {
+	key, err := encryptionKey()
+	if err != nil {
+		return err
+	}
+	client, err := db.NewMongoClient(&db.MongoConfig{
+		Uri:      conf.Mongo.Uri,
+		Host:     conf.Mongo.Host,
+		Port:     conf.Mongo.Port,
+		Username: conf.Mongo.Username,
+		Password: os.Getenv(mongoPasswordEnv),
+	})
+	if err != nil {
+		return err
+	}
+	store := client.GetDataStore(conf.Mongo.Database)
+
+	routes, err := route.NewRouteTable(client)
+	if err != nil {
+		return err
+	}
+	keys, err := apikey.NewStore(store, key)
+	if err != nil {
+		return err
+	}
+	roles, err := rbac.NewStore(store)
+	if err != nil {
+		return err
+	}
+
+	opts := []gateway.Option{
+		gateway.WithAuthorizer(roles),
+		gateway.WithRouteCache(conf.RouteCacheSize, time.Duration(conf.RouteCacheTTL)),
+	}
+	if secret := os.Getenv(identitySecretEnv); secret != "" {
+		opts = append(opts, gateway.WithIdentitySecret(secret))
+	}
+	if os.Getenv(serviceKeyIdEnv) != "" {
+		opts = append(opts, gateway.WithServiceCredentials(hash.EnvCredentials(serviceKeyIdEnv, serviceSecretEnv)))
+	}
+	keyOpts := []apikey.MiddlewareOption{
+		apikey.WithLockout(apikey.NewLockout(apikey.LockoutPolicy{}, nil)),
+	}
+	var batcher *audit.Batcher
+	if conf.Audit {
+		batcher = audit.NewBatcher(audit.NewWriterSink(os.Stdout))
+		opts = append(opts, gateway.WithAudit(batcher))
+		keyOpts = append(keyOpts, apikey.WithAudit(batcher))
+	}
+	auth := gateway.KeyStoreAuthenticator(keys, hash.NewValidator(conf.Validity), keyOpts...)
+	gw, err := gateway.New(auth, routes, opts...)
+	if err != nil {
+		return err
+	}
+
+	servers := []*http.Server{
+		{Addr: conf.Listen, Handler: count(gw)},
+		{Addr: conf.AdminListen, Handler: adminHandler(client, gw)},
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errs := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func() {
+			log.Printf("listening on %s", srv.Addr)
+			if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				errs <- err
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), time.Duration(conf.ShutdownTimeout))
+	defer cancel()
+	for _, srv := range servers {
+		_ = srv.Shutdown(shutdown)
+	}
+	if batcher != nil {
+		_ = batcher.Close(shutdown)
+	}
+	return err
+}
+
+// statusRecorder records the status of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// count updates the request counters for the requests served by next.
+func count(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		requests.Add(1)
+		if rec.status == http.StatusUnauthorized || rec.status == http.StatusForbidden {
+			rejected.Add(1)
+		}
+	})
+}
+
+// adminHandler returns the handler of the admin endpoints.
+func adminHandler(client db.StoreClient, gw *gateway.Gateway) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+		if err := client.HealthCheck(ctx); err != nil {
+			http.Error(w, "database unreachable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.Handle("GET /metrics", expvar.Handler())
+	mux.HandleFunc("POST /routes/invalidate", func(w http.ResponseWriter, r *http.Request) {
+		gw.InvalidateRoutes()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
@@ -18,3 +18,16 @@ const (
 	// grpc gateway will typically move the header to lowercase
 	GrpcClientAuthContext = "auth-info"
 )
+
+const (
+	// Identity headers injected by the Auth Gateway toward the backends,
+	// describing the authenticated identity in plain form, protected by
+	// an HMAC carried in IdentitySignatureHeader, computed using the
+	// secret shared between the gateway and the backends.
+	IdentitySubjectHeader   = "x-auth-subject"
+	IdentityTenantHeader    = "x-auth-tenant"
+	IdentityRolesHeader     = "x-auth-roles"
+	IdentityKeyIdHeader     = "x-auth-key-id"
+	IdentityTimestampHeader = "x-auth-timestamp"
+	IdentitySignatureHeader = "x-auth-signature"
+)
@@ -0,0 +1,130 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package context
+
+import (
+	"crypto/hmac"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// Identity describes the authenticated identity injected by the gateway
+// toward the backends using the x-auth-* headers.
+type Identity struct {
+	// subject authenticated, e.g. user name or service account
+	Subject string
+
+	// tenant the subject belongs to
+	Tenant string
+
+	// roles associated with the subject
+	Roles []string
+
+	// API key used for authentication, if any
+	KeyId string
+}
+
+// identityHeaders lists all the headers of the identity contract
+var identityHeaders = []string{
+	IdentitySubjectHeader,
+	IdentityTenantHeader,
+	IdentityRolesHeader,
+	IdentityKeyIdHeader,
+	IdentityTimestampHeader,
+	IdentitySignatureHeader,
+}
+
+// identitySignature computes the HMAC over the identity, the timestamp and
+// the request method and path, binding the identity to the request
+func identitySignature(r *http.Request, id *Identity, timestamp, secret string) string {
+	return hash.GenerateSHA256HMAC(secret,
+		id.Subject,
+		id.Tenant,
+		strings.Join(id.Roles, ","),
+		id.KeyId,
+		timestamp,
+		r.Method,
+		r.URL.Path,
+	)
+}
+
+// SetIdentityHeaders injects the identity headers in the request to be
+// sent to a backend, replacing any identity header received from the
+// caller, and signs them using the secret shared with the backends. It is
+// expected to be invoked after any rewrite of the request method or path.
+func SetIdentityHeaders(r *http.Request, id *Identity, secret string) error {
+	if id == nil || id.Subject == "" {
+		return errors.Wrapf(errors.InvalidArgument, "identity subject not specified")
+	}
+	if secret == "" {
+		return errors.Wrapf(errors.InvalidArgument, "identity signing secret not specified")
+	}
+	DeleteIdentityHeaders(r)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	r.Header.Set(IdentitySubjectHeader, id.Subject)
+	if id.Tenant != "" {
+		r.Header.Set(IdentityTenantHeader, id.Tenant)
+	}
+	if len(id.Roles) != 0 {
+		r.Header.Set(IdentityRolesHeader, strings.Join(id.Roles, ","))
+	}
+	if id.KeyId != "" {
+		r.Header.Set(IdentityKeyIdHeader, id.KeyId)
+	}
+	r.Header.Set(IdentityTimestampHeader, timestamp)
+	r.Header.Set(IdentitySignatureHeader, identitySignature(r, id, timestamp, secret))
+	return nil
+}
+
+// VerifyIdentityHeaders parses the identity headers injected by the gateway
+// and verifies their signature using the shared secret, a validity greater
+// than zero additionally rejects identities signed longer ago than that.
+func VerifyIdentityHeaders(r *http.Request, secret string, validity time.Duration) (*Identity, error) {
+	sig := r.Header.Get(IdentitySignatureHeader)
+	subject := r.Header.Get(IdentitySubjectHeader)
+	timestamp := r.Header.Get(IdentityTimestampHeader)
+	if sig == "" || subject == "" || timestamp == "" {
+		return nil, errors.Wrapf(errors.NotFound, "identity headers not available in the http request")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "invalid identity timestamp: %s", timestamp)
+	}
+	if validity > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > validity || age < -validity {
+			return nil, errors.Wrapf(errors.Unauthorized, "identity headers expired")
+		}
+	}
+
+	id := &Identity{
+		Subject: subject,
+		Tenant:  r.Header.Get(IdentityTenantHeader),
+		KeyId:   r.Header.Get(IdentityKeyIdHeader),
+	}
+	if roles := r.Header.Get(IdentityRolesHeader); roles != "" {
+		id.Roles = strings.Split(roles, ",")
+	}
+
+	expected := identitySignature(r, id, timestamp, secret)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(sig))) {
+		return nil, errors.Wrapf(errors.Unauthorized, "invalid identity signature")
+	}
+	return id, nil
+}
+
+// DeleteIdentityHeaders removes all the identity headers from the request,
+// to be used by the gateway on inbound requests so that callers cannot
+// spoof an identity.
+func DeleteIdentityHeaders(r *http.Request) {
+	for _, h := range identityHeaders {
+		r.Header.Del(h)
+	}
+}
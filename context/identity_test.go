@@ -0,0 +1,48 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package context
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func Test_IdentityHeaders(t *testing.T) {
+	r, _ := http.NewRequest(http.MethodGet, "http://backend/api/v1/items", nil)
+	r.Header.Set(IdentityTenantHeader, "spoofed")
+	id := &Identity{
+		Subject: "alice",
+		Tenant:  "acme",
+		Roles:   []string{"admin", "viewer"},
+		KeyId:   "key-1",
+	}
+	if err := SetIdentityHeaders(r, id, "shared"); err != nil {
+		t.Fatalf("failed to set identity headers: %s", err)
+	}
+
+	found, err := VerifyIdentityHeaders(r, "shared", time.Minute)
+	if err != nil {
+		t.Fatalf("failed to verify identity headers: %s", err)
+	}
+	if found.Subject != "alice" || found.Tenant != "acme" || len(found.Roles) != 2 || found.KeyId != "key-1" {
+		t.Errorf("unexpected identity %+v", found)
+	}
+
+	if _, err := VerifyIdentityHeaders(r, "other", time.Minute); !errors.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized with a different secret, got %v", err)
+	}
+
+	r.Header.Set(IdentityRolesHeader, "admin,root")
+	if _, err := VerifyIdentityHeaders(r, "shared", time.Minute); !errors.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized for tampered roles, got %v", err)
+	}
+
+	DeleteIdentityHeaders(r)
+	if _, err := VerifyIdentityHeaders(r, "shared", time.Minute); !errors.IsNotFound(err) {
+		t.Errorf("expected not found without identity headers, got %v", err)
+	}
+}
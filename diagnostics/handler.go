@@ -0,0 +1,73 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package diagnostics
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+/*
+Package diagnostics provides an optional handler exposing the Go runtime
+profiling (pprof) and metrics (expvar) endpoints, restricted to callers
+authenticated for the root tenancy, so operators can profile a gateway
+safely in production.
+
+The caller identity is taken from the AuthInfo attached to the request
+context (see context.ContextWithAuthInfo), falling back to the Auth-Info
+header added by the auth gateway. The handler must therefore only be
+reachable through the auth gateway or behind a middleware populating the
+AuthInfo, never directly.
+
+# Usage
+
+    mux := http.NewServeMux()
+    mux.Handle("/debug/", diagnostics.NewHandler())
+
+# Endpoints
+
+  - /debug/pprof/           index of the available profiles
+  - /debug/pprof/cmdline    command line of the running program
+  - /debug/pprof/profile    CPU profile
+  - /debug/pprof/symbol     symbol lookup
+  - /debug/pprof/trace      execution trace
+  - /debug/vars             expvar metrics including runtime memstats
+*/
+
+// NewHandler returns an http.Handler serving the pprof and expvar endpoints
+// under /debug/, accessible only to root tenancy callers.
+func NewHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return RequireRoot(mux)
+}
+
+// RequireRoot wraps the handler allowing only callers authenticated for the
+// root tenancy, other callers are rejected with 401 if no auth info is
+// available and with 403 otherwise.
+func RequireRoot(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := authctx.GetAuthInfoFromContext(r.Context())
+		if err != nil {
+			info, err = authctx.GetAuthInfoHeader(r)
+			if err != nil {
+				http.Error(w, "authentication required", http.StatusUnauthorized)
+				return
+			}
+		}
+		if !info.IsRoot {
+			http.Error(w, "root access required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
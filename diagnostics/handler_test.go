@@ -0,0 +1,47 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package diagnostics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+func TestHandlerRequiresRoot(t *testing.T) {
+	handler := NewHandler()
+
+	req := httptest.NewRequest("GET", "/debug/vars", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without auth info, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/debug/vars", nil)
+	_ = authctx.SetAuthInfoHeader(req, &authctx.AuthInfo{UserName: "user"})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 for non root caller, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/debug/vars", nil)
+	_ = authctx.SetAuthInfoHeader(req, &authctx.AuthInfo{UserName: "admin", IsRoot: true})
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for root caller, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/debug/pprof/", nil)
+	req = req.WithContext(authctx.ContextWithAuthInfo(req.Context(), &authctx.AuthInfo{UserName: "admin", IsRoot: true}))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for root caller from context, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/*
+Package events notifies the changes of the auth resources, the routes added,
+updated and deleted, the API keys created, rotated, disabled, enabled and
+deleted, and the lockouts, enabling the consumers to invalidate their caches
+or to raise alerts.
+
+The route.RouteTable, apikey.Store and apikey.Lockout publish the events to
+the Publisher set with their SetPublisher. The Bus fans the events out to
+the subscriptions of the process, delivered over channels, and optionally
+appends them to an Outbox persisted in the database, from which the other
+processes poll the events they missed.
+
+# Usage
+
+    outbox, _ := events.NewStoreOutbox(dbStore)
+    bus := events.NewBus(events.WithOutbox(outbox))
+    routeTable.SetPublisher(bus)
+    keyStore.SetPublisher(bus)
+
+    sub := bus.Subscribe(64, events.KindKeyDisabled, events.KindKeyDeleted)
+    defer sub.Close()
+    for e := range sub.C {
+        secrets.Invalidate(e.Resource)
+    }
+*/
+
+// Kind identifies the change notified by the event.
+type Kind string
+
+const (
+	KindRouteAdded   Kind = "route.added"
+	KindRouteUpdated Kind = "route.updated"
+	KindRouteDeleted Kind = "route.deleted"
+
+	// routes of a provider reconciled with its inventory, the resource
+	// being the provider
+	KindRoutesSynced Kind = "route.synced"
+
+	KindKeyCreated  Kind = "key.created"
+	KindKeyRotated  Kind = "key.rotated"
+	KindKeyDisabled Kind = "key.disabled"
+	KindKeyEnabled  Kind = "key.enabled"
+	KindKeyDeleted  Kind = "key.deleted"
+
+	// API key or source address locked after repeated authentication
+	// failures, the resource being the locked subject
+	KindLockout Kind = "lockout"
+)
+
+// Event is the notification of a change.
+type Event struct {
+	// unique identifier, ordered by the time of publication
+	Id string `json:"id" bson:"id,omitempty"`
+
+	Kind      Kind      `json:"kind" bson:"kind,omitempty"`
+	Timestamp time.Time `json:"timestamp" bson:"timestamp,omitempty"`
+
+	// tenant of the resource, empty for the shared resources
+	Tenant string `json:"tenant,omitempty" bson:"tenant,omitempty"`
+
+	// resource changed, e.g. "GET /books" for the routes, the key
+	// identifier for the API keys
+	Resource string `json:"resource,omitempty" bson:"resource,omitempty"`
+}
+
+// newEventId returns an identifier ordered by the time.
+func newEventId(t time.Time) string {
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%019d-%s", t.UnixNano(), hex.EncodeToString(b))
+}
+
+// stamp sets the identifier and timestamp of the event, if not set.
+func (e *Event) stamp() {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if e.Id == "" {
+		e.Id = newEventId(e.Timestamp)
+	}
+}
+
+// Publisher publishes the events.
+type Publisher interface {
+	// Publish publishes the event, without blocking on the consumers.
+	Publish(ctx context.Context, e *Event) error
+}
+
+// PublisherFunc is an adapter allowing the use of an ordinary function as
+// a Publisher.
+type PublisherFunc func(ctx context.Context, e *Event) error
+
+// Publish calls f(ctx, e).
+func (f PublisherFunc) Publish(ctx context.Context, e *Event) error {
+	return f(ctx, e)
+}
+
+// Subscription receives the events of a Bus over its channel.
+type Subscription struct {
+	// C delivers the events, closed once the subscription is closed
+	C <-chan *Event
+
+	ch      chan *Event
+	kinds   map[Kind]bool
+	bus     *Bus
+	dropped atomic.Uint64
+	closed  bool
+}
+
+// Dropped returns the number of events dropped as the channel was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close unsubscribes, closing the channel.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	delete(s.bus.subs, s)
+	close(s.ch)
+}
+
+// BusOption configures the Bus.
+type BusOption func(*Bus)
+
+// WithOutbox appends the events published to the outbox, before they are
+// delivered to the subscriptions.
+func WithOutbox(outbox Publisher) BusOption {
+	return func(b *Bus) {
+		b.outbox = outbox
+	}
+}
+
+// Bus delivers the events published to its subscriptions, see the package
+// documentation. A Bus is safe for concurrent use.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	outbox Publisher
+}
+
+// NewBus creates the bus.
+func NewBus(opts ...BusOption) *Bus {
+	b := &Bus{subs: map[*Subscription]struct{}{}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Subscribe subscribes to the events of the kinds, all if none, buffering
+// up to buffer events. The events published while the buffer is full are
+// dropped for the subscription, see Subscription.Dropped.
+func (b *Bus) Subscribe(buffer int, kinds ...Kind) *Subscription {
+	ch := make(chan *Event, max(buffer, 0))
+	s := &Subscription{C: ch, ch: ch, bus: b}
+	if len(kinds) != 0 {
+		s.kinds = map[Kind]bool{}
+		for _, k := range kinds {
+			s.kinds[k] = true
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Publish appends the event to the outbox, if any, and delivers it to the
+// subscriptions, failing only if the outbox fails, in which case the event
+// is delivered regardless.
+func (b *Bus) Publish(ctx context.Context, e *Event) error {
+	e.stamp()
+	var err error
+	if b.outbox != nil {
+		err = b.outbox.Publish(ctx, e)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for s := range b.subs {
+		if s.kinds != nil && !s.kinds[e.Kind] {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			s.dropped.Add(1)
+		}
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestBus(t *testing.T) {
+	outbox := []*Event{}
+	fail := false
+	bus := NewBus(WithOutbox(PublisherFunc(func(ctx context.Context, e *Event) error {
+		if fail {
+			return fmt.Errorf("outbox unavailable")
+		}
+		outbox = append(outbox, e)
+		return nil
+	})))
+	ctx := context.Background()
+
+	all := bus.Subscribe(8)
+	keys := bus.Subscribe(1, KindKeyDisabled, KindKeyDeleted)
+
+	_ = bus.Publish(ctx, &Event{Kind: KindRouteAdded, Resource: "GET /books"})
+	_ = bus.Publish(ctx, &Event{Kind: KindKeyDisabled, Tenant: "acme", Resource: "k1"})
+	_ = bus.Publish(ctx, &Event{Kind: KindKeyDeleted, Tenant: "acme", Resource: "k1"})
+
+	if len(all.C) != 3 || len(outbox) != 3 {
+		t.Fatalf("expected all the events to be delivered, got %d and %d", len(all.C), len(outbox))
+	}
+	e := <-all.C
+	if e.Id == "" || e.Timestamp.IsZero() || e.Kind != KindRouteAdded {
+		t.Errorf("unexpected event %+v", e)
+	}
+	if e2 := <-all.C; e2.Id <= e.Id {
+		t.Errorf("expected event ids to be ordered, got %s after %s", e2.Id, e.Id)
+	}
+	if e := <-keys.C; e.Kind != KindKeyDisabled || e.Resource != "k1" {
+		t.Errorf("unexpected filtered event %+v", e)
+	}
+	if keys.Dropped() != 1 {
+		t.Errorf("expected the event beyond the buffer to be dropped, got %d", keys.Dropped())
+	}
+
+	// the subscriptions receive the events the outbox failed to append
+	fail = true
+	if err := bus.Publish(ctx, &Event{Kind: KindLockout}); err == nil {
+		t.Errorf("expected the outbox failure to be returned")
+	}
+	keys.Close()
+	keys.Close()
+	if _, ok := <-keys.C; ok {
+		t.Errorf("expected the channel to be closed")
+	}
+	_ = bus.Publish(ctx, &Event{Kind: KindKeyDeleted})
+	if len(all.C) != 3 {
+		t.Errorf("expected the remaining subscription to receive the events, got %d", len(all.C))
+	}
+}
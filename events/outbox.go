@@ -0,0 +1,86 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+)
+
+// Collection name within the database the consumer supplies via db.Store.
+const OutboxCollection = "auth_events"
+
+// OutboxKey identifies a stored event.
+type OutboxKey struct {
+	Id string `bson:"id,omitempty"`
+}
+
+// storedEvent is the event as stored.
+type storedEvent struct {
+	Key   *OutboxKey `bson:"key,omitempty"`
+	Event `bson:",inline"`
+}
+
+// Outbox persists the events in the database, for the processes to poll
+// the events published by the other ones, resuming from the identifier of
+// the last event they processed.
+type Outbox struct {
+	table *table.Table[OutboxKey, storedEvent]
+}
+
+// NewStoreOutbox creates the outbox in the database supplied by the
+// consumer.
+func NewStoreOutbox(store db.Store) (*Outbox, error) {
+	if store == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "events: db store is required")
+	}
+	tbl := &table.Table[OutboxKey, storedEvent]{}
+	if err := tbl.Initialize(store.GetCollection(OutboxCollection)); err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "events: failed to initialize outbox table: %s", err)
+	}
+	return &Outbox{table: tbl}, nil
+}
+
+// Publish appends the event to the outbox.
+func (o *Outbox) Publish(ctx context.Context, e *Event) error {
+	e.stamp()
+	entry := &storedEvent{Key: &OutboxKey{Id: e.Id}, Event: *e}
+	return o.table.Insert(ctx, entry.Key, entry)
+}
+
+// Poll returns up to limit events published after the event identifier,
+// all the events if empty, in the order of their publication. A zero limit
+// returns all the events.
+func (o *Outbox) Poll(ctx context.Context, after string, limit int32) ([]*Event, error) {
+	filter := bson.D{{Key: "_id.id", Value: bson.M{"$gt": after}}}
+	list, err := o.table.FindManyWithOpts(ctx, filter,
+		table.WithLimit(limit),
+		table.WithSort(table.SortOption{Field: "_id.id", Direction: table.SortAscending}),
+	)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	events := make([]*Event, 0, len(list))
+	for _, entry := range list {
+		e := entry.Event
+		events = append(events, &e)
+	}
+	return events, nil
+}
+
+// Purge removes the events published before the time, e.g. periodically
+// to bound the size of the outbox.
+func (o *Outbox) Purge(ctx context.Context, before time.Time) error {
+	_, err := o.table.DeleteByFilter(ctx, bson.D{{Key: "timestamp", Value: bson.M{"$lt": before}}})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"context"
+	"net/http"
+
+	coreerrors "github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/apikey"
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/plugins"
+	"github.com/go-core-stack/auth/route"
+)
+
+// KeyStoreAuthenticator returns the authenticator validating the signed
+// requests with the keys of the store, enforcing the same controls as
+// apikey.Store.Middleware configured with the options: all the valid
+// generations of the secrets, the disabled and expired keys, the lockout,
+// the network policy and the impersonation grants of the keys, along with
+// the tenancy of the routes and the scopes of the keys once the route is
+// resolved by the Gateway. The caller is identified by the auth context of
+// the key, see apikey.Key.AuthContext, carrying its tenant. Locked keys and
+// addresses are rejected with 403 and a Retry-After hint. Requests
+// exceeding the latency budget of a stage, see apikey.WithBudget, are
+// rejected with 503. The keys of the streaming connections are
+// re-validated as per the StreamPolicy of the route, see
+// apikey.Authenticator.Revalidate.
+func KeyStoreAuthenticator(store *apikey.Store, v hash.Validator, opts ...apikey.MiddlewareOption) plugins.Authenticator {
+	a := store.Authenticator(v, opts...)
+	return plugins.AuthenticatorFunc(func(r *http.Request) (*authctx.Identity, error) {
+		k, authCtx, err := a.Authenticate(r)
+		if err != nil {
+			return nil, err
+		}
+		if st := authStateOf(r); st != nil {
+			st.key, st.authCtx = k, authCtx
+			st.authorize = func(rt *route.Route) error {
+				return a.Authorize(r, k, authCtx, rt)
+			}
+			st.revalidate = func(ctx context.Context, rt *route.Route) error {
+				return a.Revalidate(ctx, r, k, authCtx, rt)
+			}
+		}
+		return authCtx.Identity(), nil
+	})
+}
+
+// authStatus returns the status rejecting the requests failing
+// authentication with the error, setting the Retry-After hint of the
+// locked keys.
+func authStatus(w http.ResponseWriter, err error) int {
+	switch e := err.(type) {
+	case *apikey.LockedError:
+		w.Header().Set("Retry-After", apikey.RetryAfter(e))
+		return http.StatusForbidden
+	case *apikey.BudgetExceededError:
+		return http.StatusServiceUnavailable
+	}
+	if coreerrors.IsForbidden(err) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
@@ -0,0 +1,97 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-core-stack/auth/internal/lru"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// RouteResolver resolves the route of a request for a tenant, implemented
+// by the route.RouteTable.
+type RouteResolver interface {
+	ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error)
+}
+
+// MethodLister lists the methods of the routes registered with a url,
+// implemented by the route.RouteTable. The gateway answers the requests
+// for the other methods of a known url with 405 and the Allow header when
+// its RouteResolver implements it, and with 404 otherwise.
+type MethodLister interface {
+	GetAllowedMethods(ctx context.Context, url string) ([]route.MethodType, error)
+}
+
+// routeCacheKey identifies a resolved route.
+type routeCacheKey struct {
+	tenant string
+	method route.MethodType
+	path   string
+}
+
+// cachedRoute is a resolved route along with its expiry.
+type cachedRoute struct {
+	route  *route.Route
+	expiry time.Time
+}
+
+// routeCache caches the routes resolved by the underlying resolver, so
+// that the route table is not queried for every proxied request. Failures
+// are not cached.
+type routeCache struct {
+	base    RouteResolver
+	ttl     time.Duration
+	cache   *lru.Cache[routeCacheKey, cachedRoute]
+	metrics telemetry.Metrics
+}
+
+// newRouteCache returns the resolver caching the routes resolved by base,
+// or base itself if caching is disabled.
+func newRouteCache(base RouteResolver, size int, ttl time.Duration, metrics telemetry.Metrics) RouteResolver {
+	if ttl <= 0 {
+		return base
+	}
+	return &routeCache{
+		base:    base,
+		ttl:     ttl,
+		cache:   lru.New[routeCacheKey, cachedRoute](size),
+		metrics: metrics,
+	}
+}
+
+// ResolveTenantRoute returns the cached route, resolving it if not cached
+// or expired.
+func (c *routeCache) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	key := routeCacheKey{tenant: tenant, method: method, path: path}
+	now := time.Now()
+	if e, ok := c.cache.Get(key); ok && now.Before(e.expiry) {
+		c.recordLookup(true)
+		return e.route, nil
+	}
+	c.recordLookup(false)
+	r, err := c.base.ResolveTenantRoute(ctx, tenant, method, path)
+	if err != nil {
+		c.cache.Remove(key)
+		return nil, err
+	}
+	if c.cache.Add(key, cachedRoute{route: r, expiry: now.Add(c.ttl)}) && c.metrics != nil {
+		c.metrics.RecordCacheEviction(telemetry.CacheRoutes)
+	}
+	return r, nil
+}
+
+// recordLookup records the lookup of the route cache, if enabled.
+func (c *routeCache) recordLookup(hit bool) {
+	if c.metrics != nil {
+		c.metrics.RecordCacheLookup(telemetry.CacheRoutes, hit)
+	}
+}
+
+// purge removes all the cached routes.
+func (c *routeCache) purge() {
+	c.cache.Purge()
+}
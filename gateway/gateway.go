@@ -0,0 +1,474 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+
+	coreerrors "github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/audit"
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+	"github.com/go-core-stack/auth/plugins"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/token"
+)
+
+/*
+Package gateway provides the auth gateway, an http.Handler authenticating
+the inbound requests, resolving their route from the route table, enforcing
+the tenancy and RBAC of the route and reverse proxying the requests to the
+Endpoint of the route.
+
+For every request the gateway:
+  - authenticates the caller, e.g. validating the HMAC signature with the
+    API keys using KeyStoreAuthenticator, enforcing the controls of the
+    keys, public routes are reachable without authentication
+  - resolves the route for the tenant of the caller, cached for a short
+    while to keep the route table off the request path, answering 405 with
+    the Allow header for the methods not registered for a known url
+  - authorizes the caller as per route.Route.Authorize
+  - enforces the lifecycle flags and selects the endpoint of the requested
+    API version, balancing the requests across the healthy replicas
+  - allows the WebSocket upgrades and server sent events only for the
+    routes enabling them, bounding the connections as per the
+    route.StreamPolicy and re-validating the credentials of the caller
+    periodically if configured
+  - applies the header policy of the route, injects the signed identity
+    headers of the caller, see WithIdentitySecret, strips the signature
+    of the caller and optionally re-signs the request with the service
+    credentials of the gateway, before proxying it to the endpoint within
+    the upstream timeout of the route, retrying as per its
+    route.UpstreamPolicy, sending a copy to the mirrors of the route
+    sampling it, without the credentials of the caller
+
+# Usage
+
+    tbl, _ := route.NewRouteTable(client)
+    gw, err := gateway.New(gateway.KeyStoreAuthenticator(keys, validator), tbl,
+        gateway.WithAuthorizer(rbacStore),
+        gateway.WithServiceCredentials(hash.EnvCredentials("GW_KEY_ID", "GW_SECRET")))
+    http.ListenAndServe(":8080", gw)
+
+    // users presenting OIDC bearer tokens along with the HMAC machine traffic
+    oidc, _ := token.NewOIDCVerifier(ctx, "https://accounts.example.com", clientId)
+    auth := gateway.CompositeAuthenticator(gateway.BearerAuthenticator(oidc),
+        gateway.KeyStoreAuthenticator(keys, validator))
+*/
+
+// Gateway authenticates, authorizes and proxies the requests to the
+// endpoints of their routes.
+type Gateway struct {
+	auth    plugins.Authenticator
+	routes  RouteResolver
+	methods MethodLister
+	opts    *options
+	signer  hash.Generator
+	proxy   *httputil.ReverseProxy
+
+	// slots of the mirrored requests in flight
+	mirrors chan struct{}
+}
+
+// New returns a Gateway authenticating the callers with auth and resolving
+// the routes using routes, typically the route.RouteTable.
+func New(auth plugins.Authenticator, routes RouteResolver, opts ...Option) (*Gateway, error) {
+	if auth == nil {
+		return nil, coreerrors.Wrapf(coreerrors.InvalidArgument, "gateway authenticator not specified")
+	}
+	if routes == nil {
+		return nil, coreerrors.Wrapf(coreerrors.InvalidArgument, "gateway route resolver not specified")
+	}
+	o := newOptions(opts...)
+	if o.balancer == nil {
+		o.balancer = route.NewBalancer(route.RoundRobin, o.health)
+	}
+	methods, _ := routes.(MethodLister)
+	g := &Gateway{
+		methods: methods,
+		auth:    auth,
+		routes:  newRouteCache(routes, o.cacheSize, o.cacheTTL, o.metrics),
+		opts:    o,
+		mirrors: make(chan struct{}, maxMirrorsInFlight),
+	}
+	if o.service != nil {
+		g.signer = hash.NewGeneratorWithProvider(o.service, o.signing...)
+	}
+	g.proxy = &httputil.ReverseProxy{
+		Rewrite:        g.rewrite,
+		Transport:      &retryTransport{base: o.transport},
+		ModifyResponse: g.modifyResponse,
+		ErrorHandler:   g.proxyError,
+	}
+	return g, nil
+}
+
+// HMACAuthenticator returns the authenticator validating the HMAC signed
+// requests with the secrets of the resolver, identifying the caller by
+// its API key only, without tenant or roles. The keys of an apikey.Store
+// are authenticated with KeyStoreAuthenticator instead, enforcing their
+// controls.
+func HMACAuthenticator(v hash.Validator, secrets hash.SecretResolver) plugins.Authenticator {
+	return plugins.AuthenticatorFunc(func(r *http.Request) (*authctx.Identity, error) {
+		keyId := v.GetKeyId(r)
+		if keyId == "" {
+			return nil, coreerrors.Wrapf(coreerrors.Unauthorized, "api key id not available in the request")
+		}
+		secret, err := secrets.GetSecret(r.Context(), keyId)
+		if err != nil {
+			return nil, coreerrors.Wrapf(coreerrors.Unauthorized, "failed to resolve secret of api key %s: %s", keyId, err)
+		}
+		if ok, err := v.Validate(r, secret); !ok {
+			return nil, coreerrors.Wrapf(coreerrors.Unauthorized, "validation failed: %s", err)
+		}
+		return &authctx.Identity{Subject: keyId, KeyId: keyId}, nil
+	})
+}
+
+// TokenVerifier verifies bearer tokens, implemented by token.Verifier for
+// the tokens minted by this module and token.OIDCVerifier for the tokens
+// of an OpenID Connect provider.
+type TokenVerifier interface {
+	Verify(token string) (*token.Claims, error)
+}
+
+// BearerAuthenticator returns the authenticator verifying the bearer token
+// of the Authorization header, identifying the caller by its claims. The
+// tokens of the streaming connections are verified again as per the
+// StreamPolicy of the route.
+func BearerAuthenticator(v TokenVerifier) plugins.Authenticator {
+	return plugins.AuthenticatorFunc(func(r *http.Request) (*authctx.Identity, error) {
+		scheme, tok, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") {
+			return nil, coreerrors.Wrapf(coreerrors.Unauthorized, "bearer token not available in the request")
+		}
+		c, err := v.Verify(strings.TrimSpace(tok))
+		if err == nil && c.Cnf != nil {
+			err = c.Cnf.Verify(r)
+		}
+		if err != nil {
+			return nil, coreerrors.Wrapf(coreerrors.Unauthorized, "token validation failed: %s", err)
+		}
+		if st := authStateOf(r); st != nil {
+			// the token of a streaming connection is verified again, e.g.
+			// failing once expired
+			st.revalidate = func(ctx context.Context, rt *route.Route) error {
+				if _, err := v.Verify(strings.TrimSpace(tok)); err != nil {
+					return coreerrors.Wrapf(coreerrors.Unauthorized, "token validation failed: %s", err)
+				}
+				return nil
+			}
+		}
+		return c.AuthContext().Identity(), nil
+	})
+}
+
+// CompositeAuthenticator returns the authenticator accepting either a
+// bearer token, e.g. the OIDC tokens of the users, or an HMAC signature,
+// e.g. of the machine traffic, selected by the Authorization header of the
+// request, so that both result in the same AuthContext.
+func CompositeAuthenticator(bearer, hmac plugins.Authenticator) plugins.Authenticator {
+	return plugins.AuthenticatorFunc(func(r *http.Request) (*authctx.Identity, error) {
+		scheme, _, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if strings.EqualFold(scheme, "Bearer") {
+			return bearer.Authenticate(r)
+		}
+		return hmac.Authenticate(r)
+	})
+}
+
+// InvalidateRoutes drops the cached routes, e.g. after the route table is
+// updated, otherwise changes take effect once the cached routes expire.
+func (g *Gateway) InvalidateRoutes() {
+	if c, ok := g.routes.(*routeCache); ok {
+		c.purge()
+	}
+}
+
+// struct identifier for the context
+type proxiedRoute struct{}
+
+// authState is the state of the authentication of a request set by the
+// authenticators of the package, for the gateway to enforce the controls
+// depending on the route once resolved.
+type authState struct {
+	key     *apikey.Key
+	authCtx *model.AuthContext
+
+	// authorize enforces the controls of the credentials for the route
+	authorize func(rt *route.Route) error
+
+	// revalidate re-validates the credentials of a streaming connection
+	revalidate func(ctx context.Context, rt *route.Route) error
+}
+
+// struct identifier for the context
+type authStateSlot struct{}
+
+// withAuthState returns the request carrying an empty authState, filled by
+// the authenticators of the package.
+func withAuthState(r *http.Request) (*http.Request, *authState) {
+	st := &authState{}
+	return r.WithContext(context.WithValue(r.Context(), authStateSlot{}, st)), st
+}
+
+// authStateOf returns the authState of the request, nil if not served by
+// the gateway.
+func authStateOf(r *http.Request) *authState {
+	st, _ := r.Context().Value(authStateSlot{}).(*authState)
+	return st
+}
+
+// ServeHTTP authenticates, authorizes and proxies the request.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	rec := audit.NewRequestRecord(audit.KindAuthorization, r)
+
+	r, st := withAuthState(r)
+	id, authErr := g.auth.Authenticate(r)
+	if authErr == nil && id != nil {
+		rec.KeyId, rec.Subject, rec.Tenant = id.KeyId, id.Subject, id.Tenant
+	}
+	tenant := ""
+	if id != nil {
+		tenant = id.Tenant
+	}
+	var rt *route.Route
+	method, err := route.ParseMethod(r.Method)
+	if err == nil {
+		rt, err = g.routes.ResolveTenantRoute(ctx, tenant, method, r.URL.Path)
+	} else {
+		err = coreerrors.Wrapf(coreerrors.NotFound, "no route for method %s", r.Method)
+	}
+	switch {
+	case authErr != nil && (err != nil || rt.IsPublic == nil || !*rt.IsPublic):
+		// never reveal the routes to the callers failing authentication
+		rec.Kind = audit.KindAuthentication
+		status := authStatus(w, authErr)
+		message := "authentication failed"
+		switch status {
+		case http.StatusForbidden:
+			message = authErr.Error()
+		case http.StatusServiceUnavailable:
+			message = "service unavailable"
+		}
+		g.reject(w, r, rec.Deny(authErr.Error()), message, status)
+		return
+	case coreerrors.IsNotFound(err):
+		if allow := g.allowedMethods(ctx, r.URL.Path); allow != "" {
+			w.Header().Set("Allow", allow)
+			g.reject(w, r, rec, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		g.reject(w, r, rec, "route not found", http.StatusNotFound)
+		return
+	case err == route.ErrNoHealthyEndpoint:
+		g.reject(w, r, rec, "no healthy endpoint", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		g.reject(w, r, rec, "failed to resolve route", http.StatusInternalServerError)
+		return
+	}
+	if authErr != nil {
+		id = nil
+	}
+	rec.Route, rec.Resource, rec.Verb = rt.Key.Url, rt.Resource, rt.Verb
+
+	if id != nil && st.authorize != nil {
+		if err := st.authorize(rt); err != nil {
+			g.reject(w, r, rec.Deny(err.Error()), err.Error(), http.StatusForbidden)
+			return
+		}
+	}
+	if err := rt.Authorize(ctx, g.opts.authorizer, id); err != nil {
+		status := http.StatusForbidden
+		if coreerrors.IsUnauthorized(err) {
+			status = http.StatusUnauthorized
+		}
+		g.reject(w, r, rec.Deny(err.Error()), http.StatusText(status), status)
+		return
+	}
+	if status := rt.Lifecycle.Apply(w.Header()); status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	streaming := route.IsStreamingRequest(r)
+	if streaming && !rt.Stream.Allows() {
+		g.reject(w, r, rec.Deny("streaming not enabled for the route"), "streaming not enabled for the route", http.StatusForbidden)
+		return
+	}
+	endpoint, err := g.opts.balancer.Pick(rt, route.RequestedVersion(r))
+	switch {
+	case err == route.ErrNoHealthyEndpoint:
+		g.reject(w, r, rec, "no healthy endpoint", http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, "api version not available", http.StatusNotFound)
+		return
+	}
+	target, err := url.Parse(endpoint)
+	if err != nil || target.Host == "" {
+		http.Error(w, "invalid route endpoint", http.StatusBadGateway)
+		return
+	}
+	if g.opts.audit != nil {
+		g.opts.audit.Emit(ctx, rec.Allow())
+	}
+	if !streaming {
+		g.mirror(r, rt)
+	}
+
+	switch {
+	case id != nil && st.authCtx != nil:
+		ctx = model.WithAuthContext(apikey.ContextWithKey(ctx, st.key), st.authCtx)
+	case id != nil:
+		ctx = model.WithAuthContext(ctx, model.FromIdentity(id))
+	}
+	t := &proxyTarget{route: rt, endpoint: endpoint, url: target, id: id}
+	switch {
+	case streaming:
+		// the streaming connections are bounded by the stream policy
+		// rather than the upstream timeout
+		var cancel context.CancelFunc
+		ctx, cancel = streamContext(ctx, rt.Stream, rt, st)
+		defer cancel()
+		t.idle = rt.Stream.IdleTimeout
+	case rt.Upstream != nil && rt.Upstream.Timeout > 0:
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rt.Upstream.Timeout)
+		defer cancel()
+	}
+	ctx = context.WithValue(ctx, proxiedRoute{}, t)
+	g.proxy.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// proxyTarget is the route and endpoint a request is proxied to.
+type proxyTarget struct {
+	route    *route.Route
+	endpoint string
+	url      *url.URL
+	id       *authctx.Identity
+
+	// idle timeout of the streaming connection, zero for no limit
+	idle time.Duration
+}
+
+// allowedMethods returns the value of the Allow header listing the methods
+// of the routes registered with the url, empty if the url is not known or
+// the route resolver does not list them, see MethodLister.
+func (g *Gateway) allowedMethods(ctx context.Context, url string) string {
+	if g.methods == nil {
+		return ""
+	}
+	methods, err := g.methods.GetAllowedMethods(ctx, url)
+	if err != nil {
+		return ""
+	}
+	return route.AllowHeader(methods)
+}
+
+// reject rejects the request with the status, recording the denial.
+func (g *Gateway) reject(w http.ResponseWriter, r *http.Request, rec *audit.Record, reason string, status int) {
+	if g.opts.audit != nil {
+		if rec.Result != audit.ResultDenied {
+			rec.Deny(reason)
+		}
+		g.opts.audit.Emit(r.Context(), rec)
+	}
+	http.Error(w, reason, status)
+}
+
+// rewrite sets the endpoint of the route as the target of the outbound
+// request, applying the header policy and re-signing it if configured.
+func (g *Gateway) rewrite(pr *httputil.ProxyRequest) {
+	t := pr.In.Context().Value(proxiedRoute{}).(*proxyTarget)
+	pr.SetURL(t.url)
+	pr.SetXForwarded()
+
+	// identity asserted by the caller is never forwarded, nor its
+	// signature, the gateway signing the request with its own service
+	// credentials if configured
+	authctx.DeleteIdentityHeaders(pr.Out)
+	g.stripSignatureHeaders(pr.Out.Header)
+	var info *authctx.AuthInfo
+	if t.id != nil {
+		info = &authctx.AuthInfo{
+			Realm:    t.id.Tenant,
+			UserName: t.id.Subject,
+			Roles:    t.id.Roles,
+		}
+	}
+	if err := t.route.Headers.ApplyRequest(pr.Out, info); err != nil {
+		authctx.DeleteAuthInfoHeader(pr.Out)
+	}
+	if t.id != nil && g.opts.identitySecret != "" {
+		_ = authctx.SetIdentityHeaders(pr.Out, t.id, g.opts.identitySecret)
+	}
+	if g.signer != nil {
+		g.signer.AddAuthHeaders(pr.Out)
+	}
+}
+
+// stripSignatureHeaders removes the signature of the caller from the
+// headers: the HTTP message signature headers, and the HMAC headers along
+// with the nonce, session token and impersonation headers, named as by
+// default and as configured for re-signing.
+func (g *Gateway) stripSignatureHeaders(h http.Header) {
+	h.Del("Signature")
+	h.Del("Signature-Input")
+	for _, names := range []hash.HeaderNames{hash.DefaultHeaderNames(), hash.ResolveHeaderNames(g.opts.signing...)} {
+		for _, name := range []string{
+			names.Signature, names.Algorithm, names.Version, names.Timestamp, names.KeyId,
+			names.ContentSignature, names.Nonce, names.SessionToken,
+			names.ImpersonateUser, names.ImpersonateTenant,
+		} {
+			if name != "" {
+				h.Del(name)
+			}
+		}
+	}
+}
+
+// modifyResponse applies the header policy of the route to the response.
+func (g *Gateway) modifyResponse(resp *http.Response) error {
+	if t, ok := resp.Request.Context().Value(proxiedRoute{}).(*proxyTarget); ok {
+		t.route.Headers.ApplyResponse(resp.Header)
+		if t.idle > 0 {
+			resp.Body = newIdleBody(resp.Body, t.idle)
+		}
+		if g.opts.health != nil {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				g.opts.health.ReportFailure(t.endpoint, fmt.Errorf("endpoint responded %d", resp.StatusCode))
+			default:
+				g.opts.health.ReportSuccess(t.endpoint)
+			}
+		}
+	}
+	return nil
+}
+
+// proxyError responds with 504 if the upstream timeout of the route
+// elapsed, and 502 for the other failures reaching the endpoint.
+func (g *Gateway) proxyError(w http.ResponseWriter, r *http.Request, err error) {
+	// requests abandoned by the caller say nothing about the endpoint
+	if t, ok := r.Context().Value(proxiedRoute{}).(*proxyTarget); ok && g.opts.health != nil && !errors.Is(err, context.Canceled) {
+		g.opts.health.ReportFailure(t.endpoint, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, http.StatusText(http.StatusGatewayTimeout), http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+}
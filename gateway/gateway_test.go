@@ -0,0 +1,543 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/apikey"
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+	"github.com/go-core-stack/auth/token"
+)
+
+// fakeRoutes resolves the routes by url, counting the lookups
+type fakeRoutes struct {
+	routes  map[string]*route.Route
+	lookups atomic.Int32
+}
+
+func (f *fakeRoutes) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	f.lookups.Add(1)
+	if r, ok := f.routes[path]; ok {
+		return r, nil
+	}
+	return nil, errors.Wrapf(errors.NotFound, "no route found for %s", path)
+}
+
+func boolPtr(v bool) *bool { return &v }
+
+func newTestGateway(t *testing.T, backend string, opts ...Option) (*Gateway, *fakeRoutes) {
+	routes := &fakeRoutes{routes: map[string]*route.Route{
+		"/books":  {Key: &route.Key{Url: "/books"}, Endpoint: backend, Resource: "books", Verb: "get"},
+		"/public": {Key: &route.Key{Url: "/public"}, Endpoint: backend, IsPublic: boolPtr(true)},
+		"/gone":   {Key: &route.Key{Url: "/gone"}, Endpoint: backend, Lifecycle: &route.Lifecycle{Disabled: true}},
+	}}
+	secrets := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		if keyId == "client" {
+			return "client-secret", nil
+		}
+		return "", errors.Wrapf(errors.NotFound, "unknown key")
+	})
+	gw, err := New(HMACAuthenticator(hash.NewValidator(60), secrets), routes, opts...)
+	if err != nil {
+		t.Fatalf("failed to create gateway: %s", err)
+	}
+	return gw, routes
+}
+
+func signedRequest(path string) *http.Request {
+	r := httptest.NewRequest("GET", path, nil)
+	return hash.NewGenerator("client", "client-secret").AddAuthHeaders(r)
+}
+
+func TestGateway(t *testing.T) {
+	var received *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	allowBooks := rbac.AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+		if id.Subject == "client" && resource == "books" {
+			return nil
+		}
+		return errors.Wrapf(errors.Forbidden, "denied")
+	})
+	gw, routes := newTestGateway(t, backend.URL,
+		WithAuthorizer(allowBooks),
+		WithServiceCredentials(hash.StaticCredentials("gateway", "gateway-secret")),
+		WithIdentitySecret("identity-secret"))
+
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, signedRequest("/books"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if received.URL.Path != "/books" {
+		t.Errorf("unexpected proxied path %s", received.URL.Path)
+	}
+	if ok, err := hash.NewValidator(60).Validate(received, "gateway-secret"); !ok {
+		t.Errorf("expected request re-signed by the gateway: %s", err)
+	}
+	id, err := authctx.VerifyIdentityHeaders(received, "identity-secret", time.Minute)
+	if err != nil || id.Subject != "client" {
+		t.Errorf("expected identity headers of the caller, got %v, %v", id, err)
+	}
+
+	// resolved routes are cached
+	gw.ServeHTTP(httptest.NewRecorder(), signedRequest("/books"))
+	if n := routes.lookups.Load(); n != 1 {
+		t.Errorf("expected a single route lookup, got %d", n)
+	}
+
+	cases := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"unsigned", httptest.NewRequest("GET", "/books", nil), http.StatusUnauthorized},
+		{"unsigned unknown route", httptest.NewRequest("GET", "/unknown", nil), http.StatusUnauthorized},
+		{"unsigned public", httptest.NewRequest("GET", "/public", nil), http.StatusOK},
+		{"unknown route", signedRequest("/unknown"), http.StatusNotFound},
+		{"disabled", signedRequest("/gone"), http.StatusGone},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, c.req)
+		if w.Code != c.status {
+			t.Errorf("%s: expected %d, got %d", c.name, c.status, w.Code)
+		}
+	}
+}
+
+func TestGatewaySignatureHeaders(t *testing.T) {
+	signatureHeaders := []string{
+		"x-signature", "x-signature-alg", "x-signature-version", "x-timestamp",
+		"x-api-key-id", "x-nonce", "Signature", "Signature-Input",
+	}
+	tests := []struct {
+		name string
+		opts []Option
+
+		// headers of the signature of the gateway, if re-signed
+		resigned []string
+		keyId    string
+	}{
+		{
+			name: "no service credentials",
+		},
+		{
+			name:     "service credentials",
+			opts:     []Option{WithServiceCredentials(hash.StaticCredentials("gateway", "gateway-secret"))},
+			resigned: []string{"x-signature", "x-signature-alg", "x-signature-version", "x-timestamp", "x-api-key-id"},
+			keyId:    "x-api-key-id",
+		},
+		{
+			name:  "service credentials with header prefix",
+			opts:  []Option{WithServiceCredentials(hash.StaticCredentials("gateway", "gateway-secret"), hash.WithHeaderPrefix("x-gw-"))},
+			keyId: "x-gw-api-key-id",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var received *http.Request
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+			}))
+			defer backend.Close()
+
+			allowAll := rbac.AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+				return nil
+			})
+			gw, _ := newTestGateway(t, backend.URL, append(tc.opts, WithAuthorizer(allowAll))...)
+			r := httptest.NewRequest("GET", "/books", nil)
+			r.Header.Set("Signature", "sig1=:c2lnbmF0dXJl:")
+			r.Header.Set("Signature-Input", `sig1=("@method");keyid="client"`)
+			hash.NewGenerator("client", "client-secret", hash.WithNonce()).AddAuthHeaders(r)
+			w := httptest.NewRecorder()
+			gw.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+			}
+
+			// the signature of the caller is never forwarded
+			for _, name := range signatureHeaders {
+				if slices.Contains(tc.resigned, name) {
+					continue
+				}
+				if v := received.Header.Get(name); v != "" {
+					t.Errorf("expected %s not to be forwarded, got %q", name, v)
+				}
+			}
+			if tc.keyId != "" && received.Header.Get(tc.keyId) != "gateway" {
+				t.Errorf("expected the request signed by the gateway, got key id %q", received.Header.Get(tc.keyId))
+			}
+		})
+	}
+}
+
+func TestGatewayForbidden(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer backend.Close()
+
+	// without an authorizer, routes requiring RBAC are never reachable
+	gw, _ := newTestGateway(t, backend.URL)
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, signedRequest("/books"))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+}
+
+func TestGatewayUpstreamErrors(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	gw, routes := newTestGateway(t, slow.URL)
+	routes.routes["/slow"] = &route.Route{
+		Key:      &route.Key{Url: "/slow"},
+		Endpoint: slow.URL,
+		IsPublic: boolPtr(true),
+		Upstream: &route.UpstreamPolicy{Timeout: 20 * time.Millisecond},
+	}
+	routes.routes["/down"] = &route.Route{
+		Key:      &route.Key{Url: "/down"},
+		Endpoint: "http://127.0.0.1:1",
+		IsPublic: boolPtr(true),
+	}
+	for path, status := range map[string]int{"/slow": http.StatusGatewayTimeout, "/down": http.StatusBadGateway} {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != status {
+			t.Errorf("%s: expected %d, got %d", path, status, w.Code)
+		}
+	}
+}
+
+func TestCompositeAuthenticator(t *testing.T) {
+	key, _ := token.NewHMACKey("k1", []byte("0123456789abcdef0123456789abcdef"))
+	keys := token.NewKeySet()
+	_ = keys.Rotate(key)
+	issuer := token.NewIssuer("https://idp.example.com", keys, time.Minute)
+	secrets := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "client-secret", nil
+	})
+	auth := CompositeAuthenticator(
+		BearerAuthenticator(token.NewVerifier("https://idp.example.com", keys)),
+		HMACAuthenticator(hash.NewValidator(60), secrets))
+
+	tok, _ := issuer.Issue(&token.Claims{Subject: "alice", Tenant: "acme", Roles: []string{"reader"}})
+	r := httptest.NewRequest("GET", "/books", nil)
+	r.Header.Set("Authorization", "Bearer "+tok)
+	id, err := auth.Authenticate(r)
+	if err != nil || id.Subject != "alice" || id.Tenant != "acme" || id.KeyId != "" {
+		t.Errorf("expected bearer identity, got %+v, %v", id, err)
+	}
+
+	id, err = auth.Authenticate(signedRequest("/books"))
+	if err != nil || id.KeyId != "client" {
+		t.Errorf("expected hmac identity, got %+v, %v", id, err)
+	}
+
+	r.Header.Set("Authorization", "Bearer "+tok+"x")
+	if _, err := auth.Authenticate(r); !errors.IsUnauthorized(err) {
+		t.Errorf("expected invalid bearer token to be unauthorized, got %v", err)
+	}
+}
+
+func TestGatewayEndpointHealth(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer backend.Close()
+
+	health := route.NewHealthTracker(route.HealthPolicy{FailureThreshold: 2, RecoveryInterval: time.Hour})
+	gw, _ := newTestGateway(t, backend.URL, WithHealthTracker(health))
+
+	// failures reported passively until the endpoint is unhealthy
+	for range 2 {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, httptest.NewRequest("GET", "/public", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("expected 503 from the backend, got %d", w.Code)
+		}
+	}
+	if health.Healthy(backend.URL) {
+		t.Fatalf("expected endpoint marked unhealthy")
+	}
+
+	// requests are rejected without reaching the backend
+	status.Store(http.StatusOK)
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, httptest.NewRequest("GET", "/public", nil))
+	if w.Code != http.StatusServiceUnavailable || w.Body.String() != "no healthy endpoint\n" {
+		t.Errorf("expected 503 for unhealthy endpoint, got %d: %s", w.Code, w.Body)
+	}
+
+	health.ReportSuccess(backend.URL)
+	w = httptest.NewRecorder()
+	gw.ServeHTTP(w, httptest.NewRequest("GET", "/public", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 once recovered, got %d", w.Code)
+	}
+}
+
+func TestGatewayBalancing(t *testing.T) {
+	var hits [2]atomic.Int32
+	backends := make([]*httptest.Server, 2)
+	for i := range backends {
+		backends[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits[i].Add(1)
+		}))
+		defer backends[i].Close()
+	}
+
+	health := route.NewHealthTracker(route.HealthPolicy{FailureThreshold: 1})
+	gw, routes := newTestGateway(t, backends[0].URL, WithHealthTracker(health))
+	routes.routes["/replicated"] = &route.Route{
+		Key:       &route.Key{Url: "/replicated"},
+		Endpoint:  backends[0].URL,
+		Endpoints: []string{backends[1].URL},
+		IsPublic:  boolPtr(true),
+	}
+	for range 4 {
+		gw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/replicated", nil))
+	}
+	if hits[0].Load() != 2 || hits[1].Load() != 2 {
+		t.Errorf("expected requests spread across replicas, got %d and %d", hits[0].Load(), hits[1].Load())
+	}
+
+	health.ReportFailure(backends[0].URL, nil)
+	for range 2 {
+		gw.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/replicated", nil))
+	}
+	if hits[0].Load() != 2 || hits[1].Load() != 4 {
+		t.Errorf("expected unhealthy replica skipped, got %d and %d", hits[0].Load(), hits[1].Load())
+	}
+}
+
+func TestKeyStoreAuthenticator(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer backend.Close()
+
+	ctx := context.Background()
+	store, _ := apikey.NewStoreWithStorage(storage.NewMemoryTable[apikey.KeyId, apikey.Key](),
+		storage.NewMemoryTable[apikey.UsageKey, apikey.Usage](), make([]byte, 32))
+	acme, acmeSecret, _ := store.Create(ctx, &apikey.Key{Owner: "svc", Tenant: "acme"})
+	globex, globexSecret, _ := store.Create(ctx, &apikey.Key{Owner: "svc", Tenant: "globex"})
+	scoped, scopedSecret, _ := store.Create(ctx, &apikey.Key{Owner: "svc", Tenant: "acme", Scopes: []string{"route:GET /books"}})
+	disabled, disabledSecret, _ := store.Create(ctx, &apikey.Key{Owner: "svc", Tenant: "acme"})
+	_ = store.Disable(ctx, disabled.Key.Id)
+
+	routes := &fakeRoutes{routes: map[string]*route.Route{
+		"/books":  {Key: &route.Key{Url: "/books", Method: route.GET}, Endpoint: backend.URL},
+		"/orders": {Key: &route.Key{Url: "/orders", Method: route.GET, Tenant: "acme"}, Endpoint: backend.URL},
+	}}
+	v := hash.NewValidator(60)
+	auth := KeyStoreAuthenticator(store, v)
+	gw, err := New(auth, routes)
+	if err != nil {
+		t.Fatalf("failed to create gateway: %s", err)
+	}
+
+	id, err := auth.Authenticate(hash.NewGenerator(acme.Key.Id, acmeSecret).AddAuthHeaders(httptest.NewRequest("GET", "/orders", nil)))
+	if err != nil || id.Tenant != "acme" || id.Subject != "svc" || id.KeyId != acme.Key.Id {
+		t.Errorf("expected the identity of the key, got %+v, %v", id, err)
+	}
+
+	tests := []struct {
+		name   string
+		keyId  string
+		secret string
+		path   string
+		status int
+	}{
+		{"tenant route", acme.Key.Id, acmeSecret, "/orders", http.StatusOK},
+		{"route of another tenant", globex.Key.Id, globexSecret, "/orders", http.StatusForbidden},
+		{"shared route", globex.Key.Id, globexSecret, "/books", http.StatusOK},
+		{"route in scope", scoped.Key.Id, scopedSecret, "/books", http.StatusOK},
+		{"route out of scope", scoped.Key.Id, scopedSecret, "/orders", http.StatusForbidden},
+		{"disabled key", disabled.Key.Id, disabledSecret, "/books", http.StatusUnauthorized},
+		{"wrong secret", acme.Key.Id, globexSecret, "/books", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, hash.NewGenerator(tt.keyId, tt.secret).AddAuthHeaders(httptest.NewRequest("GET", tt.path, nil)))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, w.Code)
+		}
+	}
+
+	// the previous generation remains valid within the grace period
+	if _, err := store.Rotate(ctx, acme.Key.Id, time.Minute); err != nil {
+		t.Fatalf("failed to rotate key: %s", err)
+	}
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, hash.NewGenerator(acme.Key.Id, acmeSecret).AddAuthHeaders(httptest.NewRequest("GET", "/orders", nil)))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the previous generation to be valid, got %d", w.Code)
+	}
+
+	// the stages exceeding their latency budget are rejected with 503
+	lockout := apikey.NewLockout(apikey.LockoutPolicy{}, &slowLockoutStore{LockoutStore: apikey.NewMemoryLockoutStore()})
+	gw, _ = New(KeyStoreAuthenticator(store, v, apikey.WithLockout(lockout),
+		apikey.WithBudget(apikey.StagePolicy, apikey.Budget{Timeout: 10 * time.Millisecond})), routes)
+	w = httptest.NewRecorder()
+	gw.ServeHTTP(w, hash.NewGenerator(acme.Key.Id, acmeSecret).AddAuthHeaders(httptest.NewRequest("GET", "/orders", nil)))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected the slow lockout to be rejected, got %d", w.Code)
+	}
+}
+
+// slowLockoutStore delays the lockout checks.
+type slowLockoutStore struct {
+	apikey.LockoutStore
+}
+
+func (s *slowLockoutStore) Locked(ctx context.Context, subject string) (time.Time, error) {
+	time.Sleep(100 * time.Millisecond)
+	return s.LockoutStore.Locked(ctx, subject)
+}
+
+func TestGatewayMirror(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer backend.Close()
+	mirrored := make(chan *http.Request, 1)
+	bodies := make(chan string, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mirrored <- r
+		bodies <- string(body)
+		http.Error(w, "shadow failure", http.StatusInternalServerError)
+	}))
+	defer shadow.Close()
+
+	gw, routes := newTestGateway(t, backend.URL)
+	routes.routes["/orders"] = &route.Route{
+		Key:      &route.Key{Url: "/orders"},
+		Endpoint: backend.URL,
+		Mirrors:  []*route.Mirror{{Endpoint: shadow.URL + "/shadow", Percentage: 100}, {Endpoint: "http://unused", Percentage: 0}},
+	}
+
+	r := httptest.NewRequest("POST", "/orders?id=1", strings.NewReader(`{"book":"go"}`))
+	r.Header.Set("Authorization", "Basic c2VjcmV0")
+	hash.NewGenerator("client", "client-secret").AddAuthHeaders(r)
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, r)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected the response of the endpoint, got %d", w.Code)
+	}
+
+	select {
+	case m := <-mirrored:
+		if m.Method != "POST" || m.URL.Path != "/shadow/orders" || m.URL.RawQuery != "id=1" {
+			t.Errorf("unexpected mirrored request %s %s", m.Method, m.URL)
+		}
+		if body := <-bodies; body != `{"book":"go"}` {
+			t.Errorf("unexpected mirrored body %q", body)
+		}
+		names := hash.DefaultHeaderNames()
+		for _, h := range []string{"Authorization", names.KeyId, names.Signature, names.Timestamp} {
+			if m.Header.Get(h) != "" {
+				t.Errorf("expected %s to be stripped from the mirrored request", h)
+			}
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the request to be mirrored")
+	}
+
+	// routes without mirrors are not mirrored
+	w = httptest.NewRecorder()
+	gw.ServeHTTP(w, httptest.NewRequest("GET", "/public", nil))
+	select {
+	case <-mirrored:
+		t.Errorf("expected no request to be mirrored")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// methodRoutes resolves the routes by url and method, listing the methods
+// of the urls
+type methodRoutes struct {
+	routes map[string]map[route.MethodType]*route.Route
+}
+
+func (m *methodRoutes) ResolveTenantRoute(ctx context.Context, tenant string, method route.MethodType, path string) (*route.Route, error) {
+	if r, ok := m.routes[path][method]; ok {
+		return r, nil
+	}
+	return nil, errors.Wrapf(errors.NotFound, "no route found for %s", path)
+}
+
+func (m *methodRoutes) GetAllowedMethods(ctx context.Context, url string) ([]route.MethodType, error) {
+	methods := []route.MethodType{}
+	for method := range m.routes[url] {
+		methods = append(methods, method)
+	}
+	slices.Sort(methods)
+	return methods, nil
+}
+
+func TestGatewayMethodNotAllowed(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer backend.Close()
+	public := func(method route.MethodType) *route.Route {
+		return &route.Route{Key: &route.Key{Url: "/books", Method: method}, Endpoint: backend.URL, IsPublic: boolPtr(true)}
+	}
+	routes := &methodRoutes{routes: map[string]map[route.MethodType]*route.Route{
+		"/books": {route.GET: public(route.GET), route.POST: public(route.POST)},
+	}}
+	secrets := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "client-secret", nil
+	})
+	gw, err := New(HMACAuthenticator(hash.NewValidator(60), secrets), routes)
+	if err != nil {
+		t.Fatalf("failed to create gateway: %s", err)
+	}
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		status int
+		allow  string
+	}{
+		{"allowed", signedRequest("/books"), http.StatusOK, ""},
+		{"method not registered", hash.NewGenerator("client", "client-secret").AddAuthHeaders(httptest.NewRequest("DELETE", "/books", nil)), http.StatusMethodNotAllowed, "GET, POST"},
+		{"unknown method", hash.NewGenerator("client", "client-secret").AddAuthHeaders(httptest.NewRequest("BREW", "/books", nil)), http.StatusMethodNotAllowed, "GET, POST"},
+		{"unknown url", hash.NewGenerator("client", "client-secret").AddAuthHeaders(httptest.NewRequest("DELETE", "/authors", nil)), http.StatusNotFound, ""},
+		// the routes are not revealed to the unauthenticated callers
+		{"unauthenticated", httptest.NewRequest("DELETE", "/books", nil), http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, tt.r)
+		if w.Code != tt.status || w.Header().Get("Allow") != tt.allow {
+			t.Errorf("%s: expected %d with Allow %q, got %d with %q", tt.name, tt.status, tt.allow, w.Code, w.Header().Get("Allow"))
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/route"
+)
+
+const (
+	// maxMirrorBody bounds the request bodies buffered for the mirrors,
+	// the requests with larger bodies are not mirrored
+	maxMirrorBody = 1 << 20
+
+	// maxMirrorsInFlight bounds the mirrored requests in flight, the
+	// requests sampled beyond are not mirrored
+	maxMirrorsInFlight = 64
+
+	// mirrorTimeout bounds a mirrored request, unless the upstream timeout
+	// of the route is shorter
+	mirrorTimeout = 10 * time.Second
+)
+
+// stripMirrorHeaders removes the credentials of the caller from the
+// request sent to a mirror: the Authorization and Cookie headers, the
+// signature headers, see stripSignatureHeaders, and the identity and auth
+// info headers.
+func (g *Gateway) stripMirrorHeaders(r *http.Request) {
+	for _, name := range []string{"Authorization", "Proxy-Authorization", "Cookie"} {
+		r.Header.Del(name)
+	}
+	g.stripSignatureHeaders(r.Header)
+	authctx.DeleteIdentityHeaders(r)
+	authctx.DeleteAuthInfoHeader(r)
+}
+
+// mirror sends a copy of the request to the mirrors of the route sampling
+// it, without waiting for them, their responses and failures being
+// discarded. The credentials of the caller are stripped, the copies being
+// re-signed with the service credentials of the gateway if configured.
+func (g *Gateway) mirror(r *http.Request, rt *route.Route) {
+	targets := []*url.URL{}
+	for _, m := range rt.Mirrors {
+		if !m.Sample() {
+			continue
+		}
+		if u, err := url.Parse(m.Endpoint); err == nil && u.Host != "" {
+			targets = append(targets, u)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		buf, err := io.ReadAll(io.LimitReader(r.Body, maxMirrorBody+1))
+		if err != nil || len(buf) > maxMirrorBody {
+			// the body is restored for the proxied request
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(buf))
+		body = buf
+	}
+
+	timeout := mirrorTimeout
+	if rt.Upstream != nil && rt.Upstream.Timeout > 0 {
+		timeout = min(timeout, rt.Upstream.Timeout)
+	}
+	for _, target := range targets {
+		select {
+		case g.mirrors <- struct{}{}:
+		default:
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), timeout)
+		out, err := http.NewRequestWithContext(ctx, r.Method, target.String(), bytes.NewReader(body))
+		if err != nil {
+			cancel()
+			<-g.mirrors
+			continue
+		}
+		out.URL.Path = strings.TrimSuffix(target.Path, "/") + r.URL.Path
+		out.URL.RawQuery = r.URL.RawQuery
+		out.Header = r.Header.Clone()
+		g.stripMirrorHeaders(out)
+		if g.signer != nil {
+			g.signer.AddAuthHeaders(out)
+		}
+		go func() {
+			defer func() {
+				cancel()
+				<-g.mirrors
+			}()
+			resp, err := g.opts.transport.RoundTrip(out)
+			if err == nil {
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+)
+
+func TestGatewayMirrorCopy(t *testing.T) {
+	received := make(chan string, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- string(body)
+	}))
+	defer backend.Close()
+	mirrored := make(chan *http.Request, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(strings.NewReader(string(body)))
+		mirrored <- r
+	}))
+	defer shadow.Close()
+
+	gw, routes := newTestGateway(t, backend.URL)
+	routes.routes["/orders"] = &route.Route{
+		Key:      &route.Key{Url: "/orders"},
+		Endpoint: backend.URL,
+		Mirrors:  []*route.Mirror{{Endpoint: shadow.URL, Percentage: 100}},
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		mirrored bool
+	}{
+		{"body copied", `{"book":"go"}`, true},
+		{"body over the limit not mirrored", strings.Repeat("x", maxMirrorBody+1), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/orders", strings.NewReader(tc.body))
+			r.Header.Set("Content-Type", "application/json")
+			r.Header.Set("X-Request-Id", "req-1")
+			r.Header.Set("Cookie", "session=secret")
+			hash.NewGenerator("client", "client-secret", hash.WithNonce()).AddAuthHeaders(r)
+			w := httptest.NewRecorder()
+			gw.ServeHTTP(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected the response of the endpoint, got %d", w.Code)
+			}
+			// the endpoint receives the whole body either way
+			if body := <-received; body != tc.body {
+				t.Errorf("unexpected body of %d bytes proxied", len(body))
+			}
+
+			select {
+			case m := <-mirrored:
+				if !tc.mirrored {
+					t.Fatalf("expected no request to be mirrored")
+				}
+				if body, _ := io.ReadAll(m.Body); string(body) != tc.body {
+					t.Errorf("unexpected mirrored body %q", body)
+				}
+				if m.Header.Get("Content-Type") != "application/json" || m.Header.Get("X-Request-Id") != "req-1" {
+					t.Errorf("expected the headers to be copied, got %v", m.Header)
+				}
+				names := hash.DefaultHeaderNames()
+				for _, h := range []string{"Cookie", names.KeyId, names.Signature, names.Timestamp, names.Nonce} {
+					if m.Header.Get(h) != "" {
+						t.Errorf("expected %s to be stripped from the mirrored request", h)
+					}
+				}
+			case <-time.After(time.Second):
+				if tc.mirrored {
+					t.Fatalf("expected the request to be mirrored")
+				}
+			}
+		})
+	}
+}
+
+func TestGatewayMirrorFailures(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	defer backend.Close()
+
+	release := make(chan struct{})
+	hanging := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hanging.Close()
+	// released before the server is closed, waiting for its handlers
+	defer close(release)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "shadow failure", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+	}{
+		{"mirror failing", failing.URL},
+		{"mirror unreachable", closed.URL},
+		{"mirror hanging", hanging.URL},
+		{"mirror endpoint invalid", "://shadow"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gw, routes := newTestGateway(t, backend.URL)
+			routes.routes["/orders"] = &route.Route{
+				Key:      &route.Key{Url: "/orders"},
+				Endpoint: backend.URL,
+				Mirrors:  []*route.Mirror{{Endpoint: tc.endpoint, Percentage: 100}},
+			}
+			start := time.Now()
+			w := httptest.NewRecorder()
+			gw.ServeHTTP(w, hash.NewGenerator("client", "client-secret").AddAuthHeaders(
+				httptest.NewRequest("POST", "/orders", strings.NewReader("order"))))
+			if w.Code != http.StatusCreated || w.Body.String() != "created" {
+				t.Errorf("expected the response of the endpoint, got %d %q", w.Code, w.Body.String())
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("expected the response not to wait for the mirror, took %s", elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/auth/audit"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rbac"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// Defaults applied by New unless configured otherwise.
+const (
+	// DefaultRouteCacheSize is the number of resolved routes cached
+	DefaultRouteCacheSize = 4096
+
+	// DefaultRouteCacheTTL is how long a resolved route is cached, bounding
+	// the delay before route changes take effect
+	DefaultRouteCacheTTL = 30 * time.Second
+)
+
+// Option configures a Gateway.
+type Option func(*options)
+
+// options holds the optional configuration of a Gateway.
+type options struct {
+	authorizer     rbac.Authorizer          // authorizer enforcing the RBAC of routes
+	service        hash.CredentialsProvider // credentials re-signing the proxied requests
+	signing        []hash.Option            // options of the re-signing Generator
+	transport      http.RoundTripper        // transport to the endpoints
+	audit          audit.Emitter            // emitter receiving the decisions
+	identitySecret string                   // secret signing the identity headers
+	cacheSize      int                      // number of cached routes
+	cacheTTL       time.Duration            // lifetime of a cached route, 0 disables
+	metrics        telemetry.Metrics        // metrics of the route cache
+	health         *route.HealthTracker     // health of the endpoints
+	balancer       *route.Balancer          // selection among the endpoints
+}
+
+// newOptions returns the default options updated with the provided ones.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		transport: http.DefaultTransport,
+		cacheSize: DefaultRouteCacheSize,
+		cacheTTL:  DefaultRouteCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithAuthorizer enforces the Resource and Verb of the routes using the
+// authorizer, see route.Route.Authorize. Without an authorizer, requests
+// for routes requiring RBAC are rejected with 403.
+func WithAuthorizer(a rbac.Authorizer) Option {
+	return func(o *options) {
+		o.authorizer = a
+	}
+}
+
+// WithServiceCredentials re-signs the proxied requests with the service
+// credentials of the gateway, replacing the signature of the caller which
+// covers the inbound path only, so that the endpoints can verify that the
+// requests are received from the gateway.
+func WithServiceCredentials(creds hash.CredentialsProvider, opts ...hash.Option) Option {
+	return func(o *options) {
+		o.service = creds
+		o.signing = opts
+	}
+}
+
+// WithIdentitySecret injects the identity of the caller in the proxied
+// requests as the x-auth-* identity headers signed with the secret shared
+// with the endpoints, verified with authctx.VerifyIdentityHeaders.
+func WithIdentitySecret(secret string) Option {
+	return func(o *options) {
+		o.identitySecret = secret
+	}
+}
+
+// WithTransport sets the transport used to reach the endpoints.
+func WithTransport(t http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = t
+	}
+}
+
+// WithAudit emits an audit.Record of every authentication and authorization
+// decision of the gateway to the emitter.
+func WithAudit(e audit.Emitter) Option {
+	return func(o *options) {
+		o.audit = e
+	}
+}
+
+// WithRouteCache sets the number of resolved routes cached and how long,
+// a zero ttl disables the cache.
+func WithRouteCache(size int, ttl time.Duration) Option {
+	return func(o *options) {
+		o.cacheSize = size
+		o.cacheTTL = ttl
+	}
+}
+
+// WithHealthTracker rejects the requests for the routes whose endpoint is
+// unhealthy as per the tracker with 503, and reports the outcome of the
+// proxied requests to the tracker: transport failures along with 502, 503
+// and 504 responses as failures, any other response as a success.
+func WithHealthTracker(h *route.HealthTracker) Option {
+	return func(o *options) {
+		o.health = h
+	}
+}
+
+// WithBalancer selects the endpoint of the routes among their replicas
+// using the balancer, by default a route.RoundRobin balancer skipping the
+// endpoints unhealthy as per the tracker of WithHealthTracker. The balancer
+// is expected to use the same tracker.
+func WithBalancer(b *route.Balancer) Option {
+	return func(o *options) {
+		o.balancer = b
+	}
+}
+
+// WithMetrics enables recording of the hits, misses and evictions of the
+// route cache, reported as telemetry.CacheRoutes. Validations are recorded
+// by the Validator of the authenticator, see hash.WithMetrics.
+func WithMetrics(metrics telemetry.Metrics) Option {
+	return func(o *options) {
+		o.metrics = metrics
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxRetryBody bounds the request bodies buffered for the retries, the
+	// requests with larger bodies are not retried
+	maxRetryBody = 1 << 20
+
+	// retryBackoff is the delay before the first retry, doubled for every
+	// subsequent one
+	retryBackoff = 25 * time.Millisecond
+)
+
+// retryTransport retries the proxied requests as per the UpstreamPolicy of
+// their route, within the upstream timeout of the route. The requests
+// failing to connect are retried regardless of their method, the others
+// only for the idempotent methods.
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+// idempotent reports whether the method is idempotent, see RFC 9110.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// isConnectFailure reports whether the error is a failure to connect to
+// the endpoint, the request never reaching it.
+func isConnectFailure(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (rt *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t, ok := req.Context().Value(proxiedRoute{}).(*proxyTarget)
+	if !ok || t.route.Upstream == nil || t.route.Upstream.Retries <= 0 {
+		return rt.base.RoundTrip(req)
+	}
+	p := t.route.Upstream
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		buf, err := io.ReadAll(io.LimitReader(req.Body, maxRetryBody+1))
+		if err != nil || len(buf) > maxRetryBody {
+			req.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+			return rt.base.RoundTrip(req)
+		}
+		req.Body.Close()
+		body = buf
+	}
+
+	backoff := retryBackoff
+	for attempt := int32(0); ; attempt++ {
+		out := req
+		if body != nil {
+			out = req.Clone(req.Context())
+			out.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		resp, err := rt.base.RoundTrip(out)
+		last := attempt >= p.Retries
+		switch {
+		case err != nil:
+			connectFailure := isConnectFailure(err)
+			if last || !p.RetryOnError(connectFailure) || (!connectFailure && !idempotent(req.Method)) {
+				return nil, err
+			}
+		case !last && idempotent(req.Method) && p.RetryOnStatus(resp.StatusCode):
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryBody))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
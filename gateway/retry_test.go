@@ -0,0 +1,83 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/route"
+)
+
+func TestGatewayUpstreamRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var failures atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "" && string(body) != "payload" {
+			t.Errorf("unexpected body %q", body)
+		}
+		if failures.Add(-1) >= 0 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	down.Close()
+
+	// counts the round trips, including those failing to connect
+	var trips atomic.Int32
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		trips.Add(1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	gw, routes := newTestGateway(t, backend.URL, WithTransport(transport))
+	policy := &route.UpstreamPolicy{Retries: 2, RetryOn: []route.RetryCondition{route.RetryOnGatewayError, route.RetryOnConnectFailure}}
+	routes.routes["/retried"] = &route.Route{Key: &route.Key{Url: "/retried"}, Endpoint: backend.URL, IsPublic: boolPtr(true), Upstream: policy}
+	routes.routes["/down"] = &route.Route{Key: &route.Key{Url: "/down"}, Endpoint: down.URL, IsPublic: boolPtr(true), Upstream: policy}
+	routes.routes["/single"] = &route.Route{Key: &route.Key{Url: "/single"}, Endpoint: backend.URL, IsPublic: boolPtr(true),
+		Upstream: &route.UpstreamPolicy{Timeout: time.Second}}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		failures int32
+		status   int
+		attempts int32
+		trips    int32
+	}{
+		{"recovered", "PUT", "/retried", 2, http.StatusOK, 3, 3},
+		{"retries exhausted", "GET", "/retried", 3, http.StatusServiceUnavailable, 3, 3},
+		{"non idempotent", "POST", "/retried", 1, http.StatusServiceUnavailable, 1, 1},
+		{"no retries", "GET", "/single", 1, http.StatusServiceUnavailable, 1, 1},
+		{"connect failure", "POST", "/down", 0, http.StatusBadGateway, 0, 3},
+	}
+	for _, tt := range tests {
+		attempts.Store(0)
+		trips.Store(0)
+		failures.Store(tt.failures)
+		w := httptest.NewRecorder()
+		gw.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader("payload")))
+		if w.Code != tt.status || attempts.Load() != tt.attempts || trips.Load() != tt.trips {
+			t.Errorf("%s: expected %d after %d attempts and %d round trips, got %d after %d and %d",
+				tt.name, tt.status, tt.attempts, tt.trips, w.Code, attempts.Load(), trips.Load())
+		}
+	}
+}
+
+// roundTripFunc adapts a function to an http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/route"
+)
+
+// streamContext returns the context of a streaming connection established
+// for the route, cancelled, closing the connection, once the MaxDuration
+// of the policy elapses or the re-validation of the credentials of the
+// caller fails, see authState.
+func streamContext(ctx context.Context, p *route.StreamPolicy, rt *route.Route, st *authState) (context.Context, context.CancelFunc) {
+	cancelDuration := context.CancelFunc(func() {})
+	if p.MaxDuration > 0 {
+		ctx, cancelDuration = context.WithTimeout(ctx, p.MaxDuration)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	if p.RevalidateInterval > 0 && st.revalidate != nil {
+		go func() {
+			ticker := time.NewTicker(p.RevalidateInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := st.revalidate(ctx, rt); err != nil && ctx.Err() == nil {
+						cancel()
+						return
+					}
+				}
+			}
+		}()
+	}
+	return ctx, func() {
+		cancel()
+		cancelDuration()
+	}
+}
+
+// idleBody closes the body of a streaming response, along with the
+// upgraded connection it is for a 101 response, once no data is read from
+// or written to it for the idle timeout.
+type idleBody struct {
+	io.ReadCloser
+	idle  time.Duration
+	timer *time.Timer
+	once  sync.Once
+}
+
+// newIdleBody returns the body closed once idle for the timeout.
+func newIdleBody(body io.ReadCloser, idle time.Duration) *idleBody {
+	b := &idleBody{ReadCloser: body, idle: idle}
+	b.timer = time.AfterFunc(idle, func() { _ = b.Close() })
+	return b
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.timer.Reset(b.idle)
+	return n, err
+}
+
+// Write writes to the upgraded connection of a 101 response.
+func (b *idleBody) Write(p []byte) (int, error) {
+	w, ok := b.ReadCloser.(io.Writer)
+	if !ok {
+		return 0, errors.ErrUnsupported
+	}
+	n, err := w.Write(p)
+	b.timer.Reset(b.idle)
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	var err error
+	b.once.Do(func() {
+		b.timer.Stop()
+		err = b.ReadCloser.Close()
+	})
+	return err
+}
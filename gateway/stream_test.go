@@ -0,0 +1,244 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/apikey"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/route"
+	"github.com/go-core-stack/auth/storage"
+)
+
+// echoBackend upgrades the connections to an echo protocol
+func echoBackend() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, brw, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		_ = brw.Flush()
+		_, _ = io.Copy(conn, brw)
+	}))
+}
+
+// eventBackend streams server sent events until the request is done
+func eventBackend() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		rc := http.NewResponseController(w)
+		for {
+			if _, err := fmt.Fprint(w, "data: tick\n\n"); err != nil {
+				return
+			}
+			_ = rc.Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}))
+}
+
+// upgrade sends an upgrade request for the path to the gateway, returning
+// the status and the connection.
+func upgrade(t *testing.T, gw *httptest.Server, path string) (int, net.Conn, *bufio.Reader) {
+	conn, err := net.Dial("tcp", gw.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to dial gateway: %s", err)
+	}
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: gateway\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n", path)
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("failed to read upgrade response: %s", err)
+	}
+	return resp.StatusCode, conn, br
+}
+
+func TestGatewayStreamPolicy(t *testing.T) {
+	echo := echoBackend()
+	defer echo.Close()
+	events := eventBackend()
+	defer events.Close()
+
+	gw, routes := newTestGateway(t, echo.URL)
+	public := func(url, endpoint string, p *route.StreamPolicy) *route.Route {
+		return &route.Route{Key: &route.Key{Url: url}, Endpoint: endpoint, IsPublic: boolPtr(true), Stream: p}
+	}
+	routes.routes["/ws"] = public("/ws", echo.URL, &route.StreamPolicy{Enabled: true, IdleTimeout: 100 * time.Millisecond})
+	routes.routes["/ws-disabled"] = public("/ws-disabled", echo.URL, &route.StreamPolicy{Enabled: false})
+	routes.routes["/ws-none"] = public("/ws-none", echo.URL, nil)
+	routes.routes["/events"] = public("/events", events.URL, &route.StreamPolicy{Enabled: true, MaxDuration: 200 * time.Millisecond})
+	routes.routes["/events-disabled"] = public("/events-disabled", events.URL, &route.StreamPolicy{})
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+
+	// the routes not enabling streaming reject the upgrades
+	for _, path := range []string{"/ws-disabled", "/ws-none"} {
+		status, conn, _ := upgrade(t, srv, path)
+		conn.Close()
+		if status != http.StatusForbidden {
+			t.Errorf("%s: expected the upgrade to be rejected, got %d", path, status)
+		}
+	}
+	r, _ := http.NewRequest("GET", srv.URL+"/events-disabled", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	if resp, err := http.DefaultClient.Do(r); err != nil || resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected the event stream to be rejected, got %v, %v", resp, err)
+	}
+
+	// the upgraded connection is closed once idle
+	status, conn, br := upgrade(t, srv, "/ws")
+	defer conn.Close()
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("expected the upgrade to be proxied, got %d", status)
+	}
+	fmt.Fprint(conn, "ping\n")
+	if line, err := br.ReadString('\n'); err != nil || line != "ping\n" {
+		t.Fatalf("expected the echo, got %q, %v", line, err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := br.ReadByte(); err != io.EOF {
+		t.Errorf("expected the idle connection to be closed, got %v", err)
+	}
+
+	// the event stream is closed after the maximum duration
+	r, _ = http.NewRequest("GET", srv.URL+"/events", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	start := time.Now()
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(r)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the event stream to be proxied, got %v, %v", resp, err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > 4*time.Second {
+		t.Errorf("expected the event stream to last the maximum duration, lasted %s", elapsed)
+	}
+}
+
+func TestGatewayStreamRevalidation(t *testing.T) {
+	events := eventBackend()
+	defer events.Close()
+
+	ctx := context.Background()
+	store, _ := apikey.NewStoreWithStorage(storage.NewMemoryTable[apikey.KeyId, apikey.Key](),
+		storage.NewMemoryTable[apikey.UsageKey, apikey.Usage](), make([]byte, 32))
+	k, secret, _ := store.Create(ctx, &apikey.Key{Owner: "svc", Tenant: "acme"})
+	routes := &fakeRoutes{routes: map[string]*route.Route{
+		"/events": {
+			Key:      &route.Key{Url: "/events"},
+			Endpoint: events.URL,
+			Stream:   &route.StreamPolicy{Enabled: true, RevalidateInterval: 50 * time.Millisecond},
+		},
+	}}
+	gw, err := New(KeyStoreAuthenticator(store, hash.NewValidator(60)), routes)
+	if err != nil {
+		t.Fatalf("failed to create gateway: %s", err)
+	}
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+
+	r, _ := http.NewRequest("GET", srv.URL+"/events", nil)
+	r.Header.Set("Accept", "text/event-stream")
+	hash.NewGenerator(k.Key.Id, secret).AddAuthHeaders(r)
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(r)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the event stream to be proxied, got %v, %v", resp, err)
+	}
+	defer resp.Body.Close()
+	br := bufio.NewReader(resp.Body)
+	if line, err := br.ReadString('\n'); err != nil || line != "data: tick\n" {
+		t.Fatalf("expected an event, got %q, %v", line, err)
+	}
+
+	// the stream is closed once the key is disabled
+	_ = store.Disable(ctx, k.Key.Id)
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(io.Discard, br)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(4 * time.Second):
+		t.Errorf("expected the stream of the disabled key to be closed")
+	}
+}
+
+func TestGatewayStreamFlush(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"server sent events", "text/event-stream", "text/event-stream"},
+		{"chunked long poll", "text/event-stream", "application/json"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// the backend writes the first chunk and holds the response
+			// until released
+			release := make(chan struct{})
+			backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				fmt.Fprint(w, "data: first\n")
+				_ = http.NewResponseController(w).Flush()
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}))
+			defer backend.Close()
+			defer close(release)
+
+			gw, routes := newTestGateway(t, backend.URL)
+			routes.routes["/feed"] = &route.Route{
+				Key:      &route.Key{Url: "/feed"},
+				Endpoint: backend.URL,
+				IsPublic: boolPtr(true),
+				Stream:   &route.StreamPolicy{Enabled: true},
+			}
+			srv := httptest.NewServer(gw)
+			defer srv.Close()
+
+			r, _ := http.NewRequest("GET", srv.URL+"/feed", nil)
+			r.Header.Set("Accept", tc.accept)
+			resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(r)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				t.Fatalf("expected the stream to be proxied, got %v, %v", resp, err)
+			}
+			defer resp.Body.Close()
+
+			// the chunk is flushed to the client while the backend holds
+			// the response
+			line := make(chan string, 1)
+			go func() {
+				s, _ := bufio.NewReader(resp.Body).ReadString('\n')
+				line <- s
+			}()
+			select {
+			case s := <-line:
+				if s != "data: first\n" {
+					t.Errorf("expected the first chunk, got %q", s)
+				}
+			case <-time.After(2 * time.Second):
+				t.Errorf("expected the first chunk to be flushed")
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package grpcauth
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+)
+
+/*
+Package grpcauth brings the HMAC request signing to gRPC, so that gRPC and
+HTTP services share a single authentication scheme.
+
+The client side PerRPCCredentials emits the same x-signature, x-api-key-id,
+x-timestamp (and algorithm/version) headers as gRPC metadata, signing the
+full method name of the RPC as the path with POST as the method. The
+server side interceptors rebuild the equivalent HTTP request from the
+incoming metadata and validate it using the hash Validator, fetching the
+secret from a hash.SecretResolver.
+
+# Usage
+
+    // client side
+    conn, err := grpc.NewClient(target,
+        grpc.WithTransportCredentials(creds),
+        grpc.WithPerRPCCredentials(grpcauth.NewPerRPCCredentials(
+            hash.NewGenerator("api-key-id", "supersecret"), true)),
+    )
+
+    // server side
+    srv := grpc.NewServer(
+        grpc.UnaryInterceptor(grpcauth.UnaryServerInterceptor(hash.NewValidator(60), resolver)),
+        grpc.StreamInterceptor(grpcauth.StreamServerInterceptor(hash.NewValidator(60), resolver)),
+    )
+*/
+
+// perRPCCredentials signs every RPC using the Generator
+type perRPCCredentials struct {
+	gen        hash.Generator
+	requireTLS bool
+}
+
+// GetRequestMetadata returns the authentication headers for the RPC being
+// invoked, signed over the full method name.
+func (c *perRPCCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	ri, ok := credentials.RequestInfoFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "request info not available for signing")
+	}
+	r := signingRequest(ctx, ri.Method, http.Header{})
+	c.gen.AddAuthHeaders(r)
+	md := map[string]string{}
+	for k, v := range r.Header {
+		if len(v) != 0 {
+			md[k] = v[0]
+		}
+	}
+	if len(md) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "failed to sign request")
+	}
+	return md, nil
+}
+
+// RequireTransportSecurity reports whether the credentials require a
+// secure connection.
+func (c *perRPCCredentials) RequireTransportSecurity() bool {
+	return c.requireTLS
+}
+
+// NewPerRPCCredentials returns gRPC credentials signing every RPC using the
+// Generator, requireTLS should only be false for testing or for
+// connections secured otherwise, e.g. by a service mesh.
+func NewPerRPCCredentials(gen hash.Generator, requireTLS bool) credentials.PerRPCCredentials {
+	return &perRPCCredentials{
+		gen:        gen,
+		requireTLS: requireTLS,
+	}
+}
+
+// signingRequest returns the HTTP request equivalent of an RPC, which is
+// signed and validated
+func signingRequest(ctx context.Context, fullMethod string, h http.Header) *http.Request {
+	r := &http.Request{
+		Method: http.MethodPost,
+		URL:    &url.URL{Path: fullMethod},
+		Header: h,
+		Body:   http.NoBody,
+	}
+	return r.WithContext(ctx)
+}
+
+// keyIdCtx is the context key for the authenticated API key ID
+type keyIdCtx struct{}
+
+// KeyIdFromContext returns the API key ID authenticated by the server
+// interceptors.
+func KeyIdFromContext(ctx context.Context) (string, bool) {
+	keyId, ok := ctx.Value(keyIdCtx{}).(string)
+	return keyId, ok
+}
+
+// authenticate validates the signature carried by the incoming metadata
+// of the RPC, returning the context carrying the API key ID along with the
+// model.AuthContext of the caller.
+func authenticate(ctx context.Context, fullMethod string, validator hash.Validator, resolver hash.SecretResolver) (context.Context, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing authentication metadata")
+	}
+	h := http.Header{}
+	for k, v := range md {
+		h[http.CanonicalHeaderKey(k)] = v
+	}
+	r := signingRequest(ctx, fullMethod, h)
+
+	keyId := validator.GetKeyId(r)
+	if keyId == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing api key id")
+	}
+	secret, err := resolver.GetSecret(ctx, keyId)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, status.Error(codes.Unauthenticated, "unknown api key")
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to resolve api key: %s", err)
+	}
+	if ok, err := validator.Validate(r, secret); !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "authentication failed: %s", err)
+	}
+	ctx = model.WithAuthContext(ctx, &model.AuthContext{KeyId: keyId, Subject: keyId, SubjectType: model.SubjectService})
+	return context.WithValue(ctx, keyIdCtx{}, keyId), nil
+}
+
+// UnaryServerInterceptor returns a server interceptor authenticating unary
+// RPCs signed using PerRPCCredentials.
+func UnaryServerInterceptor(validator hash.Validator, resolver hash.SecretResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, info.FullMethod, validator, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStream overrides the context of an authenticated server stream
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context carrying the authenticated API key ID.
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// StreamServerInterceptor returns a server interceptor authenticating
+// streaming RPCs signed using PerRPCCredentials, authentication is
+// performed once when the stream is established.
+func StreamServerInterceptor(validator hash.Validator, resolver hash.SecretResolver) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod, validator, resolver)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package grpcauth
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func newTestConn(t *testing.T, gen hash.Generator) healthpb.HealthClient {
+	resolver := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		if keyId != "test-key" {
+			return "", errors.Wrapf(errors.NotFound, "unknown key %s", keyId)
+		}
+		return "supersecret", nil
+	})
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor(hash.NewValidator(60), resolver)))
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(NewPerRPCCredentials(gen, false)),
+	)
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryAuthentication(t *testing.T) {
+	cli := newTestConn(t, hash.NewGenerator("test-key", "supersecret"))
+	if _, err := cli.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Errorf("expected signed RPC to succeed: %s", err)
+	}
+
+	cli = newTestConn(t, hash.NewGenerator("test-key", "wrongsecret"))
+	_, err := cli.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated with a wrong secret, got %v", err)
+	}
+
+	cli = newTestConn(t, hash.NewGenerator("unknown-key", "supersecret"))
+	_, err = cli.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected unauthenticated with an unknown key, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha3"
+	"crypto/sha512"
+	"fmt"
+	stdhash "hash"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Algorithm identifies the keyed hash used to compute a request signature.
+// The value is carried verbatim in the x-signature-alg header so that the
+// Validator can recompute the signature with the same algorithm.
+type Algorithm string
+
+const (
+	// HMACSHA256 is HMAC with SHA-256, the default algorithm and the one
+	// assumed when a request carries no x-signature-alg header.
+	HMACSHA256 Algorithm = "hmac-sha256"
+
+	// HMACSHA512 is HMAC with SHA-512.
+	HMACSHA512 Algorithm = "hmac-sha512"
+
+	// HMACSHA3_256 is HMAC with SHA3-256.
+	HMACSHA3_256 Algorithm = "hmac-sha3-256"
+
+	// HMACBLAKE2b256 is HMAC with BLAKE2b-256.
+	HMACBLAKE2b256 Algorithm = "hmac-blake2b-256"
+)
+
+// DefaultAlgorithm is the algorithm used by the Generator when none is
+// configured explicitly.
+const DefaultAlgorithm = HMACSHA256
+
+// supportedAlgorithms maps every known algorithm to its hash constructor.
+var supportedAlgorithms = map[Algorithm]func() stdhash.Hash{
+	HMACSHA256:   sha256.New,
+	HMACSHA512:   sha512.New,
+	HMACSHA3_256: func() stdhash.Hash { return sha3.New256() },
+	HMACBLAKE2b256: func() stdhash.Hash {
+		// a nil key never fails for BLAKE2b, keying is done by HMAC
+		h, _ := blake2b.New256(nil)
+		return h
+	},
+}
+
+// SupportedAlgorithms returns all the algorithms known to this package, with
+// the default algorithm first.
+func SupportedAlgorithms() []Algorithm {
+	return []Algorithm{HMACSHA256, HMACSHA512, HMACSHA3_256, HMACBLAKE2b256}
+}
+
+// IsSupported reports whether the algorithm is known to this package.
+func (a Algorithm) IsSupported() bool {
+	_, ok := supportedAlgorithms[a]
+	return ok
+}
+
+// String returns the header representation of the algorithm.
+func (a Algorithm) String() string {
+	return string(a)
+}
+
+// generateHMAC computes the raw HMAC for the newline joined input strings
+// using the provided hash constructor and secret key.
+func generateHMAC(h func() stdhash.Hash, secret string, v ...string) []byte {
+	mac := hmac.New(h, []byte(secret))
+	mac.Write([]byte(strings.Join(v, "\n")))
+	return mac.Sum(nil)
+}
+
+// GenerateHMAC generates an HMAC signature for the given input strings using
+// the requested algorithm and secret key, input strings are joined in the
+// order provided exactly as done by GenerateSHA256HMAC.
+// Returns the signature as a hex-encoded string, or an error if the
+// algorithm is not supported.
+//
+// Example:
+//
+//	sig, err := GenerateHMAC(HMACSHA512, "mysecret", "foo", "bar")
+func GenerateHMAC(alg Algorithm, secret string, v ...string) (string, error) {
+	s, ok := signers[alg]
+	if !ok {
+		return "", fmt.Errorf("unsupported signature algorithm: %s", alg)
+	}
+	return s.Sign(secret, v...), nil
+}
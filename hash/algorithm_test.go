@@ -0,0 +1,55 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GenerateHMACMatchesSHA256(t *testing.T) {
+	sig, err := GenerateHMAC(HMACSHA256, "mysupersecretcode", "POST", "/api/service1/v1/scope/abc/test/test1", "1748410688")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sig != "04a41d00f2f133c8746d11c7d3d5bfc547fc514b583e3798b1df2c9c09204461" {
+		t.Errorf("generated HMAC signature doesn't match as expected")
+	}
+
+	if _, err := GenerateHMAC(Algorithm("md5"), "secret", "foo"); err == nil {
+		t.Errorf("expected error for unsupported algorithm")
+	}
+}
+
+func TestAlgorithmsRoundTrip(t *testing.T) {
+	for _, alg := range SupportedAlgorithms() {
+		req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+		signed := NewGenerator("test-key", "supersecret", WithAlgorithm(alg)).AddAuthHeaders(req)
+		if got := signed.Header.Get("x-signature-alg"); got != alg.String() {
+			t.Errorf("expected algorithm header %s, got %s", alg, got)
+		}
+		ok, err := NewValidator(60).Validate(signed, "supersecret")
+		if !ok {
+			t.Errorf("validation failed for %s: %v", alg, err)
+		}
+	}
+}
+
+func TestAllowedAlgorithms(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed := NewGenerator("test-key", "supersecret", WithAlgorithm(HMACSHA512)).AddAuthHeaders(req)
+
+	validator := NewValidator(60, WithAllowedAlgorithms(HMACSHA256))
+	if ok, err := validator.Validate(signed, "supersecret"); ok || err == nil {
+		t.Fatalf("expected validation to fail for algorithm outside allowlist")
+	}
+
+	// requests without the algorithm header are treated as hmac-sha256
+	req = httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed = NewGenerator("test-key", "supersecret").AddAuthHeaders(req)
+	signed.Header.Del("x-signature-alg")
+	if ok, err := validator.Validate(signed, "supersecret"); !ok {
+		t.Fatalf("expected legacy request to validate: %v", err)
+	}
+}
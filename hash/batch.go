@@ -0,0 +1,256 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file provides the BatchVerifier, re-verifying the signatures of
+recorded requests offline, e.g. in an audit or replay pipeline, much faster
+than validating them one at a time:
+
+  - the secrets of the distinct API keys of the batch are resolved once,
+    in a single round trip if the resolver implements BulkSecretResolver
+  - the signatures are verified concurrently by a pool of workers, with the
+    pooled HMAC states of the Signer
+
+The records carry the components signed with the v1 signature scheme, the
+method, path and timestamp. The timestamps are parsed but, the requests
+being recorded, not checked against a validity window.
+
+# Usage
+
+    v := hash.NewBatchVerifier(resolver, hash.WithBatchWorkers(8))
+    results, err := v.Verify(ctx, records)
+    for i, res := range results {
+        if !res.Valid {
+            log.Printf("record %d: %s: %s", i, res.Reason, res.Err)
+        }
+    }
+*/
+
+// BatchRecord is a recorded request, signed with the v1 signature scheme.
+type BatchRecord struct {
+	Method    string
+	Path      string
+	Timestamp string
+
+	// hex-encoded signature and the API key id it was signed with
+	Signature string
+	KeyId     string
+
+	// algorithm of the signature, default hmac-sha256
+	Algorithm Algorithm
+}
+
+// BatchResult is the outcome of the verification of a record.
+type BatchResult struct {
+	// Valid is set if the signature of the record matches
+	Valid bool
+
+	// Reason classifies the failure, ReasonNone for valid records
+	Reason ReasonCode
+
+	// Err is the reason of the failure
+	Err error
+}
+
+// BatchOption configures the BatchVerifier.
+type BatchOption func(*BatchVerifier)
+
+// WithBatchWorkers sets the number of workers verifying the records, by
+// default GOMAXPROCS.
+func WithBatchWorkers(n int) BatchOption {
+	return func(b *BatchVerifier) {
+		if n > 0 {
+			b.workers = n
+		}
+	}
+}
+
+// BatchVerifier verifies the signatures of batches of recorded requests,
+// see the file documentation. A BatchVerifier is safe for concurrent use.
+type BatchVerifier struct {
+	resolver SecretResolver
+	workers  int
+}
+
+// NewBatchVerifier creates the verifier resolving the secrets of the API
+// keys with the resolver.
+func NewBatchVerifier(resolver SecretResolver, opts ...BatchOption) *BatchVerifier {
+	b := &BatchVerifier{
+		resolver: resolver,
+		workers:  runtime.GOMAXPROCS(0),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Verify verifies the records, returning the result of every record at
+// its index. The records of unknown API keys fail with ReasonInvalid,
+// while the other resolver failures, or the cancellation of the context,
+// fail the whole batch.
+func (b *BatchVerifier) Verify(ctx context.Context, records []BatchRecord) ([]BatchResult, error) {
+	secrets, err := b.resolve(ctx, records)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]BatchResult, len(records))
+	err = b.parallel(ctx, len(records), func(i int) error {
+		rec := &records[i]
+		secret, ok := secrets[rec.KeyId]
+		if !ok && rec.KeyId != "" {
+			results[i] = batchFailure(failure(ReasonInvalid, "unknown api key %s", rec.KeyId))
+			return nil
+		}
+		results[i] = batchFailure(verifyRecord(rec, secret))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchFailure returns the result for the verification error.
+func batchFailure(err error) BatchResult {
+	return BatchResult{Valid: err == nil, Reason: Reason(err), Err: err}
+}
+
+// verifyRecord verifies the signature of the record with the secret.
+func verifyRecord(rec *BatchRecord, secret string) error {
+	switch {
+	case rec.KeyId == "":
+		return failure(ReasonMissingHeader, "missing api key id")
+	case rec.Signature == "":
+		return failure(ReasonMissingHeader, "missing signature")
+	case rec.Timestamp == "":
+		return failure(ReasonMissingHeader, "missing timestamp")
+	case len(rec.Signature) > maxSignatureLength:
+		return failure(ReasonMalformedHeader, "signature too long")
+	}
+	if _, err := parseTimestamp(rec.Timestamp); err != nil {
+		return failure(ReasonMalformedHeader, "error parsing timestamp: %s", err)
+	}
+
+	alg := rec.Algorithm
+	if alg == "" {
+		alg = DefaultAlgorithm
+	}
+	signer, ok := signers[alg]
+	if !ok {
+		return failure(ReasonAlgorithmNotAllowed, "signature algorithm not allowed: %s", alg)
+	}
+
+	var sig, sum [64]byte
+	n, err := hex.Decode(sig[:], []byte(rec.Signature))
+	if err != nil {
+		return failure(ReasonMalformedHeader, "invalid signature format")
+	}
+	if n != signer.size {
+		return failure(ReasonMalformedHeader, "invalid signature length for %s", alg)
+	}
+	if !hmac.Equal(sig[:n], signer.AppendSum(sum[:0], secret, rec.Method, rec.Path, rec.Timestamp)) {
+		return errSignatureMismatch
+	}
+	return nil
+}
+
+// resolve returns the secrets of the distinct API keys of the records, in
+// bulk if supported by the resolver, the unknown keys being omitted.
+func (b *BatchVerifier) resolve(ctx context.Context, records []BatchRecord) (map[string]string, error) {
+	keyIds := []string{}
+	seen := map[string]bool{}
+	for i := range records {
+		if id := records[i].KeyId; id != "" && !seen[id] {
+			seen[id] = true
+			keyIds = append(keyIds, id)
+		}
+	}
+	if len(keyIds) == 0 {
+		return map[string]string{}, nil
+	}
+
+	if bulk, ok := b.resolver.(BulkSecretResolver); ok {
+		secrets, err := bulk.GetSecrets(ctx, keyIds)
+		if err != nil {
+			return nil, errors.Wrapf(errors.GetErrCode(err), "failed to resolve secrets: %s", err)
+		}
+		return secrets, nil
+	}
+
+	var mu sync.Mutex
+	secrets := make(map[string]string, len(keyIds))
+	err := b.parallel(ctx, len(keyIds), func(i int) error {
+		secret, err := b.resolver.GetSecret(ctx, keyIds[i])
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return errors.Wrapf(errors.GetErrCode(err), "failed to resolve secret for key %s: %s", keyIds[i], err)
+		}
+		mu.Lock()
+		secrets[keyIds[i]] = secret
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// parallel calls fn for the indexes up to n with the pool of workers,
+// stopping at the first failure or the cancellation of the context.
+func (b *BatchVerifier) parallel(ctx context.Context, n int, fn func(i int) error) error {
+	var next atomic.Int64
+	var once sync.Once
+	var failed error
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	fail := func(err error) {
+		once.Do(func() {
+			failed = err
+			close(stop)
+		})
+	}
+
+	for range min(b.workers, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				select {
+				case <-stop:
+					return
+				case <-ctx.Done():
+					fail(ctx.Err())
+					return
+				default:
+				}
+				if err := fn(i); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return failed
+}
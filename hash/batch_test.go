@@ -0,0 +1,145 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// batchRecord records the request signed by the generator.
+func batchRecord(keyId, secret, path string, opts ...Option) BatchRecord {
+	r := NewGenerator(keyId, secret, opts...).AddAuthHeaders(httptest.NewRequest("GET", path, nil))
+	return BatchRecord{
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Timestamp: r.Header.Get(apiKeyTimestampHeader),
+		Signature: r.Header.Get(apiKeySignatureHeader),
+		KeyId:     r.Header.Get(apiKeyIdHeader),
+		Algorithm: Algorithm(r.Header.Get(apiKeyAlgorithmHeader)),
+	}
+}
+
+func TestBatchVerifier(t *testing.T) {
+	base := &bulkResolver{countingResolver: countingResolver{secrets: map[string]string{"key1": "secret1", "key2": "secret2"}}}
+
+	tampered := batchRecord("key1", "secret1", "/books/1")
+	tampered.Path = "/books/2"
+	odd := batchRecord("key1", "secret1", "/books/1")
+	odd.Signature = odd.Signature[1:]
+	records := []BatchRecord{
+		batchRecord("key1", "secret1", "/books/1"),
+		batchRecord("key2", "secret2", "/books/2", WithAlgorithm(HMACSHA512)),
+		batchRecord("key1", "secret1", "/books/3", WithEpochTimestamp()),
+		batchRecord("key3", "secret3", "/books/4"),
+		batchRecord("key2", "wrong", "/books/5"),
+		tampered,
+		odd,
+		{Method: "GET", Path: "/books/6"},
+	}
+	results, err := NewBatchVerifier(base, WithBatchWorkers(3)).Verify(context.Background(), records)
+	if err != nil {
+		t.Fatalf("batch verification failed: %s", err)
+	}
+	expected := []ReasonCode{
+		ReasonNone, ReasonNone, ReasonNone, ReasonInvalid, ReasonSignatureMismatch,
+		ReasonSignatureMismatch, ReasonMalformedHeader, ReasonMissingHeader,
+	}
+	for i, res := range results {
+		if res.Reason != expected[i] || res.Valid != (expected[i] == ReasonNone) {
+			t.Errorf("record %d: expected %q, got %+v", i, expected[i], res)
+		}
+	}
+	if base.bulkLookups != 1 || base.lookups != 0 {
+		t.Errorf("expected the secrets to be resolved in bulk, got %d bulk and %d lookups", base.bulkLookups, base.lookups)
+	}
+}
+
+func TestBatchVerifierResolver(t *testing.T) {
+	var lookups atomic.Int32
+	resolver := SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		lookups.Add(1)
+		switch keyId {
+		case "key1":
+			return "secret1", nil
+		case "broken":
+			return "", errors.Wrapf(errors.Unknown, "store unavailable")
+		}
+		return "", errors.Wrapf(errors.NotFound, "key %s not found", keyId)
+	})
+
+	records := []BatchRecord{}
+	for i := range 100 {
+		records = append(records, batchRecord("key1", "secret1", fmt.Sprintf("/books/%d", i)))
+	}
+	records = append(records, batchRecord("key2", "secret2", "/books"))
+	results, err := NewBatchVerifier(resolver).Verify(context.Background(), records)
+	if err != nil {
+		t.Fatalf("batch verification failed: %s", err)
+	}
+	for i, res := range results[:100] {
+		if !res.Valid {
+			t.Fatalf("record %d: unexpected failure %s", i, res.Err)
+		}
+	}
+	if res := results[100]; res.Valid || !strings.Contains(res.Err.Error(), "unknown api key") {
+		t.Errorf("expected unknown key to fail, got %+v", res)
+	}
+	if lookups.Load() != 2 {
+		t.Errorf("expected a lookup per distinct key, got %d", lookups.Load())
+	}
+
+	records = append(records, batchRecord("broken", "secret", "/books"))
+	if _, err := NewBatchVerifier(resolver).Verify(context.Background(), records); err == nil {
+		t.Errorf("expected the resolver failure to fail the batch")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewBatchVerifier(resolver).Verify(ctx, records[:100]); err != context.Canceled {
+		t.Errorf("expected the canceled context to fail the batch, got %v", err)
+	}
+}
+
+func BenchmarkBatchVerifier(b *testing.B) {
+	secrets := map[string]string{}
+	records := []BatchRecord{}
+	for i := range 1000 {
+		keyId := fmt.Sprintf("key%d", i%10)
+		secrets[keyId] = "secret-" + keyId
+		records = append(records, batchRecord(keyId, secrets[keyId], fmt.Sprintf("/books/%d", i)))
+	}
+	resolver := &bulkResolver{countingResolver: countingResolver{secrets: secrets}}
+
+	b.Run("batch", func(b *testing.B) {
+		v := NewBatchVerifier(resolver)
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := v.Verify(context.Background(), records); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("validate", func(b *testing.B) {
+		v := NewValidator(1 << 40)
+		b.ReportAllocs()
+		for b.Loop() {
+			for i := range records {
+				rec := &records[i]
+				r := httptest.NewRequest(rec.Method, rec.Path, nil)
+				r.Header.Set(apiKeyTimestampHeader, rec.Timestamp)
+				r.Header.Set(apiKeySignatureHeader, rec.Signature)
+				secret, _ := resolver.GetSecret(context.Background(), rec.KeyId)
+				if ok, err := v.Validate(r, secret); !ok {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+This file contains the canonical string builder, the single place where the
+signed string of a request is built, shared by the Generators and the
+Validators of all the algorithms, and exported as CanonicalString for
+logging, debugging and verification by SDKs in other languages.
+
+The canonical string is the list of components of the signature version
+joined with "\n", without a trailing newline, as is the signature computed
+over it, followed by the nonce for the requests carrying one:
+
+  - v1:           METHOD \n PATH \n TIMESTAMP [\n NONCE]
+  - v2:           METHOD \n PATH \n QUERY \n BODY-SHA256 \n TIMESTAMP [\n NONCE]
+  - v2-streaming: METHOD \n PATH \n QUERY \n STREAMING-PAYLOAD \n TIMESTAMP [\n NONCE]
+
+where:
+
+  - METHOD is the request method as sent, e.g. "GET"
+  - PATH is the unescaped URL path, e.g. "/api/v2/books"
+  - QUERY is the query with keys sorted and values url encoded, as per
+    url.Values.Encode, e.g. "a=1&b=2", empty if there is none
+  - BODY-SHA256 is the lower case hex sha256 of the body, the hash of an
+    empty body for requests without one
+  - TIMESTAMP is the literal value of the x-timestamp header, RFC3339 or
+    unix epoch seconds
+  - NONCE is the literal value of the x-nonce header, omitted along with
+    its separator if the request carries none, see WithNonce
+
+For example, GET https://api.example.com/resource?b=2&a=1 signed with v2 at
+2025-05-28T05:38:08Z has the canonical string:
+
+  GET
+  /resource
+  a=1&b=2
+  e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+  2025-05-28T05:38:08Z
+
+# Usage
+
+    canonical, err := hash.CanonicalString("", signedReq)
+    if err != nil {
+        return err
+    }
+    log.Printf("signed string:\n%s", canonical)
+*/
+
+// components returns the signed components of the request for the version.
+func components(version SignatureVersion, r *http.Request, timestamp string) ([]string, error) {
+	scheme, ok := getSignatureScheme(version)
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature version: %s", version)
+	}
+	return scheme(r, timestamp)
+}
+
+// canonicalString returns the string signed for the request with the
+// version, timestamp and nonce, if any.
+func canonicalString(version SignatureVersion, r *http.Request, timestamp, nonce string) (string, error) {
+	c, err := components(version, r, timestamp)
+	if err != nil {
+		return "", err
+	}
+	if nonce != "" {
+		c = append(c, nonce)
+	}
+	return strings.Join(c, "\n"), nil
+}
+
+// CanonicalString returns the exact string signed for the request, using
+// the timestamp carried by the request, see the file documentation for its
+// format. An empty version uses the version carried by the request
+// headers, defaulting to v1. The options must match the ones of the
+// Generator, e.g. for custom header names.
+func CanonicalString(version SignatureVersion, r *http.Request, opts ...Option) (string, error) {
+	o := newOptions(opts...)
+	if version == "" {
+		version = o.requestVersion(r)
+	}
+	timestamp := r.Header.Get(o.headers.Timestamp)
+	if timestamp == "" {
+		return "", fmt.Errorf("missing timestamp header")
+	}
+	return canonicalString(version, r, timestamp, r.Header.Get(o.headers.Nonce))
+}
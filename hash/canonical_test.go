@@ -0,0 +1,119 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// test vectors for SDKs in other languages
+func TestCanonicalStringVectors(t *testing.T) {
+	tests := []struct {
+		version   SignatureVersion
+		method    string
+		url       string
+		body      string
+		canonical string
+	}{
+		{
+			SignatureV1, "GET", "https://api.example.com/resource?b=2&a=1", "",
+			"GET\n/resource\n2025-05-28T05:38:08Z",
+		},
+		{
+			SignatureV2, "GET", "https://api.example.com/resource?b=2&a=1", "",
+			"GET\n/resource\na=1&b=2\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855\n2025-05-28T05:38:08Z",
+		},
+		{
+			SignatureV2, "POST", "https://api.example.com/books", `{"name":"foo"}`,
+			"POST\n/books\n\n5dca85e76989e55ebbdec9e5304832c06a9ead7138b04372c65553003cfd2849\n2025-05-28T05:38:08Z",
+		},
+		{
+			SignatureStreaming, "PUT", "https://api.example.com/upload?part=1", "chunk",
+			"PUT\n/upload\npart=1\nSTREAMING-PAYLOAD\n2025-05-28T05:38:08Z",
+		},
+	}
+	for _, tc := range tests {
+		req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+		req.Header.Set(apiKeyTimestampHeader, "2025-05-28T05:38:08Z")
+		got, err := CanonicalString(tc.version, req)
+		if err != nil {
+			t.Fatalf("%s %s: %s", tc.version, tc.url, err)
+		}
+		if got != tc.canonical {
+			t.Errorf("%s %s: expected canonical string %q, got %q", tc.version, tc.url, tc.canonical, got)
+		}
+	}
+}
+
+// a request signed by an independent implementation of the canonical string
+// is accepted by the Validator, and the Generator signs the canonical string
+func TestCanonicalStringSigning(t *testing.T) {
+	ts := time.Now().UTC().Format(time.RFC3339)
+	req := httptest.NewRequest("POST", "https://api.example.com/books?b=2&a=1", strings.NewReader("payload"))
+	req.Header.Set(apiKeyTimestampHeader, ts)
+	req.Header.Set(apiKeyVersionHeader, string(SignatureV2))
+	req.Header.Set(apiKeyIdHeader, "test-key")
+	canonical := strings.Join([]string{
+		"POST", "/books", "a=1&b=2",
+		"239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5", ts,
+	}, "\n")
+	req.Header.Set(apiKeySignatureHeader, GenerateSHA256HMAC("supersecret", canonical))
+	if ok, err := NewValidator(60).Validate(req, "supersecret"); !ok {
+		t.Fatalf("expected externally signed request to be valid: %s", err)
+	}
+
+	signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2)).AddAuthHeaders(
+		httptest.NewRequest("POST", "https://api.example.com/books?b=2&a=1", strings.NewReader("payload")))
+	got, err := CanonicalString("", signed)
+	if err != nil {
+		t.Fatalf("failed to build canonical string: %s", err)
+	}
+	if signed.Header.Get(apiKeySignatureHeader) != GenerateSHA256HMAC("supersecret", got) {
+		t.Errorf("expected the Generator to sign the canonical string %q", got)
+	}
+}
+
+// the nonce is signed as the last component, making identical requests
+// signed within the same second distinct
+func TestCanonicalStringNonce(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	req.Header.Set(apiKeyTimestampHeader, "2025-05-28T05:38:08Z")
+	req.Header.Set(apiKeyNonceHeader, "n0nce")
+	if got, _ := CanonicalString(SignatureV1, req); got != "GET\n/resource\n2025-05-28T05:38:08Z\nn0nce" {
+		t.Errorf("expected the nonce to be signed, got %q", got)
+	}
+
+	gen := NewGenerator("test-key", "supersecret", WithEpochTimestamp(), WithNonce())
+	first := gen.AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+	second := gen.AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+	if first.Header.Get(apiKeyNonceHeader) == second.Header.Get(apiKeyNonceHeader) {
+		t.Errorf("expected a random nonce per request")
+	}
+	v := NewValidator(60)
+	if ok, err := v.Validate(first, "supersecret"); !ok {
+		t.Fatalf("expected the request signed with a nonce to be valid: %s", err)
+	}
+	tests := []struct {
+		name   string
+		tamper func(r *http.Request)
+	}{
+		{"nonce replaced", func(r *http.Request) { r.Header.Set(apiKeyNonceHeader, "other") }},
+		{"nonce removed", func(r *http.Request) { r.Header.Del(apiKeyNonceHeader) }},
+		{"nonce too long", func(r *http.Request) { r.Header.Set(apiKeyNonceHeader, strings.Repeat("n", maxNonceLength+1)) }},
+		{"nonce repeated", func(r *http.Request) { r.Header.Add(apiKeyNonceHeader, r.Header.Get(apiKeyNonceHeader)) }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := first.Clone(first.Context())
+			tc.tamper(r)
+			if ok, _ := v.Validate(r, "supersecret"); ok {
+				t.Errorf("expected the request to be rejected")
+			}
+		})
+	}
+}
@@ -3,9 +3,32 @@
 
 package hash
 
+// Header names, without prefix, used in HMAC-based signing and validation.
+const (
+	signatureHeaderName = "signature"         // HMAC signature
+	algorithmHeaderName = "signature-alg"     // algorithm used to compute the signature
+	versionHeaderName   = "signature-version" // signature scheme version
+	timestampHeaderName = "timestamp"         // request timestamp (RFC3339 format)
+	keyIdHeaderName     = "api-key-id"        // API key identifier
+
+	contentSignatureHeaderName = "content-signature" // streamed body signature, sent as trailer
+	nonceHeaderName            = "nonce"             // signed nonce, see WithNonce
+	sessionTokenHeaderName     = "session-token"     // session token of temporary credentials
+)
+
+// DefaultHeaderPrefix is the prefix of the authentication header names used
+// unless configured otherwise.
+const DefaultHeaderPrefix = "x-"
+
 // Constants for HTTP authentication header keys used in HMAC-based signing and validation.
 const (
-	apiKeySignatureHeader = "x-signature"  // Header for the HMAC-SHA256 signature
-	apiKeyTimestampHeader = "x-timestamp"  // Header for the request timestamp (RFC3339 format)
-	apiKeyIdHeader        = "x-api-key-id" // Header for the API key identifier
+	apiKeySignatureHeader = DefaultHeaderPrefix + signatureHeaderName // Header for the HMAC signature
+	apiKeyAlgorithmHeader = DefaultHeaderPrefix + algorithmHeaderName // Header for the algorithm used to compute the signature
+	apiKeyVersionHeader   = DefaultHeaderPrefix + versionHeaderName   // Header for the signature scheme version
+	apiKeyTimestampHeader = DefaultHeaderPrefix + timestampHeaderName // Header for the request timestamp (RFC3339 format)
+	apiKeyIdHeader        = DefaultHeaderPrefix + keyIdHeaderName     // Header for the API key identifier
+
+	apiKeyContentSignatureHeader = DefaultHeaderPrefix + contentSignatureHeaderName // Trailer for the streamed body signature
+	apiKeyNonceHeader            = DefaultHeaderPrefix + nonceHeaderName            // Header for the signed nonce, see WithNonce
+	apiKeySessionTokenHeader     = DefaultHeaderPrefix + sessionTokenHeaderName     // Header for the session token of temporary credentials
 )
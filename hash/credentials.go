@@ -0,0 +1,76 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+/*
+This file provides the CredentialsProvider abstraction, allowing the API key
+identifier and secret used for signing to be fetched at signing time instead
+of being captured once, so that rotated credentials, e.g. refreshed from
+Vault, a mounted Kubernetes secret, or the environment, are picked up
+without rebuilding the Generator or the client.
+
+# Usage
+
+    provider := hash.CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+        return vault.CurrentKey(ctx)
+    })
+    gen := hash.NewGeneratorWithProvider(provider)
+    signedReq := gen.AddAuthHeaders(req)
+*/
+
+// CredentialsProvider returns the credentials currently in use for signing
+// requests, it is invoked for every signed request and is expected to be
+// cheap, caching the credentials as needed.
+type CredentialsProvider interface {
+	// Current returns the API key identifier and secret to sign with
+	Current(ctx context.Context) (id, secret string, err error)
+}
+
+// CredentialsProviderFunc adapts a function to the CredentialsProvider
+// interface.
+type CredentialsProviderFunc func(ctx context.Context) (id, secret string, err error)
+
+// Current invokes the function.
+func (f CredentialsProviderFunc) Current(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// staticCredentials is a CredentialsProvider always returning the same
+// credentials
+type staticCredentials struct {
+	id     string
+	secret string
+}
+
+// Current returns the static credentials.
+func (s *staticCredentials) Current(ctx context.Context) (string, string, error) {
+	return s.id, s.secret, nil
+}
+
+// StaticCredentials returns a CredentialsProvider for fixed credentials.
+func StaticCredentials(id, secret string) CredentialsProvider {
+	return &staticCredentials{
+		id:     id,
+		secret: secret,
+	}
+}
+
+// EnvCredentials returns a CredentialsProvider reading the API key
+// identifier and secret from the given environment variables on every
+// call, failing if either of them is not set.
+func EnvCredentials(idVar, secretVar string) CredentialsProvider {
+	return CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		id, secret := os.Getenv(idVar), os.Getenv(secretVar)
+		if id == "" || secret == "" {
+			return "", "", fmt.Errorf("credentials not available in %s and %s", idVar, secretVar)
+		}
+		return id, secret, nil
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGeneratorWithProviderRotation(t *testing.T) {
+	secret := "first"
+	provider := CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		if secret == "" {
+			return "", "", fmt.Errorf("no credentials")
+		}
+		return "key-" + secret, secret, nil
+	})
+	gen := NewGeneratorWithProvider(provider)
+	validator := NewValidator(60)
+
+	for _, s := range []string{"first", "second"} {
+		secret = s
+		req, _ := http.NewRequest(http.MethodGet, "http://localhost/resource", nil)
+		gen.AddAuthHeaders(req)
+		if keyId := validator.GetKeyId(req); keyId != "key-"+s {
+			t.Errorf("expected key id key-%s, got %s", s, keyId)
+		}
+		if ok, err := validator.Validate(req, s); !ok {
+			t.Errorf("expected request signed with %s to be valid: %s", s, err)
+		}
+	}
+
+	secret = ""
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost/resource", nil)
+	gen.AddAuthHeaders(req)
+	if req.Header.Get(apiKeySignatureHeader) != "" {
+		t.Errorf("expected request not to be signed without credentials")
+	}
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TEST_API_KEY_ID", "env-key")
+	t.Setenv("TEST_API_KEY_SECRET", "env-secret")
+	id, secret, err := EnvCredentials("TEST_API_KEY_ID", "TEST_API_KEY_SECRET").Current(context.Background())
+	if err != nil || id != "env-key" || secret != "env-secret" {
+		t.Errorf("unexpected credentials %s/%s: %v", id, secret, err)
+	}
+	if _, _, err := EnvCredentials("TEST_MISSING_ID", "TEST_API_KEY_SECRET").Current(context.Background()); err == nil {
+		t.Errorf("expected error for missing environment variable")
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This file provides delegated credentials, derived from an existing API key
+entirely on the client side and attenuated by caveats, in the spirit of
+macaroons, so that a job can hand restricted sub-credentials to its workers
+without a round trip to the key service.
+
+A delegated credential consists of a key ID encoding the parent key ID and
+the list of caveats, and a secret chained over them:
+
+    secret_0 = HMAC-SHA256(secret, keyId)
+    secret_i = HMAC-SHA256(secret_i-1, name_i + "\n" + value_i)
+
+The holder of a delegated credential can attenuate it further by adding
+caveats, but can never remove one, as that requires an earlier secret of
+the chain. Requests are signed with the delegated credential like with any
+other API key, and verified with the secret derived by the
+DelegatingSecretResolver from the secret of the parent key, while the
+DelegationValidator enforces the caveats. Unknown caveats are rejected.
+
+# Usage
+
+    // job side
+    id, secret, _ := hash.Delegate(keyId, keySecret,
+        hash.CaveatPathPrefix("/v1/reports/"),
+        hash.CaveatMethods(http.MethodGet),
+        hash.CaveatExpiry(time.Now().Add(time.Hour)))
+    gen := hash.NewGenerator(id, secret)
+
+    // server side
+    resolver = hash.NewDelegatingSecretResolver(resolver)
+    validator = hash.NewDelegationValidator(validator)
+*/
+
+// delegatedKeyPrefix prefixes the key IDs of delegated credentials
+const delegatedKeyPrefix = "dlg."
+
+// maxCaveats limits the number of caveats of a delegated credential
+const maxCaveats = 32
+
+// Caveat names
+const (
+	CaveatNamePathPrefix = "path-prefix"
+	CaveatNameMethods    = "methods"
+	CaveatNameExpiry     = "expiry"
+)
+
+// ErrCaveatNotSatisfied is returned for validly signed requests of a
+// delegated credential whose caveats are not satisfied by the request.
+var ErrCaveatNotSatisfied = errors.New("delegated credential caveat not satisfied")
+
+// Caveat restricts the use of a delegated credential.
+type Caveat struct {
+	Name  string `json:"n"`
+	Value string `json:"v"`
+}
+
+// CaveatPathPrefix restricts the credential to paths with the prefix.
+func CaveatPathPrefix(prefix string) Caveat {
+	return Caveat{Name: CaveatNamePathPrefix, Value: prefix}
+}
+
+// CaveatMethods restricts the credential to the HTTP methods.
+func CaveatMethods(methods ...string) Caveat {
+	return Caveat{Name: CaveatNameMethods, Value: strings.Join(methods, ",")}
+}
+
+// CaveatExpiry restricts the credential to requests signed before the
+// time.
+func CaveatExpiry(t time.Time) Caveat {
+	return Caveat{Name: CaveatNameExpiry, Value: strconv.FormatInt(t.Unix(), 10)}
+}
+
+// delegation is the content of a delegated key ID.
+type delegation struct {
+	KeyId   string   `json:"k"`
+	Caveats []Caveat `json:"c"`
+}
+
+// IsDelegatedKeyId reports whether the key ID is of a delegated
+// credential.
+func IsDelegatedKeyId(keyId string) bool {
+	return strings.HasPrefix(keyId, delegatedKeyPrefix)
+}
+
+// parseDelegatedKeyId decodes the delegated key ID.
+func parseDelegatedKeyId(keyId string) (*delegation, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(keyId, delegatedKeyPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid delegated key id: %s", err)
+	}
+	d := &delegation{}
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, fmt.Errorf("invalid delegated key id: %s", err)
+	}
+	if d.KeyId == "" || IsDelegatedKeyId(d.KeyId) || len(d.Caveats) == 0 || len(d.Caveats) > maxCaveats {
+		return nil, fmt.Errorf("invalid delegated key id")
+	}
+	return d, nil
+}
+
+// attenuate chains the caveats to the secret.
+func attenuate(secret string, caveats []Caveat) string {
+	for _, c := range caveats {
+		secret = hex.EncodeToString(generateHMAC(sha256.New, secret, c.Name, c.Value))
+	}
+	return secret
+}
+
+// chain derives the secret of the delegated credential.
+func chain(secret string, d *delegation) string {
+	return attenuate(GenerateSHA256HMAC(secret, d.KeyId), d.Caveats)
+}
+
+// Delegate derives a delegated credential attenuated by the caveats from
+// the API key, which may itself be a delegated credential, returning the
+// key ID and secret of the delegated credential.
+func Delegate(keyId, secret string, caveats ...Caveat) (string, string, error) {
+	if len(caveats) == 0 {
+		return "", "", fmt.Errorf("at least one caveat is required")
+	}
+	d := &delegation{KeyId: keyId}
+	if IsDelegatedKeyId(keyId) {
+		parent, err := parseDelegatedKeyId(keyId)
+		if err != nil {
+			return "", "", err
+		}
+		d = parent
+	}
+	if len(d.Caveats)+len(caveats) > maxCaveats {
+		return "", "", fmt.Errorf("too many caveats")
+	}
+	s := secret
+	if len(d.Caveats) == 0 {
+		s = GenerateSHA256HMAC(secret, keyId)
+	}
+	s = attenuate(s, caveats)
+	d.Caveats = append(d.Caveats, caveats...)
+	b, err := json.Marshal(d)
+	if err != nil {
+		return "", "", err
+	}
+	return delegatedKeyPrefix + base64.RawURLEncoding.EncodeToString(b), s, nil
+}
+
+// DelegatingSecretResolver resolves the secrets of delegated credentials
+// from the secret of their parent API key, and other key IDs using the
+// underlying resolver.
+type DelegatingSecretResolver struct {
+	base SecretResolver
+}
+
+// NewDelegatingSecretResolver creates a resolver deriving the secrets of
+// delegated credentials using the base resolver.
+func NewDelegatingSecretResolver(base SecretResolver) *DelegatingSecretResolver {
+	return &DelegatingSecretResolver{base: base}
+}
+
+// GetSecret returns the secret of the key ID.
+func (r *DelegatingSecretResolver) GetSecret(ctx context.Context, keyId string) (string, error) {
+	if !IsDelegatedKeyId(keyId) {
+		return r.base.GetSecret(ctx, keyId)
+	}
+	d, err := parseDelegatedKeyId(keyId)
+	if err != nil {
+		return "", err
+	}
+	secret, err := r.base.GetSecret(ctx, d.KeyId)
+	if err != nil {
+		return "", err
+	}
+	return chain(secret, d), nil
+}
+
+// ParentKeyId returns the API key from which the key ID is delegated, or
+// the key ID itself if it is not delegated.
+func ParentKeyId(keyId string) string {
+	if !IsDelegatedKeyId(keyId) {
+		return keyId
+	}
+	d, err := parseDelegatedKeyId(keyId)
+	if err != nil {
+		return ""
+	}
+	return d.KeyId
+}
+
+// satisfied reports whether the request satisfies the caveat.
+func (c *Caveat) satisfied(r *http.Request, now time.Time) bool {
+	switch c.Name {
+	case CaveatNamePathPrefix:
+		return strings.HasPrefix(r.URL.Path, c.Value)
+	case CaveatNameMethods:
+		return slices.Contains(strings.Split(c.Value, ","), r.Method)
+	case CaveatNameExpiry:
+		secs, err := strconv.ParseInt(c.Value, 10, 64)
+		return err == nil && now.Unix() < secs
+	}
+	return false
+}
+
+// delegationValidator enforces the caveats of delegated credentials.
+type delegationValidator struct {
+	Validator
+}
+
+// Unwrap returns the wrapped validator.
+func (v *delegationValidator) Unwrap() Validator {
+	return v.Validator
+}
+
+// Validate validates the request using the wrapped validator, and for
+// delegated credentials ensures that all the caveats are satisfied.
+func (v *delegationValidator) Validate(r *http.Request, secret string) (bool, error) {
+	ok, err := v.Validator.Validate(r, secret)
+	if !ok {
+		return ok, err
+	}
+	keyId := v.GetKeyId(r)
+	if !IsDelegatedKeyId(keyId) {
+		return true, nil
+	}
+	d, err := parseDelegatedKeyId(keyId)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for i := range d.Caveats {
+		if !d.Caveats[i].satisfied(r, now) {
+			return false, fmt.Errorf("%w: %s", ErrCaveatNotSatisfied, d.Caveats[i].Name)
+		}
+	}
+	return true, nil
+}
+
+// NewDelegationValidator returns a validator enforcing the caveats of the
+// delegated credentials, on top of the base validator.
+func NewDelegationValidator(base Validator) Validator {
+	return &delegationValidator{Validator: base}
+}
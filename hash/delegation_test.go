@@ -0,0 +1,62 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDelegatedCredentials(t *testing.T) {
+	resolver := NewDelegatingSecretResolver(SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "supersecret", nil
+	}))
+	validator := NewDelegationValidator(NewValidator(60))
+	validate := func(id, secret, method, path string) error {
+		req := NewGenerator(id, secret).AddAuthHeaders(httptest.NewRequest(method, path, nil))
+		s, err := resolver.GetSecret(req.Context(), validator.GetKeyId(req))
+		if err != nil {
+			return err
+		}
+		_, err = validator.Validate(req, s)
+		return err
+	}
+
+	id, secret, err := Delegate("job-key", "supersecret", CaveatPathPrefix("/v1/reports/"), CaveatMethods("GET"))
+	if err != nil {
+		t.Fatalf("failed to delegate: %s", err)
+	}
+	if ParentKeyId(id) != "job-key" {
+		t.Errorf("expected parent key job-key, got %s", ParentKeyId(id))
+	}
+	if err := validate(id, secret, "GET", "/v1/reports/42"); err != nil {
+		t.Errorf("expected delegated request to be valid: %s", err)
+	}
+	if err := validate(id, secret, "DELETE", "/v1/reports/42"); !errors.Is(err, ErrCaveatNotSatisfied) {
+		t.Errorf("expected method caveat to be enforced, got %v", err)
+	}
+	if err := validate(id, secret, "GET", "/v1/keys"); !errors.Is(err, ErrCaveatNotSatisfied) {
+		t.Errorf("expected path caveat to be enforced, got %v", err)
+	}
+
+	// further attenuation by the holder
+	sub, subSecret, err := Delegate(id, secret, CaveatExpiry(time.Now().Add(-time.Minute)))
+	if err != nil {
+		t.Fatalf("failed to attenuate: %s", err)
+	}
+	if err := validate(sub, subSecret, "GET", "/v1/reports/42"); !errors.Is(err, ErrCaveatNotSatisfied) {
+		t.Errorf("expected expiry caveat to be enforced, got %v", err)
+	}
+
+	// caveats cannot be removed, as the chained secret changes
+	if err := validate(id, subSecret, "GET", "/v1/reports/42"); err == nil {
+		t.Errorf("expected attenuated secret to be invalid for the parent credential")
+	}
+	if _, _, err := Delegate("job-key", "supersecret"); err == nil {
+		t.Errorf("expected delegation without caveats to fail")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+/*
+This file provides the derived key scheme, allowing the servers to
+validate requests without holding the plaintext secrets.
+
+The secret is never stored, instead the key store holds its verifier, the
+HKDF-SHA256 derivation of the secret bound to a scope, e.g. the service or
+region. Requests are signed with a signing key derived from the verifier
+for the UTC date of the request timestamp, so the client derives the
+signing key from the secret, while the server derives the same key from
+the stored verifier:
+
+    signing key = HMAC-SHA256(HKDF-SHA256(secret, scope), yyyymmdd)
+
+A leaked signing key is thus only usable for a day and in its scope. The
+verifier however is equivalent to the secret within its scope: whoever
+holds it derives the signing key of any date and signs requests as the
+client. It must be protected like the secret, e.g. sealed at rest as
+apikey.WithDerivedVerifiers does, the scheme only keeping the plaintext
+secret handed over to the client, and usable in other scopes, out of the
+key store.
+
+# Usage
+
+    // when the key is created, store the verifier instead of the secret
+    verifier := hash.DeriveVerifier(secret, "billing")
+
+    // client
+    gen := hash.NewGenerator(id, secret, hash.WithDerivedSigningKey("billing"))
+
+    // server, with the SecretResolver returning the stored verifier
+    v := hash.NewValidator(60, hash.WithDerivedSigningKey("billing"))
+    ok, err := v.Validate(req, verifier)
+*/
+
+// derivationSalt is the HKDF salt of the verifiers, separating them from
+// keys derived from the same secret for other purposes.
+var derivationSalt = []byte("go-core-stack/auth/derived-key")
+
+// signingDateFormat is the format of the date the signing keys are
+// derived for.
+const signingDateFormat = "20060102"
+
+// SigningDate returns the date, in UTC, the signing key of a request
+// signed at the time is derived for.
+func SigningDate(t time.Time) string {
+	return t.UTC().Format(signingDateFormat)
+}
+
+// DeriveVerifier returns the hex encoded HKDF-SHA256 derivation of the
+// secret bound to the scope, stored by the server in place of the secret
+// and to be protected as such.
+func DeriveVerifier(secret, scope string) string {
+	// HKDF-SHA256 fails only for lengths over 255 hash sizes
+	key, _ := hkdf.Key(sha256.New, []byte(secret), derivationSalt, scope, sha256.Size)
+	return hex.EncodeToString(key)
+}
+
+// DeriveSigningKeyFromVerifier returns the signing key for the date, as
+// formatted by SigningDate, derived from the stored verifier.
+func DeriveSigningKeyFromVerifier(verifier, date string) string {
+	return GenerateSHA256HMAC(verifier, date)
+}
+
+// DeriveSigningKey returns the signing key for the date, as formatted by
+// SigningDate, and scope derived from the secret, the same key the server
+// derives from the verifier of the secret.
+func DeriveSigningKey(secret, date, scope string) string {
+	return DeriveSigningKeyFromVerifier(DeriveVerifier(secret, scope), date)
+}
+
+// WithDerivedSigningKey makes the Generator sign the requests with the
+// signing key derived from the secret for the scope and the date of the
+// request, and the Validator validate them against the signing key derived
+// from the verifier passed in place of the secret, see DeriveVerifier. The
+// same scope needs to be configured on both the Generator and the
+// Validator.
+func WithDerivedSigningKey(scope string) Option {
+	return func(o *options) {
+		o.derived = true
+		o.derivedScope = scope
+	}
+}
+
+// signingKey returns the key the Generator signs a request with at the
+// time, the secret itself unless derived signing keys are configured.
+func (o *options) signingKey(secret string, t time.Time) string {
+	if !o.derived {
+		return secret
+	}
+	return DeriveSigningKey(secret, SigningDate(t), o.derivedScope)
+}
+
+// verificationKey returns the key the Validator verifies a request signed
+// at the time with, the secret itself unless derived signing keys are
+// configured, in which case the secret is the verifier.
+func (o *options) verificationKey(secret string, t time.Time) string {
+	if !o.derived {
+		return secret
+	}
+	return DeriveSigningKeyFromVerifier(secret, SigningDate(t))
+}
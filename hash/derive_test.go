@@ -0,0 +1,48 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDeriveSigningKey(t *testing.T) {
+	verifier := DeriveVerifier("supersecret", "billing")
+	if verifier == DeriveVerifier("supersecret", "orders") {
+		t.Errorf("expected verifiers to be bound to the scope")
+	}
+	date := SigningDate(time.Date(2025, 6, 1, 23, 30, 0, 0, time.FixedZone("PDT", -7*3600)))
+	if date != "20250602" {
+		t.Errorf("expected UTC signing date, got %s", date)
+	}
+	key := DeriveSigningKey("supersecret", date, "billing")
+	if key != DeriveSigningKeyFromVerifier(verifier, date) {
+		t.Errorf("expected the same signing key from the secret and the verifier")
+	}
+	if key == DeriveSigningKey("supersecret", "20250603", "billing") {
+		t.Errorf("expected signing keys to be bound to the date")
+	}
+}
+
+func TestDerivedSigningKeyValidation(t *testing.T) {
+	gen := NewGenerator("key-id", "supersecret", WithDerivedSigningKey("billing"))
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = gen.AddAuthHeaders(req)
+
+	v := NewValidator(60, WithDerivedSigningKey("billing"))
+	if ok, err := v.Validate(req, DeriveVerifier("supersecret", "billing")); !ok {
+		t.Fatalf("expected request to validate against the verifier: %s", err)
+	}
+	if ok, _ := v.Validate(req, DeriveVerifier("supersecret", "orders")); ok {
+		t.Errorf("expected the verifier of another scope to be rejected")
+	}
+	if ok, _ := v.Validate(req, "supersecret"); ok {
+		t.Errorf("expected the plaintext secret not to be accepted as verifier")
+	}
+	if ok, _ := NewValidator(60).Validate(req, "supersecret"); ok {
+		t.Errorf("expected derived signature to fail plain validation")
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build contracttest
+
+package hash
+
+import (
+	"time"
+)
+
+// DeterministicAvailable reports whether deterministic signing is compiled
+// in.
+const DeterministicAvailable = true
+
+// WithDeterministic makes the Generator sign every request with the
+// injected timestamp and emit the injected nonce in the x-nonce header, so
+// that golden-file contract tests across SDKs produce byte-identical signed
+// requests. Only available in builds with the contracttest build tag, a
+// no-op otherwise.
+func WithDeterministic(timestamp time.Time, nonce string) Option {
+	return func(o *options) {
+		o.fixedTime = timestamp
+		o.nonce = nonce
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build !contracttest
+
+package hash
+
+import (
+	"time"
+)
+
+// DeterministicAvailable reports whether deterministic signing is compiled
+// in.
+const DeterministicAvailable = false
+
+// WithDeterministic is a no-op, deterministic signing is only available in
+// builds with the contracttest build tag, guarding production builds
+// against signing with a fixed timestamp and nonce.
+func WithDeterministic(timestamp time.Time, nonce string) Option {
+	return func(o *options) {}
+}
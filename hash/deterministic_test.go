@@ -0,0 +1,41 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithDeterministic(t *testing.T) {
+	ts := time.Date(2025, 5, 28, 5, 38, 8, 0, time.UTC)
+	gen := NewGenerator("test-key", "supersecret", WithDeterministic(ts, "n0nce"))
+
+	first := gen.AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+	second := gen.AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+
+	if DeterministicAvailable {
+		if got := first.Header.Get(apiKeyTimestampHeader); got != "2025-05-28T05:38:08Z" {
+			t.Errorf("expected injected timestamp, got %q", got)
+		}
+		if got := first.Header.Get(apiKeyNonceHeader); got != "n0nce" {
+			t.Errorf("expected injected nonce, got %q", got)
+		}
+		want := GenerateSHA256HMAC("supersecret", "GET", "/resource", "2025-05-28T05:38:08Z", "n0nce")
+		if got := first.Header.Get(apiKeySignatureHeader); got != want {
+			t.Errorf("expected signature %s, got %s", want, got)
+		}
+		if first.Header.Get(apiKeySignatureHeader) != second.Header.Get(apiKeySignatureHeader) {
+			t.Errorf("expected byte-identical signatures")
+		}
+	} else {
+		if first.Header.Get(apiKeyNonceHeader) != "" {
+			t.Errorf("expected no nonce without the contracttest build tag")
+		}
+		if first.Header.Get(apiKeyTimestampHeader) == "2025-05-28T05:38:08Z" {
+			t.Errorf("expected current time without the contracttest build tag")
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+/*
+This file provides an asymmetric alternative to the HMAC based signing,
+for partners who should not share a symmetric secret with the server.
+
+The client signs requests with its Ed25519 private key, while the server
+only holds the registered public key looked up by x-api-key-id. Both sides
+implement the same Generator and Validator interfaces, where the "secret"
+passed to Validate is the hex-encoded public key of the API key.
+
+# Usage
+
+    pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+
+    // client side
+    gen := hash.NewEd25519Generator("partner-key-id", priv)
+    signedReq := gen.AddAuthHeaders(req)
+
+    // server side, public key registered for "partner-key-id"
+    validator := hash.NewEd25519Validator(60)
+    ok, err := validator.Validate(signedReq, hash.EncodeEd25519PublicKey(pub))
+*/
+
+// Ed25519 identifies signatures computed with an Ed25519 private key.
+const Ed25519 Algorithm = "ed25519"
+
+// EncodeEd25519PublicKey returns the hex encoding of the public key, which
+// is the format expected by the Ed25519 Validator.
+func EncodeEd25519PublicKey(pub ed25519.PublicKey) string {
+	return hex.EncodeToString(pub)
+}
+
+// ParseEd25519PublicKey decodes a hex-encoded Ed25519 public key.
+func ParseEd25519PublicKey(s string) (ed25519.PublicKey, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid ed25519 public key")
+	}
+	return ed25519.PublicKey(b), nil
+}
+
+// ed25519Generator is the Ed25519 implementation of the Generator interface.
+type ed25519Generator struct {
+	id   string             // API key identifier
+	key  ed25519.PrivateKey // Private key for signing
+	opts *options           // Optional configuration
+}
+
+// AddAuthHeaders attaches authentication headers to the given HTTP request,
+// same as the HMAC generator, except that x-signature carries the hex-encoded
+// Ed25519 signature of the canonical string of the signature version.
+func (g *ed25519Generator) AddAuthHeaders(r *http.Request) *http.Request {
+	timeStamp := g.opts.timestamp(g.opts.signingTime())
+	nonce := g.opts.signingNonce()
+
+	canonical, err := canonicalString(g.opts.version, r, timeStamp, nonce)
+	if err != nil {
+		return r
+	}
+	sig := ed25519.Sign(g.key, []byte(canonical))
+
+	r.Header.Set(g.opts.headers.Signature, hex.EncodeToString(sig))
+	r.Header.Set(g.opts.headers.Algorithm, Ed25519.String())
+	r.Header.Set(g.opts.headers.Version, string(g.opts.version))
+	r.Header.Set(g.opts.headers.KeyId, g.id)
+	r.Header.Set(g.opts.headers.Timestamp, timeStamp)
+	if nonce != "" {
+		r.Header.Set(g.opts.headers.Nonce, nonce)
+	} else {
+		r.Header.Del(g.opts.headers.Nonce)
+	}
+	return r
+}
+
+// NewEd25519Generator creates a Generator signing requests with the provided
+// Ed25519 private key.
+//
+// Parameters:
+//   - id:   API key identifier, for which the public key is registered
+//   - key:  Ed25519 private key of the client
+//   - opts: Optional configuration such as header names
+func NewEd25519Generator(id string, key ed25519.PrivateKey, opts ...Option) Generator {
+	return &ed25519Generator{
+		id:   id,
+		key:  key,
+		opts: newOptions(opts...),
+	}
+}
+
+// ed25519Validator is the Ed25519 implementation of the Validator interface,
+// it shares the header and validity checks with the HMAC validator.
+type ed25519Validator struct {
+	validator
+}
+
+// Validate checks the Ed25519 signature, timestamp, and expiration of the
+// HTTP request, where publicKey is the hex-encoded public key registered
+// for the API key carried in x-api-key-id.
+func (v *ed25519Validator) Validate(r *http.Request, publicKey string) (bool, error) {
+	return v.observe(r, Ed25519, func() (bool, error) {
+		return v.validateEd25519(r, publicKey)
+	})
+}
+
+// validateEd25519 performs the Ed25519 validation of the request.
+func (v *ed25519Validator) validateEd25519(r *http.Request, publicKey string) (bool, error) {
+	sig, timeStr, err := v.checkHeaders(r)
+	if err != nil {
+		return false, err
+	}
+
+	if alg := Algorithm(r.Header.Get(v.opts.headers.Algorithm)); alg != Ed25519 {
+		return false, failure(ReasonAlgorithmNotAllowed, "signature algorithm not allowed: %s", alg)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return false, failure(ReasonMalformedHeader, "invalid signature length for %s", Ed25519)
+	}
+
+	if v.opts.requestVersion(r) == SignatureStreaming {
+		return false, failure(ReasonVersionNotAllowed, "signature version not supported with ed25519: %s", SignatureStreaming)
+	}
+	if v.opts.requestImpersonation(r) != nil {
+		return false, failure(ReasonInvalid, "impersonation not supported with ed25519")
+	}
+
+	pub, err := ParseEd25519PublicKey(publicKey)
+	if err != nil {
+		return false, err
+	}
+
+	canonical, err := v.signedString(r, timeStr)
+	if err != nil {
+		return false, err
+	}
+	if !ed25519.Verify(pub, []byte(canonical), sig) {
+		return false, errEd25519Mismatch
+	}
+
+	return true, nil
+}
+
+// NewEd25519Validator creates a Validator verifying Ed25519 signed requests.
+//
+// Parameters:
+//   - validity: Allowed time window (in seconds) for the request to be valid.
+//   - opts:     Optional configuration such as header names
+func NewEd25519Validator(validity int64, opts ...Option) Validator {
+	return &ed25519Validator{
+		validator: validator{
+			validity: validity,
+			opts:     newOptions(opts...),
+		},
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEd25519GeneratorAndValidator(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %s", err)
+	}
+
+	req := httptest.NewRequest("POST", "https://api.example.com/resource", nil)
+	signedReq := NewEd25519Generator("partner-key", priv).AddAuthHeaders(req)
+
+	validator := NewEd25519Validator(60)
+	if id := validator.GetKeyId(signedReq); id != "partner-key" {
+		t.Errorf("expected key id partner-key, got %s", id)
+	}
+	ok, err := validator.Validate(signedReq, EncodeEd25519PublicKey(pub))
+	if !ok {
+		t.Fatalf("Validation failed: %v", err)
+	}
+
+	// a different public key must not validate the request
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if ok, _ := validator.Validate(signedReq, EncodeEd25519PublicKey(other)); ok {
+		t.Fatalf("expected validation to fail with a different public key")
+	}
+
+	// tampering with the path must invalidate the signature
+	signedReq.URL.Path = "/other"
+	if ok, _ := validator.Validate(signedReq, EncodeEd25519PublicKey(pub)); ok {
+		t.Fatalf("expected validation to fail for tampered path")
+	}
+
+	// HMAC validator must not accept ed25519 signed requests
+	req = httptest.NewRequest("POST", "https://api.example.com/resource", nil)
+	signedReq = NewEd25519Generator("partner-key", priv).AddAuthHeaders(req)
+	if ok, _ := NewValidator(60).Validate(signedReq, EncodeEd25519PublicKey(pub)); ok {
+		t.Fatalf("expected hmac validator to reject ed25519 signature")
+	}
+}
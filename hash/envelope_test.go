@@ -0,0 +1,78 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerifyEnvelope(t *testing.T) {
+	payload := []byte(`{"order":42}`)
+	env := SignMessage("supersecret", "api-key-id", payload, time.Now())
+
+	if err := VerifyEnvelope("supersecret", &env, 300); err != nil {
+		t.Fatalf("verification failed: %s", err)
+	}
+	if other := SignMessage("supersecret", "api-key-id", payload, time.Now()); other.Nonce == env.Nonce || other.Signature == env.Signature {
+		t.Errorf("expected every envelope to carry a unique nonce")
+	}
+
+	// the envelope survives the transport encoding
+	b, _ := json.Marshal(env)
+	decoded := &SignedEnvelope{}
+	if err := json.Unmarshal(b, decoded); err != nil {
+		t.Fatalf("failed to decode envelope: %s", err)
+	}
+	if err := VerifyEnvelope("supersecret", decoded, 300); err != nil {
+		t.Errorf("verification of decoded envelope failed: %s", err)
+	}
+
+	if err := VerifyEnvelope("other", &env, 300); Reason(err) != ReasonSignatureMismatch {
+		t.Errorf("expected signature mismatch with a different secret, got %v", err)
+	}
+	tampered := env
+	tampered.Payload = []byte(`{"order":43}`)
+	if err := VerifyEnvelope("supersecret", &tampered, 300); Reason(err) != ReasonSignatureMismatch {
+		t.Errorf("expected signature mismatch for a tampered payload, got %v", err)
+	}
+	tampered = env
+	tampered.KeyId = "other-key-id"
+	if err := VerifyEnvelope("supersecret", &tampered, 300); Reason(err) != ReasonSignatureMismatch {
+		t.Errorf("expected signature mismatch for a different key id, got %v", err)
+	}
+	tampered = env
+	tampered.Nonce = ""
+	if err := VerifyEnvelope("supersecret", &tampered, 300); Reason(err) != ReasonMissingHeader {
+		t.Errorf("expected missing nonce, got %v", err)
+	}
+	tampered = env
+	tampered.Algorithm = "md5"
+	if err := VerifyEnvelope("supersecret", &tampered, 300); Reason(err) != ReasonAlgorithmNotAllowed {
+		t.Errorf("expected algorithm not allowed, got %v", err)
+	}
+}
+
+func TestEnvelopeExpiry(t *testing.T) {
+	env := SignMessage("supersecret", "api-key-id", nil, time.Now().Add(-10*time.Minute))
+	if err := VerifyEnvelope("supersecret", &env, 300); Reason(err) != ReasonExpired {
+		t.Errorf("expected envelope to be expired, got %v", err)
+	}
+	if err := VerifyEnvelope("supersecret", &env, 3600); err != nil {
+		t.Errorf("expected envelope within validity, got %s", err)
+	}
+
+	// epoch timestamps are accepted as by the Validator
+	env.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+	env.Signature = GenerateSHA256HMAC("supersecret", envelopeComponents(&env)...)
+	if err := VerifyEnvelope("supersecret", &env, 300); err != nil {
+		t.Errorf("expected epoch timestamp to be accepted, got %s", err)
+	}
+	env.Timestamp = "yesterday"
+	if err := VerifyEnvelope("supersecret", &env, 300); Reason(err) != ReasonMalformedHeader {
+		t.Errorf("expected malformed timestamp, got %v", err)
+	}
+}
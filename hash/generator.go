@@ -4,12 +4,8 @@
 package hash
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"net/http"
-	"strings"
-	"time"
 )
 
 /*
@@ -65,28 +61,25 @@ Signing HTTP Requests:
   - AddAuthHeaders(r *http.Request) *http.Request
     Adds authentication headers to the provided HTTP request.
 
-- NewGenerator(id, secret string) Generator
+- NewGenerator(id, secret string, opts ...Option) Generator
 
   - id:     API key identifier.
   - secret: Secret key for HMAC signing.
+  - opts:   Optional configuration, e.g. WithAlgorithm(HMACSHA512).
 
   Returns a Generator instance for signing HTTP requests.
+
+- NewGeneratorWithProvider(creds CredentialsProvider, opts ...Option) Generator
+
+  Returns a Generator fetching the credentials from the provider for every
+  request, allowing credentials to be rotated at runtime.
 */
 
 // generateSHA256HMAC computes the raw SHA-256 HMAC for the concatenated input strings using the provided secret key.
 // Returns the HMAC as a byte slice (not hex-encoded).
 func generateSHA256HMAC(secret string, v ...string) []byte {
-	// Concatenate all input strings into a single string
-	raw := strings.Join(v, "\n")
-
-	// Create a new HMAC hasher using SHA-256 and the provided secret key
-	h := hmac.New(sha256.New, []byte(secret))
-
-	// Write the concatenated string to the hasher
-	h.Write([]byte(raw))
-
-	// Return the raw HMAC bytes
-	return h.Sum(nil)
+	// Compute the HMAC using SHA-256 over the newline joined input strings
+	return signers[HMACSHA256].AppendSum(nil, secret, v...)
 }
 
 // GenerateSHA256HMAC generates a SHA-256 HMAC signature for the given input strings using the provided secret key.
@@ -103,7 +96,7 @@ func generateSHA256HMAC(secret string, v ...string) []byte {
 //	// sig now contains the hex-encoded HMAC of "foobar" using "mysecret" as the key.
 func GenerateSHA256HMAC(secret string, v ...string) string {
 	// Compute the HMAC and return it as a hex-encoded string
-	return hex.EncodeToString(generateSHA256HMAC(secret, v...))
+	return signers[HMACSHA256].Sign(secret, v...)
 }
 
 // Generator defines an interface for adding authentication headers to HTTP requests.
@@ -114,34 +107,81 @@ type Generator interface {
 }
 
 // generator is a concrete implementation of the Generator interface.
-// It holds the API key ID and secret used for signing requests.
+// It holds the provider of the API key ID and secret used for signing requests.
 type generator struct {
-	id     string // API key identifier
-	secret string // Secret key for HMAC signing
+	creds CredentialsProvider // Provider of the API key identifier and secret
+	opts  *options            // Optional configuration
 }
 
 // AddAuthHeaders attaches authentication headers to the given HTTP request.
 // The following headers are added:
-//   - x-signature: HMAC-SHA256 signature of the HTTP method, path, and timestamp
+//   - x-signature: HMAC signature of the components of the signature version
+//   - x-signature-alg: The algorithm used to compute the signature
+//   - x-signature-version: The signature scheme version
 //   - x-api-key-id: The API key identifier
-//   - x-timestamp: The current timestamp in RFC3339 format
+//   - x-timestamp: The current timestamp in RFC3339 format, or epoch seconds with WithEpochTimestamp
+//   - x-nonce: A random nonce with WithNonce, signed along with the timestamp
 //
-// The signature is computed as HMAC(secret, method + path + timestamp).
+// With the default version v1 the signature is computed as
+// HMAC(secret, method + path + timestamp). If the components of the
+// signature version cannot be computed, e.g. the body can't be read, or
+// the credentials are not available, the request is returned without
+// authentication headers. Authentication headers already present, e.g.
+// copied from an earlier hop of a redirected request, are replaced.
 func (g *generator) AddAuthHeaders(r *http.Request) *http.Request {
-	// use RFC3339 format for the time stamp in the header
-	timeStamp := time.Now().Format(time.RFC3339)
+	ctx := r.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id, secret, err := g.creds.Current(ctx)
+	if err != nil {
+		return r
+	}
 
-	// Compute the signature using HTTP method, path, and timestamp
-	sig := GenerateSHA256HMAC(g.secret, r.Method, r.URL.Path, timeStamp)
+	// use RFC3339 format, or epoch seconds if configured, for the time
+	// stamp in the header
+	now := g.opts.signingTime()
+	timeStamp := g.opts.timestamp(now)
+	nonce := g.opts.signingNonce()
+	secret = impersonationKey(g.opts.signingKey(secret, now), g.opts.impersonate)
+
+	// Compute the signature over the canonical string of the signature
+	// version
+	canonical, err := canonicalString(g.opts.version, r, timeStamp, nonce)
+	if err != nil {
+		return r
+	}
+	alg := g.opts.algorithm
+	sig := signers[alg].Sign(secret, canonical)
+	if g.opts.version == SignatureStreaming {
+		signStreamingBody(r, g.opts.headers.ContentSignature, supportedAlgorithms[alg], secret, sig)
+	}
 
-	// Add the computed signature to the request headers
-	r.Header.Add(apiKeySignatureHeader, sig)
+	// Add the computed signature, the algorithm and version used to the request headers
+	r.Header.Set(g.opts.headers.Signature, sig)
+	r.Header.Set(g.opts.headers.Algorithm, alg.String())
+	r.Header.Set(g.opts.headers.Version, string(g.opts.version))
 
 	// Add the API key ID to the request headers
-	r.Header.Add(apiKeyIdHeader, g.id)
+	r.Header.Set(g.opts.headers.KeyId, id)
 
 	// add timestamp to header
-	r.Header.Add(apiKeyTimestampHeader, timeStamp)
+	r.Header.Set(g.opts.headers.Timestamp, timeStamp)
+
+	// add the signed nonce, if any
+	if nonce != "" {
+		r.Header.Set(g.opts.headers.Nonce, nonce)
+	} else {
+		r.Header.Del(g.opts.headers.Nonce)
+	}
+
+	// add the session token of temporary credentials
+	if g.opts.sessionToken != "" {
+		r.Header.Set(g.opts.headers.SessionToken, g.opts.sessionToken)
+	}
+
+	// add the identity impersonated, bound to the signing key
+	g.opts.setImpersonationHeaders(r)
 	return r
 }
 
@@ -150,6 +190,7 @@ func (g *generator) AddAuthHeaders(r *http.Request) *http.Request {
 // Parameters:
 //   - id:     API key identifier
 //   - secret: Secret key for HMAC signing
+//   - opts:   Optional configuration such as the signing algorithm or header names
 //
 // Returns:
 //   - Generator: An instance that can add authentication headers to HTTP requests.
@@ -159,9 +200,16 @@ func (g *generator) AddAuthHeaders(r *http.Request) *http.Request {
 //	gen := hash.NewGenerator("api-key-id", "supersecret")
 //	req, _ := http.NewRequest("GET", "https://api.example.com/resource", nil)
 //	signedReq := gen.AddAuthHeaders(req)
-func NewGenerator(id, secret string) Generator {
+func NewGenerator(id, secret string, opts ...Option) Generator {
+	return NewGeneratorWithProvider(StaticCredentials(id, secret), opts...)
+}
+
+// NewGeneratorWithProvider creates a new Generator fetching the API key
+// identifier and secret from the provider for every signed request, so
+// that rotated credentials are used without recreating the Generator.
+func NewGeneratorWithProvider(creds CredentialsProvider, opts ...Option) Generator {
 	return &generator{
-		id:     id,
-		secret: secret,
+		creds: creds,
+		opts:  newOptions(opts...),
 	}
 }
@@ -0,0 +1,84 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+/*
+This file provides the opt-in guards refusing obviously unsafe signing
+configurations, such as an empty secret or a secret equal to the API key
+identifier, when the Generator is constructed instead of when the requests
+start failing or, worse, succeed against a misconfigured server. The client
+package extends the guards to its transport configuration.
+
+Every failure is an *UnsafeConfigError wrapping one of the Err* sentinels,
+allowing the callers to match the specific check using errors.Is.
+
+# Usage
+
+    gen, err := hash.NewGuardedGenerator(keyId, secret)
+    if errors.Is(err, hash.ErrEmptySecret) {
+        log.Fatal("API secret not configured")
+    }
+*/
+
+// Sentinel errors of the unsafe configurations.
+var (
+	ErrEmptyKeyId        = errors.New("api key id is empty")
+	ErrEmptySecret       = errors.New("api secret is empty")
+	ErrSecretIsKeyId     = errors.New("api secret is equal to the api key id")
+	ErrInsecureTLS       = errors.New("tls verification disabled outside dev mode")
+	ErrPlaintextEndpoint = errors.New("credentials sent to a plaintext http endpoint")
+)
+
+// UnsafeConfigError is returned by the guards for an unsafe configuration.
+type UnsafeConfigError struct {
+	// sentinel of the failed check
+	Err error
+
+	// context of the failure, e.g. the offending endpoint
+	Detail string
+}
+
+// Error returns the description of the unsafe configuration.
+func (e *UnsafeConfigError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("unsafe auth configuration: %s", e.Err)
+	}
+	return fmt.Sprintf("unsafe auth configuration: %s: %s", e.Err, e.Detail)
+}
+
+// Unwrap returns the sentinel of the failed check.
+func (e *UnsafeConfigError) Unwrap() error {
+	return e.Err
+}
+
+// CheckCredentials returns an *UnsafeConfigError if the credentials are
+// obviously unsafe to sign with: an empty key id or secret, or a secret
+// equal to the key id.
+func CheckCredentials(id, secret string) error {
+	switch {
+	case id == "":
+		return &UnsafeConfigError{Err: ErrEmptyKeyId}
+	case secret == "":
+		return &UnsafeConfigError{Err: ErrEmptySecret, Detail: id}
+	case subtle.ConstantTimeCompare([]byte(id), []byte(secret)) == 1:
+		return &UnsafeConfigError{Err: ErrSecretIsKeyId, Detail: id}
+	}
+	return nil
+}
+
+// NewGuardedGenerator creates a new Generator as NewGenerator, failing
+// with an *UnsafeConfigError if the credentials do not pass
+// CheckCredentials.
+func NewGuardedGenerator(id, secret string, opts ...Option) (Generator, error) {
+	if err := CheckCredentials(id, secret); err != nil {
+		return nil, err
+	}
+	return NewGenerator(id, secret, opts...), nil
+}
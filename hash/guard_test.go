@@ -0,0 +1,44 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckCredentials(t *testing.T) {
+	cases := []struct {
+		id, secret string
+		want       error
+	}{
+		{"key", "s3cr3t-value", nil},
+		{"", "s3cr3t-value", ErrEmptyKeyId},
+		{"key", "", ErrEmptySecret},
+		{"key", "key", ErrSecretIsKeyId},
+	}
+	for _, c := range cases {
+		err := CheckCredentials(c.id, c.secret)
+		if c.want == nil {
+			if err != nil {
+				t.Errorf("CheckCredentials(%q, %q) unexpected error: %s", c.id, c.secret, err)
+			}
+			continue
+		}
+		var unsafe *UnsafeConfigError
+		if !errors.Is(err, c.want) || !errors.As(err, &unsafe) {
+			t.Errorf("CheckCredentials(%q, %q) = %v, want %v", c.id, c.secret, err, c.want)
+		}
+	}
+}
+
+func TestNewGuardedGenerator(t *testing.T) {
+	if _, err := NewGuardedGenerator("key", ""); !errors.Is(err, ErrEmptySecret) {
+		t.Errorf("expected empty secret error, got %v", err)
+	}
+	gen, err := NewGuardedGenerator("key", "s3cr3t-value")
+	if err != nil || gen == nil {
+		t.Errorf("expected generator, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+/*
+This file provides the impersonation mode, where a privileged API key signs
+requests on behalf of another identity, e.g. for admin tooling and support
+workflows, carrying the user and tenant impersonated in the
+x-impersonate-user and x-impersonate-tenant headers.
+
+The headers are covered by the signature, so that they can not be added to
+or altered in a request signed without them. HMAC signatures bind them to
+the signing key:
+
+    signing key = HMAC-SHA256(secret, "impersonate" \n USER \n TENANT)
+
+while RFC 9421 message signatures list them among the covered components.
+Ed25519 signatures do not support impersonation and the requests carrying
+the headers fail their validation.
+
+A valid signature only proves that the key asked for the impersonation,
+the ImpersonationValidator additionally ensures that the key is allowed to
+impersonate as per the ImpersonationPolicy, while the apikey Middleware
+populates the model.AuthContext with the effective identity.
+
+# Usage
+
+    // admin tooling
+    gen := hash.NewGenerator(adminKeyId, adminSecret, hash.WithImpersonation("alice", "acme"))
+
+    // server
+    v := hash.NewImpersonationValidator(hash.NewValidator(60), policy)
+*/
+
+// Header names, without prefix, of the impersonated identity.
+const (
+	impersonateUserHeaderName   = "impersonate-user"
+	impersonateTenantHeaderName = "impersonate-tenant"
+)
+
+// Default header names of the impersonated identity.
+const (
+	apiKeyImpersonateUserHeader   = DefaultHeaderPrefix + impersonateUserHeaderName
+	apiKeyImpersonateTenantHeader = DefaultHeaderPrefix + impersonateTenantHeaderName
+)
+
+// ErrImpersonationDenied is returned for validly signed requests of an API
+// key impersonating an identity it is not allowed to.
+var ErrImpersonationDenied = errors.New("impersonation not allowed for api key")
+
+// Impersonation is the identity impersonated by a request.
+type Impersonation struct {
+	User   string
+	Tenant string
+}
+
+// WithImpersonation makes the Generator sign the requests on behalf of the
+// user of the tenant, see the file documentation.
+func WithImpersonation(user, tenant string) Option {
+	return func(o *options) {
+		o.impersonate = &Impersonation{User: user, Tenant: tenant}
+	}
+}
+
+// ImpersonationFromRequest returns the identity impersonated by the
+// request, nil if none. The options must match the ones of the Generator,
+// e.g. for custom header names.
+func ImpersonationFromRequest(r *http.Request, opts ...Option) *Impersonation {
+	return newOptions(opts...).requestImpersonation(r)
+}
+
+// RequestImpersonation returns the identity impersonated by the request as
+// read by the validator, i.e. with the header names it is configured with,
+// nil if none. The wrapping validators are unwrapped, see Unwrapper, and
+// the validators of other packages fall back to the default header names.
+func RequestImpersonation(v Validator, r *http.Request) *Impersonation {
+	for {
+		switch x := v.(type) {
+		case impersonationReader:
+			return x.requestImpersonation(r)
+		case Unwrapper:
+			v = x.Unwrap()
+		default:
+			return ImpersonationFromRequest(r)
+		}
+	}
+}
+
+// Unwrapper is implemented by the validators wrapping another validator,
+// e.g. to enforce additional policies.
+type Unwrapper interface {
+	// Unwrap returns the wrapped validator.
+	Unwrap() Validator
+}
+
+// RequestNonce returns the nonce signed along with the request, as read by
+// the validator, empty if none, e.g. to detect replayed requests, see
+// WithNonce. The wrapping validators are unwrapped, see Unwrapper, and the
+// validators of other packages fall back to the nonce parameter of the
+// message signatures, see IsMessageSignature, or the default header names.
+// The nonce is only signed if the request is validated.
+func RequestNonce(v Validator, r *http.Request) string {
+	for {
+		switch x := v.(type) {
+		case nonceReader:
+			return x.requestNonce(r)
+		case Unwrapper:
+			v = x.Unwrap()
+		default:
+			if IsMessageSignature(r) {
+				return messageSignatureNonce(r)
+			}
+			return r.Header.Get(DefaultHeaderNames().Nonce)
+		}
+	}
+}
+
+// nonceReader is implemented by the validators of this package, reading
+// the nonce with their header names.
+type nonceReader interface {
+	requestNonce(r *http.Request) string
+}
+
+func (v *validator) requestNonce(r *http.Request) string {
+	return r.Header.Get(v.opts.headers.Nonce)
+}
+
+func (v *messageSignatureValidator) requestNonce(r *http.Request) string {
+	return messageSignatureNonce(r)
+}
+
+func (v *impersonationValidator) requestNonce(r *http.Request) string {
+	return RequestNonce(v.Validator, r)
+}
+
+// impersonationReader is implemented by the validators of this package,
+// reading the impersonated identity with their header names.
+type impersonationReader interface {
+	requestImpersonation(r *http.Request) *Impersonation
+}
+
+func (v *validator) requestImpersonation(r *http.Request) *Impersonation {
+	return v.opts.requestImpersonation(r)
+}
+
+func (v *impersonationValidator) requestImpersonation(r *http.Request) *Impersonation {
+	return v.opts.requestImpersonation(r)
+}
+
+// requestImpersonation returns the identity impersonated by the request,
+// nil if none.
+func (o *options) requestImpersonation(r *http.Request) *Impersonation {
+	user := strings.TrimSpace(r.Header.Get(o.headers.ImpersonateUser))
+	tenant := strings.TrimSpace(r.Header.Get(o.headers.ImpersonateTenant))
+	if user == "" && tenant == "" {
+		return nil
+	}
+	return &Impersonation{User: user, Tenant: tenant}
+}
+
+// setImpersonationHeaders sets the headers of the impersonated identity of
+// the Generator, removing the ones copied from an earlier request if the
+// Generator does not impersonate.
+func (o *options) setImpersonationHeaders(r *http.Request) {
+	if o.impersonate == nil {
+		r.Header.Del(o.headers.ImpersonateUser)
+		r.Header.Del(o.headers.ImpersonateTenant)
+		return
+	}
+	r.Header.Set(o.headers.ImpersonateUser, o.impersonate.User)
+	r.Header.Set(o.headers.ImpersonateTenant, o.impersonate.Tenant)
+}
+
+// impersonationKey returns the signing key bound to the impersonated
+// identity.
+func impersonationKey(secret string, i *Impersonation) string {
+	if i == nil {
+		return secret
+	}
+	return GenerateSHA256HMAC(secret, "impersonate", i.User, i.Tenant)
+}
+
+// ImpersonationPolicy decides which identities the API keys may
+// impersonate, implemented by the apikey.Store.
+type ImpersonationPolicy interface {
+	// CanImpersonate returns nil if the key may impersonate the identity.
+	CanImpersonate(ctx context.Context, keyId string, target *Impersonation) error
+}
+
+// ImpersonationPolicyFunc adapts a function to the ImpersonationPolicy.
+type ImpersonationPolicyFunc func(ctx context.Context, keyId string, target *Impersonation) error
+
+// CanImpersonate invokes the function.
+func (f ImpersonationPolicyFunc) CanImpersonate(ctx context.Context, keyId string, target *Impersonation) error {
+	return f(ctx, keyId, target)
+}
+
+// impersonationValidator enforces the impersonation policy.
+type impersonationValidator struct {
+	Validator
+	policy ImpersonationPolicy
+	opts   *options
+}
+
+// Validate validates the request using the wrapped validator, and for
+// requests impersonating an identity ensures that the key is allowed to.
+func (v *impersonationValidator) Validate(r *http.Request, secret string) (bool, error) {
+	ok, err := v.Validator.Validate(r, secret)
+	if !ok {
+		return ok, err
+	}
+	target := v.opts.requestImpersonation(r)
+	if target == nil {
+		return true, nil
+	}
+	if target.User == "" || target.Tenant == "" {
+		return false, failure(ReasonMalformedHeader, "impersonation requires both user and tenant")
+	}
+	if err := v.policy.CanImpersonate(r.Context(), v.GetKeyId(r), target); err != nil {
+		return false, errors.Join(ErrImpersonationDenied, err)
+	}
+	return true, nil
+}
+
+// NewImpersonationValidator returns a validator enforcing the policy for
+// the requests impersonating an identity, on top of the base validator.
+// The options configure the impersonation header names.
+func NewImpersonationValidator(base Validator, policy ImpersonationPolicy, opts ...Option) Validator {
+	return &impersonationValidator{
+		Validator: base,
+		policy:    policy,
+		opts:      newOptions(opts...),
+	}
+}
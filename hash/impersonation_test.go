@@ -0,0 +1,136 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImpersonation(t *testing.T) {
+	policy := ImpersonationPolicyFunc(func(ctx context.Context, keyId string, target *Impersonation) error {
+		if keyId == "admin" && target.Tenant == "acme" {
+			return nil
+		}
+		return errors.New("not granted")
+	})
+	v := NewImpersonationValidator(NewValidator(60), policy)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewGenerator("admin", "secret", WithImpersonation("alice", "acme")).AddAuthHeaders(req)
+	if ok, err := v.Validate(req, "secret"); !ok {
+		t.Fatalf("expected impersonating request to be valid: %s", err)
+	}
+	if i := ImpersonationFromRequest(req); i == nil || i.User != "alice" || i.Tenant != "acme" {
+		t.Errorf("unexpected impersonation %+v", i)
+	}
+
+	// the headers are covered by the signature
+	req.Header.Set("x-impersonate-user", "bob")
+	if ok, _ := v.Validate(req, "secret"); ok {
+		t.Errorf("expected altered impersonated user to be rejected")
+	}
+	plain, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	plain = NewGenerator("admin", "secret").AddAuthHeaders(plain)
+	plain.Header.Set("x-impersonate-user", "alice")
+	plain.Header.Set("x-impersonate-tenant", "acme")
+	if ok, _ := NewValidator(60).Validate(plain, "secret"); ok {
+		t.Errorf("expected impersonation headers added after signing to be rejected")
+	}
+
+	// the policy is enforced
+	req, _ = http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewGenerator("admin", "secret", WithImpersonation("alice", "other")).AddAuthHeaders(req)
+	if _, err := v.Validate(req, "secret"); !errors.Is(err, ErrImpersonationDenied) {
+		t.Errorf("expected impersonation to be denied, got %v", err)
+	}
+
+	// re-signing without impersonation drops the copied headers
+	req = NewGenerator("admin", "secret").AddAuthHeaders(req)
+	if ok, err := v.Validate(req, "secret"); !ok || ImpersonationFromRequest(req) != nil {
+		t.Errorf("expected request re-signed without impersonation, got %v", err)
+	}
+}
+
+func TestImpersonationMessageSignature(t *testing.T) {
+	v := NewMessageSignatureValidator(60)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewMessageSignatureGenerator("admin", "secret", 0, WithImpersonation("alice", "acme")).AddAuthHeaders(req)
+	if ok, err := v.Validate(req, "secret"); !ok {
+		t.Fatalf("expected impersonating request to be valid: %s", err)
+	}
+	req.Header.Set("x-impersonate-tenant", "other")
+	if ok, _ := v.Validate(req, "secret"); ok {
+		t.Errorf("expected altered impersonated tenant to be rejected")
+	}
+
+	plain, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	plain = NewMessageSignatureGenerator("admin", "secret", 0).AddAuthHeaders(plain)
+	plain.Header.Set("x-impersonate-user", "alice")
+	if ok, _ := v.Validate(plain, "secret"); ok {
+		t.Errorf("expected uncovered impersonation header to be rejected")
+	}
+}
+
+func TestRequestImpersonation(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewGenerator("admin", "secret", WithHeaderPrefix("x-acme-"), WithImpersonation("alice", "acme")).AddAuthHeaders(req)
+
+	v := NewValidator(60, WithHeaderPrefix("x-acme-"))
+	if target := RequestImpersonation(v, req); target == nil || target.User != "alice" || target.Tenant != "acme" {
+		t.Errorf("expected the impersonation read with the header names of the validator, got %+v", target)
+	}
+	wrapped := NewDelegationValidator(v)
+	if target := RequestImpersonation(wrapped, req); target == nil || target.User != "alice" {
+		t.Errorf("expected the wrapped validator to be unwrapped, got %+v", target)
+	}
+	if target := RequestImpersonation(NewValidator(60), req); target != nil {
+		t.Errorf("expected no impersonation with the default header names, got %+v", target)
+	}
+}
+
+func TestRequestNonce(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewGenerator("admin", "secret", WithHeaderPrefix("x-acme-"), WithNonce()).AddAuthHeaders(req)
+	msg, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	msg = NewMessageSignatureGenerator("admin", "secret", 0, WithNonce()).AddAuthHeaders(msg)
+	plain, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	plain = NewGenerator("admin", "secret").AddAuthHeaders(plain)
+
+	nonce := req.Header.Get("x-acme-nonce")
+	if nonce == "" {
+		t.Fatalf("expected the request to carry a nonce")
+	}
+	tests := []struct {
+		name  string
+		v     Validator
+		r     *http.Request
+		nonce string
+	}{
+		{"header names of the validator", NewValidator(60, WithHeaderPrefix("x-acme-")), req, nonce},
+		{"default header names", NewValidator(60), req, ""},
+		{"wrapped validator", NewDelegationValidator(NewValidator(60, WithHeaderPrefix("x-acme-"))), req, nonce},
+		{"impersonation validator", NewImpersonationValidator(NewValidator(60, WithHeaderPrefix("x-acme-")), nil), req, nonce},
+		{"signed without nonce", NewValidator(60), plain, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := RequestNonce(tc.v, tc.r); got != tc.nonce {
+				t.Errorf("expected nonce %q, got %q", tc.nonce, got)
+			}
+		})
+	}
+
+	// the nonce parameter of the message signatures
+	got := RequestNonce(NewMessageSignatureValidator(60), msg)
+	if got == "" || !strings.Contains(msg.Header.Get(SignatureInputHeader), `;nonce="`+got+`"`) {
+		t.Errorf("expected the nonce of the message signature, got %q", got)
+	}
+	if other := RequestNonce(nil, msg); other != got {
+		t.Errorf("expected the nonce of the message signature without validator, got %q", other)
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build !race
+
+package hash
+
+// raceEnabled reports whether the tests are built with the race detector.
+const raceEnabled = false
@@ -0,0 +1,373 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+// HeaderNames holds the names of the authentication headers emitted by the
+// Generator and consumed by the Validator.
+type HeaderNames struct {
+	Signature string // signature header, default x-signature
+	Algorithm string // signature algorithm header, default x-signature-alg
+	Version   string // signature scheme version header, default x-signature-version
+	Timestamp string // timestamp header, default x-timestamp
+	KeyId     string // API key identifier header, default x-api-key-id
+
+	// streamed body signature trailer, default x-content-signature
+	ContentSignature string
+
+	// nonce header, signed along with the request, see WithNonce,
+	// default x-nonce
+	Nonce string
+
+	// session token header of temporary credentials, default
+	// x-session-token
+	SessionToken string
+
+	// headers of the impersonated identity, default x-impersonate-user
+	// and x-impersonate-tenant
+	ImpersonateUser   string
+	ImpersonateTenant string
+}
+
+// DefaultHeaderNames returns the header names used unless configured
+// otherwise.
+func DefaultHeaderNames() HeaderNames {
+	return HeaderNames{
+		Signature: apiKeySignatureHeader,
+		Algorithm: apiKeyAlgorithmHeader,
+		Version:   apiKeyVersionHeader,
+		Timestamp: apiKeyTimestampHeader,
+		KeyId:     apiKeyIdHeader,
+
+		ContentSignature: apiKeyContentSignatureHeader,
+		Nonce:            apiKeyNonceHeader,
+		SessionToken:     apiKeySessionTokenHeader,
+
+		ImpersonateUser:   apiKeyImpersonateUserHeader,
+		ImpersonateTenant: apiKeyImpersonateTenantHeader,
+	}
+}
+
+// options holds the optional configuration shared by the Generator and the
+// Validator, an option that is not relevant for one of them is simply
+// ignored by it, which allows passing the same set of options to both.
+type options struct {
+	// names of the authentication headers
+	headers HeaderNames
+
+	// algorithm used by the Generator for signing requests
+	algorithm Algorithm
+
+	// algorithms accepted by the Validator
+	allowedAlgorithms map[Algorithm]bool
+
+	// signature scheme version used by the Generator
+	version SignatureVersion
+
+	// signature scheme versions accepted by the Validator, nil accepts
+	// every registered version
+	allowedVersions map[SignatureVersion]bool
+
+	// emit unix epoch seconds instead of RFC3339 timestamps from the
+	// Generator
+	epochTimestamp bool
+
+	// largest request body read by the Validator to hash it, see
+	// WithMaxBodySize
+	maxBodySize int64
+
+	// tracer creating spans around validations, nil disables tracing
+	tracer telemetry.Tracer
+
+	// meter recording validation metrics, nil disables metrics
+	meter telemetry.Meter
+
+	// header carrying the original path of requests rewritten by the
+	// trusted proxies, honored by the Validator
+	originalPathHeader string
+	trustedProxies     []netip.Prefix
+
+	// sign and validate with the signing keys derived for the scope, see
+	// WithDerivedSigningKey
+	derived      bool
+	derivedScope string
+
+	// session token of the temporary credentials sent by the Generator
+	sessionToken string
+
+	// identity impersonated by the Generator, see WithImpersonation
+	impersonate *Impersonation
+
+	// sign every request with a random nonce from the Generator
+	randomNonce bool
+
+	// signing time and nonce injected by the Generator in deterministic
+	// mode, only settable in builds with the contracttest build tag
+	fixedTime time.Time
+	nonce     string
+}
+
+// Option configures a Generator or a Validator.
+type Option func(*options)
+
+// newOptions returns the default options updated with the provided ones.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		headers:   DefaultHeaderNames(),
+		algorithm: DefaultAlgorithm,
+		version:   DefaultSignatureVersion,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.allowedAlgorithms == nil {
+		o.allowedAlgorithms = map[Algorithm]bool{}
+		for _, alg := range SupportedAlgorithms() {
+			o.allowedAlgorithms[alg] = true
+		}
+	}
+	return o
+}
+
+// WithAlgorithm sets the algorithm used by the Generator to sign requests,
+// unsupported algorithms are ignored and the default algorithm is used.
+func WithAlgorithm(alg Algorithm) Option {
+	return func(o *options) {
+		if alg.IsSupported() {
+			o.algorithm = alg
+		}
+	}
+}
+
+// WithAllowedAlgorithms restricts the algorithms accepted by the Validator,
+// by default every supported algorithm is accepted. Requests without an
+// x-signature-alg header are treated as HMACSHA256, so HMACSHA256 needs to
+// be part of the list if such clients should continue to work.
+func WithAllowedAlgorithms(algs ...Algorithm) Option {
+	return func(o *options) {
+		o.allowedAlgorithms = map[Algorithm]bool{}
+		for _, alg := range algs {
+			if alg.IsSupported() {
+				o.allowedAlgorithms[alg] = true
+			}
+		}
+	}
+}
+
+// WithHeaderPrefix replaces the default "x-" prefix of all the
+// authentication header names, e.g. prefix "x-acme-" results in
+// x-acme-signature, x-acme-signature-alg, x-acme-timestamp and
+// x-acme-api-key-id. The same prefix needs to be configured on both the
+// Generator and the Validator.
+func WithHeaderPrefix(prefix string) Option {
+	return func(o *options) {
+		o.headers = HeaderNames{
+			Signature: prefix + signatureHeaderName,
+			Algorithm: prefix + algorithmHeaderName,
+			Version:   prefix + versionHeaderName,
+			Timestamp: prefix + timestampHeaderName,
+			KeyId:     prefix + keyIdHeaderName,
+
+			ContentSignature: prefix + contentSignatureHeaderName,
+			Nonce:            prefix + nonceHeaderName,
+			SessionToken:     prefix + sessionTokenHeaderName,
+
+			ImpersonateUser:   prefix + impersonateUserHeaderName,
+			ImpersonateTenant: prefix + impersonateTenantHeaderName,
+		}
+	}
+}
+
+// WithHeaderNames overrides the authentication header names, fields left
+// empty keep their current value, allowing interop with gateways mandating
+// their own header conventions.
+func WithHeaderNames(names HeaderNames) Option {
+	return func(o *options) {
+		if names.Signature != "" {
+			o.headers.Signature = names.Signature
+		}
+		if names.Algorithm != "" {
+			o.headers.Algorithm = names.Algorithm
+		}
+		if names.Version != "" {
+			o.headers.Version = names.Version
+		}
+		if names.Timestamp != "" {
+			o.headers.Timestamp = names.Timestamp
+		}
+		if names.KeyId != "" {
+			o.headers.KeyId = names.KeyId
+		}
+		if names.ContentSignature != "" {
+			o.headers.ContentSignature = names.ContentSignature
+		}
+		if names.Nonce != "" {
+			o.headers.Nonce = names.Nonce
+		}
+		if names.SessionToken != "" {
+			o.headers.SessionToken = names.SessionToken
+		}
+		if names.ImpersonateUser != "" {
+			o.headers.ImpersonateUser = names.ImpersonateUser
+		}
+		if names.ImpersonateTenant != "" {
+			o.headers.ImpersonateTenant = names.ImpersonateTenant
+		}
+	}
+}
+
+// ResolveHeaderNames returns the authentication header names in effect
+// with the options, e.g. to strip the authentication headers of a request
+// forwarded to a third party.
+func ResolveHeaderNames(opts ...Option) HeaderNames {
+	return newOptions(opts...).headers
+}
+
+// WithSignatureVersion sets the signature scheme version used by the
+// Generator, the version must be registered by the time requests are
+// signed.
+func WithSignatureVersion(version SignatureVersion) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithAllowedVersions restricts the signature scheme versions accepted by
+// the Validator, by default every registered version is accepted. Requests
+// without an x-signature-version header are treated as v1.
+func WithAllowedVersions(versions ...SignatureVersion) Option {
+	return func(o *options) {
+		o.allowedVersions = map[SignatureVersion]bool{}
+		for _, v := range versions {
+			o.allowedVersions[v] = true
+		}
+	}
+}
+
+// WithEpochTimestamp makes the Generator emit the timestamp header as unix
+// epoch seconds, e.g. "1748410688", instead of RFC3339, as used by clients
+// in other languages. The Validator accepts both formats regardless.
+func WithEpochTimestamp() Option {
+	return func(o *options) {
+		o.epochTimestamp = true
+	}
+}
+
+// WithMaxBodySize limits the request bodies the Validator reads to verify
+// their hash, as signed with v2 or covered by a Content-Digest, to n bytes,
+// DefaultMaxBodySize if not positive. Larger bodies fail the validation
+// with ReasonBodyTooLarge, without being read further. The bodies streamed
+// with v2-streaming are verified as the handler reads them and are not
+// limited.
+func WithMaxBodySize(n int64) Option {
+	return func(o *options) {
+		o.maxBodySize = n
+	}
+}
+
+// WithNonce makes the Generator sign every request with a random nonce
+// carried in the x-nonce header, so that identical requests signed within
+// the same second still have distinct signatures and the validators can
+// reject the replayed ones, see RequestNonce.
+func WithNonce() Option {
+	return func(o *options) {
+		o.randomNonce = true
+	}
+}
+
+// signingNonce returns the nonce signed by the Generator, the injected
+// nonce in deterministic mode, a random one with WithNonce and none
+// otherwise.
+func (o *options) signingNonce() string {
+	if o.nonce != "" {
+		return o.nonce
+	}
+	if o.randomNonce {
+		return rand.Text()
+	}
+	return ""
+}
+
+// timestamp formats the signing time as per the configured format.
+func (o *options) timestamp(now time.Time) string {
+	if o.epochTimestamp {
+		return strconv.FormatInt(now.Unix(), 10)
+	}
+	return now.Format(time.RFC3339)
+}
+
+// signingTime returns the time used by the Generator for signing, the
+// injected time in deterministic mode and the current time otherwise.
+func (o *options) signingTime() time.Time {
+	if !o.fixedTime.IsZero() {
+		return o.fixedTime
+	}
+	return time.Now()
+}
+
+// parseTimestamp parses the timestamp header value, either unix epoch
+// seconds or RFC3339.
+func parseTimestamp(s string) (time.Time, error) {
+	if s != "" && strings.Trim(s, "0123456789") == "" {
+		secs, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// isVersionAllowed reports whether the Validator accepts the version.
+func (o *options) isVersionAllowed(version SignatureVersion) bool {
+	if o.allowedVersions != nil && !o.allowedVersions[version] {
+		return false
+	}
+	_, ok := getSignatureScheme(version)
+	return ok
+}
+
+// requestVersion returns the signature scheme version carried by the
+// request, defaulting to v1 when the header is absent.
+func (o *options) requestVersion(r *http.Request) SignatureVersion {
+	version := SignatureVersion(r.Header.Get(o.headers.Version))
+	if version == "" {
+		version = DefaultSignatureVersion
+	}
+	return version
+}
+
+// WithTracer enables tracing of the validations performed by the
+// Validator, annotated with the API key ID, algorithm and outcome.
+func WithTracer(tracer telemetry.Tracer) Option {
+	return func(o *options) {
+		o.tracer = tracer
+	}
+}
+
+// WithMeter enables recording of the validation counters and latency
+// histograms by the Validator.
+func WithMeter(meter telemetry.Meter) Option {
+	return func(o *options) {
+		o.meter = meter
+	}
+}
+
+// WithMetrics enables recording of the validation metrics by the
+// Validator, e.g. with the Prometheus collectors of the
+// telemetry/prometheus package. Equivalent to WithMeter for the Validator,
+// use WithCacheMetrics for the CachingSecretResolver.
+func WithMetrics(metrics telemetry.Metrics) Option {
+	return WithMeter(metrics)
+}
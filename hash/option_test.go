@@ -0,0 +1,58 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderPrefix(t *testing.T) {
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed := NewGenerator("test-key", "supersecret", WithHeaderPrefix("x-acme-")).AddAuthHeaders(req)
+	for _, h := range []string{"x-acme-signature", "x-acme-signature-alg", "x-acme-timestamp", "x-acme-api-key-id"} {
+		if signed.Header.Get(h) == "" {
+			t.Errorf("expected header %s to be set", h)
+		}
+	}
+	if signed.Header.Get("x-signature") != "" {
+		t.Errorf("expected default signature header to be absent")
+	}
+
+	validator := NewValidator(60, WithHeaderPrefix("x-acme-"))
+	if id := validator.GetKeyId(signed); id != "test-key" {
+		t.Errorf("expected key id test-key, got %s", id)
+	}
+	if ok, err := validator.Validate(signed, "supersecret"); !ok {
+		t.Fatalf("validation failed: %v", err)
+	}
+	if ok, _ := NewValidator(60).Validate(signed, "supersecret"); ok {
+		t.Fatalf("expected default validator to reject prefixed headers")
+	}
+}
+
+func TestHeaderNames(t *testing.T) {
+	names := HeaderNames{Signature: "Authorization-Signature", KeyId: "X-Client-Id"}
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed := NewGenerator("test-key", "supersecret", WithHeaderNames(names)).AddAuthHeaders(req)
+	if signed.Header.Get("Authorization-Signature") == "" || signed.Header.Get("X-Client-Id") != "test-key" {
+		t.Fatalf("expected custom headers to be set, got %v", signed.Header)
+	}
+	if signed.Header.Get("x-timestamp") == "" {
+		t.Errorf("expected default timestamp header to be kept")
+	}
+	if ok, err := NewValidator(60, WithHeaderNames(names)).Validate(signed, "supersecret"); !ok {
+		t.Fatalf("validation failed: %v", err)
+	}
+}
+
+func TestResolveHeaderNames(t *testing.T) {
+	if ResolveHeaderNames() != DefaultHeaderNames() {
+		t.Errorf("expected the default header names without options")
+	}
+	names := ResolveHeaderNames(WithHeaderPrefix("x-acme-"), WithHeaderNames(HeaderNames{KeyId: "X-Client-Id"}))
+	if names.Signature != "x-acme-signature" || names.KeyId != "X-Client-Id" {
+		t.Errorf("unexpected header names %+v", names)
+	}
+}
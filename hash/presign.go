@@ -0,0 +1,170 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This file provides presigned URLs, embedding the API key id, expiry, and
+signature in query parameters so that browsers and webhooks can access
+protected resources without setting authentication headers.
+
+The signature is computed as HMAC-SHA256(secret, method + host + path +
+canonical query), where the canonical query is the sorted, encoded query
+string including the x-api-key-id and x-expires parameters but excluding
+x-signature. Covering the method and the host, as the header schemes do,
+a URL presigned for a GET can neither be replayed with another method nor
+against another host served with the same key. PresignURL presigns the
+URLs for a GET, PresignURLWithMethod for any other method.
+
+# Usage
+
+    u, _ := url.Parse("https://api.example.com/files/report.pdf")
+    presigned, err := hash.PresignURL("supersecret", "api-key-id", u, 15*time.Minute)
+    upload, err := hash.PresignURLWithMethod("supersecret", "api-key-id", http.MethodPut, u, 15*time.Minute)
+
+    // server side, validating the method and the host of the request
+    secret := lookup(hash.GetPresignedKeyId(r.URL))
+    if err := hash.ValidatePresignedRequest(secret, r); err != nil {
+        // reject
+    }
+
+    // or validating an absolute URL presigned for a GET
+    err = hash.ValidatePresignedURL(secret, presigned)
+*/
+
+// query parameters carried by a presigned URL
+const (
+	presignSignatureParam = "x-signature"
+	presignKeyIdParam     = "x-api-key-id"
+	presignExpiresParam   = "x-expires"
+)
+
+// presignCanonicalString returns the string signed for a presigned URL
+// requested with the method from the host.
+func presignCanonicalString(method, host string, u *url.URL, query url.Values) string {
+	q := url.Values{}
+	for k, v := range query {
+		if k != presignSignatureParam {
+			q[k] = v
+		}
+	}
+	return strings.ToUpper(method) + "\n" + strings.ToLower(host) + "\n" + u.EscapedPath() + "\n" + q.Encode()
+}
+
+// PresignURL returns a copy of the URL carrying the API key id, expiry (unix
+// epoch seconds), and signature as query parameters, valid for the given
+// expiry duration and only for GET requests, see PresignURLWithMethod.
+//
+// Parameters:
+//   - secret: Secret key for HMAC signing
+//   - keyId:  API key identifier
+//   - u:      absolute URL to presign, its host and existing query parameters
+//     are covered by the signature
+//   - expiry: Duration after which the URL is no longer valid
+func PresignURL(secret, keyId string, u *url.URL, expiry time.Duration) (*url.URL, error) {
+	return PresignURLWithMethod(secret, keyId, http.MethodGet, u, expiry)
+}
+
+// PresignURLWithMethod returns the URL presigned as PresignURL does, valid
+// only for requests with the given method.
+func PresignURLWithMethod(secret, keyId, method string, u *url.URL, expiry time.Duration) (*url.URL, error) {
+	if u == nil {
+		return nil, fmt.Errorf("missing url")
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing url host")
+	}
+	if method == "" {
+		return nil, fmt.Errorf("missing method")
+	}
+	if keyId == "" {
+		return nil, fmt.Errorf("missing api key id")
+	}
+	if expiry <= 0 {
+		return nil, fmt.Errorf("expiry must be positive")
+	}
+
+	presigned := *u
+	query := u.Query()
+	query.Set(presignKeyIdParam, keyId)
+	query.Set(presignExpiresParam, strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	query.Del(presignSignatureParam)
+
+	sig := GenerateSHA256HMAC(secret, presignCanonicalString(method, u.Host, &presigned, query))
+	query.Set(presignSignatureParam, sig)
+	presigned.RawQuery = query.Encode()
+	return &presigned, nil
+}
+
+// GetPresignedKeyId returns the API key id carried by a presigned URL.
+func GetPresignedKeyId(u *url.URL) string {
+	return u.Query().Get(presignKeyIdParam)
+}
+
+// ValidatePresignedURL checks the signature and expiry of an absolute URL
+// presigned for a GET, see PresignURL, returning nil if the URL is valid
+// for the given secret. Servers validate the requests received with
+// ValidatePresignedRequest, the URL of a server request lacking its host.
+func ValidatePresignedURL(secret string, u *url.URL) error {
+	if u == nil {
+		return fmt.Errorf("missing url")
+	}
+	return ValidatePresignedRequest(secret, &http.Request{Method: http.MethodGet, URL: u, Host: u.Host})
+}
+
+// ValidatePresignedRequest checks the signature and expiry of the
+// presigned URL of the request, along with the method and host it was
+// presigned for, returning nil if the request is valid for the given
+// secret.
+func ValidatePresignedRequest(secret string, r *http.Request) error {
+	if r == nil || r.URL == nil {
+		return fmt.Errorf("missing url")
+	}
+	u := r.URL
+	query := u.Query()
+
+	sigStr := query.Get(presignSignatureParam)
+	if sigStr == "" {
+		return fmt.Errorf("missing signature parameter")
+	}
+	sig, err := hex.DecodeString(sigStr)
+	if err != nil {
+		return fmt.Errorf("invalid signature format")
+	}
+
+	if query.Get(presignKeyIdParam) == "" {
+		return fmt.Errorf("missing api key id parameter")
+	}
+
+	expStr := query.Get(presignExpiresParam)
+	if expStr == "" {
+		return fmt.Errorf("missing expires parameter")
+	}
+	expires, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing expires: %s", err)
+	}
+	if time.Now().Unix() >= expires {
+		return fmt.Errorf("expired access")
+	}
+
+	host := r.Host
+	if host == "" {
+		host = u.Host
+	}
+	if !hmac.Equal(sig, generateSHA256HMAC(secret, presignCanonicalString(r.Method, host, u, query))) {
+		return fmt.Errorf("invalid hmac signature")
+	}
+	return nil
+}
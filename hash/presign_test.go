@@ -0,0 +1,117 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPresignURL(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/files/report.pdf?version=2")
+	presigned, err := PresignURL("supersecret", "test-key", u, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to presign url: %s", err)
+	}
+	if u.RawQuery != "version=2" {
+		t.Errorf("expected original url to be left untouched, got %s", u.RawQuery)
+	}
+	if id := GetPresignedKeyId(presigned); id != "test-key" {
+		t.Errorf("expected key id test-key, got %s", id)
+	}
+
+	// simulate the server receiving the url from a browser
+	received := httptest.NewRequest("GET", presigned.String(), nil)
+	if err := ValidatePresignedRequest("supersecret", received); err != nil {
+		t.Fatalf("validation failed: %s", err)
+	}
+
+	if err := ValidatePresignedRequest("othersecret", received); err == nil {
+		t.Errorf("expected validation to fail with a different secret")
+	}
+
+	// replaying the url with another method or against another host must
+	// fail validation
+	for _, method := range []string{"DELETE", "PUT"} {
+		if err := ValidatePresignedRequest("supersecret", httptest.NewRequest(method, presigned.String(), nil)); err == nil {
+			t.Errorf("expected validation to fail for a %s replay", method)
+		}
+	}
+	replay := httptest.NewRequest("GET", presigned.String(), nil)
+	replay.Host = "other.example.com"
+	if err := ValidatePresignedRequest("supersecret", replay); err == nil {
+		t.Errorf("expected validation to fail against another host")
+	}
+
+	// tampering with covered query parameters must fail validation
+	tampered := *presigned
+	q := tampered.Query()
+	q.Set("version", "3")
+	tampered.RawQuery = q.Encode()
+	if err := ValidatePresignedRequest("supersecret", httptest.NewRequest("GET", tampered.String(), nil)); err == nil {
+		t.Errorf("expected validation to fail for tampered query")
+	}
+
+	tampered = *presigned
+	q = tampered.Query()
+	q.Set("x-expires", "1")
+	tampered.RawQuery = q.Encode()
+	if err := ValidatePresignedRequest("supersecret", httptest.NewRequest("GET", tampered.String(), nil)); err == nil || err.Error() != "expired access" {
+		t.Errorf("expected expired access, got %v", err)
+	}
+
+	// the absolute url presigned for a GET validates as such
+	if err := ValidatePresignedURL("supersecret", presigned); err != nil {
+		t.Errorf("expected the presigned url to be valid: %s", err)
+	}
+	relative := *presigned
+	relative.Host = ""
+	if err := ValidatePresignedURL("supersecret", &relative); err == nil {
+		t.Errorf("expected validation to fail without the host presigned for")
+	}
+
+	if _, err := PresignURL("supersecret", "test-key", u, 0); err == nil {
+		t.Errorf("expected error for non positive expiry")
+	}
+	if _, err := PresignURLWithMethod("supersecret", "test-key", "", u, time.Minute); err == nil {
+		t.Errorf("expected error for a missing method")
+	}
+	if _, err := PresignURL("supersecret", "test-key", &url.URL{Path: "/files"}, time.Minute); err == nil {
+		t.Errorf("expected error for a relative url")
+	}
+}
+
+func TestPresignURLWithMethod(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/files/report.pdf")
+	presigned, err := PresignURLWithMethod("supersecret", "test-key", "PUT", u, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to presign url: %s", err)
+	}
+	tests := []struct {
+		name   string
+		method string
+		host   string
+		valid  bool
+	}{
+		{"presigned method", "PUT", "", true},
+		{"default method", "GET", "", false},
+		{"other host", "PUT", "other.example.com", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, presigned.String(), nil)
+			if tc.host != "" {
+				r.Host = tc.host
+			}
+			if err := ValidatePresignedRequest("supersecret", r); (err == nil) != tc.valid {
+				t.Errorf("expected valid %v, got %v", tc.valid, err)
+			}
+		})
+	}
+	if err := ValidatePresignedURL("supersecret", presigned); err == nil {
+		t.Errorf("expected the url presigned for a PUT to not validate as a GET")
+	}
+}
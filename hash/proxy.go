@@ -0,0 +1,92 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/go-core-stack/auth/ipaddr"
+)
+
+const (
+	// ForwardedPathHeader carries the original path of a request whose
+	// path is rewritten by a proxy
+	ForwardedPathHeader = "X-Forwarded-Path"
+
+	// OriginalURIHeader carries the original path and query of a request
+	// whose path is rewritten by a proxy
+	OriginalURIHeader = "X-Original-URI"
+)
+
+// WithTrustedProxyPath makes the Validator verify the signature against
+// the original path carried in the header, e.g. ForwardedPathHeader or
+// OriginalURIHeader, for requests whose path is rewritten by a gateway
+// before reaching the Validator. A header value including a query replaces
+// the query as well. The header is only honored for requests received
+// directly from one of the trusted proxies, identified by the remote
+// address of the request, and ignored otherwise, so that clients cannot
+// choose the path being verified. Without any trusted proxy the option has
+// no effect.
+func WithTrustedProxyPath(header string, proxies ...netip.Prefix) Option {
+	return func(o *options) {
+		o.originalPathHeader = http.CanonicalHeaderKey(header)
+		o.trustedProxies = proxies
+	}
+}
+
+// isTrustedProxy reports whether the request is received from a trusted
+// proxy.
+func (o *options) isTrustedProxy(r *http.Request) bool {
+	addr, ok := ipaddr.FromRequest(r)
+	if !ok {
+		return false
+	}
+	for _, p := range o.trustedProxies {
+		if ipaddr.NormalizePrefix(p).Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// originalURL returns the URL of the request before being rewritten by a
+// trusted proxy, or nil if the request should be verified as received.
+func (o *options) originalURL(r *http.Request) *url.URL {
+	if o.originalPathHeader == "" || len(o.trustedProxies) == 0 {
+		return nil
+	}
+	value := r.Header.Get(o.originalPathHeader)
+	if value == "" || !o.isTrustedProxy(r) {
+		return nil
+	}
+	orig, err := url.ParseRequestURI(value)
+	if err != nil {
+		return nil
+	}
+	u := *r.URL
+	u.Path = orig.Path
+	u.RawPath = orig.RawPath
+	if strings.Contains(value, "?") {
+		u.RawQuery = orig.RawQuery
+	}
+	return &u
+}
+
+// withOriginalURL invokes fn with the request as received by the trusted
+// proxy, carrying the original URL, retaining the body as restored by fn
+// on the request.
+func (o *options) withOriginalURL(r *http.Request, fn func(r *http.Request) (string, error)) (string, error) {
+	u := o.originalURL(r)
+	if u == nil {
+		return fn(r)
+	}
+	orig := *r
+	orig.URL = u
+	s, err := fn(&orig)
+	r.Body, r.GetBody = orig.Body, orig.GetBody
+	return s, err
+}
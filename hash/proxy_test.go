@@ -0,0 +1,58 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestTrustedProxyPath(t *testing.T) {
+	gen := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2))
+	validator := NewValidator(60, WithTrustedProxyPath(OriginalURIHeader, netip.MustParsePrefix("10.0.0.0/8")))
+
+	// signed by the client for the public path, rewritten by the gateway
+	newRequest := func(remoteAddr, original string) *http.Request {
+		req := httptest.NewRequest("POST", "https://api.example.com/api/v1/books?a=1", strings.NewReader("payload"))
+		gen.AddAuthHeaders(req)
+		req.URL.Path = "/books"
+		req.URL.RawQuery = ""
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(OriginalURIHeader, original)
+		return req
+	}
+
+	req := newRequest("10.1.2.3:4567", "/api/v1/books?a=1")
+	if ok, err := validator.Validate(req, "supersecret"); !ok {
+		t.Fatalf("expected request rewritten by trusted proxy to be valid: %s", err)
+	}
+	if b, _ := io.ReadAll(req.Body); string(b) != "payload" {
+		t.Errorf("expected body to be preserved, got %q", b)
+	}
+	if req.URL.Path != "/books" {
+		t.Errorf("expected request path to be left as rewritten, got %s", req.URL.Path)
+	}
+
+	// dual-stack listeners report IPv4 peers as mapped IPv6 addresses
+	req = newRequest("[::ffff:10.1.2.3]:4567", "/api/v1/books?a=1")
+	if ok, err := validator.Validate(req, "supersecret"); !ok {
+		t.Errorf("expected request from ipv4-mapped trusted proxy to be valid: %s", err)
+	}
+
+	// the header is ignored from untrusted peers
+	req = newRequest("192.168.1.1:4567", "/api/v1/books?a=1")
+	if ok, _ := validator.Validate(req, "supersecret"); ok {
+		t.Errorf("expected original path from untrusted peer to be ignored")
+	}
+
+	// without trusted proxies the option has no effect
+	req = newRequest("10.1.2.3:4567", "/api/v1/books?a=1")
+	if ok, _ := NewValidator(60, WithTrustedProxyPath(OriginalURIHeader)).Validate(req, "supersecret"); ok {
+		t.Errorf("expected original path to be ignored without trusted proxies")
+	}
+}
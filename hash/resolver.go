@@ -0,0 +1,307 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/internal/lru"
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+/*
+This file contains the SecretResolver abstraction used by servers to fetch
+the secret associated with the API key id carried by a request, along with
+a caching decorator that can be warmed up at startup.
+
+# Usage
+
+    resolver := hash.NewCachingSecretResolver(storeResolver, hash.WithSecretTTL(5*time.Minute))
+
+    // warm the cache for the known active keys before serving traffic
+    if err := resolver.Preload(ctx, activeKeyIds); err != nil {
+        log.Printf("failed to preload secrets: %s", err)
+    }
+
+    secret, err := resolver.GetSecret(ctx, validator.GetKeyId(req))
+    ok, err := validator.Validate(req, secret)
+*/
+
+// SecretResolver resolves the secret associated with an API key id.
+// Implementations are expected to return an error with code
+// errors.NotFound when the key id is unknown.
+type SecretResolver interface {
+	// GetSecret returns the secret for the given API key id.
+	GetSecret(ctx context.Context, keyId string) (string, error)
+}
+
+// BulkSecretResolver is optionally implemented by a SecretResolver that is
+// able to resolve multiple key ids in a single round trip to its store.
+type BulkSecretResolver interface {
+	SecretResolver
+
+	// GetSecrets returns the secrets for the given API key ids, unknown key
+	// ids are omitted from the returned map.
+	GetSecrets(ctx context.Context, keyIds []string) (map[string]string, error)
+}
+
+// SecretResolverFunc is an adapter allowing the use of an ordinary function
+// as a SecretResolver.
+type SecretResolverFunc func(ctx context.Context, keyId string) (string, error)
+
+// GetSecret calls f(ctx, keyId).
+func (f SecretResolverFunc) GetSecret(ctx context.Context, keyId string) (string, error) {
+	return f(ctx, keyId)
+}
+
+// Defaults applied by NewCachingSecretResolver unless configured otherwise.
+const (
+	// DefaultSecretTTL is the default duration for which a resolved secret
+	// is cached by the CachingSecretResolver, bounding how long a secret
+	// rotated or removed in the store without an explicit invalidation
+	// remains in use.
+	DefaultSecretTTL = 5 * time.Minute
+
+	// DefaultNegativeCacheTTL is the default duration for which an unknown
+	// key id is remembered by the CachingSecretResolver.
+	DefaultNegativeCacheTTL = 5 * time.Second
+
+	// DefaultMaxCachedSecrets is the default maximum number of secrets
+	// held by the CachingSecretResolver.
+	DefaultMaxCachedSecrets = 10000
+
+	// DefaultMaxNegativeEntries is the default maximum number of unknown
+	// key ids remembered by the CachingSecretResolver.
+	DefaultMaxNegativeEntries = 10000
+)
+
+// ResolverStats captures the cache counters of a CachingSecretResolver,
+// distinguishing requests served from the cache from the ones that resulted
+// in a lookup on the underlying resolver.
+type ResolverStats struct {
+	Hits              uint64 // secrets served from the cache
+	NegativeHits      uint64 // unknown key ids rejected from the negative cache
+	Lookups           uint64 // lookups performed on the underlying resolver
+	NotFound          uint64 // lookups reporting an unknown key id
+	Evictions         uint64 // secrets evicted due to the size bound
+	NegativeEvictions uint64 // unknown key ids evicted due to the size bound
+}
+
+// cachedSecret is a cached secret along with its expiry, zero if it
+// never expires.
+type cachedSecret struct {
+	secret string
+	expiry time.Time
+}
+
+// CachingSecretResolver is a SecretResolver decorator caching the secrets
+// resolved by the underlying resolver in memory. Cached secrets are kept
+// until invalidated, evicted or expired, after DefaultSecretTTL unless set
+// otherwise with WithSecretTTL. Consumers are expected to call Invalidate
+// when a key is rotated or removed, see rotation.InvalidateOnRotation, the
+// TTL bounding the use of a stale secret when the invalidation is missed.
+//
+// Unknown key ids are cached as well for a short duration, protecting the
+// store from floods of requests bearing invalid or garbage key ids. Both
+// caches are size-bounded with least recently used eviction.
+type CachingSecretResolver struct {
+	base        SecretResolver
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxSecrets  int
+	maxNegative int
+	now         func() time.Time
+
+	secrets  *lru.Cache[string, cachedSecret]
+	negative *lru.Cache[string, time.Time] // unknown key id to expiry of the entry
+
+	metrics telemetry.Metrics
+
+	hits         atomic.Uint64
+	negativeHits atomic.Uint64
+	lookups      atomic.Uint64
+	notFound     atomic.Uint64
+}
+
+// CachingResolverOption configures a CachingSecretResolver.
+type CachingResolverOption func(*CachingSecretResolver)
+
+// WithSecretTTL sets the duration for which the resolved secrets are
+// cached, bounding how long a secret changed in the store without an
+// explicit invalidation remains in use, DefaultSecretTTL by default. A zero
+// or negative duration keeps the secrets until invalidated or evicted.
+func WithSecretTTL(ttl time.Duration) CachingResolverOption {
+	return func(c *CachingSecretResolver) {
+		c.ttl = ttl
+	}
+}
+
+// WithCacheMetrics enables recording of the hits, misses and evictions of
+// the secrets cache, reported as telemetry.CacheSecrets.
+func WithCacheMetrics(metrics telemetry.Metrics) CachingResolverOption {
+	return func(c *CachingSecretResolver) {
+		c.metrics = metrics
+	}
+}
+
+// WithNegativeCacheTTL sets the duration for which unknown key ids are
+// cached, a zero or negative duration disables negative caching.
+func WithNegativeCacheTTL(ttl time.Duration) CachingResolverOption {
+	return func(c *CachingSecretResolver) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithMaxCachedSecrets sets the maximum number of cached secrets.
+func WithMaxCachedSecrets(n int) CachingResolverOption {
+	return func(c *CachingSecretResolver) {
+		c.maxSecrets = n
+	}
+}
+
+// WithMaxNegativeEntries sets the maximum number of unknown key ids
+// remembered by the negative cache.
+func WithMaxNegativeEntries(n int) CachingResolverOption {
+	return func(c *CachingSecretResolver) {
+		c.maxNegative = n
+	}
+}
+
+// NewCachingSecretResolver creates a caching decorator over the given
+// SecretResolver.
+func NewCachingSecretResolver(base SecretResolver, opts ...CachingResolverOption) *CachingSecretResolver {
+	c := &CachingSecretResolver{
+		base:        base,
+		ttl:         DefaultSecretTTL,
+		negativeTTL: DefaultNegativeCacheTTL,
+		maxSecrets:  DefaultMaxCachedSecrets,
+		maxNegative: DefaultMaxNegativeEntries,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.secrets = lru.New[string, cachedSecret](c.maxSecrets)
+	c.negative = lru.New[string, time.Time](c.maxNegative)
+	return c
+}
+
+// GetSecret returns the cached secret for the key id, resolving and caching
+// it using the underlying resolver on a cache miss or once expired. Unknown
+// key ids are rejected from the negative cache until its entry expires.
+func (c *CachingSecretResolver) GetSecret(ctx context.Context, keyId string) (string, error) {
+	if e, ok := c.secrets.Get(keyId); ok {
+		if e.expiry.IsZero() || c.now().Before(e.expiry) {
+			c.hits.Add(1)
+			c.recordLookup(true)
+			return e.secret, nil
+		}
+		c.secrets.Remove(keyId)
+	}
+	if expiry, ok := c.negative.Get(keyId); ok {
+		if c.now().Before(expiry) {
+			c.negativeHits.Add(1)
+			c.recordLookup(true)
+			return "", errors.Wrapf(errors.NotFound, "api key %s not found", keyId)
+		}
+		c.negative.Remove(keyId)
+	}
+
+	c.recordLookup(false)
+	c.lookups.Add(1)
+	secret, err := c.base.GetSecret(ctx, keyId)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			c.notFound.Add(1)
+			if c.negativeTTL > 0 {
+				c.negative.Add(keyId, c.now().Add(c.negativeTTL))
+			}
+		}
+		return "", err
+	}
+	c.Prime(keyId, secret)
+	return secret, nil
+}
+
+// recordLookup records the lookup of the secrets cache, if enabled.
+func (c *CachingSecretResolver) recordLookup(hit bool) {
+	if c.metrics != nil {
+		c.metrics.RecordCacheLookup(telemetry.CacheSecrets, hit)
+	}
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *CachingSecretResolver) Stats() ResolverStats {
+	return ResolverStats{
+		Hits:              c.hits.Load(),
+		NegativeHits:      c.negativeHits.Load(),
+		Lookups:           c.lookups.Load(),
+		NotFound:          c.notFound.Load(),
+		Evictions:         c.secrets.Evictions(),
+		NegativeEvictions: c.negative.Evictions(),
+	}
+}
+
+// Preload warms the cache for the given key ids, typically the known active
+// keys at startup, to avoid a thundering herd of store lookups when traffic
+// arrives. The underlying resolver is used in bulk if it implements
+// BulkSecretResolver. Unknown key ids are skipped, while any other failure
+// is reported after attempting the remaining key ids.
+func (c *CachingSecretResolver) Preload(ctx context.Context, keyIds []string) error {
+	if bulk, ok := c.base.(BulkSecretResolver); ok {
+		secrets, err := bulk.GetSecrets(ctx, keyIds)
+		if err != nil {
+			return errors.Wrapf(errors.GetErrCode(err), "failed to preload secrets: %s", err)
+		}
+		for keyId, secret := range secrets {
+			c.Prime(keyId, secret)
+		}
+		return nil
+	}
+
+	var failure error
+	for _, keyId := range keyIds {
+		secret, err := c.base.GetSecret(ctx, keyId)
+		if err != nil {
+			if !errors.IsNotFound(err) && failure == nil {
+				failure = errors.Wrapf(errors.GetErrCode(err), "failed to preload secret for key %s: %s", keyId, err)
+			}
+			continue
+		}
+		c.Prime(keyId, secret)
+	}
+	return failure
+}
+
+// Prime inserts the secret for the key id directly into the cache, allowing
+// consumers to feed the cache from their own sources, e.g. key creation or
+// rotation events.
+func (c *CachingSecretResolver) Prime(keyId, secret string) {
+	e := cachedSecret{secret: secret}
+	if c.ttl > 0 {
+		e.expiry = c.now().Add(c.ttl)
+	}
+	if c.secrets.Add(keyId, e) && c.metrics != nil {
+		c.metrics.RecordCacheEviction(telemetry.CacheSecrets)
+	}
+	c.negative.Remove(keyId)
+}
+
+// Invalidate removes the cached secret or negative entry for the key id,
+// if any.
+func (c *CachingSecretResolver) Invalidate(keyId string) {
+	c.secrets.Remove(keyId)
+	c.negative.Remove(keyId)
+}
+
+// InvalidateAll removes all the cached secrets and negative entries, e.g.
+// after the encryption key of the store is rotated.
+func (c *CachingSecretResolver) InvalidateAll() {
+	c.secrets.Purge()
+	c.negative.Purge()
+}
@@ -0,0 +1,212 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// countingResolver resolves secrets from a map and counts lookups.
+type countingResolver struct {
+	secrets map[string]string
+	lookups int
+}
+
+func (r *countingResolver) GetSecret(ctx context.Context, keyId string) (string, error) {
+	r.lookups++
+	secret, ok := r.secrets[keyId]
+	if !ok {
+		return "", errors.Wrapf(errors.NotFound, "key %s not found", keyId)
+	}
+	return secret, nil
+}
+
+// bulkResolver additionally supports bulk lookups.
+type bulkResolver struct {
+	countingResolver
+	bulkLookups int
+}
+
+func (r *bulkResolver) GetSecrets(ctx context.Context, keyIds []string) (map[string]string, error) {
+	r.bulkLookups++
+	found := map[string]string{}
+	for _, id := range keyIds {
+		if secret, ok := r.secrets[id]; ok {
+			found[id] = secret
+		}
+	}
+	return found, nil
+}
+
+func TestCachingSecretResolver(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{"key1": "secret1"}}
+	resolver := NewCachingSecretResolver(base)
+
+	for range 3 {
+		secret, err := resolver.GetSecret(context.Background(), "key1")
+		if err != nil || secret != "secret1" {
+			t.Fatalf("unexpected result %q, %v", secret, err)
+		}
+	}
+	if base.lookups != 1 {
+		t.Errorf("expected a single lookup, got %d", base.lookups)
+	}
+
+	if _, err := resolver.GetSecret(context.Background(), "unknown"); !errors.IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+
+	resolver.Invalidate("key1")
+	base.secrets["key1"] = "rotated"
+	if secret, _ := resolver.GetSecret(context.Background(), "key1"); secret != "rotated" {
+		t.Errorf("expected rotated secret after invalidation, got %q", secret)
+	}
+}
+
+func TestCachingSecretResolverPreload(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{"key1": "secret1", "key2": "secret2"}}
+	resolver := NewCachingSecretResolver(base)
+	if err := resolver.Preload(context.Background(), []string{"key1", "key2", "unknown"}); err != nil {
+		t.Fatalf("unexpected preload error: %s", err)
+	}
+	lookups := base.lookups
+	if _, err := resolver.GetSecret(context.Background(), "key2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if base.lookups != lookups {
+		t.Errorf("expected preloaded secret to be served from cache")
+	}
+
+	bulk := &bulkResolver{countingResolver: countingResolver{secrets: map[string]string{"key1": "secret1", "key2": "secret2"}}}
+	resolver = NewCachingSecretResolver(bulk)
+	if err := resolver.Preload(context.Background(), []string{"key1", "key2"}); err != nil {
+		t.Fatalf("unexpected preload error: %s", err)
+	}
+	if bulk.bulkLookups != 1 || bulk.lookups != 0 {
+		t.Errorf("expected a single bulk lookup, got %d bulk and %d single", bulk.bulkLookups, bulk.lookups)
+	}
+
+	resolver.Prime("key3", "secret3")
+	if secret, _ := resolver.GetSecret(context.Background(), "key3"); secret != "secret3" {
+		t.Errorf("expected primed secret, got %q", secret)
+	}
+}
+
+func TestCachingSecretResolverNegativeCache(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{}}
+	resolver := NewCachingSecretResolver(base, WithNegativeCacheTTL(time.Minute))
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	for range 5 {
+		if _, err := resolver.GetSecret(context.Background(), "garbage"); !errors.IsNotFound(err) {
+			t.Fatalf("expected not found error, got %v", err)
+		}
+	}
+	stats := resolver.Stats()
+	if base.lookups != 1 || stats.Lookups != 1 || stats.NotFound != 1 || stats.NegativeHits != 4 {
+		t.Errorf("unexpected stats %+v with %d lookups", stats, base.lookups)
+	}
+
+	// negative entries expire after the ttl
+	now = now.Add(2 * time.Minute)
+	base.secrets["garbage"] = "now-valid"
+	if secret, err := resolver.GetSecret(context.Background(), "garbage"); err != nil || secret != "now-valid" {
+		t.Fatalf("expected lookup after negative entry expiry, got %q, %v", secret, err)
+	}
+	if stats = resolver.Stats(); stats.Hits != 0 || stats.Lookups != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// priming clears a negative entry right away
+	resolver = NewCachingSecretResolver(base)
+	_, _ = resolver.GetSecret(context.Background(), "new-key")
+	resolver.Prime("new-key", "secret")
+	if secret, err := resolver.GetSecret(context.Background(), "new-key"); err != nil || secret != "secret" {
+		t.Fatalf("expected primed secret, got %q, %v", secret, err)
+	}
+}
+
+func TestCachingSecretResolverBounds(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{"key1": "secret1", "key2": "secret2", "key3": "secret3"}}
+	resolver := NewCachingSecretResolver(base, WithMaxCachedSecrets(2), WithMaxNegativeEntries(2))
+
+	for _, id := range []string{"key1", "key2", "key3", "bad1", "bad2", "bad3"} {
+		_, _ = resolver.GetSecret(context.Background(), id)
+	}
+	stats := resolver.Stats()
+	if stats.Evictions != 1 || stats.NegativeEvictions != 1 {
+		t.Errorf("expected one eviction from each cache, got %+v", stats)
+	}
+
+	// key1 was evicted and needs another lookup
+	lookups := base.lookups
+	_, _ = resolver.GetSecret(context.Background(), "key1")
+	if base.lookups != lookups+1 {
+		t.Errorf("expected evicted secret to be resolved again")
+	}
+}
+
+func TestCachingSecretResolverTTL(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{"key": "secret"}}
+	resolver := NewCachingSecretResolver(base, WithSecretTTL(time.Minute))
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	_, _ = resolver.GetSecret(context.Background(), "key")
+	_, _ = resolver.GetSecret(context.Background(), "key")
+	if base.lookups != 1 {
+		t.Fatalf("expected a single lookup within the ttl, got %d", base.lookups)
+	}
+
+	// secrets changed in the store are picked up once expired
+	base.secrets["key"] = "rotated"
+	now = now.Add(2 * time.Minute)
+	if secret, _ := resolver.GetSecret(context.Background(), "key"); secret != "rotated" || base.lookups != 2 {
+		t.Errorf("expected the secret to be resolved again, got %q with %d lookups", secret, base.lookups)
+	}
+
+	_, _ = resolver.GetSecret(context.Background(), "unknown")
+	resolver.InvalidateAll()
+	_, _ = resolver.GetSecret(context.Background(), "key")
+	_, _ = resolver.GetSecret(context.Background(), "unknown")
+	if base.lookups != 5 {
+		t.Errorf("expected lookups after invalidating all, got %d", base.lookups)
+	}
+}
+
+func TestCachingSecretResolverDefaultTTL(t *testing.T) {
+	base := &countingResolver{secrets: map[string]string{"key": "secret"}}
+	resolver := NewCachingSecretResolver(base)
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	_, _ = resolver.GetSecret(context.Background(), "key")
+
+	// a rotation missing the invalidation is picked up once the default
+	// ttl expires
+	base.secrets["key"] = "rotated"
+	now = now.Add(DefaultSecretTTL - time.Second)
+	if secret, _ := resolver.GetSecret(context.Background(), "key"); secret != "secret" {
+		t.Errorf("expected the cached secret within the ttl, got %q", secret)
+	}
+	now = now.Add(2 * time.Second)
+	if secret, _ := resolver.GetSecret(context.Background(), "key"); secret != "rotated" || base.lookups != 2 {
+		t.Errorf("expected the rotated secret after the ttl, got %q with %d lookups", secret, base.lookups)
+	}
+
+	// a zero ttl keeps the secrets until invalidated
+	resolver = NewCachingSecretResolver(base, WithSecretTTL(0))
+	resolver.now = func() time.Time { return now }
+	_, _ = resolver.GetSecret(context.Background(), "key")
+	base.secrets["key"] = "rotated-again"
+	now = now.Add(24 * time.Hour)
+	if secret, _ := resolver.GetSecret(context.Background(), "key"); secret != "rotated" {
+		t.Errorf("expected the secret to be kept without a ttl, got %q", secret)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+/*
+This file provides the structured result of a validation, carrying the key
+id, timestamp, algorithm and signature version of the request along with
+the reason code of a failure and the latency of the validation, so that
+the middleware, audit logging and metrics consume a single result instead
+of parsing the headers again or matching on the error messages.
+
+# Usage
+
+    res := hash.ValidateWithResult(validator, req, secret)
+    if !res.Valid {
+        log.Printf("key %s rejected: %s (%s)", res.KeyId, res.Reason, res.Err)
+    }
+*/
+
+// ReasonCode identifies the reason of a validation failure, stable across
+// releases unlike the error messages.
+type ReasonCode string
+
+const (
+	// ReasonNone is the reason code of the successful validations.
+	ReasonNone ReasonCode = ""
+
+	// ReasonMissingHeader is reported when a required header is absent.
+	ReasonMissingHeader ReasonCode = "missing_header"
+
+	// ReasonMalformedHeader is reported when a header can not be parsed,
+	// e.g. a signature not hex encoded or an invalid timestamp.
+	ReasonMalformedHeader ReasonCode = "malformed_header"
+
+	// ReasonExpired is reported for requests outside the validity window.
+	ReasonExpired ReasonCode = "expired"
+
+	// ReasonAlgorithmNotAllowed is reported for requests signed with an
+	// algorithm not allowed by the validator.
+	ReasonAlgorithmNotAllowed ReasonCode = "algorithm_not_allowed"
+
+	// ReasonVersionNotAllowed is reported for requests signed with a
+	// signature scheme version not allowed by the validator.
+	ReasonVersionNotAllowed ReasonCode = "version_not_allowed"
+
+	// ReasonSignatureMismatch is reported when the signature does not
+	// match the request.
+	ReasonSignatureMismatch ReasonCode = "signature_mismatch"
+
+	// ReasonBodyTooLarge is reported when the body to be hashed exceeds
+	// the limit of the validator, see WithMaxBodySize.
+	ReasonBodyTooLarge ReasonCode = "body_too_large"
+
+	// ReasonInvalid is reported for the remaining failures.
+	ReasonInvalid ReasonCode = "invalid"
+)
+
+// reasonError is a validation error classified with its reason code.
+type reasonError struct {
+	reason ReasonCode
+	msg    string
+}
+
+// Error returns the message of the error.
+func (e *reasonError) Error() string {
+	return e.msg
+}
+
+// failure returns the validation error with the reason code.
+func failure(reason ReasonCode, format string, args ...any) error {
+	return &reasonError{reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+// Reason returns the reason code of the error returned by a Validator,
+// ReasonNone for nil.
+func Reason(err error) ReasonCode {
+	var re *reasonError
+	switch {
+	case err == nil:
+		return ReasonNone
+	case errors.As(err, &re):
+		return re.reason
+	case errors.Is(err, errExpired):
+		return ReasonExpired
+	case errors.Is(err, errSignatureMismatch), errors.Is(err, errEd25519Mismatch):
+		return ReasonSignatureMismatch
+	}
+	return ReasonInvalid
+}
+
+// ValidationResult is the outcome of the validation of a request.
+type ValidationResult struct {
+	// Valid is set if the request is authenticated
+	Valid bool
+
+	// KeyId is the API key id carried by the request
+	KeyId string
+
+	// Timestamp is the signing time of the request, zero if absent or
+	// malformed
+	Timestamp time.Time
+
+	// Algorithm and Version are the signature algorithm and scheme
+	// version of the request, defaults applied
+	Algorithm Algorithm
+	Version   SignatureVersion
+
+	// Reason classifies the failure, ReasonNone for valid requests
+	Reason ReasonCode
+
+	// Err is the error returned by the validator
+	Err error
+
+	// Latency is the time taken by the validation
+	Latency time.Duration
+}
+
+// resultDescriber is implemented by the validators of this package to
+// fill in the signing parameters of the request in the result.
+type resultDescriber interface {
+	describe(r *http.Request, res *ValidationResult)
+}
+
+// describe fills in the timestamp, algorithm and version of the request.
+func (v *validator) describe(r *http.Request, res *ValidationResult) {
+	if ts, err := parseTimestamp(r.Header.Get(v.opts.headers.Timestamp)); err == nil {
+		res.Timestamp = ts
+	}
+	res.Algorithm = Algorithm(r.Header.Get(v.opts.headers.Algorithm))
+	if res.Algorithm == "" {
+		res.Algorithm = DefaultAlgorithm
+	}
+	res.Version = v.opts.requestVersion(r)
+}
+
+// describe fills in the creation time and algorithm of the message
+// signature, which carries no signature scheme version.
+func (v *messageSignatureValidator) describe(r *http.Request, res *ValidationResult) {
+	sig, err := parseMessageSignature(r)
+	if err != nil {
+		return
+	}
+	if sig.created != 0 {
+		res.Timestamp = time.Unix(sig.created, 0)
+	}
+	res.Algorithm = Algorithm(sig.alg)
+	if res.Algorithm == "" {
+		res.Algorithm = HMACSHA256
+	}
+}
+
+// ValidateWithResult validates the request with the validator, returning
+// the structured result of the validation. It works with any Validator,
+// the timestamp, algorithm and version being filled in for the validators
+// of this package only.
+func ValidateWithResult(v Validator, r *http.Request, secret string) *ValidationResult {
+	start := time.Now()
+	ok, err := v.Validate(r, secret)
+	res := &ValidationResult{
+		Valid:   ok && err == nil,
+		KeyId:   v.GetKeyId(r),
+		Reason:  Reason(err),
+		Err:     err,
+		Latency: time.Since(start),
+	}
+	if !res.Valid && res.Reason == ReasonNone {
+		res.Reason = ReasonInvalid
+	}
+	if d, ok := v.(resultDescriber); ok {
+		d.describe(r, res)
+	}
+	return res
+}
@@ -0,0 +1,487 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This file provides the compatibility mode with RFC 9421 HTTP Message
+Signatures, for partners implementing the IETF standard instead of the
+x-signature headers. Both schemes may be accepted side by side, e.g.
+selecting the Validator by the presence of the Signature-Input header with
+IsMessageSignature.
+
+The Generator covers the method, path and query of the request, and the
+Content-Digest (RFC 9530) of requests with a body, signed with
+hmac-sha256 along with the creation and optional expiry time and the key
+id:
+
+    Content-Digest: sha-256=:X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=:
+    Signature-Input: sig1=("@method" "@path" "@query" "content-digest");created=1748410688;expires=1748410988;keyid="partner-key-id";alg="hmac-sha256"
+    Signature: sig1=:<base64>:
+
+The Validator accepts signatures covering at least the method, path and
+query, and the Content-Digest for requests with a body, along with other
+header fields and @authority. The creation time must be within the
+validity window, and the expiry, if any, not passed.
+
+# Usage
+
+    // client side
+    gen := hash.NewMessageSignatureGenerator("partner-key-id", secret, 5*time.Minute)
+    signedReq := gen.AddAuthHeaders(req)
+
+    // server side
+    validator := hash.NewMessageSignatureValidator(60)
+    secret, err := resolver.GetSecret(ctx, validator.GetKeyId(req))
+    ok, err := validator.Validate(req, secret)
+*/
+
+// Header names of RFC 9421 HTTP Message Signatures and RFC 9530 Digest
+// Fields.
+const (
+	SignatureHeader      = "Signature"
+	SignatureInputHeader = "Signature-Input"
+	ContentDigestHeader  = "Content-Digest"
+)
+
+// MessageSignatureLabel is the label of the signatures emitted by the
+// Generator, the Validator uses it or the only signature of the request.
+const MessageSignatureLabel = "sig1"
+
+// derived components of the messages
+const (
+	componentMethod    = "@method"
+	componentPath      = "@path"
+	componentQuery     = "@query"
+	componentAuthority = "@authority"
+	componentParams    = "@signature-params"
+	componentDigest    = "content-digest"
+)
+
+// components the signatures must cover
+var requiredComponents = []string{componentMethod, componentPath, componentQuery}
+
+// errMessageSignatureMismatch is returned when the message signature does
+// not match the request
+var errMessageSignatureMismatch = fmt.Errorf("%w: message signature", errSignatureMismatch)
+
+// IsMessageSignature reports whether the request carries an RFC 9421
+// message signature.
+func IsMessageSignature(r *http.Request) bool {
+	return r.Header.Get(SignatureInputHeader) != ""
+}
+
+// hasBody reports whether the request has a body to be covered by the
+// Content-Digest.
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
+
+// contentDigest returns the sha-256 Content-Digest of the request body.
+func contentDigest(r *http.Request) (string, error) {
+	sum, err := bodySum(r)
+	if err != nil {
+		return "", err
+	}
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum) + ":", nil
+}
+
+// componentValue returns the value of the covered component of the
+// request.
+func componentValue(r *http.Request, name string) (string, error) {
+	switch name {
+	case componentMethod:
+		return r.Method, nil
+	case componentPath:
+		if r.URL.EscapedPath() == "" {
+			return "/", nil
+		}
+		return r.URL.EscapedPath(), nil
+	case componentQuery:
+		return "?" + r.URL.RawQuery, nil
+	case componentAuthority:
+		host := r.Host
+		if host == "" {
+			host = r.URL.Host
+		}
+		return strings.ToLower(host), nil
+	}
+	if strings.HasPrefix(name, "@") {
+		return "", fmt.Errorf("unsupported derived component %s", name)
+	}
+	values := r.Header.Values(name)
+	if len(values) == 0 {
+		return "", fmt.Errorf("covered header %s missing", name)
+	}
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+	return strings.Join(values, ", "), nil
+}
+
+// signatureBase returns the signature base of the covered components
+// along with the serialized signature parameters.
+func signatureBase(r *http.Request, covered []string, params string) (string, error) {
+	var b strings.Builder
+	for _, name := range covered {
+		v, err := componentValue(r, name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(strconv.Quote(name))
+		b.WriteString(": ")
+		b.WriteString(v)
+		b.WriteString("\n")
+	}
+	b.WriteString(strconv.Quote(componentParams))
+	b.WriteString(": ")
+	b.WriteString(params)
+	return b.String(), nil
+}
+
+// messageSignatureGenerator signs requests with RFC 9421 message
+// signatures.
+type messageSignatureGenerator struct {
+	creds   CredentialsProvider
+	expires time.Duration
+	opts    *options
+}
+
+// AddAuthHeaders attaches the Signature-Input, Signature and, for requests
+// with a body, the Content-Digest headers to the request. If the
+// components can't be computed or the credentials are not available, the
+// request is returned without authentication headers.
+func (g *messageSignatureGenerator) AddAuthHeaders(r *http.Request) *http.Request {
+	ctx := r.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id, secret, err := g.creds.Current(ctx)
+	if err != nil {
+		return r
+	}
+	covered := slices.Clone(requiredComponents)
+	if hasBody(r) {
+		digest, err := contentDigest(r)
+		if err != nil {
+			return r
+		}
+		r.Header.Set(ContentDigestHeader, digest)
+		covered = append(covered, componentDigest)
+	}
+	g.opts.setImpersonationHeaders(r)
+	if g.opts.impersonate != nil {
+		covered = append(covered, strings.ToLower(g.opts.headers.ImpersonateUser),
+			strings.ToLower(g.opts.headers.ImpersonateTenant))
+	}
+
+	quoted := make([]string, 0, len(covered))
+	for _, name := range covered {
+		quoted = append(quoted, strconv.Quote(name))
+	}
+	now := g.opts.signingTime()
+	params := fmt.Sprintf("(%s);created=%d", strings.Join(quoted, " "), now.Unix())
+	if g.expires > 0 {
+		params += fmt.Sprintf(";expires=%d", now.Add(g.expires).Unix())
+	}
+	if nonce := g.opts.signingNonce(); nonce != "" {
+		params += fmt.Sprintf(";nonce=%s", strconv.Quote(nonce))
+	}
+	params += fmt.Sprintf(";keyid=%s;alg=%s", strconv.Quote(id), strconv.Quote(HMACSHA256.String()))
+
+	base, err := signatureBase(r, covered, params)
+	if err != nil {
+		return r
+	}
+	sig := base64.StdEncoding.EncodeToString(generateHMAC(sha256.New, secret, base))
+	r.Header.Set(SignatureInputHeader, MessageSignatureLabel+"="+params)
+	r.Header.Set(SignatureHeader, MessageSignatureLabel+"=:"+sig+":")
+	return r
+}
+
+// NewMessageSignatureGenerator creates a Generator signing requests with
+// RFC 9421 message signatures using hmac-sha256, expiring after expires,
+// or without expiry if zero.
+func NewMessageSignatureGenerator(id, secret string, expires time.Duration, opts ...Option) Generator {
+	return &messageSignatureGenerator{
+		creds:   StaticCredentials(id, secret),
+		expires: expires,
+		opts:    newOptions(opts...),
+	}
+}
+
+// messageSignature is a signature of the request parsed from the
+// Signature-Input and Signature headers.
+type messageSignature struct {
+	// covered components and the serialized signature parameters
+	covered []string
+	params  string
+
+	created int64
+	expires int64
+	nonce   string
+	keyId   string
+	alg     string
+
+	signature []byte
+}
+
+// splitMembers splits a structured field dictionary into its members,
+// ignoring the commas of quoted strings.
+func splitMembers(s string) []string {
+	var members []string
+	quoted, escaped, start := false, false, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == ',':
+			members = append(members, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(members, strings.TrimSpace(s[start:]))
+}
+
+// dictionaryMember returns the value of the member of the dictionary with
+// the label, or of its only member if label is empty.
+func dictionaryMember(field, label string) (string, string, bool) {
+	members := splitMembers(field)
+	for _, m := range members {
+		name, value, ok := strings.Cut(m, "=")
+		if !ok {
+			continue
+		}
+		if name == label || (label == "" && len(members) == 1) {
+			return name, value, true
+		}
+	}
+	return "", "", false
+}
+
+// parseInnerList parses the inner list of covered components along with
+// its parameters.
+func parseInnerList(s string, sig *messageSignature) error {
+	if !strings.HasPrefix(s, "(") {
+		return fmt.Errorf("invalid signature input")
+	}
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return fmt.Errorf("invalid signature input")
+	}
+	for _, item := range strings.Fields(s[1:end]) {
+		name, err := strconv.Unquote(item)
+		if err != nil || name == "" || name != strings.ToLower(name) {
+			return fmt.Errorf("invalid covered component %s", item)
+		}
+		if slices.Contains(sig.covered, name) {
+			return fmt.Errorf("duplicate covered component %s", name)
+		}
+		sig.covered = append(sig.covered, name)
+	}
+	for _, p := range splitParams(s[end+1:]) {
+		key, value, _ := strings.Cut(p, "=")
+		var err error
+		switch key {
+		case "created":
+			sig.created, err = strconv.ParseInt(value, 10, 64)
+		case "expires":
+			sig.expires, err = strconv.ParseInt(value, 10, 64)
+		case "nonce":
+			sig.nonce, err = strconv.Unquote(value)
+		case "keyid":
+			sig.keyId, err = strconv.Unquote(value)
+		case "alg":
+			sig.alg, err = strconv.Unquote(value)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid signature parameter %s", key)
+		}
+	}
+	sig.params = s
+	return nil
+}
+
+// splitParams splits the parameters following an inner list, ignoring
+// the semicolons of quoted strings.
+func splitParams(s string) []string {
+	var params []string
+	quoted, escaped, start := false, false, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == ';':
+			if p := strings.TrimSpace(s[start:i]); p != "" {
+				params = append(params, p)
+			}
+			start = i + 1
+		}
+	}
+	if p := strings.TrimSpace(s[start:]); p != "" {
+		params = append(params, p)
+	}
+	return params
+}
+
+// parseMessageSignature parses the message signature of the request,
+// labelled MessageSignatureLabel or the only one carried by the request.
+func parseMessageSignature(r *http.Request) (*messageSignature, error) {
+	input := strings.Join(r.Header.Values(SignatureInputHeader), ", ")
+	if input == "" {
+		return nil, failure(ReasonMissingHeader, "missing signature input header")
+	}
+	label, params, ok := dictionaryMember(input, MessageSignatureLabel)
+	if !ok {
+		if label, params, ok = dictionaryMember(input, ""); !ok {
+			return nil, failure(ReasonMissingHeader, "signature %s not found", MessageSignatureLabel)
+		}
+	}
+	sig := &messageSignature{}
+	if err := parseInnerList(params, sig); err != nil {
+		return nil, err
+	}
+	_, value, ok := dictionaryMember(strings.Join(r.Header.Values(SignatureHeader), ", "), label)
+	if !ok {
+		return nil, failure(ReasonMissingHeader, "missing signature header")
+	}
+	if !strings.HasPrefix(value, ":") || !strings.HasSuffix(value, ":") || len(value) < 2 {
+		return nil, failure(ReasonMalformedHeader, "invalid signature format")
+	}
+	b, err := base64.StdEncoding.DecodeString(value[1 : len(value)-1])
+	if err != nil {
+		return nil, failure(ReasonMalformedHeader, "invalid signature format")
+	}
+	sig.signature = b
+	return sig, nil
+}
+
+// messageSignatureNonce returns the nonce parameter of the message
+// signature of the request, empty if none.
+func messageSignatureNonce(r *http.Request) string {
+	sig, err := parseMessageSignature(r)
+	if err != nil {
+		return ""
+	}
+	return sig.nonce
+}
+
+// messageSignatureValidator validates RFC 9421 message signatures, it
+// shares the options and telemetry with the HMAC validator.
+type messageSignatureValidator struct {
+	validator
+}
+
+// Validate checks the message signature of the request, its covered
+// components, creation and expiry times, and the Content-Digest of the
+// body, where secret is the secret of the key id of the signature.
+func (v *messageSignatureValidator) Validate(r *http.Request, secret string) (bool, error) {
+	return v.observe(r, HMACSHA256, func() (bool, error) {
+		return v.validateMessageSignature(r, secret)
+	})
+}
+
+// validateMessageSignature performs the validation of the message
+// signature.
+func (v *messageSignatureValidator) validateMessageSignature(r *http.Request, secret string) (bool, error) {
+	sig, err := parseMessageSignature(r)
+	if err != nil {
+		return false, err
+	}
+	if sig.keyId == "" {
+		return false, failure(ReasonMissingHeader, "missing signature keyid")
+	}
+	if alg := Algorithm(sig.alg); alg != "" && (alg != HMACSHA256 || !v.opts.allowedAlgorithms[alg]) {
+		return false, failure(ReasonAlgorithmNotAllowed, "signature algorithm not allowed: %s", alg)
+	}
+	for _, name := range requiredComponents {
+		if !slices.Contains(sig.covered, name) {
+			return false, fmt.Errorf("signature does not cover %s", name)
+		}
+	}
+
+	// The identity impersonated must be covered
+	if v.opts.requestImpersonation(r) != nil {
+		for _, name := range []string{v.opts.headers.ImpersonateUser, v.opts.headers.ImpersonateTenant} {
+			if !slices.Contains(sig.covered, strings.ToLower(name)) {
+				return false, fmt.Errorf("signature does not cover %s", strings.ToLower(name))
+			}
+		}
+	}
+
+	// Check if the request is within the allowed validity window
+	if sig.created == 0 {
+		return false, fmt.Errorf("missing signature created time")
+	}
+	now := time.Now().Unix()
+	if now >= sig.created+v.validity || (sig.expires != 0 && now >= sig.expires) {
+		return false, errExpired
+	}
+
+	// The body must be covered by a matching digest
+	if slices.Contains(sig.covered, componentDigest) {
+		var digest string
+		err := v.opts.withBodyLimit(r, func() (err error) {
+			digest, err = contentDigest(r)
+			return err
+		})
+		if err != nil {
+			return false, err
+		}
+		if !slices.Contains(splitMembers(r.Header.Get(ContentDigestHeader)), digest) {
+			return false, failure(ReasonSignatureMismatch, "content digest mismatch")
+		}
+	} else if hasBody(r) {
+		return false, fmt.Errorf("signature does not cover %s", componentDigest)
+	}
+
+	base, err := signatureBase(r, sig.covered, sig.params)
+	if err != nil {
+		return false, err
+	}
+	if !hmac.Equal(sig.signature, generateHMAC(sha256.New, secret, base)) {
+		return false, errMessageSignatureMismatch
+	}
+	return true, nil
+}
+
+// GetKeyId returns the key id of the message signature of the request.
+func (v *messageSignatureValidator) GetKeyId(r *http.Request) string {
+	sig, err := parseMessageSignature(r)
+	if err != nil {
+		return ""
+	}
+	return sig.keyId
+}
+
+// NewMessageSignatureValidator creates a Validator for RFC 9421 message
+// signatures created within validity seconds.
+func NewMessageSignatureValidator(validity int64, opts ...Option) Validator {
+	return &messageSignatureValidator{
+		validator: validator{
+			validity: validity,
+			opts:     newOptions(opts...),
+		},
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMessageSignatureVector verifies the HMAC-SHA256 example of RFC 9421
+// appendix B.2.5.
+func TestMessageSignatureVector(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	req.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureInputHeader, `sig-b25=("date" "@authority" "content-type");created=1618884473;keyid="test-shared-secret"`)
+	req.Header.Set(SignatureHeader, `sig-b25=:pxcQw6G3AjtMBQjwo8XzkZf/bws5LelbaMk5rGIGtE8=:`)
+
+	sig, err := parseMessageSignature(req)
+	if err != nil {
+		t.Fatalf("failed to parse signature: %s", err)
+	}
+	if sig.keyId != "test-shared-secret" || sig.created != 1618884473 || len(sig.covered) != 3 {
+		t.Errorf("unexpected signature %+v", sig)
+	}
+	base, err := signatureBase(req, sig.covered, sig.params)
+	if err != nil {
+		t.Fatalf("failed to compute signature base: %s", err)
+	}
+	secret, _ := base64.StdEncoding.DecodeString("uzvJfB4u3N0Jy4T7NZ75MDVcr8zSTInedJtkgcu46YW4XByzNJjxBdtjUkdJPBtbmHhIDi6pcl8jsasjlTMtDQ==")
+	if !hmac.Equal(sig.signature, generateHMAC(sha256.New, string(secret), base)) {
+		t.Errorf("signature mismatch over base:\n%s", base)
+	}
+}
+
+func TestMessageSignature(t *testing.T) {
+	gen := NewMessageSignatureGenerator("partner", "supersecret", 5*time.Minute)
+	v := NewMessageSignatureValidator(60)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/books?limit=10", bytes.NewReader([]byte(`{"title":"go"}`)))
+	req = gen.AddAuthHeaders(req)
+	if !IsMessageSignature(req) || req.Header.Get(ContentDigestHeader) == "" {
+		t.Fatalf("expected message signature headers, got %v", req.Header)
+	}
+	if v.GetKeyId(req) != "partner" {
+		t.Errorf("unexpected key id %q", v.GetKeyId(req))
+	}
+	if ok, err := v.Validate(req, "supersecret"); !ok {
+		t.Fatalf("expected request to be valid: %s", err)
+	}
+	if ok, _ := v.Validate(req, "wrong"); ok {
+		t.Errorf("expected wrong secret to be rejected")
+	}
+
+	tampered := req.Clone(req.Context())
+	tampered.URL.RawQuery = "limit=1000"
+	if _, err := v.Validate(tampered, "supersecret"); !errors.Is(err, errSignatureMismatch) {
+		t.Errorf("expected tampered query to be rejected, got %v", err)
+	}
+
+	body, _ := http.NewRequest(http.MethodPost, "https://api.example.com/books?limit=10", bytes.NewReader([]byte(`{"title":"rust"}`)))
+	body.Header = req.Header.Clone()
+	if ok, _ := v.Validate(body, "supersecret"); ok {
+		t.Errorf("expected tampered body to be rejected")
+	}
+
+	get, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	get = NewMessageSignatureGenerator("partner", "supersecret", 0).AddAuthHeaders(get)
+	if strings.Contains(get.Header.Get(SignatureInputHeader), "content-digest") {
+		t.Errorf("expected no digest without body: %s", get.Header.Get(SignatureInputHeader))
+	}
+	if ok, err := v.Validate(get, "supersecret"); !ok {
+		t.Errorf("expected request without body to be valid: %s", err)
+	}
+}
+
+func TestMessageSignatureRequiredComponents(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	params := `("@method" "@path");created=` + strconv.FormatInt(time.Now().Unix(), 10) + `;keyid="partner"`
+	base, _ := signatureBase(req, []string{"@method", "@path"}, params)
+	req.Header.Set(SignatureInputHeader, "sig1="+params)
+	req.Header.Set(SignatureHeader, "sig1=:"+base64.StdEncoding.EncodeToString(generateHMAC(sha256.New, "supersecret", base))+":")
+	if ok, _ := NewMessageSignatureValidator(60).Validate(req, "supersecret"); ok {
+		t.Errorf("expected signature not covering the query to be rejected")
+	}
+}
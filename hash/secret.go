@@ -0,0 +1,141 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+)
+
+/*
+This file provides the generation of API key identifiers and secrets from
+crypto/rand, encoded as unpadded base64url so that they are safe in
+headers, URLs and environment variables.
+
+A KeyFormat optionally adds a prefix identifying the kind of credential,
+e.g. "ak_live_", and a checksum, allowing secret scanners to detect leaked
+credentials with few false positives and clients to reject mistyped ones
+without a round trip to the server.
+
+# Usage
+
+    id, _ := hash.GenerateKeyID()
+    secret, _ := hash.GenerateSecret(hash.DefaultSecretBytes)
+
+    live := hash.KeyFormat{Prefix: "ak_live_", Checksum: true}
+    secret, _ = live.GenerateSecret(hash.DefaultSecretBytes)
+    err := live.Verify(secret)
+*/
+
+const (
+	// DefaultSecretBytes is the recommended number of random bytes of a
+	// secret
+	DefaultSecretBytes = 32
+
+	// MinSecretBytes is the minimum number of random bytes of a secret
+	MinSecretBytes = 16
+
+	// KeyIDBytes is the number of random bytes of a key identifier
+	KeyIDBytes = 16
+
+	// length of the encoded checksum, the base64url encoding of the
+	// 4 bytes of the CRC32
+	checksumLength = 6
+)
+
+// ErrInvalidKeyFormat is returned by KeyFormat.Verify for values without
+// the prefix or with a checksum mismatch.
+var ErrInvalidKeyFormat = errors.New("invalid key format")
+
+// randomToken returns n random bytes encoded as unpadded base64url.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// GenerateSecret returns a secret of the number of random bytes, at least
+// MinSecretBytes, encoded as unpadded base64url.
+func GenerateSecret(bytes int) (string, error) {
+	if bytes < MinSecretBytes {
+		return "", fmt.Errorf("secret of %d bytes is shorter than the minimum of %d bytes", bytes, MinSecretBytes)
+	}
+	return randomToken(bytes)
+}
+
+// GenerateKeyID returns a key identifier of KeyIDBytes random bytes,
+// encoded as unpadded base64url.
+func GenerateKeyID() (string, error) {
+	return randomToken(KeyIDBytes)
+}
+
+// KeyFormat describes the conventions of the generated key identifiers
+// and secrets.
+type KeyFormat struct {
+	// prefix identifying the kind of credential, e.g. "ak_live_"
+	Prefix string
+
+	// append a CRC32 checksum of the prefix and the random part
+	Checksum bool
+}
+
+// checksum returns the encoded checksum of the value.
+func checksum(v string) string {
+	b := binary.BigEndian.AppendUint32(nil, crc32.ChecksumIEEE([]byte(v)))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// format applies the prefix and the checksum to the random token.
+func (f KeyFormat) format(token string) string {
+	v := f.Prefix + token
+	if f.Checksum {
+		v += checksum(v)
+	}
+	return v
+}
+
+// GenerateSecret returns a secret as per GenerateSecret in the format.
+func (f KeyFormat) GenerateSecret(bytes int) (string, error) {
+	token, err := GenerateSecret(bytes)
+	if err != nil {
+		return "", err
+	}
+	return f.format(token), nil
+}
+
+// GenerateKeyID returns a key identifier as per GenerateKeyID in the
+// format.
+func (f KeyFormat) GenerateKeyID() (string, error) {
+	token, err := GenerateKeyID()
+	if err != nil {
+		return "", err
+	}
+	return f.format(token), nil
+}
+
+// Verify returns ErrInvalidKeyFormat if the value does not carry the
+// prefix or its checksum does not match.
+func (f KeyFormat) Verify(v string) error {
+	if !strings.HasPrefix(v, f.Prefix) {
+		return fmt.Errorf("%w: missing prefix %q", ErrInvalidKeyFormat, f.Prefix)
+	}
+	if !f.Checksum {
+		return nil
+	}
+	if len(v) <= len(f.Prefix)+checksumLength {
+		return fmt.Errorf("%w: too short", ErrInvalidKeyFormat)
+	}
+	body, sum := v[:len(v)-checksumLength], v[len(v)-checksumLength:]
+	if checksum(body) != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidKeyFormat)
+	}
+	return nil
+}
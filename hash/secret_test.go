@@ -0,0 +1,74 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret(DefaultSecretBytes)
+	if err != nil {
+		t.Fatalf("failed to generate secret: %s", err)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(secret)
+	if err != nil || len(b) != DefaultSecretBytes {
+		t.Errorf("expected %d base64url encoded bytes, got %q", DefaultSecretBytes, secret)
+	}
+	other, _ := GenerateSecret(DefaultSecretBytes)
+	if other == secret {
+		t.Errorf("expected distinct secrets")
+	}
+	if _, err := GenerateSecret(MinSecretBytes - 1); err == nil {
+		t.Errorf("expected short secret to be refused")
+	}
+}
+
+func TestGenerateKeyID(t *testing.T) {
+	id, err := GenerateKeyID()
+	if err != nil {
+		t.Fatalf("failed to generate key id: %s", err)
+	}
+	if b, err := base64.RawURLEncoding.DecodeString(id); err != nil || len(b) != KeyIDBytes {
+		t.Errorf("unexpected key id %q", id)
+	}
+	if IsDelegatedKeyId(id) {
+		t.Errorf("generated key id must not be taken for a delegated one")
+	}
+}
+
+func TestKeyFormat(t *testing.T) {
+	f := KeyFormat{Prefix: "ak_live_", Checksum: true}
+	secret, err := f.GenerateSecret(DefaultSecretBytes)
+	if err != nil {
+		t.Fatalf("failed to generate secret: %s", err)
+	}
+	if !strings.HasPrefix(secret, "ak_live_") {
+		t.Errorf("expected prefix, got %q", secret)
+	}
+	if err := f.Verify(secret); err != nil {
+		t.Errorf("expected generated secret to verify: %s", err)
+	}
+
+	// a single mistyped character fails the checksum
+	i := len(f.Prefix) + 3
+	c := byte('A')
+	if secret[i] == c {
+		c = 'B'
+	}
+	typo := secret[:i] + string(c) + secret[i+1:]
+	for _, v := range []string{typo, "ak_test_" + secret[8:], "ak_live_", secret[:len(secret)-1]} {
+		if err := f.Verify(v); !errors.Is(err, ErrInvalidKeyFormat) {
+			t.Errorf("Verify(%q) = %v, expected ErrInvalidKeyFormat", v, err)
+		}
+	}
+
+	id, _ := KeyFormat{Prefix: "ak_"}.GenerateKeyID()
+	if err := (KeyFormat{Prefix: "ak_"}).Verify(id); err != nil || !strings.HasPrefix(id, "ak_") {
+		t.Errorf("unexpected key id %q: %v", id, err)
+	}
+}
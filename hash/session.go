@@ -0,0 +1,308 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This file provides temporary credentials, in the spirit of a security
+token service: a caller signing with its long-lived API key exchanges it
+for a temporary key ID, secret and session token expiring after a short
+duration, handed out to jobs instead of the long-lived key.
+
+The temporary credentials are stateless, the secret is derived from the
+key of the issuer and the temporary key ID, and the session token carries
+the temporary and parent key IDs along with the expiry, authenticated with
+the key of the issuer:
+
+    secret = HMAC-SHA256(issuer key, keyId)
+    token  = base64url(claims) + "." + base64url(HMAC-SHA256(issuer key, claims))
+
+Requests are signed with the temporary key ID and secret, and carry the
+session token in the x-session-token header. The SessionIssuer resolves
+the secrets of the temporary key IDs, while the SessionValidator requires
+a valid unexpired session token issued for the key ID of the request.
+
+# Usage
+
+    issuer, _ := hash.NewSessionIssuer(issuerKey, time.Hour)
+
+    // server side, exchanging the long-lived keys at /v1/sessions
+    mux.Handle("/v1/sessions", issuer.Handler(validator, resolver))
+    resolver = issuer.SecretResolver(resolver)
+    validator = hash.NewSessionValidator(validator, issuer)
+
+    // job side
+    creds, _ := issuer.Issue(ctx, keyId, 15*time.Minute)
+    gen := hash.NewGenerator(creds.KeyId, creds.Secret, hash.WithSessionToken(creds.SessionToken))
+*/
+
+// sessionKeyPrefix prefixes the key IDs of temporary credentials
+const sessionKeyPrefix = "ses."
+
+// Session durations applied unless configured otherwise.
+const (
+	// DefaultSessionTTL is the duration of the temporary credentials
+	// issued without a requested duration
+	DefaultSessionTTL = 15 * time.Minute
+
+	// MinSessionTTL is the minimum duration of temporary credentials
+	MinSessionTTL = time.Minute
+)
+
+// minSessionIssuerKeyBytes is the minimum length of the issuer key
+const minSessionIssuerKeyBytes = 32
+
+var (
+	// ErrInvalidSessionToken is returned for session tokens not issued
+	// by the issuer, malformed, or not matching the key ID of the request.
+	ErrInvalidSessionToken = errors.New("invalid session token")
+
+	// ErrSessionExpired is returned for expired session tokens.
+	ErrSessionExpired = errors.New("session expired")
+)
+
+// Session is the content of a session token.
+type Session struct {
+	// temporary key ID the token is issued for
+	KeyId string `json:"k"`
+
+	// long-lived API key exchanged for the temporary credentials
+	ParentKeyId string `json:"p"`
+
+	// expiry of the temporary credentials, unix seconds
+	Expiry int64 `json:"e"`
+}
+
+// SessionCredentials are temporary credentials.
+type SessionCredentials struct {
+	KeyId        string `json:"key_id"`
+	Secret       string `json:"secret"`
+	SessionToken string `json:"session_token"`
+
+	// expiry of the credentials, unix seconds
+	Expiry int64 `json:"expiry"`
+}
+
+// IsSessionKeyId reports whether the key ID is of temporary credentials.
+func IsSessionKeyId(keyId string) bool {
+	return strings.HasPrefix(keyId, sessionKeyPrefix)
+}
+
+// SessionIssuer issues and verifies temporary credentials.
+type SessionIssuer struct {
+	key    []byte
+	maxTTL time.Duration
+	now    func() time.Time
+}
+
+// NewSessionIssuer creates the issuer authenticating the temporary
+// credentials with the key, of at least 32 bytes, shared by all the
+// replicas, and issuing them for at most maxTTL.
+func NewSessionIssuer(key []byte, maxTTL time.Duration) (*SessionIssuer, error) {
+	if len(key) < minSessionIssuerKeyBytes {
+		return nil, fmt.Errorf("session issuer key must be at least %d bytes", minSessionIssuerKeyBytes)
+	}
+	if maxTTL < MinSessionTTL {
+		maxTTL = MinSessionTTL
+	}
+	return &SessionIssuer{
+		key:    key,
+		maxTTL: maxTTL,
+		now:    time.Now,
+	}, nil
+}
+
+// mac authenticates the value with the key of the issuer.
+func (i *SessionIssuer) mac(v ...string) []byte {
+	return generateHMAC(sha256.New, string(i.key), v...)
+}
+
+// secret derives the secret of the temporary key ID.
+func (i *SessionIssuer) secret(keyId string) string {
+	return base64.RawURLEncoding.EncodeToString(i.mac(keyId))
+}
+
+// Issue issues temporary credentials for the API key, expiring after ttl,
+// DefaultSessionTTL if zero, bounded by the maximum duration of the
+// issuer. Temporary credentials can not be exchanged again.
+func (i *SessionIssuer) Issue(ctx context.Context, parentKeyId string, ttl time.Duration) (*SessionCredentials, error) {
+	if parentKeyId == "" || IsSessionKeyId(parentKeyId) {
+		return nil, fmt.Errorf("invalid parent key id %q", parentKeyId)
+	}
+	if ttl == 0 {
+		ttl = DefaultSessionTTL
+	}
+	ttl = min(max(ttl, MinSessionTTL), i.maxTTL)
+	id, err := GenerateKeyID()
+	if err != nil {
+		return nil, err
+	}
+	s := &Session{
+		KeyId:       sessionKeyPrefix + id,
+		ParentKeyId: parentKeyId,
+		Expiry:      i.now().Add(ttl).Unix(),
+	}
+	claims, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(claims)
+	return &SessionCredentials{
+		KeyId:        s.KeyId,
+		Secret:       i.secret(s.KeyId),
+		SessionToken: encoded + "." + base64.RawURLEncoding.EncodeToString(i.mac(encoded)),
+		Expiry:       s.Expiry,
+	}, nil
+}
+
+// VerifyToken authenticates the session token and ensures it is not
+// expired, returning its content.
+func (i *SessionIssuer) VerifyToken(token string) (*Session, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidSessionToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, i.mac(encoded)) {
+		return nil, ErrInvalidSessionToken
+	}
+	claims, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidSessionToken
+	}
+	s := &Session{}
+	if err := json.Unmarshal(claims, s); err != nil || !IsSessionKeyId(s.KeyId) {
+		return nil, ErrInvalidSessionToken
+	}
+	if i.now().Unix() >= s.Expiry {
+		return nil, ErrSessionExpired
+	}
+	return s, nil
+}
+
+// SecretResolver returns a resolver deriving the secrets of temporary key
+// IDs, and resolving other key IDs using the base resolver.
+func (i *SessionIssuer) SecretResolver(base SecretResolver) SecretResolver {
+	return SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		if !IsSessionKeyId(keyId) {
+			return base.GetSecret(ctx, keyId)
+		}
+		return i.secret(keyId), nil
+	})
+}
+
+// sessionDurationParam is the query parameter carrying the requested
+// duration of the temporary credentials, in seconds.
+const sessionDurationParam = "duration"
+
+// Handler returns the handler exchanging the API key signing the request,
+// validated using v with the secret resolved by secrets, for temporary
+// credentials, returned as JSON. The requested duration in seconds is
+// taken from the duration query parameter.
+func (i *SessionIssuer) Handler(v Validator, secrets SecretResolver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		keyId := v.GetKeyId(r)
+		if keyId == "" || IsSessionKeyId(keyId) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		secret, err := secrets.GetSecret(r.Context(), keyId)
+		if err != nil {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if ok, _ := v.Validate(r, secret); !ok {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var ttl time.Duration
+		if d := r.URL.Query().Get(sessionDurationParam); d != "" {
+			secs, err := strconv.ParseInt(d, 10, 64)
+			if err != nil || secs <= 0 {
+				http.Error(w, "invalid duration", http.StatusBadRequest)
+				return
+			}
+			ttl = time.Duration(secs) * time.Second
+		}
+		creds, err := i.Issue(r.Context(), keyId, ttl)
+		if err != nil {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(creds)
+	})
+}
+
+// WithSessionToken makes the Generator send the session token of the
+// temporary credentials it signs with.
+func WithSessionToken(token string) Option {
+	return func(o *options) {
+		o.sessionToken = token
+	}
+}
+
+// sessionValidator enforces the session tokens of temporary credentials.
+type sessionValidator struct {
+	Validator
+	issuer *SessionIssuer
+	header string
+}
+
+// Unwrap returns the wrapped validator.
+func (v *sessionValidator) Unwrap() Validator {
+	return v.Validator
+}
+
+// Validate validates the request using the wrapped validator, and for
+// temporary credentials ensures that the request carries a valid session
+// token issued for its key ID.
+func (v *sessionValidator) Validate(r *http.Request, secret string) (bool, error) {
+	ok, err := v.Validator.Validate(r, secret)
+	if !ok {
+		return ok, err
+	}
+	keyId := v.GetKeyId(r)
+	if !IsSessionKeyId(keyId) {
+		return true, nil
+	}
+	s, err := v.issuer.VerifyToken(r.Header.Get(v.header))
+	if err != nil {
+		return false, err
+	}
+	if s.KeyId != keyId {
+		return false, ErrInvalidSessionToken
+	}
+	return true, nil
+}
+
+// NewSessionValidator returns a validator enforcing the session tokens of
+// the temporary credentials issued by the issuer, on top of the base
+// validator. The options configure the session token header name.
+func NewSessionValidator(base Validator, issuer *SessionIssuer, opts ...Option) Validator {
+	return &sessionValidator{
+		Validator: base,
+		issuer:    issuer,
+		header:    newOptions(opts...).headers.SessionToken,
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSessionIssuer(t *testing.T) *SessionIssuer {
+	issuer, err := NewSessionIssuer([]byte(strings.Repeat("k", 32)), time.Hour)
+	if err != nil {
+		t.Fatalf("failed to create issuer: %s", err)
+	}
+	return issuer
+}
+
+func TestSessionCredentials(t *testing.T) {
+	issuer := newTestSessionIssuer(t)
+	creds, err := issuer.Issue(context.Background(), "parent", 2*time.Hour)
+	if err != nil {
+		t.Fatalf("failed to issue credentials: %s", err)
+	}
+	if !IsSessionKeyId(creds.KeyId) || creds.Expiry > time.Now().Add(time.Hour).Unix() || creds.Expiry < time.Now().Add(59*time.Minute).Unix() {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+
+	base := SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "", errors.New("unknown key")
+	})
+	secret, err := issuer.SecretResolver(base).GetSecret(context.Background(), creds.KeyId)
+	if err != nil || secret != creds.Secret {
+		t.Fatalf("expected the secret to be derived, got %q %v", secret, err)
+	}
+
+	v := NewSessionValidator(NewValidator(60), issuer)
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/books", nil)
+	req = NewGenerator(creds.KeyId, creds.Secret, WithSessionToken(creds.SessionToken)).AddAuthHeaders(req)
+	if ok, err := v.Validate(req, secret); !ok {
+		t.Fatalf("expected request to be valid: %s", err)
+	}
+
+	req.Header.Del("x-session-token")
+	if ok, _ := v.Validate(req, secret); ok {
+		t.Errorf("expected request without session token to be rejected")
+	}
+
+	other, _ := issuer.Issue(context.Background(), "parent", 0)
+	req.Header.Set("x-session-token", other.SessionToken)
+	if _, err := v.Validate(req, secret); !errors.Is(err, ErrInvalidSessionToken) {
+		t.Errorf("expected token of another key to be rejected, got %v", err)
+	}
+
+	issuer.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, err := issuer.VerifyToken(creds.SessionToken); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected expired session, got %v", err)
+	}
+	if _, err := issuer.VerifyToken(creds.SessionToken + "x"); !errors.Is(err, ErrInvalidSessionToken) {
+		t.Errorf("expected tampered token to be rejected, got %v", err)
+	}
+}
+
+func TestSessionHandler(t *testing.T) {
+	issuer := newTestSessionIssuer(t)
+	resolver := SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "supersecret", nil
+	})
+	h := issuer.Handler(NewValidator(60), resolver)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions?duration=600", nil)
+	req = NewGenerator("parent", "supersecret").AddAuthHeaders(req)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected credentials to be issued, got %d", rec.Code)
+	}
+	creds := &SessionCredentials{}
+	if err := json.Unmarshal(rec.Body.Bytes(), creds); err != nil {
+		t.Fatalf("invalid response: %s", err)
+	}
+	s, err := issuer.VerifyToken(creds.SessionToken)
+	if err != nil || s.ParentKeyId != "parent" || s.KeyId != creds.KeyId {
+		t.Errorf("unexpected session %+v %v", s, err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/sessions", nil)
+	req = NewGenerator("parent", "wrong").AddAuthHeaders(req)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"fmt"
+	stdhash "hash"
+	"sync"
+
+	"github.com/go-core-stack/auth/internal/lru"
+)
+
+/*
+This file provides the Signer, computing the HMAC of the newline joined
+input strings exactly as GenerateHMAC does, without allocating on the hot
+path of the Generator and the Validator:
+
+  - the keyed HMAC states are pooled per secret and reset between uses,
+    instead of deriving the inner and outer pads on every call
+  - the inputs are written to the HMAC incrementally through a fixed
+    scratch buffer, instead of being joined into a new string
+  - the signature is encoded into a buffer provided by the caller
+
+The pooled states retain the secrets in memory, the number of secrets
+pooled is bounded by DefaultSignerSecrets, dropping the pools of the least
+recently used ones, so that the per request derived keys, e.g. of the
+impersonated users, neither grow the pools without bound nor flush the
+pools of the secrets in use.
+
+# Usage
+
+    s, _ := hash.NewSigner(hash.HMACSHA256)
+    sig := s.Sign(secret, method, path, timestamp)
+
+    // zero allocation, with a reusable buffer
+    buf = s.AppendSign(buf[:0], secret, method, path, timestamp)
+*/
+
+// DefaultSignerSecrets is the maximum number of secrets the Signer pools
+// the HMAC states of.
+const DefaultSignerSecrets = 1024
+
+// signerScratchSize is the size of the buffer the inputs are written to
+// the HMAC through.
+const signerScratchSize = 512
+
+// newline separating the input strings
+var newline = []byte{'\n'}
+
+// hmacState is a pooled keyed HMAC along with its buffers.
+type hmacState struct {
+	mac     stdhash.Hash
+	scratch [signerScratchSize]byte
+	sum     []byte
+}
+
+// write writes the string to the HMAC without converting it to a new byte
+// slice.
+func (st *hmacState) write(s string) {
+	for len(s) > 0 {
+		n := copy(st.scratch[:], s)
+		st.mac.Write(st.scratch[:n])
+		s = s[n:]
+	}
+}
+
+// Signer computes the HMAC signatures of an algorithm with pooled states,
+// see the file documentation. A Signer is safe for concurrent use.
+type Signer struct {
+	h    func() stdhash.Hash
+	size int // size of the digest in bytes
+
+	pools *lru.Cache[string, *sync.Pool]
+}
+
+// NewSigner creates the signer for the algorithm, failing if the algorithm
+// is not supported.
+func NewSigner(alg Algorithm) (*Signer, error) {
+	h, ok := supportedAlgorithms[alg]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signature algorithm: %s", alg)
+	}
+	return &Signer{h: h, size: h().Size(), pools: lru.New[string, *sync.Pool](DefaultSignerSecrets)}, nil
+}
+
+// pool returns the pool of the HMAC states keyed with the secret.
+func (s *Signer) pool(secret string) *sync.Pool {
+	if p, ok := s.pools.Get(secret); ok {
+		return p
+	}
+	// the pools created concurrently for the same secret are equivalent,
+	// the last one added is kept
+	key := []byte(secret)
+	p := &sync.Pool{
+		New: func() any {
+			return &hmacState{mac: hmac.New(s.h, key)}
+		},
+	}
+	s.pools.Add(secret, p)
+	return p
+}
+
+// sum computes the raw HMAC of the newline joined inputs into the scratch
+// sum of the state, returning the state to be released to the pool.
+func (s *Signer) sum(secret string, v []string) (*sync.Pool, *hmacState) {
+	p := s.pool(secret)
+	st := p.Get().(*hmacState)
+	st.mac.Reset()
+	for i, e := range v {
+		if i != 0 {
+			st.mac.Write(newline)
+		}
+		st.write(e)
+	}
+	st.sum = st.mac.Sum(st.sum[:0])
+	return p, st
+}
+
+// AppendSum appends the raw HMAC of the newline joined inputs to dst.
+func (s *Signer) AppendSum(dst []byte, secret string, v ...string) []byte {
+	p, st := s.sum(secret, v)
+	dst = append(dst, st.sum...)
+	p.Put(st)
+	return dst
+}
+
+// AppendSign appends the hex-encoded HMAC of the newline joined inputs to
+// dst, not allocating if dst has the capacity for it.
+func (s *Signer) AppendSign(dst []byte, secret string, v ...string) []byte {
+	p, st := s.sum(secret, v)
+	dst = hex.AppendEncode(dst, st.sum)
+	p.Put(st)
+	return dst
+}
+
+// Sign returns the hex-encoded HMAC of the newline joined inputs, same as
+// GenerateHMAC.
+func (s *Signer) Sign(secret string, v ...string) string {
+	var buf [2 * 64]byte
+	return string(s.AppendSign(buf[:0], secret, v...))
+}
+
+// signers are the package wide signers of the supported algorithms.
+var signers = func() map[Algorithm]*Signer {
+	m := map[Algorithm]*Signer{}
+	for alg := range supportedAlgorithms {
+		m[alg], _ = NewSigner(alg)
+	}
+	return m
+}()
@@ -0,0 +1,137 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSigner(t *testing.T) {
+	long := strings.Repeat("x", 3*signerScratchSize+7)
+	inputs := [][]string{
+		{},
+		{""},
+		{"", ""},
+		{"POST", "/api/service1/v1/scope/abc/test/test1", "1748410688"},
+		{"PUT", long, "1748410688"},
+	}
+	for _, alg := range SupportedAlgorithms() {
+		s, err := NewSigner(alg)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		for _, v := range inputs {
+			want := hex.EncodeToString(generateHMAC(supportedAlgorithms[alg], "supersecret", v...))
+			if got := s.Sign("supersecret", v...); got != want {
+				t.Errorf("%s: expected %s, got %s", alg, want, got)
+			}
+			raw := generateHMAC(supportedAlgorithms[alg], "supersecret", v...)
+			if got := s.AppendSum([]byte("prefix"), "supersecret", v...); !bytes.Equal(got, append([]byte("prefix"), raw...)) {
+				t.Errorf("%s: unexpected raw sum %x", alg, got)
+			}
+		}
+		if s.Sign("secret1", "foo") == s.Sign("secret2", "foo") {
+			t.Errorf("%s: expected signatures to differ across secrets", alg)
+		}
+	}
+
+	if _, err := NewSigner(Algorithm("md5")); err == nil {
+		t.Errorf("expected error for unsupported algorithm")
+	}
+}
+
+func TestSignerBounded(t *testing.T) {
+	s, _ := NewSigner(HMACSHA256)
+	for i := range DefaultSignerSecrets + 10 {
+		secret := fmt.Sprintf("secret-%d", i)
+		if s.Sign(secret, "foo") != GenerateSHA256HMAC(secret, "foo") {
+			t.Fatalf("unexpected signature for %s", secret)
+		}
+	}
+	if s.pools.Len() > DefaultSignerSecrets {
+		t.Errorf("expected at most %d pooled secrets, got %d", DefaultSignerSecrets, s.pools.Len())
+	}
+}
+
+func TestSignerKeepsSecretsInUse(t *testing.T) {
+	s, _ := NewSigner(HMACSHA256)
+	s.Sign("in-use", "foo")
+	p, _ := s.pools.Get("in-use")
+
+	// derived secrets flooding the signer do not flush the pool of the
+	// secret in use
+	for i := range 4 * DefaultSignerSecrets {
+		s.Sign(fmt.Sprintf("derived-%d", i), "foo")
+		if i%(DefaultSignerSecrets/2) == 0 {
+			s.Sign("in-use", "foo")
+		}
+	}
+	if cur, ok := s.pools.Get("in-use"); !ok || cur != p {
+		t.Errorf("expected the pool of the secret in use to be kept")
+	}
+}
+
+func TestSignerConcurrent(t *testing.T) {
+	s, _ := NewSigner(HMACSHA256)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			secret := fmt.Sprintf("secret-%d", i%2)
+			want := hex.EncodeToString(generateHMAC(sha256.New, secret, "GET", "/books"))
+			for range 100 {
+				if got := s.Sign(secret, "GET", "/books"); got != want {
+					t.Errorf("expected %s, got %s", want, got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSignerAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not meaningful with the race detector")
+	}
+	s, _ := NewSigner(HMACSHA256)
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = s.AppendSign(buf[:0], "supersecret", "GET", "/books/42", "limit=10", "1748410688")
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkGenerateHMACUnpooled(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = hex.EncodeToString(generateHMAC(sha256.New, "supersecret", "GET", "/books/42", "limit=10", "1748410688"))
+	}
+}
+
+func BenchmarkSignerSign(b *testing.B) {
+	s, _ := NewSigner(HMACSHA256)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = s.Sign("supersecret", "GET", "/books/42", "limit=10", "1748410688")
+	}
+}
+
+func BenchmarkSignerAppendSign(b *testing.B) {
+	s, _ := NewSigner(HMACSHA256)
+	buf := make([]byte, 0, 64)
+	b.ReportAllocs()
+	for b.Loop() {
+		buf = s.AppendSign(buf[:0], "supersecret", "GET", "/books/42", "limit=10", "1748410688")
+	}
+}
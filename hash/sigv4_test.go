@@ -0,0 +1,48 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSigV4 verifies the vectors of the AWS Signature Version 4 test
+// suite.
+func TestSigV4(t *testing.T) {
+	cases := map[string]string{
+		"https://example.amazonaws.com/":                             "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		"https://example.amazonaws.com/?Param2=value2&Param1=value1": "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+	}
+	for url, expected := range cases {
+		gen := NewSigV4Generator("us-east-1", "service",
+			StaticCredentials("AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY")).(*sigV4Generator)
+		gen.now = func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) }
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		req = gen.AddAuthHeaders(req)
+
+		auth := req.Header.Get("Authorization")
+		prefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature="
+		if auth != prefix+expected {
+			t.Errorf("%s: unexpected authorization %q", url, auth)
+		}
+		if req.Header.Get("X-Amz-Date") != "20150830T123600Z" {
+			t.Errorf("%s: unexpected date %q", url, req.Header.Get("X-Amz-Date"))
+		}
+	}
+}
+
+func TestSigV4SessionToken(t *testing.T) {
+	gen := NewSigV4Generator("us-east-1", "execute-api", StaticCredentials("AKIDEXAMPLE", "secret"), WithSessionToken("token"))
+	req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/books", strings.NewReader(`{}`))
+	req = gen.AddAuthHeaders(req)
+	if req.Header.Get("X-Amz-Security-Token") != "token" {
+		t.Errorf("expected security token header")
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+		t.Errorf("expected security token to be signed, got %q", req.Header.Get("Authorization"))
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	stdhash "hash"
+	"io"
+	"net/http"
+)
+
+/*
+This file provides the streaming signature version, which protects the
+integrity of large request bodies, e.g. multi-GB uploads, without buffering
+them in memory to compute the body hash upfront.
+
+With SignatureStreaming the request headers are signed over the method,
+path, canonical query and timestamp, while the body is hashed as it is
+sent and its signature is carried in the x-content-signature trailer of the
+chunked request:
+
+    HMAC(secret, signature + "\n" + hex(sha256(body)))
+
+chaining the body to the header signature of the same request. On the
+server side the Validator verifies the header signature upfront and wraps
+the request body, such that reading the body to the end fails with
+ErrContentSignatureMismatch if the body does not match its signature.
+Handlers must therefore consume the body completely and treat read errors
+as a rejected request.
+
+# Usage
+
+    gen := hash.NewGenerator("api-key-id", "supersecret",
+        hash.WithSignatureVersion(hash.SignatureStreaming))
+    req, _ := http.NewRequest("PUT", "https://api.example.com/files/big.iso", file)
+    resp, err := http.DefaultClient.Do(gen.AddAuthHeaders(req))
+
+Streaming signatures are only supported with HMAC algorithms.
+*/
+
+// SignatureStreaming signs the method, path, canonical query and timestamp
+// in the headers, with the body signature sent as a trailer.
+const SignatureStreaming SignatureVersion = "v2-streaming"
+
+// streamingPayload is the placeholder for the body hash in the signed
+// components of a streamed request
+const streamingPayload = "STREAMING-PAYLOAD"
+
+// ErrContentSignatureMismatch is returned while reading the body of a
+// streamed request whose body does not match the content signature.
+var ErrContentSignatureMismatch = errors.New("invalid content signature")
+
+// signatureSchemeStreaming signs the method, path, canonical query and
+// timestamp, the body is covered by the content signature trailer.
+func signatureSchemeStreaming(r *http.Request, timestamp string) ([]string, error) {
+	return []string{r.Method, r.URL.Path, r.URL.Query().Encode(), streamingPayload, timestamp}, nil
+}
+
+// contentSignature computes the signature of the streamed body, chained to
+// the header signature.
+func contentSignature(h func() stdhash.Hash, secret, signature, bodyHash string) string {
+	return hex.EncodeToString(generateHMAC(h, secret, signature, bodyHash))
+}
+
+// digestBody hashes the body as it is read, invoking onEOF with the hex
+// encoded sha256 of the body once it is read completely.
+type digestBody struct {
+	body  io.ReadCloser
+	sum   stdhash.Hash
+	onEOF func(bodyHash string) error
+	done  bool
+}
+
+// Read reads from the underlying body, replacing io.EOF with the error
+// returned by onEOF if any.
+func (d *digestBody) Read(p []byte) (int, error) {
+	n, err := d.body.Read(p)
+	d.sum.Write(p[:n])
+	if err == io.EOF && !d.done {
+		d.done = true
+		if e := d.onEOF(hex.EncodeToString(d.sum.Sum(nil))); e != nil {
+			return n, e
+		}
+	}
+	return n, err
+}
+
+// Close closes the underlying body.
+func (d *digestBody) Close() error {
+	return d.body.Close()
+}
+
+// signStreamingBody arranges for the content signature to be sent as a
+// trailer once the body is completely sent. An empty body carries the
+// content signature as a header instead.
+func signStreamingBody(r *http.Request, name string, h func() stdhash.Hash, secret, signature string) {
+	if r.Body == nil || r.Body == http.NoBody {
+		empty := sha256.Sum256(nil)
+		r.Header.Set(name, contentSignature(h, secret, signature, hex.EncodeToString(empty[:])))
+		return
+	}
+	r.Trailer = http.Header{}
+	r.Trailer[http.CanonicalHeaderKey(name)] = nil
+	r.ContentLength = -1
+	wrap := func(body io.ReadCloser) io.ReadCloser {
+		return &digestBody{
+			body: body,
+			sum:  sha256.New(),
+			onEOF: func(bodyHash string) error {
+				r.Trailer.Set(name, contentSignature(h, secret, signature, bodyHash))
+				return nil
+			},
+		}
+	}
+	r.Body = wrap(r.Body)
+	if getBody := r.GetBody; getBody != nil {
+		r.GetBody = func() (io.ReadCloser, error) {
+			body, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return wrap(body), nil
+		}
+	}
+}
+
+// verifyStreamingBody wraps the request body to verify the content
+// signature once the body is read completely.
+func verifyStreamingBody(r *http.Request, name string, h func() stdhash.Hash, secret string, signature []byte) {
+	sigHex := hex.EncodeToString(signature)
+	body := r.Body
+	if body == nil {
+		body = http.NoBody
+	}
+	r.Body = &digestBody{
+		body: body,
+		sum:  sha256.New(),
+		onEOF: func(bodyHash string) error {
+			received := r.Trailer.Get(name)
+			if received == "" {
+				received = r.Header.Get(name)
+			}
+			expected := contentSignature(h, secret, sigHex, bodyHash)
+			if !hmac.Equal([]byte(received), []byte(expected)) {
+				return ErrContentSignatureMismatch
+			}
+			return nil
+		},
+	}
+}
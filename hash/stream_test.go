@@ -0,0 +1,65 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestStreamingSignature(t *testing.T) {
+	validator := NewValidator(60)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, err := validator.Validate(r, "supersecret"); !ok {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	gen := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureStreaming))
+	for _, body := range []io.Reader{strings.NewReader(strings.Repeat("chunk", 100000)), nil} {
+		// hide the length of the body to force streaming
+		if body != nil {
+			body = io.MultiReader(body)
+		}
+		req, _ := http.NewRequest(http.MethodPut, srv.URL+"/files/big", body)
+		resp, err := http.DefaultClient.Do(gen.AddAuthHeaders(req))
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("expected streamed request to be accepted, got %d", resp.StatusCode)
+		}
+	}
+}
+
+func TestStreamingSignatureTamperedBody(t *testing.T) {
+	gen := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureStreaming))
+	req := httptest.NewRequest(http.MethodPut, "https://api.example.com/files/big", strings.NewReader("original"))
+	gen.AddAuthHeaders(req)
+	_, _ = io.Copy(io.Discard, req.Body)
+
+	// replay the signed headers and trailer with a different body
+	tampered := httptest.NewRequest(http.MethodPut, "https://api.example.com/files/big", strings.NewReader("tampered"))
+	tampered.Header = req.Header.Clone()
+	tampered.Trailer = req.Trailer.Clone()
+
+	if ok, err := NewValidator(60).Validate(tampered, "supersecret"); !ok {
+		t.Fatalf("expected header signature to be valid: %s", err)
+	}
+	if _, err := io.Copy(io.Discard, tampered.Body); !errors.Is(err, ErrContentSignatureMismatch) {
+		t.Errorf("expected content signature mismatch, got %v", err)
+	}
+}
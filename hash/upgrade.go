@@ -0,0 +1,72 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*
+This file provides the validation of protocol upgrade requests, e.g. the
+WebSocket handshake. Clients able to set headers on the handshake, such as
+client.DialWebSocket, sign it like any other request, whereas browsers,
+which cannot set headers on a WebSocket handshake, use a presigned URL
+instead, see PresignURL.
+
+# Usage
+
+    if hash.IsUpgradeRequest(r) {
+        secret, _ := resolver.GetSecret(ctx, hash.UpgradeKeyId(validator, r))
+        ok, err := hash.ValidateUpgrade(validator, r, secret)
+        ...
+    }
+*/
+
+// IsUpgradeRequest reports whether the request asks for a protocol upgrade,
+// e.g. a WebSocket handshake.
+func IsUpgradeRequest(r *http.Request) bool {
+	if r.Header.Get("Upgrade") == "" {
+		return false
+	}
+	for _, v := range r.Header.Values("Connection") {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isPresigned reports whether the request URL carries a presigned signature
+func isPresigned(r *http.Request) bool {
+	return r.URL.Query().Get(presignSignatureParam) != ""
+}
+
+// UpgradeKeyId returns the API key identifier of an upgrade request, carried
+// either by the authentication headers or by the presigned URL.
+func UpgradeKeyId(v Validator, r *http.Request) string {
+	if keyId := v.GetKeyId(r); keyId != "" {
+		return keyId
+	}
+	return GetPresignedKeyId(r.URL)
+}
+
+// ValidateUpgrade validates the signature of an upgrade request, signed
+// either using the authentication headers, validated by the Validator, or
+// using a presigned URL.
+func ValidateUpgrade(v Validator, r *http.Request, secret string) (bool, error) {
+	if !IsUpgradeRequest(r) {
+		return false, fmt.Errorf("not an upgrade request")
+	}
+	if v.GetKeyId(r) == "" && isPresigned(r) {
+		if err := ValidatePresignedRequest(secret, r); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return v.Validate(r, secret)
+}
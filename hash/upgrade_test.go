@@ -0,0 +1,41 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestValidateUpgradePresigned(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/events")
+	signed, err := PresignURL("supersecret", "test-key", u, time.Minute)
+	if err != nil {
+		t.Fatalf("failed to presign url: %s", err)
+	}
+	r := httptest.NewRequest("GET", signed.String(), nil)
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+
+	validator := NewValidator(60)
+	if !IsUpgradeRequest(r) {
+		t.Fatalf("expected upgrade request to be recognized")
+	}
+	if keyId := UpgradeKeyId(validator, r); keyId != "test-key" {
+		t.Errorf("expected key id test-key, got %s", keyId)
+	}
+	if ok, err := ValidateUpgrade(validator, r, "supersecret"); !ok {
+		t.Errorf("expected presigned upgrade to be valid: %s", err)
+	}
+	if ok, _ := ValidateUpgrade(validator, r, "other"); ok {
+		t.Errorf("expected presigned upgrade with a wrong secret to fail")
+	}
+
+	r.Header.Del("Upgrade")
+	if ok, _ := ValidateUpgrade(validator, r, "supersecret"); ok {
+		t.Errorf("expected non upgrade request to be rejected")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrUsageExhausted is returned by a limited use Validator for a validly
+// signed request of an API key that reached its maximum number of uses.
+var ErrUsageExhausted = errors.New("api key usage limit exhausted")
+
+// UsageLimitFunc returns the maximum number of uses of the API key, zero
+// for keys without a limit, e.g. one for single use bootstrap credentials.
+type UsageLimitFunc func(ctx context.Context, keyId string) (int64, error)
+
+// UsageCounter counts the uses of the API keys. Implementations must
+// increment atomically, also across the replicas sharing the counter, so
+// that a key is never used beyond its limit.
+type UsageCounter interface {
+	// Increment records a use of the key, returning the number of uses
+	// including this one.
+	Increment(ctx context.Context, keyId string) (int64, error)
+}
+
+// memoryUsageCounter counts the uses in memory.
+type memoryUsageCounter struct {
+	mu   sync.Mutex
+	uses map[string]int64
+}
+
+// Increment records a use of the key.
+func (c *memoryUsageCounter) Increment(ctx context.Context, keyId string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uses[keyId]++
+	return c.uses[keyId], nil
+}
+
+// NewMemoryUsageCounter returns a UsageCounter for a single instance,
+// keeping the counts in memory.
+func NewMemoryUsageCounter() UsageCounter {
+	return &memoryUsageCounter{uses: map[string]int64{}}
+}
+
+// UsageAuditFunc is invoked for every request rejected as the key reached
+// its limit, with the number of uses attempted so far.
+type UsageAuditFunc func(ctx context.Context, keyId string, uses int64)
+
+// limitedUseValidator enforces the maximum number of uses of the keys.
+type limitedUseValidator struct {
+	Validator
+	limit   UsageLimitFunc
+	counter UsageCounter
+	audit   UsageAuditFunc
+}
+
+// Unwrap returns the wrapped validator.
+func (v *limitedUseValidator) Unwrap() Validator {
+	return v.Validator
+}
+
+// Validate validates the request using the wrapped validator, and records
+// a use of the key for validly signed requests, failing with
+// ErrUsageExhausted once the key is used beyond its limit.
+func (v *limitedUseValidator) Validate(r *http.Request, secret string) (bool, error) {
+	ok, err := v.Validator.Validate(r, secret)
+	if !ok {
+		return ok, err
+	}
+	keyId := v.GetKeyId(r)
+	limit, err := v.limit(r.Context(), keyId)
+	if err != nil {
+		return false, err
+	}
+	if limit <= 0 {
+		return true, nil
+	}
+	uses, err := v.counter.Increment(r.Context(), keyId)
+	if err != nil {
+		return false, err
+	}
+	if uses > limit {
+		if v.audit != nil {
+			v.audit(r.Context(), keyId, uses)
+		}
+		return false, ErrUsageExhausted
+	}
+	return true, nil
+}
+
+// NewLimitedUseValidator returns a validator enforcing the maximum number
+// of uses of the API keys, as returned by limit, counted by the counter.
+// Requests rejected as the key is exhausted are reported to audit if not
+// nil.
+func NewLimitedUseValidator(base Validator, limit UsageLimitFunc, counter UsageCounter, audit UsageAuditFunc) Validator {
+	return &limitedUseValidator{
+		Validator: base,
+		limit:     limit,
+		counter:   counter,
+		audit:     audit,
+	}
+}
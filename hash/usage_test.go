@@ -0,0 +1,49 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLimitedUseValidator(t *testing.T) {
+	limits := map[string]int64{"bootstrap": 1}
+	audited := 0
+	validator := NewLimitedUseValidator(NewValidator(60),
+		func(ctx context.Context, keyId string) (int64, error) { return limits[keyId], nil },
+		NewMemoryUsageCounter(),
+		func(ctx context.Context, keyId string, uses int64) { audited++ })
+
+	sign := func(keyId string) bool {
+		req := NewGenerator(keyId, "supersecret").AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+		ok, err := validator.Validate(req, "supersecret")
+		if !ok && !errors.Is(err, ErrUsageExhausted) {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return ok
+	}
+
+	// invalid signatures do not consume the key
+	req := NewGenerator("bootstrap", "othersecret").AddAuthHeaders(httptest.NewRequest("GET", "/resource", nil))
+	if ok, _ := validator.Validate(req, "supersecret"); ok {
+		t.Fatalf("expected invalid signature to fail")
+	}
+	if !sign("bootstrap") {
+		t.Errorf("expected first use of single use key to succeed")
+	}
+	if sign("bootstrap") {
+		t.Errorf("expected second use of single use key to fail")
+	}
+	if audited != 1 {
+		t.Errorf("expected exhausted use to be audited, got %d", audited)
+	}
+	for i := 0; i < 3; i++ {
+		if !sign("unlimited") {
+			t.Errorf("expected key without limit to succeed")
+		}
+	}
+}
@@ -9,9 +9,11 @@ package hash
 import (
 	"crypto/hmac"
 	"encoding/hex"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
+
+	"github.com/go-core-stack/auth/telemetry"
 )
 
 /*
@@ -49,9 +51,10 @@ This file contains:
   - Validate(r *http.Request, secret string) (bool, error)
     Validates the authentication headers on the provided HTTP request.
 
-- NewValidator(validity int64) Validator
+- NewValidator(validity int64, opts ...Option) Validator
 
   - validity: Allowed time window (in seconds) for the request to be valid.
+  - opts:     Optional configuration, e.g. WithAllowedAlgorithms(HMACSHA256).
 
   Returns a Validator instance for validating HTTP requests.
 */
@@ -66,20 +69,53 @@ type Validator interface {
 	GetKeyId(r *http.Request) string
 }
 
+var (
+	// errExpired is returned for requests outside the validity window
+	errExpired = errors.New("expired access")
+
+	// errSignatureMismatch is returned when the HMAC signature does not
+	// match the request
+	errSignatureMismatch = errors.New("invalid hmac signature")
+
+	// errEd25519Mismatch is returned when the Ed25519 signature does not
+	// match the request
+	errEd25519Mismatch = errors.New("invalid ed25519 signature")
+)
+
+// Limits of the authentication headers, rejecting the oversized values
+// before decoding them.
+const (
+	// maxSignatureLength is the length of the longest hex-encoded
+	// signature, of the 64 bytes digests and Ed25519 signatures.
+	maxSignatureLength = 2 * 64
+
+	// maxTimestampLength is the length of the longest timestamp, RFC3339
+	// with nanoseconds and a zone offset taking 35 characters.
+	maxTimestampLength = 64
+
+	// maxNonceLength is the length of the longest nonce.
+	maxNonceLength = 128
+)
+
 // validator is a concrete implementation of the Validator interface.
 // It holds the allowed validity window (in seconds) for request timestamps.
 type validator struct {
-	validity int64 // Allowed time window (in seconds) for request validity
+	validity int64    // Allowed time window (in seconds) for request validity
+	opts     *options // Optional configuration
 }
 
 // Validate checks the HMAC signature, timestamp, and expiration of the HTTP request.
 //
 // Steps performed:
-//  1. Ensures required headers are present: x-signature and x-timestamp.
+//  1. Ensures required headers are present, once, and within their size
+//     limits: x-signature and x-timestamp.
 //  2. Decodes the hex-encoded signature from the x-signature header.
-//  3. Parses the timestamp from the x-timestamp header (RFC3339 format).
+//  3. Parses the timestamp from the x-timestamp header (RFC3339 or epoch seconds).
 //  4. Checks if the request is within the allowed validity window.
-//  5. Recomputes the expected HMAC signature and compares it to the provided signature.
+//  5. Ensures the algorithm in x-signature-alg (default hmac-sha256) is
+//     allowed, and the signature is of the size of its digest.
+//  6. Ensures the version in x-signature-version (default v1) is allowed.
+//  7. Recomputes the expected HMAC signature and compares it to the provided signature.
 //
 // Parameters:
 //   - r:      The HTTP request to validate.
@@ -89,57 +125,200 @@ type validator struct {
 //   - bool:  true if the request is valid, false otherwise.
 //   - error: Reason for validation failure, if any.
 func (v *validator) Validate(r *http.Request, secret string) (bool, error) {
+	return v.observe(r, HMACSHA256, func() (bool, error) {
+		return v.validate(r, secret)
+	})
+}
+
+// validate performs the HMAC validation of the request.
+func (v *validator) validate(r *http.Request, secret string) (bool, error) {
+	sig, timeStr, err := v.checkHeaders(r)
+	if err != nil {
+		return false, err
+	}
+	if v.opts.derived {
+		// the timestamp is already validated by checkHeaders
+		timeStamp, _ := parseTimestamp(timeStr)
+		secret = v.opts.verificationKey(secret, timeStamp)
+	}
+	secret = impersonationKey(secret, v.opts.requestImpersonation(r))
+
+	// Resolve the algorithm used for signing, absence of the header
+	// indicates a client signing with the default algorithm
+	alg := Algorithm(r.Header.Get(v.opts.headers.Algorithm))
+	if alg == "" {
+		alg = DefaultAlgorithm
+	}
+	if !v.opts.allowedAlgorithms[alg] {
+		return false, failure(ReasonAlgorithmNotAllowed, "signature algorithm not allowed: %s", alg)
+	}
+	if len(sig) != signers[alg].size {
+		return false, failure(ReasonMalformedHeader, "invalid signature length for %s", alg)
+	}
+
+	// Resolve the signature scheme version and the signed string
+	canonical, err := v.signedString(r, timeStr)
+	if err != nil {
+		return false, err
+	}
+
+	// Recompute the expected HMAC signature over the signed string
+	var sum [64]byte
+	if !hmac.Equal(sig, signers[alg].AppendSum(sum[:0], secret, canonical)) {
+		return false, errSignatureMismatch
+	}
+
+	// The streamed body is verified against the content signature as it
+	// is read by the handler
+	if v.opts.requestVersion(r) == SignatureStreaming {
+		verifyStreamingBody(r, v.opts.headers.ContentSignature, supportedAlgorithms[alg], secret, sig)
+	}
+
+	return true, nil
+}
+
+// checkHeaders ensures the signature and timestamp headers are present and
+// well formed, and that the request is within the allowed validity window.
+// Returns the decoded signature and the literal timestamp header value
+// which is covered by the signature.
+func (v *validator) checkHeaders(r *http.Request) ([]byte, string, error) {
 	// Ensure headers are present
 	if len(r.Header) == 0 {
-		return false, fmt.Errorf("missing required headers")
+		return nil, "", failure(ReasonMissingHeader, "missing required headers")
+	}
+
+	// Reject repeated authentication headers, of which the validator and
+	// the proxies in front of it could pick different values
+	h := &v.opts.headers
+	for _, name := range [...]string{h.Signature, h.Algorithm, h.Version, h.Timestamp, h.KeyId, h.Nonce} {
+		if len(r.Header.Values(name)) > 1 {
+			return nil, "", failure(ReasonMalformedHeader, "duplicate %s header", name)
+		}
 	}
 
 	// Retrieve the signature from the header
-	sigStr := r.Header.Get(apiKeySignatureHeader)
+	sigStr := r.Header.Get(v.opts.headers.Signature)
 	if sigStr == "" {
-		return false, fmt.Errorf("missing signature header")
+		return nil, "", failure(ReasonMissingHeader, "missing signature header")
+	}
+	if len(sigStr) > maxSignatureLength {
+		return nil, "", failure(ReasonMalformedHeader, "signature header too long")
 	}
 
 	// Decode the hex-encoded signature
 	sig, err := hex.DecodeString(sigStr)
 	if err != nil {
-		return false, fmt.Errorf("invalid signature format")
+		return nil, "", failure(ReasonMalformedHeader, "invalid signature format")
 	}
 
 	// Retrieve the timestamp from the header
-	timeStr := r.Header.Get(apiKeyTimestampHeader)
+	timeStr := r.Header.Get(v.opts.headers.Timestamp)
 	if timeStr == "" {
-		return false, fmt.Errorf("missing timestamp header")
+		return nil, "", failure(ReasonMissingHeader, "missing timestamp header")
+	}
+	if len(timeStr) > maxTimestampLength {
+		return nil, "", failure(ReasonMalformedHeader, "timestamp header too long")
+	}
+	if len(r.Header.Get(h.Nonce)) > maxNonceLength {
+		return nil, "", failure(ReasonMalformedHeader, "nonce header too long")
 	}
 
-	// Parse the timestamp (RFC3339 format)
-	timeStamp, err := time.Parse(time.RFC3339, timeStr)
+	// Parse the timestamp (RFC3339 format or unix epoch seconds), the
+	// signature is computed over the literal header value
+	timeStamp, err := parseTimestamp(timeStr)
 	if err != nil {
-		return false, fmt.Errorf("error parsing timestamp: %s", err)
+		return nil, "", failure(ReasonMalformedHeader, "error parsing timestamp: %s", err)
 	}
 
 	// Check if the request is within the allowed validity window
 	now := time.Now().Unix()
 	if now >= (timeStamp.Unix() + v.validity) {
-		return false, fmt.Errorf("expired access")
+		return nil, "", errExpired
+	}
+
+	return sig, timeStr, nil
+}
+
+// signedString returns the canonical string signed by the client for the
+// signature scheme version carried by the request, failing if the version
+// is not allowed.
+func (v *validator) signedString(r *http.Request, timeStr string) (string, error) {
+	version := v.opts.requestVersion(r)
+	if !v.opts.isVersionAllowed(version) {
+		return "", failure(ReasonVersionNotAllowed, "signature version not allowed: %s", version)
 	}
+	nonce := r.Header.Get(v.opts.headers.Nonce)
+	return v.opts.withOriginalURL(r, func(r *http.Request) (string, error) {
+		if version == SignatureStreaming {
+			return canonicalString(version, r, timeStr, nonce)
+		}
+		var canonical string
+		err := v.opts.withBodyLimit(r, func() (err error) {
+			canonical, err = canonicalString(version, r, timeStr, nonce)
+			return err
+		})
+		return canonical, err
+	})
+}
 
-	// Recompute the expected HMAC signature using the method, path, and timestamp
-	if !hmac.Equal(sig, generateSHA256HMAC(secret, r.Method, r.URL.Path, timeStr)) {
-		return false, fmt.Errorf("invalid hmac signature")
+// observe runs the validation, tracing it and recording its outcome if
+// telemetry is configured, where alg is the algorithm assumed when the
+// request doesn't carry one.
+func (v *validator) observe(r *http.Request, alg Algorithm, validate func() (bool, error)) (bool, error) {
+	if v.opts.tracer == nil && v.opts.meter == nil {
+		return validate()
+	}
+	start := time.Now()
+	ctx := r.Context()
+	span := telemetry.NoopSpan()
+	if v.opts.tracer != nil {
+		ctx, span = v.opts.tracer.Start(ctx, "auth.Validate")
+		defer span.End()
 	}
 
-	return true, nil
+	ok, err := validate()
+
+	outcome := validationOutcome(err)
+	if h := r.Header.Get(v.opts.headers.Algorithm); h != "" {
+		alg = Algorithm(h)
+	}
+	span.SetAttributes(
+		telemetry.String("auth.api_key_id", v.GetKeyId(r)),
+		telemetry.String("auth.signature.algorithm", alg.String()),
+		telemetry.String("auth.validation.result", string(outcome)),
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+	if v.opts.meter != nil {
+		v.opts.meter.RecordValidation(ctx, outcome, time.Since(start))
+	}
+	return ok, err
+}
+
+// validationOutcome classifies the validation error
+func validationOutcome(err error) telemetry.Outcome {
+	switch Reason(err) {
+	case ReasonNone:
+		return telemetry.OutcomeSuccess
+	case ReasonExpired:
+		return telemetry.OutcomeExpired
+	case ReasonSignatureMismatch:
+		return telemetry.OutcomeMismatch
+	}
+	return telemetry.OutcomeInvalid
 }
 
+// GetKeyId returns the API key identifier carried by the request.
 func (v *validator) GetKeyId(r *http.Request) string {
-	return r.Header.Get(apiKeyIdHeader)
+	return r.Header.Get(v.opts.headers.KeyId)
 }
 
 // NewValidator creates a new Validator instance for validating HTTP requests.
 //
 // Parameters:
 //   - validity: Allowed time window (in seconds) for the request to be valid.
+//   - opts:     Optional configuration such as the allowed algorithms or header names.
 //
 // Returns:
 //   - Validator: An instance that can validate authentication headers on HTTP requests.
@@ -148,8 +327,9 @@ func (v *validator) GetKeyId(r *http.Request) string {
 //
 //	validator := hash.NewValidator(60) // 60 seconds validity
 //	ok, err := validator.Validate(req, "supersecret")
-func NewValidator(validity int64) Validator {
+func NewValidator(validity int64, opts ...Option) Validator {
 	return &validator{
 		validity: validity,
+		opts:     newOptions(opts...),
 	}
 }
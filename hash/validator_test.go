@@ -4,9 +4,18 @@
 package hash
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/go-core-stack/auth/telemetry"
 )
 
 // TestGeneratorAndValidator demonstrates signing an HTTP request with Generator
@@ -47,3 +56,190 @@ func TestGeneratorAndValidator(t *testing.T) {
 		t.Fatalf("Expected expired access error, got: %v", err)
 	}
 }
+
+// recordingMeter records the outcomes of the validations.
+type recordingMeter struct {
+	outcomes []telemetry.Outcome
+}
+
+func (m *recordingMeter) RecordValidation(ctx context.Context, outcome telemetry.Outcome, duration time.Duration) {
+	m.outcomes = append(m.outcomes, outcome)
+}
+
+func TestValidatorMeter(t *testing.T) {
+	meter := &recordingMeter{}
+	validator := NewValidator(60, WithMeter(meter))
+
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	NewGenerator("test-key", "supersecret").AddAuthHeaders(req)
+	_, _ = validator.Validate(req, "supersecret")
+	_, _ = validator.Validate(req, "othersecret")
+
+	expired := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	expired.Header.Set(apiKeySignatureHeader, "00")
+	expired.Header.Set(apiKeyTimestampHeader, time.Now().Add(-time.Hour).Format(time.RFC3339))
+	_, _ = validator.Validate(expired, "supersecret")
+
+	_, _ = validator.Validate(httptest.NewRequest("GET", "https://api.example.com/resource", nil), "supersecret")
+
+	expected := []telemetry.Outcome{
+		telemetry.OutcomeSuccess,
+		telemetry.OutcomeMismatch,
+		telemetry.OutcomeExpired,
+		telemetry.OutcomeInvalid,
+	}
+	if !slices.Equal(meter.outcomes, expected) {
+		t.Errorf("expected outcomes %v, got %v", expected, meter.outcomes)
+	}
+}
+
+func TestValidatorEpochTimestamp(t *testing.T) {
+	validator := NewValidator(60)
+
+	// signed by the Generator emitting epoch seconds
+	req := httptest.NewRequest("POST", "https://api.example.com/api/service1/v1/scope/abc/test/test1", nil)
+	NewGenerator("test-key", "mysupersecretcode", WithEpochTimestamp()).AddAuthHeaders(req)
+	if ts := req.Header.Get(apiKeyTimestampHeader); strings.Trim(ts, "0123456789") != "" {
+		t.Fatalf("expected epoch timestamp, got %s", ts)
+	}
+	if ok, err := validator.Validate(req, "mysupersecretcode"); !ok {
+		t.Errorf("expected epoch signed request to be valid: %s", err)
+	}
+
+	// signed by a non Go client over the literal epoch timestamp
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req = httptest.NewRequest("POST", "https://api.example.com/api/service1/v1/scope/abc/test/test1", nil)
+	req.Header.Set(apiKeyTimestampHeader, timestamp)
+	req.Header.Set(apiKeySignatureHeader, GenerateSHA256HMAC("mysupersecretcode", "POST", "/api/service1/v1/scope/abc/test/test1", timestamp))
+	if ok, err := validator.Validate(req, "mysupersecretcode"); !ok {
+		t.Errorf("expected externally signed epoch request to be valid: %s", err)
+	}
+
+	// the test vector timestamp is long expired
+	req.Header.Set(apiKeyTimestampHeader, "1748410688")
+	req.Header.Set(apiKeySignatureHeader, "04a41d00f2f133c8746d11c7d3d5bfc547fc514b583e3798b1df2c9c09204461")
+	if _, err := validator.Validate(req, "mysupersecretcode"); err == nil || err.Error() != "expired access" {
+		t.Errorf("expected expired access for the test vector, got %v", err)
+	}
+}
+
+// stubValidator accepts every request, for the results of the validators
+// outside this package.
+type stubValidator struct{}
+
+func (stubValidator) Validate(r *http.Request, secret string) (bool, error) { return true, nil }
+func (stubValidator) GetKeyId(r *http.Request) string                       { return "stub" }
+
+func TestValidateWithResult(t *testing.T) {
+	validator := NewValidator(60, WithAllowedAlgorithms(HMACSHA256))
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	NewGenerator("test-key", "supersecret").AddAuthHeaders(req)
+
+	res := ValidateWithResult(validator, req, "supersecret")
+	if !res.Valid || res.Reason != ReasonNone || res.Err != nil {
+		t.Fatalf("expected valid result, got %+v", res)
+	}
+	if res.KeyId != "test-key" || res.Algorithm != HMACSHA256 || res.Version != DefaultSignatureVersion {
+		t.Errorf("unexpected signing parameters %+v", res)
+	}
+	if time.Since(res.Timestamp) > time.Minute || res.Latency <= 0 {
+		t.Errorf("unexpected timestamp %s or latency %s", res.Timestamp, res.Latency)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(r *http.Request)
+		reason ReasonCode
+	}{
+		{"mismatch", func(r *http.Request) { r.Header.Set(apiKeySignatureHeader, strings.Repeat("ab", 32)) }, ReasonSignatureMismatch},
+		{"length", func(r *http.Request) { r.Header.Set(apiKeySignatureHeader, "deadbeef") }, ReasonMalformedHeader},
+		{"oversized", func(r *http.Request) { r.Header.Set(apiKeySignatureHeader, strings.Repeat("ab", 1024)) }, ReasonMalformedHeader},
+		{"duplicate", func(r *http.Request) { r.Header.Add(apiKeyIdHeader, "other-key") }, ReasonMalformedHeader},
+		{"missing", func(r *http.Request) { r.Header.Del(apiKeySignatureHeader) }, ReasonMissingHeader},
+		{"malformed", func(r *http.Request) { r.Header.Set(apiKeySignatureHeader, "zz") }, ReasonMalformedHeader},
+		{"timestamp", func(r *http.Request) { r.Header.Set(apiKeyTimestampHeader, "yesterday") }, ReasonMalformedHeader},
+		{"long timestamp", func(r *http.Request) { r.Header.Set(apiKeyTimestampHeader, strings.Repeat("9", 100)) }, ReasonMalformedHeader},
+		{"expired", func(r *http.Request) {
+			r.Header.Set(apiKeyTimestampHeader, time.Now().Add(-time.Hour).Format(time.RFC3339))
+		}, ReasonExpired},
+		{"algorithm", func(r *http.Request) { r.Header.Set(apiKeyAlgorithmHeader, string(HMACSHA512)) }, ReasonAlgorithmNotAllowed},
+		{"version", func(r *http.Request) { r.Header.Set(apiKeyVersionHeader, "v9") }, ReasonVersionNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := req.Clone(req.Context())
+			tt.modify(r)
+			res := ValidateWithResult(validator, r, "supersecret")
+			if res.Valid || res.Reason != tt.reason || res.Err == nil {
+				t.Errorf("expected reason %s, got %+v", tt.reason, res)
+			}
+			if ok, err := validator.Validate(r, "supersecret"); ok || err.Error() != res.Err.Error() {
+				t.Errorf("expected the result to carry the error of Validate, got %v", err)
+			}
+		})
+	}
+
+	res = ValidateWithResult(stubValidator{}, req, "")
+	if !res.Valid || res.KeyId != "stub" || res.Algorithm != "" {
+		t.Errorf("unexpected result of a foreign validator %+v", res)
+	}
+	if Reason(errors.New("unknown")) != ReasonInvalid {
+		t.Errorf("expected unclassified errors to be invalid")
+	}
+}
+
+// FuzzValidate checks that the Validator rejects the malformed headers
+// without panicking, and never accepts a request with altered headers.
+func FuzzValidate(f *testing.F) {
+	signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2)).
+		AddAuthHeaders(httptest.NewRequest("POST", "https://api.example.com/books?limit=10", strings.NewReader("{}")))
+	sig := signed.Header.Get(apiKeySignatureHeader)
+	ts := signed.Header.Get(apiKeyTimestampHeader)
+	f.Add(sig, ts, string(HMACSHA256), string(SignatureV2))
+	f.Add(sig, ts, "", "")
+	f.Add(strings.Repeat("0", 128), "1748410688", string(HMACSHA512), string(SignatureV1))
+	f.Add("zz", "2025-13-45T99:99:99Z", "md5", "v9")
+	f.Add(sig+sig, strings.Repeat("9", 100), string(Ed25519), string(SignatureStreaming))
+
+	validators := []Validator{NewValidator(300), NewEd25519Validator(300)}
+	f.Fuzz(func(t *testing.T, sig, ts, alg, version string) {
+		r := signed.Clone(signed.Context())
+		r.Body = io.NopCloser(strings.NewReader("{}"))
+		r.Header.Set(apiKeySignatureHeader, sig)
+		r.Header.Set(apiKeyTimestampHeader, ts)
+		r.Header.Set(apiKeyAlgorithmHeader, alg)
+		r.Header.Set(apiKeyVersionHeader, version)
+		altered := !strings.EqualFold(sig, signed.Header.Get(apiKeySignatureHeader)) ||
+			ts != signed.Header.Get(apiKeyTimestampHeader) ||
+			(alg != string(HMACSHA256) && alg != "") ||
+			(version != string(SignatureV2))
+		for _, v := range validators {
+			ok, err := v.Validate(r, "supersecret")
+			if ok && altered {
+				t.Errorf("accepted altered headers %q %q %q %q", sig, ts, alg, version)
+			}
+			if !ok && err == nil {
+				t.Errorf("expected an error for the rejected request")
+			}
+		}
+	})
+}
+
+func BenchmarkAddAuthHeaders(b *testing.B) {
+	gen := NewGenerator("bench", "supersecret")
+	b.ReportAllocs()
+	for b.Loop() {
+		gen.AddAuthHeaders(httptest.NewRequest("GET", "/books/42?limit=10", nil))
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	req := NewGenerator("bench", "supersecret").AddAuthHeaders(httptest.NewRequest("GET", "/books/42?limit=10", nil))
+	v := NewValidator(300)
+	b.ReportAllocs()
+	for b.Loop() {
+		if ok, err := v.Validate(req, "supersecret"); !ok {
+			b.Fatalf("validation failed: %s", err)
+		}
+	}
+}
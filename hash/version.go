@@ -0,0 +1,190 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+/*
+This file contains the registry of signature scheme versions. A version
+defines the list of request components that are signed, and is carried in
+the x-signature-version header so that the Validator can verify requests
+signed by clients on different versions simultaneously, enabling gradual
+client migration whenever the signed string format changes.
+
+Built-in versions:
+
+  - v1: method, path, timestamp
+  - v2: method, path, canonical query, hex sha256 of the body, timestamp
+  - v2-streaming: same as v2 with the body signed in a trailer, see stream.go
+
+Requests without the x-signature-version header are treated as v1.
+*/
+
+// SignatureVersion identifies the scheme used to build the signed string.
+type SignatureVersion string
+
+const (
+	// SignatureV1 signs the method, path, and timestamp.
+	SignatureV1 SignatureVersion = "v1"
+
+	// SignatureV2 signs the method, path, canonical query, body hash, and
+	// timestamp.
+	SignatureV2 SignatureVersion = "v2"
+)
+
+// DefaultSignatureVersion is the version used by the Generator when none is
+// configured explicitly, and assumed by the Validator when a request
+// carries no x-signature-version header.
+const DefaultSignatureVersion = SignatureV1
+
+// SignatureScheme returns the ordered list of components to be signed for
+// the request with the given timestamp. A scheme consuming the request body
+// must leave it readable for the subsequent handlers or transports.
+type SignatureScheme func(r *http.Request, timestamp string) ([]string, error)
+
+var (
+	// schemesMu guards the schemes registry
+	schemesMu sync.RWMutex
+
+	// schemes holds the registered signature scheme versions
+	schemes = map[SignatureVersion]SignatureScheme{
+		SignatureV1: signatureSchemeV1,
+		SignatureV2: signatureSchemeV2,
+
+		SignatureStreaming: signatureSchemeStreaming,
+	}
+)
+
+// RegisterSignatureScheme registers a new signature scheme version, it fails
+// if the version is already registered.
+func RegisterSignatureScheme(version SignatureVersion, scheme SignatureScheme) error {
+	if version == "" || scheme == nil {
+		return fmt.Errorf("invalid signature scheme")
+	}
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	if _, ok := schemes[version]; ok {
+		return fmt.Errorf("signature scheme %s already registered", version)
+	}
+	schemes[version] = scheme
+	return nil
+}
+
+// RegisteredSignatureVersions returns all the registered signature scheme
+// versions.
+func RegisteredSignatureVersions() []SignatureVersion {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	versions := make([]SignatureVersion, 0, len(schemes))
+	for v := range schemes {
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+// getSignatureScheme returns the scheme registered for the version.
+func getSignatureScheme(version SignatureVersion) (SignatureScheme, bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	scheme, ok := schemes[version]
+	return scheme, ok
+}
+
+// signatureSchemeV1 signs the method, path, and timestamp.
+func signatureSchemeV1(r *http.Request, timestamp string) ([]string, error) {
+	return []string{r.Method, r.URL.Path, timestamp}, nil
+}
+
+// signatureSchemeV2 signs the method, path, canonical query, body hash, and
+// timestamp.
+func signatureSchemeV2(r *http.Request, timestamp string) ([]string, error) {
+	bodyHash, err := hashBody(r)
+	if err != nil {
+		return nil, err
+	}
+	return []string{r.Method, r.URL.Path, r.URL.Query().Encode(), bodyHash, timestamp}, nil
+}
+
+// hashBody returns the hex encoded sha256 of the request body, see
+// bodySum.
+func hashBody(r *http.Request) (string, error) {
+	sum, err := bodySum(r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// DefaultMaxBodySize is the largest request body read by the Validator to
+// verify its hash unless configured otherwise, see WithMaxBodySize.
+const DefaultMaxBodySize = 10 << 20
+
+// withBodyLimit runs fn, hashing the body of the request, with the body
+// limited to the maximum body size, the body being restored if left
+// unread.
+func (o *options) withBodyLimit(r *http.Request, fn func() error) error {
+	body := r.Body
+	if body == nil || body == http.NoBody {
+		return fn()
+	}
+	limit := o.maxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	limited := http.MaxBytesReader(nil, body, limit)
+	r.Body = limited
+	err := fn()
+	if r.Body == limited {
+		r.Body = body
+	}
+	return err
+}
+
+// bodySum returns the sha256 of the request body. When a client request
+// provides GetBody, e.g. built by http.NewRequestWithContext, the hash is
+// computed over a fresh copy leaving Body and GetBody untouched, so that
+// the body replayed by the transport on redirects and retries hashes the
+// same. Otherwise, and always for server requests, so that the received
+// body is what gets verified, the body is buffered and restored, along with
+// a GetBody replaying it, so that it can be read again.
+func bodySum(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		sum := sha256.Sum256(nil)
+		return sum[:], nil
+	}
+	if r.GetBody != nil && r.RequestURI == "" {
+		if body, err := r.GetBody(); err == nil {
+			sum := sha256.New()
+			_, err = io.Copy(sum, body)
+			_ = body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body: %s", err)
+			}
+			return sum.Sum(nil), nil
+		}
+	}
+	b, err := io.ReadAll(r.Body)
+	_ = r.Body.Close()
+	if tooLarge := (*http.MaxBytesError)(nil); errors.As(err, &tooLarge) {
+		return nil, failure(ReasonBodyTooLarge, "request body larger than %d bytes", tooLarge.Limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %s", err)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
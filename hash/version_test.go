@@ -0,0 +1,154 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignatureVersions(t *testing.T) {
+	validator := NewValidator(60)
+
+	// v1 and v2 clients are accepted simultaneously
+	for _, version := range []SignatureVersion{SignatureV1, SignatureV2} {
+		req := httptest.NewRequest("POST", "https://api.example.com/resource?b=2&a=1", strings.NewReader(`{"name":"foo"}`))
+		signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(version)).AddAuthHeaders(req)
+		if got := signed.Header.Get("x-signature-version"); got != string(version) {
+			t.Errorf("expected version header %s, got %s", version, got)
+		}
+		if ok, err := validator.Validate(signed, "supersecret"); !ok {
+			t.Fatalf("validation failed for %s: %v", version, err)
+		}
+		// body must remain readable after signing and validation
+		b, _ := io.ReadAll(signed.Body)
+		if string(b) != `{"name":"foo"}` {
+			t.Errorf("expected body to be preserved for %s, got %q", version, b)
+		}
+	}
+
+	// v2 covers the body and the query
+	req := httptest.NewRequest("POST", "https://api.example.com/resource?a=1", strings.NewReader("original"))
+	signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2)).AddAuthHeaders(req)
+	signed.Body = io.NopCloser(strings.NewReader("tampered"))
+	if ok, _ := validator.Validate(signed, "supersecret"); ok {
+		t.Errorf("expected validation to fail for tampered body")
+	}
+	req = httptest.NewRequest("POST", "https://api.example.com/resource?a=1", nil)
+	signed = NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2)).AddAuthHeaders(req)
+	signed.URL.RawQuery = "a=2"
+	if ok, _ := validator.Validate(signed, "supersecret"); ok {
+		t.Errorf("expected validation to fail for tampered query")
+	}
+
+	// downgrade is prevented by restricting the allowed versions
+	req = httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed = NewGenerator("test-key", "supersecret").AddAuthHeaders(req)
+	if ok, _ := NewValidator(60, WithAllowedVersions(SignatureV2)).Validate(signed, "supersecret"); ok {
+		t.Errorf("expected validation to fail for version outside allowlist")
+	}
+	signed.Header.Set("x-signature-version", "v9")
+	if ok, _ := validator.Validate(signed, "supersecret"); ok {
+		t.Errorf("expected validation to fail for unknown version")
+	}
+}
+
+func TestSignGetBody(t *testing.T) {
+	reads := 0
+	req, _ := http.NewRequestWithContext(t.Context(), "POST", "https://api.example.com/resource", strings.NewReader("payload"))
+	getBody := req.GetBody
+	req.GetBody = func() (io.ReadCloser, error) {
+		reads++
+		return getBody()
+	}
+	body := req.Body
+	signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(SignatureV2)).AddAuthHeaders(req)
+	if signed.Body != body || reads != 1 {
+		t.Fatalf("expected body to be hashed using GetBody, leaving Body as is")
+	}
+	if signed.Context() != t.Context() {
+		t.Errorf("expected context to be preserved")
+	}
+
+	// a replayed body, as sent on redirects and retries, hashes the same
+	replay := signed.Clone(signed.Context())
+	replay.Body, _ = signed.GetBody()
+	replay.RequestURI = replay.URL.RequestURI()
+	if ok, err := NewValidator(60).Validate(replay, "supersecret"); !ok {
+		t.Errorf("expected replayed body to be valid: %s", err)
+	}
+
+	// a server request is validated against the received body, ignoring
+	// GetBody
+	replay = signed.Clone(signed.Context())
+	replay.Body = io.NopCloser(strings.NewReader("tampered"))
+	replay.RequestURI = replay.URL.RequestURI()
+	if ok, _ := NewValidator(60).Validate(replay, "supersecret"); ok {
+		t.Errorf("expected validation to fail for tampered body")
+	}
+}
+
+func TestRegisterSignatureScheme(t *testing.T) {
+	custom := SignatureVersion("test-host")
+	err := RegisterSignatureScheme(custom, func(r *http.Request, timestamp string) ([]string, error) {
+		return []string{r.Method, r.Host, r.URL.Path, timestamp}, nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register scheme: %s", err)
+	}
+	if err := RegisterSignatureScheme(custom, signatureSchemeV1); err == nil {
+		t.Errorf("expected duplicate registration to fail")
+	}
+
+	req := httptest.NewRequest("GET", "https://api.example.com/resource", nil)
+	signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(custom)).AddAuthHeaders(req)
+	if ok, err := NewValidator(60).Validate(signed, "supersecret"); !ok {
+		t.Fatalf("validation failed for custom scheme: %v", err)
+	}
+	signed.Host = "evil.example.com"
+	if ok, _ := NewValidator(60).Validate(signed, "supersecret"); ok {
+		t.Errorf("expected validation to fail for tampered host")
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	tests := []struct {
+		name    string
+		version SignatureVersion
+		body    string
+		reason  ReasonCode
+	}{
+		{"v2 within the limit", SignatureV2, "12345678", ReasonNone},
+		{"v2 over the limit", SignatureV2, "123456789", ReasonBodyTooLarge},
+		{"v1 not hashing the body", SignatureV1, "123456789", ReasonNone},
+	}
+	validator := NewValidator(60, WithMaxBodySize(8))
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "https://api.example.com/resource", strings.NewReader(tc.body))
+			signed := NewGenerator("test-key", "supersecret", WithSignatureVersion(tc.version)).AddAuthHeaders(req)
+			res := ValidateWithResult(validator, signed, "supersecret")
+			if res.Reason != tc.reason {
+				t.Fatalf("expected reason %q, got %q: %v", tc.reason, res.Reason, res.Err)
+			}
+			if tc.reason != ReasonNone {
+				return
+			}
+			// the handler reads the whole body
+			if b, _ := io.ReadAll(signed.Body); string(b) != tc.body {
+				t.Errorf("expected body to be preserved, got %q", b)
+			}
+		})
+	}
+
+	// the message signatures hash the body in the same way
+	req := httptest.NewRequest("POST", "https://api.example.com/resource", strings.NewReader("123456789"))
+	signed := NewMessageSignatureGenerator("test-key", "supersecret", 0).AddAuthHeaders(req)
+	if res := ValidateWithResult(NewMessageSignatureValidator(60, WithMaxBodySize(8)), signed, "supersecret"); res.Reason != ReasonBodyTooLarge {
+		t.Errorf("expected the content digest of the large body to be rejected, got %q: %v", res.Reason, res.Err)
+	}
+}
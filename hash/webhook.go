@@ -0,0 +1,123 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/*
+This file provides helpers for signing outgoing webhook payloads and
+verifying inbound ones, following the widely used timestamped scheme:
+
+    x-webhook-signature: t=1748410688,v1=<hex>
+
+where v1 is HMAC-SHA256(secret, timestamp + "." + raw body). Multiple v1
+entries may be present in a header, e.g. while the sender rotates secrets,
+and the payload is considered valid if any of them matches.
+
+# Usage
+
+    // sender
+    req.Header.Set(hash.WebhookSignatureHeader, hash.SignWebhook(secret, body))
+
+    // receiver, body must be the raw request body
+    err := hash.VerifyWebhook(secret, r.Header.Get(hash.WebhookSignatureHeader), body, 5*time.Minute)
+*/
+
+// WebhookSignatureHeader is the header conventionally used to carry the
+// webhook signature.
+const WebhookSignatureHeader = "x-webhook-signature"
+
+// webhook signature scheme identifiers
+const (
+	webhookTimestampKey = "t"
+	webhookSchemeV1     = "v1"
+)
+
+// webhookSignature computes the v1 signature for the timestamp and payload.
+func webhookSignature(secret string, timestamp string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// SignWebhook returns the signature header value for the payload, signed
+// with the secret at the current time.
+func SignWebhook(secret string, payload []byte) string {
+	return signWebhookAt(secret, payload, time.Now())
+}
+
+// signWebhookAt returns the signature header value for the given time.
+func signWebhookAt(secret string, payload []byte, now time.Time) string {
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := hex.EncodeToString(webhookSignature(secret, ts, payload))
+	return webhookTimestampKey + "=" + ts + "," + webhookSchemeV1 + "=" + sig
+}
+
+// VerifyWebhook verifies the signature header value against the raw payload
+// using the secret. The signature must be created within tolerance of the
+// current time, a zero or negative tolerance disables the timestamp check.
+// Returns nil if any of the v1 signatures in the header matches.
+func VerifyWebhook(secret, header string, payload []byte, tolerance time.Duration) error {
+	if header == "" {
+		return fmt.Errorf("missing webhook signature")
+	}
+
+	var ts string
+	var sigs [][]byte
+	for _, part := range strings.Split(header, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return fmt.Errorf("invalid webhook signature format")
+		}
+		switch k {
+		case webhookTimestampKey:
+			ts = v
+		case webhookSchemeV1:
+			sig, err := hex.DecodeString(v)
+			if err != nil {
+				return fmt.Errorf("invalid signature format")
+			}
+			sigs = append(sigs, sig)
+		default:
+			// unknown schemes are ignored to allow introducing new
+			// versions without breaking existing receivers
+		}
+	}
+
+	if ts == "" {
+		return fmt.Errorf("missing webhook timestamp")
+	}
+	if len(sigs) == 0 {
+		return fmt.Errorf("missing %s webhook signature", webhookSchemeV1)
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("error parsing timestamp: %s", err)
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(unix, 0))
+		if age > tolerance || age < -tolerance {
+			return fmt.Errorf("webhook timestamp outside tolerance")
+		}
+	}
+
+	expected := webhookSignature(secret, ts, payload)
+	for _, sig := range sigs {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid hmac signature")
+}
@@ -0,0 +1,53 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package hash
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_WebhookSignatureVector(t *testing.T) {
+	header := signWebhookAt("whsec", []byte(`{"id":1}`), time.Unix(1748410688, 0))
+	expected := "t=1748410688,v1=" + GenerateSHA256HMAC("whsec", `1748410688.{"id":1}`)
+	if header != expected {
+		t.Errorf("expected %s, got %s", expected, header)
+	}
+}
+
+func TestSignAndVerifyWebhook(t *testing.T) {
+	payload := []byte(`{"event":"created"}`)
+	header := SignWebhook("whsec", payload)
+
+	if err := VerifyWebhook("whsec", header, payload, time.Minute); err != nil {
+		t.Fatalf("verification failed: %s", err)
+	}
+	if err := VerifyWebhook("other", header, payload, time.Minute); err == nil {
+		t.Errorf("expected verification to fail with a different secret")
+	}
+	if err := VerifyWebhook("whsec", header, []byte(`{"event":"deleted"}`), time.Minute); err == nil {
+		t.Errorf("expected verification to fail for a tampered payload")
+	}
+
+	// rotation, any matching signature is accepted
+	old := signWebhookAt("old-secret", payload, time.Now())
+	rotated := header + "," + old[len("t=1748410688,"):]
+	if err := VerifyWebhook("whsec", rotated, payload, time.Minute); err != nil {
+		t.Errorf("expected verification with multiple signatures to pass: %s", err)
+	}
+
+	stale := signWebhookAt("whsec", payload, time.Now().Add(-time.Hour))
+	if err := VerifyWebhook("whsec", stale, payload, time.Minute); err == nil {
+		t.Errorf("expected verification to fail for a stale signature")
+	}
+	if err := VerifyWebhook("whsec", stale, payload, 0); err != nil {
+		t.Errorf("expected verification to pass without tolerance: %s", err)
+	}
+
+	for _, bad := range []string{"", "garbage", "t=abc,v1=00", "t=1748410688", "v1=zz"} {
+		if err := VerifyWebhook("whsec", bad, payload, 0); err == nil {
+			t.Errorf("expected verification to fail for header %q", bad)
+		}
+	}
+}
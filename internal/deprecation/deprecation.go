@@ -0,0 +1,120 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package deprecation
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+Package deprecation holds the deprecated API of the module, kept working as
+migration shims until the v2 module removes them, and finds their uses in
+Go source files for the auth-deprecations report.
+
+Every entry of List corresponds to an identifier documented with a
+"Deprecated:" paragraph, which is verified by the tests of this package.
+*/
+
+// ModulePath is the import path of the module.
+const ModulePath = "github.com/go-core-stack/auth"
+
+// Entry is a deprecated identifier along with its replacement.
+type Entry struct {
+	// package path relative to the module, e.g. "route"
+	Package string
+
+	// name of the deprecated identifier
+	Name string
+
+	// replacement to migrate to
+	Replacement string
+}
+
+// ImportPath returns the import path of the package of the entry.
+func (e Entry) ImportPath() string {
+	return ModulePath + "/" + e.Package
+}
+
+// List holds the deprecated identifiers of the module.
+var List = []Entry{
+	{Package: "client", Name: "NewClient", Replacement: "client.New(endpoint, client.WithCredentials(id, secret))"},
+	{Package: "client", Name: "NewClientWithProvider", Replacement: "client.New(endpoint, client.WithCredentialsProvider(creds))"},
+	{Package: "route", Name: "GetRouteTable", Replacement: "the table returned by route.NewRouteTable"},
+	{Package: "route", Name: "LocateRouteTable", Replacement: "route.NewRouteTable"},
+	{Package: "route", Name: "GetRouteProviderTable", Replacement: "the table returned by route.NewRouteProviderTable"},
+	{Package: "route", Name: "LocateRouteProviderTable", Replacement: "route.NewRouteProviderTable"},
+	{Package: "route", Name: "SyncRoutes", Replacement: "(*route.RouteTable).SyncRoutes"},
+}
+
+// Finding is a use of a deprecated identifier.
+type Finding struct {
+	Pos   token.Position
+	Entry Entry
+}
+
+// String formats the finding as "file:line:col: message".
+func (f Finding) String() string {
+	return f.Pos.String() + ": " + f.Entry.Package + "." + f.Entry.Name +
+		" is deprecated, use " + f.Entry.Replacement
+}
+
+// Scan returns the uses of the deprecated identifiers in the file, sorted
+// by position.
+func Scan(fset *token.FileSet, file *ast.File) []Finding {
+	// local names of the imported packages holding deprecated identifiers
+	byName := map[string]map[string]Entry{}
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || !strings.HasPrefix(path, ModulePath+"/") {
+			continue
+		}
+		local := path[strings.LastIndex(path, "/")+1:]
+		if imp.Name != nil {
+			local = imp.Name.Name
+		}
+		if local == "_" || local == "." {
+			continue
+		}
+		for _, e := range List {
+			if e.ImportPath() != path {
+				continue
+			}
+			if byName[local] == nil {
+				byName[local] = map[string]Entry{}
+			}
+			byName[local][e.Name] = e
+		}
+	}
+	if len(byName) == 0 {
+		return nil
+	}
+
+	findings := []Finding{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pkg, ok := sel.X.(*ast.Ident)
+		if !ok || pkg.Obj != nil {
+			// not a package qualifier, e.g. a local variable
+			return true
+		}
+		if e, ok := byName[pkg.Name][sel.Sel.Name]; ok {
+			findings = append(findings, Finding{Pos: fset.Position(sel.Pos()), Entry: e})
+		}
+		return true
+	})
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Pos.Line != findings[j].Pos.Line {
+			return findings[i].Pos.Line < findings[j].Pos.Line
+		}
+		return findings[i].Pos.Column < findings[j].Pos.Column
+	})
+	return findings
+}
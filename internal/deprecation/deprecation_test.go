@@ -0,0 +1,102 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package deprecation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// deprecatedFuncs returns the functions of the package, relative to the
+// module root, documented as deprecated.
+func deprecatedFuncs(t *testing.T, pkg string) map[string]bool {
+	dir := filepath.Join("..", "..", pkg)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read package %s: %s", pkg, err)
+	}
+	fset := token.NewFileSet()
+	found := map[string]bool{}
+	for _, e := range entries {
+		if !strings.HasSuffix(e.Name(), ".go") || strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, parser.ParseComments)
+		if err != nil {
+			t.Fatalf("failed to parse %s: %s", e.Name(), err)
+		}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Doc == nil {
+				continue
+			}
+			if strings.Contains(fn.Doc.Text(), "\nDeprecated: ") {
+				found[fn.Name.Name] = true
+			}
+		}
+	}
+	return found
+}
+
+func TestListMatchesSource(t *testing.T) {
+	listed := map[string]map[string]bool{}
+	for _, e := range List {
+		if listed[e.Package] == nil {
+			listed[e.Package] = map[string]bool{}
+		}
+		listed[e.Package][e.Name] = true
+	}
+	for pkg, names := range listed {
+		found := deprecatedFuncs(t, pkg)
+		for name := range names {
+			if !found[name] {
+				t.Errorf("%s.%s is listed but not documented as deprecated", pkg, name)
+			}
+		}
+		for name := range found {
+			if !names[name] {
+				t.Errorf("%s.%s is documented as deprecated but not listed", pkg, name)
+			}
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	src := `package main
+
+import (
+	authclient "github.com/go-core-stack/auth/client"
+	"github.com/go-core-stack/auth/route"
+)
+
+func main() {
+	tbl, _ := route.LocateRouteTable(nil)
+	_, _ = authclient.NewClient("https://api.example.com", "id", "secret", false)
+	_, _ = route.NewRouteTable(nil)
+	client := struct{ NewClient func() }{}
+	client.NewClient()
+	_ = tbl
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, 0)
+	if err != nil {
+		t.Fatalf("failed to parse: %s", err)
+	}
+	findings := Scan(fset, f)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %v", findings)
+	}
+	if findings[0].Entry.Name != "LocateRouteTable" || findings[0].Pos.Line != 9 {
+		t.Errorf("unexpected finding %s", findings[0])
+	}
+	if findings[1].Entry.Name != "NewClient" || !strings.Contains(findings[1].String(), "use client.New") {
+		t.Errorf("unexpected finding %s", findings[1])
+	}
+}
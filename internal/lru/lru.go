@@ -0,0 +1,122 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Package lru provides a size-bounded, concurrency safe, least recently used
+cache backing the in-memory stores of the auth packages, so that none of
+them can grow without bound, e.g. during key-scanning attacks.
+*/
+
+// entry is the element stored in the eviction list.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// Cache is a size-bounded least recently used cache. The zero value is not
+// usable, create instances with New.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	items   map[K]*list.Element
+	order   *list.List // front is the most recently used
+	evicted atomic.Uint64
+	onEvict func(K, V)
+}
+
+// New creates a cache holding at most size entries, a size of zero or less
+// results in a cache holding a single entry.
+func New[K comparable, V any](size int) *Cache[K, V] {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache[K, V]{
+		size:  size,
+		items: map[K]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// OnEvict registers a callback invoked, with the cache lock held, for every
+// entry evicted due to the size bound. Must be called before the cache is
+// used.
+func (c *Cache[K, V]) OnEvict(fn func(K, V)) {
+	c.onEvict = fn
+}
+
+// Get returns the value for the key marking it as recently used.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*entry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Add inserts or updates the value for the key, evicting the least recently
+// used entry if the cache is full. Returns true if an entry was evicted.
+func (c *Cache[K, V]) Add(key K, value V) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		e.Value.(*entry[K, V]).value = value
+		return false
+	}
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value})
+	if c.order.Len() <= c.size {
+		return false
+	}
+	oldest := c.order.Back()
+	c.order.Remove(oldest)
+	old := oldest.Value.(*entry[K, V])
+	delete(c.items, old.key)
+	c.evicted.Add(1)
+	if c.onEvict != nil {
+		c.onEvict(old.key, old.value)
+	}
+	return true
+}
+
+// Remove deletes the key from the cache, returning true if it was present.
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.Remove(e)
+		delete(c.items, key)
+		return true
+	}
+	return false
+}
+
+// Len returns the number of entries in the cache.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Evictions returns the number of entries evicted due to the size bound.
+func (c *Cache[K, V]) Evictions() uint64 {
+	return c.evicted.Load()
+}
+
+// Purge removes all the entries from the cache.
+func (c *Cache[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = map[K]*list.Element{}
+	c.order.Init()
+}
@@ -0,0 +1,49 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package lru
+
+import (
+	"testing"
+)
+
+func TestCacheEviction(t *testing.T) {
+	c := New[string, int](2)
+	var evicted []string
+	c.OnEvict(func(k string, v int) { evicted = append(evicted, k) })
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("expected a to be present")
+	}
+	// b is now the least recently used entry
+	if !c.Add("c", 3) {
+		t.Errorf("expected an eviction when exceeding the size")
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Errorf("expected a to be retained, got %d, %v", v, ok)
+	}
+	if c.Len() != 2 || c.Evictions() != 1 || len(evicted) != 1 || evicted[0] != "b" {
+		t.Errorf("unexpected state len %d evictions %d evicted %v", c.Len(), c.Evictions(), evicted)
+	}
+
+	// updating an existing key never evicts
+	if c.Add("a", 10) {
+		t.Errorf("expected no eviction on update")
+	}
+	if v, _ := c.Get("a"); v != 10 {
+		t.Errorf("expected updated value, got %d", v)
+	}
+
+	if !c.Remove("a") || c.Remove("a") {
+		t.Errorf("expected remove to report presence correctly")
+	}
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("expected empty cache after purge")
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package ipaddr
+
+import (
+	"context"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+/*
+This file resolves the real client of the requests received behind load
+balancers and reverse proxies. The forwarding headers are honored only for
+the requests received from a trusted proxy, walking the chain of proxies
+from the right and stopping at the first address outside the trusted
+prefixes, so that the clients cannot spoof their address. The headers are
+used in the order:
+
+  - Forwarded, RFC 7239, e.g. "for=192.0.2.60;proto=https;host=example.com"
+  - X-Forwarded-For, along with X-Forwarded-Proto and X-Forwarded-Host
+  - X-Real-IP, holding the single address set by the proxy
+
+The Resolver middleware attaches the resolved client to the context of the
+request, where RequestIP and Allowlist pick it up, so that the middlewares,
+rate limiters and audit records behind it agree on the caller.
+
+# Usage
+
+    proxies, _ := ipaddr.ParseAllowlist("10.0.0.0/8")
+    resolver := ipaddr.NewResolver(proxies)
+    handler = resolver.Middleware(limiter.Middleware(throttle.ByKeyId)(handler))
+*/
+
+// Forwarding headers honored for the requests of trusted proxies.
+const (
+	ForwardedHeader      = "Forwarded"
+	ForwardedForHeader   = "X-Forwarded-For"
+	ForwardedProtoHeader = "X-Forwarded-Proto"
+	ForwardedHostHeader  = "X-Forwarded-Host"
+	RealIPHeader         = "X-Real-IP"
+)
+
+// Client is the client of a request as resolved by the Resolver.
+type Client struct {
+	// normalized address of the client
+	Addr netip.Addr
+
+	// scheme and host requested by the client, e.g. "https" and
+	// "api.example.com"
+	Scheme string
+	Host   string
+}
+
+// Resolver resolves the client of the requests behind the trusted proxies.
+// A nil Resolver trusts no proxy.
+type Resolver struct {
+	trusted Allowlist
+}
+
+// NewResolver creates the resolver honoring the forwarding headers of the
+// requests received from the trusted prefixes.
+func NewResolver(trusted Allowlist) *Resolver {
+	return &Resolver{trusted: trusted}
+}
+
+// forwardedElement is a parsed element of the Forwarded header.
+type forwardedElement struct {
+	addr  string
+	proto string
+	host  string
+}
+
+// parseForwarded parses the elements of the Forwarded header values.
+func parseForwarded(values []string) []forwardedElement {
+	elements := []forwardedElement{}
+	for _, v := range values {
+		for _, e := range strings.Split(v, ",") {
+			element := forwardedElement{}
+			for _, pair := range strings.Split(e, ";") {
+				name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					continue
+				}
+				value = strings.Trim(value, `"`)
+				switch strings.ToLower(name) {
+				case "for":
+					element.addr = value
+				case "proto":
+					element.proto = strings.ToLower(value)
+				case "host":
+					element.host = value
+				}
+			}
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// splitValues splits the comma separated values of the header.
+func splitValues(r *http.Request, header string) []string {
+	values := []string{}
+	for _, v := range r.Header.Values(header) {
+		for _, e := range strings.Split(v, ",") {
+			values = append(values, strings.TrimSpace(e))
+		}
+	}
+	return values
+}
+
+// walk returns the index of the client among the hops, the right-most hop
+// outside the trusted prefixes, or the last parsed hop if a malformed or
+// obfuscated hop is reached first, -1 if none is parsed.
+func (res *Resolver) walk(hops []string) (int, netip.Addr) {
+	index, addr := -1, netip.Addr{}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := ParseAddr(hops[i])
+		if err != nil {
+			// the hops beyond a malformed one are not reliable
+			break
+		}
+		index, addr = i, hop
+		if !res.trusted.Contains(hop) {
+			break
+		}
+	}
+	return index, addr
+}
+
+// Resolve returns the client of the request, as per the forwarding headers
+// if the request is received from a trusted proxy, otherwise as per the
+// remote address, TLS state and host of the request.
+func (res *Resolver) Resolve(r *http.Request) *Client {
+	client := &Client{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		client.Scheme = "https"
+	}
+	addr, ok := FromRequest(r)
+	if ok {
+		client.Addr = addr
+	}
+	if !ok || res == nil || !res.trusted.Contains(addr) {
+		return client
+	}
+
+	if elements := parseForwarded(r.Header.Values(ForwardedHeader)); len(elements) != 0 {
+		hops := make([]string, len(elements))
+		for i, e := range elements {
+			hops[i] = e.addr
+		}
+		if i, hop := res.walk(hops); i >= 0 {
+			client.Addr = hop
+			if elements[i].proto != "" {
+				client.Scheme = elements[i].proto
+			}
+			if elements[i].host != "" {
+				client.Host = elements[i].host
+			}
+		}
+		return client
+	}
+	if hops := splitValues(r, ForwardedForHeader); len(hops) != 0 {
+		if i, hop := res.walk(hops); i >= 0 {
+			client.Addr = hop
+		}
+	} else if hop, err := ParseAddr(r.Header.Get(RealIPHeader)); err == nil {
+		client.Addr = hop
+	}
+	// the left-most values are the ones of the client facing proxy
+	if proto := splitValues(r, ForwardedProtoHeader); len(proto) != 0 && proto[0] != "" {
+		client.Scheme = strings.ToLower(proto[0])
+	}
+	if host := splitValues(r, ForwardedHostHeader); len(host) != 0 && host[0] != "" {
+		client.Host = host[0]
+	}
+	return client
+}
+
+// ClientAddr returns the normalized address of the client of the request,
+// see Resolve.
+func (res *Resolver) ClientAddr(r *http.Request) (netip.Addr, bool) {
+	if client, ok := ClientFromContext(r.Context()); ok {
+		return client.Addr, client.Addr.IsValid()
+	}
+	client := res.Resolve(r)
+	return client.Addr, client.Addr.IsValid()
+}
+
+// Middleware attaches the resolved client to the context of the request.
+func (res *Resolver) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ContextWithClient(r.Context(), res.Resolve(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClientAddr returns the normalized address of the client behind the
+// trusted proxies, see Resolver.
+func ClientAddr(r *http.Request, trusted Allowlist) (netip.Addr, bool) {
+	return NewResolver(trusted).ClientAddr(r)
+}
+
+// struct identifier for the context
+type clientInfo struct{}
+
+// ContextWithClient returns a new context with the resolved client
+// attached.
+func ContextWithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientInfo{}, c)
+}
+
+// ClientFromContext returns the client attached to the context by the
+// Resolver middleware.
+func ClientFromContext(ctx context.Context) (*Client, bool) {
+	c, ok := ctx.Value(clientInfo{}).(*Client)
+	return c, ok
+}
@@ -0,0 +1,146 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package ipaddr
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+/*
+Package ipaddr parses and matches the client IP addresses of dual-stack
+deployments, shared by the allowlists, rate limiting keys and audit records.
+
+Addresses are normalized so that the same client always yields the same
+address, whichever way it reached the server: the port and brackets of a
+remote address are removed, IPv4-mapped IPv6 addresses (::ffff:10.0.0.1)
+are unmapped to IPv4 and IPv6 zone IDs (fe80::1%eth0) are dropped. Matching
+is done on the parsed prefixes, never on the strings, which is wrong for
+IPv6, where the same address has many textual forms.
+
+# Usage
+
+    allow, err := ipaddr.ParseAllowlist("10.0.0.0/8", "2001:db8::/32", "192.0.2.7")
+    handler = allow.Middleware(handler)
+
+    // clients behind the load balancers, see forwarded.go
+    handler = ipaddr.NewResolver(proxies).Middleware(handler)
+*/
+
+// ParseAddr parses and normalizes an IP address, optionally with a port
+// and brackets, e.g. "192.0.2.1", "[2001:db8::1]:443" or "fe80::1%eth0".
+func ParseAddr(s string) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	if ap, err := netip.ParseAddrPort(s); err == nil {
+		return Normalize(ap.Addr()), nil
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid ip address %q: %w", s, err)
+	}
+	return Normalize(addr), nil
+}
+
+// Normalize unmaps IPv4-mapped IPv6 addresses and drops the IPv6 zone.
+func Normalize(addr netip.Addr) netip.Addr {
+	return addr.Unmap().WithZone("")
+}
+
+// FromRequest returns the normalized address of the client the request is
+// received from, as per its remote address.
+func FromRequest(r *http.Request) (netip.Addr, bool) {
+	addr, err := ParseAddr(r.RemoteAddr)
+	return addr, err == nil
+}
+
+// RequestIP returns the normalized address of the client of the request
+// as a string, the one resolved by the Resolver middleware if any, or else
+// the one the request is received from, or the remote address as is if it
+// is not an IP address, e.g. for unix sockets.
+func RequestIP(r *http.Request) string {
+	if addr, ok := (*Resolver)(nil).ClientAddr(r); ok {
+		return addr.String()
+	}
+	return r.RemoteAddr
+}
+
+// ParsePrefix parses a CIDR prefix, or a single address as the prefix
+// holding only itself, normalized to match the normalized addresses, e.g.
+// "::ffff:10.0.0.0/104" is parsed as "10.0.0.0/8".
+func ParsePrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "/") {
+		addr, err := ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid ip prefix %q: %w", s, err)
+	}
+	if p.Addr().Is4In6() && p.Bits() < 96 {
+		return netip.Prefix{}, fmt.Errorf("invalid ip prefix %q: ipv4-mapped prefix shorter than /96", s)
+	}
+	return NormalizePrefix(p), nil
+}
+
+// NormalizePrefix masks the prefix and unmaps IPv4-mapped IPv6 prefixes of
+// at least 96 bits, so that they contain the normalized addresses.
+func NormalizePrefix(p netip.Prefix) netip.Prefix {
+	addr, bits := p.Addr(), p.Bits()
+	if addr.Is4In6() && bits >= 96 {
+		addr, bits = addr.Unmap(), bits-96
+	}
+	return netip.PrefixFrom(addr, bits).Masked()
+}
+
+// Allowlist is a list of prefixes the clients are allowed from.
+type Allowlist []netip.Prefix
+
+// ParseAllowlist parses the entries, CIDR prefixes or single addresses.
+func ParseAllowlist(entries ...string) (Allowlist, error) {
+	list := Allowlist{}
+	for _, e := range entries {
+		p, err := ParsePrefix(e)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// Contains reports whether the address is within one of the prefixes.
+func (l Allowlist) Contains(addr netip.Addr) bool {
+	addr = Normalize(addr)
+	for _, p := range l {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsRequest reports whether the client of the request, as per
+// RequestIP, is allowed.
+func (l Allowlist) AllowsRequest(r *http.Request) bool {
+	addr, ok := (*Resolver)(nil).ClientAddr(r)
+	return ok && l.Contains(addr)
+}
+
+// Middleware returns a handler rejecting with 403 the requests from clients
+// outside the allowlist, an empty allowlist rejecting all the requests.
+func (l Allowlist) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !l.AllowsRequest(r) {
+			http.Error(w, "client address not allowed", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,185 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package ipaddr
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestParseAddr(t *testing.T) {
+	cases := map[string]string{
+		"192.0.2.1":            "192.0.2.1",
+		"192.0.2.1:8080":       "192.0.2.1",
+		"[2001:db8::1]:443":    "2001:db8::1",
+		"[2001:db8::1]":        "2001:db8::1",
+		"2001:DB8:0:0::1":      "2001:db8::1",
+		"::ffff:10.0.0.1":      "10.0.0.1",
+		"[::ffff:10.0.0.1]:80": "10.0.0.1",
+		"fe80::1%eth0":         "fe80::1",
+		"[fe80::1%eth0]:8443":  "fe80::1",
+	}
+	for in, want := range cases {
+		addr, err := ParseAddr(in)
+		if err != nil {
+			t.Errorf("ParseAddr(%q) failed: %s", in, err)
+			continue
+		}
+		if addr.String() != want {
+			t.Errorf("ParseAddr(%q) = %s, want %s", in, addr, want)
+		}
+	}
+	for _, in := range []string{"", "host:80", "10.0.0.256"} {
+		if _, err := ParseAddr(in); err == nil {
+			t.Errorf("ParseAddr(%q) expected to fail", in)
+		}
+	}
+}
+
+func TestParsePrefix(t *testing.T) {
+	cases := map[string]string{
+		"10.0.0.0/8":          "10.0.0.0/8",
+		"10.1.2.3/8":          "10.0.0.0/8",
+		"192.0.2.7":           "192.0.2.7/32",
+		"2001:db8::1":         "2001:db8::1/128",
+		"2001:db8::/32":       "2001:db8::/32",
+		"::ffff:10.0.0.0/104": "10.0.0.0/8",
+	}
+	for in, want := range cases {
+		p, err := ParsePrefix(in)
+		if err != nil {
+			t.Errorf("ParsePrefix(%q) failed: %s", in, err)
+			continue
+		}
+		if p.String() != want {
+			t.Errorf("ParsePrefix(%q) = %s, want %s", in, p, want)
+		}
+	}
+	if _, err := ParsePrefix("::ffff:0.0.0.0/64"); err == nil {
+		t.Errorf("expected short ipv4-mapped prefix to fail")
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	allow, err := ParseAllowlist("10.0.0.0/8", "2001:db8::/32")
+	if err != nil {
+		t.Fatalf("failed to parse allowlist: %s", err)
+	}
+	cases := map[string]bool{
+		"10.2.3.4":        true,
+		"::ffff:10.2.3.4": true,
+		"2001:db8:1::5":   true,
+		"2001:db80::5":    false,
+		"192.0.2.1":       false,
+		"fe80::1%eth0":    false,
+	}
+	for in, want := range cases {
+		if got := allow.Contains(netip.MustParseAddr(in)); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", in, got, want)
+		}
+	}
+
+	h := allow.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for remote, status := range map[string]int{
+		"[::ffff:10.0.0.1]:1234": http.StatusOK,
+		"[2001:db8::7]:1234":     http.StatusOK,
+		"192.0.2.1:1234":         http.StatusForbidden,
+		"@":                      http.StatusForbidden,
+	} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = remote
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != status {
+			t.Errorf("remote %s: got %d, want %d", remote, w.Code, status)
+		}
+	}
+}
+
+func TestRequestIP(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "[::ffff:192.0.2.1]:5555"
+	if got := RequestIP(r); got != "192.0.2.1" {
+		t.Errorf("RequestIP = %s", got)
+	}
+	r.RemoteAddr = "@"
+	if got := RequestIP(r); got != "@" {
+		t.Errorf("RequestIP = %s", got)
+	}
+}
+
+func TestClientAddr(t *testing.T) {
+	trusted, _ := ParseAllowlist("10.0.0.0/8")
+	cases := []struct {
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"192.0.2.1:1234", []string{"198.51.100.1"}, "192.0.2.1"},
+		{"10.0.0.1:1234", nil, "10.0.0.1"},
+		{"10.0.0.1:1234", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"10.0.0.1:1234", []string{"203.0.113.9, 198.51.100.1, 10.0.0.2"}, "198.51.100.1"},
+		{"10.0.0.1:1234", []string{"203.0.113.9", "198.51.100.1"}, "198.51.100.1"},
+		{"10.0.0.1:1234", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"10.0.0.1:1234", []string{"198.51.100.1, bogus, 10.0.0.2"}, "10.0.0.2"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = c.remote
+		for _, v := range c.xff {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		addr, ok := ClientAddr(r, trusted)
+		if !ok || addr.String() != c.want {
+			t.Errorf("ClientAddr(%s, %v) = %s, want %s", c.remote, c.xff, addr, c.want)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	trusted, _ := ParseAllowlist("10.0.0.0/8")
+	res := NewResolver(trusted)
+	cases := []struct {
+		name    string
+		remote  string
+		headers map[string]string
+		addr    string
+		scheme  string
+		host    string
+	}{
+		{"direct", "192.0.2.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1", "X-Forwarded-Proto": "https"}, "192.0.2.1", "http", "example.com"},
+		{"forwarded", "10.0.0.1:1234", map[string]string{"Forwarded": `for=203.0.113.9, for="[2001:db8::1]:4711";proto=https;host=api.example.com, for=10.0.0.2`}, "2001:db8::1", "https", "api.example.com"},
+		{"forwarded obfuscated", "10.0.0.1:1234", map[string]string{"Forwarded": "for=_hidden, for=10.0.0.2"}, "10.0.0.2", "http", "example.com"},
+		{"x-forwarded", "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.2", "X-Forwarded-Proto": "HTTPS, http", "X-Forwarded-Host": "api.example.com"}, "198.51.100.1", "https", "api.example.com"},
+		{"x-real-ip", "10.0.0.1:1234", map[string]string{"X-Real-IP": "198.51.100.7"}, "198.51.100.7", "http", "example.com"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("GET", "http://example.com/", nil)
+		r.RemoteAddr = c.remote
+		for k, v := range c.headers {
+			r.Header.Set(k, v)
+		}
+		client := res.Resolve(r)
+		if client.Addr.String() != c.addr || client.Scheme != c.scheme || client.Host != c.host {
+			t.Errorf("%s: unexpected client %s %s %s", c.name, client.Addr, client.Scheme, client.Host)
+		}
+	}
+
+	// handlers behind the middleware agree on the client
+	var ip string
+	var allowed bool
+	allow, _ := ParseAllowlist("198.51.100.0/24")
+	h := res.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip, allowed = RequestIP(r), allow.AllowsRequest(r)
+	}))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "198.51.100.1")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if ip != "198.51.100.1" || !allowed {
+		t.Errorf("expected resolved client behind the middleware, got %s, %v", ip, allowed)
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package labels
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+Package labels provides free form labels for the entities managed by this
+module, e.g. routes, providers and API keys, along with label selectors
+used by the list APIs to group entities operationally without schema
+changes.
+
+A selector is a comma separated list of requirements, all of which must be
+satisfied:
+
+  - key=value, key==value: label is set to the value
+  - key!=value:            label is not set to the value, or not set at all
+  - key in (v1,v2):        label is set to one of the values
+  - key notin (v1,v2):     label is not set to any of the values
+  - key:                   label is set
+  - !key:                  label is not set
+
+# Usage
+
+    sel, err := labels.Parse("env=prod,team in (payments,billing)")
+    if sel.Matches(route.Labels) {
+        ...
+    }
+    filter := sel.Filter("labels") // mongo filter over the labels field
+*/
+
+// Labels are the key value pairs attached to an entity.
+type Labels map[string]string
+
+// operator of a requirement
+type operator int
+
+const (
+	opEquals operator = iota
+	opNotEquals
+	opIn
+	opNotIn
+	opExists
+	opNotExists
+)
+
+// requirement is a single condition of a selector
+type requirement struct {
+	key    string
+	op     operator
+	values []string
+}
+
+// matches reports whether the labels satisfy the requirement
+func (r *requirement) matches(l map[string]string) bool {
+	v, ok := l[r.key]
+	switch r.op {
+	case opEquals:
+		return ok && v == r.values[0]
+	case opNotEquals:
+		return !ok || v != r.values[0]
+	case opIn:
+		return ok && slices.Contains(r.values, v)
+	case opNotIn:
+		return !ok || !slices.Contains(r.values, v)
+	case opExists:
+		return ok
+	case opNotExists:
+		return !ok
+	}
+	return false
+}
+
+// filter returns the mongo filter for the requirement
+func (r *requirement) filter(field string) bson.E {
+	path := field + "." + r.key
+	switch r.op {
+	case opEquals:
+		return bson.E{Key: path, Value: r.values[0]}
+	case opNotEquals:
+		return bson.E{Key: path, Value: bson.D{{Key: "$ne", Value: r.values[0]}}}
+	case opIn:
+		return bson.E{Key: path, Value: bson.D{{Key: "$in", Value: r.values}}}
+	case opNotIn:
+		return bson.E{Key: path, Value: bson.D{{Key: "$nin", Value: r.values}}}
+	case opExists:
+		return bson.E{Key: path, Value: bson.D{{Key: "$exists", Value: true}}}
+	}
+	return bson.E{Key: path, Value: bson.D{{Key: "$exists", Value: false}}}
+}
+
+// Selector selects entities using their labels, the zero value selects
+// every entity.
+type Selector struct {
+	requirements []requirement
+}
+
+// Empty reports whether the selector selects every entity.
+func (s Selector) Empty() bool {
+	return len(s.requirements) == 0
+}
+
+// Matches reports whether the labels satisfy all the requirements of the
+// selector.
+func (s Selector) Matches(l map[string]string) bool {
+	for i := range s.requirements {
+		if !s.requirements[i].matches(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the mongo filter selecting the documents whose labels,
+// stored as a sub document in the given field, satisfy the selector.
+func (s Selector) Filter(field string) bson.D {
+	filter := bson.D{}
+	for i := range s.requirements {
+		filter = append(filter, s.requirements[i].filter(field))
+	}
+	return filter
+}
+
+// FromLabels returns the selector matching all the labels exactly.
+func FromLabels(l map[string]string) Selector {
+	keys := make([]string, 0, len(l))
+	for k := range l {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	s := Selector{}
+	for _, k := range keys {
+		s.requirements = append(s.requirements, requirement{key: k, op: opEquals, values: []string{l[k]}})
+	}
+	return s
+}
+
+// Parse parses the label selector, e.g. "env=prod,team=payments", an empty
+// string returns the selector selecting every entity.
+func Parse(selector string) (Selector, error) {
+	s := Selector{}
+	for _, term := range splitTerms(selector) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		r, err := parseRequirement(term)
+		if err != nil {
+			return Selector{}, err
+		}
+		s.requirements = append(s.requirements, r)
+	}
+	return s, nil
+}
+
+// splitTerms splits the selector on commas outside of parenthesis
+func splitTerms(selector string) []string {
+	terms := []string{}
+	depth, start := 0, 0
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, selector[start:])
+}
+
+// parseRequirement parses a single term of a selector
+func parseRequirement(term string) (requirement, error) {
+	if key, ok := strings.CutPrefix(term, "!"); ok {
+		return newRequirement(strings.TrimSpace(key), opNotExists, nil)
+	}
+	if key, value, ok := strings.Cut(term, "!="); ok {
+		return newRequirement(strings.TrimSpace(key), opNotEquals, []string{strings.TrimSpace(value)})
+	}
+	if key, value, ok := strings.Cut(term, "=="); ok {
+		return newRequirement(strings.TrimSpace(key), opEquals, []string{strings.TrimSpace(value)})
+	}
+	if key, value, ok := strings.Cut(term, "="); ok {
+		return newRequirement(strings.TrimSpace(key), opEquals, []string{strings.TrimSpace(value)})
+	}
+	fields := strings.Fields(term)
+	if len(fields) == 1 {
+		return newRequirement(fields[0], opExists, nil)
+	}
+	if len(fields) >= 2 && (fields[1] == "in" || fields[1] == "notin") {
+		set := strings.TrimSpace(strings.TrimPrefix(term, fields[0]))
+		set = strings.TrimSpace(strings.TrimPrefix(set, fields[1]))
+		if !strings.HasPrefix(set, "(") || !strings.HasSuffix(set, ")") {
+			return requirement{}, errors.Wrapf(errors.InvalidArgument, "invalid label selector: %s", term)
+		}
+		values := []string{}
+		for _, v := range strings.Split(set[1:len(set)-1], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		op := opIn
+		if fields[1] == "notin" {
+			op = opNotIn
+		}
+		return newRequirement(fields[0], op, values)
+	}
+	return requirement{}, errors.Wrapf(errors.InvalidArgument, "invalid label selector: %s", term)
+}
+
+// newRequirement validates and creates a requirement
+func newRequirement(key string, op operator, values []string) (requirement, error) {
+	if err := ValidateKey(key); err != nil {
+		return requirement{}, err
+	}
+	if (op == opIn || op == opNotIn) && len(values) == 0 {
+		return requirement{}, errors.Wrapf(errors.InvalidArgument, "empty value set for label %s", key)
+	}
+	return requirement{key: key, op: op, values: values}, nil
+}
+
+// ValidateKey ensures the label key is non empty, and consists only of
+// alphanumerics, '-', '_' and '/', keeping it usable as a document field.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > 63 {
+		return errors.Wrapf(errors.InvalidArgument, "invalid label key: %q", key)
+	}
+	for _, c := range key {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '/':
+		default:
+			return errors.Wrapf(errors.InvalidArgument, "invalid label key: %q", key)
+		}
+	}
+	return nil
+}
+
+// Validate ensures all the label keys are valid.
+func (l Labels) Validate() error {
+	for k := range l {
+		if err := ValidateKey(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package labels
+
+import (
+	"testing"
+)
+
+func Test_SelectorMatches(t *testing.T) {
+	l := Labels{"env": "prod", "team": "payments"}
+	tests := []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env==prod,team=payments", true},
+		{"env=prod,team=billing", false},
+		{"env!=dev", true},
+		{"region!=eu", true},
+		{"team in (payments, billing)", true},
+		{"env=prod, team notin (payments)", false},
+		{"env", true},
+		{"!region", true},
+		{"!env", false},
+	}
+	for _, test := range tests {
+		sel, err := Parse(test.selector)
+		if err != nil {
+			t.Errorf("failed to parse %q: %s", test.selector, err)
+			continue
+		}
+		if sel.Matches(l) != test.matches {
+			t.Errorf("selector %q: expected match %v", test.selector, test.matches)
+		}
+	}
+}
+
+func Test_SelectorInvalid(t *testing.T) {
+	for _, selector := range []string{"env.name=prod", "team in payments", "team in ()", "=prod", "a b c"} {
+		if _, err := Parse(selector); err == nil {
+			t.Errorf("expected error parsing %q", selector)
+		}
+	}
+}
+
+func Test_SelectorFilter(t *testing.T) {
+	sel, _ := Parse("env=prod,team in (a,b)")
+	filter := sel.Filter("labels")
+	if len(filter) != 2 || filter[0].Key != "labels.env" || filter[0].Value != "prod" || filter[1].Key != "labels.team" {
+		t.Errorf("unexpected filter %v", filter)
+	}
+}
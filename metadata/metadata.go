@@ -0,0 +1,160 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+/*
+Package metadata describes the auth capabilities of a deployment in a JSON
+document served at /.well-known/auth-configuration, allowing client SDKs
+to configure themselves instead of hard-coding the settings per
+environment.
+
+# Usage
+
+    cfg := metadata.DefaultConfiguration(60 * time.Second)
+    cfg.Headers = hash.DefaultHeaderNames() // same as the Validator
+    cfg.TokenIssuer = "https://auth.example.com"
+    cfg.JwksURI = "https://auth.example.com/.well-known/jwks.json"
+    mux.Handle(metadata.WellKnownPath, metadata.NewHandler(cfg))
+*/
+
+// WellKnownPath is the path the configuration is served at.
+const WellKnownPath = "/.well-known/auth-configuration"
+
+// Timestamp formats accepted by the Validator.
+const (
+	TimestampRFC3339 = "rfc3339"
+	TimestampEpoch   = "epoch"
+)
+
+// Headers holds the names of the authentication headers.
+type Headers struct {
+	Signature        string `json:"signature"`
+	Algorithm        string `json:"algorithm"`
+	Version          string `json:"version"`
+	Timestamp        string `json:"timestamp"`
+	KeyId            string `json:"key_id"`
+	ContentSignature string `json:"content_signature,omitempty"`
+	Nonce            string `json:"nonce,omitempty"`
+	SessionToken     string `json:"session_token,omitempty"`
+
+	ImpersonateUser   string `json:"impersonate_user,omitempty"`
+	ImpersonateTenant string `json:"impersonate_tenant,omitempty"`
+}
+
+// HeadersFrom returns the header names of the hash package.
+func HeadersFrom(h hash.HeaderNames) Headers {
+	return Headers{
+		Signature:        h.Signature,
+		Algorithm:        h.Algorithm,
+		Version:          h.Version,
+		Timestamp:        h.Timestamp,
+		KeyId:            h.KeyId,
+		ContentSignature: h.ContentSignature,
+		Nonce:            h.Nonce,
+		SessionToken:     h.SessionToken,
+
+		ImpersonateUser:   h.ImpersonateUser,
+		ImpersonateTenant: h.ImpersonateTenant,
+	}
+}
+
+// HeaderNames returns the header names as used by the hash package.
+func (h Headers) HeaderNames() hash.HeaderNames {
+	return hash.HeaderNames{
+		Signature:        h.Signature,
+		Algorithm:        h.Algorithm,
+		Version:          h.Version,
+		Timestamp:        h.Timestamp,
+		KeyId:            h.KeyId,
+		ContentSignature: h.ContentSignature,
+		Nonce:            h.Nonce,
+		SessionToken:     h.SessionToken,
+
+		ImpersonateUser:   h.ImpersonateUser,
+		ImpersonateTenant: h.ImpersonateTenant,
+	}
+}
+
+// Configuration is the auth configuration of the deployment.
+type Configuration struct {
+	// signature versions and algorithms accepted, along with the ones
+	// expected to be used by the clients
+	SignatureVersions         []string `json:"signature_versions_supported"`
+	SignatureAlgorithms       []string `json:"signature_algorithms_supported"`
+	DefaultSignatureVersion   string   `json:"default_signature_version"`
+	DefaultSignatureAlgorithm string   `json:"default_signature_algorithm"`
+
+	// names of the authentication headers
+	Headers Headers `json:"headers"`
+
+	// timestamp formats accepted
+	TimestampFormats []string `json:"timestamp_formats_supported"`
+
+	// tolerated difference between the clocks of the client and the
+	// server, i.e. the validity of the signed requests
+	ClockSkewSeconds int64 `json:"clock_skew_seconds"`
+
+	// issuer of the tokens and the URI of its JSON Web Key Set, if tokens
+	// are supported
+	TokenIssuer string `json:"token_issuer,omitempty"`
+	JwksURI     string `json:"jwks_uri,omitempty"`
+}
+
+// DefaultConfiguration returns the configuration of a Validator with the
+// default options and the clock skew tolerance.
+func DefaultConfiguration(clockSkew time.Duration) *Configuration {
+	versions := []string{}
+	for _, v := range hash.RegisteredSignatureVersions() {
+		versions = append(versions, string(v))
+	}
+	sort.Strings(versions)
+	algorithms := []string{}
+	for _, alg := range hash.SupportedAlgorithms() {
+		algorithms = append(algorithms, alg.String())
+	}
+	return &Configuration{
+		SignatureVersions:         versions,
+		SignatureAlgorithms:       algorithms,
+		DefaultSignatureVersion:   string(hash.DefaultSignatureVersion),
+		DefaultSignatureAlgorithm: hash.DefaultAlgorithm.String(),
+		Headers:                   HeadersFrom(hash.DefaultHeaderNames()),
+		TimestampFormats:          []string{TimestampRFC3339, TimestampEpoch},
+		ClockSkewSeconds:          int64(clockSkew / time.Second),
+	}
+}
+
+// DefaultMaxAge is the duration clients may cache the configuration for.
+const DefaultMaxAge = 5 * time.Minute
+
+// NewHandler returns an http.Handler serving the configuration as JSON,
+// cacheable by the clients for DefaultMaxAge.
+func NewHandler(cfg *Configuration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		b, err := json.Marshal(cfg)
+		if err != nil {
+			http.Error(w, "failed to encode auth configuration", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(DefaultMaxAge/time.Second)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(b)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestHandler(t *testing.T) {
+	cfg := DefaultConfiguration(60 * time.Second)
+	cfg.TokenIssuer = "https://auth.example.com"
+	h := NewHandler(cfg)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, WellKnownPath, nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	got := &Configuration{}
+	if err := json.Unmarshal(rec.Body.Bytes(), got); err != nil {
+		t.Fatalf("failed to decode configuration: %s", err)
+	}
+	if got.ClockSkewSeconds != 60 || got.TokenIssuer != cfg.TokenIssuer {
+		t.Errorf("unexpected configuration %+v", got)
+	}
+	if !slices.Contains(got.SignatureVersions, string(hash.SignatureV2)) {
+		t.Errorf("expected v2 in %v", got.SignatureVersions)
+	}
+	if got.Headers.HeaderNames() != hash.DefaultHeaderNames() {
+		t.Errorf("unexpected headers %+v", got.Headers)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, WellKnownPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package model
+
+import (
+	"context"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/route"
+)
+
+// SubjectType identifies the kind of the authenticated subject.
+type SubjectType string
+
+const (
+	// human user, e.g. authenticated by the identity provider
+	SubjectUser SubjectType = "user"
+
+	// service or machine identity, e.g. authenticated by an API key
+	SubjectService SubjectType = "service"
+)
+
+// AuthContext describes the authenticated caller of a request, populated
+// by the validation middleware and interceptors, and read by downstream
+// handlers using FromContext.
+type AuthContext struct {
+	KeyId       string      // API key used for authentication, if any
+	Tenant      string      // tenant the caller belongs to
+	Subject     string      // authenticated user or service identity
+	SubjectType SubjectType // kind of the subject
+	Roles       []string    // roles associated with the subject
+	IsRoot      bool        // caller belongs to the root tenancy
+
+	// subject impersonating the caller, e.g. the owner of the admin API
+	// key signing on behalf of the subject
+	ImpersonatedBy string
+}
+
+// Identity returns the caller as the identity used for authorization.
+func (a *AuthContext) Identity() *authctx.Identity {
+	return &authctx.Identity{
+		Subject: a.Subject,
+		Tenant:  a.Tenant,
+		Roles:   a.Roles,
+		KeyId:   a.KeyId,
+	}
+}
+
+// Impersonate returns the auth context of the user of the tenant
+// impersonated by the caller, keeping the API key and recording the
+// subject of the caller as ImpersonatedBy. Roles are not carried over.
+func (a *AuthContext) Impersonate(user, tenant string) *AuthContext {
+	return &AuthContext{
+		KeyId:          a.KeyId,
+		Tenant:         tenant,
+		Subject:        user,
+		SubjectType:    SubjectUser,
+		IsRoot:         tenant == route.RootTenant,
+		ImpersonatedBy: a.Subject,
+	}
+}
+
+// FromIdentity returns the auth context of the identity, e.g. verified
+// from the identity headers set by the gateway.
+func FromIdentity(id *authctx.Identity) *AuthContext {
+	a := &AuthContext{
+		KeyId:       id.KeyId,
+		Tenant:      id.Tenant,
+		Subject:     id.Subject,
+		SubjectType: SubjectUser,
+		Roles:       id.Roles,
+	}
+	if id.KeyId != "" {
+		a.SubjectType = SubjectService
+	}
+	return a
+}
+
+// FromAuthInfo returns the auth context of the user authenticated by the
+// identity provider.
+func FromAuthInfo(info *authctx.AuthInfo) *AuthContext {
+	return &AuthContext{
+		Tenant:      info.Realm,
+		Subject:     info.UserName,
+		SubjectType: SubjectUser,
+		Roles:       info.Roles,
+		IsRoot:      info.IsRoot,
+	}
+}
+
+// struct identifier for the context
+type authContextKey struct{}
+
+// WithAuthContext returns a new context with the auth context attached.
+func WithAuthContext(ctx context.Context, a *AuthContext) context.Context {
+	return context.WithValue(ctx, authContextKey{}, a)
+}
+
+// FromContext returns the auth context attached to the context, if any.
+func FromContext(ctx context.Context) (*AuthContext, bool) {
+	a, ok := ctx.Value(authContextKey{}).(*AuthContext)
+	return a, ok && a != nil
+}
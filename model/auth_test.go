@@ -0,0 +1,47 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package model
+
+import (
+	"context"
+	"testing"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+func TestAuthContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Errorf("expected no auth context")
+	}
+
+	a := FromIdentity(&authctx.Identity{Subject: "svc", Tenant: "acme", KeyId: "k1", Roles: []string{"reader"}})
+	if a.SubjectType != SubjectService || a.KeyId != "k1" {
+		t.Errorf("unexpected auth context %+v", a)
+	}
+	ctx := WithAuthContext(context.Background(), a)
+	got, ok := FromContext(ctx)
+	if !ok || got != a {
+		t.Fatalf("expected auth context from context")
+	}
+	id := got.Identity()
+	if id.Subject != "svc" || id.Tenant != "acme" || id.KeyId != "k1" {
+		t.Errorf("unexpected identity %+v", id)
+	}
+
+	u := FromAuthInfo(&authctx.AuthInfo{Realm: "root", UserName: "alice", IsRoot: true})
+	if u.SubjectType != SubjectUser || u.Subject != "alice" || !u.IsRoot {
+		t.Errorf("unexpected auth context %+v", u)
+	}
+}
+
+func TestAuthContextImpersonate(t *testing.T) {
+	admin := &AuthContext{KeyId: "k1", Tenant: "root", Subject: "admin", SubjectType: SubjectService, Roles: []string{"admin"}, IsRoot: true}
+	a := admin.Impersonate("alice", "acme")
+	if a.Subject != "alice" || a.Tenant != "acme" || a.SubjectType != SubjectUser || a.IsRoot || len(a.Roles) != 0 {
+		t.Errorf("unexpected auth context %+v", a)
+	}
+	if a.KeyId != "k1" || a.ImpersonatedBy != "admin" {
+		t.Errorf("expected key and impersonating subject kept, got %+v", a)
+	}
+}
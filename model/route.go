@@ -4,8 +4,6 @@
 package model
 
 import (
-	"net/http"
-
 	"github.com/go-core-stack/auth/route"
 )
 
@@ -27,9 +25,9 @@ type Route struct {
 //
 // Returns:
 //   - *Route: Pointer to the created Route struct.
+//   - error:  InvalidArgument if the method is unknown.
 //
 // The method string is mapped to the corresponding route.MethodType.
-// If the method is unknown, it defaults to route.GET.
 //
 // # Usage
 //
@@ -38,34 +36,20 @@ type Route struct {
 //	)
 //
 //	func main() {
-//	    r := model.NewRoute("/api/v1/resource", "POST")
+//	    r, err := model.NewRoute("/api/v1/resource", "POST")
+//	    if err != nil {
+//	        panic(err)
+//	    }
 //	    fmt.Println(r.Url)    // Output: /api/v1/resource
-//	    fmt.Println(r.Method) // Output: 1 (assuming route.POST == 1)
+//	    fmt.Println(r.Method) // Output: POST
 //	}
-func NewRoute(url, method string) *Route {
-	var m route.MethodType
-	switch method {
-	case http.MethodHead:
-		m = route.HEAD
-	case http.MethodPost:
-		m = route.POST
-	case http.MethodPut:
-		m = route.PUT
-	case http.MethodPatch:
-		m = route.PATCH
-	case http.MethodDelete:
-		m = route.DELETE
-	case http.MethodConnect:
-		m = route.CONNECT
-	case http.MethodOptions:
-		m = route.OPTIONS
-	case http.MethodTrace:
-		m = route.TRACE
-	default:
-		m = route.GET // Default to GET if method is unknown
+func NewRoute(url, method string) (*Route, error) {
+	m, err := route.ParseMethod(method)
+	if err != nil {
+		return nil, err
 	}
 	return &Route{
 		Url:    url,
 		Method: m,
-	}
+	}, nil
 }
@@ -0,0 +1,25 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build (linux || darwin || freebsd) && cgo
+
+package plugins
+
+import (
+	"plugin"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// LoadAvailable reports whether Go plugins can be loaded by this build.
+const LoadAvailable = true
+
+// Load opens the Go plugin at the path, whose init functions register its
+// implementations. The plugin needs to be built with the same version of
+// this package and of the Go toolchain as the binary loading it.
+func Load(path string) error {
+	if _, err := plugin.Open(path); err != nil {
+		return errors.Wrapf(errors.InvalidArgument, "failed to load plugin %s: %s", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+//go:build !((linux || darwin || freebsd) && cgo)
+
+package plugins
+
+import (
+	"github.com/go-core-stack/core/errors"
+)
+
+// LoadAvailable reports whether Go plugins can be loaded by this build.
+const LoadAvailable = false
+
+// Load fails as Go plugins are not supported by this build, implementations
+// need to be compiled into the binary instead.
+func Load(path string) error {
+	return errors.Wrapf(errors.InvalidArgument, "failed to load plugin %s: go plugins not supported by this build", path)
+}
@@ -0,0 +1,266 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package plugins
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+/*
+Package plugins allows deployments to add custom Authenticator,
+SecretProvider and Enforcer implementations without forking the package.
+
+Implementations are registered under a name by a factory, typically from
+the init function of the package providing them, which is either compiled
+into the binary or loaded at runtime as a Go plugin using Load. The
+implementation to use is then selected by name from the configuration of
+the deployment.
+
+# Usage
+
+    // package providing the implementation
+    func init() {
+        plugins.RegisterSecretProvider("vault", func(cfg map[string]any) (plugins.SecretProvider, error) {
+            return newVaultResolver(cfg["address"].(string))
+        })
+    }
+
+    // deployment, name and config read from its configuration
+    _ = plugins.Load("/usr/lib/auth/vault.so") // only for runtime plugins
+    resolver, err := plugins.NewSecretProvider("vault", map[string]any{"address": addr})
+*/
+
+// Authenticator authenticates the caller of a request.
+type Authenticator interface {
+	// Authenticate returns the identity of the caller, or an error with
+	// code errors.Unauthorized.
+	Authenticate(r *http.Request) (*authctx.Identity, error)
+}
+
+// AuthenticatorFunc is an adapter allowing the use of an ordinary function
+// as an Authenticator.
+type AuthenticatorFunc func(r *http.Request) (*authctx.Identity, error)
+
+// Authenticate calls f(r).
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*authctx.Identity, error) {
+	return f(r)
+}
+
+// SecretProvider provides the secrets of the API keys.
+type SecretProvider = hash.SecretResolver
+
+// Enforcer enforces the permissions of the identities.
+type Enforcer = rbac.Authorizer
+
+// Factories create the implementations out of their free form
+// configuration.
+type (
+	AuthenticatorFactory  func(config map[string]any) (Authenticator, error)
+	SecretProviderFactory func(config map[string]any) (SecretProvider, error)
+	EnforcerFactory       func(config map[string]any) (Enforcer, error)
+)
+
+// registry holds the factories of a kind of implementation by name.
+type registry[F any] struct {
+	kind      string
+	mu        sync.RWMutex
+	factories map[string]F
+}
+
+// newRegistry creates the registry for the kind of implementations.
+func newRegistry[F any](kind string) *registry[F] {
+	return &registry[F]{
+		kind:      kind,
+		factories: map[string]F{},
+	}
+}
+
+// register registers the factory, failing if the name is already taken.
+func (r *registry[F]) register(name string, factory F) error {
+	if name == "" {
+		return errors.Wrapf(errors.InvalidArgument, "%s name not specified", r.kind)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.factories[name]; ok {
+		return errors.Wrapf(errors.AlreadyExists, "%s %s already registered", r.kind, name)
+	}
+	r.factories[name] = factory
+	return nil
+}
+
+// get returns the factory registered with the name.
+func (r *registry[F]) get(name string) (F, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	f, ok := r.factories[name]
+	if !ok {
+		return f, errors.Wrapf(errors.NotFound, "%s %s not registered", r.kind, name)
+	}
+	return f, nil
+}
+
+// names returns the registered names, sorted.
+func (r *registry[F]) names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Registry holds the registered factories of every kind. The package level
+// functions use DefaultRegistry, consumers needing isolation, e.g. tests or
+// multiple gateways in the same process, create their own with NewRegistry.
+type Registry struct {
+	authenticators  *registry[AuthenticatorFactory]
+	secretProviders *registry[SecretProviderFactory]
+	enforcers       *registry[EnforcerFactory]
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		authenticators:  newRegistry[AuthenticatorFactory]("authenticator"),
+		secretProviders: newRegistry[SecretProviderFactory]("secret provider"),
+		enforcers:       newRegistry[EnforcerFactory]("enforcer"),
+	}
+}
+
+// DefaultRegistry is the Registry used by the package level functions,
+// and by the runtime plugins registering from their init functions.
+var DefaultRegistry = NewRegistry()
+
+// RegisterAuthenticator registers the Authenticator factory under the
+// name, failing if the name is already registered.
+func (r *Registry) RegisterAuthenticator(name string, factory AuthenticatorFactory) error {
+	if factory == nil {
+		return errors.Wrapf(errors.InvalidArgument, "authenticator factory not specified")
+	}
+	return r.authenticators.register(name, factory)
+}
+
+// NewAuthenticator creates the Authenticator registered under the name.
+func (r *Registry) NewAuthenticator(name string, config map[string]any) (Authenticator, error) {
+	f, err := r.authenticators.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return f(config)
+}
+
+// Authenticators returns the names of the registered Authenticators.
+func (r *Registry) Authenticators() []string {
+	return r.authenticators.names()
+}
+
+// RegisterSecretProvider registers the SecretProvider factory under the
+// name, failing if the name is already registered.
+func (r *Registry) RegisterSecretProvider(name string, factory SecretProviderFactory) error {
+	if factory == nil {
+		return errors.Wrapf(errors.InvalidArgument, "secret provider factory not specified")
+	}
+	return r.secretProviders.register(name, factory)
+}
+
+// NewSecretProvider creates the SecretProvider registered under the name.
+func (r *Registry) NewSecretProvider(name string, config map[string]any) (SecretProvider, error) {
+	f, err := r.secretProviders.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return f(config)
+}
+
+// SecretProviders returns the names of the registered SecretProviders.
+func (r *Registry) SecretProviders() []string {
+	return r.secretProviders.names()
+}
+
+// RegisterEnforcer registers the Enforcer factory under the name, failing
+// if the name is already registered.
+func (r *Registry) RegisterEnforcer(name string, factory EnforcerFactory) error {
+	if factory == nil {
+		return errors.Wrapf(errors.InvalidArgument, "enforcer factory not specified")
+	}
+	return r.enforcers.register(name, factory)
+}
+
+// NewEnforcer creates the Enforcer registered under the name.
+func (r *Registry) NewEnforcer(name string, config map[string]any) (Enforcer, error) {
+	f, err := r.enforcers.get(name)
+	if err != nil {
+		return nil, err
+	}
+	return f(config)
+}
+
+// Enforcers returns the names of the registered Enforcers.
+func (r *Registry) Enforcers() []string {
+	return r.enforcers.names()
+}
+
+// RegisterAuthenticator registers the Authenticator factory with the
+// DefaultRegistry.
+func RegisterAuthenticator(name string, factory AuthenticatorFactory) error {
+	return DefaultRegistry.RegisterAuthenticator(name, factory)
+}
+
+// NewAuthenticator creates the Authenticator registered under the name
+// with the DefaultRegistry.
+func NewAuthenticator(name string, config map[string]any) (Authenticator, error) {
+	return DefaultRegistry.NewAuthenticator(name, config)
+}
+
+// Authenticators returns the names of the Authenticators registered with
+// the DefaultRegistry.
+func Authenticators() []string {
+	return DefaultRegistry.Authenticators()
+}
+
+// RegisterSecretProvider registers the SecretProvider factory with the
+// DefaultRegistry.
+func RegisterSecretProvider(name string, factory SecretProviderFactory) error {
+	return DefaultRegistry.RegisterSecretProvider(name, factory)
+}
+
+// NewSecretProvider creates the SecretProvider registered under the name
+// with the DefaultRegistry.
+func NewSecretProvider(name string, config map[string]any) (SecretProvider, error) {
+	return DefaultRegistry.NewSecretProvider(name, config)
+}
+
+// SecretProviders returns the names of the SecretProviders registered with
+// the DefaultRegistry.
+func SecretProviders() []string {
+	return DefaultRegistry.SecretProviders()
+}
+
+// RegisterEnforcer registers the Enforcer factory with the DefaultRegistry.
+func RegisterEnforcer(name string, factory EnforcerFactory) error {
+	return DefaultRegistry.RegisterEnforcer(name, factory)
+}
+
+// NewEnforcer creates the Enforcer registered under the name with the
+// DefaultRegistry.
+func NewEnforcer(name string, config map[string]any) (Enforcer, error) {
+	return DefaultRegistry.NewEnforcer(name, config)
+}
+
+// Enforcers returns the names of the Enforcers registered with the
+// DefaultRegistry.
+func Enforcers() []string {
+	return DefaultRegistry.Enforcers()
+}
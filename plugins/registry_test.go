@@ -0,0 +1,74 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package plugins
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestRegistry(t *testing.T) {
+	err := RegisterAuthenticator("test-static", func(cfg map[string]any) (Authenticator, error) {
+		subject, _ := cfg["subject"].(string)
+		return AuthenticatorFunc(func(r *http.Request) (*authctx.Identity, error) {
+			return &authctx.Identity{Subject: subject}, nil
+		}), nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register authenticator: %s", err)
+	}
+	err = RegisterAuthenticator("test-static", func(cfg map[string]any) (Authenticator, error) { return nil, nil })
+	if !errors.IsAlreadyExists(err) {
+		t.Errorf("expected already exists, got %v", err)
+	}
+
+	a, err := NewAuthenticator("test-static", map[string]any{"subject": "svc"})
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %s", err)
+	}
+	id, _ := a.Authenticate(&http.Request{})
+	if id.Subject != "svc" {
+		t.Errorf("unexpected subject %s", id.Subject)
+	}
+	if _, err := NewAuthenticator("unknown", nil); !errors.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	err = RegisterSecretProvider("test-static", func(cfg map[string]any) (SecretProvider, error) {
+		return hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+			return "secret", nil
+		}), nil
+	})
+	if err != nil {
+		t.Fatalf("failed to register secret provider: %s", err)
+	}
+	if names := SecretProviders(); len(names) != 1 || names[0] != "test-static" {
+		t.Errorf("unexpected secret providers %v", names)
+	}
+	if err := RegisterEnforcer("", nil); err == nil {
+		t.Errorf("expected error registering without name and factory")
+	}
+}
+
+func TestRegistryIsolation(t *testing.T) {
+	r := NewRegistry()
+	factory := func(cfg map[string]any) (SecretProvider, error) {
+		return hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) { return "s", nil }), nil
+	}
+	if err := r.RegisterSecretProvider("isolated", factory); err != nil {
+		t.Fatalf("failed to register: %s", err)
+	}
+	if _, err := r.NewSecretProvider("isolated", nil); err != nil {
+		t.Errorf("expected the provider in the instance registry: %s", err)
+	}
+	if _, err := NewSecretProvider("isolated", nil); !errors.IsNotFound(err) {
+		t.Errorf("expected the default registry to be unaffected, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import (
+	"context"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+/*
+Package rbac provides the role based access control of the resources and
+verbs, including the control plane of the auth system itself, whose routes,
+keys and roles are governed as the built-in resources auth.routes,
+auth.keys and auth.roles.
+
+# Usage
+
+    secured := route.NewSecuredRouteTable(tbl, authorizer)
+    err := secured.AddRoute(ctx, caller, r) // requires create on auth.routes
+*/
+
+// Built-in resources of the auth control plane.
+const (
+	ResourceRoutes = "auth.routes"
+	ResourceKeys   = "auth.keys"
+	ResourceRoles  = "auth.roles"
+)
+
+// Verbs of the built-in resources.
+const (
+	VerbCreate = "create"
+	VerbUpdate = "update"
+	VerbDelete = "delete"
+	VerbGet    = "get"
+	VerbList   = "list"
+)
+
+// Authorizer authorizes the actions of the identities.
+type Authorizer interface {
+	// Authorize returns nil if the identity is allowed to perform the verb
+	// on the resource, and an error with code errors.Forbidden otherwise.
+	Authorize(ctx context.Context, id *authctx.Identity, resource, verb string) error
+}
+
+// AuthorizerFunc is an adapter allowing the use of an ordinary function as
+// an Authorizer.
+type AuthorizerFunc func(ctx context.Context, id *authctx.Identity, resource, verb string) error
+
+// Authorize calls f(ctx, id, resource, verb).
+func (f AuthorizerFunc) Authorize(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+	return f(ctx, id, resource, verb)
+}
+
+// Check authorizes the identity for the verb on the resource, failing with
+// Unauthorized without an identity, and Forbidden without an authorizer,
+// so that a missing configuration never grants access.
+func Check(ctx context.Context, a Authorizer, id *authctx.Identity, resource, verb string) error {
+	if id == nil || id.Subject == "" {
+		return errors.Wrapf(errors.Unauthorized, "caller identity not available")
+	}
+	if a == nil {
+		return errors.Wrapf(errors.Forbidden, "no authorizer configured for %s", resource)
+	}
+	return a.Authorize(ctx, id, resource, verb)
+}
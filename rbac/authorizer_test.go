@@ -0,0 +1,36 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+func TestCheck(t *testing.T) {
+	admin := &authctx.Identity{Subject: "admin"}
+	authz := AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+		if id.Subject == "admin" {
+			return nil
+		}
+		return errors.Wrapf(errors.Forbidden, "%s not allowed to %s %s", id.Subject, verb, resource)
+	})
+	ctx := context.Background()
+	if err := Check(ctx, authz, admin, ResourceRoutes, VerbCreate); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := Check(ctx, authz, &authctx.Identity{Subject: "guest"}, ResourceRoutes, VerbCreate); !errors.IsForbidden(err) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+	if err := Check(ctx, authz, nil, ResourceRoutes, VerbCreate); !errors.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized without identity, got %v", err)
+	}
+	if err := Check(ctx, nil, admin, ResourceRoutes, VerbCreate); !errors.IsForbidden(err) {
+		t.Errorf("expected forbidden without authorizer, got %v", err)
+	}
+}
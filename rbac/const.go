@@ -0,0 +1,15 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+const (
+	// Collection names within the database the consumer supplies via
+	// db.Store.
+	RolesCollection        = "roles"
+	RoleBindingsCollection = "role_bindings"
+
+	// Wildcard matches any resource or verb in a rule, a trailing
+	// wildcard matches by prefix, e.g. "auth.*".
+	Wildcard = "*"
+)
@@ -0,0 +1,63 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+// Policy is the snapshot of the roles and role bindings of a tenancy,
+// evaluating the permissions of its subjects without accessing the store.
+type Policy struct {
+	// roles by name, tenancy roles shadowing the global ones
+	roles map[string]*Role
+
+	// role names bound to each subject
+	bindings map[string][]string
+}
+
+// NewPolicy creates the policy of the tenancy out of its roles, including
+// the global roles, and its role bindings. Bindings of other tenancies and
+// of unknown roles are ignored.
+func NewPolicy(tenant string, roles []*Role, bindings []*RoleBinding) *Policy {
+	p := &Policy{
+		roles:    map[string]*Role{},
+		bindings: map[string][]string{},
+	}
+	for _, r := range roles {
+		if r == nil || r.Key == nil {
+			continue
+		}
+		switch r.Key.Tenant {
+		case tenant:
+			p.roles[r.Key.Name] = r
+		case "":
+			if _, ok := p.roles[r.Key.Name]; !ok {
+				p.roles[r.Key.Name] = r
+			}
+		}
+	}
+	for _, b := range bindings {
+		if b == nil || b.Key == nil || b.Key.Tenant != tenant {
+			continue
+		}
+		if _, ok := p.roles[b.Key.Role]; !ok {
+			continue
+		}
+		p.bindings[b.Key.Subject] = append(p.bindings[b.Key.Subject], b.Key.Role)
+	}
+	return p
+}
+
+// Roles returns the names of the roles bound to the subject.
+func (p *Policy) Roles(subject string) []string {
+	return p.bindings[subject]
+}
+
+// Evaluate reports whether any role bound to the subject grants the verb
+// on the resource, denying by default.
+func (p *Policy) Evaluate(subject, resource, verb string) bool {
+	for _, name := range p.bindings[subject] {
+		if p.roles[name].Allows(resource, verb) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import "testing"
+
+func TestPolicyEvaluate(t *testing.T) {
+	roles := []*Role{
+		{
+			Key:   &RoleKey{Name: "admin"},
+			Rules: []*Rule{{Resources: []string{"auth.*"}, Verbs: []string{Wildcard}}},
+		},
+		{
+			Key:   &RoleKey{Name: "reader"},
+			Rules: []*Rule{{Resources: []string{"books"}, Verbs: []string{VerbGet, VerbList}}},
+		},
+		{
+			// tenancy role shadowing the global one
+			Key:   &RoleKey{Tenant: "acme", Name: "reader"},
+			Rules: []*Rule{{Resources: []string{"books", "authors"}, Verbs: []string{VerbGet}}},
+		},
+	}
+	bindings := []*RoleBinding{
+		{Key: &RoleBindingKey{Tenant: "acme", Subject: "alice", Role: "admin"}},
+		{Key: &RoleBindingKey{Tenant: "acme", Subject: "bob", Role: "reader"}},
+		{Key: &RoleBindingKey{Tenant: "acme", Subject: "carol", Role: "unknown"}},
+		{Key: &RoleBindingKey{Tenant: "other", Subject: "dave", Role: "admin"}},
+	}
+	p := NewPolicy("acme", roles, bindings)
+
+	tests := []struct {
+		subject, resource, verb string
+		want                    bool
+	}{
+		{"alice", ResourceRoutes, VerbCreate, true},
+		{"alice", ResourceRoles, VerbDelete, true},
+		{"alice", "books", VerbGet, false},
+		{"bob", "authors", VerbGet, true},
+		{"bob", "books", VerbList, false},
+		{"bob", "books", VerbDelete, false},
+		{"carol", "books", VerbGet, false},
+		{"dave", ResourceRoutes, VerbCreate, false},
+		{"eve", "books", VerbGet, false},
+	}
+	for _, tt := range tests {
+		if got := p.Evaluate(tt.subject, tt.resource, tt.verb); got != tt.want {
+			t.Errorf("Evaluate(%s, %s, %s) = %v, want %v", tt.subject, tt.resource, tt.verb, got, tt.want)
+		}
+	}
+}
+
+func TestRoleValidate(t *testing.T) {
+	if err := (&Role{Key: &RoleKey{}}).validate(); err == nil {
+		t.Errorf("expected error for role without name")
+	}
+	r := &Role{Key: &RoleKey{Name: "reader"}, Rules: []*Rule{{Resources: []string{"books"}}}}
+	if err := r.validate(); err == nil {
+		t.Errorf("expected error for rule without verbs")
+	}
+	r.Rules[0].Verbs = []string{VerbGet}
+	if err := r.validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import (
+	"strings"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// RoleKey identifies a role within a tenancy, roles of the empty tenancy
+// are global, available for binding in every tenancy.
+type RoleKey struct {
+	Tenant string `bson:"tenant"`
+	Name   string `bson:"name,omitempty"`
+}
+
+// Rule grants the verbs on the resources, matching the Resource and Verb
+// of the routes, see Wildcard for the patterns.
+type Rule struct {
+	Resources []string `bson:"resources,omitempty"`
+	Verbs     []string `bson:"verbs,omitempty"`
+}
+
+// Role is a named set of rules.
+type Role struct {
+	Key *RoleKey `bson:"key,omitempty"`
+
+	// human readable description of the role
+	Description string `bson:"description,omitempty"`
+
+	// rules granted by the role
+	Rules []*Rule `bson:"rules,omitempty"`
+}
+
+// RoleBindingKey identifies the binding of a subject to a role within a
+// tenancy.
+type RoleBindingKey struct {
+	Tenant  string `bson:"tenant"`
+	Subject string `bson:"subject,omitempty"`
+	Role    string `bson:"role,omitempty"`
+}
+
+// RoleBinding binds a subject to a role within a tenancy.
+type RoleBinding struct {
+	Key *RoleBindingKey `bson:"key,omitempty"`
+
+	// time of creation, unix seconds
+	Created int64 `bson:"created,omitempty"`
+}
+
+// matchPattern reports whether the value matches the rule pattern.
+func matchPattern(pattern, value string) bool {
+	if pattern == Wildcard || pattern == value {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, Wildcard); ok {
+		return strings.HasPrefix(value, prefix)
+	}
+	return false
+}
+
+// matchAny reports whether the value matches any of the patterns.
+func matchAny(patterns []string, value string) bool {
+	for _, p := range patterns {
+		if matchPattern(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allows reports whether the rule grants the verb on the resource.
+func (r *Rule) Allows(resource, verb string) bool {
+	return matchAny(r.Resources, resource) && matchAny(r.Verbs, verb)
+}
+
+// Allows reports whether any rule of the role grants the verb on the
+// resource.
+func (r *Role) Allows(resource, verb string) bool {
+	for _, rule := range r.Rules {
+		if rule != nil && rule.Allows(resource, verb) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the role before it is stored.
+func (r *Role) validate() error {
+	if r.Key == nil || r.Key.Name == "" {
+		return errors.Wrapf(errors.InvalidArgument, "role name is required")
+	}
+	for i, rule := range r.Rules {
+		if rule == nil || len(rule.Resources) == 0 || len(rule.Verbs) == 0 {
+			return errors.Wrapf(errors.InvalidArgument, "rule %d of role %s requires resources and verbs", i, r.Key.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import (
+	"context"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+// SecuredStore exposes the role and role binding APIs on behalf of a
+// caller, authorizing every call against the built-in auth.roles resource.
+type SecuredStore struct {
+	store *Store
+	authz Authorizer
+}
+
+// NewSecuredStore creates the secured view of the store, the store itself
+// being typically also the authorizer.
+func NewSecuredStore(store *Store, authz Authorizer) *SecuredStore {
+	return &SecuredStore{
+		store: store,
+		authz: authz,
+	}
+}
+
+// AddRole adds the role, requiring create on auth.roles.
+func (s *SecuredStore) AddRole(ctx context.Context, id *authctx.Identity, r *Role) error {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbCreate); err != nil {
+		return err
+	}
+	return s.store.AddRole(ctx, r)
+}
+
+// UpdateRole updates the role, requiring update on auth.roles.
+func (s *SecuredStore) UpdateRole(ctx context.Context, id *authctx.Identity, r *Role) error {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbUpdate); err != nil {
+		return err
+	}
+	return s.store.UpdateRole(ctx, r)
+}
+
+// DeleteRole deletes the role, requiring delete on auth.roles.
+func (s *SecuredStore) DeleteRole(ctx context.Context, id *authctx.Identity, key *RoleKey) error {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbDelete); err != nil {
+		return err
+	}
+	return s.store.DeleteRole(ctx, key)
+}
+
+// GetRole returns the role, requiring get on auth.roles.
+func (s *SecuredStore) GetRole(ctx context.Context, id *authctx.Identity, key *RoleKey) (*Role, error) {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbGet); err != nil {
+		return nil, err
+	}
+	return s.store.GetRole(ctx, key)
+}
+
+// ListRoles returns the roles of the tenancy, requiring list on
+// auth.roles.
+func (s *SecuredStore) ListRoles(ctx context.Context, id *authctx.Identity, tenant string) ([]*Role, error) {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbList); err != nil {
+		return nil, err
+	}
+	return s.store.ListRoles(ctx, tenant)
+}
+
+// Bind binds the subject to the role, requiring update on auth.roles.
+func (s *SecuredStore) Bind(ctx context.Context, id *authctx.Identity, tenant, subject, role string) error {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbUpdate); err != nil {
+		return err
+	}
+	return s.store.Bind(ctx, tenant, subject, role)
+}
+
+// Unbind removes the binding, requiring update on auth.roles.
+func (s *SecuredStore) Unbind(ctx context.Context, id *authctx.Identity, tenant, subject, role string) error {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbUpdate); err != nil {
+		return err
+	}
+	return s.store.Unbind(ctx, tenant, subject, role)
+}
+
+// ListBindings returns the role bindings, requiring list on auth.roles.
+func (s *SecuredStore) ListBindings(ctx context.Context, id *authctx.Identity, tenant, subject string) ([]*RoleBinding, error) {
+	if err := Check(ctx, s.authz, id, ResourceRoles, VerbList); err != nil {
+		return nil, err
+	}
+	return s.store.ListBindings(ctx, tenant, subject)
+}
@@ -0,0 +1,185 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rbac
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+/*
+The Store holds the roles and the role bindings, and evaluates the
+permissions of the identities against them, implementing the Authorizer.
+
+# Usage
+
+    store, _ := rbac.NewStore(dbStore)
+    _ = store.AddRole(ctx, &rbac.Role{
+        Key:   &rbac.RoleKey{Name: "librarian"},
+        Rules: []*rbac.Rule{{Resources: []string{"books"}, Verbs: []string{"*"}}},
+    })
+    _ = store.Bind(ctx, "acme", "alice", "librarian")
+
+    policy, _ := store.LoadPolicy(ctx, "acme")
+    ok := policy.Evaluate("alice", "books", "create")
+
+    // enforcing the resource and verb of the route
+    err := r.Authorize(ctx, store, identity)
+*/
+
+// Store holds the roles and role bindings.
+type Store struct {
+	roles    *table.Table[RoleKey, Role]
+	bindings *table.Table[RoleBindingKey, RoleBinding]
+}
+
+// NewStore creates the role and role binding tables in the database
+// supplied by the consumer.
+func NewStore(store db.Store) (*Store, error) {
+	if store == nil {
+		return nil, errors.Wrap(errors.InvalidArgument, "rbac: db store is required")
+	}
+	roles := &table.Table[RoleKey, Role]{}
+	if err := roles.Initialize(store.GetCollection(RolesCollection)); err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "rbac: failed to initialize role table: %s", err)
+	}
+	bindings := &table.Table[RoleBindingKey, RoleBinding]{}
+	if err := bindings.Initialize(store.GetCollection(RoleBindingsCollection)); err != nil {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "rbac: failed to initialize role binding table: %s", err)
+	}
+	return &Store{
+		roles:    roles,
+		bindings: bindings,
+	}, nil
+}
+
+// AddRole adds the role, failing with AlreadyExists if already present.
+func (s *Store) AddRole(ctx context.Context, r *Role) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	if _, err := s.roles.Find(ctx, r.Key); err == nil {
+		return errors.Wrapf(errors.AlreadyExists, "role %s already exists", r.Key.Name)
+	}
+	return s.roles.Insert(ctx, r.Key, r)
+}
+
+// UpdateRole replaces the description and rules of an existing role.
+func (s *Store) UpdateRole(ctx context.Context, r *Role) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	if _, err := s.roles.Find(ctx, r.Key); err != nil {
+		return err
+	}
+	return s.roles.Locate(ctx, r.Key, r)
+}
+
+// DeleteRole deletes the role along with its bindings.
+func (s *Store) DeleteRole(ctx context.Context, key *RoleKey) error {
+	if err := s.roles.DeleteKey(ctx, key); err != nil {
+		return err
+	}
+	filter := bson.D{{Key: "_id.role", Value: key.Name}}
+	if key.Tenant != "" {
+		// bindings of global roles exist across tenancies
+		filter = append(filter, bson.E{Key: "_id.tenant", Value: key.Tenant})
+	}
+	if _, err := s.bindings.DeleteByFilter(ctx, filter); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// GetRole returns the role.
+func (s *Store) GetRole(ctx context.Context, key *RoleKey) (*Role, error) {
+	return s.roles.Find(ctx, key)
+}
+
+// ListRoles returns the roles available in the tenancy, i.e. its own and
+// the global ones.
+func (s *Store) ListRoles(ctx context.Context, tenant string) ([]*Role, error) {
+	filter := bson.D{{Key: "_id.tenant", Value: bson.D{{Key: "$in", Value: bson.A{"", tenant}}}}}
+	list, err := s.roles.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Bind binds the subject to the role within the tenancy, the role being
+// either of the tenancy or global.
+func (s *Store) Bind(ctx context.Context, tenant, subject, role string) error {
+	if subject == "" || role == "" {
+		return errors.Wrapf(errors.InvalidArgument, "subject and role are required")
+	}
+	if _, err := s.roles.Find(ctx, &RoleKey{Tenant: tenant, Name: role}); err != nil {
+		if _, err := s.roles.Find(ctx, &RoleKey{Name: role}); err != nil {
+			return errors.Wrapf(errors.NotFound, "role %s not found", role)
+		}
+	}
+	key := &RoleBindingKey{Tenant: tenant, Subject: subject, Role: role}
+	return s.bindings.Locate(ctx, key, &RoleBinding{Key: key, Created: time.Now().Unix()})
+}
+
+// Unbind removes the binding of the subject to the role.
+func (s *Store) Unbind(ctx context.Context, tenant, subject, role string) error {
+	return s.bindings.DeleteKey(ctx, &RoleBindingKey{Tenant: tenant, Subject: subject, Role: role})
+}
+
+// ListBindings returns the role bindings of the tenancy, of only the
+// subject if not empty.
+func (s *Store) ListBindings(ctx context.Context, tenant, subject string) ([]*RoleBinding, error) {
+	filter := bson.D{{Key: "_id.tenant", Value: tenant}}
+	if subject != "" {
+		filter = append(filter, bson.E{Key: "_id.subject", Value: subject})
+	}
+	list, err := s.bindings.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return list, nil
+}
+
+// LoadPolicy returns the snapshot of the roles and role bindings of the
+// tenancy.
+func (s *Store) LoadPolicy(ctx context.Context, tenant string) (*Policy, error) {
+	roles, err := s.ListRoles(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := s.ListBindings(ctx, tenant, "")
+	if err != nil {
+		return nil, err
+	}
+	return NewPolicy(tenant, roles, bindings), nil
+}
+
+// Authorize implements the Authorizer, evaluating the roles bound to the
+// subject within its tenancy.
+func (s *Store) Authorize(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+	if id == nil || id.Subject == "" {
+		return errors.Wrapf(errors.Unauthorized, "caller identity not available")
+	}
+	roles, err := s.ListRoles(ctx, id.Tenant)
+	if err != nil {
+		return err
+	}
+	bindings, err := s.ListBindings(ctx, id.Tenant, id.Subject)
+	if err != nil {
+		return err
+	}
+	if !NewPolicy(id.Tenant, roles, bindings).Evaluate(id.Subject, resource, verb) {
+		return errors.Wrapf(errors.Forbidden, "%s is not allowed to %s %s", id.Subject, verb, resource)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rotation
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	coreerrors "github.com/go-core-stack/core/errors"
+)
+
+/*
+Package rotation automates the rotation of API key secrets as per rotation
+policies per key class.
+
+Every API key has one or more generations of its secret. The scheduler
+creates the next generation of a secret ahead of time, once the current
+generation enters the rotation window before reaching its maximum age, and
+notifies the owner so the new secret can be rolled out. Both generations
+remain valid during the grace period, after which the old generation is
+revoked automatically, closing the loop left open by manual rotation.
+
+The keys are managed through the KeyStore interface, implemented by the
+store of the API keys.
+
+# Usage
+
+    scheduler := rotation.NewScheduler(store, notify,
+        rotation.Policy{Class: "service", MaxAge: 90 * 24 * time.Hour,
+            RotationWindow: 14 * 24 * time.Hour, GracePeriod: 7 * 24 * time.Hour})
+    go scheduler.Run(ctx, time.Hour, func(err error) { log.Println(err) })
+
+    // dropping the secrets cached by the servers of the same process
+    scheduler := rotation.NewScheduler(store, rotation.InvalidateOnRotation(resolver, notify), policies...)
+*/
+
+// Policy is the rotation policy of a class of keys.
+type Policy struct {
+	// class of keys the policy applies to
+	Class string
+
+	// maximum age of a secret generation
+	MaxAge time.Duration
+
+	// duration before reaching the maximum age, in which the next
+	// generation is created
+	RotationWindow time.Duration
+
+	// duration for which the previous generation remains valid once the
+	// next generation is created
+	GracePeriod time.Duration
+}
+
+// validate ensures the policy is consistent.
+func (p *Policy) validate() error {
+	if p.Class == "" {
+		return coreerrors.Wrapf(coreerrors.InvalidArgument, "rotation policy class not specified")
+	}
+	if p.MaxAge <= 0 || p.RotationWindow < 0 || p.RotationWindow >= p.MaxAge || p.GracePeriod < 0 {
+		return coreerrors.Wrapf(coreerrors.InvalidArgument, "invalid rotation policy for class %s", p.Class)
+	}
+	return nil
+}
+
+// Generation is a generation of the secret of an API key.
+type Generation struct {
+	// API key identifier shared by all the generations
+	KeyId string
+
+	// class and owner of the key
+	Class string
+	Owner string
+
+	// generation number, increasing with every rotation
+	Generation int32
+
+	// time the generation was created
+	Created time.Time
+}
+
+// KeyStore provides the secret generations of the API keys.
+type KeyStore interface {
+	// ListGenerations returns the valid generations of the keys of the
+	// class.
+	ListGenerations(ctx context.Context, class string) ([]*Generation, error)
+
+	// CreateGeneration creates the next generation of the secret of the
+	// key, following the given generation.
+	CreateGeneration(ctx context.Context, current *Generation) (*Generation, error)
+
+	// RevokeGeneration revokes the generation of the secret.
+	RevokeGeneration(ctx context.Context, gen *Generation) error
+}
+
+// EventType identifies a rotation event.
+type EventType string
+
+const (
+	// next generation created, to be rolled out by the owner
+	EventRotated EventType = "rotated"
+
+	// previous generation revoked after the grace period
+	EventRevoked EventType = "revoked"
+)
+
+// Event notifies the owner of a key about a rotation.
+type Event struct {
+	Type       EventType
+	Generation *Generation
+
+	// time the previous generation is revoked, for EventRotated
+	RevokeAt time.Time
+}
+
+// Notifier delivers the rotation events to the owners of the keys.
+type Notifier func(ctx context.Context, ev *Event)
+
+// Invalidator drops the cached secrets of a key, implemented by the
+// hash.CachingSecretResolver.
+type Invalidator interface {
+	Invalidate(keyId string)
+}
+
+// InvalidateOnRotation returns the notifier invalidating the cached
+// secrets of the keys on every rotation event before notifying next, if
+// not nil, so that revoked generations are not accepted from the cache.
+func InvalidateOnRotation(cache Invalidator, next Notifier) Notifier {
+	return func(ctx context.Context, ev *Event) {
+		cache.Invalidate(ev.Generation.KeyId)
+		if next != nil {
+			next(ctx, ev)
+		}
+	}
+}
+
+// Scheduler executes the rotation policies.
+type Scheduler struct {
+	store    KeyStore
+	notify   Notifier
+	policies []Policy
+	now      func() time.Time
+}
+
+// NewScheduler creates a scheduler rotating the keys of the store as per
+// the policies, notifying the events to notify if not nil.
+func NewScheduler(store KeyStore, notify Notifier, policies ...Policy) *Scheduler {
+	return &Scheduler{
+		store:    store,
+		notify:   notify,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// RunOnce executes the rotation policies once, creating the generations
+// due for rotation and revoking the generations past their grace period.
+// Keys failing to rotate do not prevent the rotation of the other keys,
+// the errors are returned joined.
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	var errs []error
+	for i := range s.policies {
+		p := &s.policies[i]
+		if err := p.validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		gens, err := s.store.ListGenerations(ctx, p.Class)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, key := range groupByKey(gens) {
+			if err := s.rotate(ctx, p, key); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// groupByKey groups the generations by key, ordered by generation.
+func groupByKey(gens []*Generation) [][]*Generation {
+	keys := map[string][]*Generation{}
+	ids := []string{}
+	for _, g := range gens {
+		if _, ok := keys[g.KeyId]; !ok {
+			ids = append(ids, g.KeyId)
+		}
+		keys[g.KeyId] = append(keys[g.KeyId], g)
+	}
+	sort.Strings(ids)
+	list := [][]*Generation{}
+	for _, id := range ids {
+		key := keys[id]
+		sort.Slice(key, func(i, j int) bool { return key[i].Generation < key[j].Generation })
+		list = append(list, key)
+	}
+	return list
+}
+
+// rotate applies the policy to the generations of a key.
+func (s *Scheduler) rotate(ctx context.Context, p *Policy, gens []*Generation) error {
+	now := s.now()
+	latest := gens[len(gens)-1]
+
+	// revoke the previous generations once the grace period since the
+	// creation of the latest one is over
+	for _, g := range gens[:len(gens)-1] {
+		if now.Before(latest.Created.Add(p.GracePeriod)) {
+			break
+		}
+		if err := s.store.RevokeGeneration(ctx, g); err != nil {
+			return err
+		}
+		s.emit(ctx, &Event{Type: EventRevoked, Generation: g})
+	}
+
+	// create the next generation once the latest one enters the rotation
+	// window
+	if now.Before(latest.Created.Add(p.MaxAge - p.RotationWindow)) {
+		return nil
+	}
+	next, err := s.store.CreateGeneration(ctx, latest)
+	if err != nil {
+		return err
+	}
+	s.emit(ctx, &Event{Type: EventRotated, Generation: next, RevokeAt: next.Created.Add(p.GracePeriod)})
+	return nil
+}
+
+// emit notifies the event if a notifier is configured.
+func (s *Scheduler) emit(ctx context.Context, ev *Event) {
+	if s.notify != nil {
+		s.notify(ctx, ev)
+	}
+}
+
+// Run executes the rotation policies at the given interval until the
+// context is done, failures are reported to onError if not nil.
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
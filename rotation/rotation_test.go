@@ -0,0 +1,118 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package rotation
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// memoryStore is an in memory KeyStore.
+type memoryStore struct {
+	gens []*Generation
+	now  func() time.Time
+}
+
+func (m *memoryStore) ListGenerations(ctx context.Context, class string) ([]*Generation, error) {
+	list := []*Generation{}
+	for _, g := range m.gens {
+		if g.Class == class {
+			list = append(list, g)
+		}
+	}
+	return list, nil
+}
+
+func (m *memoryStore) CreateGeneration(ctx context.Context, current *Generation) (*Generation, error) {
+	next := *current
+	next.Generation++
+	next.Created = m.now()
+	m.gens = append(m.gens, &next)
+	return &next, nil
+}
+
+func (m *memoryStore) RevokeGeneration(ctx context.Context, gen *Generation) error {
+	for i, g := range m.gens {
+		if g == gen {
+			m.gens = append(m.gens[:i], m.gens[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+	clock := func() time.Time { return now }
+	store := &memoryStore{now: clock}
+	store.gens = []*Generation{{KeyId: "key-1", Class: "service", Owner: "books", Generation: 1, Created: start}}
+
+	events := []EventType{}
+	s := NewScheduler(store, func(ctx context.Context, ev *Event) {
+		events = append(events, ev.Type)
+	}, Policy{Class: "service", MaxAge: 90 * day, RotationWindow: 14 * day, GracePeriod: 7 * day})
+	s.now = clock
+
+	// before the rotation window nothing happens
+	now = start.Add(70 * day)
+	if err := s.RunOnce(context.Background()); err != nil || len(store.gens) != 1 {
+		t.Fatalf("expected no rotation, got %d generations: %v", len(store.gens), err)
+	}
+
+	// the next generation is created in the rotation window
+	now = start.Add(77 * day)
+	if err := s.RunOnce(context.Background()); err != nil || len(store.gens) != 2 {
+		t.Fatalf("expected rotation, got %d generations: %v", len(store.gens), err)
+	}
+
+	// both generations remain valid in the grace period
+	now = start.Add(80 * day)
+	_ = s.RunOnce(context.Background())
+	if len(store.gens) != 2 {
+		t.Fatalf("expected both generations in grace period, got %d", len(store.gens))
+	}
+
+	// the old generation is revoked after the grace period
+	now = start.Add(85 * day)
+	_ = s.RunOnce(context.Background())
+	if len(store.gens) != 1 || store.gens[0].Generation != 2 {
+		t.Fatalf("expected only generation 2 to remain, got %+v", store.gens)
+	}
+	if len(events) != 2 || events[0] != EventRotated || events[1] != EventRevoked {
+		t.Errorf("unexpected events %v", events)
+	}
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	s := NewScheduler(&memoryStore{now: time.Now}, nil, Policy{Class: "service", MaxAge: time.Hour, RotationWindow: 2 * time.Hour})
+	if err := s.RunOnce(context.Background()); err == nil {
+		t.Errorf("expected invalid policy to be reported")
+	}
+}
+
+// invalidations records the invalidated key ids
+type invalidations []string
+
+func (i *invalidations) Invalidate(keyId string) {
+	*i = append(*i, keyId)
+}
+
+func TestInvalidateOnRotation(t *testing.T) {
+	cache := &invalidations{}
+	notified := 0
+	notify := InvalidateOnRotation(cache, func(ctx context.Context, ev *Event) {
+		notified++
+	})
+	notify(context.Background(), &Event{Type: EventRevoked, Generation: &Generation{KeyId: "key-1"}})
+	if len(*cache) != 1 || (*cache)[0] != "key-1" || notified != 1 {
+		t.Errorf("expected key-1 invalidated and notified, got %v, %d", *cache, notified)
+	}
+	InvalidateOnRotation(cache, nil)(context.Background(), &Event{Type: EventRotated, Generation: &Generation{KeyId: "key-2"}})
+	if len(*cache) != 2 {
+		t.Errorf("expected key-2 invalidated, got %v", *cache)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file spreads the requests of a route across the replicas of the
+service serving it. The Endpoint of the route is served along with the
+additional Endpoints of the route, and the Balancer picks one of them for
+every request, skipping the endpoints unhealthy as per the HealthTracker.
+
+# Usage
+
+    r := &route.Route{
+        Key:       &route.Key{Url: "/api/books/v1/books", Method: route.GET},
+        Endpoint:  "http://books-1:8080",
+        Endpoints: []string{"http://books-2:8080", "http://books-3:8080"},
+        Balance:   route.LeastFailures,
+    }
+
+    balancer := route.NewBalancer(route.RoundRobin, health)
+    endpoint, err := balancer.Pick(r, route.RequestedVersion(req))
+*/
+
+// BalanceStrategy selects the endpoint of a route among its replicas.
+type BalanceStrategy string
+
+const (
+	// RoundRobin cycles through the healthy endpoints
+	RoundRobin BalanceStrategy = "round-robin"
+
+	// LeastFailures prefers the healthy endpoints with the fewest
+	// consecutive failures, cycling through the ones with the same count
+	LeastFailures BalanceStrategy = "least-failures"
+)
+
+// validate ensures the strategy is known, the empty strategy being the
+// default of the balancer.
+func (s BalanceStrategy) validate() error {
+	switch s {
+	case "", RoundRobin, LeastFailures:
+		return nil
+	}
+	return errors.Wrapf(errors.InvalidArgument, "unknown balance strategy %q", s)
+}
+
+// AllEndpoints returns the Endpoint of the route followed by its additional
+// Endpoints, i.e. the replicas serving the default version.
+func (r *Route) AllEndpoints() []string {
+	endpoints := make([]string, 0, 1+len(r.Endpoints))
+	if r.Endpoint != "" {
+		endpoints = append(endpoints, r.Endpoint)
+	}
+	return append(endpoints, r.Endpoints...)
+}
+
+// EndpointsForVersion returns the endpoints serving the requested version
+// of the route, see EndpointForVersion, all the replicas serving the
+// default version.
+func (r *Route) EndpointsForVersion(version string) ([]string, error) {
+	endpoint, err := r.EndpointForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint != r.Endpoint {
+		return []string{endpoint}, nil
+	}
+	return r.AllEndpoints(), nil
+}
+
+// Balancer picks the endpoint of a route for the requests, as per the
+// Balance strategy of the route or the default strategy of the balancer.
+// It is safe for concurrent use.
+type Balancer struct {
+	strategy BalanceStrategy
+	health   *HealthTracker
+
+	mu   sync.Mutex
+	next map[string]uint64
+}
+
+// NewBalancer creates the balancer with the default strategy, RoundRobin
+// if empty, skipping the endpoints unhealthy as per the tracker, if any.
+func NewBalancer(strategy BalanceStrategy, health *HealthTracker) *Balancer {
+	if strategy == "" {
+		strategy = RoundRobin
+	}
+	return &Balancer{
+		strategy: strategy,
+		health:   health,
+		next:     map[string]uint64{},
+	}
+}
+
+// Pick returns the endpoint serving the requested version of the route,
+// failing with NotFound if the version is not available, and with
+// ErrNoHealthyEndpoint if none of its endpoints is healthy.
+func (b *Balancer) Pick(r *Route, version string) (string, error) {
+	endpoints, err := r.EndpointsForVersion(version)
+	if err != nil {
+		return "", err
+	}
+	strategy := r.Balance
+	if strategy == "" {
+		strategy = b.strategy
+	}
+	key := ""
+	if r.Key != nil {
+		key = fmt.Sprintf("%s %s %s %s", r.Key.Tenant, r.Key.Method, r.Key.Url, version)
+	}
+	return b.pick(key, endpoints, strategy)
+}
+
+// pick selects one of the endpoints of the pool identified by the key.
+func (b *Balancer) pick(key string, endpoints []string, strategy BalanceStrategy) (string, error) {
+	candidates := make([]string, 0, len(endpoints))
+	var fewest int32 = -1
+	for _, endpoint := range endpoints {
+		if b.health == nil {
+			candidates = append(candidates, endpoint)
+			continue
+		}
+		if !b.health.Healthy(endpoint) {
+			continue
+		}
+		if strategy == LeastFailures {
+			failures := b.health.Health(endpoint).Failures
+			if fewest >= 0 && failures > fewest {
+				continue
+			}
+			if failures < fewest {
+				candidates = candidates[:0]
+			}
+			fewest = failures
+		}
+		candidates = append(candidates, endpoint)
+	}
+	switch len(candidates) {
+	case 0:
+		return "", ErrNoHealthyEndpoint
+	case 1:
+		return candidates[0], nil
+	}
+	b.mu.Lock()
+	n := b.next[key]
+	b.next[key] = n + 1
+	b.mu.Unlock()
+	return candidates[n%uint64(len(candidates))], nil
+}
@@ -0,0 +1,110 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func newReplicatedRoute() *Route {
+	return &Route{
+		Key:       &Key{Url: "/books"},
+		Endpoint:  "http://books-1:8080",
+		Endpoints: []string{"http://books-2:8080", "http://books-3:8080"},
+	}
+}
+
+func TestBalancerRoundRobin(t *testing.T) {
+	health := NewHealthTracker(HealthPolicy{FailureThreshold: 1})
+	b := NewBalancer("", health)
+	r := newReplicatedRoute()
+
+	counts := map[string]int{}
+	for range 6 {
+		endpoint, err := b.Pick(r, "")
+		if err != nil {
+			t.Fatalf("failed to pick endpoint: %s", err)
+		}
+		counts[endpoint]++
+	}
+	for _, endpoint := range r.AllEndpoints() {
+		if counts[endpoint] != 2 {
+			t.Errorf("expected 2 requests to %s, got %d", endpoint, counts[endpoint])
+		}
+	}
+
+	// unhealthy endpoints are skipped
+	health.ReportFailure("http://books-2:8080", nil)
+	for range 4 {
+		if endpoint, _ := b.Pick(r, ""); endpoint == "http://books-2:8080" {
+			t.Errorf("expected unhealthy endpoint to be skipped")
+		}
+	}
+	for _, endpoint := range r.AllEndpoints() {
+		health.ReportFailure(endpoint, nil)
+	}
+	if _, err := b.Pick(r, ""); err != ErrNoHealthyEndpoint {
+		t.Errorf("expected ErrNoHealthyEndpoint, got %v", err)
+	}
+}
+
+func TestBalancerLeastFailures(t *testing.T) {
+	health := NewHealthTracker(HealthPolicy{FailureThreshold: 5})
+	b := NewBalancer(RoundRobin, health)
+	r := newReplicatedRoute()
+	r.Balance = LeastFailures
+
+	health.ReportFailure("http://books-1:8080", nil)
+	health.ReportFailure("http://books-1:8080", nil)
+	health.ReportFailure("http://books-3:8080", nil)
+	for range 3 {
+		if endpoint, _ := b.Pick(r, ""); endpoint != "http://books-2:8080" {
+			t.Errorf("expected endpoint with fewest failures, got %s", endpoint)
+		}
+	}
+	health.ReportFailure("http://books-2:8080", nil)
+	seen := map[string]bool{}
+	for range 4 {
+		endpoint, _ := b.Pick(r, "")
+		seen[endpoint] = true
+	}
+	if len(seen) != 2 || seen["http://books-1:8080"] {
+		t.Errorf("expected requests spread across endpoints with fewest failures, got %v", seen)
+	}
+}
+
+func TestBalancerVersions(t *testing.T) {
+	b := NewBalancer(RoundRobin, nil)
+	r := newReplicatedRoute()
+	r.DefaultVersion = "v1"
+	r.Versions = []*VersionedEndpoint{{Version: "v2", Endpoint: "http://books-v2:8080"}}
+
+	if endpoint, _ := b.Pick(r, "v2"); endpoint != "http://books-v2:8080" {
+		t.Errorf("expected endpoint of v2, got %s", endpoint)
+	}
+	if endpoint, _ := b.Pick(r, "v1"); endpoint == "http://books-v2:8080" {
+		t.Errorf("expected replica of the default version, got %s", endpoint)
+	}
+	if _, err := b.Pick(r, "v3"); !errors.IsNotFound(err) {
+		t.Errorf("expected NotFound for unknown version, got %v", err)
+	}
+}
+
+func TestRouteValidateEndpoints(t *testing.T) {
+	r := newReplicatedRoute()
+	if err := r.Validate(); err != nil {
+		t.Errorf("expected valid route, got %s", err)
+	}
+	r.Endpoints = append(r.Endpoints, "books-4:8080")
+	if err := r.Validate(); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected invalid endpoint, got %v", err)
+	}
+	r = newReplicatedRoute()
+	r.Balance = "random"
+	if err := r.Validate(); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected invalid balance strategy, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file detects the conflicts between the routes while they are
+registered, complementing the precedence model of the resolver.
+
+A request is resolved to the route of the tenant, falling back to the
+shared routes, for its method with:
+
+ 1. the url exactly equal to the request path, then
+ 2. the path template matching the request path with the most specific
+    segments, compared from left to right, where a literal segment takes
+    precedence over a {param} segment, which in turn takes precedence over
+    the trailing /* wildcard.
+
+e.g. GET /api/v1/items/special is resolved to the route registered for
+/api/v1/items/special rather than /api/v1/items/{id}, which in turn takes
+precedence over /api/v1/*.
+
+The precedence leaves two kinds of overlapping routes, rejected with a
+*ConflictError when registered:
+
+  - ambiguous routes, templates equally specific for the same paths, e.g.
+    /items/{id} and /items/{name}, where the choice would be arbitrary
+  - routes overlapping with the routes of a different provider, e.g. the
+    provider of /api/v1/items/{id} shadowed for some of its paths by
+    another provider registering /api/v1/items/special
+*/
+
+// ConflictError is returned when a route being registered conflicts with
+// a registered route of the same method and tenant.
+type ConflictError struct {
+	// key of the route being registered
+	Key *Key
+
+	// key of the conflicting route
+	Existing *Key
+
+	// provider owning the conflicting route, if any
+	Provider string
+
+	// set if the routes are equally specific, otherwise the routes
+	// overlap with the routes of different providers
+	Ambiguous bool
+}
+
+// Error returns the description of the conflict.
+func (e *ConflictError) Error() string {
+	if e.Ambiguous {
+		return fmt.Sprintf("route %s %s is ambiguous with route %s", e.Key.Method, e.Key.Url, e.Existing.Url)
+	}
+	return fmt.Sprintf("route %s %s overlaps with route %s of provider %s",
+		e.Key.Method, e.Key.Url, e.Existing.Url, e.Provider)
+}
+
+// IsConflict reports whether the error is a *ConflictError.
+func IsConflict(err error) bool {
+	_, ok := err.(*ConflictError)
+	return ok
+}
+
+// Overlaps reports whether some request path is matched by both the route
+// urls, exact paths or templates, see MatchPath.
+func Overlaps(a, b string) bool {
+	aSegs := strings.Split(strings.TrimPrefix(a, "/"), "/")
+	bSegs := strings.Split(strings.TrimPrefix(b, "/"), "/")
+	for i := 0; i < len(aSegs) && i < len(bSegs); i++ {
+		ka, kb := segmentKind(aSegs[i]), segmentKind(bSegs[i])
+		switch {
+		case ka == wildcardSegment || kb == wildcardSegment:
+			return true
+		case ka == literalSegment && kb == literalSegment && aSegs[i] != bSegs[i]:
+			return false
+		case ka == paramSegment && bSegs[i] == "", kb == paramSegment && aSegs[i] == "":
+			// a parameter never matches an empty segment
+			return false
+		}
+	}
+	if len(aSegs) == len(bSegs) {
+		return true
+	}
+	// the longer one matches the paths of the shorter one only if its
+	// next segment is a wildcard, matching an empty remainder
+	longer := aSegs
+	if len(bSegs) > len(aSegs) {
+		longer = bSegs
+	}
+	n := min(len(aSegs), len(bSegs))
+	return len(longer) == n+1 && segmentKind(longer[n]) == wildcardSegment
+}
+
+// ambiguous reports whether the distinct route urls are templates equally
+// specific for the same paths, i.e. differing only by the names of their
+// parameters.
+func ambiguous(a, b string) bool {
+	if a == b {
+		return false
+	}
+	aSegs := strings.Split(strings.TrimPrefix(a, "/"), "/")
+	bSegs := strings.Split(strings.TrimPrefix(b, "/"), "/")
+	if len(aSegs) != len(bSegs) {
+		return false
+	}
+	for i := range aSegs {
+		ka, kb := segmentKind(aSegs[i]), segmentKind(bSegs[i])
+		if ka != kb || (ka == literalSegment && aSegs[i] != bSegs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConflict returns the *ConflictError of the route with the first
+// conflicting route among the registered ones, routes with the same key
+// being the route itself.
+func checkConflict(r *Route, registered []*Route) error {
+	for _, e := range registered {
+		if e == nil || e.Key == nil || *e.Key == *r.Key {
+			continue
+		}
+		if e.Key.Method != r.Key.Method || e.Key.Tenant != r.Key.Tenant {
+			continue
+		}
+		if ambiguous(r.Key.Url, e.Key.Url) {
+			return &ConflictError{Key: r.Key, Existing: e.Key, Provider: e.Provider, Ambiguous: true}
+		}
+		if r.Provider != "" && e.Provider != "" && r.Provider != e.Provider && Overlaps(r.Key.Url, e.Key.Url) {
+			return &ConflictError{Key: r.Key, Existing: e.Key, Provider: e.Provider}
+		}
+	}
+	return nil
+}
+
+// checkConflict returns the *ConflictError of the route with the routes
+// registered for its tenant, if any.
+func (t *RouteTable) checkConflict(ctx context.Context, r *Route) error {
+	registered, err := t.ListRoutes(ctx, &RouteFilter{Tenant: &r.Key.Tenant})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return checkConflict(r, registered)
+}
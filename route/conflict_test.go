@@ -0,0 +1,83 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import "testing"
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		overlap bool
+	}{
+		{"/api/v1/items/{id}", "/api/v1/items/special", true},
+		{"/api/v1/items/{id}", "/api/v1/items/{name}", true},
+		{"/api/v1/items/{id}", "/api/v1/orders/{id}", false},
+		{"/api/v1/items/{id}", "/api/v1/items/{id}/parts", false},
+		{"/api/v1/*", "/api/v1/items/{id}/parts", true},
+		{"/api/v1/items/*", "/api/v1/items", true},
+		{"/api/v1/items/*", "/api/v2/items", false},
+		{"/a/{x}/c", "/a/b/{y}", true},
+		{"/a/{x}", "/a/", false},
+		{"/books", "/books", true},
+	}
+	for _, tc := range tests {
+		if got := Overlaps(tc.a, tc.b); got != tc.overlap {
+			t.Errorf("Overlaps(%s, %s) = %v, expected %v", tc.a, tc.b, got, tc.overlap)
+		}
+		if got := Overlaps(tc.b, tc.a); got != tc.overlap {
+			t.Errorf("Overlaps(%s, %s) = %v, expected %v", tc.b, tc.a, got, tc.overlap)
+		}
+	}
+}
+
+func TestCheckConflict(t *testing.T) {
+	registered := []*Route{
+		{Key: &Key{Url: "/api/v1/items/{id}", Method: GET}, Provider: "items"},
+		{Key: &Key{Url: "/api/v1/orders/{id}", Method: GET}},
+		{Key: &Key{Url: "/api/v1/items/{id}", Method: GET, Tenant: "acme"}, Provider: "acme"},
+	}
+	tests := []struct {
+		route     *Route
+		conflict  bool
+		ambiguous bool
+	}{
+		// the route itself, e.g. being updated
+		{&Route{Key: &Key{Url: "/api/v1/items/{id}", Method: GET}, Provider: "items"}, false, false},
+		// resolved by precedence for the same provider or without one
+		{&Route{Key: &Key{Url: "/api/v1/items/special", Method: GET}, Provider: "items"}, false, false},
+		{&Route{Key: &Key{Url: "/api/v1/items/special", Method: GET}}, false, false},
+		{&Route{Key: &Key{Url: "/api/v1/items/special", Method: POST}, Provider: "other"}, false, false},
+		// shadowing the route of another provider
+		{&Route{Key: &Key{Url: "/api/v1/items/special", Method: GET}, Provider: "other"}, true, false},
+		{&Route{Key: &Key{Url: "/api/*", Method: GET}, Provider: "other"}, true, false},
+		// equally specific, regardless of the provider
+		{&Route{Key: &Key{Url: "/api/v1/orders/{orderId}", Method: GET}}, true, true},
+		{&Route{Key: &Key{Url: "/api/v1/items/{itemId}", Method: GET}, Provider: "items"}, true, true},
+		// routes of other tenants don't conflict
+		{&Route{Key: &Key{Url: "/api/v1/orders/{orderId}", Method: GET, Tenant: "acme"}}, false, false},
+	}
+	for i, tc := range tests {
+		err := checkConflict(tc.route, registered)
+		if IsConflict(err) != tc.conflict {
+			t.Errorf("case %d: expected conflict %v, got %v", i, tc.conflict, err)
+			continue
+		}
+		if tc.conflict && err.(*ConflictError).Ambiguous != tc.ambiguous {
+			t.Errorf("case %d: expected ambiguous %v, got %v", i, tc.ambiguous, err)
+		}
+	}
+
+	// imported routes are checked against the routes registered once
+	// imported
+	if _, err := planImport(registered, []*Route{
+		{Key: &Key{Url: "/api/v1/orders/{orderId}", Method: GET}, Endpoint: "http://orders"},
+	}, &ImportOptions{}); !IsConflict(err) {
+		t.Errorf("expected the import of an ambiguous route to conflict, got %v", err)
+	}
+	if _, err := planImport(registered, []*Route{
+		{Key: &Key{Url: "/api/v1/orders/{orderId}", Method: GET}, Endpoint: "http://orders"},
+	}, &ImportOptions{Prune: true}); err != nil {
+		t.Errorf("expected the import replacing the route not to conflict, got %v", err)
+	}
+}
@@ -8,7 +8,15 @@ const (
 	ServicesDatabaseName = "services"
 )
 
+const (
+	// root tenancy, whose callers reach the routes of all the tenants
+	RootTenant = "root"
+)
+
 const (
 	// Routes collection name
 	RoutesCollectionName = "routes"
+
+	// Route providers collection name
+	RouteProvidersCollectionName = "route-providers"
 )
@@ -0,0 +1,291 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/labels"
+)
+
+// ValidateKey ensures the route key carries a url path and a known method.
+func ValidateKey(key *Key) error {
+	if key == nil || key.Url == "" {
+		return errors.Wrapf(errors.InvalidArgument, "route url not specified")
+	}
+	if !strings.HasPrefix(key.Url, "/") {
+		return errors.Wrapf(errors.InvalidArgument, "route url %s is not an absolute path", key.Url)
+	}
+	if key.Method < 0 || int(key.Method) >= len(methodNames) {
+		return errors.Wrapf(errors.InvalidArgument, "unknown route method %d", key.Method)
+	}
+	return nil
+}
+
+// validateEndpoint ensures the endpoint is an absolute http(s) url.
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return errors.Wrapf(errors.InvalidArgument, "invalid route endpoint %s: %s", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.Wrapf(errors.InvalidArgument, "route endpoint %s is not an http(s) url", endpoint)
+	}
+	return nil
+}
+
+// Validate ensures the route has a valid key, endpoints and labels.
+func (r *Route) Validate() error {
+	if err := ValidateKey(r.Key); err != nil {
+		return err
+	}
+	if r.Endpoint == "" {
+		return errors.Wrapf(errors.InvalidArgument, "route endpoint not specified")
+	}
+	if err := validateEndpoint(r.Endpoint); err != nil {
+		return err
+	}
+	for _, endpoint := range r.Endpoints {
+		if err := validateEndpoint(endpoint); err != nil {
+			return err
+		}
+	}
+	if err := r.Balance.validate(); err != nil {
+		return err
+	}
+	for _, v := range r.Versions {
+		if v == nil || v.Version == "" {
+			return errors.Wrapf(errors.InvalidArgument, "route version not specified")
+		}
+		if err := validateEndpoint(v.Endpoint); err != nil {
+			return err
+		}
+	}
+	if err := r.Labels.Validate(); err != nil {
+		return errors.Wrapf(errors.InvalidArgument, "invalid route labels: %s", err)
+	}
+	return nil
+}
+
+// RouteFilter selects the routes listed by ListRoutes, empty fields match
+// every route.
+type RouteFilter struct {
+	// routes with the url, or the url prefix if ending with "*"
+	Url string
+
+	// routes of the tenant, the empty tenant selecting the shared routes
+	Tenant *string
+
+	// routes owned by the provider
+	Provider string
+
+	// routes proxied to the endpoint, among their replicas
+	Endpoint string
+
+	// routes for the RBAC resource and verb
+	Resource string
+	Verb     string
+
+	// routes with the public and root flags set or unset
+	IsPublic *bool
+	IsRoot   *bool
+
+	// routes whose labels satisfy the selector
+	Selector labels.Selector
+}
+
+// tenantFilter returns the filter for the routes of the tenant, where the
+// tenant of the shared routes is absent in the store.
+func tenantFilter(tenant string) bson.E {
+	if tenant == "" {
+		return bson.E{Key: "_id.tenant", Value: bson.M{"$exists": false}}
+	}
+	return bson.E{Key: "_id.tenant", Value: tenant}
+}
+
+// flagFilter returns the filter for a boolean flag, where an unset flag is
+// absent in the store.
+func flagFilter(field string, v bool) bson.E {
+	if v {
+		return bson.E{Key: field, Value: true}
+	}
+	return bson.E{Key: field, Value: bson.M{"$ne": true}}
+}
+
+// bson returns the store filter of the route filter.
+func (f *RouteFilter) bson() bson.D {
+	filter := bson.D{}
+	if f == nil {
+		return filter
+	}
+	if prefix, ok := strings.CutSuffix(f.Url, "*"); ok {
+		filter = append(filter, bson.E{Key: "_id.url", Value: bson.Regex{Pattern: "^" + regexp.QuoteMeta(prefix)}})
+	} else if f.Url != "" {
+		filter = append(filter, bson.E{Key: "_id.url", Value: f.Url})
+	}
+	if f.Tenant != nil {
+		filter = append(filter, tenantFilter(*f.Tenant))
+	}
+	if f.Provider != "" {
+		filter = append(filter, bson.E{Key: "provider", Value: f.Provider})
+	}
+	if f.Endpoint != "" {
+		filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+			bson.M{"endpoint": f.Endpoint},
+			bson.M{"endpoints": f.Endpoint},
+		}})
+	}
+	if f.Resource != "" {
+		filter = append(filter, bson.E{Key: "resource", Value: f.Resource})
+	}
+	if f.Verb != "" {
+		filter = append(filter, bson.E{Key: "verb", Value: f.Verb})
+	}
+	if f.IsPublic != nil {
+		filter = append(filter, flagFilter("isPublic", *f.IsPublic))
+	}
+	if f.IsRoot != nil {
+		filter = append(filter, flagFilter("isRoot", *f.IsRoot))
+	}
+	return append(filter, f.Selector.Filter("labels")...)
+}
+
+// SetPublisher makes the route table publish the events of the routes
+// added, updated and deleted through its methods, and of the inventories
+// synced by the providers.
+func (t *RouteTable) SetPublisher(p events.Publisher) {
+	t.events = p
+}
+
+// publish publishes the event of the route, if enabled, failures being
+// ignored as the change is already applied.
+func (t *RouteTable) publish(ctx context.Context, kind events.Kind, key *Key) {
+	if t.events != nil {
+		_ = t.events.Publish(ctx, &events.Event{Kind: kind, Tenant: key.Tenant, Resource: key.Method.String() + " " + key.Url})
+	}
+}
+
+// AddRoute validates and adds the route, failing with AlreadyExists if a
+// route with the same key exists, and with a *ConflictError if it
+// conflicts with a registered route.
+func (t *RouteTable) AddRoute(ctx context.Context, r *Route) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if _, err := t.Find(ctx, r.Key); err == nil {
+		return errors.Wrapf(errors.AlreadyExists, "route %s %s already exists", r.Key.Method, r.Key.Url)
+	}
+	if err := t.checkConflict(ctx, r); err != nil {
+		return err
+	}
+	r.Modified = time.Now().UnixMilli()
+	if err := t.Insert(ctx, r.Key, r); err != nil {
+		return err
+	}
+	t.publish(ctx, events.KindRouteAdded, r.Key)
+	return nil
+}
+
+// UpdateRoute validates and updates the existing route, failing with
+// NotFound if the route doesn't exist, and with a *ConflictError if it
+// conflicts with a registered route.
+func (t *RouteTable) UpdateRoute(ctx context.Context, r *Route) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if err := t.checkConflict(ctx, r); err != nil {
+		return err
+	}
+	r.Modified = time.Now().UnixMilli()
+	if err := t.Update(ctx, r.Key, r); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.Wrapf(errors.NotFound, "route %s %s not found", r.Key.Method, r.Key.Url)
+		}
+		return err
+	}
+	t.publish(ctx, events.KindRouteUpdated, r.Key)
+	return nil
+}
+
+// DeleteRoute deletes the route, failing with NotFound if the route
+// doesn't exist.
+func (t *RouteTable) DeleteRoute(ctx context.Context, key *Key) error {
+	if err := ValidateKey(key); err != nil {
+		return err
+	}
+	if err := t.DeleteKey(ctx, key); err != nil {
+		if errors.IsNotFound(err) {
+			return errors.Wrapf(errors.NotFound, "route %s %s not found", key.Method, key.Url)
+		}
+		return err
+	}
+	t.publish(ctx, events.KindRouteDeleted, key)
+	return nil
+}
+
+// GetRoute returns the route, failing with NotFound if the route doesn't
+// exist.
+func (t *RouteTable) GetRoute(ctx context.Context, key *Key) (*Route, error) {
+	if err := ValidateKey(key); err != nil {
+		return nil, err
+	}
+	r, err := t.Find(ctx, key)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, errors.Wrapf(errors.NotFound, "route %s %s not found", key.Method, key.Url)
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// ListRoutes returns the routes matching the filter, a nil filter lists
+// all the routes.
+func (t *RouteTable) ListRoutes(ctx context.Context, filter *RouteFilter) ([]*Route, error) {
+	return t.FindMany(ctx, filter.bson(), 0, 0)
+}
+
+// RouteList is a page of the routes matching a filter.
+type RouteList struct {
+	// routes of the page
+	Routes []*Route
+
+	// total number of routes matching the filter
+	Total int64
+}
+
+// QueryRoutes returns the page of the routes matching the filter, ordered
+// by url and method, skipping offset routes and returning up to limit
+// routes, a zero limit returns all the remaining routes.
+func (t *RouteTable) QueryRoutes(ctx context.Context, filter *RouteFilter, offset, limit int32) (*RouteList, error) {
+	if offset < 0 || limit < 0 {
+		return nil, errors.Wrapf(errors.InvalidArgument, "invalid offset %d or limit %d", offset, limit)
+	}
+	f := filter.bson()
+	total, err := t.Count(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	routes, err := t.FindManyWithOpts(ctx, f,
+		table.WithOffset(offset),
+		table.WithLimit(limit),
+		table.WithSort(
+			table.SortOption{Field: "_id.url", Direction: table.SortAscending},
+			table.SortOption{Field: "_id.method", Direction: table.SortAscending},
+		))
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return &RouteList{Routes: routes, Total: total}, nil
+}
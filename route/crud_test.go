@@ -0,0 +1,103 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/labels"
+	"github.com/go-core-stack/auth/storage"
+)
+
+func TestRouteValidate(t *testing.T) {
+	tests := []struct {
+		route *Route
+		valid bool
+	}{
+		{&Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "http://books:8080"}, true},
+		{&Route{Key: &Key{Url: "/books", Method: POST}, Endpoint: "https://books.example.com/api"}, true},
+		{&Route{Key: &Key{Url: "", Method: GET}, Endpoint: "http://books:8080"}, false},
+		{&Route{Key: &Key{Url: "books", Method: GET}, Endpoint: "http://books:8080"}, false},
+		{&Route{Key: &Key{Url: "/books", Method: MethodType(42)}, Endpoint: "http://books:8080"}, false},
+		{&Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "books:8080"}, false},
+		{&Route{Key: &Key{Url: "/books", Method: GET}}, false},
+		{&Route{Endpoint: "http://books:8080"}, false},
+		{&Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "http://books:8080",
+			Versions: []*VersionedEndpoint{{Version: "v2", Endpoint: "ftp://books"}}}, false},
+		{&Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "http://books:8080",
+			Labels: labels.Labels{"bad key!": "x"}}, false},
+	}
+	for i, tc := range tests {
+		err := tc.route.Validate()
+		if tc.valid && err != nil {
+			t.Errorf("case %d: unexpected error: %s", i, err)
+		}
+		if !tc.valid && !errors.IsInvalidArgument(err) {
+			t.Errorf("case %d: expected invalid argument error, got %v", i, err)
+		}
+	}
+}
+
+func TestRouteListFilter(t *testing.T) {
+	if f := (*RouteFilter)(nil).bson(); len(f) != 0 {
+		t.Errorf("expected nil filter to match all routes, got %v", f)
+	}
+	f := (&RouteFilter{Url: "/api/v1.0/*", Provider: "books"}).bson()
+	if len(f) != 2 || f[0].Value.(bson.Regex).Pattern != `^/api/v1\.0/` || f[1].Value != "books" {
+		t.Errorf("unexpected filter %v", f)
+	}
+
+	// unset flags are absent in the store
+	public, root := true, false
+	f = (&RouteFilter{Resource: "books", Verb: "get", IsPublic: &public, IsRoot: &root}).bson()
+	if len(f) != 4 || f[2].Value != true || f[3].Value.(bson.M)["$ne"] != true {
+		t.Errorf("unexpected filter %v", f)
+	}
+}
+
+func TestRouteTableStorage(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewRouteTableWithStorage(storage.NewMemoryTable[Key, Route]())
+	other := NewRouteTableWithStorage(storage.NewMemoryTable[Key, Route]())
+
+	books := &Route{Key: &Key{Url: "/books/{id}", Method: GET}, Endpoint: "http://books:8080"}
+	if err := tbl.AddRoute(ctx, books); err != nil {
+		t.Fatalf("failed to add route: %s", err)
+	}
+	if err := tbl.AddRoute(ctx, books); !errors.IsAlreadyExists(err) {
+		t.Errorf("expected the duplicate route to fail, got %v", err)
+	}
+	if r, err := tbl.ResolveRoute(ctx, GET, "/books/42"); err != nil || r.Endpoint != books.Endpoint {
+		t.Errorf("expected the template route to be resolved, got %v: %v", r, err)
+	}
+	if methods, err := tbl.GetAllowedMethods(ctx, "/books/{id}"); err != nil || len(methods) != 1 || methods[0] != GET {
+		t.Errorf("unexpected allowed methods %v: %v", methods, err)
+	}
+	if _, err := other.ResolveRoute(ctx, GET, "/books/42"); !errors.IsNotFound(err) {
+		t.Errorf("expected the tables to be independent, got %v", err)
+	}
+
+	inventory := []Route{
+		{Key: &Key{Url: "/authors", Method: GET}, Endpoint: "http://authors:8080"},
+		{Key: &Key{Url: "/authors/{id}", Method: DELETE}, Endpoint: "http://authors:8080"},
+	}
+	if err := tbl.SyncRoutes(ctx, "authors", inventory); err != nil {
+		t.Fatalf("failed to sync routes: %s", err)
+	}
+	if err := tbl.SyncRoutes(ctx, "authors", inventory[:1]); err != nil {
+		t.Fatalf("failed to sync routes: %s", err)
+	}
+	routes, err := tbl.ListRoutes(ctx, &RouteFilter{Provider: "authors"})
+	if err != nil || len(routes) != 1 || routes[0].Key.Url != "/authors" {
+		t.Errorf("expected the stale route to be removed, got %v: %v", routes, err)
+	}
+	if routes, _ := tbl.ListRoutes(ctx, nil); len(routes) != 2 {
+		t.Errorf("expected the routes of other providers to be kept, got %d routes", len(routes))
+	}
+}
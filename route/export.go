@@ -0,0 +1,333 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file provides the import and export of the route inventory as JSON or
+YAML documents, so that the routes, including their RBAC fields, are
+bootstrapped and managed declaratively from version controlled files.
+
+The document lists the routes under "routes", with the field names of the
+Route, and the same structure in both the formats:
+
+    routes:
+      - Key:
+          Url: /api/books/v1/books
+          Method: GET
+        Endpoint: http://books:8080
+        Resource: books
+        Verb: list
+
+# Usage
+
+    // export the current inventory
+    err := routes.ExportRoutes(ctx, os.Stdout, route.FormatYAML)
+
+    // reconcile the table with the file, removing the routes absent in it
+    res, err := routes.ImportRoutes(ctx, file, route.FormatYAML, &route.ImportOptions{Prune: true})
+*/
+
+// Format is the serialization format of the route documents.
+type Format string
+
+const (
+	// FormatJSON serializes the routes as JSON.
+	FormatJSON Format = "json"
+
+	// FormatYAML serializes the routes as YAML.
+	FormatYAML Format = "yaml"
+)
+
+// routeDocument is the document holding the route inventory.
+type routeDocument struct {
+	Routes []*Route `json:"routes"`
+}
+
+// sortRoutes orders the routes by tenant, url and method, for the
+// exported documents to diff cleanly.
+func sortRoutes(routes []*Route) {
+	sort.Slice(routes, func(i, j int) bool {
+		a, b := routes[i].Key, routes[j].Key
+		if a.Tenant != b.Tenant {
+			return a.Tenant < b.Tenant
+		}
+		if a.Url != b.Url {
+			return a.Url < b.Url
+		}
+		return a.Method < b.Method
+	})
+}
+
+// yamlValue converts the numbers of a JSON value decoded with UseNumber
+// to integers where possible, so that they are not emitted as floats in
+// YAML.
+func yamlValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = yamlValue(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = yamlValue(e)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return v
+}
+
+// WriteRoutes writes the routes as a document of the format, ordered by
+// tenant, url and method. YAML documents are converted from JSON, for the
+// field names and the encoding of the methods to be the same.
+func WriteRoutes(w io.Writer, format Format, routes []*Route) error {
+	sorted := make([]*Route, 0, len(routes))
+	for _, r := range routes {
+		if r != nil && r.Key != nil {
+			sorted = append(sorted, r)
+		}
+	}
+	sortRoutes(sorted)
+	data, err := json.MarshalIndent(&routeDocument{Routes: sorted}, "", "  ")
+	if err != nil {
+		return errors.Wrapf(errors.InvalidArgument, "failed to encode routes: %s", err)
+	}
+	switch format {
+	case FormatJSON:
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatYAML:
+		var doc any
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return errors.Wrapf(errors.Unknown, "failed to convert routes: %s", err)
+		}
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(yamlValue(doc)); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return errors.Wrapf(errors.InvalidArgument, "unknown route format %q", format)
+}
+
+// ReadRoutes reads the routes of a document of the format, validating
+// them and ensuring that no route is listed twice.
+func ReadRoutes(r io.Reader, format Format) ([]*Route, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, errors.Wrapf(errors.InvalidArgument, "invalid route document: %s", err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, errors.Wrapf(errors.InvalidArgument, "invalid route document: %s", err)
+		}
+	default:
+		return nil, errors.Wrapf(errors.InvalidArgument, "unknown route format %q", format)
+	}
+
+	doc := &routeDocument{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(doc); err != nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "invalid route document: %s", err)
+	}
+	seen := map[Key]bool{}
+	for _, route := range doc.Routes {
+		if route == nil {
+			return nil, errors.Wrapf(errors.InvalidArgument, "empty route in document")
+		}
+		if err := route.Validate(); err != nil {
+			return nil, err
+		}
+		if seen[*route.Key] {
+			return nil, errors.Wrapf(errors.InvalidArgument, "route %s %s listed more than once",
+				route.Key.Method, route.Key.Url)
+		}
+		seen[*route.Key] = true
+	}
+	return doc.Routes, nil
+}
+
+// ImportOptions configures the reconciliation of the imported routes.
+type ImportOptions struct {
+	// Provider owning the imported routes, overriding the provider of the
+	// routes in the document. Routes owned by other providers are not
+	// taken over, and pruning is restricted to the routes of the provider
+	Provider string
+
+	// Prune removes the routes absent in the document
+	Prune bool
+
+	// DryRun computes the changes without applying them
+	DryRun bool
+}
+
+// ImportResult lists the keys of the routes changed by an import.
+type ImportResult struct {
+	Created   []*Key
+	Updated   []*Key
+	Unchanged []*Key
+	Deleted   []*Key
+}
+
+// importPlan is the set of changes reconciling the existing routes with
+// the imported ones.
+type importPlan struct {
+	create []*Route
+	update []*Route
+	delete []*Key
+	result *ImportResult
+}
+
+// planImport computes the changes reconciling the existing routes with the
+// imported ones, failing with Forbidden if an imported route is owned by
+// a different provider than the one of the options, and with a
+// *ConflictError if a created or updated route conflicts with the routes
+// registered once imported.
+func planImport(existing, routes []*Route, opts *ImportOptions) (*importPlan, error) {
+	current := map[Key]*Route{}
+	for _, r := range existing {
+		current[*r.Key] = r
+	}
+	plan := &importPlan{result: &ImportResult{}}
+	imported := map[Key]bool{}
+	for _, r := range routes {
+		entry := *r
+		if opts.Provider != "" {
+			entry.Provider = opts.Provider
+		}
+		imported[*entry.Key] = true
+		old, ok := current[*entry.Key]
+		switch {
+		case !ok:
+			plan.create = append(plan.create, &entry)
+			plan.result.Created = append(plan.result.Created, entry.Key)
+		case opts.Provider != "" && old.Provider != "" && old.Provider != opts.Provider:
+			return nil, errors.Wrapf(errors.Forbidden, "route %s %s is owned by provider %s",
+				entry.Key.Method, entry.Key.Url, old.Provider)
+		case reflect.DeepEqual(old, &entry):
+			plan.result.Unchanged = append(plan.result.Unchanged, entry.Key)
+		default:
+			plan.update = append(plan.update, &entry)
+			plan.result.Updated = append(plan.result.Updated, entry.Key)
+		}
+	}
+	if opts.Prune {
+		for _, r := range existing {
+			if imported[*r.Key] || (opts.Provider != "" && r.Provider != opts.Provider) {
+				continue
+			}
+			plan.delete = append(plan.delete, r.Key)
+			plan.result.Deleted = append(plan.result.Deleted, r.Key)
+		}
+	}
+
+	// the routes registered once imported, for the conflict detection
+	changed := slices.Concat(plan.create, plan.update)
+	replaced := map[Key]bool{}
+	for _, r := range changed {
+		replaced[*r.Key] = true
+	}
+	for _, key := range plan.delete {
+		replaced[*key] = true
+	}
+	registered := slices.Clone(changed)
+	for _, r := range existing {
+		if !replaced[*r.Key] {
+			registered = append(registered, r)
+		}
+	}
+	for _, r := range changed {
+		if err := checkConflict(r, registered); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+// ExportRoutes writes all the routes of the table, including their RBAC
+// fields, as a document of the format.
+func (t *RouteTable) ExportRoutes(ctx context.Context, w io.Writer, format Format) error {
+	if t.Table == nil {
+		return errors.Wrapf(errors.InvalidArgument, "route table not initialized")
+	}
+	routes, err := t.ListRoutes(ctx, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return WriteRoutes(w, format, routes)
+}
+
+// ImportRoutes reconciles the table with the routes of a document of the
+// format, creating the missing routes, updating the changed ones and, with
+// Prune, removing the routes absent in the document. The document is
+// validated as a whole before any change is applied. A nil opts imports
+// the routes without pruning.
+func (t *RouteTable) ImportRoutes(ctx context.Context, r io.Reader, format Format, opts *ImportOptions) (*ImportResult, error) {
+	if t.Table == nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "route table not initialized")
+	}
+	if opts == nil {
+		opts = &ImportOptions{}
+	}
+	routes, err := ReadRoutes(r, format)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := t.ListRoutes(ctx, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	plan, err := planImport(existing, routes, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return plan.result, nil
+	}
+
+	for _, entry := range plan.create {
+		if err := t.Insert(ctx, entry.Key, entry); err != nil {
+			return nil, errors.Wrapf(errors.GetErrCode(err), "failed to create route %s %s: %s", entry.Key.Method, entry.Key.Url, err)
+		}
+	}
+	for _, entry := range plan.update {
+		if err := t.Update(ctx, entry.Key, entry); err != nil {
+			return nil, errors.Wrapf(errors.GetErrCode(err), "failed to update route %s %s: %s", entry.Key.Method, entry.Key.Url, err)
+		}
+	}
+	for _, key := range plan.delete {
+		if err := t.DeleteKey(ctx, key); err != nil && !errors.IsNotFound(err) {
+			return nil, errors.Wrapf(errors.GetErrCode(err), "failed to delete route %s %s: %s", key.Method, key.Url, err)
+		}
+	}
+	return plan.result, nil
+}
@@ -0,0 +1,105 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/labels"
+)
+
+func testInventory() []*Route {
+	public := true
+	return []*Route{
+		{Key: &Key{Url: "/api/books/v1/books", Method: POST}, Endpoint: "http://books:8080",
+			Resource: "books", Verb: "create", Scopes: []string{"ou"}, Labels: labels.Labels{"team": "library"},
+			Upstream: &UpstreamPolicy{Timeout: 5 * time.Second, Retries: 2}},
+		{Key: &Key{Url: "/api/books/v1/books", Method: GET}, Endpoint: "http://books:8080",
+			Resource: "books", Verb: "list"},
+		{Key: &Key{Url: "/healthz", Method: GET, Tenant: "acme"}, Endpoint: "http://books:8080", IsPublic: &public},
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		buf := &bytes.Buffer{}
+		if err := WriteRoutes(buf, format, testInventory()); err != nil {
+			t.Fatalf("%s: failed to write routes: %s", format, err)
+		}
+		if format == FormatYAML && !strings.Contains(buf.String(), "Method: GET") ||
+			strings.Contains(buf.String(), "e+09") {
+			t.Errorf("%s: unexpected document\n%s", format, buf.String())
+		}
+		routes, err := ReadRoutes(buf, format)
+		if err != nil {
+			t.Fatalf("%s: failed to read routes: %s", format, err)
+		}
+		expected := testInventory()
+		sortRoutes(expected)
+		if !reflect.DeepEqual(routes, expected) {
+			t.Errorf("%s: routes changed by the round trip", format)
+		}
+	}
+
+	invalid := []string{
+		"routes: [{Key: {Url: books, Method: GET}, Endpoint: http://books}]",
+		"routes: [{Key: {Url: /books, Method: FETCH}, Endpoint: http://books}]",
+		"routes: [{Key: {Url: /books}, Endpoint: http://books, Unknown: x}]",
+		"routes: [{Key: {Url: /books}, Endpoint: http://books}, {Key: {Url: /books}, Endpoint: http://other}]",
+	}
+	for _, doc := range invalid {
+		if _, err := ReadRoutes(strings.NewReader(doc), FormatYAML); !errors.IsInvalidArgument(err) {
+			t.Errorf("expected invalid argument for %q, got %v", doc, err)
+		}
+	}
+	if _, err := ReadRoutes(strings.NewReader("{}"), Format("toml")); !errors.IsInvalidArgument(err) {
+		t.Errorf("expected unknown format to be rejected, got %v", err)
+	}
+}
+
+func TestPlanImport(t *testing.T) {
+	existing := testInventory()
+	existing[1].Provider = "books"
+	existing = append(existing, &Route{Key: &Key{Url: "/api/orders", Method: GET}, Endpoint: "http://orders", Provider: "orders"})
+
+	routes := testInventory()[:2]
+	routes[0].Verb = "add"
+	routes = append(routes, &Route{Key: &Key{Url: "/api/books/v1/books", Method: DELETE}, Endpoint: "http://books:8080"})
+
+	plan, err := planImport(existing, routes, &ImportOptions{Prune: true})
+	if err != nil {
+		t.Fatalf("failed to plan import: %s", err)
+	}
+	res := plan.result
+	if len(res.Created) != 1 || res.Created[0].Method != DELETE {
+		t.Errorf("unexpected created routes %v", res.Created)
+	}
+	// the provider of the existing route differs from the document
+	if len(res.Updated) != 2 || len(res.Unchanged) != 0 {
+		t.Errorf("unexpected updated routes %v, unchanged %v", res.Updated, res.Unchanged)
+	}
+	if len(res.Deleted) != 2 {
+		t.Errorf("expected the routes absent in the document to be deleted, got %v", res.Deleted)
+	}
+
+	// pruning is restricted to the routes of the provider
+	plan, err = planImport(existing, routes[1:2], &ImportOptions{Provider: "books", Prune: true})
+	if err != nil {
+		t.Fatalf("failed to plan import: %s", err)
+	}
+	if len(plan.result.Unchanged) != 1 || len(plan.result.Deleted) != 0 {
+		t.Errorf("unexpected plan %+v", plan.result)
+	}
+
+	// routes of other providers are not taken over
+	if _, err := planImport(existing, routes[1:2], &ImportOptions{Provider: "library"}); !errors.IsForbidden(err) {
+		t.Errorf("expected forbidden for a route of another provider, got %v", err)
+	}
+}
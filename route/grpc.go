@@ -0,0 +1,98 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// gRPC routes are keyed by the full method name of the RPC, i.e.
+// "/package.Service/Method", as the url along with the POST method, which
+// is the HTTP method used by every gRPC call, allowing the same lookup and
+// RBAC enforcement as REST routes for gRPC services behind the gateway.
+
+// IsGrpcRequest reports whether the HTTP request carries a gRPC call.
+func IsGrpcRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
+
+// ParseGrpcMethod splits the full method name of a gRPC call into the fully
+// qualified service name and the method name.
+func ParseGrpcMethod(fullMethod string) (service, method string, err error) {
+	name, ok := strings.CutPrefix(fullMethod, "/")
+	if ok {
+		service, method, ok = strings.Cut(name, "/")
+	}
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", errors.Wrapf(errors.InvalidArgument, "invalid grpc method: %s", fullMethod)
+	}
+	return service, method, nil
+}
+
+// GrpcRouteKey returns the route key for the full method name of a gRPC
+// call.
+func GrpcRouteKey(fullMethod string) *Key {
+	return &Key{
+		Url:    fullMethod,
+		Method: POST,
+	}
+}
+
+// grpcVerbPrefixes maps the conventional RPC method name prefixes to RBAC
+// verbs
+var grpcVerbPrefixes = []struct {
+	prefix string
+	verb   string
+}{
+	{"Get", "get"},
+	{"List", "list"},
+	{"Watch", "watch"},
+	{"Create", "create"},
+	{"Update", "update"},
+	{"Patch", "patch"},
+	{"Delete", "delete"},
+}
+
+// GrpcResourceVerb derives the RBAC resource and verb of a gRPC method, as
+// per the conventional naming of RPCs. The resource is the lower cased
+// service name, without package and "Service" suffix, and the verb is
+// derived from the method name prefix, e.g. "/api.v1.BookService/ListBooks"
+// maps to resource "book" and verb "list". Methods not following the
+// convention map to the lower cased method name as verb.
+func GrpcResourceVerb(fullMethod string) (resource, verb string, err error) {
+	service, method, err := ParseGrpcMethod(fullMethod)
+	if err != nil {
+		return "", "", err
+	}
+	if i := strings.LastIndex(service, "."); i >= 0 {
+		service = service[i+1:]
+	}
+	resource = strings.ToLower(strings.TrimSuffix(service, "Service"))
+	for _, p := range grpcVerbPrefixes {
+		if strings.HasPrefix(method, p.prefix) {
+			return resource, p.verb, nil
+		}
+	}
+	return resource, strings.ToLower(method), nil
+}
+
+// NewGrpcRoute creates a route for the gRPC method served by the endpoint,
+// with the resource and verb derived from the method name.
+func NewGrpcRoute(fullMethod, endpoint string) (*Route, error) {
+	resource, verb, err := GrpcResourceVerb(fullMethod)
+	if err != nil {
+		return nil, err
+	}
+	isGrpc := true
+	return &Route{
+		Key:      GrpcRouteKey(fullMethod),
+		Endpoint: endpoint,
+		IsGrpc:   &isGrpc,
+		Resource: resource,
+		Verb:     verb,
+	}, nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func TestIsGrpcRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		protoMajor  int
+		contentType string
+		grpc        bool
+	}{
+		{"grpc over http2", 2, "application/grpc", true},
+		{"grpc with codec", 2, "application/grpc+proto", true},
+		{"grpc over http1", 1, "application/grpc", false},
+		{"json over http2", 2, "application/json", false},
+		{"no content type", 2, "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api.v1.BookService/GetBook", nil)
+			r.ProtoMajor = tc.protoMajor
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			if grpc := IsGrpcRequest(r); grpc != tc.grpc {
+				t.Errorf("expected grpc %v, got %v", tc.grpc, grpc)
+			}
+		})
+	}
+}
+
+func TestParseGrpcMethod(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		service    string
+		method     string
+		valid      bool
+	}{
+		{"/api.v1.BookService/GetBook", "api.v1.BookService", "GetBook", true},
+		{"/Health/Check", "Health", "Check", true},
+		{"api.v1.BookService/GetBook", "", "", false},
+		{"/api.v1.BookService", "", "", false},
+		{"/api.v1.BookService/", "", "", false},
+		{"//GetBook", "", "", false},
+		{"/api.v1.BookService/GetBook/extra", "", "", false},
+		{"", "", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.fullMethod, func(t *testing.T) {
+			service, method, err := ParseGrpcMethod(tc.fullMethod)
+			if !tc.valid {
+				if errors.GetErrCode(err) != errors.InvalidArgument {
+					t.Errorf("expected invalid argument, got %v", err)
+				}
+				return
+			}
+			if err != nil || service != tc.service || method != tc.method {
+				t.Errorf("expected %s %s, got %s %s, %v", tc.service, tc.method, service, method, err)
+			}
+		})
+	}
+}
+
+func TestGrpcResourceVerb(t *testing.T) {
+	tests := []struct {
+		fullMethod string
+		resource   string
+		verb       string
+	}{
+		{"/api.v1.BookService/GetBook", "book", "get"},
+		{"/api.v1.BookService/ListBooks", "book", "list"},
+		{"/api.v1.BookService/WatchBooks", "book", "watch"},
+		{"/api.v1.BookService/CreateBook", "book", "create"},
+		{"/api.v1.BookService/UpdateBook", "book", "update"},
+		{"/api.v1.BookService/PatchBook", "book", "patch"},
+		{"/api.v1.BookService/DeleteBook", "book", "delete"},
+		// methods not following the convention use the method name
+		{"/api.v1.BookService/Archive", "book", "archive"},
+		// services without package or Service suffix
+		{"/Library/GetShelf", "library", "get"},
+		{"/grpc.health.v1.Health/Check", "health", "check"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.fullMethod, func(t *testing.T) {
+			resource, verb, err := GrpcResourceVerb(tc.fullMethod)
+			if err != nil || resource != tc.resource || verb != tc.verb {
+				t.Errorf("expected %s %s, got %s %s, %v", tc.resource, tc.verb, resource, verb, err)
+			}
+		})
+	}
+	if _, _, err := GrpcResourceVerb("BookService.GetBook"); err == nil {
+		t.Errorf("expected an invalid method to fail")
+	}
+}
+
+func TestNewGrpcRoute(t *testing.T) {
+	r, err := NewGrpcRoute("/api.v1.BookService/ListBooks", "http://books:9090")
+	if err != nil {
+		t.Fatalf("failed to create route: %s", err)
+	}
+	if r.Key.Url != "/api.v1.BookService/ListBooks" || r.Key.Method != POST {
+		t.Errorf("expected the route keyed by the method with POST, got %+v", r.Key)
+	}
+	if r.Endpoint != "http://books:9090" || r.IsGrpc == nil || !*r.IsGrpc {
+		t.Errorf("expected a grpc route to the endpoint, got %+v", r)
+	}
+	if r.Resource != "book" || r.Verb != "list" {
+		t.Errorf("expected resource book and verb list, got %s %s", r.Resource, r.Verb)
+	}
+	if key := GrpcRouteKey("/api.v1.BookService/ListBooks"); *key != *r.Key {
+		t.Errorf("expected the route key %+v, got %+v", r.Key, key)
+	}
+	if _, err := NewGrpcRoute("/api.v1.BookService", "http://books:9090"); err == nil {
+		t.Errorf("expected an invalid method to fail")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+// DefaultStrippedResponseHeaders are the server identifying response
+// headers removed when a header policy enables StripServerHeaders
+var DefaultStrippedResponseHeaders = []string{
+	"Server",
+	"X-Powered-By",
+	"X-AspNet-Version",
+	"X-AspNetMvc-Version",
+}
+
+// HeaderPolicy describes the request and response header manipulation
+// performed by the gateway for a route.
+type HeaderPolicy struct {
+	// inbound request headers removed before proxying to the endpoint
+	StripRequestHeaders []string `bson:"stripRequestHeaders,omitempty"`
+
+	// request headers set before proxying to the endpoint
+	SetRequestHeaders map[string]string `bson:"setRequestHeaders,omitempty"`
+
+	// inject the auth info of the authenticated identity for the backend,
+	// any auth info header sent by the caller is always removed
+	InjectIdentity bool `bson:"injectIdentity,omitempty"`
+
+	// response headers removed before responding to the caller
+	StripResponseHeaders []string `bson:"stripResponseHeaders,omitempty"`
+
+	// remove the default server identifying response headers
+	StripServerHeaders bool `bson:"stripServerHeaders,omitempty"`
+}
+
+// ApplyRequest applies the policy to the request being proxied, where info
+// is the auth info of the authenticated identity, if any. Auth info headers
+// received from the caller are never forwarded to the backend.
+func (p *HeaderPolicy) ApplyRequest(r *http.Request, info *authctx.AuthInfo) error {
+	authctx.DeleteAuthInfoHeader(r)
+	if p == nil {
+		return nil
+	}
+	for _, h := range p.StripRequestHeaders {
+		r.Header.Del(h)
+	}
+	for h, v := range p.SetRequestHeaders {
+		r.Header.Set(h, v)
+	}
+	if p.InjectIdentity && info != nil {
+		return authctx.SetAuthInfoHeader(r, info)
+	}
+	return nil
+}
+
+// ApplyResponse applies the policy to the response headers received from
+// the backend.
+func (p *HeaderPolicy) ApplyResponse(h http.Header) {
+	if p == nil {
+		return
+	}
+	for _, name := range p.StripResponseHeaders {
+		h.Del(name)
+	}
+	if p.StripServerHeaders {
+		for _, name := range DefaultStrippedResponseHeaders {
+			h.Del(name)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authctx "github.com/go-core-stack/auth/context"
+)
+
+func TestHeaderPolicyApplyRequest(t *testing.T) {
+	alice := &authctx.AuthInfo{UserName: "alice", Roles: []string{"admin"}}
+	tests := []struct {
+		name   string
+		policy *HeaderPolicy
+		info   *authctx.AuthInfo
+		header map[string]string
+		want   map[string]string
+		user   string
+		absent []string
+	}{
+		{
+			name:   "no policy drops the caller auth info",
+			header: map[string]string{"X-Debug": "1"},
+			info:   alice,
+			want:   map[string]string{"X-Debug": "1"},
+		},
+		{
+			name:   "strips and sets the request headers",
+			policy: &HeaderPolicy{StripRequestHeaders: []string{"x-debug"}, SetRequestHeaders: map[string]string{"X-Env": "prod", "X-Trace": "on"}},
+			header: map[string]string{"X-Debug": "1", "X-Trace": "off"},
+			want:   map[string]string{"X-Env": "prod", "X-Trace": "on"},
+			absent: []string{"X-Debug"},
+		},
+		{
+			name:   "injects the identity of the caller",
+			policy: &HeaderPolicy{InjectIdentity: true},
+			info:   alice,
+			user:   "alice",
+		},
+		{
+			name:   "injects nothing without an identity",
+			policy: &HeaderPolicy{InjectIdentity: true},
+		},
+		{
+			name:   "does not inject the identity unless enabled",
+			policy: &HeaderPolicy{SetRequestHeaders: map[string]string{"X-Env": "prod"}},
+			info:   alice,
+			want:   map[string]string{"X-Env": "prod"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/books", nil)
+			for h, v := range tc.header {
+				r.Header.Set(h, v)
+			}
+			// the auth info forged by the caller is never forwarded
+			_ = authctx.SetAuthInfoHeader(r, &authctx.AuthInfo{UserName: "mallory", IsRoot: true})
+
+			if err := tc.policy.ApplyRequest(r, tc.info); err != nil {
+				t.Fatalf("failed to apply the policy: %s", err)
+			}
+			for h, v := range tc.want {
+				if got := r.Header.Get(h); got != v {
+					t.Errorf("expected %s: %q, got %q", h, v, got)
+				}
+			}
+			for _, h := range tc.absent {
+				if _, ok := r.Header[http.CanonicalHeaderKey(h)]; ok {
+					t.Errorf("expected %s to be stripped", h)
+				}
+			}
+			info, err := authctx.GetAuthInfoHeader(r)
+			switch {
+			case tc.user == "" && err == nil:
+				t.Errorf("expected no auth info, got %q", info.UserName)
+			case tc.user != "" && (err != nil || info.UserName != tc.user || info.IsRoot):
+				t.Errorf("expected the auth info of %s, got %+v, %v", tc.user, info, err)
+			}
+		})
+	}
+}
+
+func TestHeaderPolicyApplyResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *HeaderPolicy
+		kept   []string
+		absent []string
+	}{
+		{
+			name: "no policy keeps the headers",
+			kept: []string{"Server", "X-Powered-By", "X-Internal"},
+		},
+		{
+			name:   "strips the listed headers",
+			policy: &HeaderPolicy{StripResponseHeaders: []string{"x-internal"}},
+			kept:   []string{"Server", "X-Powered-By", "Content-Type"},
+			absent: []string{"X-Internal"},
+		},
+		{
+			name:   "strips the server identifying headers",
+			policy: &HeaderPolicy{StripServerHeaders: true},
+			kept:   []string{"X-Internal", "Content-Type"},
+			absent: []string{"Server", "X-Powered-By", "X-AspNet-Version"},
+		},
+		{
+			name:   "strips both",
+			policy: &HeaderPolicy{StripResponseHeaders: []string{"X-Internal"}, StripServerHeaders: true},
+			kept:   []string{"Content-Type"},
+			absent: []string{"Server", "X-Powered-By", "X-Internal"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			for _, name := range []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-Internal", "Content-Type"} {
+				h.Set(name, "value")
+			}
+			tc.policy.ApplyResponse(h)
+			for _, name := range tc.kept {
+				if h.Get(name) == "" {
+					t.Errorf("expected %s to be kept", name)
+				}
+			}
+			for _, name := range tc.absent {
+				if h.Get(name) != "" {
+					t.Errorf("expected %s to be stripped", name)
+				}
+			}
+		})
+	}
+}
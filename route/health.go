@@ -0,0 +1,376 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file tracks the health of the endpoints serving the routes, so that
+the requests are not proxied to dead endpoints. The HealthTracker marks an
+endpoint unhealthy after consecutive failures, reported passively by the
+gateway proxying the requests or actively by the HealthChecker probing the
+endpoints, and lets a request through again once the recovery interval
+elapsed since its last failure to detect the recovered endpoints.
+
+The state changes are recorded alongside the route providers, so that
+FindAlive skips the unhealthy instances, and the route table resolves the
+routes to ErrNoHealthyEndpoint while all their endpoints are unhealthy.
+
+# Usage
+
+    health := route.NewHealthTracker(route.DefaultHealthPolicy())
+    health.OnChange(providers.RecordHealth)
+    routes.SetHealthTracker(health)
+
+    // passive tracking by the gateway
+    gw, _ := gateway.New(auth, routes, gateway.WithHealthTracker(health))
+
+    // active checks of the endpoints of the providers
+    checker := route.NewHealthChecker(health, route.HealthCheck{Path: "/healthz"}, providers.Endpoints)
+    go checker.Run(ctx)
+*/
+
+// ErrNoHealthyEndpoint is returned while resolving a route whose endpoints
+// are all unhealthy.
+var ErrNoHealthyEndpoint = errors.New("no healthy endpoint for route")
+
+// HealthStatus is the health of an endpoint.
+type HealthStatus string
+
+const (
+	// Healthy endpoints receive requests
+	Healthy HealthStatus = "healthy"
+
+	// Unhealthy endpoints receive no requests until the recovery
+	// interval elapsed
+	Unhealthy HealthStatus = "unhealthy"
+)
+
+// EndpointHealth is the health state of an endpoint.
+type EndpointHealth struct {
+	Status HealthStatus `bson:"status,omitempty"`
+
+	// consecutive failures observed
+	Failures int32 `bson:"failures,omitempty"`
+
+	// time of the last change of the status, unix seconds
+	Since int64 `bson:"since,omitempty"`
+
+	// last failure observed, if any
+	LastError string `bson:"lastError,omitempty"`
+}
+
+// HealthPolicy configures when the endpoints are considered unhealthy.
+type HealthPolicy struct {
+	// consecutive failures marking an endpoint unhealthy
+	FailureThreshold int
+
+	// duration after the last failure of an unhealthy endpoint for which
+	// it receives no requests, the next successful request marking it
+	// healthy again
+	RecoveryInterval time.Duration
+}
+
+// DefaultHealthPolicy marks the endpoints unhealthy after 3 consecutive
+// failures, retrying them every 10 seconds.
+func DefaultHealthPolicy() HealthPolicy {
+	return HealthPolicy{
+		FailureThreshold: 3,
+		RecoveryInterval: 10 * time.Second,
+	}
+}
+
+// endpointState is the tracked state of an endpoint.
+type endpointState struct {
+	health EndpointHealth
+
+	// time from which an unhealthy endpoint receives requests again
+	retry time.Time
+}
+
+// HealthTracker tracks the health of the endpoints in memory, the
+// endpoints never reported being healthy.
+type HealthTracker struct {
+	policy    HealthPolicy
+	mu        sync.Mutex
+	endpoints map[string]*endpointState
+	onChange  []func(endpoint string, health EndpointHealth)
+	now       func() time.Time
+}
+
+// NewHealthTracker creates the tracker of the endpoint health as per the
+// policy.
+func NewHealthTracker(p HealthPolicy) *HealthTracker {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = DefaultHealthPolicy().FailureThreshold
+	}
+	if p.RecoveryInterval <= 0 {
+		p.RecoveryInterval = DefaultHealthPolicy().RecoveryInterval
+	}
+	return &HealthTracker{
+		policy:    p,
+		endpoints: map[string]*endpointState{},
+		now:       time.Now,
+	}
+}
+
+// OnChange registers the callback invoked whenever the status of an
+// endpoint changes, e.g. RouteProviderTable.RecordHealth. It is invoked
+// synchronously, outside the lock of the tracker.
+func (h *HealthTracker) OnChange(fn func(endpoint string, health EndpointHealth)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onChange = append(h.onChange, fn)
+}
+
+// state returns the state of the endpoint, created if unknown.
+func (h *HealthTracker) state(endpoint string) *endpointState {
+	s, ok := h.endpoints[endpoint]
+	if !ok {
+		s = &endpointState{health: EndpointHealth{Status: Healthy}}
+		h.endpoints[endpoint] = s
+	}
+	return s
+}
+
+// notify invokes the callbacks of a status change.
+func (h *HealthTracker) notify(changed bool, endpoint string, health EndpointHealth) {
+	if !changed {
+		return
+	}
+	h.mu.Lock()
+	callbacks := h.onChange
+	h.mu.Unlock()
+	for _, fn := range callbacks {
+		fn(endpoint, health)
+	}
+}
+
+// ReportSuccess records a successful request to the endpoint, marking it
+// healthy.
+func (h *HealthTracker) ReportSuccess(endpoint string) {
+	h.mu.Lock()
+	s := h.state(endpoint)
+	changed := s.health.Status != Healthy
+	s.health.Failures = 0
+	s.health.LastError = ""
+	if changed {
+		s.health.Status = Healthy
+		s.health.Since = h.now().Unix()
+	}
+	health := s.health
+	h.mu.Unlock()
+	h.notify(changed, endpoint, health)
+}
+
+// ReportFailure records a failed request to the endpoint, marking it
+// unhealthy once the failure threshold is reached.
+func (h *HealthTracker) ReportFailure(endpoint string, err error) {
+	h.mu.Lock()
+	now := h.now()
+	s := h.state(endpoint)
+	s.health.Failures++
+	if err != nil {
+		s.health.LastError = err.Error()
+	}
+	changed := false
+	if int(s.health.Failures) >= h.policy.FailureThreshold {
+		changed = s.health.Status != Unhealthy
+		if changed {
+			s.health.Status = Unhealthy
+			s.health.Since = now.Unix()
+		}
+		s.retry = now.Add(h.policy.RecoveryInterval)
+	}
+	health := s.health
+	h.mu.Unlock()
+	h.notify(changed, endpoint, health)
+}
+
+// Healthy reports whether the requests may be sent to the endpoint. An
+// unhealthy endpoint receives requests again once the recovery interval
+// elapsed since its last failure, until the next failure reported.
+func (h *HealthTracker) Healthy(endpoint string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.endpoints[endpoint]
+	return !ok || s.health.Status == Healthy || !h.now().Before(s.retry)
+}
+
+// Health returns the health of the endpoint.
+func (h *HealthTracker) Health(endpoint string) EndpointHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.endpoints[endpoint]; ok {
+		return s.health
+	}
+	return EndpointHealth{Status: Healthy}
+}
+
+// HealthCheck configures the active health checks of the endpoints.
+type HealthCheck struct {
+	// path probed on the endpoints, e.g. "/healthz", any status below
+	// 500 is considered healthy
+	Path string
+
+	// interval between the probes of an endpoint, 10 seconds if unset
+	Interval time.Duration
+
+	// timeout of a probe, 2 seconds if unset
+	Timeout time.Duration
+}
+
+// HealthChecker probes the endpoints periodically, reporting the outcome
+// to the tracker.
+type HealthChecker struct {
+	tracker   *HealthTracker
+	check     HealthCheck
+	endpoints func(ctx context.Context) ([]string, error)
+	client    *http.Client
+}
+
+// NewHealthChecker creates the checker probing the endpoints returned by
+// the function, e.g. RouteProviderTable.Endpoints.
+func NewHealthChecker(tracker *HealthTracker, check HealthCheck, endpoints func(ctx context.Context) ([]string, error)) *HealthChecker {
+	if check.Interval <= 0 {
+		check.Interval = 10 * time.Second
+	}
+	if check.Timeout <= 0 {
+		check.Timeout = 2 * time.Second
+	}
+	return &HealthChecker{
+		tracker:   tracker,
+		check:     check,
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: check.Timeout},
+	}
+}
+
+// probe checks the health of the endpoint.
+func (c *HealthChecker) probe(ctx context.Context, endpoint string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+c.check.Path, nil)
+	if err != nil {
+		c.tracker.ReportFailure(endpoint, err)
+		return
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.tracker.ReportFailure(endpoint, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		c.tracker.ReportFailure(endpoint, errors.Wrapf(errors.Unknown, "health check returned %d", resp.StatusCode))
+		return
+	}
+	c.tracker.ReportSuccess(endpoint)
+}
+
+// CheckOnce probes all the endpoints concurrently, waiting for the probes
+// to complete.
+func (c *HealthChecker) CheckOnce(ctx context.Context) error {
+	endpoints, err := c.endpoints(ctx)
+	if err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.probe(ctx, endpoint)
+		}()
+	}
+	wg.Wait()
+	return nil
+}
+
+// Run probes the endpoints every interval until the context is done.
+func (c *HealthChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.check.Interval)
+	defer ticker.Stop()
+	for {
+		_ = c.CheckOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Endpoints returns the distinct endpoints of the instances with a valid
+// lease, for the HealthChecker.
+func (t *RouteProviderTable) Endpoints(ctx context.Context) ([]string, error) {
+	filter := bson.D{{Key: "leaseExpiry", Value: bson.M{"$gt": time.Now().Unix()}}}
+	providers, err := t.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	endpoints := []string{}
+	for _, p := range providers {
+		if p.Key != nil && !seen[p.Key.Endpoint] {
+			seen[p.Key.Endpoint] = true
+			endpoints = append(endpoints, p.Key.Endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+// SetHealth records the health of the instances serving the endpoint.
+func (t *RouteProviderTable) SetHealth(ctx context.Context, endpoint string, health EndpointHealth) error {
+	providers, err := t.FindMany(ctx, bson.D{{Key: "_id.endpoint", Value: endpoint}}, 0, 0)
+	if err != nil {
+		return err
+	}
+	for _, p := range providers {
+		if err := t.Update(ctx, p.Key, &RouteProvider{Health: &health}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordHealth is the HealthTracker.OnChange callback recording the
+// health of the instances serving the endpoint, failures are ignored as
+// the next change records the health again.
+func (t *RouteProviderTable) RecordHealth(endpoint string, health EndpointHealth) {
+	_ = t.SetHealth(context.Background(), endpoint, health)
+}
+
+// SetHealthTracker makes the route table resolve the routes whose
+// endpoints are all unhealthy as per the tracker to ErrNoHealthyEndpoint.
+func (t *RouteTable) SetHealthTracker(h *HealthTracker) {
+	t.health = h
+}
+
+// checkHealth returns the route unless all its endpoints are unhealthy.
+func (t *RouteTable) checkHealth(r *Route) (*Route, error) {
+	return checkHealth(t.health, r)
+}
+
+// checkHealth returns the route unless all its endpoints are unhealthy as
+// per the tracker, if any.
+func checkHealth(h *HealthTracker, r *Route) (*Route, error) {
+	if h == nil {
+		return r, nil
+	}
+	for _, endpoint := range r.AllEndpoints() {
+		if h.Healthy(endpoint) {
+			return r, nil
+		}
+	}
+	return nil, ErrNoHealthyEndpoint
+}
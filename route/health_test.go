@@ -0,0 +1,101 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func TestHealthTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	h := NewHealthTracker(HealthPolicy{FailureThreshold: 2, RecoveryInterval: 10 * time.Second})
+	h.now = func() time.Time { return now }
+	changes := []HealthStatus{}
+	h.OnChange(func(endpoint string, health EndpointHealth) {
+		changes = append(changes, health.Status)
+	})
+
+	const ep = "http://books:8080"
+	if !h.Healthy(ep) {
+		t.Fatalf("expected unknown endpoint to be healthy")
+	}
+	h.ReportFailure(ep, errors.New("connection refused"))
+	if !h.Healthy(ep) {
+		t.Errorf("expected endpoint healthy below the failure threshold")
+	}
+	h.ReportFailure(ep, errors.New("connection refused"))
+	if h.Healthy(ep) {
+		t.Errorf("expected endpoint unhealthy at the failure threshold")
+	}
+	if health := h.Health(ep); health.Status != Unhealthy || health.Failures != 2 || health.LastError != "connection refused" {
+		t.Errorf("unexpected health %+v", health)
+	}
+
+	// retried once the recovery interval elapsed
+	now = now.Add(10 * time.Second)
+	if !h.Healthy(ep) {
+		t.Errorf("expected endpoint retried after the recovery interval")
+	}
+	h.ReportFailure(ep, nil)
+	if h.Healthy(ep) {
+		t.Errorf("expected endpoint unhealthy after a failed retry")
+	}
+	h.ReportSuccess(ep)
+	if !h.Healthy(ep) || h.Health(ep).Failures != 0 {
+		t.Errorf("expected endpoint healthy after a success, got %+v", h.Health(ep))
+	}
+	if len(changes) != 2 || changes[0] != Unhealthy || changes[1] != Healthy {
+		t.Errorf("unexpected status changes %v", changes)
+	}
+}
+
+func TestHealthChecker(t *testing.T) {
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	h := NewHealthTracker(HealthPolicy{FailureThreshold: 1})
+	endpoints := func(ctx context.Context) ([]string, error) {
+		return []string{healthy.URL, failing.URL, "http://127.0.0.1:1"}, nil
+	}
+	c := NewHealthChecker(h, HealthCheck{Path: "/healthz"}, endpoints)
+	if err := c.CheckOnce(context.Background()); err != nil {
+		t.Fatalf("health check failed: %s", err)
+	}
+	if !h.Healthy(healthy.URL) {
+		t.Errorf("expected %s healthy, got %+v", healthy.URL, h.Health(healthy.URL))
+	}
+	for _, ep := range []string{failing.URL, "http://127.0.0.1:1"} {
+		if h.Healthy(ep) {
+			t.Errorf("expected %s unhealthy", ep)
+		}
+	}
+}
+
+func TestRouteTableCheckHealth(t *testing.T) {
+	h := NewHealthTracker(HealthPolicy{FailureThreshold: 1})
+	tbl := &RouteTable{}
+	tbl.SetHealthTracker(h)
+	r := &Route{Key: &Key{Url: "/books"}, Endpoint: "http://books:8080"}
+	if got, err := tbl.checkHealth(r); err != nil || got != r {
+		t.Errorf("expected route of healthy endpoint, got %v, %v", got, err)
+	}
+	h.ReportFailure(r.Endpoint, nil)
+	if _, err := tbl.checkHealth(r); err != ErrNoHealthyEndpoint {
+		t.Errorf("expected ErrNoHealthyEndpoint, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/client"
+	"github.com/go-core-stack/auth/hash"
+)
+
+/*
+This file provides the signed heartbeats exchanged between the route
+providers and the route registry. A provider periodically sends a heartbeat
+signed with its API key, covering the body using signature version v2, and
+the registry verifies the signature before extending the liveness of the
+provider, so that a spoofed heartbeat cannot keep a hijacked endpoint
+alive in the table.
+
+# Usage
+
+    // provider side
+    cli, _ := client.New(registry, client.WithCredentials(keyId, secret),
+        client.WithSigningOptions(hash.WithSignatureVersion(hash.SignatureV2)))
+    sender := route.NewHeartbeatSender(cli, "books-service", "http://books:8080")
+    go sender.Run(ctx, 10*time.Second, nil)
+
+    // registry side
+    verifier := route.NewHeartbeatVerifier(resolver, 60)
+    mux.Handle(route.HeartbeatPath, verifier.Handler(
+        func(ctx context.Context, keyId string, hb *route.Heartbeat) error {
+            // ensure keyId owns hb.Provider and extend its lease
+        }))
+*/
+
+// HeartbeatPath is the path of the registry receiving heartbeats
+const HeartbeatPath = "/v1/providers/heartbeat"
+
+// maxHeartbeatSize limits the size of the heartbeat body accepted by the
+// registry
+const maxHeartbeatSize = 16 * 1024
+
+// Heartbeat is the liveness ping sent by a route provider.
+type Heartbeat struct {
+	// provider sending the heartbeat
+	Provider string `json:"provider"`
+
+	// endpoint served by the provider
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// time the heartbeat was sent, unix seconds
+	Timestamp int64 `json:"timestamp"`
+}
+
+// HeartbeatSender sends the heartbeats of a provider to the registry,
+// signed by the client with the API key of the provider.
+type HeartbeatSender struct {
+	cli      client.Client
+	provider string
+	endpoint string
+}
+
+// NewHeartbeatSender creates a heartbeat sender for the provider serving
+// the endpoint, the client is expected to sign using signature version v2
+// so that the heartbeat body is covered by the signature.
+func NewHeartbeatSender(cli client.Client, provider, endpoint string) *HeartbeatSender {
+	return &HeartbeatSender{
+		cli:      cli,
+		provider: provider,
+		endpoint: endpoint,
+	}
+}
+
+// Send sends a single heartbeat.
+func (s *HeartbeatSender) Send(ctx context.Context) error {
+	hb := &Heartbeat{
+		Provider:  s.provider,
+		Endpoint:  s.endpoint,
+		Timestamp: time.Now().Unix(),
+	}
+	return s.cli.PostJSON(ctx, HeartbeatPath, hb, nil)
+}
+
+// Run sends heartbeats at the given interval until the context is done,
+// failures are reported to onError if not nil.
+func (s *HeartbeatSender) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Send(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// HeartbeatVerifier verifies the heartbeats received by the registry.
+type HeartbeatVerifier struct {
+	validator hash.Validator
+	resolver  hash.SecretResolver
+}
+
+// NewHeartbeatVerifier creates a verifier accepting heartbeats signed with
+// signature version v2 within validity seconds, with the secret of the
+// signing API key obtained from the resolver.
+func NewHeartbeatVerifier(resolver hash.SecretResolver, validity int64) *HeartbeatVerifier {
+	return &HeartbeatVerifier{
+		validator: hash.NewValidator(validity, hash.WithAllowedVersions(hash.SignatureV2)),
+		resolver:  resolver,
+	}
+}
+
+// Verify validates the signature of the heartbeat request, returning the
+// API key ID that signed it along with the heartbeat. The caller is
+// responsible for ensuring that the API key owns the provider.
+func (v *HeartbeatVerifier) Verify(r *http.Request) (string, *Heartbeat, error) {
+	keyId := v.validator.GetKeyId(r)
+	if keyId == "" {
+		return "", nil, errors.Wrapf(errors.Unauthorized, "heartbeat not signed")
+	}
+	secret, err := v.resolver.GetSecret(r.Context(), keyId)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil, errors.Wrapf(errors.Unauthorized, "unknown api key %s", keyId)
+		}
+		return "", nil, err
+	}
+	if r.Body != nil {
+		r.Body = io.NopCloser(io.LimitReader(r.Body, maxHeartbeatSize))
+	}
+	if ok, err := v.validator.Validate(r, secret); !ok {
+		return "", nil, errors.Wrapf(errors.Unauthorized, "invalid heartbeat signature: %s", err)
+	}
+
+	hb := &Heartbeat{}
+	if err := json.NewDecoder(r.Body).Decode(hb); err != nil {
+		return "", nil, errors.Wrapf(errors.InvalidArgument, "invalid heartbeat: %s", err)
+	}
+	if hb.Provider == "" {
+		return "", nil, errors.Wrapf(errors.InvalidArgument, "heartbeat provider not specified")
+	}
+	return keyId, hb, nil
+}
+
+// Handler returns the registry HTTP handler verifying heartbeats, and
+// invoking onBeat for the verified ones, which is expected to check the
+// ownership of the provider by the API key and to extend its liveness.
+func (v *HeartbeatVerifier) Handler(onBeat func(ctx context.Context, keyId string, hb *Heartbeat) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		keyId, hb, err := v.Verify(r)
+		if err == nil {
+			err = onBeat(r.Context(), keyId, hb)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), heartbeatStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// heartbeatStatus maps the heartbeat error to the HTTP status code
+func heartbeatStatus(err error) int {
+	switch {
+	case errors.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case errors.IsForbidden(err):
+		return http.StatusForbidden
+	case errors.IsInvalidArgument(err):
+		return http.StatusBadRequest
+	case errors.IsNotFound(err):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
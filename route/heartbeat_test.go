@@ -0,0 +1,61 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/client"
+	"github.com/go-core-stack/auth/hash"
+)
+
+func TestSignedHeartbeat(t *testing.T) {
+	resolver := hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		if keyId != "provider-key" {
+			return "", errors.Wrapf(errors.NotFound, "unknown key %s", keyId)
+		}
+		return "provider-secret", nil
+	})
+	beats := 0
+	verifier := NewHeartbeatVerifier(resolver, 60)
+	srv := httptest.NewServer(verifier.Handler(func(ctx context.Context, keyId string, hb *Heartbeat) error {
+		if keyId != "provider-key" || hb.Provider != "books" {
+			t.Errorf("unexpected heartbeat %+v from %s", hb, keyId)
+		}
+		beats++
+		return nil
+	}))
+	defer srv.Close()
+
+	v2 := client.WithSigningOptions(hash.WithSignatureVersion(hash.SignatureV2))
+	cli, _ := client.New(srv.URL, client.WithCredentials("provider-key", "provider-secret"), v2)
+	if err := NewHeartbeatSender(cli, "books", "http://books:8080").Send(context.Background()); err != nil {
+		t.Fatalf("failed to send heartbeat: %s", err)
+	}
+	if beats != 1 {
+		t.Errorf("expected heartbeat to be accepted")
+	}
+
+	// spoofed heartbeat signed with a wrong secret
+	cli, _ = client.New(srv.URL, client.WithCredentials("provider-key", "guessed"), v2)
+	err := NewHeartbeatSender(cli, "books", "http://evil:8080").Send(context.Background())
+	if !client.IsStatusError(err, http.StatusUnauthorized) {
+		t.Errorf("expected spoofed heartbeat to be rejected, got %v", err)
+	}
+
+	// heartbeat signed without body coverage
+	cli, _ = client.New(srv.URL, client.WithCredentials("provider-key", "provider-secret"))
+	err = NewHeartbeatSender(cli, "books", "http://books:8080").Send(context.Background())
+	if !client.IsStatusError(err, http.StatusUnauthorized) {
+		t.Errorf("expected v1 signed heartbeat to be rejected, got %v", err)
+	}
+	if beats != 1 {
+		t.Errorf("expected only a single heartbeat to be accepted, got %d", beats)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+/*
+This file provides the Index, an in-memory index of the routes resolving
+the requests without scanning the route templates, for the route sets too
+large for the linear match of the RouteTable, e.g. tens of thousands of
+routes.
+
+The routes are kept in a map by exact url, and the path templates in a
+tree of path segments per tenant and method, where every node has its
+literal children by segment, a single child for the {param} segments and
+the routes ending with the /* wildcard. The lookup walks the tree trying
+the literal child first, then the {param} child and the wildcard last, so
+that the first route found is the one the precedence model of conflict.go
+prefers, in time bounded by the number of segments of the path rather
+than the number of routes.
+
+The Index is a snapshot, rebuilt with RouteTable.LoadIndex or maintained
+with Add and Remove as the routes change.
+
+# Usage
+
+    idx, err := table.LoadIndex(ctx)
+    r, err := idx.ResolveTenantRoute(ctx, tenant, route.GET, "/api/books/v1/books/42")
+    params, _ := route.MatchPath(r.Key.Url, "/api/books/v1/books/42")
+*/
+
+// indexTree identifies the tree of the templates of a tenant and method.
+type indexTree struct {
+	tenant string
+	method MethodType
+}
+
+// indexNode is a node of the template tree for a path segment.
+type indexNode struct {
+	// children for the literal segments
+	literal map[string]*indexNode
+
+	// child for the {param} segments, regardless of the name
+	param *indexNode
+
+	// routes of the templates ending at the node and with a trailing /*
+	// wildcard at the node, ordered by url
+	routes   []*Route
+	wildcard []*Route
+}
+
+// insertRoute inserts or replaces the route of the list ordered by url.
+func insertRoute(list []*Route, r *Route) []*Route {
+	i, found := slices.BinarySearchFunc(list, r.Key.Url, func(e *Route, url string) int {
+		return strings.Compare(e.Key.Url, url)
+	})
+	if found {
+		list[i] = r
+		return list
+	}
+	return slices.Insert(list, i, r)
+}
+
+// removeRoute removes the route of the url from the list.
+func removeRoute(list []*Route, url string) []*Route {
+	return slices.DeleteFunc(list, func(e *Route) bool {
+		return e.Key.Url == url
+	})
+}
+
+// node returns the node of the template segments, creating the missing
+// ones if create is set, nil if missing otherwise.
+func (n *indexNode) node(segs []string, create bool) *indexNode {
+	for _, s := range segs {
+		var next *indexNode
+		if segmentKind(s) == paramSegment {
+			if n.param == nil && create {
+				n.param = &indexNode{}
+			}
+			next = n.param
+		} else {
+			next = n.literal[s]
+			if next == nil && create {
+				if n.literal == nil {
+					n.literal = map[string]*indexNode{}
+				}
+				next = &indexNode{}
+				n.literal[s] = next
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// lookup returns the route of the template best matching the remainder of
+// the path, whose segments are exhausted unless more is set.
+func (n *indexNode) lookup(rest string, more bool) *Route {
+	if !more {
+		// a trailing wildcard matches an empty remainder, and takes
+		// precedence over the shorter template, see moreSpecific
+		if len(n.wildcard) != 0 {
+			return n.wildcard[0]
+		}
+		if len(n.routes) != 0 {
+			return n.routes[0]
+		}
+		return nil
+	}
+	seg, tail, found := strings.Cut(rest, "/")
+	if child := n.literal[seg]; child != nil {
+		if r := child.lookup(tail, found); r != nil {
+			return r
+		}
+	}
+	if n.param != nil && seg != "" {
+		if r := n.param.lookup(tail, found); r != nil {
+			return r
+		}
+	}
+	if len(n.wildcard) != 0 {
+		return n.wildcard[0]
+	}
+	return nil
+}
+
+// Index is an in-memory index of the routes, see the file documentation.
+// An Index is safe for concurrent use.
+type Index struct {
+	mu     sync.RWMutex
+	exact  map[Key]*Route
+	trees  map[indexTree]*indexNode
+	health *HealthTracker
+}
+
+// NewIndex creates an empty index, resolving the routes whose endpoints
+// are all unhealthy as per the tracker, if any, to ErrNoHealthyEndpoint.
+func NewIndex(health *HealthTracker) *Index {
+	return &Index{
+		exact:  map[Key]*Route{},
+		trees:  map[indexTree]*indexNode{},
+		health: health,
+	}
+}
+
+// LoadIndex returns an index of all the routes of the table, using the
+// health tracker of the table.
+func (t *RouteTable) LoadIndex(ctx context.Context) (*Index, error) {
+	list, err := t.ListRoutes(ctx, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	idx := NewIndex(t.health)
+	for _, r := range list {
+		idx.Add(r)
+	}
+	return idx, nil
+}
+
+// templateSegments returns the segments of the template along with
+// whether it ends with the /* wildcard, removed from the segments.
+func templateSegments(url string) ([]string, bool) {
+	segs := strings.Split(strings.TrimPrefix(url, "/"), "/")
+	if segmentKind(segs[len(segs)-1]) == wildcardSegment {
+		return segs[:len(segs)-1], true
+	}
+	return segs, false
+}
+
+// Add adds the route to the index, replacing the route of the same key.
+func (idx *Index) Add(r *Route) {
+	if r == nil || r.Key == nil {
+		return
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.exact[*r.Key] = r
+	if !IsTemplate(r.Key.Url) {
+		return
+	}
+	tree := indexTree{tenant: r.Key.Tenant, method: r.Key.Method}
+	root := idx.trees[tree]
+	if root == nil {
+		root = &indexNode{}
+		idx.trees[tree] = root
+	}
+	segs, wildcard := templateSegments(r.Key.Url)
+	n := root.node(segs, true)
+	if wildcard {
+		n.wildcard = insertRoute(n.wildcard, r)
+	} else {
+		n.routes = insertRoute(n.routes, r)
+	}
+}
+
+// Remove removes the route of the key from the index. The nodes left
+// empty are kept until the index is rebuilt.
+func (idx *Index) Remove(key *Key) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if _, ok := idx.exact[*key]; !ok {
+		return
+	}
+	delete(idx.exact, *key)
+	root := idx.trees[indexTree{tenant: key.Tenant, method: key.Method}]
+	if root == nil || !IsTemplate(key.Url) {
+		return
+	}
+	segs, wildcard := templateSegments(key.Url)
+	if n := root.node(segs, false); n != nil {
+		if wildcard {
+			n.wildcard = removeRoute(n.wildcard, key.Url)
+		} else {
+			n.routes = removeRoute(n.routes, key.Url)
+		}
+	}
+}
+
+// Len returns the number of routes in the index.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.exact)
+}
+
+// lookup returns the route of the tenant best matching the path, nil if
+// none.
+func (idx *Index) lookup(tenant string, method MethodType, path string) *Route {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if r, ok := idx.exact[Key{Url: path, Method: method, Tenant: tenant}]; ok {
+		return r
+	}
+	if root := idx.trees[indexTree{tenant: tenant, method: method}]; root != nil {
+		return root.lookup(strings.TrimPrefix(path, "/"), true)
+	}
+	return nil
+}
+
+// ResolveRoute returns the shared route best matching the request path,
+// with the precedence of RouteTable.ResolveRoute.
+func (idx *Index) ResolveRoute(ctx context.Context, method MethodType, path string) (*Route, error) {
+	return idx.ResolveTenantRoute(ctx, "", method, path)
+}
+
+// ResolveTenantRoute returns the route of the tenant best matching the
+// request path, falling back to the shared routes, with the precedence of
+// RouteTable.ResolveTenantRoute.
+func (idx *Index) ResolveTenantRoute(ctx context.Context, tenant string, method MethodType, path string) (*Route, error) {
+	r := idx.lookup(tenant, method, path)
+	if r == nil && tenant != "" {
+		r = idx.lookup("", method, path)
+	}
+	if r == nil {
+		return nil, errors.Wrapf(errors.NotFound, "no route found for %s %s", method, path)
+	}
+	return checkHealth(idx.health, r)
+}
@@ -0,0 +1,157 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// naiveResolve resolves the path scanning all the templates, as done by
+// the RouteTable.
+func naiveResolve(routes []*Route, tenant string, method MethodType, path string) *Route {
+	var best *Route
+	for _, r := range routes {
+		if r.Key.Method != method || r.Key.Tenant != tenant {
+			continue
+		}
+		if r.Key.Url == path {
+			return r
+		}
+		if !IsTemplate(r.Key.Url) {
+			continue
+		}
+		if _, ok := MatchPath(r.Key.Url, path); !ok {
+			continue
+		}
+		if best == nil || moreSpecific(r.Key.Url, best.Key.Url) {
+			best = r
+		}
+	}
+	return best
+}
+
+func TestIndex(t *testing.T) {
+	urls := []string{
+		"/",
+		"/*",
+		"/api/*",
+		"/api/{version}/books/{id}",
+		"/api/v1/*",
+		"/api/v1/books/{id}",
+		"/api/{version}/books/latest",
+		"/api/v1/books/special",
+		"/api/v1/books/{id}/pages/*",
+		"/api/v1/books/{id}/pages",
+		"/files/*",
+		"/items/{a}",
+		"/items/{b}",
+	}
+	routes := []*Route{}
+	idx := NewIndex(nil)
+	for _, url := range urls {
+		r := &Route{Key: &Key{Url: url, Method: GET}, Endpoint: "http://" + url}
+		routes = append(routes, r)
+		idx.Add(r)
+	}
+	tenantRoute := &Route{Key: &Key{Url: "/api/v1/books/{id}", Method: GET, Tenant: "acme"}}
+	idx.Add(tenantRoute)
+
+	paths := []string{
+		"/", "/api", "/api/", "/api/v1", "/api/v1/books", "/api/v1/books/42",
+		"/api/v2/books/42", "/api/v2/books/latest", "/api/v1/books/special",
+		"/api/v1/books/42/pages", "/api/v1/books/42/pages/7", "/api/v1/books//pages",
+		"/files", "/files/a/b", "/items/x", "/items/", "/other",
+	}
+	for _, path := range paths {
+		want := naiveResolve(routes, "", GET, path)
+		got, err := idx.ResolveRoute(context.Background(), GET, path)
+		if want == nil {
+			if !errors.IsNotFound(err) {
+				t.Errorf("%s: expected not found, got %v, %v", path, got, err)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("%s: expected %s, got %v, %v", path, want.Key.Url, got, err)
+		}
+	}
+
+	got, err := idx.ResolveTenantRoute(context.Background(), "acme", GET, "/api/v1/books/42")
+	if err != nil || got != tenantRoute {
+		t.Errorf("expected the route of the tenant, got %v, %v", got, err)
+	}
+	got, err = idx.ResolveTenantRoute(context.Background(), "acme", GET, "/api/v2/books/42")
+	if err != nil || got.Key.Tenant != "" {
+		t.Errorf("expected the shared route, got %v, %v", got, err)
+	}
+	if _, err := idx.ResolveRoute(context.Background(), POST, "/api/v1/books/42"); !errors.IsNotFound(err) {
+		t.Errorf("expected not found for another method, got %v", err)
+	}
+
+	idx.Remove(&Key{Url: "/api/v1/books/{id}", Method: GET})
+	got, err = idx.ResolveRoute(context.Background(), GET, "/api/v1/books/42")
+	if err != nil || got.Key.Url != "/api/v1/*" {
+		t.Errorf("expected the less specific template once removed, got %v, %v", got, err)
+	}
+	if idx.Len() != len(urls) {
+		t.Errorf("expected %d routes, got %d", len(urls), idx.Len())
+	}
+}
+
+func TestIndexHealth(t *testing.T) {
+	health := NewHealthTracker(HealthPolicy{FailureThreshold: 1, RecoveryInterval: time.Minute})
+	idx := NewIndex(health)
+	idx.Add(&Route{Key: &Key{Url: "/books/{id}", Method: GET}, Endpoint: "http://books:8080"})
+	health.ReportFailure("http://books:8080", nil)
+	if _, err := idx.ResolveRoute(context.Background(), GET, "/books/42"); err != ErrNoHealthyEndpoint {
+		t.Errorf("expected no healthy endpoint, got %v", err)
+	}
+}
+
+// benchmarkRoutes returns n routes across services, mixing literal urls
+// and templates.
+func benchmarkRoutes(n int) []*Route {
+	routes := make([]*Route, 0, n)
+	for i := 0; len(routes) < n; i++ {
+		prefix := fmt.Sprintf("/api/service%d/v1", i)
+		for _, url := range []string{
+			prefix + "/items",
+			prefix + "/items/{id}",
+			prefix + "/items/{id}/parts/{part}",
+			prefix + "/files/*",
+		} {
+			routes = append(routes, &Route{Key: &Key{Url: url, Method: GET}})
+		}
+	}
+	return routes
+}
+
+func BenchmarkResolveNaive(b *testing.B) {
+	routes := benchmarkRoutes(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if naiveResolve(routes, "", GET, "/api/service2000/v1/items/42/parts/7") == nil {
+			b.Fatal("route not found")
+		}
+	}
+}
+
+func BenchmarkResolveIndex(b *testing.B) {
+	idx := NewIndex(nil)
+	for _, r := range benchmarkRoutes(10000) {
+		idx.Add(r)
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := idx.ResolveRoute(ctx, GET, "/api/service2000/v1/items/42/parts/7"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
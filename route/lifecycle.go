@@ -0,0 +1,67 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Lifecycle holds the lifecycle flags of a route, managed centrally by the
+// API owners and enforced by the gateway.
+type Lifecycle struct {
+	// time since which the route is deprecated, the gateway emits the
+	// Deprecation header (RFC 9745) once set
+	DeprecatedAt *time.Time `bson:"deprecatedAt,omitempty"`
+
+	// time after which the route is expected to be removed, the gateway
+	// emits the Sunset header (RFC 8594) once set
+	SunsetAt *time.Time `bson:"sunsetAt,omitempty"`
+
+	// link to the documentation describing the deprecation or migration
+	Link string `bson:"link,omitempty"`
+
+	// route is removed, requests are rejected with 410 Gone
+	Disabled bool `bson:"disabled,omitempty"`
+
+	// route is under maintenance, requests are rejected with 503
+	Maintenance bool `bson:"maintenance,omitempty"`
+
+	// suggested delay, in seconds, sent as Retry-After while under
+	// maintenance
+	RetryAfter int32 `bson:"retryAfter,omitempty"`
+}
+
+// Apply sets the lifecycle headers on the response headers and returns the
+// status code to reject the request with, or 0 if the request is to be
+// proxied to the endpoint.
+func (l *Lifecycle) Apply(h http.Header) int {
+	if l == nil {
+		return 0
+	}
+	if l.DeprecatedAt != nil {
+		h.Set("Deprecation", fmt.Sprintf("@%d", l.DeprecatedAt.Unix()))
+		if l.Link != "" {
+			h.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", l.Link))
+		}
+	}
+	if l.SunsetAt != nil {
+		h.Set("Sunset", l.SunsetAt.UTC().Format(http.TimeFormat))
+		if l.Link != "" {
+			h.Add("Link", fmt.Sprintf("<%s>; rel=\"sunset\"", l.Link))
+		}
+	}
+	switch {
+	case l.Disabled:
+		return http.StatusGone
+	case l.Maintenance:
+		if l.RetryAfter > 0 {
+			h.Set("Retry-After", strconv.Itoa(int(l.RetryAfter)))
+		}
+		return http.StatusServiceUnavailable
+	}
+	return 0
+}
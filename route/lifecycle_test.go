@@ -0,0 +1,91 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLifecycleApply(t *testing.T) {
+	deprecated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sunset := time.Date(2025, 6, 30, 0, 0, 0, 0, time.FixedZone("IST", 5*3600+1800))
+	tests := []struct {
+		name      string
+		lifecycle *Lifecycle
+		status    int
+		headers   map[string]string
+		links     []string
+	}{
+		{
+			name: "no lifecycle",
+		},
+		{
+			name:      "active route",
+			lifecycle: &Lifecycle{Link: "https://docs.example.com/v2"},
+		},
+		{
+			name:      "deprecated route",
+			lifecycle: &Lifecycle{DeprecatedAt: &deprecated},
+			headers:   map[string]string{"Deprecation": "@1735787045"},
+		},
+		{
+			name:      "deprecated and sunset route with link",
+			lifecycle: &Lifecycle{DeprecatedAt: &deprecated, SunsetAt: &sunset, Link: "https://docs.example.com/v2"},
+			headers: map[string]string{
+				"Deprecation": "@1735787045",
+				"Sunset":      "Sun, 29 Jun 2025 18:30:00 GMT",
+			},
+			links: []string{
+				`<https://docs.example.com/v2>; rel="deprecation"`,
+				`<https://docs.example.com/v2>; rel="sunset"`,
+			},
+		},
+		{
+			name:      "disabled route",
+			lifecycle: &Lifecycle{Disabled: true, SunsetAt: &sunset},
+			status:    http.StatusGone,
+			headers:   map[string]string{"Sunset": "Sun, 29 Jun 2025 18:30:00 GMT"},
+		},
+		{
+			name:      "disabled route under maintenance",
+			lifecycle: &Lifecycle{Disabled: true, Maintenance: true, RetryAfter: 60},
+			status:    http.StatusGone,
+		},
+		{
+			name:      "maintenance with retry after",
+			lifecycle: &Lifecycle{Maintenance: true, RetryAfter: 120},
+			status:    http.StatusServiceUnavailable,
+			headers:   map[string]string{"Retry-After": "120"},
+		},
+		{
+			name:      "maintenance without retry after",
+			lifecycle: &Lifecycle{Maintenance: true},
+			status:    http.StatusServiceUnavailable,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := http.Header{}
+			if status := tc.lifecycle.Apply(h); status != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, status)
+			}
+			for name, v := range tc.headers {
+				if got := h.Get(name); got != v {
+					t.Errorf("expected %s: %q, got %q", name, v, got)
+				}
+			}
+			for _, name := range []string{"Deprecation", "Sunset", "Retry-After"} {
+				if _, ok := tc.headers[name]; !ok && h.Get(name) != "" {
+					t.Errorf("unexpected %s: %q", name, h.Get(name))
+				}
+			}
+			if links := h.Values("Link"); !slices.Equal(links, tc.links) {
+				t.Errorf("expected links %q, got %q", tc.links, links)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// segment kinds in the order of their precedence while matching
+const (
+	literalSegment = iota
+	paramSegment
+	wildcardSegment
+)
+
+// IsTemplate reports whether the route url is a path template, i.e. it
+// contains {param} segments or ends with the /* prefix wildcard.
+func IsTemplate(url string) bool {
+	return strings.Contains(url, "{") || strings.HasSuffix(url, "/*")
+}
+
+// segmentKind returns the kind of the template segment.
+func segmentKind(s string) int {
+	switch {
+	case s == "*":
+		return wildcardSegment
+	case len(s) > 2 && s[0] == '{' && s[len(s)-1] == '}':
+		return paramSegment
+	}
+	return literalSegment
+}
+
+// MatchPath matches the request path against the route url template,
+// returning the values of the {param} segments, and for a trailing /*
+// wildcard the remainder of the path under the name "*". A wildcard
+// matches any remainder, including an empty one, e.g. "/files/*" matches
+// "/files", "/files/" and "/files/a/b".
+func MatchPath(template, path string) (map[string]string, bool) {
+	tSegs := strings.Split(strings.TrimPrefix(template, "/"), "/")
+	pSegs := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	params := map[string]string{}
+	for i, t := range tSegs {
+		switch segmentKind(t) {
+		case wildcardSegment:
+			if i != len(tSegs)-1 {
+				return nil, false
+			}
+			if i > len(pSegs) {
+				return nil, false
+			}
+			params["*"] = strings.Join(pSegs[i:], "/")
+			return params, true
+		case paramSegment:
+			if i >= len(pSegs) || pSegs[i] == "" {
+				return nil, false
+			}
+			params[t[1:len(t)-1]] = pSegs[i]
+		default:
+			if i >= len(pSegs) || pSegs[i] != t {
+				return nil, false
+			}
+		}
+	}
+	if len(tSegs) != len(pSegs) {
+		return nil, false
+	}
+	return params, true
+}
+
+// moreSpecific reports whether template a takes precedence over template
+// b, when both match the same path. Segments are compared from left to
+// right, with a literal segment preferred over a {param} segment, which in
+// turn is preferred over a wildcard, the first differing segment deciding.
+// Templates equally specific are ordered lexically to keep the result
+// deterministic.
+func moreSpecific(a, b string) bool {
+	aSegs := strings.Split(strings.TrimPrefix(a, "/"), "/")
+	bSegs := strings.Split(strings.TrimPrefix(b, "/"), "/")
+	for i := 0; i < len(aSegs) && i < len(bSegs); i++ {
+		ka, kb := segmentKind(aSegs[i]), segmentKind(bSegs[i])
+		if ka != kb {
+			return ka < kb
+		}
+	}
+	if len(aSegs) != len(bSegs) {
+		// only possible when the shorter one ends with a wildcard
+		return len(aSegs) > len(bSegs)
+	}
+	return a < b
+}
+
+// ResolveRoute returns the shared route registered for the method best
+// matching the request path. A route registered with exactly the path is
+// preferred, followed by the path templates as per their precedence, see
+// MatchPath for the template syntax, and conflict.go for the precedence
+// model along with the conflicts rejected at registration. Returns NotFound
+// if no route matches, and ErrNoHealthyEndpoint if the endpoints of the route
+// are all unhealthy as per the tracker set with SetHealthTracker.
+func (t *RouteTable) ResolveRoute(ctx context.Context, method MethodType, path string) (*Route, error) {
+	return t.resolveRoute(ctx, "", method, path)
+}
+
+// ResolveTenantRoute returns the route of the tenant best matching the
+// request path as per ResolveRoute, falling back to the shared routes if
+// the tenant has no matching route of its own.
+func (t *RouteTable) ResolveTenantRoute(ctx context.Context, tenant string, method MethodType, path string) (*Route, error) {
+	if tenant != "" {
+		entry, err := t.resolveRoute(ctx, tenant, method, path)
+		if !errors.IsNotFound(err) {
+			return entry, err
+		}
+	}
+	return t.resolveRoute(ctx, "", method, path)
+}
+
+// resolveRoute returns the route of the tenant best matching the path.
+func (t *RouteTable) resolveRoute(ctx context.Context, tenant string, method MethodType, path string) (*Route, error) {
+	if t.Table == nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "route table not initialized")
+	}
+	entry, err := t.Find(ctx, &Key{Url: path, Method: method, Tenant: tenant})
+	if err == nil {
+		return t.checkHealth(entry)
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	filter := bson.D{
+		{Key: "_id.url", Value: bson.Regex{Pattern: `\{|/\*$`}},
+		tenantFilter(tenant),
+	}
+	list, err := t.FindMany(ctx, filter, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "failed to find route templates: %s", err)
+	}
+	var best *Route
+	for _, e := range list {
+		if e.Key == nil || e.Key.Method != method || e.Key.Tenant != tenant {
+			continue
+		}
+		if _, ok := MatchPath(e.Key.Url, path); !ok {
+			continue
+		}
+		if best == nil || moreSpecific(e.Key.Url, best.Key.Url) {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil, errors.Wrapf(errors.NotFound, "no route found for %s %s", method, path)
+	}
+	return t.checkHealth(best)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		template string
+		path     string
+		ok       bool
+		params   map[string]string
+	}{
+		{"/api/v1/scope/{id}/items", "/api/v1/scope/42/items", true, map[string]string{"id": "42"}},
+		{"/api/v1/scope/{id}/items", "/api/v1/scope//items", false, nil},
+		{"/api/v1/scope/{id}/items", "/api/v1/scope/42", false, nil},
+		{"/api/v1/scope/{id}", "/api/v1/scope/42/items", false, nil},
+		{"/files/*", "/files/a/b", true, map[string]string{"*": "a/b"}},
+		{"/files/*", "/files", true, map[string]string{"*": ""}},
+		{"/files/*", "/other/a", false, nil},
+		{"/*", "/anything/at/all", true, map[string]string{"*": "anything/at/all"}},
+		{"/books", "/books", true, map[string]string{}},
+	}
+	for _, tc := range tests {
+		params, ok := MatchPath(tc.template, tc.path)
+		if ok != tc.ok {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tc.template, tc.path, ok, tc.ok)
+			continue
+		}
+		for k, v := range tc.params {
+			if params[k] != v {
+				t.Errorf("MatchPath(%q, %q) param %s = %q, want %q", tc.template, tc.path, k, params[k], v)
+			}
+		}
+	}
+}
+
+func TestTemplatePrecedence(t *testing.T) {
+	templates := []string{
+		"/api/*",
+		"/api/{version}/books/{id}",
+		"/api/v1/*",
+		"/api/v1/books/{id}",
+		"/api/{version}/books/latest",
+		"/api/v1/books/{isbn}",
+	}
+	sort.Slice(templates, func(i, j int) bool { return moreSpecific(templates[i], templates[j]) })
+	want := []string{
+		"/api/v1/books/{id}",
+		"/api/v1/books/{isbn}",
+		"/api/v1/*",
+		"/api/{version}/books/latest",
+		"/api/{version}/books/{id}",
+		"/api/*",
+	}
+	for i := range want {
+		if templates[i] != want[i] {
+			t.Fatalf("unexpected precedence order %v", templates)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// String returns the HTTP method name, e.g. "PUT", or the number of an
+// unknown method type.
+func (m MethodType) String() string {
+	if m >= 0 && int(m) < len(methodNames) {
+		return methodNames[m]
+	}
+	return "MethodType(" + strconv.Itoa(int(m)) + ")"
+}
+
+// ParseMethod returns the MethodType of the HTTP method name, matched case
+// insensitively, failing with InvalidArgument for unknown methods.
+func ParseMethod(method string) (MethodType, error) {
+	for m, name := range methodNames {
+		if strings.EqualFold(name, method) {
+			return MethodType(m), nil
+		}
+	}
+	return GET, errors.Wrapf(errors.InvalidArgument, "unknown http method %q", method)
+}
+
+// MarshalText encodes the method as its name.
+func (m MethodType) MarshalText() ([]byte, error) {
+	if m < 0 || int(m) >= len(methodNames) {
+		return nil, errors.Wrapf(errors.InvalidArgument, "unknown method type %d", m)
+	}
+	return []byte(methodNames[m]), nil
+}
+
+// UnmarshalText decodes the method from its name.
+func (m *MethodType) UnmarshalText(text []byte) error {
+	v, err := ParseMethod(string(text))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// MarshalJSON encodes the method as its name, e.g. "PUT".
+func (m MethodType) MarshalJSON() ([]byte, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the method from its name, or from its number as
+// encoded before methods were encoded by name.
+func (m *MethodType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return m.UnmarshalText([]byte(name))
+	}
+	var n int32
+	if err := json.Unmarshal(data, &n); err != nil {
+		return errors.Wrapf(errors.InvalidArgument, "invalid method %s", data)
+	}
+	return m.setNumber(int64(n))
+}
+
+// setNumber sets the method from its number.
+func (m *MethodType) setNumber(n int64) error {
+	if n < 0 || n >= int64(len(methodNames)) {
+		return errors.Wrapf(errors.InvalidArgument, "unknown method type %d", n)
+	}
+	*m = MethodType(n)
+	return nil
+}
+
+// MarshalBSONValue encodes the method as int32, as the method is part of
+// the route keys already stored.
+func (m MethodType) MarshalBSONValue() (byte, []byte, error) {
+	return byte(bson.TypeInt32), binary.LittleEndian.AppendUint32(nil, uint32(m)), nil
+}
+
+// UnmarshalBSONValue decodes the method from its number, or from its name.
+func (m *MethodType) UnmarshalBSONValue(typ byte, data []byte) error {
+	rv := bson.RawValue{Type: bson.Type(typ), Value: data}
+	switch rv.Type {
+	case bson.TypeInt32:
+		return m.setNumber(int64(rv.Int32()))
+	case bson.TypeInt64:
+		return m.setNumber(rv.Int64())
+	case bson.TypeString:
+		return m.UnmarshalText([]byte(rv.StringValue()))
+	}
+	return errors.Wrapf(errors.InvalidArgument, "invalid bson type %s for method", rv.Type)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMethodType(t *testing.T) {
+	if PUT.String() != "PUT" || MethodType(42).String() != "MethodType(42)" {
+		t.Errorf("unexpected method names %s, %s", PUT, MethodType(42))
+	}
+	if m, err := ParseMethod("patch"); err != nil || m != PATCH {
+		t.Errorf("expected PATCH, got %s: %v", m, err)
+	}
+	if _, err := ParseMethod("FETCH"); err == nil {
+		t.Errorf("expected unknown method to fail")
+	}
+
+	// JSON encodes the name, and decodes names and legacy numbers
+	b, _ := json.Marshal(&Key{Url: "/books", Method: PUT})
+	if string(b) != `{"Url":"/books","Method":"PUT"}` {
+		t.Errorf("unexpected json %s", b)
+	}
+	k := &Key{}
+	if err := json.Unmarshal([]byte(`{"Url":"/books","Method":5}`), k); err != nil || k.Method != DELETE {
+		t.Errorf("expected DELETE, got %s: %v", k.Method, err)
+	}
+	if err := json.Unmarshal([]byte(`{"Method":"FETCH"}`), k); err == nil {
+		t.Errorf("expected unknown method to fail")
+	}
+
+	// BSON retains the numeric encoding of the stored keys
+	b, _ = bson.Marshal(&Key{Url: "/books", Method: PUT})
+	if v := bson.Raw(b).Lookup("method"); v.Type != bson.TypeInt32 || v.Int32() != int32(PUT) {
+		t.Errorf("expected method to be stored as int32, got %s", v)
+	}
+	k = &Key{}
+	if err := bson.Unmarshal(b, k); err != nil || k.Method != PUT {
+		t.Errorf("expected PUT, got %s: %v", k.Method, err)
+	}
+	b, _ = bson.Marshal(bson.D{{Key: "method", Value: "POST"}})
+	if err := bson.Unmarshal(b, k); err != nil || k.Method != POST {
+		t.Errorf("expected POST decoded from name, got %s: %v", k.Method, err)
+	}
+	b, _ = bson.Marshal(&Key{Url: "/books", Method: GET})
+	if _, err := bson.Raw(b).LookupErr("method"); err == nil {
+		t.Errorf("expected GET to be omitted")
+	}
+}
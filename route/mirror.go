@@ -0,0 +1,31 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"math/rand/v2"
+)
+
+// Mirror describes a shadow target to which the gateway asynchronously
+// duplicates a sampled percentage of the requests of a route, the
+// responses of the mirror are discarded.
+type Mirror struct {
+	// endpoint of the shadow backend, requests are re-signed for it
+	Endpoint string `bson:"endpoint,omitempty"`
+
+	// percentage of requests mirrored, in the range 0 - 100
+	Percentage int32 `bson:"percentage,omitempty"`
+}
+
+// Sample reports whether a request is to be mirrored, picking requests at
+// random as per the configured percentage.
+func (m *Mirror) Sample() bool {
+	if m == nil || m.Endpoint == "" || m.Percentage <= 0 {
+		return false
+	}
+	if m.Percentage >= 100 {
+		return true
+	}
+	return rand.Int32N(100) < m.Percentage
+}
@@ -0,0 +1,62 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"testing"
+)
+
+func TestMirrorSample(t *testing.T) {
+	tests := []struct {
+		name   string
+		mirror *Mirror
+		want   bool
+	}{
+		{"no mirror", nil, false},
+		{"no endpoint", &Mirror{Percentage: 100}, false},
+		{"zero percentage", &Mirror{Endpoint: "http://shadow", Percentage: 0}, false},
+		{"negative percentage", &Mirror{Endpoint: "http://shadow", Percentage: -10}, false},
+		{"full percentage", &Mirror{Endpoint: "http://shadow", Percentage: 100}, true},
+		{"percentage over 100", &Mirror{Endpoint: "http://shadow", Percentage: 150}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for range 1000 {
+				if got := tc.mirror.Sample(); got != tc.want {
+					t.Fatalf("expected sample %v, got %v", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMirrorSamplePercentage(t *testing.T) {
+	const samples = 20000
+	tests := []struct {
+		name       string
+		percentage int32
+	}{
+		{"1%", 1},
+		{"25%", 25},
+		{"50%", 50},
+		{"90%", 90},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Mirror{Endpoint: "http://shadow", Percentage: tc.percentage}
+			sampled := 0
+			for range samples {
+				if m.Sample() {
+					sampled++
+				}
+			}
+			// within 2 points of the percentage, more than 4 standard
+			// deviations away
+			got := float64(sampled) * 100 / samples
+			if got < float64(tc.percentage)-2 || got > float64(tc.percentage)+2 {
+				t.Errorf("expected about %d%% of the requests sampled, got %.2f%%", tc.percentage, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/labels"
+	"github.com/go-core-stack/auth/storage"
+)
+
+/*
+This file provides the registry of route providers, i.e. the service
+instances serving the routes. An instance registers itself as the provider
+for a set of routes with a lease, which it keeps renewing using signed
+heartbeats, see heartbeat.go, so that the gateway only forwards to the
+endpoints that are alive.
+
+# Usage
+
+    tbl, _ := route.NewRouteProviderTable(client)
+
+    // registration by the service instance, owned by its API key
+    key := &route.ProviderKey{Name: "books-service", Endpoint: "http://books-1:8080"}
+    err := tbl.Register(ctx, key, keyId, routes, 30*time.Second)
+
+    // registry renewing the lease on verified heartbeats
+    verifier := route.NewHeartbeatVerifier(resolver, 60)
+    mux.Handle(route.HeartbeatPath, verifier.Handler(tbl.OnHeartbeat(30*time.Second)))
+
+    // gateway resolving the endpoints alive for a route
+    providers, err := tbl.FindAlive(ctx, &route.Key{Url: "/books", Method: route.GET})
+*/
+
+// ProviderKey identifies a single instance of a route provider.
+type ProviderKey struct {
+	// name of the provider, shared by all its instances
+	Name string `bson:"name,omitempty"`
+
+	// endpoint served by the instance
+	Endpoint string `bson:"endpoint,omitempty"`
+}
+
+// RouteProvider is a service instance registered as provider of routes.
+type RouteProvider struct {
+	Key *ProviderKey `bson:"key,omitempty"`
+
+	// API key owning the registration, only heartbeats signed by it
+	// renew the lease
+	KeyId string `bson:"keyId,omitempty"`
+
+	// routes served by the instance
+	Routes []*Key `bson:"routes,omitempty"`
+
+	// free form labels of the instance, e.g. zone or version
+	Labels labels.Labels `bson:"labels,omitempty"`
+
+	// time of registration and the last renewal of the lease, unix
+	// seconds
+	Registered    int64 `bson:"registered,omitempty"`
+	LastHeartbeat int64 `bson:"lastHeartbeat,omitempty"`
+
+	// time the lease expires unless renewed, unix seconds
+	LeaseExpiry int64 `bson:"leaseExpiry,omitempty"`
+
+	// health of the endpoint, see HealthTracker
+	Health *EndpointHealth `bson:"health,omitempty"`
+}
+
+// IsAlive reports whether the lease of the provider is valid at the time.
+func (p *RouteProvider) IsAlive(now time.Time) bool {
+	return p.LeaseExpiry > now.Unix()
+}
+
+type RouteProviderTable struct {
+	storage.Table[ProviderKey, RouteProvider]
+}
+
+var routeProviderTable *RouteProviderTable
+
+// NewRouteProviderTable returns a route provider table using the route
+// providers collection of the store client, every call returning a new
+// instance.
+func NewRouteProviderTable(client db.StoreClient) (*RouteProviderTable, error) {
+	col := client.GetCollection(ServicesDatabaseName, RouteProvidersCollectionName)
+	tbl, err := storage.NewStoreTable[ProviderKey, RouteProvider](col)
+	if err != nil {
+		return nil, err
+	}
+	return NewRouteProviderTableWithStorage(tbl), nil
+}
+
+// NewRouteProviderTableWithStorage returns a route provider table keeping
+// the providers in the storage.
+func NewRouteProviderTableWithStorage(tbl storage.Table[ProviderKey, RouteProvider]) *RouteProviderTable {
+	return &RouteProviderTable{
+		Table: tbl,
+	}
+}
+
+// GetRouteProviderTable returns the process wide route provider table
+// created by LocateRouteProviderTable.
+//
+// Deprecated: keep the table returned by NewRouteProviderTable instead.
+func GetRouteProviderTable() (*RouteProviderTable, error) {
+	if routeProviderTable != nil {
+		return routeProviderTable, nil
+	}
+
+	return nil, errors.Wrapf(errors.NotFound, "route provider table not found")
+}
+
+// LocateRouteProviderTable returns the process wide route provider table,
+// created on the first call.
+//
+// Deprecated: use NewRouteProviderTable.
+func LocateRouteProviderTable(client db.StoreClient) (*RouteProviderTable, error) {
+	if routeProviderTable != nil {
+		return routeProviderTable, nil
+	}
+
+	tbl, err := NewRouteProviderTable(client)
+	if err != nil {
+		return nil, err
+	}
+	routeProviderTable = tbl
+
+	return routeProviderTable, nil
+}
+
+// Register registers the instance as provider of the routes, owned by the
+// API key, with a lease valid for the duration. Registering again replaces
+// the routes and renews the lease, which fails with Forbidden if the
+// instance is registered by another API key.
+func (t *RouteProviderTable) Register(ctx context.Context, key *ProviderKey, keyId string, routes []*Key, lease time.Duration) error {
+	if key == nil || key.Name == "" || key.Endpoint == "" {
+		return errors.Wrapf(errors.InvalidArgument, "provider name and endpoint are required")
+	}
+	if keyId == "" {
+		return errors.Wrapf(errors.InvalidArgument, "provider api key not specified")
+	}
+	existing, err := t.Find(ctx, key)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	now := time.Now()
+	entry := &RouteProvider{
+		Key:           key,
+		KeyId:         keyId,
+		Routes:        routes,
+		Registered:    now.Unix(),
+		LastHeartbeat: now.Unix(),
+		LeaseExpiry:   now.Add(lease).Unix(),
+	}
+	if existing != nil {
+		if existing.KeyId != keyId {
+			return errors.Wrapf(errors.Forbidden, "provider %s at %s is owned by another api key", key.Name, key.Endpoint)
+		}
+		entry.Registered = existing.Registered
+	}
+	return t.Locate(ctx, key, entry)
+}
+
+// Renew extends the lease of the registered instance by the duration,
+// failing with NotFound if it is not registered, and with Forbidden if
+// it is registered by another API key.
+func (t *RouteProviderTable) Renew(ctx context.Context, key *ProviderKey, keyId string, lease time.Duration) error {
+	existing, err := t.Find(ctx, key)
+	if err != nil {
+		return err
+	}
+	if existing.KeyId != keyId {
+		return errors.Wrapf(errors.Forbidden, "provider %s at %s is owned by another api key", key.Name, key.Endpoint)
+	}
+	now := time.Now()
+	return t.Update(ctx, key, &RouteProvider{
+		LastHeartbeat: now.Unix(),
+		LeaseExpiry:   now.Add(lease).Unix(),
+	})
+}
+
+// Deregister removes the registration of the instance.
+func (t *RouteProviderTable) Deregister(ctx context.Context, key *ProviderKey) error {
+	return t.DeleteKey(ctx, key)
+}
+
+// OnHeartbeat returns the heartbeat callback for HeartbeatVerifier.Handler,
+// renewing the lease of the instance sending the heartbeat.
+func (t *RouteProviderTable) OnHeartbeat(lease time.Duration) func(ctx context.Context, keyId string, hb *Heartbeat) error {
+	return func(ctx context.Context, keyId string, hb *Heartbeat) error {
+		return t.Renew(ctx, &ProviderKey{Name: hb.Provider, Endpoint: hb.Endpoint}, keyId, lease)
+	}
+}
+
+// routeFilter returns the filter matching a route key in a list of route
+// keys, where the method is absent for GET as the zero value is omitted.
+func routeFilter(key *Key) bson.D {
+	method := bson.E{Key: "method", Value: key.Method}
+	if key.Method == GET {
+		method.Value = bson.M{"$exists": false}
+	}
+	return bson.D{{Key: "url", Value: key.Url}, method}
+}
+
+// FindAlive returns the instances with a valid lease providing the route,
+// skipping the instances recorded unhealthy.
+func (t *RouteProviderTable) FindAlive(ctx context.Context, key *Key) ([]*RouteProvider, error) {
+	filter := bson.D{
+		{Key: "routes", Value: bson.M{"$elemMatch": routeFilter(key)}},
+		{Key: "leaseExpiry", Value: bson.M{"$gt": time.Now().Unix()}},
+		{Key: "health.status", Value: bson.M{"$ne": Unhealthy}},
+	}
+	return t.FindMany(ctx, filter, 0, 0)
+}
+
+// ExpireLeases removes the instances whose lease has expired, returning
+// the number of instances removed.
+func (t *RouteProviderTable) ExpireLeases(ctx context.Context) (int64, error) {
+	filter := bson.D{{Key: "leaseExpiry", Value: bson.M{"$lte": time.Now().Unix()}}}
+	return t.DeleteByFilter(ctx, filter)
+}
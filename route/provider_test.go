@@ -0,0 +1,38 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRouteProviderIsAlive(t *testing.T) {
+	now := time.Now()
+	p := &RouteProvider{LeaseExpiry: now.Add(time.Second).Unix()}
+	if !p.IsAlive(now) {
+		t.Errorf("expected provider with valid lease to be alive")
+	}
+	if p.IsAlive(now.Add(2 * time.Second)) {
+		t.Errorf("expected provider with expired lease not to be alive")
+	}
+}
+
+func TestRouteFilter(t *testing.T) {
+	// stored route keys omit the GET method, being the zero value
+	get, _ := bson.Marshal(&Key{Url: "/books", Method: GET})
+	if _, err := bson.Raw(get).LookupErr("method"); err == nil {
+		t.Fatalf("expected GET method to be omitted")
+	}
+	f := routeFilter(&Key{Url: "/books", Method: GET})
+	if f[1].Key != "method" || f[1].Value.(bson.M)["$exists"] != false {
+		t.Errorf("expected GET to match an absent method, got %v", f)
+	}
+	f = routeFilter(&Key{Url: "/books", Method: POST})
+	if f[1].Value != POST {
+		t.Errorf("expected POST to match the method, got %v", f)
+	}
+}
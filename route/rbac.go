@@ -0,0 +1,120 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"strings"
+)
+
+/*
+This file derives the RBAC resource and verb of the routes from their
+definition, keeping the RBAC metadata consistent across the services
+publishing their routes, instead of every service spelling them out.
+
+The resource is the lower cased last literal segment of the url, e.g.
+"books" for "/api/v1/books/{id}", and the verb is derived from the method:
+
+    GET, HEAD  get for the urls ending with a {param}, list otherwise
+    POST       create
+    PUT        update
+    PATCH      patch
+    DELETE     delete
+    others     the lower cased method name
+
+gRPC routes map as per GrpcResourceVerb. The routes setting their Resource
+or Verb keep them, overriding the derived ones, and the public and user
+specific routes, not subject to RBAC, are left as is.
+
+# Usage
+
+    routeTable.SetRBACMapping(&route.RBACMapping{
+        Verbs: map[route.MethodType]string{route.PATCH: "update"},
+    })
+    err := routeTable.SyncRoutes(ctx, "books", inventory)
+*/
+
+// methodVerbs maps the methods to the RBAC verbs, GET and HEAD mapping to
+// list for collection urls
+var methodVerbs = map[MethodType]string{
+	GET:    "get",
+	HEAD:   "get",
+	POST:   "create",
+	PUT:    "update",
+	PATCH:  "patch",
+	DELETE: "delete",
+}
+
+// ResourceVerb derives the RBAC resource and verb of the REST route of the
+// method and url, see the file documentation.
+func ResourceVerb(method MethodType, url string) (resource, verb string) {
+	segments := strings.Split(strings.Trim(url, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && segmentKind(segments[i]) == literalSegment {
+			resource = strings.ToLower(segments[i])
+			break
+		}
+	}
+	verb, ok := methodVerbs[method]
+	if !ok {
+		verb = strings.ToLower(method.String())
+	}
+	if (method == GET || method == HEAD) && segmentKind(segments[len(segments)-1]) != paramSegment {
+		verb = "list"
+	}
+	return resource, verb
+}
+
+// RBACMapping derives the resource and verb of the routes not setting
+// them, see the file documentation. A nil mapping applies the defaults.
+type RBACMapping struct {
+	// Verbs overrides the verbs of the methods, e.g. PATCH to update
+	Verbs map[MethodType]string
+
+	// Resource overrides the derivation of the resource from the url,
+	// returning empty leaves the resource derived by default
+	Resource func(url string) string
+}
+
+// Apply sets the resource and verb of the route, if not set.
+func (m *RBACMapping) Apply(r *Route) {
+	if r.Key == nil || (r.IsPublic != nil && *r.IsPublic) || (r.IsUserSpecific != nil && *r.IsUserSpecific) {
+		return
+	}
+	if r.Resource != "" && r.Verb != "" {
+		return
+	}
+
+	var resource, verb string
+	if r.IsGrpc != nil && *r.IsGrpc {
+		var err error
+		if resource, verb, err = GrpcResourceVerb(r.Key.Url); err != nil {
+			return
+		}
+	} else {
+		resource, verb = ResourceVerb(r.Key.Method, r.Key.Url)
+		if m != nil {
+			if v, ok := m.Verbs[r.Key.Method]; ok {
+				verb = v
+			}
+			if m.Resource != nil {
+				if res := m.Resource(r.Key.Url); res != "" {
+					resource = res
+				}
+			}
+		}
+	}
+	if r.Resource == "" {
+		r.Resource = resource
+	}
+	if r.Verb == "" {
+		r.Verb = verb
+	}
+}
+
+// SetRBACMapping makes SyncRoutes derive the resource and verb of the
+// routes of the inventories not setting them, as per the mapping, e.g.
+// &RBACMapping{} for the defaults. A nil mapping disables the derivation.
+func (t *RouteTable) SetRBACMapping(m *RBACMapping) {
+	t.rbac = m
+}
@@ -0,0 +1,84 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResourceVerb(t *testing.T) {
+	tests := []struct {
+		method   MethodType
+		url      string
+		resource string
+		verb     string
+	}{
+		{GET, "/api/v1/books", "books", "list"},
+		{GET, "/api/v1/books/{id}", "books", "get"},
+		{HEAD, "/api/v1/books/{id}", "books", "get"},
+		{GET, "/api/v1/books/{id}/Reviews", "reviews", "list"},
+		{GET, "/files/*", "files", "list"},
+		{POST, "/api/v1/books", "books", "create"},
+		{PUT, "/api/v1/books/{id}", "books", "update"},
+		{PATCH, "/api/v1/books/{id}", "books", "patch"},
+		{DELETE, "/api/v1/books/{id}", "books", "delete"},
+		{OPTIONS, "/api/v1/books", "books", "options"},
+		{GET, "/", "", "list"},
+	}
+	for _, tt := range tests {
+		resource, verb := ResourceVerb(tt.method, tt.url)
+		if resource != tt.resource || verb != tt.verb {
+			t.Errorf("%s %s: expected %s/%s, got %s/%s", tt.method, tt.url, tt.resource, tt.verb, resource, verb)
+		}
+	}
+}
+
+func TestRBACMappingApply(t *testing.T) {
+	var defaults *RBACMapping
+	r := &Route{Key: &Key{Url: "/api/v1/books/{id}", Method: PATCH}}
+	defaults.Apply(r)
+	if r.Resource != "books" || r.Verb != "patch" {
+		t.Errorf("unexpected default mapping %s/%s", r.Resource, r.Verb)
+	}
+
+	m := &RBACMapping{
+		Verbs: map[MethodType]string{PATCH: "update"},
+		Resource: func(url string) string {
+			if strings.HasPrefix(url, "/api/v1/library/") {
+				return "library"
+			}
+			return ""
+		},
+	}
+	r = &Route{Key: &Key{Url: "/api/v1/books/{id}", Method: PATCH}}
+	m.Apply(r)
+	if r.Resource != "books" || r.Verb != "update" {
+		t.Errorf("expected the verb to be overridden, got %s/%s", r.Resource, r.Verb)
+	}
+	r = &Route{Key: &Key{Url: "/api/v1/library/shelves", Method: GET}}
+	m.Apply(r)
+	if r.Resource != "library" || r.Verb != "list" {
+		t.Errorf("expected the resource to be overridden, got %s/%s", r.Resource, r.Verb)
+	}
+
+	// the route overrides the mapping
+	r = &Route{Key: &Key{Url: "/api/v1/books/{id}/publish", Method: POST}, Verb: "publish"}
+	m.Apply(r)
+	if r.Resource != "publish" || r.Verb != "publish" {
+		t.Errorf("expected the verb of the route to be kept, got %s/%s", r.Resource, r.Verb)
+	}
+
+	isGrpc, isPublic := true, true
+	r = &Route{Key: GrpcRouteKey("/api.v1.BookService/ListBooks"), IsGrpc: &isGrpc}
+	m.Apply(r)
+	if r.Resource != "book" || r.Verb != "list" {
+		t.Errorf("unexpected grpc mapping %s/%s", r.Resource, r.Verb)
+	}
+	r = &Route{Key: &Key{Url: "/healthz", Method: GET}, IsPublic: &isPublic}
+	m.Apply(r)
+	if r.Resource != "" || r.Verb != "" {
+		t.Errorf("expected the public route to be left as is, got %s/%s", r.Resource, r.Verb)
+	}
+}
@@ -0,0 +1,364 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/storage"
+)
+
+/*
+This file provides the replication of the routes and the route providers
+between the independent stores of two regions, so that a regional gateway
+keeps serving from its own store while the stores are partitioned, the
+changes made on either side being exchanged once the partition heals.
+
+Both sides accept changes, the Replicator merging them with the state of
+the last synchronization as the common base:
+
+  - a record changed on one side only is copied to the other side, and a
+    record deleted on one side only is deleted on the other side
+  - a record changed on both sides is resolved last-write-wins, by the
+    Modified time of the routes and the last heartbeat of the providers,
+    the Name of the replicas breaking the ties
+  - a record deleted on one side and changed on the other is kept
+
+The records resolved from concurrent changes are reported as a
+*ReplicationConflict to the handler set with SetConflictHandler. The base
+is kept in memory: the first synchronization of a Replicator merges the
+two stores as a union, resolving the records present on both sides
+last-write-wins.
+
+The routes written by AddRoute, UpdateRoute and SyncRoutes carry their
+Modified time, the routes written directly in the storage without it
+losing to any concurrent change. The copies are written as is, without
+the conflict detection of AddRoute, and publish the route events on the
+route table receiving them, see RouteTable.SetPublisher.
+
+# Usage
+
+    local := &route.Replica{Name: "eu-west", Routes: euRoutes, Providers: euProviders}
+    remote := &route.Replica{Name: "us-east", Routes: usRoutes, Providers: usProviders}
+    r := route.NewReplicator(local, remote, 30*time.Second)
+    r.SetConflictHandler(func(ctx context.Context, c *route.ReplicationConflict) {
+        log.Printf("replication conflict: %s", c)
+    })
+    go r.Run(ctx)
+*/
+
+// DefaultReplicationInterval is how often the Replicator synchronizes the
+// replicas unless configured otherwise.
+const DefaultReplicationInterval = 30 * time.Second
+
+// Replica is the store of a region replicated by the Replicator.
+type Replica struct {
+	// name of the replica, unique across the replicas
+	Name string
+
+	// routes of the replica
+	Routes *RouteTable
+
+	// route providers of the replica, not replicated if nil on either
+	// side
+	Providers *RouteProviderTable
+}
+
+// ReplicationConflict is a record changed on both replicas since their
+// last synchronization.
+type ReplicationConflict struct {
+	// kind of the record, "route" or "provider"
+	Kind string
+
+	// key of the record
+	Key string
+
+	// name of the replica whose record is kept, and of the one whose
+	// change is discarded
+	Winner string
+	Loser  string
+
+	// set if the record discarded is a deletion
+	Deleted bool
+}
+
+// String returns the description of the conflict.
+func (c *ReplicationConflict) String() string {
+	change := "change"
+	if c.Deleted {
+		change = "deletion"
+	}
+	return fmt.Sprintf("%s %s: kept the record of %s, discarded the %s of %s", c.Kind, c.Key, c.Winner, change, c.Loser)
+}
+
+// ReplicationStats captures the counters of a Replicator.
+type ReplicationStats struct {
+	Syncs     uint64 // synchronizations completed
+	Failures  uint64 // synchronizations failed, e.g. during a partition
+	Copied    uint64 // records copied to the other replica
+	Deleted   uint64 // records deleted from the other replica
+	Conflicts uint64 // records changed on both replicas
+}
+
+// Replicator replicates the routes and the route providers between two
+// replicas, see the file documentation. It is safe for concurrent use.
+type Replicator struct {
+	local, remote *Replica
+	interval      time.Duration
+
+	// serializes the synchronizations, guarding the bases
+	mu        sync.Mutex
+	routes    *replication[Key, Route]
+	providers *replication[ProviderKey, RouteProvider]
+	conflicts func(ctx context.Context, c *ReplicationConflict)
+
+	syncs         atomic.Uint64
+	failures      atomic.Uint64
+	copied        atomic.Uint64
+	deleted       atomic.Uint64
+	conflictCount atomic.Uint64
+}
+
+// NewReplicator creates the replicator synchronizing the replicas every
+// interval, DefaultReplicationInterval if zero, once started with Run.
+func NewReplicator(local, remote *Replica, interval time.Duration) *Replicator {
+	if interval <= 0 {
+		interval = DefaultReplicationInterval
+	}
+	r := &Replicator{local: local, remote: remote, interval: interval}
+	r.routes = &replication[Key, Route]{
+		kind:     "route",
+		key:      func(e *Route) *Key { return e.Key },
+		modified: func(e *Route) int64 { return e.Modified },
+		publish: func(ctx context.Context, replica *Replica, kind events.Kind, k *Key) {
+			replica.Routes.publish(ctx, kind, k)
+		},
+		base: map[Key]int64{},
+	}
+	if local.Providers != nil && remote.Providers != nil {
+		r.providers = &replication[ProviderKey, RouteProvider]{
+			kind: "provider",
+			key:  func(e *RouteProvider) *ProviderKey { return e.Key },
+			modified: func(e *RouteProvider) int64 {
+				return max(e.LastHeartbeat, e.Registered)
+			},
+			base: map[ProviderKey]int64{},
+		}
+	}
+	return r
+}
+
+// SetConflictHandler makes the replicator report the records changed on
+// both replicas to the handler.
+func (r *Replicator) SetConflictHandler(fn func(ctx context.Context, c *ReplicationConflict)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.conflicts = fn
+}
+
+// Sync synchronizes the replicas once, returning the conflicts resolved.
+// The records failing to be read or written are synchronized again by the
+// next synchronization.
+func (r *Replicator) Sync(ctx context.Context) ([]*ReplicationConflict, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var conflicts []*ReplicationConflict
+	err := r.routes.sync(ctx, r, r.local.Routes.Table, r.remote.Routes.Table, &conflicts)
+	if err == nil && r.providers != nil {
+		err = r.providers.sync(ctx, r, r.local.Providers.Table, r.remote.Providers.Table, &conflicts)
+	}
+	r.conflictCount.Add(uint64(len(conflicts)))
+	if r.conflicts != nil {
+		for _, c := range conflicts {
+			r.conflicts(ctx, c)
+		}
+	}
+	if err != nil {
+		r.failures.Add(1)
+		return conflicts, err
+	}
+	r.syncs.Add(1)
+	return conflicts, nil
+}
+
+// Run synchronizes the replicas every interval until the context is done.
+func (r *Replicator) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		_, _ = r.Sync(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stats returns a snapshot of the counters of the replicator.
+func (r *Replicator) Stats() ReplicationStats {
+	return ReplicationStats{
+		Syncs:     r.syncs.Load(),
+		Failures:  r.failures.Load(),
+		Copied:    r.copied.Load(),
+		Deleted:   r.deleted.Load(),
+		Conflicts: r.conflictCount.Load(),
+	}
+}
+
+// replication merges the records of a kind, keeping the modification time
+// of the records as of the last synchronization as the base.
+type replication[K comparable, E any] struct {
+	kind     string
+	key      func(e *E) *K
+	modified func(e *E) int64
+
+	// publishes the event of the record written to the replica, if set
+	publish func(ctx context.Context, replica *Replica, kind events.Kind, k *K)
+
+	base map[K]int64
+}
+
+// list returns the records of the table by key.
+func (s *replication[K, E]) list(ctx context.Context, tbl storage.Table[K, E]) (map[K]*E, error) {
+	list, err := tbl.FindMany(ctx, bson.D{}, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	entries := make(map[K]*E, len(list))
+	for _, e := range list {
+		if k := s.key(e); k != nil {
+			entries[*k] = e
+		}
+	}
+	return entries, nil
+}
+
+// changed reports whether the record of the key, nil if missing, changed
+// since the base.
+func (s *replication[K, E]) changed(k K, e *E) bool {
+	mod, ok := s.base[k]
+	if e == nil {
+		return ok
+	}
+	return !ok || s.modified(e) != mod
+}
+
+// sync merges the records of the replicas, see the file documentation.
+func (s *replication[K, E]) sync(ctx context.Context, r *Replicator, local, remote storage.Table[K, E], conflicts *[]*ReplicationConflict) error {
+	locals, err := s.list(ctx, local)
+	if err != nil {
+		return errors.Wrapf(errors.GetErrCode(err), "failed to list the %ss of %s: %s", s.kind, r.local.Name, err)
+	}
+	remotes, err := s.list(ctx, remote)
+	if err != nil {
+		return errors.Wrapf(errors.GetErrCode(err), "failed to list the %ss of %s: %s", s.kind, r.remote.Name, err)
+	}
+	keys := map[K]bool{}
+	for _, m := range []map[K]*E{locals, remotes} {
+		for k := range m {
+			keys[k] = true
+		}
+	}
+	for k := range s.base {
+		keys[k] = true
+	}
+
+	var failed error
+	for k := range keys {
+		l, rm := locals[k], remotes[k]
+		lChanged, rChanged := s.changed(k, l), s.changed(k, rm)
+		var conflict *ReplicationConflict
+		switch {
+		case l == nil && rm == nil:
+			delete(s.base, k)
+			continue
+		case l != nil && rm != nil && reflect.DeepEqual(l, rm):
+			// same record on both sides
+			s.base[k] = s.modified(l)
+			continue
+		case lChanged && rChanged:
+			conflict = &ReplicationConflict{Kind: s.kind, Key: fmt.Sprint(k)}
+			if s.wins(l, rm, r.local.Name, r.remote.Name) {
+				rChanged = false
+				conflict.Winner, conflict.Loser, conflict.Deleted = r.local.Name, r.remote.Name, rm == nil
+			} else {
+				lChanged = false
+				conflict.Winner, conflict.Loser, conflict.Deleted = r.remote.Name, r.local.Name, l == nil
+			}
+		case !lChanged && !rChanged:
+			continue
+		}
+		var err error
+		if lChanged {
+			err = s.apply(ctx, r, r.remote, remote, k, l)
+		} else {
+			err = s.apply(ctx, r, r.local, local, k, rm)
+		}
+		if err != nil {
+			// retried by the next synchronization, the base being kept
+			failed = errors.Wrapf(errors.GetErrCode(err), "failed to replicate %s %v: %s", s.kind, k, err)
+			continue
+		}
+		if conflict != nil {
+			*conflicts = append(*conflicts, conflict)
+		}
+	}
+	return failed
+}
+
+// wins reports whether the local record wins over the remote one changed
+// concurrently: a record over a deletion, then the last written, then the
+// record of the replica with the greater name.
+func (s *replication[K, E]) wins(l, rm *E, local, remote string) bool {
+	switch {
+	case l == nil || rm == nil:
+		return l != nil
+	case s.modified(l) != s.modified(rm):
+		return s.modified(l) > s.modified(rm)
+	}
+	return local > remote
+}
+
+// apply writes the record of the key to the table of the replica, deleting
+// it if nil, and records it in the base.
+func (s *replication[K, E]) apply(ctx context.Context, r *Replicator, replica *Replica, tbl storage.Table[K, E], k K, e *E) error {
+	if e == nil {
+		if err := tbl.DeleteKey(ctx, &k); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		delete(s.base, k)
+		r.deleted.Add(1)
+		s.published(ctx, replica, events.KindRouteDeleted, &k)
+		return nil
+	}
+	// the record is replaced, so that the fields cleared are not kept
+	if err := tbl.DeleteKey(ctx, &k); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err := tbl.Insert(ctx, &k, e); err != nil {
+		return err
+	}
+	s.base[k] = s.modified(e)
+	r.copied.Add(1)
+	s.published(ctx, replica, events.KindRouteUpdated, &k)
+	return nil
+}
+
+// published publishes the event of the record written to the replica.
+func (s *replication[K, E]) published(ctx context.Context, replica *Replica, kind events.Kind, k *K) {
+	if s.publish != nil {
+		s.publish(ctx, replica, kind, k)
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/storage"
+)
+
+// newReplica returns a replica keeping its routes and providers in memory.
+func newReplica(name string) *Replica {
+	return &Replica{
+		Name:      name,
+		Routes:    NewRouteTableWithStorage(storage.NewMemoryTable[Key, Route]()),
+		Providers: NewRouteProviderTableWithStorage(storage.NewMemoryTable[ProviderKey, RouteProvider]()),
+	}
+}
+
+// putRoute writes the route of the url to the replica as modified at the
+// time, deleting it if the endpoint is empty.
+func putRoute(t *testing.T, replica *Replica, url, endpoint string, modified int64) {
+	t.Helper()
+	ctx := context.Background()
+	key := &Key{Url: url, Method: GET}
+	if err := replica.Routes.DeleteKey(ctx, key); err != nil && !errors.IsNotFound(err) {
+		t.Fatalf("failed to delete route: %s", err)
+	}
+	if endpoint == "" {
+		return
+	}
+	if err := replica.Routes.Insert(ctx, key, &Route{Key: key, Endpoint: endpoint, Modified: modified}); err != nil {
+		t.Fatalf("failed to insert route: %s", err)
+	}
+}
+
+// routeEndpoint returns the endpoint of the route of the url in the
+// replica, empty if missing.
+func routeEndpoint(replica *Replica, url string) string {
+	r, err := replica.Routes.Find(context.Background(), &Key{Url: url, Method: GET})
+	if err != nil {
+		return ""
+	}
+	return r.Endpoint
+}
+
+func TestReplicatorRoutes(t *testing.T) {
+	type change struct {
+		local, remote string
+		modified      int64
+	}
+	tests := []struct {
+		name string
+
+		// routes of /books on both replicas at the first synchronization,
+		// and the changes made on each side before the second one
+		initial, changed change
+
+		// endpoint on both replicas after the second synchronization
+		want string
+
+		// conflict reported, if any
+		winner  string
+		deleted bool
+	}{
+		{
+			name:    "local update is copied",
+			initial: change{"http://v1", "http://v1", 1},
+			changed: change{"http://v2", "http://v1", 2},
+			want:    "http://v2",
+		},
+		{
+			name:    "remote update is copied",
+			initial: change{"http://v1", "http://v1", 1},
+			changed: change{"http://v1", "http://v2", 2},
+			want:    "http://v2",
+		},
+		{
+			name:    "local deletion is replicated",
+			initial: change{"http://v1", "http://v1", 1},
+			changed: change{"", "http://v1", 1},
+			want:    "",
+		},
+		{
+			name:    "remote addition is copied",
+			initial: change{"", "", 0},
+			changed: change{"", "http://v1", 1},
+			want:    "http://v1",
+		},
+		{
+			name:    "concurrent deletions",
+			initial: change{"http://v1", "http://v1", 1},
+			changed: change{"", "", 0},
+			want:    "",
+		},
+		{
+			name:    "update wins over deletion",
+			initial: change{"http://v1", "http://v1", 1},
+			changed: change{"", "http://v2", 2},
+			want:    "http://v2",
+			winner:  "us-east",
+			deleted: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			local, remote := newReplica("eu-west"), newReplica("us-east")
+			r := NewReplicator(local, remote, 0)
+			putRoute(t, local, "/books", tc.initial.local, tc.initial.modified)
+			putRoute(t, remote, "/books", tc.initial.remote, tc.initial.modified)
+			if _, err := r.Sync(ctx); err != nil {
+				t.Fatalf("failed to sync: %s", err)
+			}
+			// the unchanged sides are left as synchronized
+			if tc.changed.local != tc.initial.local {
+				putRoute(t, local, "/books", tc.changed.local, tc.changed.modified)
+			}
+			if tc.changed.remote != tc.initial.remote {
+				putRoute(t, remote, "/books", tc.changed.remote, tc.changed.modified)
+			}
+
+			conflicts, err := r.Sync(ctx)
+			if err != nil {
+				t.Fatalf("failed to sync: %s", err)
+			}
+			for _, replica := range []*Replica{local, remote} {
+				if got := routeEndpoint(replica, "/books"); got != tc.want {
+					t.Errorf("expected endpoint %q on %s, got %q", tc.want, replica.Name, got)
+				}
+			}
+			switch {
+			case tc.winner == "" && len(conflicts) != 0:
+				t.Errorf("unexpected conflicts %v", conflicts)
+			case tc.winner != "" && (len(conflicts) != 1 || conflicts[0].Winner != tc.winner || conflicts[0].Deleted != tc.deleted):
+				t.Errorf("expected a conflict won by %s, got %v", tc.winner, conflicts)
+			}
+		})
+	}
+}
+
+func TestReplicatorLastWriteWins(t *testing.T) {
+	tests := []struct {
+		name           string
+		local, remote  int64
+		winner, loser  string
+		wantEndpoint   string
+		wantLocalWrite bool
+	}{
+		{"local written last", 3, 2, "eu-west", "us-east", "http://eu", false},
+		{"remote written last", 2, 3, "us-east", "eu-west", "http://us", true},
+		{"tie broken by name", 2, 2, "us-east", "eu-west", "http://us", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			local, remote := newReplica("eu-west"), newReplica("us-east")
+			r := NewReplicator(local, remote, 0)
+			var reported []*ReplicationConflict
+			r.SetConflictHandler(func(ctx context.Context, c *ReplicationConflict) {
+				reported = append(reported, c)
+			})
+			putRoute(t, local, "/books", "http://v1", 1)
+			putRoute(t, remote, "/books", "http://v1", 1)
+			if _, err := r.Sync(ctx); err != nil {
+				t.Fatalf("failed to sync: %s", err)
+			}
+
+			// changed on both sides while partitioned
+			putRoute(t, local, "/books", "http://eu", tc.local)
+			putRoute(t, remote, "/books", "http://us", tc.remote)
+			bus := events.NewBus()
+			sub := bus.Subscribe(4)
+			local.Routes.SetPublisher(bus)
+			if _, err := r.Sync(ctx); err != nil {
+				t.Fatalf("failed to sync: %s", err)
+			}
+			for _, replica := range []*Replica{local, remote} {
+				if got := routeEndpoint(replica, "/books"); got != tc.wantEndpoint {
+					t.Errorf("expected endpoint %q on %s, got %q", tc.wantEndpoint, replica.Name, got)
+				}
+			}
+			if len(reported) != 1 || reported[0].Winner != tc.winner || reported[0].Loser != tc.loser || reported[0].Kind != "route" {
+				t.Errorf("expected a route conflict won by %s, got %v", tc.winner, reported)
+			}
+			select {
+			case e := <-sub.C:
+				if !tc.wantLocalWrite || e.Kind != events.KindRouteUpdated {
+					t.Errorf("unexpected event %v", e)
+				}
+			default:
+				if tc.wantLocalWrite {
+					t.Errorf("expected the route copied to the local replica to be published")
+				}
+			}
+
+			// resolved, nothing left to replicate
+			if conflicts, err := r.Sync(ctx); err != nil || len(conflicts) != 0 {
+				t.Errorf("expected the replicas to be converged, got %v: %v", conflicts, err)
+			}
+			if s := r.Stats(); s.Syncs != 3 || s.Conflicts != 1 {
+				t.Errorf("unexpected stats %+v", s)
+			}
+		})
+	}
+}
+
+// failingTable is a route storage failing every read, as a partitioned
+// replica.
+type failingTable struct {
+	storage.Table[Key, Route]
+}
+
+func (failingTable) FindMany(ctx context.Context, filter any, offset, limit int32) ([]*Route, error) {
+	return nil, errors.Wrapf(errors.Unknown, "replica unreachable")
+}
+
+func TestReplicatorPartition(t *testing.T) {
+	ctx := context.Background()
+	local := newReplica("eu-west")
+	reachable := newReplica("us-east")
+	remote := &Replica{Name: "us-east", Routes: NewRouteTableWithStorage(failingTable{reachable.Routes.Table})}
+	r := NewReplicator(local, remote, 0)
+
+	// the local replica keeps serving its routes during the partition
+	if err := local.Routes.AddRoute(ctx, &Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "http://v1"}); err != nil {
+		t.Fatalf("failed to add route: %s", err)
+	}
+	if _, err := r.Sync(ctx); err == nil {
+		t.Fatalf("expected the partitioned sync to fail")
+	}
+	if got := routeEndpoint(local, "/books"); got != "http://v1" {
+		t.Errorf("expected the local route to be kept, got %q", got)
+	}
+	if s := r.Stats(); s.Failures != 1 || s.Syncs != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	// healed
+	r.remote = reachable
+	if _, err := r.Sync(ctx); err != nil {
+		t.Fatalf("failed to sync: %s", err)
+	}
+	if got := routeEndpoint(reachable, "/books"); got != "http://v1" {
+		t.Errorf("expected the route to be replicated once healed, got %q", got)
+	}
+}
+
+func TestReplicatorProviders(t *testing.T) {
+	ctx := context.Background()
+	local, remote := newReplica("eu-west"), newReplica("us-east")
+	r := NewReplicator(local, remote, 0)
+	key := &ProviderKey{Name: "books", Endpoint: "http://books:8080"}
+	if err := local.Providers.Insert(ctx, key, &RouteProvider{Key: key, Registered: 10, LastHeartbeat: 10}); err != nil {
+		t.Fatalf("failed to register provider: %s", err)
+	}
+	if _, err := r.Sync(ctx); err != nil {
+		t.Fatalf("failed to sync: %s", err)
+	}
+	if _, err := remote.Providers.Find(ctx, key); err != nil {
+		t.Fatalf("expected the provider to be replicated: %s", err)
+	}
+
+	// the provider heartbeats to the remote replica, and is deregistered
+	// from the local one meanwhile, the heartbeat winning
+	if err := remote.Providers.Update(ctx, key, &RouteProvider{LastHeartbeat: 20}); err != nil {
+		t.Fatalf("failed to update provider: %s", err)
+	}
+	if err := local.Providers.DeleteKey(ctx, key); err != nil {
+		t.Fatalf("failed to delete provider: %s", err)
+	}
+	conflicts, err := r.Sync(ctx)
+	if err != nil {
+		t.Fatalf("failed to sync: %s", err)
+	}
+	if p, err := local.Providers.Find(ctx, key); err != nil || p.LastHeartbeat != 20 {
+		t.Errorf("expected the heartbeat to be replicated, got %v: %v", p, err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Kind != "provider" || conflicts[0].Winner != "us-east" || !conflicts[0].Deleted {
+		t.Errorf("expected the deletion to be reported, got %v", conflicts)
+	}
+}
+
+func TestSyncRoutesModified(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewRouteTableWithStorage(storage.NewMemoryTable[Key, Route]())
+	inventory := []Route{{Key: &Key{Url: "/authors", Method: GET}, Endpoint: "http://authors:8080"}}
+	if err := tbl.SyncRoutes(ctx, "authors", inventory); err != nil {
+		t.Fatalf("failed to sync routes: %s", err)
+	}
+	first, err := tbl.Find(ctx, inventory[0].Key)
+	if err != nil || first.Modified == 0 {
+		t.Fatalf("expected the modification time to be set, got %v: %v", first, err)
+	}
+
+	// republished unchanged
+	if err := tbl.SyncRoutes(ctx, "authors", inventory); err != nil {
+		t.Fatalf("failed to sync routes: %s", err)
+	}
+	if again, _ := tbl.Find(ctx, inventory[0].Key); again.Modified != first.Modified {
+		t.Errorf("expected the unchanged route to keep its modification time")
+	}
+}
@@ -4,9 +4,18 @@
 package route
 
 import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
 	"github.com/go-core-stack/core/db"
 	"github.com/go-core-stack/core/errors"
-	"github.com/go-core-stack/core/table"
+
+	"github.com/go-core-stack/auth/events"
+	"github.com/go-core-stack/auth/labels"
+	"github.com/go-core-stack/auth/storage"
 )
 
 type MethodType int32
@@ -23,15 +32,43 @@ const (
 	TRACE
 )
 
+// methodNames holds the HTTP method names indexed by MethodType
+var methodNames = [...]string{
+	GET:     "GET",
+	HEAD:    "HEAD",
+	POST:    "POST",
+	PUT:     "PUT",
+	PATCH:   "PATCH",
+	DELETE:  "DELETE",
+	CONNECT: "CONNECT",
+	OPTIONS: "OPTIONS",
+	TRACE:   "TRACE",
+}
+
 type Key struct {
 	Url    string     `bson:"url,omitempty"`
 	Method MethodType `bson:"method,omitempty"`
+
+	// tenant the route belongs to, routes without a tenant are shared by
+	// all the tenants
+	Tenant string `bson:"tenant,omitempty" json:",omitempty"`
 }
 
 type Route struct {
 	Key      *Key   `bson:"key,omitempty"`
 	Endpoint string `bson:"endpoint,omitempty"`
 
+	// additional replicas serving the route along with Endpoint, and the
+	// strategy balancing the requests across them, see Balancer
+	Endpoints []string        `bson:"endpoints,omitempty"`
+	Balance   BalanceStrategy `bson:"balance,omitempty"`
+
+	// API version served by Endpoint, and the endpoints serving other
+	// versions of the same route, selected using the version requested
+	// by the client
+	DefaultVersion string               `bson:"defaultVersion,omitempty"`
+	Versions       []*VersionedEndpoint `bson:"versions,omitempty"`
+
 	// If the route is publically accessible, then rest of the fields
 	// below are not relevant
 	IsPublic *bool `bson:"isPublic,omitempty"`
@@ -43,6 +80,10 @@ type Route struct {
 	// the fields below are not relevant
 	IsUserSpecific *bool `bson:"isUserSpecific,omitempty"`
 
+	// route is a gRPC method, where url is the full method name and the
+	// endpoint is proxied using HTTP/2 (h2c for plain text endpoints)
+	IsGrpc *bool `bson:"isGrpc,omitempty"`
+
 	// RBAC constructs associated with Route
 	Group    string `bson:"group,omitempty"`
 	Resource string `bson:"resource,omitempty"`
@@ -54,15 +95,72 @@ type Route struct {
 	// if first scope is not ou, then it is assumed to be
 	// equivalent to be empty and hence not scoped
 	Scopes []string `bson:"scopes,omitempty"`
+
+	// upstream timeout and retry policy honored by the gateway while
+	// proxying to the endpoint, gateway defaults apply if not set
+	Upstream *UpstreamPolicy `bson:"upstream,omitempty"`
+
+	// shadow targets receiving a copy of sampled requests
+	Mirrors []*Mirror `bson:"mirrors,omitempty"`
+
+	// request and response header manipulation while proxying
+	Headers *HeaderPolicy `bson:"headers,omitempty"`
+
+	// handling of WebSocket, SSE and other long lived connections
+	Stream *StreamPolicy `bson:"stream,omitempty"`
+
+	// enforce the authentication of the route while the middleware only
+	// records the denials, see apikey.WithShadowMode
+	Enforce *bool `bson:"enforce,omitempty"`
+
+	// deprecation, sunset, maintenance and disabled flags of the route
+	Lifecycle *Lifecycle `bson:"lifecycle,omitempty"`
+
+	// free form labels for operational grouping of routes
+	Labels labels.Labels `bson:"labels,omitempty"`
+
+	// provider owning the route, as published using SyncRoutes
+	Provider string `bson:"provider,omitempty"`
+
+	// time the route was last written by AddRoute, UpdateRoute or
+	// SyncRoutes, unix milliseconds, resolving the concurrent changes of
+	// the replicas, see Replicator
+	Modified int64 `bson:"modified,omitempty"`
 }
 
 type RouteTable struct {
-	table.Table[Key, Route]
-	col db.StoreCollection
+	storage.Table[Key, Route]
+	health *HealthTracker
+	events events.Publisher
+	rbac   *RBACMapping
 }
 
 var routeTable *RouteTable
 
+// NewRouteTable returns a route table using the routes collection of the
+// store client, every call returning a new instance.
+func NewRouteTable(client db.StoreClient) (*RouteTable, error) {
+	col := client.GetCollection(ServicesDatabaseName, RoutesCollectionName)
+	tbl, err := storage.NewStoreTable[Key, Route](col)
+	if err != nil {
+		return nil, err
+	}
+	return NewRouteTableWithStorage(tbl), nil
+}
+
+// NewRouteTableWithStorage returns a route table keeping the routes in the
+// storage, e.g. storage.NewMemoryTable for the tests and the embedded uses
+// not running a database.
+func NewRouteTableWithStorage(tbl storage.Table[Key, Route]) *RouteTable {
+	return &RouteTable{
+		Table: tbl,
+	}
+}
+
+// GetRouteTable returns the process wide route table created by
+// LocateRouteTable.
+//
+// Deprecated: keep the table returned by NewRouteTable instead.
 func GetRouteTable() (*RouteTable, error) {
 	if routeTable != nil {
 		return routeTable, nil
@@ -71,17 +169,16 @@ func GetRouteTable() (*RouteTable, error) {
 	return nil, errors.Wrapf(errors.NotFound, "route table not found")
 }
 
+// LocateRouteTable returns the process wide route table, created on the
+// first call.
+//
+// Deprecated: use NewRouteTable.
 func LocateRouteTable(client db.StoreClient) (*RouteTable, error) {
 	if routeTable != nil {
 		return routeTable, nil
 	}
 
-	col := client.GetCollection(ServicesDatabaseName, RoutesCollectionName)
-	tbl := &RouteTable{
-		col: col,
-	}
-
-	err := tbl.Initialize(col)
+	tbl, err := NewRouteTable(client)
 	if err != nil {
 		return nil, err
 	}
@@ -89,3 +186,45 @@ func LocateRouteTable(client db.StoreClient) (*RouteTable, error) {
 
 	return routeTable, nil
 }
+
+// GetAllowedMethods returns the methods for which a route is registered with
+// exactly the given url, sorted in MethodType order. An empty list
+// indicates that the url is not known at all, allowing a gateway to
+// differentiate 404 from 405 responses.
+func (t *RouteTable) GetAllowedMethods(ctx context.Context, url string) ([]MethodType, error) {
+	if t.Table == nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "route table not initialized")
+	}
+	list, err := t.FindMany(ctx, bson.D{{Key: "_id.url", Value: url}}, 0, 0)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Wrapf(errors.GetErrCode(err), "failed to find routes for url %s: %s", url, err)
+	}
+	methods := []MethodType{}
+	for _, e := range list {
+		if e.Key != nil {
+			methods = append(methods, e.Key.Method)
+		}
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i] < methods[j] })
+	return methods, nil
+}
+
+// AllowHeader formats the methods as the value of an HTTP Allow header, to
+// be sent along with a 405 Method Not Allowed response. Unknown method
+// types are skipped.
+func AllowHeader(methods []MethodType) string {
+	names := []string{}
+	for _, m := range methods {
+		if m >= 0 && int(m) < len(methodNames) {
+			names = append(names, methodNames[m])
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// FindByLabels returns the routes whose labels satisfy the selector, e.g.
+// parsed from "env=prod,team=payments", with offset and limit allowing to
+// page through the results, a zero limit returns all the routes.
+func (t *RouteTable) FindByLabels(ctx context.Context, selector labels.Selector, offset, limit int32) ([]*Route, error) {
+	return t.FindMany(ctx, selector.Filter("labels"), offset, limit)
+}
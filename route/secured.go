@@ -0,0 +1,101 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+// SecuredRouteTable exposes the route table APIs on behalf of a caller,
+// authorizing every call against the built-in auth.routes resource, so
+// that the routes are governed by the same RBAC they enforce.
+type SecuredRouteTable struct {
+	tbl   *RouteTable
+	authz rbac.Authorizer
+}
+
+// NewSecuredRouteTable creates the secured view of the route table.
+func NewSecuredRouteTable(tbl *RouteTable, authz rbac.Authorizer) *SecuredRouteTable {
+	return &SecuredRouteTable{
+		tbl:   tbl,
+		authz: authz,
+	}
+}
+
+// AddRoute adds the route, requiring create on auth.routes.
+func (t *SecuredRouteTable) AddRoute(ctx context.Context, id *authctx.Identity, r *Route) error {
+	if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, rbac.VerbCreate); err != nil {
+		return err
+	}
+	return t.tbl.AddRoute(ctx, r)
+}
+
+// UpdateRoute updates the route, requiring update on auth.routes.
+func (t *SecuredRouteTable) UpdateRoute(ctx context.Context, id *authctx.Identity, r *Route) error {
+	if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, rbac.VerbUpdate); err != nil {
+		return err
+	}
+	return t.tbl.UpdateRoute(ctx, r)
+}
+
+// DeleteRoute deletes the route, requiring delete on auth.routes.
+func (t *SecuredRouteTable) DeleteRoute(ctx context.Context, id *authctx.Identity, key *Key) error {
+	if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, rbac.VerbDelete); err != nil {
+		return err
+	}
+	return t.tbl.DeleteRoute(ctx, key)
+}
+
+// GetRoute returns the route, requiring get on auth.routes.
+func (t *SecuredRouteTable) GetRoute(ctx context.Context, id *authctx.Identity, key *Key) (*Route, error) {
+	if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, rbac.VerbGet); err != nil {
+		return nil, err
+	}
+	return t.tbl.GetRoute(ctx, key)
+}
+
+// QueryRoutes returns a page of the routes, requiring list on auth.routes.
+func (t *SecuredRouteTable) QueryRoutes(ctx context.Context, id *authctx.Identity, filter *RouteFilter, offset, limit int32) (*RouteList, error) {
+	if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, rbac.VerbList); err != nil {
+		return nil, err
+	}
+	return t.tbl.QueryRoutes(ctx, filter, offset, limit)
+}
+
+// SyncRoutes reconciles the routes of the provider, requiring both create
+// and delete on auth.routes, as stale routes are removed.
+func (t *SecuredRouteTable) SyncRoutes(ctx context.Context, id *authctx.Identity, provider string, routes []Route) error {
+	for _, verb := range []string{rbac.VerbCreate, rbac.VerbDelete} {
+		if err := rbac.Check(ctx, t.authz, id, rbac.ResourceRoutes, verb); err != nil {
+			return err
+		}
+	}
+	return t.tbl.SyncRoutes(ctx, provider, routes)
+}
+
+// Authorize enforces the tenancy and the RBAC constructs of the route for
+// the caller, requiring the route to be reachable from the tenant of the
+// caller, see AllowsTenant, and the verb on the resource of the route.
+// Public routes, user specific routes and routes without a resource only
+// require the caller to be identified, if at all.
+func (r *Route) Authorize(ctx context.Context, authz rbac.Authorizer, id *authctx.Identity) error {
+	if r.IsPublic != nil && *r.IsPublic {
+		return nil
+	}
+	if id == nil || id.Subject == "" {
+		return errors.Wrapf(errors.Unauthorized, "caller identity not available")
+	}
+	if !r.AllowsTenant(id.Tenant) {
+		return errors.Wrapf(errors.Forbidden, "route not accessible for tenant %s", id.Tenant)
+	}
+	if (r.IsUserSpecific != nil && *r.IsUserSpecific) || r.Resource == "" {
+		return nil
+	}
+	return rbac.Check(ctx, authz, id, r.Resource, r.Verb)
+}
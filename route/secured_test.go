@@ -0,0 +1,83 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+
+	authctx "github.com/go-core-stack/auth/context"
+	"github.com/go-core-stack/auth/rbac"
+)
+
+func TestSecuredRouteTable(t *testing.T) {
+	checks := []string{}
+	authz := rbac.AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+		checks = append(checks, resource+":"+verb)
+		return errors.Wrapf(errors.Forbidden, "denied")
+	})
+	tbl := NewSecuredRouteTable(&RouteTable{}, authz)
+	caller := &authctx.Identity{Subject: "guest"}
+	ctx := context.Background()
+	r := &Route{Key: &Key{Url: "/books", Method: GET}, Endpoint: "http://books:8080"}
+
+	if err := tbl.AddRoute(ctx, caller, r); !errors.IsForbidden(err) {
+		t.Errorf("expected add to be forbidden, got %v", err)
+	}
+	if err := tbl.DeleteRoute(ctx, caller, r.Key); !errors.IsForbidden(err) {
+		t.Errorf("expected delete to be forbidden, got %v", err)
+	}
+	if _, err := tbl.QueryRoutes(ctx, caller, nil, 0, 10); !errors.IsForbidden(err) {
+		t.Errorf("expected list to be forbidden, got %v", err)
+	}
+	if err := tbl.SyncRoutes(ctx, nil, "books", nil); !errors.IsUnauthorized(err) {
+		t.Errorf("expected sync without identity to be unauthorized, got %v", err)
+	}
+	want := []string{"auth.routes:create", "auth.routes:delete", "auth.routes:list"}
+	if len(checks) != len(want) {
+		t.Fatalf("unexpected checks %v", checks)
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Errorf("unexpected checks %v", checks)
+		}
+	}
+}
+
+func TestRouteAuthorize(t *testing.T) {
+	authz := rbac.AuthorizerFunc(func(ctx context.Context, id *authctx.Identity, resource, verb string) error {
+		if id.Subject == "alice" && resource == "books" && verb == "create" {
+			return nil
+		}
+		return errors.Wrapf(errors.Forbidden, "denied")
+	})
+	ctx := context.Background()
+	alice := &authctx.Identity{Subject: "alice"}
+	bob := &authctx.Identity{Subject: "bob"}
+	public := true
+
+	r := &Route{Key: &Key{Url: "/books", Method: POST}, Resource: "books", Verb: "create"}
+	if err := r.Authorize(ctx, authz, alice); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+	if err := r.Authorize(ctx, authz, bob); !errors.IsForbidden(err) {
+		t.Errorf("expected forbidden, got %v", err)
+	}
+	if err := r.Authorize(ctx, authz, nil); !errors.IsUnauthorized(err) {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
+	other := &authctx.Identity{Subject: "alice", Tenant: "other"}
+	r.Key.Tenant = "acme"
+	if err := r.Authorize(ctx, authz, other); !errors.IsForbidden(err) {
+		t.Errorf("expected route of another tenant to be forbidden, got %v", err)
+	}
+	r.Key.Tenant = ""
+
+	r.IsPublic = &public
+	if err := r.Authorize(ctx, authz, nil); err != nil {
+		t.Errorf("unexpected error for public route: %s", err)
+	}
+}
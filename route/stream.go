@@ -0,0 +1,71 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+)
+
+// StreamPolicy describes the handling of long lived connections, i.e.
+// WebSocket upgrades, server sent events and chunked long polls, for a
+// route. Authentication is performed once when the connection is
+// established, optionally re-validated periodically by the gateway.
+type StreamPolicy struct {
+	// allow upgrading and streaming connections for the route
+	Enabled bool `bson:"enabled,omitempty"`
+
+	// interval after which the identity of an established connection is
+	// re-validated, the connection is closed if validation fails, zero
+	// disables re-validation
+	RevalidateInterval time.Duration `bson:"revalidateInterval,omitempty"`
+
+	// maximum duration of a connection, zero for no limit
+	MaxDuration time.Duration `bson:"maxDuration,omitempty"`
+
+	// duration without any data exchanged after which a connection is
+	// closed, zero for no limit
+	IdleTimeout time.Duration `bson:"idleTimeout,omitempty"`
+}
+
+// Allows reports whether the policy allows the streaming connections, the
+// routes without a policy not allowing them.
+func (p *StreamPolicy) Allows() bool {
+	return p != nil && p.Enabled
+}
+
+// IsUpgradeRequest reports whether the request asks for a protocol
+// upgrade, e.g. a WebSocket handshake.
+func IsUpgradeRequest(r *http.Request) bool {
+	return hash.IsUpgradeRequest(r)
+}
+
+// IsEventStreamRequest reports whether the request expects a server sent
+// events stream.
+func IsEventStreamRequest(r *http.Request) bool {
+	return headerHasToken(r.Header, "Accept", "text/event-stream")
+}
+
+// IsStreamingRequest reports whether the request establishes a long lived
+// connection which is to be handled as per the StreamPolicy of the route.
+func IsStreamingRequest(r *http.Request) bool {
+	return IsUpgradeRequest(r) || IsEventStreamRequest(r)
+}
+
+// headerHasToken reports whether the comma separated values of the header
+// contain the token, ignoring case and parameters
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			t, _, _ = strings.Cut(t, ";")
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestStreamingRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string][]string
+
+		upgrade, events bool
+	}{
+		{
+			name: "plain request",
+		},
+		{
+			name:    "json request",
+			headers: map[string][]string{"Accept": {"application/json"}},
+		},
+		{
+			name:    "websocket upgrade",
+			headers: map[string][]string{"Connection": {"Upgrade"}, "Upgrade": {"websocket"}},
+			upgrade: true,
+		},
+		{
+			name:    "upgrade among connection options",
+			headers: map[string][]string{"Connection": {"keep-alive, upgrade"}, "Upgrade": {"websocket"}},
+			upgrade: true,
+		},
+		{
+			name:    "upgrade without protocol",
+			headers: map[string][]string{"Connection": {"Upgrade"}},
+		},
+		{
+			name:    "protocol without upgrade",
+			headers: map[string][]string{"Connection": {"keep-alive"}, "Upgrade": {"websocket"}},
+		},
+		{
+			name:    "event stream",
+			headers: map[string][]string{"Accept": {"text/event-stream"}},
+			events:  true,
+		},
+		{
+			name:    "event stream in another case",
+			headers: map[string][]string{"Accept": {"Text/Event-Stream"}},
+			events:  true,
+		},
+		{
+			name:    "event stream among accepted types",
+			headers: map[string][]string{"Accept": {"application/json, text/event-stream"}},
+			events:  true,
+		},
+		{
+			name:    "event stream with parameters",
+			headers: map[string][]string{"Accept": {"text/html;q=1.0, text/event-stream;q=0.9"}},
+			events:  true,
+		},
+		{
+			name:    "event stream in a repeated header",
+			headers: map[string][]string{"Accept": {"application/json", "text/event-stream"}},
+			events:  true,
+		},
+		{
+			name:    "event stream prefix",
+			headers: map[string][]string{"Accept": {"text/event-streams"}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, _ := http.NewRequest(http.MethodGet, "http://gateway/feed", nil)
+			for name, values := range tc.headers {
+				for _, v := range values {
+					r.Header.Add(name, v)
+				}
+			}
+			if got := IsUpgradeRequest(r); got != tc.upgrade {
+				t.Errorf("expected upgrade %v, got %v", tc.upgrade, got)
+			}
+			if got := IsEventStreamRequest(r); got != tc.events {
+				t.Errorf("expected event stream %v, got %v", tc.events, got)
+			}
+			if got := IsStreamingRequest(r); got != (tc.upgrade || tc.events) {
+				t.Errorf("expected streaming %v, got %v", tc.upgrade || tc.events, got)
+			}
+		})
+	}
+}
+
+func TestStreamPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy *StreamPolicy
+		allows bool
+	}{
+		{"no policy", nil, false},
+		{"disabled", &StreamPolicy{}, false},
+		{"disabled with timeouts", &StreamPolicy{MaxDuration: time.Hour, IdleTimeout: time.Minute}, false},
+		{"enabled", &StreamPolicy{Enabled: true}, true},
+		{
+			name: "enabled with timeouts",
+			policy: &StreamPolicy{
+				Enabled:            true,
+				RevalidateInterval: 30 * time.Second,
+				MaxDuration:        time.Hour,
+				IdleTimeout:        time.Minute,
+			},
+			allows: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.Allows(); got != tc.allows {
+				t.Errorf("expected allows %v, got %v", tc.allows, got)
+			}
+			if tc.policy == nil {
+				return
+			}
+
+			// the timeouts are stored along with the route
+			in := &Route{Key: &Key{Url: "/feed", Method: GET}, Stream: tc.policy}
+			data, err := bson.Marshal(in)
+			if err != nil {
+				t.Fatalf("failed to marshal route: %s", err)
+			}
+			out := &Route{}
+			if err := bson.Unmarshal(data, out); err != nil {
+				t.Fatalf("failed to unmarshal route: %s", err)
+			}
+			if out.Stream == nil || *out.Stream != *tc.policy {
+				t.Errorf("expected stream policy %+v, got %+v", tc.policy, out.Stream)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+
+	"github.com/go-core-stack/auth/events"
+)
+
+// SyncRoutes publishes the route inventory of the provider using the
+// process wide route table, see RouteTable.SyncRoutes.
+//
+// Deprecated: use RouteTable.SyncRoutes of the table from NewRouteTable.
+func SyncRoutes(ctx context.Context, provider string, routes []Route) error {
+	tbl, err := GetRouteTable()
+	if err != nil {
+		return err
+	}
+	return tbl.SyncRoutes(ctx, provider, routes)
+}
+
+// SyncRoutes reconciles the routes owned by the provider with the given
+// route inventory, typically published by a service at startup. The routes
+// are upserted as owned by the provider, and the routes previously owned by
+// the provider which are no longer part of the inventory are removed.
+//
+// Reconciliation is idempotent, replicas of a service publishing the same
+// inventory concurrently converge to the same state. A route owned by a
+// different provider is not taken over, and results in a Forbidden error
+// once the rest of the inventory is reconciled. Likewise, a route
+// conflicting with the routes of other providers or of the inventory
+// itself is skipped, resulting in a *ConflictError. The resource and verb
+// of the routes are derived as per the mapping set with SetRBACMapping, if
+// any.
+func (t *RouteTable) SyncRoutes(ctx context.Context, provider string, routes []Route) error {
+	if t.Table == nil {
+		return errors.Wrapf(errors.InvalidArgument, "route table not initialized")
+	}
+	if provider == "" {
+		return errors.Wrapf(errors.InvalidArgument, "route provider not specified")
+	}
+	if t.rbac != nil {
+		routes = slices.Clone(routes)
+		for i := range routes {
+			t.rbac.Apply(&routes[i])
+		}
+	}
+	keys := []*Key{}
+	for i := range routes {
+		if routes[i].Key == nil || routes[i].Key.Url == "" {
+			return errors.Wrapf(errors.InvalidArgument, "route key not specified")
+		}
+		keys = append(keys, routes[i].Key)
+	}
+
+	// the routes of the other providers along with the inventory, for the
+	// conflict detection
+	registered, err := t.ListRoutes(ctx, nil)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	registered = slices.DeleteFunc(registered, func(r *Route) bool { return r.Provider == provider })
+	for i := range routes {
+		entry := routes[i]
+		entry.Provider = provider
+		registered = append(registered, &entry)
+	}
+
+	var conflict error
+	for i := range routes {
+		entry := routes[i]
+		entry.Provider = provider
+		if err := checkConflict(&entry, registered); err != nil {
+			conflict = err
+			continue
+		}
+		existing, err := t.Find(ctx, entry.Key)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if existing != nil && existing.Provider != "" && existing.Provider != provider {
+			conflict = errors.Wrapf(errors.Forbidden, "route %s %s is owned by provider %s",
+				entry.Key.Method, entry.Key.Url, existing.Provider)
+			continue
+		}
+		// the routes published unchanged keep their modification time,
+		// for the replication, see Replicator
+		entry.Modified = time.Now().UnixMilli()
+		if existing != nil {
+			unchanged := entry
+			unchanged.Modified = existing.Modified
+			if reflect.DeepEqual(&unchanged, existing) {
+				entry.Modified = existing.Modified
+			}
+		}
+		if err := t.Locate(ctx, entry.Key, &entry); err != nil {
+			return errors.Wrapf(errors.GetErrCode(err), "failed to sync route %s: %s", entry.Key.Url, err)
+		}
+	}
+
+	stale := bson.D{
+		{Key: "provider", Value: provider},
+		{Key: "_id", Value: bson.M{"$nin": keys}},
+	}
+	if _, err := t.DeleteByFilter(ctx, stale); err != nil && !errors.IsNotFound(err) {
+		return errors.Wrapf(errors.GetErrCode(err), "failed to remove stale routes of provider %s: %s", provider, err)
+	}
+	if t.events != nil {
+		_ = t.events.Publish(ctx, &events.Event{Kind: events.KindRoutesSynced, Resource: provider})
+	}
+	return conflict
+}
@@ -0,0 +1,23 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+// IsShared reports whether the route is shared by all the tenants.
+func (r *Route) IsShared() bool {
+	return r.Key == nil || r.Key.Tenant == ""
+}
+
+// AllowsTenant reports whether callers of the tenant may reach the route.
+// Callers of the root tenancy reach all the routes, while the callers of
+// other tenants reach the shared routes and the routes of their tenant,
+// except for the routes accessible only for the root tenancy.
+func (r *Route) AllowsTenant(tenant string) bool {
+	if tenant == RootTenant {
+		return true
+	}
+	if r.IsRoot != nil && *r.IsRoot {
+		return false
+	}
+	return r.IsShared() || r.Key.Tenant == tenant
+}
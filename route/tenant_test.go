@@ -0,0 +1,53 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestRouteAllowsTenant(t *testing.T) {
+	root := true
+	shared := &Route{Key: &Key{Url: "/books"}}
+	acme := &Route{Key: &Key{Url: "/books", Tenant: "acme"}}
+	admin := &Route{Key: &Key{Url: "/admin"}, IsRoot: &root}
+
+	tests := []struct {
+		r      *Route
+		tenant string
+		want   bool
+	}{
+		{shared, "acme", true},
+		{shared, "", true},
+		{acme, "acme", true},
+		{acme, "other", false},
+		{acme, RootTenant, true},
+		{admin, "acme", false},
+		{admin, RootTenant, true},
+	}
+	for i, tt := range tests {
+		if got := tt.r.AllowsTenant(tt.tenant); got != tt.want {
+			t.Errorf("test %d: AllowsTenant(%q) = %v, want %v", i, tt.tenant, got, tt.want)
+		}
+	}
+}
+
+func TestTenantKeyCompatible(t *testing.T) {
+	// shared routes keep the key stored before tenancy was introduced
+	b, err := bson.Marshal(&Key{Url: "/books", Method: POST})
+	if err != nil {
+		t.Fatalf("failed to marshal key: %s", err)
+	}
+	legacy, _ := bson.Marshal(bson.D{{Key: "url", Value: "/books"}, {Key: "method", Value: int32(POST)}})
+	if string(b) != string(legacy) {
+		t.Errorf("shared route key changed its encoding")
+	}
+	tenant := "acme"
+	f := &RouteFilter{Tenant: &tenant}
+	if got := f.bson(); len(got) != 1 || got[0].Key != "_id.tenant" || got[0].Value != "acme" {
+		t.Errorf("unexpected tenant filter %v", got)
+	}
+}
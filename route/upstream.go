@@ -0,0 +1,85 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RetryCondition identifies the upstream failures on which the gateway
+// retries a proxied request.
+type RetryCondition string
+
+const (
+	// RetryOnConnectFailure retries when the connection to the endpoint
+	// could not be established, the request never reached the backend
+	RetryOnConnectFailure RetryCondition = "connect-failure"
+
+	// RetryOnReset retries when the connection was reset or closed before
+	// a response was received
+	RetryOnReset RetryCondition = "reset"
+
+	// RetryOnGatewayError retries on 502, 503 and 504 responses
+	RetryOnGatewayError RetryCondition = "gateway-error"
+
+	// RetryOn5xx retries on any 5xx response
+	RetryOn5xx RetryCondition = "5xx"
+)
+
+// UpstreamPolicy holds the resilience settings applied by the gateway while
+// proxying requests of a route to its endpoint, the unset fields disabling
+// the corresponding setting. The requests failing to connect are retried
+// regardless of their method, while the other conditions only retry the
+// idempotent methods, with an exponential backoff between the attempts.
+type UpstreamPolicy struct {
+	// Timeout for the upstream request, including all the retries
+	Timeout time.Duration `bson:"timeout,omitempty"`
+
+	// Retries is the number of retries after the first attempt
+	Retries int32 `bson:"retries,omitempty"`
+
+	// RetryOn lists the conditions triggering a retry, defaults to
+	// connect-failure when retries are configured
+	RetryOn []RetryCondition `bson:"retryOn,omitempty"`
+}
+
+// retryOn returns the effective retry conditions of the policy
+func (p *UpstreamPolicy) retryOn() []RetryCondition {
+	if len(p.RetryOn) == 0 {
+		return []RetryCondition{RetryOnConnectFailure}
+	}
+	return p.RetryOn
+}
+
+// RetryOnStatus reports whether a response with the given status code is
+// to be retried as per the policy.
+func (p *UpstreamPolicy) RetryOnStatus(status int) bool {
+	if p == nil || p.Retries <= 0 {
+		return false
+	}
+	conditions := p.retryOn()
+	if status >= 500 && status <= 599 && slices.Contains(conditions, RetryOn5xx) {
+		return true
+	}
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return slices.Contains(conditions, RetryOnGatewayError)
+	}
+	return false
+}
+
+// RetryOnError reports whether a transport error is to be retried as per
+// the policy, where connectFailure indicates that the connection to the
+// endpoint could not be established.
+func (p *UpstreamPolicy) RetryOnError(connectFailure bool) bool {
+	if p == nil || p.Retries <= 0 {
+		return false
+	}
+	if connectFailure {
+		return slices.Contains(p.retryOn(), RetryOnConnectFailure)
+	}
+	return slices.Contains(p.retryOn(), RetryOnReset)
+}
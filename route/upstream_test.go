@@ -0,0 +1,55 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUpstreamRetryOnStatus(t *testing.T) {
+	tests := []struct {
+		policy *UpstreamPolicy
+		status int
+		retry  bool
+	}{
+		{nil, http.StatusBadGateway, false},
+		{&UpstreamPolicy{RetryOn: []RetryCondition{RetryOnGatewayError}}, http.StatusBadGateway, false},
+		// connect failures only by default
+		{&UpstreamPolicy{Retries: 2}, http.StatusBadGateway, false},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOnGatewayError}}, http.StatusBadGateway, true},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOnGatewayError}}, http.StatusServiceUnavailable, true},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOnGatewayError}}, http.StatusGatewayTimeout, true},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOnGatewayError}}, http.StatusInternalServerError, false},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOn5xx}}, http.StatusInternalServerError, true},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOn5xx}}, http.StatusNotFound, false},
+		{&UpstreamPolicy{Retries: 2, RetryOn: []RetryCondition{RetryOn5xx}}, http.StatusOK, false},
+	}
+	for i, tc := range tests {
+		if retry := tc.policy.RetryOnStatus(tc.status); retry != tc.retry {
+			t.Errorf("case %d: expected retry %v for %d, got %v", i, tc.retry, tc.status, retry)
+		}
+	}
+}
+
+func TestUpstreamRetryOnError(t *testing.T) {
+	tests := []struct {
+		policy         *UpstreamPolicy
+		connectFailure bool
+		retry          bool
+	}{
+		{nil, true, false},
+		{&UpstreamPolicy{}, true, false},
+		{&UpstreamPolicy{Retries: 1}, true, true},
+		{&UpstreamPolicy{Retries: 1}, false, false},
+		{&UpstreamPolicy{Retries: 1, RetryOn: []RetryCondition{RetryOnReset}}, false, true},
+		{&UpstreamPolicy{Retries: 1, RetryOn: []RetryCondition{RetryOnReset}}, true, false},
+		{&UpstreamPolicy{Retries: 1, RetryOn: []RetryCondition{RetryOnGatewayError}}, true, false},
+	}
+	for i, tc := range tests {
+		if retry := tc.policy.RetryOnError(tc.connectFailure); retry != tc.retry {
+			t.Errorf("case %d: expected retry %v, got %v", i, tc.retry, retry)
+		}
+	}
+}
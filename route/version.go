@@ -0,0 +1,78 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+const (
+	// AcceptVersionHeader carries the API version requested by the client
+	AcceptVersionHeader = "Accept-Version"
+
+	// ApiVersionHeader is the alternative header carrying the API version
+	ApiVersionHeader = "X-API-Version"
+)
+
+// VersionedEndpoint is the endpoint serving a specific API version of a
+// route.
+type VersionedEndpoint struct {
+	// API version, e.g. "v2"
+	Version string `bson:"version,omitempty"`
+
+	// endpoint serving the version
+	Endpoint string `bson:"endpoint,omitempty"`
+}
+
+// RequestedVersion returns the API version requested by the client, using
+// the Accept-Version header, followed by the X-API-Version header, and
+// finally a leading version segment of the path, e.g. "/v2/books". An
+// empty string is returned if no version is requested.
+func RequestedVersion(r *http.Request) string {
+	if v := r.Header.Get(AcceptVersionHeader); v != "" {
+		return strings.TrimSpace(v)
+	}
+	if v := r.Header.Get(ApiVersionHeader); v != "" {
+		return strings.TrimSpace(v)
+	}
+	return PathVersion(r.URL.Path)
+}
+
+// PathVersion returns the version segment leading the path, i.e. "v"
+// followed by digits such as "v1" in "/v1/books", or an empty string if
+// the path does not start with a version segment.
+func PathVersion(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if len(segment) < 2 || segment[0] != 'v' {
+		return ""
+	}
+	for _, c := range segment[1:] {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+	return segment
+}
+
+// EndpointForVersion returns the endpoint serving the requested version of
+// the route, the default endpoint of the route is used if no version is
+// requested or when the version matches DefaultVersion.
+func (r *Route) EndpointForVersion(version string) (string, error) {
+	if version == "" || version == r.DefaultVersion {
+		return r.Endpoint, nil
+	}
+	for _, v := range r.Versions {
+		if v != nil && v.Version == version {
+			return v.Endpoint, nil
+		}
+	}
+	if len(r.Versions) == 0 && r.DefaultVersion == "" {
+		// route is not versioned, the version is part of the path
+		return r.Endpoint, nil
+	}
+	return "", errors.Wrapf(errors.NotFound, "api version %s not available", version)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+func TestPathVersion(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+	}{
+		{"/v1/books", "v1"},
+		{"/v12/books/1", "v12"},
+		{"v2/books", "v2"},
+		{"/v3", "v3"},
+		{"/books/v1", ""},
+		{"/v/books", ""},
+		{"/version/books", ""},
+		{"/v1beta/books", ""},
+		{"/V1/books", ""},
+		{"/", ""},
+		{"", ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			if v := PathVersion(tc.path); v != tc.version {
+				t.Errorf("expected version %q, got %q", tc.version, v)
+			}
+		})
+	}
+}
+
+func TestRequestedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		headers map[string]string
+		version string
+	}{
+		{"accept version header", "/v1/books", map[string]string{AcceptVersionHeader: " v3 ", ApiVersionHeader: "v2"}, "v3"},
+		{"api version header", "/v1/books", map[string]string{ApiVersionHeader: "v2"}, "v2"},
+		{"path version", "/v1/books", nil, "v1"},
+		{"no version", "/books", nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			for h, v := range tc.headers {
+				r.Header.Set(h, v)
+			}
+			if v := RequestedVersion(r); v != tc.version {
+				t.Errorf("expected version %q, got %q", tc.version, v)
+			}
+		})
+	}
+}
+
+func TestEndpointForVersion(t *testing.T) {
+	versioned := &Route{
+		Endpoint:       "http://books-v1:8080",
+		DefaultVersion: "v1",
+		Versions: []*VersionedEndpoint{
+			nil,
+			{Version: "v2", Endpoint: "http://books-v2:8080"},
+			{Version: "v3", Endpoint: "http://books-v3:8080"},
+		},
+	}
+	unversioned := &Route{Endpoint: "http://books:8080"}
+	tests := []struct {
+		name     string
+		route    *Route
+		version  string
+		endpoint string
+		notFound bool
+	}{
+		{"no version requested", versioned, "", "http://books-v1:8080", false},
+		{"default version", versioned, "v1", "http://books-v1:8080", false},
+		{"versioned endpoint", versioned, "v2", "http://books-v2:8080", false},
+		{"another versioned endpoint", versioned, "v3", "http://books-v3:8080", false},
+		{"unknown version", versioned, "v4", "", true},
+		{"unversioned route", unversioned, "v4", "http://books:8080", false},
+		{"default version only", &Route{Endpoint: "http://books:8080", DefaultVersion: "v1"}, "v2", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			endpoint, err := tc.route.EndpointForVersion(tc.version)
+			if tc.notFound {
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected not found, got %q, %v", endpoint, err)
+				}
+				return
+			}
+			if err != nil || endpoint != tc.endpoint {
+				t.Errorf("expected endpoint %s, got %q, %v", tc.endpoint, endpoint, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,431 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package storage
+
+import (
+	"bytes"
+	"cmp"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+)
+
+// toDocument converts the value, a struct, bson.D or bson.M, to its bson
+// document, with the nested documents as bson.D and the arrays as bson.A.
+func toDocument(v any) (bson.D, error) {
+	doc := bson.D{}
+	if v == nil {
+		return doc, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "failed to encode document: %s", err)
+	}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "failed to decode document: %s", err)
+	}
+	return doc, nil
+}
+
+// ensureMatches ensures the entry of the key matches the filter, failing
+// with AlreadyExists otherwise.
+func ensureMatches(entry, filter, key any) error {
+	doc, err := toDocument(entry)
+	if err != nil {
+		return err
+	}
+	f, err := toDocument(filter)
+	if err != nil {
+		return err
+	}
+	if ok, err := matches(doc, f); err != nil {
+		return err
+	} else if !ok {
+		return errors.Wrapf(errors.AlreadyExists, "entry with key %v modified concurrently", key)
+	}
+	return nil
+}
+
+// isOperators reports whether the value is a document of query operators.
+func isOperators(v any) (bson.D, bool) {
+	d, ok := v.(bson.D)
+	if !ok || len(d) == 0 || !strings.HasPrefix(d[0].Key, "$") {
+		return nil, false
+	}
+	return d, true
+}
+
+// matches reports whether the document matches the filter.
+func matches(doc, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		var ok bool
+		var err error
+		switch e.Key {
+		case "$and", "$or", "$nor":
+			ok, err = matchLogical(doc, e.Key, e.Value)
+		default:
+			if strings.HasPrefix(e.Key, "$") {
+				return false, errors.Wrapf(errors.InvalidArgument, "unsupported filter operator %s", e.Key)
+			}
+			ok, err = matchCondition(lookup(doc, strings.Split(e.Key, ".")), e.Value)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchLogical evaluates the $and, $or or $nor of the filters.
+func matchLogical(doc bson.D, op string, v any) (bool, error) {
+	filters, ok := v.(bson.A)
+	if !ok || len(filters) == 0 {
+		return false, errors.Wrapf(errors.InvalidArgument, "%s expects a non empty array", op)
+	}
+	for _, f := range filters {
+		sub, ok := f.(bson.D)
+		if !ok {
+			return false, errors.Wrapf(errors.InvalidArgument, "%s expects an array of documents", op)
+		}
+		ok, err := matches(doc, sub)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case op == "$and" && !ok:
+			return false, nil
+		case op == "$or" && ok:
+			return true, nil
+		case op == "$nor" && ok:
+			return false, nil
+		}
+	}
+	return op != "$or", nil
+}
+
+// lookup returns the values at the path of the document, traversing the
+// arrays along the path, none if the field is absent.
+func lookup(v any, path []string) []any {
+	if len(path) == 0 {
+		return []any{v}
+	}
+	switch d := v.(type) {
+	case bson.D:
+		for _, e := range d {
+			if e.Key == path[0] {
+				return lookup(e.Value, path[1:])
+			}
+		}
+	case bson.A:
+		values := []any{}
+		for _, x := range d {
+			if _, ok := x.(bson.D); ok {
+				values = append(values, lookup(x, path)...)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// matchCondition reports whether the values of a field match the
+// condition, a value or a document of operators.
+func matchCondition(values []any, cond any) (bool, error) {
+	ops, ok := isOperators(cond)
+	if !ok {
+		return matchEqual(values, cond), nil
+	}
+	for _, op := range ops {
+		ok, err := matchOperator(values, op.Key, op.Value, ops)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchOperator evaluates the operator on the values of a field, ops being
+// the document of operators it belongs to.
+func matchOperator(values []any, op string, v any, ops bson.D) (bool, error) {
+	switch op {
+	case "$eq":
+		return matchEqual(values, v), nil
+	case "$ne":
+		return !matchEqual(values, v), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		return matchCompare(values, op, v), nil
+	case "$in", "$nin":
+		list, ok := v.(bson.A)
+		if !ok {
+			return false, errors.Wrapf(errors.InvalidArgument, "%s expects an array", op)
+		}
+		in := false
+		for _, x := range list {
+			if matchEqual(values, x) {
+				in = true
+				break
+			}
+		}
+		return in == (op == "$in"), nil
+	case "$exists":
+		return (len(values) != 0) == truthy(v), nil
+	case "$regex":
+		re, err := compileRegex(v, ops)
+		if err != nil {
+			return false, err
+		}
+		return matchRegex(values, re), nil
+	case "$options":
+		return true, nil
+	case "$not":
+		ok, err := matchCondition(values, v)
+		return !ok, err
+	case "$elemMatch":
+		return matchElement(values, v)
+	}
+	return false, errors.Wrapf(errors.InvalidArgument, "unsupported filter operator %s", op)
+}
+
+// matchEqual reports whether any of the values, or of their elements for
+// the arrays, equals the value, nil matching the absent fields.
+func matchEqual(values []any, v any) bool {
+	if re, ok := v.(bson.Regex); ok {
+		return matchRegex(values, regexpOf(re))
+	}
+	if v == nil && len(values) == 0 {
+		return true
+	}
+	for _, x := range values {
+		if equal(x, v) {
+			return true
+		}
+		if a, ok := x.(bson.A); ok {
+			for _, y := range a {
+				if equal(y, v) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchCompare reports whether any of the values, or of their elements
+// for the arrays, compares to the value as per the operator.
+func matchCompare(values []any, op string, v any) bool {
+	check := func(x any) bool {
+		c, ok := compare(x, v)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "$gt":
+			return c > 0
+		case "$gte":
+			return c >= 0
+		case "$lt":
+			return c < 0
+		}
+		return c <= 0
+	}
+	for _, x := range values {
+		if check(x) {
+			return true
+		}
+		if a, ok := x.(bson.A); ok {
+			for _, y := range a {
+				if check(y) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// matchElement reports whether an element of the array values matches the
+// condition, a filter for the document elements or a document of
+// operators.
+func matchElement(values []any, cond any) (bool, error) {
+	filter, ok := cond.(bson.D)
+	if !ok {
+		return false, errors.Wrapf(errors.InvalidArgument, "$elemMatch expects a document")
+	}
+	_, isOps := isOperators(filter)
+	for _, x := range values {
+		a, ok := x.(bson.A)
+		if !ok {
+			continue
+		}
+		for _, y := range a {
+			var ok bool
+			var err error
+			if isOps {
+				ok, err = matchCondition([]any{y}, filter)
+			} else if d, isDoc := y.(bson.D); isDoc {
+				ok, err = matches(d, filter)
+			}
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// regexpOf compiles the bson regular expression, the unsupported ones
+// matching nothing.
+func regexpOf(re bson.Regex) *regexp.Regexp {
+	pattern := re.Pattern
+	if flags := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("ims", r) {
+			return r
+		}
+		return -1
+	}, re.Options); flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	return compiled
+}
+
+// compileRegex compiles the value of a $regex operator, along with the
+// $options of the operators.
+func compileRegex(v any, ops bson.D) (*regexp.Regexp, error) {
+	var re bson.Regex
+	switch p := v.(type) {
+	case bson.Regex:
+		re = p
+	case string:
+		re.Pattern = p
+	default:
+		return nil, errors.Wrapf(errors.InvalidArgument, "$regex expects a string")
+	}
+	for _, op := range ops {
+		if options, ok := op.Value.(string); ok && op.Key == "$options" {
+			re.Options = options
+		}
+	}
+	compiled := regexpOf(re)
+	if compiled == nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "invalid regular expression %s", re.Pattern)
+	}
+	return compiled, nil
+}
+
+// matchRegex reports whether any of the string values, or of their
+// elements for the arrays, matches the regular expression.
+func matchRegex(values []any, re *regexp.Regexp) bool {
+	if re == nil {
+		return false
+	}
+	for _, x := range values {
+		if s, ok := x.(string); ok && re.MatchString(s) {
+			return true
+		}
+		if a, ok := x.(bson.A); ok && matchRegex(a, re) {
+			return true
+		}
+	}
+	return false
+}
+
+// truthy reports whether the operand of $exists is true.
+func truthy(v any) bool {
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	if n, ok := number(v); ok {
+		return n != 0
+	}
+	return v != nil
+}
+
+// number returns the value of the numeric types.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// compare orders the scalar values of the same type, the numbers being
+// compared across their types.
+func compare(a, b any) (int, bool) {
+	if x, ok := number(a); ok {
+		y, ok := number(b)
+		return cmp.Compare(x, y), ok
+	}
+	switch x := a.(type) {
+	case nil:
+		return 0, b == nil
+	case string:
+		y, ok := b.(string)
+		return strings.Compare(x, y), ok
+	case bool:
+		y, ok := b.(bool)
+		switch {
+		case x == y:
+			return 0, ok
+		case y:
+			return -1, ok
+		}
+		return 1, ok
+	case bson.DateTime:
+		y, ok := b.(bson.DateTime)
+		return cmp.Compare(x, y), ok
+	case bson.ObjectID:
+		y, ok := b.(bson.ObjectID)
+		return bytes.Compare(x[:], y[:]), ok
+	}
+	return 0, false
+}
+
+// equal reports whether the values are equal, the documents being equal
+// if they have the same fields in the same order.
+func equal(a, b any) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	switch x := a.(type) {
+	case bson.D:
+		y, ok := b.(bson.D)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i].Key != y[i].Key || !equal(x[i].Value, y[i].Value) {
+				return false
+			}
+		}
+		return true
+	case bson.A:
+		y, ok := b.(bson.A)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,321 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+)
+
+// record is an entry of the memory table, as the documents of the store
+type record struct {
+	id  bson.D
+	doc bson.D
+}
+
+// document returns the document of the record, with its key as _id.
+func (r *record) document() bson.D {
+	return append(bson.D{{Key: "_id", Value: r.id}}, r.doc...)
+}
+
+// memoryTable is the Table keeping the entries in memory, see
+// NewMemoryTable.
+type memoryTable[K any, E any] struct {
+	mu      sync.RWMutex
+	records map[string]*record
+
+	// keys of the records in insertion order, the natural order of the
+	// results
+	order []string
+}
+
+// NewMemoryTable returns an empty table keeping the entries in memory,
+// with the semantics of the store: the entries are encoded as bson
+// documents keyed by _id, Update and Locate set the non empty top level
+// fields, and the filters are evaluated as per the package documentation.
+// The table is safe for concurrent use.
+func NewMemoryTable[K any, E any]() Table[K, E] {
+	return &memoryTable[K, E]{
+		records: map[string]*record{},
+	}
+}
+
+// encodeKey returns the key of the record and its _id document.
+func encodeKey[K any](key *K) (string, bson.D, error) {
+	if key == nil {
+		return "", nil, errors.Wrapf(errors.InvalidArgument, "key not specified")
+	}
+	raw, err := bson.Marshal(key)
+	if err != nil {
+		return "", nil, errors.Wrapf(errors.InvalidArgument, "failed to encode key: %s", err)
+	}
+	id := bson.D{}
+	if err := bson.Unmarshal(raw, &id); err != nil {
+		return "", nil, errors.Wrapf(errors.InvalidArgument, "failed to decode key: %s", err)
+	}
+	return string(raw), id, nil
+}
+
+// encodeEntry returns the document of the entry, without its _id.
+func encodeEntry[E any](entry *E) (bson.D, error) {
+	if entry == nil {
+		return nil, errors.Wrapf(errors.InvalidArgument, "entry not specified")
+	}
+	doc, err := toDocument(entry)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(doc, func(e bson.E) bool { return e.Key == "_id" }), nil
+}
+
+// decode returns the entry of the record.
+func (t *memoryTable[K, E]) decode(r *record) (*E, error) {
+	raw, err := bson.Marshal(r.document())
+	if err != nil {
+		return nil, errors.Wrapf(errors.Unknown, "failed to encode entry: %s", err)
+	}
+	var entry E
+	if err := bson.Unmarshal(raw, &entry); err != nil {
+		return nil, errors.Wrapf(errors.Unknown, "failed to decode entry: %s", err)
+	}
+	return &entry, nil
+}
+
+func (t *memoryTable[K, E]) Insert(ctx context.Context, key *K, entry *E) error {
+	k, id, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	doc, err := encodeEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.records[k]; ok {
+		return errors.Wrapf(errors.AlreadyExists, "entry with key %v already exists", key)
+	}
+	t.records[k] = &record{id: id, doc: doc}
+	t.order = append(t.order, k)
+	return nil
+}
+
+func (t *memoryTable[K, E]) Locate(ctx context.Context, key *K, entry *E) error {
+	return t.update(key, nil, entry, true)
+}
+
+func (t *memoryTable[K, E]) Update(ctx context.Context, key *K, entry *E) error {
+	return t.update(key, nil, entry, false)
+}
+
+func (t *memoryTable[K, E]) UpdateIf(ctx context.Context, key *K, filter any, entry *E) error {
+	f, err := toDocument(filter)
+	if err != nil {
+		return err
+	}
+	return t.update(key, f, entry, false)
+}
+
+// update sets the fields of the entry in the record of the key if it
+// matches the filter, inserting it if missing with upsert.
+func (t *memoryTable[K, E]) update(key *K, filter bson.D, entry *E, upsert bool) error {
+	k, id, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	doc, err := encodeEntry(entry)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	r, ok := t.records[k]
+	if !ok {
+		if !upsert {
+			return errors.Wrapf(errors.NotFound, "entry with key %v not found", key)
+		}
+		t.records[k] = &record{id: id, doc: doc}
+		t.order = append(t.order, k)
+		return nil
+	}
+	if ok, err := matches(r.document(), filter); err != nil {
+		return err
+	} else if !ok {
+		return errors.Wrapf(errors.AlreadyExists, "entry with key %v modified concurrently", key)
+	}
+
+	// the records are replaced rather than modified, the documents being
+	// shared with the readers
+	merged := slices.Clone(r.doc)
+	for _, e := range doc {
+		i := slices.IndexFunc(merged, func(m bson.E) bool { return m.Key == e.Key })
+		if i < 0 {
+			merged = append(merged, e)
+		} else {
+			merged[i] = e
+		}
+	}
+	t.records[k] = &record{id: r.id, doc: merged}
+	return nil
+}
+
+func (t *memoryTable[K, E]) Find(ctx context.Context, key *K) (*E, error) {
+	k, _, err := encodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.RLock()
+	r, ok := t.records[k]
+	t.mu.RUnlock()
+	if !ok {
+		return nil, errors.Wrapf(errors.NotFound, "failed to find entry with key %v", key)
+	}
+	return t.decode(r)
+}
+
+func (t *memoryTable[K, E]) FindMany(ctx context.Context, filter any, offset, limit int32) ([]*E, error) {
+	return t.FindManyWithOpts(ctx, filter, table.WithOffset(offset), table.WithLimit(limit))
+}
+
+func (t *memoryTable[K, E]) FindManyWithOpts(ctx context.Context, filter any, opts ...table.FindOption) ([]*E, error) {
+	findOpts := &table.FindOptions{}
+	for _, opt := range opts {
+		opt(findOpts)
+	}
+	list, err := t.match(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(findOpts.Sort) > 0 {
+		slices.SortStableFunc(list, func(a, b *record) int {
+			return compareBy(a, b, findOpts.Sort)
+		})
+	}
+	if findOpts.Offset != nil && *findOpts.Offset > 0 {
+		list = list[min(int(*findOpts.Offset), len(list)):]
+	}
+	if findOpts.Limit != nil && *findOpts.Limit > 0 {
+		list = list[:min(int(*findOpts.Limit), len(list))]
+	}
+
+	entries := make([]*E, 0, len(list))
+	for _, r := range list {
+		entry, err := t.decode(r)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (t *memoryTable[K, E]) Count(ctx context.Context, filter any) (int64, error) {
+	list, err := t.match(filter)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(list)), nil
+}
+
+func (t *memoryTable[K, E]) DeleteByFilter(ctx context.Context, filter any) (int64, error) {
+	f, err := toDocument(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	remaining, deleted := []string{}, []string{}
+	for _, k := range t.order {
+		ok, err := matches(t.records[k].document(), f)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			deleted = append(deleted, k)
+		} else {
+			remaining = append(remaining, k)
+		}
+	}
+	if len(deleted) == 0 {
+		return 0, errors.Wrapf(errors.NotFound, "no matching entries found to delete")
+	}
+	for _, k := range deleted {
+		delete(t.records, k)
+	}
+	t.order = remaining
+	return int64(len(deleted)), nil
+}
+
+func (t *memoryTable[K, E]) DeleteKey(ctx context.Context, key *K) error {
+	k, _, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.records[k]; !ok {
+		return errors.Wrapf(errors.NotFound, "entry with key %v not found", key)
+	}
+	delete(t.records, k)
+	t.order = slices.DeleteFunc(t.order, func(o string) bool { return o == k })
+	return nil
+}
+
+// match returns the records matching the filter, in insertion order.
+func (t *memoryTable[K, E]) match(filter any) ([]*record, error) {
+	f, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	list := []*record{}
+	for _, k := range t.order {
+		r := t.records[k]
+		ok, err := matches(r.document(), f)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+// compareBy orders the records as per the sort options, the absent fields
+// first in ascending order.
+func compareBy(a, b *record, sort []table.SortOption) int {
+	for _, s := range sort {
+		path := strings.Split(s.Field, ".")
+		x, y := lookup(a.document(), path), lookup(b.document(), path)
+		var c int
+		switch {
+		case len(x) == 0 && len(y) == 0:
+		case len(x) == 0:
+			c = -1
+		case len(y) == 0:
+			c = 1
+		default:
+			c, _ = compare(x[0], y[0])
+		}
+		if c != 0 {
+			return c * int(s.Direction)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,118 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package storage
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-core-stack/core/db"
+	"github.com/go-core-stack/core/table"
+)
+
+/*
+Package storage decouples the route and API key tables from the database
+they are stored in. The tables are built on the Table interface, with two
+backends:
+
+  - NewStoreTable, a collection of the go-core-stack/core db store, as used
+    in production
+  - NewMemoryTable, keeping the entries in memory, for the tests and the
+    embedded uses not running a database
+
+The filters are MongoDB query documents, bson.D or bson.M, the memory
+backend evaluating the subset used by the tables: equality, including on
+nested fields and array elements, $eq, $ne, $gt, $gte, $lt, $lte, $in,
+$nin, $exists, $regex, $not, $elemMatch, $and, $or and $nor.
+
+Every table is an independent instance, any number of them may be used in
+the same process.
+
+# Usage
+
+    routes := route.NewRouteTableWithStorage(storage.NewMemoryTable[route.Key, route.Route]())
+    keys, _ := apikey.NewStoreWithStorage(
+        storage.NewMemoryTable[apikey.KeyId, apikey.Key](),
+        storage.NewMemoryTable[apikey.UsageKey, apikey.Usage](),
+        encryptionKey,
+    )
+*/
+
+// Table stores the entries of type E by key of type K, both structs, see
+// NewStoreTable and NewMemoryTable.
+type Table[K any, E any] interface {
+	// Insert adds the entry, failing with AlreadyExists if the key exists
+	Insert(ctx context.Context, key *K, entry *E) error
+
+	// Locate inserts the entry or updates the existing one
+	Locate(ctx context.Context, key *K, entry *E) error
+
+	// Update sets the non empty fields of the entry, failing with NotFound
+	// if the key does not exist
+	Update(ctx context.Context, key *K, entry *E) error
+
+	// UpdateIf sets the non empty fields of the entry if the entry of the
+	// key matches the filter, failing with NotFound if the key does not
+	// exist and with AlreadyExists if the entry does not match, e.g. once
+	// modified concurrently
+	UpdateIf(ctx context.Context, key *K, filter any, entry *E) error
+
+	// Find returns the entry of the key, failing with NotFound if the key
+	// does not exist
+	Find(ctx context.Context, key *K) (*E, error)
+
+	// FindMany returns the entries matching the filter, a zero limit
+	// returning all of them
+	FindMany(ctx context.Context, filter any, offset, limit int32) ([]*E, error)
+
+	// FindManyWithOpts returns the entries matching the filter, paginated
+	// and sorted as per the options
+	FindManyWithOpts(ctx context.Context, filter any, opts ...table.FindOption) ([]*E, error)
+
+	// Count returns the number of entries matching the filter
+	Count(ctx context.Context, filter any) (int64, error)
+
+	// DeleteByFilter removes the entries matching the filter, failing with
+	// NotFound if none matches
+	DeleteByFilter(ctx context.Context, filter any) (int64, error)
+
+	// DeleteKey removes the entry of the key, failing with NotFound if the
+	// key does not exist
+	DeleteKey(ctx context.Context, key *K) error
+}
+
+// storeTable is the Table stored in a collection of the core db store, see
+// NewStoreTable.
+type storeTable[K any, E any] struct {
+	*table.Table[K, E]
+
+	// serializes the conditional updates
+	mu sync.Mutex
+}
+
+// NewStoreTable returns the table stored in the collection of the core db
+// store. The store updating the entries by key only, UpdateIf evaluates
+// the filter and updates the entry under a lock of the table, atomically
+// for the users of the table in the process, while the updates of the
+// other processes sharing the collection may interleave.
+func NewStoreTable[K any, E any](col db.StoreCollection) (Table[K, E], error) {
+	tbl := &table.Table[K, E]{}
+	if err := tbl.Initialize(col); err != nil {
+		return nil, err
+	}
+	return &storeTable[K, E]{Table: tbl}, nil
+}
+
+func (t *storeTable[K, E]) UpdateIf(ctx context.Context, key *K, filter any, entry *E) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cur, err := t.Find(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := ensureMatches(cur, filter, key); err != nil {
+		return err
+	}
+	return t.Update(ctx, key, entry)
+}
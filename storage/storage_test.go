@@ -0,0 +1,184 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	"github.com/go-core-stack/core/errors"
+	"github.com/go-core-stack/core/table"
+)
+
+// the core table is a storage backend
+var _ Table[testKey, testEntry] = &storeTable[testKey, testEntry]{Table: &table.Table[testKey, testEntry]{}}
+
+type testKey struct {
+	Name   string `bson:"name,omitempty"`
+	Tenant string `bson:"tenant,omitempty"`
+}
+
+type testLabel struct {
+	Key   string `bson:"key,omitempty"`
+	Value string `bson:"value,omitempty"`
+}
+
+type testEntry struct {
+	Key    *testKey          `bson:"key,omitempty"`
+	Owner  string            `bson:"owner,omitempty"`
+	Size   int64             `bson:"size,omitempty"`
+	Tags   []string          `bson:"tags,omitempty"`
+	Labels []*testLabel      `bson:"labels,omitempty"`
+	Meta   map[string]string `bson:"meta,omitempty"`
+}
+
+func TestMemoryTable(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewMemoryTable[testKey, testEntry]()
+
+	key := &testKey{Name: "a"}
+	if err := tbl.Insert(ctx, key, &testEntry{Key: key, Owner: "alice", Size: 1}); err != nil {
+		t.Fatalf("failed to insert entry: %s", err)
+	}
+	if err := tbl.Insert(ctx, key, &testEntry{Key: key}); errors.GetErrCode(err) != errors.AlreadyExists {
+		t.Errorf("expected the duplicate insert to fail, got %v", err)
+	}
+	if err := tbl.Update(ctx, &testKey{Name: "b"}, &testEntry{Owner: "bob"}); !errors.IsNotFound(err) {
+		t.Errorf("expected the update of a missing entry to fail, got %v", err)
+	}
+
+	// update sets the non empty fields only
+	if err := tbl.Update(ctx, key, &testEntry{Size: 2}); err != nil {
+		t.Fatalf("failed to update entry: %s", err)
+	}
+	entry, err := tbl.Find(ctx, key)
+	if err != nil || entry.Owner != "alice" || entry.Size != 2 || entry.Key.Name != "a" {
+		t.Errorf("unexpected entry %+v: %v", entry, err)
+	}
+
+	// conditional updates apply only to the matching entry
+	if err := tbl.UpdateIf(ctx, key, bson.D{{Key: "size", Value: 2}}, &testEntry{Size: 3}); err != nil {
+		t.Fatalf("failed to update matching entry: %s", err)
+	}
+	if err := tbl.UpdateIf(ctx, key, bson.D{{Key: "size", Value: 2}}, &testEntry{Size: 4}); errors.GetErrCode(err) != errors.AlreadyExists {
+		t.Errorf("expected the update of a changed entry to fail, got %v", err)
+	}
+	if err := tbl.UpdateIf(ctx, &testKey{Name: "b"}, nil, &testEntry{Size: 4}); !errors.IsNotFound(err) {
+		t.Errorf("expected the conditional update of a missing entry to fail, got %v", err)
+	}
+	if entry, _ := tbl.Find(ctx, key); entry.Size != 3 {
+		t.Errorf("expected the matching update only, got size %d", entry.Size)
+	}
+	if err := ensureMatches(entry, bson.D{{Key: "owner", Value: "bob"}}, key); errors.GetErrCode(err) != errors.AlreadyExists {
+		t.Errorf("expected the entry to not match, got %v", err)
+	}
+
+	if err := tbl.Locate(ctx, &testKey{Name: "b"}, &testEntry{Owner: "bob"}); err != nil {
+		t.Fatalf("failed to locate entry: %s", err)
+	}
+	if _, err := tbl.Find(ctx, &testKey{Name: "c"}); !errors.IsNotFound(err) {
+		t.Errorf("expected missing entry to not be found, got %v", err)
+	}
+
+	if err := tbl.DeleteKey(ctx, key); err != nil {
+		t.Errorf("failed to delete entry: %s", err)
+	}
+	if err := tbl.DeleteKey(ctx, key); !errors.IsNotFound(err) {
+		t.Errorf("expected the delete of a missing entry to fail, got %v", err)
+	}
+	if n, err := tbl.Count(ctx, nil); err != nil || n != 1 {
+		t.Errorf("expected one entry, got %d: %v", n, err)
+	}
+
+	// the tables are independent
+	other := NewMemoryTable[testKey, testEntry]()
+	if n, _ := other.Count(ctx, nil); n != 0 {
+		t.Errorf("expected the new table to be empty, got %d entries", n)
+	}
+}
+
+func TestMemoryTableFilters(t *testing.T) {
+	ctx := context.Background()
+	tbl := NewMemoryTable[testKey, testEntry]()
+	entries := []*testEntry{
+		{Key: &testKey{Name: "a"}, Owner: "alice", Size: 1, Tags: []string{"red", "blue"}, Meta: map[string]string{"env": "prod"}},
+		{Key: &testKey{Name: "b", Tenant: "acme"}, Owner: "bob", Size: 5, Labels: []*testLabel{{Key: "env", Value: "dev"}}},
+		{Key: &testKey{Name: "c", Tenant: "acme"}, Owner: "carol", Size: 10, Tags: []string{"green"}},
+		{Key: &testKey{Name: "{id}"}, Size: 20},
+	}
+	for _, e := range entries {
+		if err := tbl.Insert(ctx, e.Key, e); err != nil {
+			t.Fatalf("failed to insert entry: %s", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter any
+		names  []string
+	}{
+		{"all", nil, []string{"a", "b", "c", "{id}"}},
+		{"equal", bson.D{{Key: "owner", Value: "bob"}}, []string{"b"}},
+		{"key field", bson.D{{Key: "_id.tenant", Value: "acme"}}, []string{"b", "c"}},
+		{"key", bson.D{{Key: "_id", Value: &testKey{Name: "b", Tenant: "acme"}}}, []string{"b"}},
+		{"exists", bson.D{{Key: "_id.tenant", Value: bson.M{"$exists": false}}}, []string{"a", "{id}"}},
+		{"not equal", bson.D{{Key: "owner", Value: bson.M{"$ne": "alice"}}}, []string{"b", "c", "{id}"}},
+		{"range", bson.D{{Key: "size", Value: bson.D{{Key: "$gt", Value: 1}, {Key: "$lte", Value: int64(10)}}}}, []string{"b", "c"}},
+		{"in", bson.D{{Key: "owner", Value: bson.M{"$in": []string{"alice", "carol"}}}}, []string{"a", "c"}},
+		{"not in keys", bson.D{{Key: "_id", Value: bson.M{"$nin": []*testKey{{Name: "a"}, {Name: "{id}"}}}}}, []string{"b", "c"}},
+		{"array element", bson.D{{Key: "tags", Value: "blue"}}, []string{"a"}},
+		{"map field", bson.D{{Key: "meta.env", Value: "prod"}}, []string{"a"}},
+		{"nested array", bson.D{{Key: "labels.key", Value: "env"}}, []string{"b"}},
+		{"elem match", bson.D{{Key: "labels", Value: bson.M{"$elemMatch": bson.D{{Key: "key", Value: "env"}, {Key: "value", Value: "dev"}}}}}, []string{"b"}},
+		{"regex", bson.D{{Key: "_id.name", Value: bson.Regex{Pattern: `\{|/\*$`}}}, []string{"{id}"}},
+		{"regex operator", bson.D{{Key: "owner", Value: bson.M{"$regex": "^A", "$options": "i"}}}, []string{"a"}},
+		{"or", bson.D{{Key: "$or", Value: bson.A{
+			bson.M{"owner": "alice"},
+			bson.M{"owner": bson.M{"$exists": false}, "size": bson.M{"$gte": 20}},
+		}}}, []string{"a", "{id}"}},
+		{"not", bson.D{{Key: "size", Value: bson.M{"$not": bson.M{"$lt": 10}}}}, []string{"c", "{id}"}},
+	}
+	for _, tt := range tests {
+		list, err := tbl.FindMany(ctx, tt.filter, 0, 0)
+		if err != nil {
+			t.Errorf("%s: find failed: %s", tt.name, err)
+			continue
+		}
+		names := []string{}
+		for _, e := range list {
+			names = append(names, e.Key.Name)
+		}
+		if len(names) != len(tt.names) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.names, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != tt.names[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.names, names)
+				break
+			}
+		}
+	}
+
+	if _, err := tbl.FindMany(ctx, bson.D{{Key: "size", Value: bson.M{"$size": 1}}}, 0, 0); errors.GetErrCode(err) != errors.InvalidArgument {
+		t.Errorf("expected the unsupported operator to fail, got %v", err)
+	}
+
+	list, err := tbl.FindManyWithOpts(ctx, nil,
+		table.WithSort(table.SortOption{Field: "size", Direction: table.SortDescending}),
+		table.WithOffset(1), table.WithLimit(2))
+	if err != nil || len(list) != 2 || list[0].Key.Name != "c" || list[1].Key.Name != "b" {
+		t.Errorf("unexpected sorted page %v: %v", list, err)
+	}
+
+	n, err := tbl.DeleteByFilter(ctx, bson.D{{Key: "_id.tenant", Value: "acme"}})
+	if err != nil || n != 2 {
+		t.Errorf("expected two entries to be deleted, got %d: %v", n, err)
+	}
+	if _, err := tbl.DeleteByFilter(ctx, bson.D{{Key: "_id.tenant", Value: "acme"}}); !errors.IsNotFound(err) {
+		t.Errorf("expected no entry to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+/*
+Package otel implements the telemetry interfaces using OpenTelemetry,
+providing spans around client requests and signature validations, along
+with validation counters and latency histograms.
+
+# Usage
+
+    tracer := otel.NewTracer(otelapi.GetTracerProvider())
+    meter, err := otel.NewMeter(otelapi.GetMeterProvider())
+*/
+
+// instrumentationName identifies the instrumentation scope of this module
+const instrumentationName = "github.com/go-core-stack/auth"
+
+// attributes converts the telemetry attributes to OpenTelemetry attributes
+func attributes(attrs []telemetry.Attribute) []attribute.KeyValue {
+	kv := make([]attribute.KeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		switch v := a.Value.(type) {
+		case string:
+			kv = append(kv, attribute.String(a.Key, v))
+		case bool:
+			kv = append(kv, attribute.Bool(a.Key, v))
+		case int:
+			kv = append(kv, attribute.Int(a.Key, v))
+		case int64:
+			kv = append(kv, attribute.Int64(a.Key, v))
+		case float64:
+			kv = append(kv, attribute.Float64(a.Key, v))
+		}
+	}
+	return kv
+}
+
+// span adapts an OpenTelemetry span to telemetry.Span
+type span struct {
+	span trace.Span
+}
+
+func (s *span) SetAttributes(attrs ...telemetry.Attribute) {
+	s.span.SetAttributes(attributes(attrs)...)
+}
+
+func (s *span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *span) End() {
+	s.span.End()
+}
+
+// tracer adapts an OpenTelemetry tracer to telemetry.Tracer
+type tracer struct {
+	tracer trace.Tracer
+}
+
+func (t *tracer) Start(ctx context.Context, name string, attrs ...telemetry.Attribute) (context.Context, telemetry.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, &span{span: s}
+}
+
+func (t *tracer) SpanFromContext(ctx context.Context) telemetry.Span {
+	return &span{span: trace.SpanFromContext(ctx)}
+}
+
+// NewTracer returns a telemetry.Tracer creating spans using the provider.
+func NewTracer(provider trace.TracerProvider) telemetry.Tracer {
+	return &tracer{
+		tracer: provider.Tracer(instrumentationName),
+	}
+}
+
+// meter records the auth metrics using OpenTelemetry instruments
+type meter struct {
+	validations metric.Int64Counter
+	latency     metric.Float64Histogram
+}
+
+func (m *meter) RecordValidation(ctx context.Context, outcome telemetry.Outcome, duration time.Duration) {
+	result := metric.WithAttributes(attribute.String("result", string(outcome)))
+	m.validations.Add(ctx, 1, result)
+	m.latency.Record(ctx, duration.Seconds(), result)
+}
+
+// NewMeter returns a telemetry.Meter recording the auth.validations
+// counter and the auth.validation.duration histogram using the provider.
+func NewMeter(provider metric.MeterProvider) (telemetry.Meter, error) {
+	m := provider.Meter(instrumentationName)
+	validations, err := m.Int64Counter("auth.validations",
+		metric.WithDescription("Number of signature validations by result"))
+	if err != nil {
+		return nil, err
+	}
+	latency, err := m.Float64Histogram("auth.validation.duration",
+		metric.WithDescription("Duration of signature validations"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return &meter{
+		validations: validations,
+		latency:     latency,
+	}, nil
+}
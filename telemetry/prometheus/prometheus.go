@@ -0,0 +1,138 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package prometheus
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/go-core-stack/auth/telemetry"
+)
+
+/*
+Package prometheus implements the telemetry Metrics as Prometheus
+collectors, registered with the provided registerer:
+
+  - auth_validations_total{result}: signature validations by result
+  - auth_validation_duration_seconds{result}: latency of the validations
+  - auth_client_requests_total{method,code}: requests sent by the client
+    by the status code of the response, "error" if the request failed
+  - auth_client_request_duration_seconds{method}: latency of the requests
+  - auth_cache_lookups_total{cache,result}: cache hits and misses, of the
+    route cache of the gateway and of the caching secret resolver
+  - auth_cache_evictions_total{cache}: entries evicted by the size bound
+  - auth_budget_exceeded_total{stage}: stages of the authentication
+    exceeding their latency budget
+
+Nothing is collected unless the metrics are passed to the WithMetrics
+option of the hash, client, gateway and apikey packages.
+
+# Usage
+
+    metrics, err := prometheus.NewMetrics(promclient.DefaultRegisterer)
+
+    validator := hash.NewValidator(60, hash.WithMetrics(metrics))
+    resolver := hash.NewCachingSecretResolver(store, hash.WithCacheMetrics(metrics))
+    cli, _ := client.New(endpoint, client.WithCredentials(keyId, secret), client.WithMetrics(metrics))
+    gw, _ := gateway.New(auth, routes, gateway.WithMetrics(metrics))
+    handler = keys.Middleware(validator, routes, apikey.WithMetrics(metrics))(handler)
+*/
+
+// namespace prefixes the names of the metrics
+const namespace = "auth"
+
+// metrics records the auth metrics with the Prometheus collectors
+type metrics struct {
+	validations     *prom.CounterVec
+	latency         *prom.HistogramVec
+	requests        *prom.CounterVec
+	requestsLatency *prom.HistogramVec
+	lookups         *prom.CounterVec
+	evictions       *prom.CounterVec
+	budgets         *prom.CounterVec
+}
+
+func (m *metrics) RecordValidation(ctx context.Context, outcome telemetry.Outcome, duration time.Duration) {
+	m.validations.WithLabelValues(string(outcome)).Inc()
+	m.latency.WithLabelValues(string(outcome)).Observe(duration.Seconds())
+}
+
+func (m *metrics) RecordClientRequest(ctx context.Context, method string, status int, duration time.Duration) {
+	code := "error"
+	if status != 0 {
+		code = strconv.Itoa(status)
+	}
+	m.requests.WithLabelValues(method, code).Inc()
+	m.requestsLatency.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+func (m *metrics) RecordCacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.lookups.WithLabelValues(cache, result).Inc()
+}
+
+func (m *metrics) RecordCacheEviction(cache string) {
+	m.evictions.WithLabelValues(cache).Inc()
+}
+
+func (m *metrics) RecordBudgetExceeded(stage string) {
+	m.budgets.WithLabelValues(stage).Inc()
+}
+
+// NewMetrics returns the telemetry.Metrics recording the auth metrics with
+// Prometheus collectors registered with the registerer, failing if any of
+// them is already registered.
+func NewMetrics(reg prom.Registerer) (telemetry.Metrics, error) {
+	m := &metrics{
+		validations: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "validations_total",
+			Help:      "Number of signature validations by result.",
+		}, []string{"result"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "validation_duration_seconds",
+			Help:      "Duration of signature validations.",
+			Buckets:   prom.ExponentialBuckets(0.00001, 4, 8),
+		}, []string{"result"}),
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "client_requests_total",
+			Help:      "Number of requests sent by the client by status code.",
+		}, []string{"method", "code"}),
+		requestsLatency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "client_request_duration_seconds",
+			Help:      "Duration of requests sent by the client.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"method"}),
+		lookups: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of cache lookups by cache and result, hit or miss.",
+		}, []string{"cache", "result"}),
+		evictions: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_evictions_total",
+			Help:      "Number of cache entries evicted due to the size bound.",
+		}, []string{"cache"}),
+		budgets: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "budget_exceeded_total",
+			Help:      "Number of authentication stages exceeding their latency budget.",
+		}, []string{"stage"}),
+	}
+	for _, c := range []prom.Collector{m.validations, m.latency, m.requests, m.requestsLatency, m.lookups, m.evictions, m.budgets} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
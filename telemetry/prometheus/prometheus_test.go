@@ -0,0 +1,114 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/go-core-stack/auth/client"
+	"github.com/go-core-stack/auth/hash"
+)
+
+// counter returns the value of the counter with the labels, 0 if absent.
+func counter(t *testing.T, reg *prom.Registry, name string, labels map[string]string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %s", err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if matches(m, labels) {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+// matches reports whether the metric carries all the labels.
+func matches(m *dto.Metric, labels map[string]string) bool {
+	found := 0
+	for _, l := range m.GetLabel() {
+		if v, ok := labels[l.GetName()]; ok && v == l.GetValue() {
+			found++
+		}
+	}
+	return found == len(labels)
+}
+
+func TestMetrics(t *testing.T) {
+	reg := prom.NewRegistry()
+	metrics, err := NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("failed to create metrics: %s", err)
+	}
+	if _, err := NewMetrics(reg); err == nil {
+		t.Errorf("expected registering the metrics twice to fail")
+	}
+
+	validator := hash.NewValidator(60, hash.WithMetrics(metrics))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ok, _ := validator.Validate(r, "supersecret"); !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	for _, secret := range []string{"supersecret", "supersecret", "othersecret"} {
+		cli, err := client.New(server.URL, client.WithCredentials("test-key", secret), client.WithMetrics(metrics))
+		if err != nil {
+			t.Fatalf("failed to create client: %s", err)
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/resource", nil)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		resp.Body.Close()
+	}
+
+	if v := counter(t, reg, "auth_validations_total", map[string]string{"result": "success"}); v != 2 {
+		t.Errorf("expected 2 successful validations, got %v", v)
+	}
+	if v := counter(t, reg, "auth_validations_total", map[string]string{"result": "mismatch"}); v != 1 {
+		t.Errorf("expected 1 mismatched validation, got %v", v)
+	}
+	if v := counter(t, reg, "auth_client_requests_total", map[string]string{"method": "GET", "code": "200"}); v != 2 {
+		t.Errorf("expected 2 client requests with 200, got %v", v)
+	}
+	if v := counter(t, reg, "auth_client_requests_total", map[string]string{"code": "401"}); v != 1 {
+		t.Errorf("expected 1 client request with 401, got %v", v)
+	}
+
+	resolver := hash.NewCachingSecretResolver(hash.SecretResolverFunc(func(ctx context.Context, keyId string) (string, error) {
+		return "secret-" + keyId, nil
+	}), hash.WithMaxCachedSecrets(1), hash.WithCacheMetrics(metrics))
+	for _, keyId := range []string{"a", "a", "b", "a"} {
+		_, _ = resolver.GetSecret(context.Background(), keyId)
+	}
+	if v := counter(t, reg, "auth_cache_lookups_total", map[string]string{"cache": "secrets", "result": "hit"}); v != 1 {
+		t.Errorf("expected 1 cache hit, got %v", v)
+	}
+	if v := counter(t, reg, "auth_cache_lookups_total", map[string]string{"cache": "secrets", "result": "miss"}); v != 3 {
+		t.Errorf("expected 3 cache misses, got %v", v)
+	}
+	if v := counter(t, reg, "auth_cache_evictions_total", map[string]string{"cache": "secrets"}); v != 2 {
+		t.Errorf("expected 2 cache evictions, got %v", v)
+	}
+
+	metrics.RecordBudgetExceeded("policy")
+	if v := counter(t, reg, "auth_budget_exceeded_total", map[string]string{"stage": "policy"}); v != 1 {
+		t.Errorf("expected 1 exceeded budget, got %v", v)
+	}
+}
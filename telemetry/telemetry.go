@@ -0,0 +1,153 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+/*
+Package telemetry defines the minimal tracing and metrics interfaces used to
+instrument the client, the hash Validator, and the middlewares of this
+module, without depending on any specific telemetry library. Nothing is
+recorded unless an implementation is injected using the respective
+options, so users without telemetry pay nothing.
+
+The telemetry/otel package provides the OpenTelemetry implementation, and
+the telemetry/prometheus package exposes the Metrics as Prometheus
+collectors.
+
+# Usage
+
+    import (
+        "go.opentelemetry.io/otel"
+
+        "github.com/go-core-stack/auth/client"
+        "github.com/go-core-stack/auth/hash"
+        authotel "github.com/go-core-stack/auth/telemetry/otel"
+    )
+
+    tracer := authotel.NewTracer(otel.GetTracerProvider())
+    meter, _ := authotel.NewMeter(otel.GetMeterProvider())
+
+    cli, _ := client.New(endpoint, client.WithCredentials(keyId, secret), client.WithTracer(tracer))
+    validator := hash.NewValidator(60, hash.WithTracer(tracer), hash.WithMeter(meter))
+
+    // or with Prometheus
+    metrics, _ := authprom.NewMetrics(prometheus.DefaultRegisterer)
+    cli, _ = client.New(endpoint, client.WithCredentials(keyId, secret), client.WithMetrics(metrics))
+    validator = hash.NewValidator(60, hash.WithMetrics(metrics))
+*/
+
+// Attribute is a key value pair annotating a span, supported value types
+// are string, bool, int, int64 and float64.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a unit of work being traced.
+type Span interface {
+	// SetAttributes annotates the span
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError records the error and marks the span as failed
+	RecordError(err error)
+
+	// End completes the span
+	End()
+}
+
+// Tracer creates spans.
+type Tracer interface {
+	// Start creates a span as child of the span available in the context,
+	// if any, returning the context carrying the new span
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+
+	// SpanFromContext returns the span available in the context, allowing
+	// it to be enriched, e.g. with the authenticated API key ID
+	SpanFromContext(ctx context.Context) Span
+}
+
+// Outcome is the result of a signature validation.
+type Outcome string
+
+const (
+	// OutcomeSuccess indicates a valid signature
+	OutcomeSuccess Outcome = "success"
+
+	// OutcomeExpired indicates a request outside the validity window
+	OutcomeExpired Outcome = "expired"
+
+	// OutcomeMismatch indicates a signature not matching the request
+	OutcomeMismatch Outcome = "mismatch"
+
+	// OutcomeInvalid indicates missing or malformed authentication headers,
+	// or a disallowed algorithm or version
+	OutcomeInvalid Outcome = "invalid"
+)
+
+// Meter records metrics of the auth operations.
+type Meter interface {
+	// RecordValidation counts a signature validation with its outcome and
+	// records the time taken
+	RecordValidation(ctx context.Context, outcome Outcome, duration time.Duration)
+}
+
+// Names of the caches reported to Metrics.
+const (
+	// CacheRoutes is the route cache of the gateway
+	CacheRoutes = "routes"
+
+	// CacheSecrets is the cache of the CachingSecretResolver
+	CacheSecrets = "secrets"
+)
+
+// Metrics records the metrics of all the auth operations, the validations
+// along with the requests sent by the client, the cache efficiency and the
+// latency budgets exceeded.
+// The telemetry/prometheus package provides the Prometheus implementation.
+type Metrics interface {
+	Meter
+
+	// RecordClientRequest counts a request sent by the client with the
+	// status code of its response, 0 if it failed, and records the time
+	// taken
+	RecordClientRequest(ctx context.Context, method string, status int, duration time.Duration)
+
+	// RecordCacheLookup counts a lookup of the named cache as a hit or
+	// a miss
+	RecordCacheLookup(cache string, hit bool)
+
+	// RecordCacheEviction counts an entry of the named cache evicted due
+	// to its size bound
+	RecordCacheEviction(cache string)
+
+	// RecordBudgetExceeded counts a stage of the authentication exceeding
+	// its latency budget, see apikey.WithBudget
+	RecordBudgetExceeded(stage string)
+}
+
+// noopSpan is the Span used when no Tracer is configured.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// NoopSpan returns a Span discarding everything.
+func NoopSpan() Span {
+	return noopSpan{}
+}
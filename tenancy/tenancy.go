@@ -0,0 +1,229 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+)
+
+/*
+Package tenancy resolves the tenant of the requests with pluggable
+strategies, so that every consumer extracts the tenant the same way:
+
+  - PathSegment: a segment of the path, e.g. /tenants/{tenant}/books
+  - Subdomain: the subdomain of a base domain, e.g. {tenant}.api.example.com
+  - Header: a request header, e.g. X-Tenant-Id
+  - Claim: a claim of the token presented with the request
+  - AuthContext: the tenant of the caller authenticated by the middleware
+  - APIKey: the tenant the API key signing the request belongs to
+
+The strategies are combined in an ordered Chain, configured per
+deployment, the first strategy finding a tenant wins.
+
+# Usage
+
+    resolver := tenancy.Chain(
+        tenancy.AuthContext(),
+        tenancy.Subdomain("api.example.com"),
+        tenancy.Header("X-Tenant-Id"),
+    )
+    handler = tenancy.Middleware(resolver)(handler)
+
+    // in the handler
+    tenant, ok := tenancy.FromContext(r.Context())
+*/
+
+var (
+	// ErrNoTenant is returned by a strategy not finding a tenant in the
+	// request, the Chain continues with the next strategy.
+	ErrNoTenant = errors.New("tenant not found in request")
+
+	// ErrInvalidTenant is returned for malformed tenant names.
+	ErrInvalidTenant = errors.New("invalid tenant")
+)
+
+// maxTenantLength bounds the length of the tenant names.
+const maxTenantLength = 128
+
+// TenantResolver resolves the tenant of a request.
+type TenantResolver interface {
+	// ResolveTenant returns the tenant of the request, ErrNoTenant if the
+	// request carries none.
+	ResolveTenant(r *http.Request) (string, error)
+}
+
+// ResolverFunc is an adapter allowing the use of an ordinary function as a
+// TenantResolver.
+type ResolverFunc func(r *http.Request) (string, error)
+
+// ResolveTenant calls f(r).
+func (f ResolverFunc) ResolveTenant(r *http.Request) (string, error) {
+	return f(r)
+}
+
+// validate ensures the tenant is a well formed name, rejecting values
+// smuggling path separators or control characters.
+func validate(tenant string) (string, error) {
+	if tenant == "" {
+		return "", ErrNoTenant
+	}
+	if len(tenant) > maxTenantLength {
+		return "", fmt.Errorf("%w: too long", ErrInvalidTenant)
+	}
+	for _, c := range tenant {
+		if c <= ' ' || c == '/' || c == '\\' || c == 0x7f {
+			return "", fmt.Errorf("%w: %q", ErrInvalidTenant, tenant)
+		}
+	}
+	return tenant, nil
+}
+
+// PathSegment resolves the tenant from the segment of the path at the
+// index, zero being the first segment, e.g. index 1 for
+// /tenants/{tenant}/books.
+func PathSegment(index int) TenantResolver {
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if index < 0 || index >= len(segments) {
+			return "", ErrNoTenant
+		}
+		return validate(segments[index])
+	})
+}
+
+// Subdomain resolves the tenant from the subdomain of the base domain,
+// e.g. "acme" for the host acme.api.example.com and the base domain
+// api.example.com. Nested subdomains are not tenants.
+func Subdomain(baseDomain string) TenantResolver {
+	suffix := "." + strings.ToLower(strings.Trim(baseDomain, "."))
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		host := r.Host
+		if i := strings.LastIndexByte(host, ':'); i >= 0 && !strings.Contains(host[i:], "]") {
+			host = host[:i]
+		}
+		sub, ok := strings.CutSuffix(strings.ToLower(strings.TrimSuffix(host, ".")), suffix)
+		if !ok || strings.Contains(sub, ".") {
+			return "", ErrNoTenant
+		}
+		return validate(sub)
+	})
+}
+
+// Header resolves the tenant from the request header.
+func Header(name string) TenantResolver {
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		return validate(strings.TrimSpace(r.Header.Get(name)))
+	})
+}
+
+// ClaimsFunc returns the verified claims of the token presented with the
+// request, failing if the token is invalid.
+type ClaimsFunc func(r *http.Request) (map[string]any, error)
+
+// Claim resolves the tenant from the string claim of the token presented
+// with the request, requests without a token yield ErrNoTenant as long as
+// claims does.
+func Claim(name string, claims ClaimsFunc) TenantResolver {
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		c, err := claims(r)
+		if err != nil {
+			return "", err
+		}
+		tenant, _ := c[name].(string)
+		return validate(tenant)
+	})
+}
+
+// AuthContext resolves the tenant of the caller authenticated by the
+// validation middleware, see model.FromContext.
+func AuthContext() TenantResolver {
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		a, ok := model.FromContext(r.Context())
+		if !ok {
+			return "", ErrNoTenant
+		}
+		return validate(a.Tenant)
+	})
+}
+
+// KeyTenantFunc returns the tenant the API key belongs to.
+type KeyTenantFunc func(ctx context.Context, keyId string) (string, error)
+
+// APIKey resolves the tenant the API key signing the request, as carried
+// in the key id header of the validator, is associated with. The
+// signature is not validated, so the tenant must only be trusted once the
+// request is authenticated.
+func APIKey(v hash.Validator, lookup KeyTenantFunc) TenantResolver {
+	return ResolverFunc(func(r *http.Request) (string, error) {
+		keyId := v.GetKeyId(r)
+		if keyId == "" {
+			return "", ErrNoTenant
+		}
+		tenant, err := lookup(r.Context(), keyId)
+		if err != nil {
+			return "", err
+		}
+		return validate(tenant)
+	})
+}
+
+// chain tries the resolvers in order.
+type chain []TenantResolver
+
+// ResolveTenant returns the tenant found by the first resolver, failing
+// with ErrNoTenant if none finds one, or with the first error other than
+// ErrNoTenant.
+func (c chain) ResolveTenant(r *http.Request) (string, error) {
+	for _, resolver := range c {
+		tenant, err := resolver.ResolveTenant(r)
+		if errors.Is(err, ErrNoTenant) {
+			continue
+		}
+		return tenant, err
+	}
+	return "", ErrNoTenant
+}
+
+// Chain returns the resolver trying the resolvers in order, the first one
+// finding a tenant wins.
+func Chain(resolvers ...TenantResolver) TenantResolver {
+	return chain(resolvers)
+}
+
+// struct identifier for the context
+type tenantKey struct{}
+
+// WithTenant returns a new context with the resolved tenant attached.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant resolved by the Middleware, if any.
+func FromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// Middleware returns a middleware attaching the tenant resolved for the
+// request to its context, rejecting the requests without a valid tenant
+// with 400.
+func Middleware(resolver TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant, err := resolver.ResolveTenant(r)
+			if err != nil {
+				http.Error(w, "tenant not resolved", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+		})
+	}
+}
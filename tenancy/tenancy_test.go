@@ -0,0 +1,87 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package tenancy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+)
+
+func TestStrategies(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "http://acme.api.example.com:8443/tenants/globex/books", nil)
+	req.Header.Set("X-Tenant-Id", "initech")
+	req.Header.Set("x-api-key-id", "k1")
+	req = req.WithContext(model.WithAuthContext(req.Context(), &model.AuthContext{Tenant: "umbrella"}))
+
+	keys := func(ctx context.Context, keyId string) (string, error) {
+		if keyId == "k1" {
+			return "hooli", nil
+		}
+		return "", errors.New("unknown key")
+	}
+	claims := func(r *http.Request) (map[string]any, error) {
+		return map[string]any{"tenant": "wayne"}, nil
+	}
+	cases := map[string]struct {
+		resolver TenantResolver
+		expected string
+	}{
+		"path":      {PathSegment(1), "globex"},
+		"subdomain": {Subdomain("api.example.com"), "acme"},
+		"header":    {Header("X-Tenant-Id"), "initech"},
+		"claim":     {Claim("tenant", claims), "wayne"},
+		"auth":      {AuthContext(), "umbrella"},
+		"apikey":    {APIKey(hash.NewValidator(60), keys), "hooli"},
+	}
+	for name, c := range cases {
+		if tenant, err := c.resolver.ResolveTenant(req); err != nil || tenant != c.expected {
+			t.Errorf("%s: expected %s, got %q %v", name, c.expected, tenant, err)
+		}
+	}
+
+	other := httptest.NewRequest(http.MethodGet, "http://a.b.api.example.com/", nil)
+	for name, resolver := range map[string]TenantResolver{
+		"path":      PathSegment(3),
+		"subdomain": Subdomain("api.example.com"),
+		"header":    Header("X-Tenant-Id"),
+		"auth":      AuthContext(),
+	} {
+		if _, err := resolver.ResolveTenant(other); !errors.Is(err, ErrNoTenant) {
+			t.Errorf("%s: expected no tenant, got %v", name, err)
+		}
+	}
+
+	other.Header.Set("X-Tenant-Id", "acme\x00")
+	if _, err := Header("X-Tenant-Id").ResolveTenant(other); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected invalid tenant, got %v", err)
+	}
+}
+
+func TestChainMiddleware(t *testing.T) {
+	resolver := Chain(AuthContext(), Header("X-Tenant-Id"), Subdomain("api.example.com"))
+	var got string
+	h := Middleware(resolver)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "http://acme.api.example.com/books", nil)
+	req.Header.Set("X-Tenant-Id", "initech")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || got != "initech" {
+		t.Errorf("expected the first strategy finding a tenant to win, got %d %q", rec.Code, got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://api.example.com/books", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 without tenant, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package throttle
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// SemaphoreStore keeps the number of requests in flight per caller.
+// Implementations shared across the replicas, e.g. backed by a cache, must
+// acquire atomically so that a caller never exceeds its limit.
+type SemaphoreStore interface {
+	// Acquire takes a slot for the caller if less than limit are taken,
+	// reporting whether the slot is acquired.
+	Acquire(ctx context.Context, key string, limit int) (bool, error)
+
+	// Release returns a slot acquired by the caller.
+	Release(ctx context.Context, key string) error
+}
+
+// memorySemaphoreStore keeps the slots in memory.
+type memorySemaphoreStore struct {
+	mu       sync.Mutex
+	inflight map[string]int
+}
+
+// Acquire takes a slot for the caller.
+func (s *memorySemaphoreStore) Acquire(ctx context.Context, key string, limit int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[key] >= limit {
+		return false, nil
+	}
+	s.inflight[key]++
+	return true, nil
+}
+
+// Release returns a slot of the caller, dropping the callers without any
+// request in flight.
+func (s *memorySemaphoreStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight[key] <= 1 {
+		delete(s.inflight, key)
+		return nil
+	}
+	s.inflight[key]--
+	return nil
+}
+
+// NewMemorySemaphoreStore returns a SemaphoreStore for a single instance,
+// keeping the slots in memory.
+func NewMemorySemaphoreStore() SemaphoreStore {
+	return &memorySemaphoreStore{inflight: map[string]int{}}
+}
+
+// ConcurrencyLimiter bounds the number of requests in flight per caller,
+// so that a single misbehaving integration cannot saturate the worker
+// pool of a backend even within its rate limit.
+type ConcurrencyLimiter struct {
+	limit int
+	store SemaphoreStore
+}
+
+// NewConcurrencyLimiter creates the limiter allowing up to limit requests
+// in flight per caller, keeping the slots in store, in memory if nil.
+func NewConcurrencyLimiter(limit int, store SemaphoreStore) *ConcurrencyLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	if store == nil {
+		store = NewMemorySemaphoreStore()
+	}
+	return &ConcurrencyLimiter{limit: limit, store: store}
+}
+
+// Limit returns the maximum number of requests in flight per caller.
+func (l *ConcurrencyLimiter) Limit() int {
+	return l.limit
+}
+
+// Middleware returns a middleware bounding the requests in flight per
+// caller as identified by key, ByKeyId if nil, rejecting the requests over
+// the limit with 429 and a Retry-After hint. The slot is released once the
+// wrapped handler returns.
+func (l *ConcurrencyLimiter) Middleware(key KeyFunc) func(http.Handler) http.Handler {
+	if key == nil {
+		key = ByKeyId
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k := key(r)
+			ok, err := l.store.Acquire(r.Context(), k, l.limit)
+			if err != nil {
+				http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			if !ok {
+				w.Header().Set(RetryAfterHeader, strconv.Itoa(1))
+				http.Error(w, "too many concurrent requests", http.StatusTooManyRequests)
+				return
+			}
+			// release even if the request context is already cancelled
+			defer func() { _ = l.store.Release(context.WithoutCancel(r.Context()), k) }()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package throttle
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/internal/lru"
+)
+
+// RateStore keeps the token buckets of the callers. Implementations shared
+// across the replicas, e.g. backed by Redis, must take the tokens
+// atomically so that the fleet enforces a single quota per caller.
+type RateStore interface {
+	// Take takes a token for the caller from its bucket refilled as per
+	// the policy.
+	Take(ctx context.Context, key string, p Policy) (Decision, error)
+}
+
+// memoryRateStore keeps the buckets in memory.
+type memoryRateStore struct {
+	mu      sync.Mutex
+	buckets *lru.Cache[string, *bucket]
+	now     func() time.Time
+}
+
+// NewMemoryRateStore returns a RateStore for a single instance, keeping
+// the buckets of the most recently seen callers in memory.
+func NewMemoryRateStore() RateStore {
+	return &memoryRateStore{buckets: lru.New[string, *bucket](maxBuckets), now: time.Now}
+}
+
+// Take takes a token for the caller, a bucket starting full with the
+// limit of the policy it is first taken with.
+func (s *memoryRateStore) Take(ctx context.Context, key string, p Policy) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	b, ok := s.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: float64(p.Limit), last: now}
+		s.buckets.Add(key, b)
+	}
+	return b.take(p, now), nil
+}
+
+// PolicyResolver provides the rate limit overrides of the API keys,
+// implemented by the apikey.Store.
+type PolicyResolver interface {
+	// RatePolicy returns the policy of the key, nil for the default.
+	RatePolicy(ctx context.Context, keyId string) (*Policy, error)
+}
+
+// DefaultPolicyCacheTTL is how long the policy of a key is cached by the
+// KeyLimiter, bounding the delay before a changed override takes effect.
+const DefaultPolicyCacheTTL = time.Minute
+
+// cachedPolicy is the cached override of a key, nil for the default.
+type cachedPolicy struct {
+	policy *Policy
+	expiry time.Time
+}
+
+// KeyLimiter rate limits the requests per API key, with a default policy
+// and per key overrides, typically from the API key table, keeping the
+// buckets in a RateStore shared by the replicas if required.
+type KeyLimiter struct {
+	policy    Policy
+	store     RateStore
+	overrides PolicyResolver
+	cache     *lru.Cache[string, cachedPolicy]
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewKeyLimiter creates the limiter enforcing the default policy, or the
+// override of the key if overrides is not nil, keeping the buckets in
+// store, in memory if nil.
+func NewKeyLimiter(p Policy, store RateStore, overrides PolicyResolver) *KeyLimiter {
+	if store == nil {
+		store = NewMemoryRateStore()
+	}
+	return &KeyLimiter{
+		policy:    normalize(p),
+		store:     store,
+		overrides: overrides,
+		cache:     lru.New[string, cachedPolicy](maxBuckets),
+		ttl:       DefaultPolicyCacheTTL,
+		now:       time.Now,
+	}
+}
+
+// normalize returns the policy with a limit and window of at least one.
+func normalize(p Policy) Policy {
+	if p.Limit < 1 {
+		p.Limit = 1
+	}
+	if p.Window <= 0 {
+		p.Window = time.Second
+	}
+	return p
+}
+
+// Policy returns the policy enforced for the key, the default if the key
+// has no override or it can't be resolved.
+func (l *KeyLimiter) Policy(ctx context.Context, keyId string) Policy {
+	if l.overrides == nil || keyId == "" {
+		return l.policy
+	}
+	now := l.now()
+	e, ok := l.cache.Get(keyId)
+	if !ok || !now.Before(e.expiry) {
+		p, err := l.overrides.RatePolicy(ctx, keyId)
+		if err != nil {
+			// don't cache the failures, the default applies meanwhile
+			return l.policy
+		}
+		e = cachedPolicy{policy: p, expiry: now.Add(l.ttl)}
+		l.cache.Add(keyId, e)
+	}
+	if e.policy == nil {
+		return l.policy
+	}
+	return normalize(*e.policy)
+}
+
+// Middleware returns a middleware rate limiting the requests per API key,
+// as authenticated by the preceding middleware, see ByKeyId, and the
+// remaining ones per client address with the default policy. Requests over
+// the limit are rejected with 429 and the backoff hints, and with 503 if
+// the store fails.
+func (l *KeyLimiter) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			keyId := authenticatedKeyId(r)
+			key := ByKeyId(r)
+			p := l.Policy(r.Context(), keyId)
+			d, err := l.store.Take(r.Context(), key, p)
+			if err != nil {
+				http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			WriteHeaders(w.Header(), p, d)
+			if !d.Allowed {
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package throttle
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/internal/lru"
+	"github.com/go-core-stack/auth/ipaddr"
+	"github.com/go-core-stack/auth/model"
+)
+
+/*
+Package throttle limits the rate of the requests served per caller, and
+rejects the requests over the limit with 429 Too Many Requests along with
+standardized backoff hints, so that clients coordinate their backoff
+instead of hammering the server:
+
+  - Retry-After: seconds until the request may be retried
+  - RateLimit-Policy: the quota and its window, e.g. "100;w=60"
+  - RateLimit: the remaining quota and the seconds until it is reset, e.g.
+    "limit=100, remaining=0, reset=12"
+
+as per the IETF draft for RateLimit header fields for HTTP. The client
+package honors these headers in its retry logic.
+
+# Usage
+
+    limiter := throttle.NewLimiter(throttle.Policy{Limit: 100, Window: time.Minute})
+    handler = limiter.Middleware(nil)(handler) // keyed by API key id
+
+    // per API key quotas with the overrides of the API key table, placed
+    // after the validation middleware
+    keyLimiter := throttle.NewKeyLimiter(throttle.Policy{Limit: 100, Window: time.Minute}, nil, apiKeyStore)
+    handler = keyLimiter.Middleware()(handler)
+*/
+
+// Header names emitted for the rate limited requests.
+const (
+	RetryAfterHeader      = "Retry-After"
+	RateLimitPolicyHeader = "RateLimit-Policy"
+	RateLimitHeader       = "RateLimit"
+)
+
+// Policy allows Limit requests per Window per caller, refilled
+// continuously, allowing bursts of up to Limit requests.
+type Policy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// String formats the policy as the value of the RateLimit-Policy header.
+func (p Policy) String() string {
+	return fmt.Sprintf("%d;w=%d", p.Limit, int64(math.Ceil(p.Window.Seconds())))
+}
+
+// rate returns the tokens refilled per second.
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// Decision is the outcome of the rate limiting of a request.
+type Decision struct {
+	Allowed bool
+
+	// requests remaining in the quota
+	Remaining int
+
+	// duration until the quota is fully replenished
+	Reset time.Duration
+
+	// duration until the next request is allowed, for rejected requests
+	RetryAfter time.Duration
+}
+
+// seconds rounds the duration up to whole seconds.
+func seconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
+
+// WriteHeaders sets the rate limit headers of the decision on the
+// response, including Retry-After for rejected requests, for use by other
+// layers rejecting requests over their quota.
+func WriteHeaders(h http.Header, p Policy, d Decision) {
+	h.Set(RateLimitPolicyHeader, p.String())
+	h.Set(RateLimitHeader, fmt.Sprintf("limit=%d, remaining=%d, reset=%d", p.Limit, d.Remaining, seconds(d.Reset)))
+	if !d.Allowed {
+		h.Set(RetryAfterHeader, strconv.FormatInt(max(seconds(d.RetryAfter), 1), 10))
+	}
+}
+
+// bucket is the token bucket of a caller.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// maxBuckets bounds the number of callers tracked, the buckets of the least
+// recently seen ones being dropped.
+const maxBuckets = 10000
+
+// Limiter is an in-memory token bucket limiter per caller.
+type Limiter struct {
+	policy  Policy
+	mu      sync.Mutex
+	buckets *lru.Cache[string, *bucket]
+	now     func() time.Time
+}
+
+// NewLimiter creates the limiter enforcing the policy.
+func NewLimiter(p Policy) *Limiter {
+	return &Limiter{
+		policy:  normalize(p),
+		buckets: lru.New[string, *bucket](maxBuckets),
+		now:     time.Now,
+	}
+}
+
+// Policy returns the policy enforced by the limiter.
+func (l *Limiter) Policy() Policy {
+	return l.policy
+}
+
+// Allow takes a token for the request of the caller.
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	b, ok := l.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: float64(l.policy.Limit), last: now}
+		l.buckets.Add(key, b)
+	}
+	return b.take(l.policy, now)
+}
+
+// take takes a token from the bucket refilled as per the policy.
+func (b *bucket) take(p Policy, now time.Time) Decision {
+	rate := p.rate()
+	limit := float64(p.Limit)
+	b.tokens = min(limit, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	d := Decision{}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = time.Duration((limit - b.tokens) / rate * float64(time.Second))
+	return d
+}
+
+// KeyFunc returns the caller a request is accounted to.
+type KeyFunc func(r *http.Request) string
+
+// ByKeyId accounts the requests to the API key they are authenticated
+// with, as per the model.AuthContext attached by the preceding middleware,
+// e.g. apikey.Store.Middleware, and the remaining ones to the normalized
+// client address, see ipaddr.RequestIP. The key id headers of the requests
+// are never trusted, they are not authenticated yet.
+func ByKeyId(r *http.Request) string {
+	if keyId := authenticatedKeyId(r); keyId != "" {
+		return "key:" + keyId
+	}
+	return "addr:" + ipaddr.RequestIP(r)
+}
+
+// authenticatedKeyId returns the id of the API key the request is
+// authenticated with, empty if none.
+func authenticatedKeyId(r *http.Request) string {
+	if a, ok := model.FromContext(r.Context()); ok {
+		return a.KeyId
+	}
+	return ""
+}
+
+// Middleware returns a middleware rate limiting the requests per caller
+// as identified by key, ByKeyId if nil, rejecting the requests over the
+// limit with 429 and the backoff hints. The rate limit headers are set on
+// the allowed responses as well.
+func (l *Limiter) Middleware(key KeyFunc) func(http.Handler) http.Handler {
+	if key == nil {
+		key = ByKeyId
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := l.Allow(key(r))
+			WriteHeaders(w.Header(), l.policy, d)
+			if !d.Allowed {
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
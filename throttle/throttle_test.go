@@ -0,0 +1,195 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/model"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLimiter(Policy{Limit: 2, Window: 10 * time.Second})
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if d := l.Allow("a"); !d.Allowed {
+			t.Fatalf("expected request %d to be allowed", i)
+		}
+	}
+	d := l.Allow("a")
+	if d.Allowed || d.Remaining != 0 || d.RetryAfter != 5*time.Second || d.Reset != 10*time.Second {
+		t.Errorf("unexpected decision %+v", d)
+	}
+	if !l.Allow("b").Allowed {
+		t.Errorf("expected other caller to be allowed")
+	}
+	now = now.Add(5 * time.Second)
+	if !l.Allow("a").Allowed {
+		t.Errorf("expected request to be allowed after refill")
+	}
+}
+
+func TestLimiterBound(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := NewLimiter(Policy{Limit: 1, Window: time.Hour})
+	l.now = func() time.Time { return now }
+
+	l.Allow("a")
+	// callers sprayed without replenishing never grow the limiter beyond
+	// its bound, the active caller keeping its drained bucket
+	for i := range maxBuckets + 100 {
+		l.Allow(fmt.Sprintf("spray-%d", i))
+		if i%100 == 0 && l.Allow("a").Allowed {
+			t.Fatalf("expected the drained caller to remain limited after %d callers", i)
+		}
+	}
+	if n := l.buckets.Len(); n != maxBuckets {
+		t.Errorf("expected %d buckets, got %d", maxBuckets, n)
+	}
+}
+
+// keyRequest returns a request authenticated with the API key
+func keyRequest(path, keyId string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	return r.WithContext(model.WithAuthContext(r.Context(), &model.AuthContext{KeyId: keyId}))
+}
+
+func TestMiddleware(t *testing.T) {
+	l := NewLimiter(Policy{Limit: 1, Window: time.Minute})
+	h := l.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := keyRequest("/books", "k1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Header().Get(RateLimitPolicyHeader) != "1;w=60" {
+		t.Fatalf("unexpected response %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if got := rec.Header().Get(RetryAfterHeader); got != "60" {
+		t.Errorf("unexpected Retry-After %q", got)
+	}
+	if got := rec.Header().Get(RateLimitHeader); got != "limit=1, remaining=0, reset=60" {
+		t.Errorf("unexpected RateLimit %q", got)
+	}
+}
+
+func TestByKeyIdNormalizesAddress(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.RemoteAddr = "[::ffff:192.0.2.1]:1234"
+	mapped := ByKeyId(req)
+	req.RemoteAddr = "192.0.2.1:4321"
+	if plain := ByKeyId(req); plain != mapped || plain != "addr:192.0.2.1" {
+		t.Errorf("expected the same key for the same client, got %q and %q", mapped, plain)
+	}
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	l := NewConcurrencyLimiter(1, nil)
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	h := l.Middleware(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(entered)
+			<-unblock
+		}
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req := keyRequest("/slow", "k1")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	<-entered
+
+	req := keyRequest("/books", "k1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get(RetryAfterHeader) != "1" {
+		t.Fatalf("expected 429 with Retry-After, got %d %v", rec.Code, rec.Header())
+	}
+
+	other := keyRequest("/books", "k2")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, other)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected other caller to be allowed, got %d", rec.Code)
+	}
+
+	close(unblock)
+	<-done
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected request to be allowed after release, got %d", rec.Code)
+	}
+}
+
+// overrides provides the policies of the keys, counting the lookups
+type overrides struct {
+	policies map[string]*Policy
+	lookups  int
+}
+
+func (o *overrides) RatePolicy(ctx context.Context, keyId string) (*Policy, error) {
+	o.lookups++
+	return o.policies[keyId], nil
+}
+
+func TestKeyLimiter(t *testing.T) {
+	o := &overrides{policies: map[string]*Policy{"premium": {Limit: 3, Window: time.Minute}}}
+	l := NewKeyLimiter(Policy{Limit: 1, Window: time.Minute}, nil, o)
+	h := l.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	allowed := func(keyId string) int {
+		n := 0
+		for range 5 {
+			req := httptest.NewRequest(http.MethodGet, "/books", nil)
+			if keyId != "" {
+				req = keyRequest("/books", keyId)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code == http.StatusOK {
+				n++
+			} else if rec.Header().Get(RetryAfterHeader) == "" {
+				t.Errorf("expected Retry-After on %d", rec.Code)
+			}
+		}
+		return n
+	}
+	if n := allowed("premium"); n != 3 {
+		t.Errorf("expected the override to allow 3 requests, got %d", n)
+	}
+	if n := allowed("basic"); n != 1 {
+		t.Errorf("expected the default to allow 1 request, got %d", n)
+	}
+	if n := allowed(""); n != 1 {
+		t.Errorf("expected unauthenticated requests limited per address, got %d", n)
+	}
+
+	// the key id header of the unauthenticated requests is not trusted
+	req := httptest.NewRequest(http.MethodGet, "/books", nil)
+	req.Header.Set("x-api-key-id", "premium")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected the request to be limited per address, got %d", rec.Code)
+	}
+	if o.lookups != 2 {
+		t.Errorf("expected the policies to be cached, got %d lookups", o.lookups)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-core-stack/auth/hash"
+	"github.com/go-core-stack/auth/model"
+)
+
+// Issuer mints the tokens, signed with the signing key of its key set.
+type Issuer struct {
+	issuer string
+	keys   *KeySet
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewIssuer creates the issuer of the tokens valid for ttl, identified by
+// the iss claim.
+func NewIssuer(issuer string, keys *KeySet, ttl time.Duration) *Issuer {
+	return &Issuer{
+		issuer: issuer,
+		keys:   keys,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Issue mints the token of the claims for the audience, setting the
+// issuer, the times and a unique id.
+func (i *Issuer) Issue(c *Claims, audience ...string) (string, error) {
+	key, err := i.keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+	id, err := hash.GenerateKeyID()
+	if err != nil {
+		return "", err
+	}
+	now := i.now()
+	claims := *c
+	claims.Issuer = i.issuer
+	claims.Audience = audience
+	claims.IssuedAt = now.Unix()
+	claims.NotBefore = now.Unix()
+	claims.ExpiresAt = now.Add(i.ttl).Unix()
+	claims.ID = id
+	return sign(key, &claims)
+}
+
+// IssueAuthContext mints the token carrying the auth context for the
+// audience.
+func (i *Issuer) IssueAuthContext(a *model.AuthContext, audience ...string) (string, error) {
+	return i.Issue(ClaimsFromAuthContext(a), audience...)
+}
+
+// transport adds the token of the auth context of the outgoing requests.
+type transport struct {
+	base     http.RoundTripper
+	issuer   *Issuer
+	audience []string
+}
+
+// RoundTrip adds the bearer token carrying the auth context attached to
+// the request context, requests without one are sent as is.
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	a, ok := model.FromContext(r.Context())
+	if !ok {
+		return t.base.RoundTrip(r)
+	}
+	tok, err := t.issuer.IssueAuthContext(a, t.audience...)
+	if err != nil {
+		return nil, err
+	}
+	// the request is not modified as per the RoundTripper contract
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+tok)
+	return t.base.RoundTrip(r)
+}
+
+// Transport returns the transport propagating the auth context of the
+// requests, e.g. populated by the HMAC validation middleware, to the next
+// hop as a bearer token for the audience, using base or
+// http.DefaultTransport if nil.
+func (i *Issuer) Transport(base http.RoundTripper, audience ...string) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base, issuer: i, audience: audience}
+}
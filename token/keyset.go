@@ -0,0 +1,293 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+)
+
+// Algorithm identifies the algorithm signing the tokens, the alg header.
+type Algorithm string
+
+const (
+	// HS256 is HMAC with SHA-256, for tokens verified by the holders of
+	// the shared secret only.
+	HS256 Algorithm = "HS256"
+
+	// RS256 is RSASSA-PKCS1-v1_5 with SHA-256.
+	RS256 Algorithm = "RS256"
+
+	// EdDSA is Ed25519.
+	EdDSA Algorithm = "EdDSA"
+)
+
+// minHMACKeyBytes is the minimum length of the HS256 secrets
+const minHMACKeyBytes = 32
+
+// minRSAKeyBits is the minimum size of the RSA keys
+const minRSAKeyBits = 2048
+
+// Key is a key signing or verifying the tokens, public keys verify only.
+type Key struct {
+	ID        string
+	Algorithm Algorithm
+
+	secret  []byte
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// NewHMACKey creates the HS256 key with the secret of at least 32 bytes.
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if len(secret) < minHMACKeyBytes {
+		return nil, fmt.Errorf("hmac key must be at least %d bytes", minHMACKeyBytes)
+	}
+	return &Key{ID: id, Algorithm: HS256, secret: secret}, nil
+}
+
+// NewRSAKey creates the RS256 signing key.
+func NewRSAKey(id string, priv *rsa.PrivateKey) (*Key, error) {
+	if priv.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+	}
+	return &Key{ID: id, Algorithm: RS256, private: priv, public: &priv.PublicKey}, nil
+}
+
+// NewRSAPublicKey creates the RS256 verification key.
+func NewRSAPublicKey(id string, pub *rsa.PublicKey) *Key {
+	return &Key{ID: id, Algorithm: RS256, public: pub}
+}
+
+// NewEd25519Key creates the EdDSA signing key.
+func NewEd25519Key(id string, priv ed25519.PrivateKey) (*Key, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid ed25519 private key")
+	}
+	return &Key{ID: id, Algorithm: EdDSA, private: priv, public: priv.Public()}, nil
+}
+
+// NewEd25519PublicKey creates the EdDSA verification key.
+func NewEd25519PublicKey(id string, pub ed25519.PublicKey) *Key {
+	return &Key{ID: id, Algorithm: EdDSA, public: pub}
+}
+
+// CanSign reports whether the key holds the secret or private key.
+func (k *Key) CanSign() bool {
+	return k.secret != nil || k.private != nil
+}
+
+// sign signs the signing input of a token.
+func (k *Key) sign(input []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case RS256:
+		if k.private == nil {
+			return nil, fmt.Errorf("key %s can not sign", k.ID)
+		}
+		sum := sha256.Sum256(input)
+		return k.private.Sign(rand.Reader, sum[:], crypto.SHA256)
+	case EdDSA:
+		if k.private == nil {
+			return nil, fmt.Errorf("key %s can not sign", k.ID)
+		}
+		return k.private.Sign(rand.Reader, input, crypto.Hash(0))
+	}
+	return nil, fmt.Errorf("unsupported algorithm %s", k.Algorithm)
+}
+
+// verify verifies the signature of the signing input of a token.
+func (k *Key) verify(input, sig []byte) bool {
+	switch k.Algorithm {
+	case HS256:
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(input)
+		return k.secret != nil && hmac.Equal(sig, mac.Sum(nil))
+	case RS256:
+		pub, ok := k.public.(*rsa.PublicKey)
+		sum := sha256.Sum256(input)
+		return ok && rsa.VerifyPKCS1v15(pub, crypto.SHA256, sum[:], sig) == nil
+	case EdDSA:
+		pub, ok := k.public.(ed25519.PublicKey)
+		return ok && ed25519.Verify(pub, input, sig)
+	}
+	return false
+}
+
+// KeySet holds the keys of the tokens, the signing key along with the
+// keys verifying the tokens issued before the last rotations.
+type KeySet struct {
+	mu      sync.RWMutex
+	keys    map[string]*Key
+	signing string
+}
+
+// NewKeySet creates the key set holding the keys.
+func NewKeySet(keys ...*Key) *KeySet {
+	ks := &KeySet{keys: map[string]*Key{}}
+	for _, k := range keys {
+		ks.keys[k.ID] = k
+	}
+	return ks
+}
+
+// Add adds the key for verification, replacing the key with the same id.
+func (ks *KeySet) Add(k *Key) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[k.ID] = k
+}
+
+// Rotate adds the key and makes it the signing key, the previous keys
+// remaining valid for verification until removed.
+func (ks *KeySet) Rotate(k *Key) error {
+	if !k.CanSign() {
+		return fmt.Errorf("key %s can not sign", k.ID)
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[k.ID] = k
+	ks.signing = k.ID
+	return nil
+}
+
+// Remove removes the key, tokens signed with it no longer verify. The
+// signing key can not be removed.
+func (ks *KeySet) Remove(id string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if id == ks.signing {
+		return fmt.Errorf("key %s is the signing key", id)
+	}
+	delete(ks.keys, id)
+	return nil
+}
+
+// Get returns the key with the id.
+func (ks *KeySet) Get(id string) (*Key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	k, ok := ks.keys[id]
+	return k, ok
+}
+
+// SigningKey returns the key signing the tokens.
+func (ks *KeySet) SigningKey() (*Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	k, ok := ks.keys[ks.signing]
+	if !ok {
+		return nil, errors.New("no signing key")
+	}
+	return k, nil
+}
+
+// JWK is a JSON Web Key of the JWKS.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyId     string `json:"kid"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+
+	// RSA modulus and exponent
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// OKP curve and public key
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, HS256 secrets are never
+// published.
+func (ks *KeySet) JWKS() *JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	set := &JWKS{Keys: []*JWK{}}
+	for _, k := range ks.keys {
+		switch pub := k.public.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, &JWK{
+				KeyType:   "RSA",
+				KeyId:     k.ID,
+				Algorithm: string(RS256),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, &JWK{
+				KeyType:   "OKP",
+				KeyId:     k.ID,
+				Algorithm: string(EdDSA),
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+// JWKSHandler returns the handler serving the JWKS of the key set.
+func (ks *KeySet) JWKSHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "max-age=300")
+		_ = json.NewEncoder(w).Encode(ks.JWKS())
+	})
+}
+
+// ParseJWKS returns the key set verifying the tokens with the public keys
+// of the JWKS, keys of unsupported types are skipped.
+func ParseJWKS(data []byte) (*KeySet, error) {
+	set := &JWKS{}
+	if err := json.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+	ks := NewKeySet()
+	for _, jwk := range set.Keys {
+		switch {
+		case jwk.KeyType == "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rsa key %s", jwk.KeyId)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return nil, fmt.Errorf("invalid rsa key %s", jwk.KeyId)
+			}
+			ks.Add(NewRSAPublicKey(jwk.KeyId, &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}))
+		case jwk.KeyType == "OKP" && jwk.Curve == "Ed25519":
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid ed25519 key %s", jwk.KeyId)
+			}
+			ks.Add(NewEd25519PublicKey(jwk.KeyId, ed25519.PublicKey(x)))
+		}
+	}
+	return ks, nil
+}
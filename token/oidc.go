@@ -0,0 +1,253 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-core-stack/auth/model"
+)
+
+// Defaults of the OIDC verifier.
+const (
+	// DefaultJWKSMaxAge is how long the JWKS of the provider is used before
+	// being fetched again
+	DefaultJWKSMaxAge = time.Hour
+
+	// DefaultJWKSMinRefresh is the minimum interval between two fetches of
+	// the JWKS triggered by tokens signed with an unknown key, so that
+	// forged kids can't be used to flood the provider
+	DefaultJWKSMinRefresh = time.Minute
+
+	// maxOIDCResponseBytes bounds the discovery and JWKS documents read
+	maxOIDCResponseBytes = 1 << 20
+)
+
+// OIDCOption configures an OIDCVerifier.
+type OIDCOption func(*OIDCVerifier)
+
+// WithHTTPClient sets the client fetching the discovery document and the
+// JWKS, http.DefaultClient if not set.
+func WithHTTPClient(c *http.Client) OIDCOption {
+	return func(v *OIDCVerifier) {
+		v.client = c
+	}
+}
+
+// WithJWKSRefresh sets how long the JWKS is cached, and the minimum
+// interval between the fetches triggered by unknown keys.
+func WithJWKSRefresh(maxAge, minRefresh time.Duration) OIDCOption {
+	return func(v *OIDCVerifier) {
+		v.maxAge = maxAge
+		v.minRefresh = minRefresh
+	}
+}
+
+// WithClaimNames sets the names of the claims carrying the tenant and the
+// roles of the user, e.g. "org_id" and "groups", "tenant" and "roles" if
+// not set.
+func WithClaimNames(tenant, roles string) OIDCOption {
+	return func(v *OIDCVerifier) {
+		v.tenantClaim = tenant
+		v.rolesClaim = roles
+	}
+}
+
+// WithOIDCLeeway sets the tolerated clock difference with the provider,
+// DefaultLeeway if not set.
+func WithOIDCLeeway(leeway time.Duration) OIDCOption {
+	return func(v *OIDCVerifier) {
+		v.leeway = leeway
+	}
+}
+
+// providerMetadata is the subset of the OpenID provider metadata used.
+type providerMetadata struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// OIDCVerifier verifies the ID and access tokens issued by an OpenID
+// Connect provider, discovered from its issuer URL, caching its JWKS.
+type OIDCVerifier struct {
+	issuer      string
+	audience    string
+	jwksURI     string
+	client      *http.Client
+	maxAge      time.Duration
+	minRefresh  time.Duration
+	leeway      time.Duration
+	tenantClaim string
+	rolesClaim  string
+	now         func() time.Time
+
+	mu      sync.Mutex
+	keys    *KeySet
+	fetched time.Time
+}
+
+// NewOIDCVerifier discovers the provider of the issuer with its
+// .well-known/openid-configuration document, and fetches its JWKS. The
+// tokens are required to be issued for the audience, the client id of the
+// application.
+func NewOIDCVerifier(ctx context.Context, issuer, audience string, opts ...OIDCOption) (*OIDCVerifier, error) {
+	if audience == "" {
+		return nil, errors.New("oidc: audience is required")
+	}
+	v := &OIDCVerifier{
+		issuer:      issuer,
+		audience:    audience,
+		client:      http.DefaultClient,
+		maxAge:      DefaultJWKSMaxAge,
+		minRefresh:  DefaultJWKSMinRefresh,
+		leeway:      DefaultLeeway,
+		tenantClaim: "tenant",
+		rolesClaim:  "roles",
+		now:         time.Now,
+		keys:        NewKeySet(),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	meta := &providerMetadata{}
+	if err := v.getJSON(ctx, strings.TrimRight(issuer, "/")+"/.well-known/openid-configuration", meta); err != nil {
+		return nil, err
+	}
+	// the issuer of the metadata must be the one it is discovered from,
+	// see OpenID Connect Discovery 1.0 section 4.3
+	if meta.Issuer != issuer {
+		return nil, fmt.Errorf("oidc: issuer mismatch, discovered %q", meta.Issuer)
+	}
+	if meta.JWKSURI == "" {
+		return nil, errors.New("oidc: provider metadata without jwks_uri")
+	}
+	v.jwksURI = meta.JWKSURI
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// getJSON fetches and decodes the JSON document.
+func (v *OIDCVerifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("oidc: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("oidc: request to %s failed: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: %s returned HTTP status %d", url, resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOIDCResponseBytes))
+	if err != nil {
+		return fmt.Errorf("oidc: failed to read %s: %w", url, err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("oidc: invalid response from %s: %w", url, err)
+	}
+	return nil
+}
+
+// refresh fetches the JWKS of the provider, replacing the cached keys.
+func (v *OIDCVerifier) refresh(ctx context.Context) error {
+	var raw json.RawMessage
+	if err := v.getJSON(ctx, v.jwksURI, &raw); err != nil {
+		return err
+	}
+	keys, err := ParseJWKS(raw)
+	if err != nil {
+		return fmt.Errorf("oidc: %w", err)
+	}
+	v.mu.Lock()
+	v.keys = keys
+	v.fetched = v.now()
+	v.mu.Unlock()
+	return nil
+}
+
+// keySet returns the cached keys, fetching the JWKS again once it is older
+// than the max age, or on an unknown key if not fetched within the minimum
+// refresh interval. A failed fetch keeps the cached keys.
+func (v *OIDCVerifier) keySet(unknownKey bool) *KeySet {
+	v.mu.Lock()
+	age := v.now().Sub(v.fetched)
+	keys := v.keys
+	v.mu.Unlock()
+	if age >= v.maxAge || (unknownKey && age >= v.minRefresh) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if v.refresh(ctx) == nil {
+			v.mu.Lock()
+			keys = v.keys
+			v.mu.Unlock()
+		}
+	}
+	return keys
+}
+
+// Verify verifies the signature, issuer, audience and validity of the
+// token, returning its claims along with the tenant and roles mapped from
+// the configured claims.
+func (v *OIDCVerifier) Verify(token string) (*Claims, error) {
+	verifier := &Verifier{issuer: v.issuer, audience: v.audience, leeway: v.leeway, now: v.now}
+	verifier.keys = v.keySet(false)
+	c, err := verifier.Verify(token)
+	if errors.Is(err, ErrUnknownKey) {
+		// the provider may have rotated its keys
+		verifier.keys = v.keySet(true)
+		c, err = verifier.Verify(token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rawClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	// the claims of this package are not issued by the provider
+	c.Tenant = ""
+	c.Roles = nil
+	c.IsRoot = false
+	c.KeyId = ""
+	c.SubjectType = model.SubjectUser
+	if t, ok := raw[v.tenantClaim]; ok {
+		_ = json.Unmarshal(t, &c.Tenant)
+	}
+	if roles, ok := raw[v.rolesClaim]; ok {
+		_ = json.Unmarshal(roles, &c.Roles)
+	}
+	return c, nil
+}
+
+// rawClaims returns the claims of the payload of the token, to be called
+// once verified.
+func rawClaims(token string) (map[string]json.RawMessage, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return raw, nil
+}
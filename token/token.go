@@ -0,0 +1,224 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/go-core-stack/auth/binding"
+	"github.com/go-core-stack/auth/model"
+)
+
+/*
+Package token mints signed JWTs carrying the AuthContext of the caller
+authenticated with HMAC, and verifies them on the subsequent hops, so that
+internal service-to-service calls don't have to resolve the API keys on
+every hop.
+
+Tokens are signed with HS256, RS256 or EdDSA (Ed25519) keys held in a
+KeySet, identified by the kid header. A key is rotated by adding the next
+key as the signing key, the previous ones remaining valid for verification
+until removed once the tokens they signed expired. The public keys are
+published as a JSON Web Key Set for verifiers in other services.
+
+Tokens may be bound to the credential of the client they are issued to
+with the cnf claim, see the binding package, enforced by the verifying
+middleware.
+
+Tokens issued by an OpenID Connect provider to the users are verified
+with the OIDCVerifier, discovering the provider from its issuer URL and
+caching its JWKS, refreshed as the provider rotates its keys.
+
+# Usage
+
+    keys := token.NewKeySet()
+    key, _ := token.NewEd25519Key("2025-06", priv)
+    keys.Rotate(key)
+    mux.Handle("/.well-known/jwks.json", keys.JWKSHandler())
+
+    // first hop, behind the HMAC validation middleware
+    issuer := token.NewIssuer("https://auth.example.com", keys, 5*time.Minute)
+    client := &http.Client{Transport: issuer.Transport(nil, "orders")}
+
+    // subsequent hops
+    verifier := token.NewVerifier("https://auth.example.com", keys, token.WithAudience("orders"))
+    handler = verifier.Middleware(handler)
+
+    // tokens of an OpenID Connect provider
+    oidc, err := token.NewOIDCVerifier(ctx, "https://accounts.example.com", clientId,
+        token.WithClaimNames("org_id", "groups"))
+    claims, err := oidc.Verify(rawToken)
+*/
+
+var (
+	// ErrInvalidToken is returned for malformed tokens, tokens with an
+	// invalid signature, or not issued by the expected issuer for the
+	// expected audience.
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpired is returned for tokens past their expiry, or not valid
+	// yet.
+	ErrExpired = errors.New("token expired")
+
+	// ErrUnknownKey is returned for tokens signed with a key not in the
+	// key set.
+	ErrUnknownKey = errors.New("unknown token signing key")
+)
+
+// Audience is the aud claim, a single string or an array of strings.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decodes a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// Contains reports whether the audience includes the value.
+func (a Audience) Contains(v string) bool {
+	for _, aud := range a {
+		if aud == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims are the claims of the tokens, the registered claims along with
+// the AuthContext of the caller.
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+
+	// AuthContext of the caller
+	KeyId       string            `json:"api_key_id,omitempty"`
+	Tenant      string            `json:"tenant,omitempty"`
+	SubjectType model.SubjectType `json:"sub_type,omitempty"`
+	Roles       []string          `json:"roles,omitempty"`
+	IsRoot      bool              `json:"root,omitempty"`
+
+	// credential the token is bound to, if any
+	Cnf *binding.Confirmation `json:"cnf,omitempty"`
+}
+
+// ClaimsFromAuthContext returns the claims carrying the auth context.
+func ClaimsFromAuthContext(a *model.AuthContext) *Claims {
+	return &Claims{
+		Subject:     a.Subject,
+		KeyId:       a.KeyId,
+		Tenant:      a.Tenant,
+		SubjectType: a.SubjectType,
+		Roles:       a.Roles,
+		IsRoot:      a.IsRoot,
+	}
+}
+
+// AuthContext returns the auth context carried by the claims.
+func (c *Claims) AuthContext() *model.AuthContext {
+	return &model.AuthContext{
+		KeyId:       c.KeyId,
+		Tenant:      c.Tenant,
+		Subject:     c.Subject,
+		SubjectType: c.SubjectType,
+		Roles:       c.Roles,
+		IsRoot:      c.IsRoot,
+	}
+}
+
+// header is the JOSE header of the tokens.
+type header struct {
+	Algorithm Algorithm `json:"alg"`
+	Type      string    `json:"typ,omitempty"`
+	KeyId     string    `json:"kid,omitempty"`
+}
+
+// encode returns the base64url encoding of the JSON of v.
+func encode(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// sign returns the token of the claims signed with the key.
+func sign(key *Key, c *Claims) (string, error) {
+	h, err := encode(&header{Algorithm: key.Algorithm, Type: "JWT", KeyId: key.ID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encode(c)
+	if err != nil {
+		return "", err
+	}
+	signingInput := h + "." + payload
+	sig, err := key.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// parse verifies the signature of the token with the key of the key set
+// identified by its kid, returning its claims.
+func parse(keys *KeySet, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	b, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	h := &header{}
+	if err := json.Unmarshal(b, h); err != nil {
+		return nil, ErrInvalidToken
+	}
+	key, ok := keys.Get(h.KeyId)
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	// the algorithm is the one of the key, never the one of the token
+	if h.Algorithm != key.Algorithm {
+		return nil, ErrInvalidToken
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !key.verify([]byte(parts[0]+"."+parts[1]), sig) {
+		return nil, ErrInvalidToken
+	}
+	b, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	c := &Claims{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return c, nil
+}
@@ -0,0 +1,286 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-core-stack/auth/binding"
+	"github.com/go-core-stack/auth/model"
+)
+
+const testIssuer = "https://auth.example.com"
+
+func testKeys(t *testing.T) []*Key {
+	hs, err := NewHMACKey("hs", []byte(strings.Repeat("s", 32)))
+	if err != nil {
+		t.Fatalf("failed to create hmac key: %s", err)
+	}
+	rsaPriv, _ := rsa.GenerateKey(rand.Reader, 2048)
+	rs, err := NewRSAKey("rs", rsaPriv)
+	if err != nil {
+		t.Fatalf("failed to create rsa key: %s", err)
+	}
+	_, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	ed, err := NewEd25519Key("ed", edPriv)
+	if err != nil {
+		t.Fatalf("failed to create ed25519 key: %s", err)
+	}
+	return []*Key{hs, rs, ed}
+}
+
+func TestIssueVerify(t *testing.T) {
+	a := &model.AuthContext{KeyId: "k1", Tenant: "acme", Subject: "alice", SubjectType: model.SubjectService, Roles: []string{"reader"}}
+	for _, key := range testKeys(t) {
+		keys := NewKeySet()
+		if err := keys.Rotate(key); err != nil {
+			t.Fatalf("failed to rotate: %s", err)
+		}
+		tok, err := NewIssuer(testIssuer, keys, time.Minute).IssueAuthContext(a, "orders")
+		if err != nil {
+			t.Fatalf("%s: failed to issue: %s", key.Algorithm, err)
+		}
+		c, err := NewVerifier(testIssuer, keys, WithAudience("orders")).Verify(tok)
+		if err != nil {
+			t.Fatalf("%s: failed to verify: %s", key.Algorithm, err)
+		}
+		got := c.AuthContext()
+		if got.KeyId != "k1" || got.Tenant != "acme" || got.Subject != "alice" || got.Roles[0] != "reader" {
+			t.Errorf("%s: unexpected auth context %+v", key.Algorithm, got)
+		}
+
+		if _, err := NewVerifier(testIssuer, keys, WithAudience("billing")).Verify(tok); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected other audience to be rejected, got %v", key.Algorithm, err)
+		}
+		if _, err := NewVerifier("https://other.example.com", keys).Verify(tok); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: expected other issuer to be rejected, got %v", key.Algorithm, err)
+		}
+		v := NewVerifier(testIssuer, keys)
+		v.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+		if _, err := v.Verify(tok); !errors.Is(err, ErrExpired) {
+			t.Errorf("%s: expected expired token, got %v", key.Algorithm, err)
+		}
+		parts := strings.Split(tok, ".")
+		if _, err := v.Verify(parts[0] + "." + parts[1] + "." + parts[2][:len(parts[2])-2]); err == nil {
+			t.Errorf("%s: expected tampered signature to be rejected", key.Algorithm)
+		}
+	}
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	keys := testKeys(t)
+	ks := NewKeySet(keys...)
+	_ = ks.Rotate(keys[0])
+	tok, _ := NewIssuer(testIssuer, ks, time.Minute).Issue(&Claims{Subject: "alice"})
+
+	// an HS256 token claiming the kid of the Ed25519 key must not verify
+	parts := strings.Split(tok, ".")
+	h, _ := encode(&header{Algorithm: HS256, KeyId: "ed"})
+	if _, err := NewVerifier(testIssuer, ks).Verify(h + "." + parts[1] + "." + parts[2]); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected algorithm confusion to be rejected, got %v", err)
+	}
+	none, _ := encode(&header{Algorithm: "none", KeyId: "hs"})
+	if _, err := NewVerifier(testIssuer, ks).Verify(none + "." + parts[1] + "."); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected unsigned token to be rejected, got %v", err)
+	}
+}
+
+func TestMiddlewareAndTransport(t *testing.T) {
+	keys := NewKeySet()
+	_ = keys.Rotate(testKeys(t)[2])
+	issuer := NewIssuer(testIssuer, keys, time.Minute)
+
+	var got *model.AuthContext
+	srv := httptest.NewServer(NewVerifier(testIssuer, keys, WithAudience("orders")).Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = model.FromContext(r.Context())
+		})))
+	defer srv.Close()
+
+	cli := &http.Client{Transport: issuer.Transport(nil, "orders")}
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := cli.Do(req.WithContext(model.WithAuthContext(req.Context(), &model.AuthContext{Subject: "alice", Tenant: "acme"})))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || got == nil || got.Subject != "alice" || got.Tenant != "acme" {
+		t.Fatalf("expected the auth context to be propagated, got %d %+v", resp.StatusCode, got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("expected the request of the caller to be left unmodified")
+	}
+
+	resp, err = cli.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected request without token to be rejected, got %d", resp.StatusCode)
+	}
+}
+
+func TestBoundToken(t *testing.T) {
+	keys := NewKeySet()
+	_ = keys.Rotate(testKeys(t)[0])
+	c := &Claims{Subject: "alice", Cnf: binding.ForKey("k1")}
+	tok, _ := NewIssuer(testIssuer, keys, time.Minute).Issue(c)
+	h := NewVerifier(testIssuer, keys).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for keyId, expected := range map[string]int{"k1": http.StatusOK, "k2": http.StatusUnauthorized} {
+		req := httptest.NewRequest(http.MethodGet, "/books", nil)
+		req.Header.Set("Authorization", "Bearer "+tok)
+		req = req.WithContext(model.WithAuthContext(req.Context(), &model.AuthContext{KeyId: keyId}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != expected {
+			t.Errorf("%s: expected %d, got %d", keyId, expected, rec.Code)
+		}
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	keys := testKeys(t)
+	ks := NewKeySet()
+	_ = ks.Rotate(keys[1])
+	issuer := NewIssuer(testIssuer, ks, time.Minute)
+	old, _ := issuer.Issue(&Claims{Subject: "alice"})
+
+	_ = ks.Rotate(keys[2])
+	current, _ := issuer.Issue(&Claims{Subject: "alice"})
+	v := NewVerifier(testIssuer, ks)
+	for _, tok := range []string{old, current} {
+		if _, err := v.Verify(tok); err != nil {
+			t.Errorf("expected token to verify during rotation: %s", err)
+		}
+	}
+	if err := ks.Remove(keys[2].ID); err == nil {
+		t.Errorf("expected signing key removal to be refused")
+	}
+	_ = ks.Remove(keys[1].ID)
+	if _, err := v.Verify(old); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("expected token of removed key to be rejected, got %v", err)
+	}
+	if err := ks.Rotate(NewEd25519PublicKey("pub", nil)); err == nil {
+		t.Errorf("expected public key rotation to be refused")
+	}
+}
+
+func TestJWKS(t *testing.T) {
+	keys := testKeys(t)
+	ks := NewKeySet(keys...)
+	_ = ks.Rotate(keys[1])
+	rsTok, _ := NewIssuer(testIssuer, ks, time.Minute).Issue(&Claims{Subject: "alice"})
+	_ = ks.Rotate(keys[2])
+	edTok, _ := NewIssuer(testIssuer, ks, time.Minute).Issue(&Claims{Subject: "alice"})
+
+	data, err := json.Marshal(ks.JWKS())
+	if err != nil {
+		t.Fatalf("failed to marshal jwks: %s", err)
+	}
+	published, err := ParseJWKS(data)
+	if err != nil {
+		t.Fatalf("failed to parse jwks: %s", err)
+	}
+	if _, ok := published.Get("hs"); ok {
+		t.Errorf("expected hmac secret not to be published")
+	}
+	v := NewVerifier(testIssuer, published)
+	for _, tok := range []string{rsTok, edTok} {
+		if _, err := v.Verify(tok); err != nil {
+			t.Errorf("expected token to verify with the published keys: %s", err)
+		}
+	}
+}
+
+// newTestProvider serves the discovery document and the JWKS of the key
+// set, counting the JWKS fetches.
+func newTestProvider(t *testing.T, keys *KeySet, fetches *int) *httptest.Server {
+	var srv *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": srv.URL, "jwks_uri": srv.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		*fetches++
+		keys.JWKSHandler().ServeHTTP(w, r)
+	})
+	srv = httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	keys := testKeys(t)
+	ks := NewKeySet()
+	_ = ks.Rotate(keys[1])
+	fetches := 0
+	srv := newTestProvider(t, ks, &fetches)
+
+	v, err := NewOIDCVerifier(context.Background(), srv.URL, "console", WithClaimNames("org_id", "groups"))
+	if err != nil {
+		t.Fatalf("failed to discover provider: %s", err)
+	}
+	issue := func(c *Claims, aud string) string {
+		c.Issuer, c.Audience, c.ExpiresAt = srv.URL, Audience{aud}, time.Now().Add(time.Hour).Unix()
+		tok, _ := sign(mustSigningKey(t, ks), c)
+		return tok
+	}
+
+	// tenant and roles are mapped from the provider claims, the ones of
+	// this package are ignored
+	payload := issue(&Claims{Subject: "alice", Tenant: "forged", IsRoot: true}, "console")
+	parts := strings.Split(payload, ".")
+	raw, _ := encode(map[string]any{"iss": srv.URL, "aud": "console", "sub": "alice", "exp": time.Now().Add(time.Minute).Unix(),
+		"org_id": "acme", "groups": []string{"admin"}, "tenant": "forged", "root": true})
+	key := mustSigningKey(t, ks)
+	sig, _ := key.sign([]byte(parts[0] + "." + raw))
+	c, err := v.Verify(parts[0] + "." + raw + "." + base64.RawURLEncoding.EncodeToString(sig))
+	if err != nil {
+		t.Fatalf("failed to verify: %s", err)
+	}
+	if a := c.AuthContext(); a.Tenant != "acme" || len(a.Roles) != 1 || a.Roles[0] != "admin" || a.IsRoot || a.SubjectType != model.SubjectUser {
+		t.Errorf("unexpected auth context %+v", a)
+	}
+	if _, err := v.Verify(issue(&Claims{Subject: "alice"}, "other")); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected other audience to be rejected, got %v", err)
+	}
+
+	// the provider rotating its keys triggers a single fetch of the JWKS
+	// past the minimum refresh interval
+	_ = ks.Rotate(keys[2])
+	v.now = func() time.Time { return time.Now().Add(2 * DefaultJWKSMinRefresh) }
+	if _, err := v.Verify(issue(&Claims{Subject: "alice"}, "console")); err != nil {
+		t.Errorf("expected token of the rotated key to verify: %s", err)
+	}
+	unknown, _ := sign(&Key{ID: "forged", Algorithm: HS256, secret: []byte(strings.Repeat("f", 32))}, &Claims{Issuer: srv.URL})
+	_, _ = v.Verify(unknown)
+	if fetches != 2 {
+		t.Errorf("expected the jwks to be fetched twice, got %d", fetches)
+	}
+
+	if _, err := NewOIDCVerifier(context.Background(), srv.URL+"/", "console"); err == nil {
+		t.Errorf("expected issuer mismatch to be rejected")
+	}
+}
+
+func mustSigningKey(t *testing.T, ks *KeySet) *Key {
+	k, err := ks.SigningKey()
+	if err != nil {
+		t.Fatalf("no signing key: %s", err)
+	}
+	return k
+}
@@ -0,0 +1,110 @@
+// Copyright © 2025 Prabhjot Singh Sethi, All Rights reserved
+// Author: Prabhjot Singh Sethi <prabhjot.sethi@gmail.com>
+
+package token
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-core-stack/auth/model"
+)
+
+// DefaultLeeway is the tolerated difference between the clocks of the
+// issuer and the verifier.
+const DefaultLeeway = 30 * time.Second
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithAudience requires the tokens to be issued for the audience.
+func WithAudience(audience string) VerifierOption {
+	return func(v *Verifier) {
+		v.audience = audience
+	}
+}
+
+// WithLeeway sets the tolerated clock difference, DefaultLeeway if not
+// set.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// Verifier verifies the tokens.
+type Verifier struct {
+	issuer   string
+	keys     *KeySet
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier creates the verifier of the tokens issued by the issuer,
+// signed with the keys of the key set.
+func NewVerifier(issuer string, keys *KeySet, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		issuer: issuer,
+		keys:   keys,
+		leeway: DefaultLeeway,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify verifies the signature, issuer, audience and validity of the
+// token, returning its claims.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	c, err := parse(v.keys, token)
+	if err != nil {
+		return nil, err
+	}
+	if c.Issuer != v.issuer {
+		return nil, ErrInvalidToken
+	}
+	if v.audience != "" && !c.Audience.Contains(v.audience) {
+		return nil, ErrInvalidToken
+	}
+	now := v.now()
+	if c.ExpiresAt == 0 || now.Add(-v.leeway).Unix() >= c.ExpiresAt {
+		return nil, ErrExpired
+	}
+	if c.NotBefore != 0 && now.Add(v.leeway).Unix() < c.NotBefore {
+		return nil, ErrExpired
+	}
+	return c, nil
+}
+
+// bearerToken returns the bearer token of the Authorization header.
+func bearerToken(r *http.Request) string {
+	scheme, tok, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(tok)
+}
+
+// Middleware returns the handler verifying the bearer token of the
+// requests and attaching the auth context it carries, see
+// model.FromContext, rejecting the requests without a valid token, or
+// presented with another credential than the one the token is bound to,
+// with 401.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := v.Verify(bearerToken(r))
+		if err == nil && c.Cnf != nil {
+			err = c.Cnf.Verify(r)
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(model.WithAuthContext(r.Context(), c.AuthContext())))
+	})
+}